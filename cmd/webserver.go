@@ -313,6 +313,14 @@ func startWebServer(c *core.CliContext) error {
 
 		apiRoute.GET("/logout.json", bindApiWithTokenUpdate(api.Tokens.TokenRevokeCurrentHandler, config))
 
+		if len(config.ExchangeRatesMetricsAllowedRemoteIPs) > 0 {
+			exchangeRatesMetricsRoute := apiRoute.Group("/v1/exchange_rates")
+			exchangeRatesMetricsRoute.Use(bindMiddleware(middlewares.ExchangeRatesMetricsIpLimit(config)))
+			{
+				exchangeRatesMetricsRoute.GET("/metrics.json", bindApi(api.ExchangeRates.ExchangeRatesMetricsHandler))
+			}
+		}
+
 		apiV1Route := apiRoute.Group("/v1")
 		apiV1Route.Use(bindMiddleware(middlewares.JWTAuthorization))
 		{
@@ -376,6 +384,9 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/funds/:fundId/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
 			apiV1Route.POST("/funds/:fundId/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
 			apiV1Route.POST("/funds/:fundId/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler))
+			apiV1Route.GET("/funds/:fundId/accounts/loan/amortization.json", bindApi(api.Accounts.AccountLoanAmortizationHandler))
+			apiV1Route.GET("/funds/:fundId/accounts/credit_card/statements.json", bindApi(api.Accounts.AccountCreditCardStatementsHandler))
+			apiV1Route.GET("/funds/:fundId/accounts/balance_history.json", bindApi(api.Accounts.AccountBalanceHistoryHandler))
 
 			// Legacy account routes (for backward compatibility)
 			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
@@ -386,6 +397,9 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
 			apiV1Route.POST("/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler))
+			apiV1Route.GET("/accounts/loan/amortization.json", bindApi(api.Accounts.AccountLoanAmortizationHandler))
+			apiV1Route.GET("/accounts/credit_card/statements.json", bindApi(api.Accounts.AccountCreditCardStatementsHandler))
+			apiV1Route.GET("/accounts/balance_history.json", bindApi(api.Accounts.AccountBalanceHistoryHandler))
 
 			// Transactions (with fund context)
 			apiV1Route.GET("/funds/:fundId/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
@@ -394,12 +408,28 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/funds/:fundId/transactions/reconciliation_statements.json", bindApi(api.Transactions.TransactionReconciliationStatementHandler))
 			apiV1Route.GET("/funds/:fundId/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
 			apiV1Route.GET("/funds/:fundId/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/statistics/payees.json", bindApi(api.Transactions.TransactionPayeeStatisticsHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/statistics/payees/trends.json", bindApi(api.Transactions.TransactionPayeeStatisticsTrendsHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/forecast.json", bindApi(api.Transactions.TransactionForecastHandler))
 			apiV1Route.GET("/funds/:fundId/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/funds/:fundId/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
 			apiV1Route.POST("/funds/:fundId/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
 			apiV1Route.POST("/funds/:fundId/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/batch_modify.json", bindApi(api.Transactions.TransactionBatchModifyHandler))
 			apiV1Route.POST("/funds/:fundId/transactions/move/all.json", bindApi(api.Transactions.TransactionMoveAllBetweenAccountsHandler))
 			apiV1Route.POST("/funds/:fundId/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/cleared_status/modify.json", bindApi(api.Transactions.TransactionClearedStatusModifyHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/reconciliations/list.json", bindApi(api.Transactions.TransactionReconciliationListHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/reconciliations/get.json", bindApi(api.Transactions.TransactionReconciliationGetHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/reconciliations/add.json", bindApi(api.Transactions.TransactionReconciliationCreateHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/reconciliations/mark.json", bindApi(api.Transactions.TransactionReconciliationMarkHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/reconciliations/complete.json", bindApi(api.Transactions.TransactionReconciliationCompleteHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/reconciliations/delete.json", bindApi(api.Transactions.TransactionReconciliationDeleteHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/revisions/list.json", bindApi(api.Transactions.TransactionRevisionListHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/revisions/restore.json", bindApi(api.Transactions.TransactionRevisionRestoreHandler))
+			apiV1Route.GET("/funds/:fundId/transactions/links/list.json", bindApi(api.Transactions.TransactionLinkListHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/links/add.json", bindApi(api.Transactions.TransactionLinkCreateHandler))
+			apiV1Route.POST("/funds/:fundId/transactions/links/delete.json", bindApi(api.Transactions.TransactionLinkDeleteHandler))
 
 			// Legacy transaction routes (for backward compatibility)
 			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
@@ -408,10 +438,12 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/transactions/reconciliation_statements.json", bindApi(api.Transactions.TransactionReconciliationStatementHandler))
 			apiV1Route.GET("/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
 			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
+			apiV1Route.GET("/transactions/forecast.json", bindApi(api.Transactions.TransactionForecastHandler))
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
 			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
+			apiV1Route.POST("/transactions/batch_modify.json", bindApi(api.Transactions.TransactionBatchModifyHandler))
 			apiV1Route.POST("/transactions/move/all.json", bindApi(api.Transactions.TransactionMoveAllBetweenAccountsHandler))
 			apiV1Route.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
 
@@ -471,7 +503,9 @@ func startWebServer(c *core.CliContext) error {
 			// Transaction Templates (with fund context)
 			apiV1Route.GET("/funds/:fundId/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
 			apiV1Route.GET("/funds/:fundId/transaction/templates/get.json", bindApi(api.TransactionTemplates.TemplateGetHandler))
+			apiV1Route.GET("/funds/:fundId/transaction/templates/scheduled_occurrences.json", bindApi(api.TransactionTemplates.TemplateScheduledOccurrencesHandler))
 			apiV1Route.POST("/funds/:fundId/transaction/templates/add.json", bindApi(api.TransactionTemplates.TemplateCreateHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/templates/loan_payments/add.json", bindApi(api.TransactionTemplates.TemplateLoanPaymentsCreateHandler))
 			apiV1Route.POST("/funds/:fundId/transaction/templates/modify.json", bindApi(api.TransactionTemplates.TemplateModifyHandler))
 			apiV1Route.POST("/funds/:fundId/transaction/templates/hide.json", bindApi(api.TransactionTemplates.TemplateHideHandler))
 			apiV1Route.POST("/funds/:fundId/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler))
@@ -480,7 +514,9 @@ func startWebServer(c *core.CliContext) error {
 			// Legacy transaction template routes (for backward compatibility)
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
 			apiV1Route.GET("/transaction/templates/get.json", bindApi(api.TransactionTemplates.TemplateGetHandler))
+			apiV1Route.GET("/transaction/templates/scheduled_occurrences.json", bindApi(api.TransactionTemplates.TemplateScheduledOccurrencesHandler))
 			apiV1Route.POST("/transaction/templates/add.json", bindApi(api.TransactionTemplates.TemplateCreateHandler))
+			apiV1Route.POST("/transaction/templates/loan_payments/add.json", bindApi(api.TransactionTemplates.TemplateLoanPaymentsCreateHandler))
 			apiV1Route.POST("/transaction/templates/modify.json", bindApi(api.TransactionTemplates.TemplateModifyHandler))
 			apiV1Route.POST("/transaction/templates/hide.json", bindApi(api.TransactionTemplates.TemplateHideHandler))
 			apiV1Route.POST("/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler))
@@ -499,15 +535,78 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/funds/add.json", bindApi(api.Funds.FundCreateHandler))
 			apiV1Route.POST("/funds/:fundId/modify.json", bindApi(api.Funds.FundModifyHandler))
 			apiV1Route.POST("/funds/:fundId/delete.json", bindApi(api.Funds.FundDeleteHandler))
+			apiV1Route.POST("/funds/:fundId/transfer_ownership.json", bindApi(api.Funds.FundOwnershipTransferHandler))
+			apiV1Route.POST("/funds/:fundId/leave.json", bindApi(api.Funds.FundLeaveHandler))
 
 			// Fund Members
 			apiV1Route.GET("/funds/:fundId/members/list.json", bindApi(api.Funds.FundMemberListHandler))
 			apiV1Route.POST("/funds/:fundId/members/add.json", bindApi(api.Funds.FundMemberCreateHandler))
 			apiV1Route.POST("/funds/:fundId/members/delete.json", bindApi(api.Funds.FundMemberDeleteHandler))
 			apiV1Route.POST("/funds/:fundId/members/link.json", bindApi(api.Funds.FundMemberLinkHandler))
+			apiV1Route.POST("/funds/:fundId/members/modify_role.json", bindApi(api.Funds.FundMemberRoleModifyHandler))
+
+			// Fund Invitations
+			apiV1Route.GET("/funds/:fundId/invitations/list.json", bindApi(api.FundInvitations.FundInvitationListHandler))
+			apiV1Route.POST("/funds/:fundId/invitations/add.json", bindApi(api.FundInvitations.FundInvitationCreateHandler))
+			apiV1Route.POST("/funds/:fundId/invitations/revoke.json", bindApi(api.FundInvitations.FundInvitationRevokeHandler))
+			apiV1Route.GET("/funds/invitations/list.json", bindApi(api.FundInvitations.FundInvitationReceivedListHandler))
+			apiV1Route.POST("/funds/invitations/accept.json", bindApi(api.FundInvitations.FundInvitationAcceptHandler))
+			apiV1Route.POST("/funds/invitations/decline.json", bindApi(api.FundInvitations.FundInvitationDeclineHandler))
+
+			// Fund Settlements
+			apiV1Route.GET("/funds/:fundId/settlement.json", bindApi(api.FundSettlements.FundSettlementGetHandler))
+			apiV1Route.POST("/funds/:fundId/settlement/settle.json", bindApi(api.FundSettlements.FundSettlementCreateHandler))
+
+			// Fund Activities
+			apiV1Route.GET("/funds/:fundId/activities/list.json", bindApi(api.FundActivities.FundActivityListHandler))
+
+			// Budgets
+			apiV1Route.GET("/funds/:fundId/budgets/list.json", bindApi(api.Budgets.BudgetListHandler))
+			apiV1Route.GET("/funds/:fundId/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler))
+			apiV1Route.GET("/funds/:fundId/budgets/progress.json", bindApi(api.Budgets.BudgetProgressHandler))
+			apiV1Route.POST("/funds/:fundId/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler))
+			apiV1Route.POST("/funds/:fundId/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler))
+			apiV1Route.POST("/funds/:fundId/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler))
+
+			// Transaction Rules
+			apiV1Route.GET("/funds/:fundId/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler))
+			apiV1Route.GET("/funds/:fundId/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler))
+			apiV1Route.GET("/funds/:fundId/transaction/rules/test.json", bindApi(api.TransactionRules.RuleTestHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/rules/apply.json", bindApi(api.TransactionRules.RuleApplyHandler))
+
+			// Payees
+			apiV1Route.GET("/funds/:fundId/transaction/payees/list.json", bindApi(api.Payees.PayeeListHandler))
+			apiV1Route.GET("/funds/:fundId/transaction/payees/get.json", bindApi(api.Payees.PayeeGetHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/payees/add.json", bindApi(api.Payees.PayeeCreateHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/payees/modify.json", bindApi(api.Payees.PayeeModifyHandler))
+			apiV1Route.POST("/funds/:fundId/transaction/payees/delete.json", bindApi(api.Payees.PayeeDeleteHandler))
+
+			// Investments
+			apiV1Route.GET("/funds/:fundId/investments/securities/list.json", bindApi(api.Investments.SecurityListHandler))
+			apiV1Route.POST("/funds/:fundId/investments/securities/add.json", bindApi(api.Investments.SecurityCreateHandler))
+			apiV1Route.POST("/funds/:fundId/investments/securities/modify.json", bindApi(api.Investments.SecurityModifyHandler))
+			apiV1Route.POST("/funds/:fundId/investments/securities/delete.json", bindApi(api.Investments.SecurityDeleteHandler))
+			apiV1Route.GET("/funds/:fundId/investments/trades/list.json", bindApi(api.Investments.TradeListHandler))
+			apiV1Route.POST("/funds/:fundId/investments/trades/add.json", bindApi(api.Investments.TradeCreateHandler))
+			apiV1Route.POST("/funds/:fundId/investments/trades/delete.json", bindApi(api.Investments.TradeDeleteHandler))
+			apiV1Route.GET("/funds/:fundId/investments/prices/list.json", bindApi(api.Investments.SecurityPriceListHandler))
+			apiV1Route.POST("/funds/:fundId/investments/prices/add.json", bindApi(api.Investments.SecurityPriceAddHandler))
+			apiV1Route.POST("/funds/:fundId/investments/prices/import.json", bindApi(api.Investments.SecurityPriceImportHandler))
+			apiV1Route.GET("/funds/:fundId/investments/holdings.json", bindApi(api.Investments.HoldingsHandler))
+
+			// Trash Bin
+			apiV1Route.GET("/trash/list.json", bindApi(api.Trash.TrashListHandler))
+			apiV1Route.POST("/trash/restore.json", bindApi(api.Trash.TrashRestoreHandler))
+			apiV1Route.GET("/funds/:fundId/trash/list.json", bindApi(api.Trash.TrashListHandler))
+			apiV1Route.POST("/funds/:fundId/trash/restore.json", bindApi(api.Trash.TrashRestoreHandler))
 
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/history.json", bindApi(api.ExchangeRates.HistoricalExchangeRatesHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler))
 
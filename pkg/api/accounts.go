@@ -2,10 +2,12 @@ package api
 
 import (
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
@@ -18,7 +20,9 @@ import (
 type AccountsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	accounts *services.AccountService
+	users                *services.UserService
+	accounts             *services.AccountService
+	creditCardStatements *services.CreditCardStatementService
 }
 
 // Initialize an account api singleton instance
@@ -33,7 +37,9 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		accounts: services.Accounts,
+		users:                services.Users,
+		accounts:             services.Accounts,
+		creditCardStatements: services.CreditCardStatements,
 	}
 )
 
@@ -50,7 +56,7 @@ func (a *AccountsApi) AccountListHandler(c *core.WebContext) (any, *errs.Error)
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -117,7 +123,7 @@ func (a *AccountsApi) AccountGetHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -181,6 +187,33 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrCannotSetStatementDateForNonCreditCard
 	}
 
+	if accountCreateReq.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD && accountCreateReq.CreditCardLimit != 0 {
+		log.Warnf(c, "[accounts.AccountCreateHandler] cannot set credit limit with category \"%d\"", accountCreateReq.Category)
+		return nil, errs.ErrCannotSetCreditLimitForNonCreditCard
+	}
+
+	if accountCreateReq.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD && accountCreateReq.HasCreditCardPaymentSettings() {
+		log.Warnf(c, "[accounts.AccountCreateHandler] cannot set payment settings with category \"%d\"", accountCreateReq.Category)
+		return nil, errs.ErrCannotSetPaymentSettingsForNonCreditCard
+	}
+
+	if accountCreateReq.CreditCardDueReminderDays != 0 && accountCreateReq.CreditCardDueReminderDays >= accountCreateReq.CreditCardPaymentDueDays {
+		log.Warnf(c, "[accounts.AccountCreateHandler] due reminder days \"%d\" is not less than payment due days \"%d\"", accountCreateReq.CreditCardDueReminderDays, accountCreateReq.CreditCardPaymentDueDays)
+		return nil, errs.ErrCreditCardDueReminderDaysInvalid
+	}
+
+	if accountCreateReq.Category != models.ACCOUNT_CATEGORY_DEBT && accountCreateReq.Loan != nil {
+		log.Warnf(c, "[accounts.AccountCreateHandler] cannot set loan info with category \"%d\"", accountCreateReq.Category)
+		return nil, errs.ErrCannotSetLoanInfoForNonDebtAccount
+	}
+
+	if accountCreateReq.Loan != nil {
+		if _, err := accountCreateReq.Loan.ToAccountLoanInfo(); err != nil {
+			log.Warnf(c, "[accounts.AccountCreateHandler] loan info is invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrLoanInfoInvalid)
+		}
+	}
+
 	if accountCreateReq.Type == models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
 		if len(accountCreateReq.SubAccounts) > 0 {
 			log.Warnf(c, "[accounts.AccountCreateHandler] account cannot have any sub-accounts")
@@ -239,6 +272,21 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 				log.Warnf(c, "[accounts.AccountCreateHandler] sub-account#%d cannot set statement date", i)
 				return nil, errs.ErrCannotSetStatementDateForSubAccount
 			}
+
+			if subAccount.CreditCardLimit != 0 {
+				log.Warnf(c, "[accounts.AccountCreateHandler] sub-account#%d cannot set credit limit", i)
+				return nil, errs.ErrCannotSetCreditLimitForSubAccount
+			}
+
+			if subAccount.HasCreditCardPaymentSettings() {
+				log.Warnf(c, "[accounts.AccountCreateHandler] sub-account#%d cannot set payment settings", i)
+				return nil, errs.ErrCannotSetPaymentSettingsForSubAccount
+			}
+
+			if subAccount.Loan != nil {
+				log.Warnf(c, "[accounts.AccountCreateHandler] sub-account#%d cannot set loan info", i)
+				return nil, errs.ErrCannotSetLoanInfoForSubAccount
+			}
 		}
 	} else {
 		log.Warnf(c, "[accounts.AccountCreateHandler] account type invalid, type is %d", accountCreateReq.Type)
@@ -248,7 +296,7 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_CREATE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -271,12 +319,6 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 			accountId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				// Get fundId for this account lookup
-				fundId, fundErr := GetFundIdFromContext(c, uid)
-				if fundErr != nil {
-					return nil, fundErr
-				}
-
 				accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, fundId, accountId)
 
 				if err != nil {
@@ -359,10 +401,37 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrCannotSetStatementDateForNonCreditCard
 	}
 
+	if accountModifyReq.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD && accountModifyReq.CreditCardLimit != 0 {
+		log.Warnf(c, "[accounts.AccountModifyHandler] cannot set credit limit with category \"%d\"", accountModifyReq.Category)
+		return nil, errs.ErrCannotSetCreditLimitForNonCreditCard
+	}
+
+	if accountModifyReq.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD && accountModifyReq.HasCreditCardPaymentSettings() {
+		log.Warnf(c, "[accounts.AccountModifyHandler] cannot set payment settings with category \"%d\"", accountModifyReq.Category)
+		return nil, errs.ErrCannotSetPaymentSettingsForNonCreditCard
+	}
+
+	if accountModifyReq.CreditCardDueReminderDays != 0 && accountModifyReq.CreditCardDueReminderDays >= accountModifyReq.CreditCardPaymentDueDays {
+		log.Warnf(c, "[accounts.AccountModifyHandler] due reminder days \"%d\" is not less than payment due days \"%d\"", accountModifyReq.CreditCardDueReminderDays, accountModifyReq.CreditCardPaymentDueDays)
+		return nil, errs.ErrCreditCardDueReminderDaysInvalid
+	}
+
+	if accountModifyReq.Category != models.ACCOUNT_CATEGORY_DEBT && accountModifyReq.Loan != nil {
+		log.Warnf(c, "[accounts.AccountModifyHandler] cannot set loan info with category \"%d\"", accountModifyReq.Category)
+		return nil, errs.ErrCannotSetLoanInfoForNonDebtAccount
+	}
+
+	if accountModifyReq.Loan != nil {
+		if _, err := accountModifyReq.Loan.ToAccountLoanInfo(); err != nil {
+			log.Warnf(c, "[accounts.AccountModifyHandler] loan info is invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrLoanInfoInvalid)
+		}
+	}
+
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -459,6 +528,21 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 				log.Warnf(c, "[accounts.AccountModifyHandler] sub-account#%d cannot set statement date", i)
 				return nil, errs.ErrCannotSetStatementDateForSubAccount
 			}
+
+			if subAccountReq.CreditCardLimit != 0 {
+				log.Warnf(c, "[accounts.AccountModifyHandler] sub-account#%d cannot set credit limit", i)
+				return nil, errs.ErrCannotSetCreditLimitForSubAccount
+			}
+
+			if subAccountReq.HasCreditCardPaymentSettings() {
+				log.Warnf(c, "[accounts.AccountModifyHandler] sub-account#%d cannot set payment settings", i)
+				return nil, errs.ErrCannotSetPaymentSettingsForSubAccount
+			}
+
+			if subAccountReq.Loan != nil {
+				log.Warnf(c, "[accounts.AccountModifyHandler] sub-account#%d cannot set loan info", i)
+				return nil, errs.ErrCannotSetLoanInfoForSubAccount
+			}
 		}
 	}
 
@@ -644,7 +728,7 @@ func (a *AccountsApi) AccountHideHandler(c *core.WebContext) (any, *errs.Error)
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -685,7 +769,7 @@ func (a *AccountsApi) AccountMoveHandler(c *core.WebContext) (any, *errs.Error)
 	}
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -714,7 +798,7 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.WebContext) (any, *errs.Error
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_DELETE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -743,7 +827,7 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 	uid := c.GetCurrentUid()
 
 	// Get fundId from URL parameter or use default personal fund
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_DELETE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -759,11 +843,215 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
+// AccountLoanAmortizationHandler returns the amortization schedule of one specific debt account with loan info of current user
+func (a *AccountsApi) AccountLoanAmortizationHandler(c *core.WebContext) (any, *errs.Error) {
+	var amortizationReq models.LoanAmortizationRequest
+	err := c.ShouldBindQuery(&amortizationReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountLoanAmortizationHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	account, err := a.accounts.GetAccountByAccountId(c, uid, fundId, amortizationReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountLoanAmortizationHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", amortizationReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loanInfo := account.GetLoanInfo()
+
+	if loanInfo == nil {
+		return nil, errs.ErrAccountHasNoLoanInfo
+	}
+
+	schedule, err := loanInfo.GetAmortizationSchedule()
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountLoanAmortizationHandler] failed to get amortization schedule of account \"id:%d\" for user \"uid:%d\", because %s", amortizationReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return account.ToLoanAmortizationResponse(schedule), nil
+}
+
+// AccountBalanceHistoryHandler returns the end-of-period balances of all accounts and the net worth in default currency of current user
+func (a *AccountsApi) AccountBalanceHistoryHandler(c *core.WebContext) (any, *errs.Error) {
+	var historyReq models.AccountBalanceHistoryRequest
+	err := c.ShouldBindQuery(&historyReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountBalanceHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountBalanceHistoryHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountBalanceHistoryHandler] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	periods, err := models.GetAccountBalanceHistoryPeriods(historyReq.StartTime, historyReq.EndTime, historyReq.PeriodType, user.FirstDayOfWeek, clientTimezone)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountBalanceHistoryHandler] failed to get periods for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allAccounts, err := a.accounts.GetAllAccountsByUid(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountBalanceHistoryHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts := make([]*models.Account, 0, len(allAccounts))
+	needExchangeRates := false
+
+	for i := 0; i < len(allAccounts); i++ {
+		if allAccounts[i].Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			continue
+		}
+
+		accounts = append(accounts, allAccounts[i])
+
+		if allAccounts[i].Currency != user.DefaultCurrency {
+			needExchangeRates = true
+		}
+	}
+
+	history, err := a.accounts.GetAccountBalanceHistory(c, uid, fundId, accounts, periods)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountBalanceHistoryHandler] failed to get balance history for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var exchangeRates *models.LatestExchangeRateResponse
+
+	if needExchangeRates {
+		exchangeRates, err = exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
+
+		if err != nil {
+			log.Errorf(c, "[accounts.AccountBalanceHistoryHandler] failed to get latest exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	historyResp, err := models.ToAccountBalanceHistoryResponse(periods, accounts, history, user.DefaultCurrency, exchangeRates)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountBalanceHistoryHandler] failed to convert balance history to default currency \"%s\" for user \"uid:%d\", because %s", user.DefaultCurrency, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return historyResp, nil
+}
+
+// AccountCreditCardStatementsHandler returns the latest statement cycles of one specific credit card account of current user
+func (a *AccountsApi) AccountCreditCardStatementsHandler(c *core.WebContext) (any, *errs.Error) {
+	var statementsReq models.CreditCardStatementsRequest
+	err := c.ShouldBindQuery(&statementsReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountCreditCardStatementsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if statementsReq.Count < 1 {
+		statementsReq.Count = 1
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountCreditCardStatementsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_ACCOUNT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	account, err := a.accounts.GetAccountByAccountId(c, uid, fundId, statementsReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountCreditCardStatementsHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", statementsReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD || account.ParentAccountId != models.LevelOneAccountParentId {
+		return nil, errs.ErrAccountCategoryInvalid
+	}
+
+	statementsResp, err := a.creditCardStatements.GetStatements(c, account, time.Now().Unix(), clientTimezone, statementsReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountCreditCardStatementsHandler] failed to get statements of account \"id:%d\" for user \"uid:%d\", because %s", statementsReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return statementsResp, nil
+}
+
 func (a *AccountsApi) createNewAccountModel(uid int64, fundId int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
 	if !isSubAccount && accountCreateReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
 		accountExtend.CreditCardStatementDate = &accountCreateReq.CreditCardStatementDate
+
+		if accountCreateReq.CreditCardLimit > 0 {
+			accountExtend.CreditCardLimit = &accountCreateReq.CreditCardLimit
+		}
+
+		if accountCreateReq.CreditCardPaymentDueDays > 0 {
+			accountExtend.CreditCardPaymentDueDays = &accountCreateReq.CreditCardPaymentDueDays
+		}
+
+		if accountCreateReq.CreditCardMinimumPaymentRate > 0 {
+			accountExtend.CreditCardMinimumPaymentRate = &accountCreateReq.CreditCardMinimumPaymentRate
+		}
+
+		if accountCreateReq.CreditCardMinimumPaymentAmount > 0 {
+			accountExtend.CreditCardMinimumPaymentAmount = &accountCreateReq.CreditCardMinimumPaymentAmount
+		}
+
+		if accountCreateReq.CreditCardDueReminderDays > 0 {
+			accountExtend.CreditCardDueReminderDays = &accountCreateReq.CreditCardDueReminderDays
+		}
+	}
+
+	if !isSubAccount && accountCreateReq.Category == models.ACCOUNT_CATEGORY_DEBT && accountCreateReq.Loan != nil {
+		accountExtend.Loan, _ = accountCreateReq.Loan.ToAccountLoanInfo()
 	}
 
 	return &models.Account{
@@ -822,6 +1110,30 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 
 	if !isSubAccount && accountModifyReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
 		newAccountExtend.CreditCardStatementDate = &accountModifyReq.CreditCardStatementDate
+
+		if accountModifyReq.CreditCardLimit > 0 {
+			newAccountExtend.CreditCardLimit = &accountModifyReq.CreditCardLimit
+		}
+
+		if accountModifyReq.CreditCardPaymentDueDays > 0 {
+			newAccountExtend.CreditCardPaymentDueDays = &accountModifyReq.CreditCardPaymentDueDays
+		}
+
+		if accountModifyReq.CreditCardMinimumPaymentRate > 0 {
+			newAccountExtend.CreditCardMinimumPaymentRate = &accountModifyReq.CreditCardMinimumPaymentRate
+		}
+
+		if accountModifyReq.CreditCardMinimumPaymentAmount > 0 {
+			newAccountExtend.CreditCardMinimumPaymentAmount = &accountModifyReq.CreditCardMinimumPaymentAmount
+		}
+
+		if accountModifyReq.CreditCardDueReminderDays > 0 {
+			newAccountExtend.CreditCardDueReminderDays = &accountModifyReq.CreditCardDueReminderDays
+		}
+	}
+
+	if !isSubAccount && accountModifyReq.Category == models.ACCOUNT_CATEGORY_DEBT && accountModifyReq.Loan != nil {
+		newAccountExtend.Loan, _ = accountModifyReq.Loan.ToAccountLoanInfo()
 	}
 
 	newAccount := &models.Account{
@@ -857,6 +1169,26 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 		return newAccount
 	}
 
+	if newAccount.GetCreditCardLimit() != oldAccount.GetCreditCardLimit() {
+		return newAccount
+	}
+
+	if newAccount.GetCreditCardPaymentDueDays() != oldAccount.GetCreditCardPaymentDueDays() ||
+		newAccount.GetCreditCardDueReminderDays() != oldAccount.GetCreditCardDueReminderDays() {
+		return newAccount
+	}
+
+	newMinimumPaymentRate, newMinimumPaymentAmount := newAccount.GetCreditCardMinimumPayment()
+	oldMinimumPaymentRate, oldMinimumPaymentAmount := oldAccount.GetCreditCardMinimumPayment()
+
+	if newMinimumPaymentRate != oldMinimumPaymentRate || newMinimumPaymentAmount != oldMinimumPaymentAmount {
+		return newAccount
+	}
+
+	if !newAccount.GetLoanInfo().Equals(oldAccount.GetLoanInfo()) {
+		return newAccount
+	}
+
 	return nil
 }
 
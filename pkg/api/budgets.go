@@ -0,0 +1,517 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	ApiUsingConfig
+	budgets      *services.BudgetService
+	funds        *services.FundService
+	users        *services.UserService
+	accounts     *services.AccountService
+	categories   *services.TransactionCategoryService
+	transactions *services.TransactionService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		budgets:      services.Budgets,
+		funds:        services.Funds,
+		users:        services.Users,
+		accounts:     services.Accounts,
+		categories:   services.TransactionCategories,
+		transactions: services.Transactions,
+	}
+)
+
+// BudgetListHandler returns all budgets of current fund which are visible to current user
+func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budgets, err := a.budgets.GetAllBudgetsByFundId(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make(models.BudgetInfoResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	sort.Sort(budgetResps)
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current fund
+func (a *BudgetsApi) BudgetGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, fundId, budgetGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current fund
+func (a *BudgetsApi) BudgetCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budget := &models.Budget{
+		Uid:        uid,
+		FundId:     fundId,
+		Scope:      budgetCreateReq.Scope,
+		Name:       budgetCreateReq.Name,
+		CategoryId: budgetCreateReq.CategoryId,
+		PeriodType: budgetCreateReq.PeriodType,
+		Currency:   budgetCreateReq.Currency,
+		Amount:     budgetCreateReq.Amount,
+		Rollover:   budgetCreateReq.Rollover,
+		Comment:    budgetCreateReq.Comment,
+	}
+
+	if errPermission := a.checkBudgetPermission(c, uid, budget, models.FUND_ACTION_CREATE); errPermission != nil {
+		return nil, errPermission
+	}
+
+	if errCategory := a.checkBudgetCategory(c, uid, fundId, budget.CategoryId); errCategory != nil {
+		return nil, errCategory
+	}
+
+	err = a.budgets.CreateBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to create budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current fund
+func (a *BudgetsApi) BudgetModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, fundId, budgetModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if errPermission := a.checkBudgetPermission(c, uid, budget, models.FUND_ACTION_MODIFY); errPermission != nil {
+		return nil, errPermission
+	}
+
+	newBudget := &models.Budget{
+		BudgetId:        budget.BudgetId,
+		Uid:             budget.Uid,
+		FundId:          budget.FundId,
+		Scope:           budget.Scope,
+		Name:            budgetModifyReq.Name,
+		CategoryId:      budgetModifyReq.CategoryId,
+		PeriodType:      budgetModifyReq.PeriodType,
+		Currency:        budgetModifyReq.Currency,
+		Amount:          budgetModifyReq.Amount,
+		Rollover:        budgetModifyReq.Rollover,
+		Comment:         budgetModifyReq.Comment,
+		CreatedUnixTime: budget.CreatedUnixTime,
+	}
+
+	if newBudget.Name == budget.Name &&
+		newBudget.CategoryId == budget.CategoryId &&
+		newBudget.PeriodType == budget.PeriodType &&
+		newBudget.Currency == budget.Currency &&
+		newBudget.Amount == budget.Amount &&
+		newBudget.Rollover == budget.Rollover &&
+		newBudget.Comment == budget.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	if newBudget.CategoryId != budget.CategoryId {
+		if errCategory := a.checkBudgetCategory(c, uid, fundId, newBudget.CategoryId); errCategory != nil {
+			return nil, errCategory
+		}
+	}
+
+	err = a.budgets.ModifyBudget(c, uid, newBudget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	return newBudget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current fund
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, fundId, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if errPermission := a.checkBudgetPermission(c, uid, budget, models.FUND_ACTION_DELETE); errPermission != nil {
+		return nil, errPermission
+	}
+
+	err = a.budgets.DeleteBudget(c, uid, fundId, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+// BudgetProgressHandler returns the limit and the spending of a budget in the requested period
+func (a *BudgetsApi) BudgetProgressHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetProgressReq models.BudgetProgressRequest
+	err := c.ShouldBindQuery(&budgetProgressReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, fundId, budgetProgressReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetProgressReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrUserNotFound)
+	}
+
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	currentTime := budgetProgressReq.Time
+
+	if currentTime <= 0 {
+		currentTime = time.Now().Unix()
+	}
+
+	startTime, endTime, err := budget.PeriodType.GetPeriodRange(currentTime, user.FiscalYearStart, clientLocation)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get period of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryIds, errCategory := a.getBudgetCategoryIds(c, budget)
+
+	if errCategory != nil {
+		return nil, errCategory
+	}
+
+	memberUids, errMember := a.getBudgetMemberUids(c, uid, budget)
+
+	if errMember != nil {
+		return nil, errMember
+	}
+
+	var exchangeRates *models.LatestExchangeRateResponse
+
+	spentAmount, errSpent := a.getBudgetSpentAmount(c, uid, budget, memberUids, categoryIds, startTime, endTime, utcOffset, &exchangeRates)
+
+	if errSpent != nil {
+		return nil, errSpent
+	}
+
+	rolloverAmount := int64(0)
+
+	if budget.Rollover {
+		rolloverStartTime, rolloverPeriodCount, err := budget.GetRolloverPeriods(startTime, user.FiscalYearStart, clientLocation)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get previous periods of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if rolloverPeriodCount > 0 {
+			previousSpentAmount, errSpent := a.getBudgetSpentAmount(c, uid, budget, memberUids, categoryIds, rolloverStartTime, startTime-1, utcOffset, &exchangeRates)
+
+			if errSpent != nil {
+				return nil, errSpent
+			}
+
+			rolloverAmount = budget.GetRolloverAmount(rolloverPeriodCount, previousSpentAmount)
+		}
+	}
+
+	return budget.ToBudgetProgressResponse(startTime, endTime, rolloverAmount, spentAmount), nil
+}
+
+func (a *BudgetsApi) checkBudgetPermission(c *core.WebContext, uid int64, budget *models.Budget, action models.FundAction) *errs.Error {
+	if budget.Scope == models.BUDGET_SCOPE_PERSONAL {
+		if budget.Uid != uid {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	}
+
+	if budget.Scope != models.BUDGET_SCOPE_FUND {
+		return errs.ErrBudgetScopeInvalid
+	}
+
+	permission, err := a.funds.GetUserPermissionInFund(c, uid, budget.FundId, models.FUND_RESOURCE_BUDGET, action)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.checkBudgetPermission] user uid:%d cannot perform action \"%s\" on budgets of fund id:%d, because %s", uid, action, budget.FundId, err.Error())
+		return errs.Or(err, errs.ErrFundPermissionDenied)
+	}
+
+	if !permission.IsAllowedOn(uid, budget.Uid) {
+		return errs.ErrFundPermissionDenied
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) checkBudgetCategory(c *core.WebContext, uid int64, fundId int64, categoryId int64) *errs.Error {
+	category, err := a.categories.GetCategoryByCategoryId(c, uid, fundId, categoryId)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.checkBudgetCategory] failed to get category \"id:%d\" for user \"uid:%d\", because %s", categoryId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if category.Type != models.CATEGORY_TYPE_EXPENSE {
+		log.Warnf(c, "[budgets.checkBudgetCategory] category \"id:%d\" is not an expense category", categoryId)
+		return errs.ErrBudgetCategoryTypeInvalid
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) getBudgetCategoryIds(c *core.WebContext, budget *models.Budget) (map[int64]bool, *errs.Error) {
+	category, err := a.categories.GetCategoryByCategoryId(c, budget.Uid, budget.FundId, budget.CategoryId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.getBudgetCategoryIds] failed to get category \"id:%d\" of budget \"id:%d\", because %s", budget.CategoryId, budget.BudgetId, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryIds := map[int64]bool{
+		category.CategoryId: true,
+	}
+
+	if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		return categoryIds, nil
+	}
+
+	subCategories, err := a.categories.GetSubCategoriesByCategoryIds(c, budget.Uid, budget.FundId, []int64{category.CategoryId})
+
+	if err != nil {
+		log.Errorf(c, "[budgets.getBudgetCategoryIds] failed to get sub categories of category \"id:%d\" of budget \"id:%d\", because %s", budget.CategoryId, budget.BudgetId, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for _, subCategory := range subCategories {
+		categoryIds[subCategory.CategoryId] = true
+	}
+
+	return categoryIds, nil
+}
+
+func (a *BudgetsApi) getBudgetMemberUids(c *core.WebContext, uid int64, budget *models.Budget) ([]int64, *errs.Error) {
+	if budget.Scope != models.BUDGET_SCOPE_FUND {
+		return []int64{budget.Uid}, nil
+	}
+
+	members, err := a.funds.GetFundMembersByFundId(c, uid, budget.FundId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.getBudgetMemberUids] failed to get members of fund \"id:%d\" for user \"uid:%d\", because %s", budget.FundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	memberUids := make([]int64, 0, len(members))
+
+	for _, member := range members {
+		if member.LinkedUid > 0 {
+			memberUids = append(memberUids, member.LinkedUid)
+		}
+	}
+
+	return utils.ToUniqueInt64Slice(memberUids), nil
+}
+
+func (a *BudgetsApi) getBudgetSpentAmount(c *core.WebContext, uid int64, budget *models.Budget, memberUids []int64, categoryIds map[int64]bool, startTime int64, endTime int64, utcOffset int16, exchangeRates **models.LatestExchangeRateResponse) (int64, *errs.Error) {
+	spentAmount := int64(0)
+
+	for _, memberUid := range memberUids {
+		totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, memberUid, budget.FundId, startTime, endTime, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", nil, utcOffset, false, false, nil)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.getBudgetSpentAmount] failed to get total expense of user \"uid:%d\" for budget \"id:%d\", because %s", memberUid, budget.BudgetId, err.Error())
+			return 0, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		expenseAmounts := make([]*models.Transaction, 0, len(totalAmounts))
+		accountIds := make([]int64, 0, len(totalAmounts))
+
+		for _, totalAmount := range totalAmounts {
+			if totalAmount.Type != models.TRANSACTION_DB_TYPE_EXPENSE || !categoryIds[totalAmount.CategoryId] {
+				continue
+			}
+
+			expenseAmounts = append(expenseAmounts, totalAmount)
+			accountIds = append(accountIds, totalAmount.AccountId)
+		}
+
+		if len(expenseAmounts) < 1 {
+			continue
+		}
+
+		accounts, err := a.accounts.GetAccountsByAccountIds(c, memberUid, budget.FundId, utils.ToUniqueInt64Slice(accountIds))
+
+		if err != nil {
+			log.Errorf(c, "[budgets.getBudgetSpentAmount] failed to get accounts of user \"uid:%d\" for budget \"id:%d\", because %s", memberUid, budget.BudgetId, err.Error())
+			return 0, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		for _, expenseAmount := range expenseAmounts {
+			account, exists := accounts[expenseAmount.AccountId]
+
+			if !exists {
+				log.Warnf(c, "[budgets.getBudgetSpentAmount] expense of account \"id:%d\" is skipped, because the account does not exist", expenseAmount.AccountId)
+				continue
+			}
+
+			if account.Currency == budget.Currency {
+				spentAmount += expenseAmount.Amount
+				continue
+			}
+
+			if *exchangeRates == nil {
+				*exchangeRates, err = exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
+
+				if err != nil {
+					log.Errorf(c, "[budgets.getBudgetSpentAmount] failed to get latest exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+					return 0, errs.Or(err, errs.ErrOperationFailed)
+				}
+			}
+
+			amount, exists := (*exchangeRates).ConvertAmount(expenseAmount.Amount, account.Currency, budget.Currency)
+
+			if !exists {
+				log.Warnf(c, "[budgets.getBudgetSpentAmount] cannot convert currency \"%s\" to budget currency \"%s\" for user \"uid:%d\"", account.Currency, budget.Currency, uid)
+				return 0, errs.ErrBudgetExchangeRateNotFound
+			}
+
+			spentAmount += amount
+		}
+	}
+
+	return spentAmount, nil
+}
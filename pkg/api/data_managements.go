@@ -29,6 +29,7 @@ type DataManagementsApi struct {
 	tags                    *services.TransactionTagService
 	pictures                *services.TransactionPictureService
 	templates               *services.TransactionTemplateService
+	transactionSplits       *services.TransactionSplitService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
 }
 
@@ -46,6 +47,7 @@ var (
 		tags:                    services.TransactionTags,
 		pictures:                services.TransactionPictures,
 		templates:               services.TransactionTemplates,
+		transactionSplits:       services.TransactionSplits,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
 	}
 )
@@ -422,20 +424,27 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, exportTransactionDataReq.TagFilterType, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, exportTransactionDataReq.TagFilterType, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, nil, pageCountForDataExport, true)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
+	allSplits, err := a.transactionSplits.GetSplitsByTransactionIds(c, uid, a.transactions.GetTransactionIds(allTransactions))
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get transaction split line items for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
 		return nil, "", errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes, allSplits)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get csv format exported data for \"uid:%d\", because %s", uid, err.Error())
@@ -15,6 +15,7 @@ type ExchangeRatesApi struct {
 	ApiUsingConfig
 	users                   *services.UserService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	historicalExchangeRates *services.HistoricalExchangeRateService
 }
 
 // Initialize a exchange rate api singleton instance
@@ -25,6 +26,7 @@ var (
 		},
 		users:                   services.Users,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		historicalExchangeRates: services.HistoricalExchangeRates,
 	}
 )
 
@@ -39,6 +41,50 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// ExchangeRatesMetricsHandler returns the metrics of requesting latest exchange rates from the exchange rates data sources
+func (a *ExchangeRatesApi) ExchangeRatesMetricsHandler(c *core.WebContext) (any, *errs.Error) {
+	return exchangerates.Container.GetMetrics(), nil
+}
+
+// HistoricalExchangeRatesHandler returns the stored exchange rates history of the current exchange rates data source
+func (a *ExchangeRatesApi) HistoricalExchangeRatesHandler(c *core.WebContext) (any, *errs.Error) {
+	var historicalExchangeRatesReq models.HistoricalExchangeRatesRequest
+	err := c.ShouldBindQuery(&historicalExchangeRatesReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRatesHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	err = models.ValidateHistoricalExchangeRatesDateRange(historicalExchangeRatesReq.StartDate, historicalExchangeRatesReq.EndDate)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRatesHandler] date range \"%s\" - \"%s\" is invalid, because %s", historicalExchangeRatesReq.StartDate, historicalExchangeRatesReq.EndDate, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+
+	if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	var currencies []string
+
+	if historicalExchangeRatesReq.Currency != "" {
+		currencies = []string{historicalExchangeRatesReq.Currency}
+	}
+
+	rates, err := a.historicalExchangeRates.GetExchangeRatesByDateRange(c, dataSource, historicalExchangeRatesReq.StartDate, historicalExchangeRatesReq.EndDate, currencies)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.HistoricalExchangeRatesHandler] failed to get exchange rates history of \"%s\" for user \"uid:%d\", because %s", dataSource, c.GetCurrentUid(), err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return models.ToHistoricalExchangeRatesResponses(rates), nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
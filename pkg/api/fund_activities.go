@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// FundActivitiesApi represents fund activity api
+type FundActivitiesApi struct {
+	fundActivities *services.FundActivityService
+}
+
+// Initialize a fund activity api singleton instance
+var (
+	FundActivities = &FundActivitiesApi{
+		fundActivities: services.FundActivities,
+	}
+)
+
+// FundActivityListHandler returns the activities of a fund by the filters
+func (a *FundActivitiesApi) FundActivityListHandler(c *core.WebContext) (any, *errs.Error) {
+	var activityListReq models.FundActivityListRequest
+	err := c.ShouldBindQuery(&activityListReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_activities.FundActivityListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId from URL context parameter
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	totalCount, err := a.fundActivities.GetActivityCountByFundId(c, uid, fundId, activityListReq.ActorUid, activityListReq.EntityType, activityListReq.MaxTime, activityListReq.MinTime)
+
+	if err != nil {
+		log.Errorf(c, "[fund_activities.FundActivityListHandler] failed to get activity count of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	activities, err := a.fundActivities.GetActivitiesByFundId(c, uid, fundId, activityListReq.ActorUid, activityListReq.EntityType, activityListReq.MaxTime, activityListReq.MinTime, activityListReq.Page, activityListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[fund_activities.FundActivityListHandler] failed to get activities of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	activityResps := make([]*models.FundActivityInfoResponse, len(activities))
+
+	for i := 0; i < len(activities); i++ {
+		activityResps[i] = activities[i].ToFundActivityInfoResponse()
+	}
+
+	return &models.FundActivityInfoPageWrapperResponse{
+		Items:      activityResps,
+		TotalCount: totalCount,
+	}, nil
+}
@@ -0,0 +1,334 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// FundInvitationsApi represents fund invitation api
+type FundInvitationsApi struct {
+	ApiUsingConfig
+	users           *services.UserService
+	tokens          *services.TokenService
+	funds           *services.FundService
+	fundInvitations *services.FundInvitationService
+}
+
+// Initialize a fund invitation api singleton instance
+var (
+	FundInvitations = &FundInvitationsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		users:           services.Users,
+		tokens:          services.Tokens,
+		funds:           services.Funds,
+		fundInvitations: services.FundInvitations,
+	}
+)
+
+// FundInvitationListHandler returns pending invitations of a specific fund
+func (a *FundInvitationsApi) FundInvitationListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	fundId, errFund := GetFundIdFromContext(c, uid)
+
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	invitations, err := a.fundInvitations.GetPendingInvitationsByFundId(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationListHandler] failed to get invitations of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	invitationResps := make(models.FundInvitationInfoResponseSlice, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		invitationResps[i] = invitations[i].ToFundInvitationInfoResponse("")
+	}
+
+	sort.Sort(invitationResps)
+
+	return invitationResps, nil
+}
+
+// FundInvitationCreateHandler sends an invitation to the email of an unlinked fund member
+func (a *FundInvitationsApi) FundInvitationCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationCreateReq models.FundInvitationCreateRequest
+	err := c.ShouldBindJSON(&invitationCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.FundInvitationCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, errFund := GetFundIdFromContext(c, uid)
+
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	fund, err := a.funds.GetFundByFundId(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to get fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	member, err := a.funds.GetFundMemberByMemberId(c, uid, invitationCreateReq.MemberId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to get fund member \"id:%d\" for user \"uid:%d\", because %s", invitationCreateReq.MemberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if member.FundId != fundId {
+		log.Warnf(c, "[fund_invitations.FundInvitationCreateHandler] fund member \"id:%d\" does not belong to fund \"id:%d\"", member.MemberId, fundId)
+		return nil, errs.ErrMemberNotFound
+	}
+
+	if member.Email == "" {
+		return nil, errs.ErrFundInvitationEmailIsEmpty
+	}
+
+	inviter, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	invitee, err := a.users.GetUserByEmail(c, member.Email)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to get invitee user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrFundInviteeNotFound
+	}
+
+	if invitee.Disabled {
+		log.Warnf(c, "[fund_invitations.FundInvitationCreateHandler] invitee user \"uid:%d\" is disabled", invitee.Uid)
+		return nil, errs.ErrFundInviteeNotFound
+	}
+
+	invitation, err := a.fundInvitations.CreateInvitation(c, uid, member, invitee.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to create invitation of member \"id:%d\" for user \"uid:%d\", because %s", member.MemberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fund_invitations.FundInvitationCreateHandler] user \"uid:%d\" has invited user \"uid:%d\" to fund \"id:%d\" as member \"id:%d\"", uid, invitee.Uid, fundId, member.MemberId)
+
+	if a.CurrentConfig().EnableSMTP {
+		tokenContext, err := json.Marshal(&models.FundInvitationTokenContext{
+			InvitationId: invitation.InvitationId,
+		})
+
+		if err != nil {
+			log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to marshal token context for invitation \"id:%d\", because %s", invitation.InvitationId, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+
+		token, _, err := a.tokens.CreateFundInvitationToken(c, invitee, string(tokenContext))
+
+		if err != nil {
+			log.Errorf(c, "[fund_invitations.FundInvitationCreateHandler] failed to create invitation token for user \"uid:%d\", because %s", invitee.Uid, err.Error())
+		} else {
+			go func() {
+				err = a.fundInvitations.SendInvitationEmail(c, inviter, invitee, fund, token, c.GetClientLocale())
+
+				if err != nil {
+					log.Warnf(c, "[fund_invitations.FundInvitationCreateHandler] cannot send invitation email to \"%s\", because %s", invitee.Email, err.Error())
+				}
+			}()
+		}
+	}
+
+	return invitation.ToFundInvitationInfoResponse(fund.Name), nil
+}
+
+// FundInvitationRevokeHandler revokes a pending invitation of a specific fund
+func (a *FundInvitationsApi) FundInvitationRevokeHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationRevokeReq models.FundInvitationRevokeRequest
+	err := c.ShouldBindJSON(&invitationRevokeReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.FundInvitationRevokeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, errFund := GetFundIdFromContext(c, uid)
+
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err = a.fundInvitations.RevokeInvitation(c, uid, fundId, invitationRevokeReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationRevokeHandler] failed to revoke invitation \"id:%d\" of fund \"id:%d\" for user \"uid:%d\", because %s", invitationRevokeReq.Id, fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fund_invitations.FundInvitationRevokeHandler] user \"uid:%d\" has revoked invitation \"id:%d\" of fund \"id:%d\"", uid, invitationRevokeReq.Id, fundId)
+	return true, nil
+}
+
+// FundInvitationReceivedListHandler returns pending invitations sent to current user
+func (a *FundInvitationsApi) FundInvitationReceivedListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	invitations, err := a.fundInvitations.GetPendingInvitationsByInviteeUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationReceivedListHandler] failed to get invitations for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fundMap, err := a.fundInvitations.GetInvitedFundsByInvitations(c, uid, invitations)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationReceivedListHandler] failed to get invited funds for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	invitationResps := make(models.FundInvitationInfoResponseSlice, 0, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		fund, exists := fundMap[invitations[i].FundId]
+
+		if !exists {
+			continue
+		}
+
+		invitationResps = append(invitationResps, invitations[i].ToFundInvitationInfoResponse(fund.Name))
+	}
+
+	sort.Sort(invitationResps)
+
+	return invitationResps, nil
+}
+
+// FundInvitationAcceptHandler accepts an invitation by invitation id or invitation token for current user
+func (a *FundInvitationsApi) FundInvitationAcceptHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationReplyReq models.FundInvitationReplyRequest
+	err := c.ShouldBindJSON(&invitationReplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.FundInvitationAcceptHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	invitationId, tokenClaims, errInvitation := a.getInvitationIdFromRequest(c, uid, &invitationReplyReq)
+
+	if errInvitation != nil {
+		return nil, errInvitation
+	}
+
+	member, err := a.fundInvitations.AcceptInvitation(c, uid, invitationId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationAcceptHandler] failed to accept invitation \"id:%d\" for user \"uid:%d\", because %s", invitationId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fund_invitations.FundInvitationAcceptHandler] user \"uid:%d\" has accepted invitation \"id:%d\" and linked to member \"id:%d\"", uid, invitationId, member.MemberId)
+
+	a.deleteInvitationToken(c, tokenClaims)
+
+	return member.ToFundMemberResponse(), nil
+}
+
+// FundInvitationDeclineHandler declines an invitation by invitation id or invitation token for current user
+func (a *FundInvitationsApi) FundInvitationDeclineHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationReplyReq models.FundInvitationReplyRequest
+	err := c.ShouldBindJSON(&invitationReplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.FundInvitationDeclineHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	invitationId, tokenClaims, errInvitation := a.getInvitationIdFromRequest(c, uid, &invitationReplyReq)
+
+	if errInvitation != nil {
+		return nil, errInvitation
+	}
+
+	err = a.fundInvitations.DeclineInvitation(c, uid, invitationId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.FundInvitationDeclineHandler] failed to decline invitation \"id:%d\" for user \"uid:%d\", because %s", invitationId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fund_invitations.FundInvitationDeclineHandler] user \"uid:%d\" has declined invitation \"id:%d\"", uid, invitationId)
+
+	a.deleteInvitationToken(c, tokenClaims)
+
+	return true, nil
+}
+
+func (a *FundInvitationsApi) getInvitationIdFromRequest(c *core.WebContext, uid int64, invitationReplyReq *models.FundInvitationReplyRequest) (int64, *core.UserTokenClaims, *errs.Error) {
+	if invitationReplyReq.Token == "" {
+		if invitationReplyReq.Id <= 0 {
+			return 0, nil, errs.ErrFundInvitationRequireIdOrToken
+		}
+
+		return invitationReplyReq.Id, nil, nil
+	}
+
+	_, claims, tokenContext, err := a.tokens.ParseToken(c, invitationReplyReq.Token)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.getInvitationIdFromRequest] failed to parse invitation token for user \"uid:%d\", because %s", uid, err.Error())
+		return 0, nil, errs.ErrFundInvitationTokenInvalid
+	}
+
+	if claims.Type != core.USER_TOKEN_TYPE_FUND_INVITATION || claims.Uid != uid {
+		log.Warnf(c, "[fund_invitations.getInvitationIdFromRequest] token of user \"uid:%d\" is not a fund invitation token for user \"uid:%d\"", claims.Uid, uid)
+		return 0, nil, errs.ErrFundInvitationTokenInvalid
+	}
+
+	var invitationTokenContext models.FundInvitationTokenContext
+	err = json.Unmarshal([]byte(tokenContext), &invitationTokenContext)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.getInvitationIdFromRequest] parse token context failed, because %s", err.Error())
+		return 0, nil, errs.ErrFundInvitationTokenInvalid
+	}
+
+	if invitationReplyReq.Id > 0 && invitationReplyReq.Id != invitationTokenContext.InvitationId {
+		log.Warnf(c, "[fund_invitations.getInvitationIdFromRequest] invitation id \"%d\" in request does not match invitation id \"%d\" in token", invitationReplyReq.Id, invitationTokenContext.InvitationId)
+		return 0, nil, errs.ErrFundInvitationTokenInvalid
+	}
+
+	return invitationTokenContext.InvitationId, claims, nil
+}
+
+func (a *FundInvitationsApi) deleteInvitationToken(c *core.WebContext, tokenClaims *core.UserTokenClaims) {
+	if tokenClaims == nil {
+		return
+	}
+
+	err := a.tokens.DeleteTokenByClaims(c, tokenClaims)
+
+	if err != nil {
+		log.Warnf(c, "[fund_invitations.deleteInvitationToken] failed to delete invitation token \"utid:%s\" of user \"uid:%d\", because %s", tokenClaims.UserTokenId, tokenClaims.Uid, err.Error())
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// FundSettlementsApi represents fund settlement api
+type FundSettlementsApi struct {
+	fundSettlements *services.FundSettlementService
+	accounts        *services.AccountService
+}
+
+// Initialize a fund settlement api singleton instance
+var (
+	FundSettlements = &FundSettlementsApi{
+		fundSettlements: services.FundSettlements,
+		accounts:        services.Accounts,
+	}
+)
+
+// FundSettlementGetHandler returns the unsettled balances of all members and the repayments to settle them
+func (a *FundSettlementsApi) FundSettlementGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var settlementGetReq models.FundSettlementGetRequest
+	err := c.ShouldBindQuery(&settlementGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_settlements.FundSettlementGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	endTime := settlementGetReq.EndTime
+
+	if endTime <= 0 {
+		endTime = time.Now().Unix()
+	}
+
+	startTime, _, balances, repayments, errSettlement := a.getUnsettledBalances(c, uid, fundId, endTime)
+
+	if errSettlement != nil {
+		return nil, errSettlement
+	}
+
+	settlementResp := &models.FundSettlementResponse{
+		FundId:     fundId,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Balances:   make([]*models.FundMemberBalanceResponse, len(balances)),
+		Repayments: make([]*models.FundSettlementRepaymentResponse, len(repayments)),
+	}
+
+	for i := 0; i < len(balances); i++ {
+		settlementResp.Balances[i] = balances[i].ToFundMemberBalanceResponse()
+	}
+
+	for i := 0; i < len(repayments); i++ {
+		settlementResp.Repayments[i] = repayments[i].ToFundSettlementRepaymentResponse()
+	}
+
+	return settlementResp, nil
+}
+
+// FundSettlementCreateHandler records the repayments as transfer transactions and marks the period as settled
+func (a *FundSettlementsApi) FundSettlementCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var settlementCreateReq models.FundSettlementCreateRequest
+	err := c.ShouldBindJSON(&settlementCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[fund_settlements.FundSettlementCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	// Settling up affects the expenses of all members
+	if permission != models.FUND_PERMISSION_ALL {
+		return nil, errs.ErrFundPermissionDenied
+	}
+
+	if settlementCreateReq.EndTime > time.Now().Unix() {
+		return nil, errs.ErrFundSettlementTimeInvalid
+	}
+
+	startTime, expenses, _, repayments, errSettlement := a.getUnsettledBalances(c, uid, fundId, settlementCreateReq.EndTime)
+
+	if errSettlement != nil {
+		return nil, errSettlement
+	}
+
+	if settlementCreateReq.EndTime <= startTime {
+		return nil, errs.ErrFundSettlementTimeInvalid
+	}
+
+	if !a.isRepaymentsMatched(repayments, settlementCreateReq.Repayments) {
+		log.Warnf(c, "[fund_settlements.FundSettlementCreateHandler] the repayments in request do not match the balances of fund \"id:%d\" for user \"uid:%d\"", fundId, uid)
+		return nil, errs.ErrFundSettlementRepaymentsMismatch
+	}
+
+	transactions, errTransactions := a.createRepaymentTransactions(c, uid, fundId, &settlementCreateReq)
+
+	if errTransactions != nil {
+		return nil, errTransactions
+	}
+
+	settledTransactionIds := make([]int64, len(expenses))
+
+	for i := 0; i < len(expenses); i++ {
+		settledTransactionIds[i] = expenses[i].TransactionId
+	}
+
+	settlement := &models.FundSettlement{
+		FundId:      fundId,
+		SettledTime: settlementCreateReq.EndTime,
+		Uid:         uid,
+		Comment:     settlementCreateReq.Comment,
+	}
+
+	err = a.fundSettlements.CreateSettlement(c, settlement, transactions, settledTransactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[fund_settlements.FundSettlementCreateHandler] failed to create settlement of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionIds := make([]string, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = utils.Int64ToString(transactions[i].TransactionId)
+	}
+
+	log.Infof(c, "[fund_settlements.FundSettlementCreateHandler] user \"uid:%d\" has settled fund \"id:%d\" until %d with %d repayments", uid, fundId, settlement.SettledTime, len(transactions))
+
+	return settlement.ToFundSettlementInfoResponse(transactionIds), nil
+}
+
+func (a *FundSettlementsApi) getUnsettledBalances(c *core.WebContext, uid int64, fundId int64, endTime int64) (int64, []*models.FundSharedExpense, []*models.FundMemberBalance, []*models.FundSettlementRepayment, *errs.Error) {
+	startTime, err := a.fundSettlements.GetLastSettledTime(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[fund_settlements.getUnsettledBalances] failed to get last settled time of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return 0, nil, nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if endTime <= startTime {
+		return startTime, make([]*models.FundSharedExpense, 0), make([]*models.FundMemberBalance, 0), make([]*models.FundSettlementRepayment, 0), nil
+	}
+
+	// Expenses are selected by whether they have been settled instead of by the last settled time, so that backdated expenses are included as well
+	expenses, err := a.fundSettlements.GetSharedExpenses(c, uid, fundId, endTime)
+
+	if err != nil {
+		log.Errorf(c, "[fund_settlements.getUnsettledBalances] failed to get shared expenses of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return 0, nil, nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	balances := models.CalculateFundMemberBalances(expenses)
+	repayments := models.SimplifyFundMemberBalances(balances)
+
+	return startTime, expenses, balances, repayments, nil
+}
+
+func (a *FundSettlementsApi) isRepaymentsMatched(repayments []*models.FundSettlementRepayment, repaymentReqs []*models.FundSettlementRepaymentRequest) bool {
+	if len(repayments) != len(repaymentReqs) {
+		return false
+	}
+
+	remainingRepayments := make(map[string]int, len(repayments))
+
+	for _, repayment := range repayments {
+		remainingRepayments[fmt.Sprintf("%d_%d_%s_%d", repayment.FromMemberId, repayment.ToMemberId, repayment.Currency, repayment.Amount)]++
+	}
+
+	for _, repaymentReq := range repaymentReqs {
+		key := fmt.Sprintf("%d_%d_%s_%d", repaymentReq.FromMemberId, repaymentReq.ToMemberId, repaymentReq.Currency, repaymentReq.Amount)
+
+		if remainingRepayments[key] < 1 {
+			return false
+		}
+
+		remainingRepayments[key]--
+	}
+
+	return true
+}
+
+func (a *FundSettlementsApi) createRepaymentTransactions(c *core.WebContext, uid int64, fundId int64, settlementCreateReq *models.FundSettlementCreateRequest) ([]*models.Transaction, *errs.Error) {
+	accountIds := make([]int64, 0, len(settlementCreateReq.Repayments)*2)
+
+	for _, repaymentReq := range settlementCreateReq.Repayments {
+		accountIds = append(accountIds, repaymentReq.SourceAccountId, repaymentReq.DestinationAccountId)
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, fundId, utils.ToUniqueInt64Slice(accountIds))
+
+	if err != nil {
+		log.Errorf(c, "[fund_settlements.createRepaymentTransactions] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now().Unix()
+	transactions := make([]*models.Transaction, len(settlementCreateReq.Repayments))
+
+	for i, repaymentReq := range settlementCreateReq.Repayments {
+		sourceAccount, exists := accountMap[repaymentReq.SourceAccountId]
+
+		if !exists {
+			return nil, errs.ErrSourceAccountNotFound
+		}
+
+		destinationAccount, exists := accountMap[repaymentReq.DestinationAccountId]
+
+		if !exists {
+			return nil, errs.ErrDestinationAccountNotFound
+		}
+
+		if sourceAccount.Currency != repaymentReq.Currency || destinationAccount.Currency != repaymentReq.Currency {
+			return nil, errs.ErrFundSettlementAccountCurrencyInvalid
+		}
+
+		if sourceAccount.AccountId == destinationAccount.AccountId {
+			return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+		}
+
+		transactions[i] = &models.Transaction{
+			Uid:                  uid,
+			FundId:               fundId,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			CategoryId:           settlementCreateReq.CategoryId,
+			TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(now),
+			TimezoneUtcOffset:    settlementCreateReq.UtcOffset,
+			AccountId:            repaymentReq.SourceAccountId,
+			Amount:               repaymentReq.Amount,
+			RelatedAccountId:     repaymentReq.DestinationAccountId,
+			RelatedAccountAmount: repaymentReq.Amount,
+			Comment:              settlementCreateReq.Comment,
+			CreatedIp:            c.ClientIP(),
+		}
+	}
+
+	return transactions, nil
+}
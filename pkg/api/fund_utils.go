@@ -4,6 +4,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 )
 
@@ -41,3 +42,20 @@ func GetFundIdFromContext(c *core.WebContext, uid int64) (int64, *errs.Error) {
 	// Return the first fund (should be personal fund)
 	return funds[0].FundId, nil
 }
+
+// GetFundIdFromContextWithPermission extracts fundId like GetFundIdFromContext and checks the fund permission matrix
+// to make sure the user's role in that fund allows performing the action on the resource
+func GetFundIdFromContextWithPermission(c *core.WebContext, uid int64, resource models.FundResource, action models.FundAction) (int64, models.FundPermission, *errs.Error) {
+	fundId, err := GetFundIdFromContext(c, uid)
+	if err != nil {
+		return 0, models.FUND_PERMISSION_NONE, err
+	}
+
+	permission, errPermission := services.Funds.GetUserPermissionInFund(c, uid, fundId, resource, action)
+	if errPermission != nil {
+		log.Warnf(c, "[GetFundIdFromContextWithPermission] user uid:%d cannot perform action \"%s\" on resource \"%s\" of fund id:%d, because %s", uid, action, resource, fundId, errPermission.Error())
+		return 0, models.FUND_PERMISSION_NONE, errs.Or(errPermission, errs.ErrFundPermissionDenied)
+	}
+
+	return fundId, permission, nil
+}
@@ -347,6 +347,86 @@ func (a *FundsApi) FundMemberDeleteHandler(c *core.WebContext) (any, *errs.Error
 	return true, nil
 }
 
+// FundMemberRoleModifyHandler promotes or demotes a member of a fund
+func (a *FundsApi) FundMemberRoleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var fundMemberRoleModifyReq models.FundMemberRoleModifyRequest
+	err := c.ShouldBindJSON(&fundMemberRoleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[funds.FundMemberRoleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId from URL context parameter
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	member, err := a.funds.ModifyMemberRole(c, uid, fundId, fundMemberRoleModifyReq.MemberId, fundMemberRoleModifyReq.Role)
+
+	if err != nil {
+		log.Errorf(c, "[funds.FundMemberRoleModifyHandler] failed to change role of member \"id:%d\" in fund \"id:%d\" for user \"uid:%d\", because %s", fundMemberRoleModifyReq.MemberId, fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[funds.FundMemberRoleModifyHandler] user \"uid:%d\" has changed role of member \"id:%d\" in fund \"id:%d\" to \"%s\"", uid, member.MemberId, fundId, member.Role)
+
+	return member.ToFundMemberResponse(), nil
+}
+
+// FundOwnershipTransferHandler transfers the ownership of a fund to another linked member
+func (a *FundsApi) FundOwnershipTransferHandler(c *core.WebContext) (any, *errs.Error) {
+	var fundOwnershipTransferReq models.FundOwnershipTransferRequest
+	err := c.ShouldBindJSON(&fundOwnershipTransferReq)
+
+	if err != nil {
+		log.Warnf(c, "[funds.FundOwnershipTransferHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId from URL context parameter
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err = a.funds.TransferOwnership(c, uid, fundId, fundOwnershipTransferReq.MemberId)
+
+	if err != nil {
+		log.Errorf(c, "[funds.FundOwnershipTransferHandler] failed to transfer ownership of fund \"id:%d\" to member \"id:%d\" for user \"uid:%d\", because %s", fundId, fundOwnershipTransferReq.MemberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[funds.FundOwnershipTransferHandler] user \"uid:%d\" has transferred ownership of fund \"id:%d\" to member \"id:%d\"", uid, fundId, fundOwnershipTransferReq.MemberId)
+	return true, nil
+}
+
+// FundLeaveHandler unlinks current user from a fund
+func (a *FundsApi) FundLeaveHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+
+	// Get fundId from URL context parameter
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err := a.funds.LeaveFund(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[funds.FundLeaveHandler] failed to leave fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[funds.FundLeaveHandler] user \"uid:%d\" has left fund \"id:%d\"", uid, fundId)
+	return true, nil
+}
+
 // FundMemberLinkHandler links a fund member to an existing user
 func (a *FundsApi) FundMemberLinkHandler(c *core.WebContext) (any, *errs.Error) {
 	var fundMemberLinkReq models.FundMemberLinkRequest
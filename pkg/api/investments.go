@@ -0,0 +1,482 @@
+package api
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentsApi represents investment api
+type InvestmentsApi struct {
+	investments *services.InvestmentService
+	accounts    *services.AccountService
+}
+
+// Initialize an investment api singleton instance
+var (
+	Investments = &InvestmentsApi{
+		investments: services.Investments,
+		accounts:    services.Accounts,
+	}
+)
+
+// SecurityListHandler returns security list of current user
+func (a *InvestmentsApi) SecurityListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	securities, err := a.investments.GetAllSecuritiesByUid(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityListHandler] failed to get securities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	securityResps := make([]*models.SecurityInfoResponse, len(securities))
+
+	for i := 0; i < len(securities); i++ {
+		securityResps[i] = securities[i].ToSecurityInfoResponse()
+	}
+
+	return securityResps, nil
+}
+
+// SecurityCreateHandler saves a new security by request parameters for current user
+func (a *InvestmentsApi) SecurityCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityCreateReq models.SecurityCreateRequest
+	err := c.ShouldBindJSON(&securityCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	security := &models.Security{
+		Uid:             uid,
+		FundId:          fundId,
+		Symbol:          securityCreateReq.Symbol,
+		Name:            securityCreateReq.Name,
+		Currency:        securityCreateReq.Currency,
+		CostBasisMethod: securityCreateReq.CostBasisMethod,
+	}
+
+	err = a.investments.CreateSecurity(c, security)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityCreateHandler] failed to create security \"id:%d\" for user \"uid:%d\", because %s", security.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityCreateHandler] user \"uid:%d\" has created a new security \"id:%d\" successfully", uid, security.SecurityId)
+
+	return security.ToSecurityInfoResponse(), nil
+}
+
+// SecurityModifyHandler saves an existed security by request parameters for current user
+func (a *InvestmentsApi) SecurityModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityModifyReq models.SecurityModifyRequest
+	err := c.ShouldBindJSON(&securityModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	security, err := a.investments.GetSecurityById(c, uid, fundId, securityModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityModifyHandler] failed to get security \"id:%d\" for user \"uid:%d\", because %s", securityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newSecurity := &models.Security{
+		SecurityId:      security.SecurityId,
+		Uid:             uid,
+		FundId:          fundId,
+		Symbol:          securityModifyReq.Symbol,
+		Name:            securityModifyReq.Name,
+		Currency:        securityModifyReq.Currency,
+		CostBasisMethod: securityModifyReq.CostBasisMethod,
+	}
+
+	if newSecurity.Symbol == security.Symbol &&
+		newSecurity.Name == security.Name &&
+		newSecurity.Currency == security.Currency &&
+		newSecurity.CostBasisMethod == security.CostBasisMethod {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.investments.ModifySecurity(c, newSecurity)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityModifyHandler] failed to update security \"id:%d\" for user \"uid:%d\", because %s", securityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityModifyHandler] user \"uid:%d\" has updated security \"id:%d\" successfully", uid, securityModifyReq.Id)
+
+	return newSecurity.ToSecurityInfoResponse(), nil
+}
+
+// SecurityDeleteHandler deletes an existed security by request parameters for current user
+func (a *InvestmentsApi) SecurityDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityDeleteReq models.SecurityDeleteRequest
+	err := c.ShouldBindJSON(&securityDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err = a.investments.DeleteSecurity(c, uid, fundId, securityDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityDeleteHandler] failed to delete security \"id:%d\" for user \"uid:%d\", because %s", securityDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityDeleteHandler] user \"uid:%d\" has deleted security \"id:%d\"", uid, securityDeleteReq.Id)
+	return true, nil
+}
+
+// TradeListHandler returns investment trade list of current user
+func (a *InvestmentsApi) TradeListHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeListReq models.InvestmentTradeListRequest
+	err := c.ShouldBindQuery(&tradeListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	trades, err := a.investments.GetTrades(c, uid, fundId, tradeListReq.AccountId, tradeListReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeListHandler] failed to get investment trades for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tradeResps := make([]*models.InvestmentTradeInfoResponse, len(trades))
+
+	for i := 0; i < len(trades); i++ {
+		tradeResps[i] = trades[i].ToInvestmentTradeInfoResponse()
+	}
+
+	return tradeResps, nil
+}
+
+// TradeCreateHandler saves a new investment trade by request parameters for current user
+func (a *InvestmentsApi) TradeCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeCreateReq models.InvestmentTradeCreateRequest
+	err := c.ShouldBindJSON(&tradeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	trade := &models.InvestmentTrade{
+		Uid:        uid,
+		FundId:     fundId,
+		AccountId:  tradeCreateReq.AccountId,
+		SecurityId: tradeCreateReq.SecurityId,
+		Type:       tradeCreateReq.Type,
+		TradeTime:  tradeCreateReq.TradeTime,
+		Quantity:   tradeCreateReq.Quantity,
+		UnitPrice:  tradeCreateReq.UnitPrice,
+		Amount:     tradeCreateReq.Amount,
+		Fee:        tradeCreateReq.Fee,
+		Comment:    tradeCreateReq.Comment,
+	}
+
+	err = a.investments.CreateTrade(c, trade, tradeCreateReq.CategoryId, tradeCreateReq.UtcOffset, c.ClientIP())
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeCreateHandler] failed to create investment trade for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.TradeCreateHandler] user \"uid:%d\" has created a new investment trade \"id:%d\" successfully", uid, trade.TradeId)
+
+	return trade.ToInvestmentTradeInfoResponse(), nil
+}
+
+// TradeDeleteHandler deletes an existed investment trade by request parameters for current user
+func (a *InvestmentsApi) TradeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var tradeDeleteReq models.InvestmentTradeDeleteRequest
+	err := c.ShouldBindJSON(&tradeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.TradeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err = a.investments.DeleteTrade(c, uid, fundId, tradeDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.TradeDeleteHandler] failed to delete investment trade \"id:%d\" for user \"uid:%d\", because %s", tradeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.TradeDeleteHandler] user \"uid:%d\" has deleted investment trade \"id:%d\"", uid, tradeDeleteReq.Id)
+	return true, nil
+}
+
+// SecurityPriceListHandler returns the price history of one specific security of current user
+func (a *InvestmentsApi) SecurityPriceListHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceListReq models.SecurityPriceListRequest
+	err := c.ShouldBindQuery(&priceListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	prices, err := a.investments.GetSecurityPrices(c, uid, fundId, priceListReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceListHandler] failed to get prices of security \"id:%d\" for user \"uid:%d\", because %s", priceListReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	priceResps := make([]*models.SecurityPriceInfoResponse, len(prices))
+
+	for i := 0; i < len(prices); i++ {
+		priceResps[i] = prices[i].ToSecurityPriceInfoResponse()
+	}
+
+	return priceResps, nil
+}
+
+// SecurityPriceAddHandler saves a manually entered security price by request parameters for current user
+func (a *InvestmentsApi) SecurityPriceAddHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceAddReq models.SecurityPriceAddRequest
+	err := c.ShouldBindJSON(&priceAddReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceAddHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	price := &models.SecurityPrice{
+		PriceDate: priceAddReq.Date,
+		Price:     priceAddReq.Price,
+		Source:    models.SECURITY_PRICE_SOURCE_MANUAL,
+	}
+
+	err = a.investments.SetSecurityPrices(c, uid, fundId, priceAddReq.SecurityId, []*models.SecurityPrice{price})
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceAddHandler] failed to save price of security \"id:%d\" for user \"uid:%d\", because %s", priceAddReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityPriceAddHandler] user \"uid:%d\" has saved price of security \"id:%d\" on \"%s\" successfully", uid, priceAddReq.SecurityId, priceAddReq.Date)
+
+	return price.ToSecurityPriceInfoResponse(), nil
+}
+
+// SecurityPriceImportHandler saves the imported price history of a security by request parameters for current user
+func (a *InvestmentsApi) SecurityPriceImportHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceImportReq models.SecurityPriceImportRequest
+	err := c.ShouldBindJSON(&priceImportReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceImportHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if len(priceImportReq.Prices) > models.MaximumSecurityPricesCountOfImport {
+		return nil, errs.ErrTooManySecurityPricesToImport
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	prices := make([]*models.SecurityPrice, len(priceImportReq.Prices))
+
+	for i := 0; i < len(priceImportReq.Prices); i++ {
+		prices[i] = &models.SecurityPrice{
+			PriceDate: priceImportReq.Prices[i].Date,
+			Price:     priceImportReq.Prices[i].Price,
+			Source:    models.SECURITY_PRICE_SOURCE_IMPORTED,
+		}
+	}
+
+	err = a.investments.SetSecurityPrices(c, uid, fundId, priceImportReq.SecurityId, prices)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceImportHandler] failed to import prices of security \"id:%d\" for user \"uid:%d\", because %s", priceImportReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityPriceImportHandler] user \"uid:%d\" has imported %d prices of security \"id:%d\" successfully", uid, len(prices), priceImportReq.SecurityId)
+
+	return true, nil
+}
+
+// HoldingsHandler returns the market value, cost basis and gains of every holding and every investment account of current user
+func (a *InvestmentsApi) HoldingsHandler(c *core.WebContext) (any, *errs.Error) {
+	var holdingsReq models.InvestmentHoldingsRequest
+	err := c.ShouldBindQuery(&holdingsReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.HoldingsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[investments.HoldingsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	date := holdingsReq.Date
+
+	if date == "" {
+		date = utils.FormatUnixTimeToLongDate(time.Now().Unix(), time.FixedZone("Client Timezone", int(utcOffset)*60))
+	} else if err = models.ValidateSecurityPriceDate(date); err != nil {
+		return nil, errs.Or(err, errs.ErrSecurityPriceDateInvalid)
+	}
+
+	dateLastTime, err := utils.ParseFromLongDateLastTime(date, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[investments.HoldingsHandler] cannot parse date \"%s\", because %s", date, err.Error())
+		return nil, errs.ErrSecurityPriceDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_INVESTMENT, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	allTrades, err := a.investments.GetTrades(c, uid, fundId, holdingsReq.AccountId, 0)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingsHandler] failed to get investment trades for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	trades := make([]*models.InvestmentTrade, 0, len(allTrades))
+	accountIds := make([]int64, 0, len(allTrades))
+	securityIds := make([]int64, 0, len(allTrades))
+
+	for _, trade := range allTrades {
+		if trade.TradeTime > dateLastTime.Unix() {
+			continue
+		}
+
+		trades = append(trades, trade)
+		accountIds = append(accountIds, trade.AccountId)
+		securityIds = append(securityIds, trade.SecurityId)
+	}
+
+	securities, err := a.investments.GetAllSecuritiesByUid(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingsHandler] failed to get securities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	securitiesMap := make(map[int64]*models.Security, len(securities))
+
+	for _, security := range securities {
+		securitiesMap[security.SecurityId] = security
+	}
+
+	holdings, err := models.BuildInvestmentHoldings(trades, securitiesMap)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingsHandler] failed to build investment holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	prices, err := a.investments.GetLatestSecurityPrices(c, uid, securityIds, date)
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingsHandler] failed to get security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAccountsByAccountIds(c, uid, fundId, utils.ToUniqueInt64Slice(accountIds))
+
+	if err != nil {
+		log.Errorf(c, "[investments.HoldingsHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return models.ToInvestmentAccountHoldingsResponses(holdings, accounts, prices), nil
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// PayeesApi represents payee api
+type PayeesApi struct {
+	payees *services.PayeeService
+}
+
+// Initialize a payee api singleton instance
+var (
+	Payees = &PayeesApi{
+		payees: services.Payees,
+	}
+)
+
+// PayeeListHandler returns payee list of current user
+func (a *PayeesApi) PayeeListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+
+	// Get fundId for the user
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	payees, err := a.payees.GetAllPayeesByUid(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeListHandler] failed to get payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeResps := make(models.PayeeInfoResponseSlice, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		payeeResps[i] = payees[i].ToPayeeInfoResponse()
+	}
+
+	sort.Sort(payeeResps)
+
+	return payeeResps, nil
+}
+
+// PayeeGetHandler returns one specific payee of current user
+func (a *PayeesApi) PayeeGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeGetReq models.PayeeGetRequest
+	err := c.ShouldBindQuery(&payeeGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId for the user
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, fundId, payeeGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeGetHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return payee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeCreateHandler saves a new payee by request parameters for current user
+func (a *PayeesApi) PayeeCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeCreateReq models.PayeeCreateRequest
+	err := c.ShouldBindJSON(&payeeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId for the user
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	payee := &models.Payee{
+		Uid:               uid,
+		FundId:            fundId,
+		Name:              payeeCreateReq.Name,
+		DefaultCategoryId: payeeCreateReq.DefaultCategoryId,
+		Address:           payeeCreateReq.Address,
+	}
+
+	if payeeCreateReq.GeoLocation != nil {
+		payee.GeoLongitude = payeeCreateReq.GeoLocation.Longitude
+		payee.GeoLatitude = payeeCreateReq.GeoLocation.Latitude
+	}
+
+	err = payee.SetAliases(payeeCreateReq.Aliases)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] payee aliases are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrPayeeAliasInvalid)
+	}
+
+	err = a.payees.CreatePayee(c, payee)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateHandler] failed to create payee \"id:%d\" for user \"uid:%d\", because %s", payee.PayeeId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeCreateHandler] user \"uid:%d\" has created a new payee \"id:%d\" successfully", uid, payee.PayeeId)
+
+	return payee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeModifyHandler saves an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeModifyReq models.PayeeModifyRequest
+	err := c.ShouldBindJSON(&payeeModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId for the user
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, fundId, payeeModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newPayee := &models.Payee{
+		PayeeId:           payee.PayeeId,
+		Uid:               uid,
+		FundId:            fundId,
+		Name:              payeeModifyReq.Name,
+		DefaultCategoryId: payeeModifyReq.DefaultCategoryId,
+		Address:           payeeModifyReq.Address,
+	}
+
+	if payeeModifyReq.GeoLocation != nil {
+		newPayee.GeoLongitude = payeeModifyReq.GeoLocation.Longitude
+		newPayee.GeoLatitude = payeeModifyReq.GeoLocation.Latitude
+	}
+
+	err = newPayee.SetAliases(payeeModifyReq.Aliases)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] payee aliases are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrPayeeAliasInvalid)
+	}
+
+	if newPayee.Name == payee.Name &&
+		newPayee.Aliases == payee.Aliases &&
+		newPayee.DefaultCategoryId == payee.DefaultCategoryId &&
+		newPayee.Address == payee.Address &&
+		newPayee.GeoLongitude == payee.GeoLongitude &&
+		newPayee.GeoLatitude == payee.GeoLatitude {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.payees.ModifyPayee(c, newPayee)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to update payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeModifyHandler] user \"uid:%d\" has updated payee \"id:%d\" successfully", uid, payeeModifyReq.Id)
+
+	return newPayee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeDeleteHandler deletes an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeDeleteReq models.PayeeDeleteRequest
+	err := c.ShouldBindJSON(&payeeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	// Get fundId for the user
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	err = a.payees.DeletePayee(c, uid, fundId, payeeDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeDeleteHandler] failed to delete payee \"id:%d\" for user \"uid:%d\", because %s", payeeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeDeleteHandler] user \"uid:%d\" has deleted payee \"id:%d\"", uid, payeeDeleteReq.Id)
+	return true, nil
+}
@@ -49,7 +49,7 @@ func (a *TransactionCategoriesApi) CategoryListHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -77,7 +77,7 @@ func (a *TransactionCategoriesApi) CategoryGetHandler(c *core.WebContext) (any,
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -111,7 +111,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 
 	uid := c.GetCurrentUid()
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_CREATE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -220,7 +220,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 
 	uid := c.GetCurrentUid()
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -312,7 +312,7 @@ func (a *TransactionCategoriesApi) CategoryHideHandler(c *core.WebContext) (any,
 
 	uid := c.GetCurrentUid()
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -340,7 +340,7 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.WebContext) (any,
 
 	uid := c.GetCurrentUid()
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -381,7 +381,7 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 
 	uid := c.GetCurrentUid()
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_DELETE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -404,7 +404,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 	categoriesMap[nil] = make([]*models.TransactionCategory, len(categoryCreateBatchReq.Categories))
 	totalCount := 0
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_CATEGORY, models.FUND_ACTION_CREATE)
 	if errFund != nil {
 		return nil, errFund
 	}
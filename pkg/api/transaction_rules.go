@@ -0,0 +1,565 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const pageCountForTransactionRuleMatching = 1000
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	ApiUsingConfig
+	rules        *services.TransactionRuleService
+	funds        *services.FundService
+	users        *services.UserService
+	transactions *services.TransactionService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		rules:        services.TransactionRules,
+		funds:        services.Funds,
+		users:        services.Users,
+		transactions: services.Transactions,
+	}
+)
+
+// RuleListHandler returns all transaction rules of current fund which are visible to current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rules, err := a.rules.GetAllRulesByFundId(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleListHandler] failed to get transaction rules of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current fund
+func (a *TransactionRulesApi) RuleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule, err := a.rules.GetRuleByRuleId(c, uid, fundId, ruleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleGetHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current fund
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, memberIds, errIds := a.getRuleActionIds(c, ruleCreateReq.AddTagIds, ruleCreateReq.SetMemberIds)
+
+	if errIds != nil {
+		return nil, errIds
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule := &models.TransactionRule{
+		Uid:                   uid,
+		FundId:                fundId,
+		Scope:                 ruleCreateReq.Scope,
+		Name:                  ruleCreateReq.Name,
+		Disabled:              ruleCreateReq.Disabled,
+		TransactionType:       ruleCreateReq.TransactionType,
+		CommentPattern:        ruleCreateReq.CommentPattern,
+		MinAmount:             ruleCreateReq.MinAmount,
+		MaxAmount:             ruleCreateReq.MaxAmount,
+		AccountId:             ruleCreateReq.AccountId,
+		CounterpartyAccountId: ruleCreateReq.CounterpartyAccountId,
+		SetCategoryId:         ruleCreateReq.SetCategoryId,
+		AddTagIds:             addTagIds,
+		SetMemberIds:          memberIds,
+		SetComment:            ruleCreateReq.SetComment,
+	}
+
+	if errPermission := a.checkRulePermission(c, uid, rule, models.FUND_ACTION_CREATE); errPermission != nil {
+		return nil, errPermission
+	}
+
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule.DisplayOrder = maxOrderId + 1
+
+	err = a.rules.CreateRule(c, rule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to create transaction rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new transaction rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current fund
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, memberIds, errIds := a.getRuleActionIds(c, ruleModifyReq.AddTagIds, ruleModifyReq.SetMemberIds)
+
+	if errIds != nil {
+		return nil, errIds
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule, err := a.rules.GetRuleByRuleId(c, uid, fundId, ruleModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if errPermission := a.checkRulePermission(c, uid, rule, models.FUND_ACTION_MODIFY); errPermission != nil {
+		return nil, errPermission
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:                rule.RuleId,
+		Uid:                   rule.Uid,
+		FundId:                rule.FundId,
+		Scope:                 rule.Scope,
+		Name:                  ruleModifyReq.Name,
+		DisplayOrder:          rule.DisplayOrder,
+		Disabled:              ruleModifyReq.Disabled,
+		TransactionType:       ruleModifyReq.TransactionType,
+		CommentPattern:        ruleModifyReq.CommentPattern,
+		MinAmount:             ruleModifyReq.MinAmount,
+		MaxAmount:             ruleModifyReq.MaxAmount,
+		AccountId:             ruleModifyReq.AccountId,
+		CounterpartyAccountId: ruleModifyReq.CounterpartyAccountId,
+		SetCategoryId:         ruleModifyReq.SetCategoryId,
+		AddTagIds:             addTagIds,
+		SetMemberIds:          memberIds,
+		SetComment:            ruleModifyReq.SetComment,
+		CreatedUnixTime:       rule.CreatedUnixTime,
+	}
+
+	if newRule.Name == rule.Name &&
+		newRule.Disabled == rule.Disabled &&
+		newRule.TransactionType == rule.TransactionType &&
+		newRule.CommentPattern == rule.CommentPattern &&
+		newRule.MinAmount == rule.MinAmount &&
+		newRule.MaxAmount == rule.MaxAmount &&
+		newRule.AccountId == rule.AccountId &&
+		newRule.CounterpartyAccountId == rule.CounterpartyAccountId &&
+		newRule.SetCategoryId == rule.SetCategoryId &&
+		newRule.AddTagIds == rule.AddTagIds &&
+		newRule.SetMemberIds == rule.SetMemberIds &&
+		newRule.SetComment == rule.SetComment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.rules.ModifyRule(c, uid, newRule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to update transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated transaction rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	return newRule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleMoveHandler moves display order of existed transaction rules by request parameters for current fund
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION_RULE, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			RuleId:       newDisplayOrder.Id,
+			FundId:       fundId,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, fundId, rules)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleMoveHandler] failed to move transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved transaction rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current fund
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule, err := a.rules.GetRuleByRuleId(c, uid, fundId, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if errPermission := a.checkRulePermission(c, uid, rule, models.FUND_ACTION_DELETE); errPermission != nil {
+		return nil, errPermission
+	}
+
+	err = a.rules.DeleteRule(c, uid, fundId, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to delete transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted transaction rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleTestHandler returns the existing transactions of current user which are matched by a transaction rule without modifying them
+func (a *TransactionRulesApi) RuleTestHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleTestReq models.TransactionRuleTestRequest
+	err := c.ShouldBindQuery(&ruleTestReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleTestHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule, err := a.rules.GetRuleByRuleId(c, uid, fundId, ruleTestReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleTestHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleTestReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleEngine, matchedTransactions, err := a.getMatchedTransactions(c, uid, fundId, rule, ruleTestReq.MaxTime, ruleTestReq.MinTime)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	testResp := &models.TransactionRuleTestResponse{
+		Items:        make([]*models.TransactionRuleMatchedTransactionResponse, 0, ruleTestReq.Count),
+		MatchedCount: int64(len(matchedTransactions)),
+	}
+
+	for i := 0; i < len(matchedTransactions) && i < int(ruleTestReq.Count); i++ {
+		transaction := matchedTransactions[i]
+		transactionType, err := transaction.Type.ToTransactionType()
+
+		if err != nil {
+			continue
+		}
+
+		result := ruleEngine.Evaluate(transaction)
+		newTransaction := *transaction
+		result.ApplyTo(&newTransaction, nil)
+
+		testResp.Items = append(testResp.Items, &models.TransactionRuleMatchedTransactionResponse{
+			Id:            transaction.TransactionId,
+			Time:          utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime),
+			Type:          transactionType,
+			AccountId:     transaction.AccountId,
+			Amount:        transaction.Amount,
+			CategoryId:    transaction.CategoryId,
+			Comment:       transaction.Comment,
+			NewCategoryId: newTransaction.CategoryId,
+			NewComment:    newTransaction.Comment,
+			AddTagIds:     utils.Int64ArrayToStringArray(result.AddTagIds),
+			SetMemberIds:  utils.Int64ArrayToStringArray(result.MemberIds),
+		})
+	}
+
+	return testResp, nil
+}
+
+// RuleApplyHandler applies a transaction rule to the existing transactions of current user which are matched by the rule
+func (a *TransactionRulesApi) RuleApplyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleApplyReq models.TransactionRuleApplyRequest
+	err := c.ShouldBindJSON(&ruleApplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	rule, err := a.rules.GetRuleByRuleId(c, uid, fundId, ruleApplyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleApplyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	_, matchedTransactions, err := a.getMatchedTransactions(c, uid, fundId, rule, ruleApplyReq.MaxTime, ruleApplyReq.MinTime)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(matchedTransactions) > models.MaximumTransactionsCountOfBatchModification {
+		return nil, errs.ErrTooManyTransactionsMatchedByRule
+	}
+
+	if len(matchedTransactions) < 1 {
+		return &models.TransactionRuleApplyResponse{}, nil
+	}
+
+	transactionIds := a.transactions.GetTransactionIds(matchedTransactions)
+	modification := &models.TransactionBatchModification{
+		CategoryId: rule.SetCategoryId,
+		AddTagIds:  rule.GetAddTagIds(),
+		MemberIds:  rule.GetSetMemberIds(),
+		Comment:    rule.SetComment,
+	}
+
+	modifiedCount, transactionErrors, err := a.transactions.BatchModifyTransactions(c, uid, fundId, transactionIds, modification, func(transaction *models.Transaction) error {
+		if !permission.IsAllowedOn(uid, transaction.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset) {
+			return errs.ErrCannotModifyTransactionWithThisTransactionTime
+		}
+
+		return nil
+	})
+
+	if err != nil && len(transactionErrors) > 0 {
+		errorResps := make([]*models.TransactionBatchModifyErrorResponse, 0, len(transactionErrors))
+
+		for _, transactionId := range transactionIds {
+			transactionErr, exists := transactionErrors[transactionId]
+
+			if !exists {
+				continue
+			}
+
+			finalErr := errs.Or(transactionErr, errs.ErrOperationFailed)
+			errorResps = append(errorResps, &models.TransactionBatchModifyErrorResponse{
+				Id:           transactionId,
+				ErrorCode:    finalErr.Code(),
+				ErrorMessage: finalErr.Message,
+			})
+		}
+
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] %d transactions cannot be modified by transaction rule \"id:%d\" for user \"uid:%d\"", len(errorResps), rule.RuleId, uid)
+		return nil, errs.NewErrorWithContext(errs.ErrTransactionBatchModificationFailed, errorResps)
+	} else if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to apply transaction rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleApplyHandler] user \"uid:%d\" has applied transaction rule \"id:%d\" to %d transactions successfully", uid, rule.RuleId, modifiedCount)
+
+	return &models.TransactionRuleApplyResponse{
+		MatchedCount:  len(matchedTransactions),
+		ModifiedCount: modifiedCount,
+	}, nil
+}
+
+func (a *TransactionRulesApi) checkRulePermission(c *core.WebContext, uid int64, rule *models.TransactionRule, action models.FundAction) *errs.Error {
+	if rule.Scope == models.TRANSACTION_RULE_SCOPE_PERSONAL {
+		if rule.Uid != uid {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	}
+
+	if rule.Scope != models.TRANSACTION_RULE_SCOPE_FUND {
+		return errs.ErrTransactionRuleScopeInvalid
+	}
+
+	permission, err := a.funds.GetUserPermissionInFund(c, uid, rule.FundId, models.FUND_RESOURCE_TRANSACTION_RULE, action)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.checkRulePermission] user uid:%d cannot perform action \"%s\" on transaction rules of fund id:%d, because %s", uid, action, rule.FundId, err.Error())
+		return errs.Or(err, errs.ErrFundPermissionDenied)
+	}
+
+	if !permission.IsAllowedOn(uid, rule.Uid) {
+		return errs.ErrFundPermissionDenied
+	}
+
+	return nil
+}
+
+func (a *TransactionRulesApi) getRuleActionIds(c *core.WebContext, tagIds []string, memberIds []string) (string, string, *errs.Error) {
+	addTagIds, err := utils.StringArrayToInt64Array(tagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.getRuleActionIds] parse tag ids failed, because %s", err.Error())
+		return "", "", errs.ErrTransactionTagIdInvalid
+	}
+
+	setMemberIds, err := utils.StringArrayToInt64Array(memberIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.getRuleActionIds] parse member ids failed, because %s", err.Error())
+		return "", "", errs.ErrMemberIdInvalid
+	}
+
+	return strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(addTagIds)), ","), strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(setMemberIds)), ","), nil
+}
+
+// getMatchedTransactions returns the transactions of user in the fund which are matched by the specified rule,
+// the rule is evaluated even if it is disabled
+func (a *TransactionRulesApi) getMatchedTransactions(c *core.WebContext, uid int64, fundId int64, rule *models.TransactionRule, maxTime int64, minTime int64) (*models.TransactionRuleEngine, []*models.Transaction, error) {
+	enabledRule := *rule
+	enabledRule.Disabled = false
+	ruleEngine := models.NewTransactionRuleEngine([]*models.TransactionRule{&enabledRule})
+
+	if ruleEngine.IsEmpty() {
+		return nil, nil, errs.ErrTransactionRuleCommentPatternInvalid
+	}
+
+	var accountIds []int64
+
+	if rule.AccountId > 0 {
+		accountIds = []int64{rule.AccountId}
+	}
+
+	transactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTime, minTime, rule.TransactionType, nil, accountIds, nil, false, 0, "", "", nil, pageCountForTransactionRuleMatching, true)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.getMatchedTransactions] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	matchedTransactions := make([]*models.Transaction, 0)
+
+	for _, transaction := range transactions {
+		if transaction.FundId == fundId && ruleEngine.Evaluate(transaction).IsMatched() {
+			matchedTransactions = append(matchedTransactions, transaction)
+		}
+	}
+
+	return ruleEngine, matchedTransactions, nil
+}
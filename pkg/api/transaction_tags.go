@@ -27,7 +27,7 @@ func (a *TransactionTagsApi) TagListHandler(c *core.WebContext) (any, *errs.Erro
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -63,7 +63,7 @@ func (a *TransactionTagsApi) TagGetHandler(c *core.WebContext) (any, *errs.Error
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -93,7 +93,7 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.WebContext) (any, *errs.Er
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_CREATE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -134,7 +134,7 @@ func (a *TransactionTagsApi) TagCreateBatchHandler(c *core.WebContext) (any, *er
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_CREATE)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -181,7 +181,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -231,7 +231,7 @@ func (a *TransactionTagsApi) TagHideHandler(c *core.WebContext) (any, *errs.Erro
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -260,7 +260,7 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.WebContext) (any, *errs.Erro
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -303,7 +303,7 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.WebContext) (any, *errs.Er
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TAG, models.FUND_ACTION_DELETE)
 	if errFund != nil {
 		return nil, errFund
 	}
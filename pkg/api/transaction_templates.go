@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -22,6 +23,7 @@ type TransactionTemplatesApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
 	templates *services.TransactionTemplateService
+	accounts  *services.AccountService
 }
 
 // Initialize a transaction template api singleton instance
@@ -37,6 +39,7 @@ var (
 			container: duplicatechecker.Container,
 		},
 		templates: services.TransactionTemplates,
+		accounts:  services.Accounts,
 	}
 )
 
@@ -60,7 +63,13 @@ func (a *TransactionTemplatesApi) TemplateListHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	templates, err := a.templates.GetAllTemplatesByUid(c, uid, templateListReq.TemplateType)
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	templates, err := a.templates.GetAllTemplatesByUid(c, uid, fundId, templateListReq.TemplateType)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateListHandler] failed to get templates for user \"uid:%d\", because %s", uid, err.Error())
@@ -90,7 +99,13 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 	}
 
 	uid := c.GetCurrentUid()
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateGetReq.Id)
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateGetHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateGetReq.Id, uid, err.Error())
@@ -138,10 +153,9 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
 
-		if *templateCreateReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateCreateReq.ScheduledFrequency != "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
-		} else if *templateCreateReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateCreateReq.ScheduledFrequency == "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
+		if err := models.ValidateScheduledFrequency(*templateCreateReq.ScheduledFrequencyType, *templateCreateReq.ScheduledFrequency); err != nil {
+			log.Warnf(c, "[transaction_templates.TemplateCreateHandler] scheduled frequency \"%s\" is invalid", *templateCreateReq.ScheduledFrequency)
+			return nil, errs.Or(err, errs.ErrScheduledTransactionFrequencyInvalid)
 		}
 	}
 
@@ -151,7 +165,12 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 
 	uid := c.GetCurrentUid()
 
-	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, templateCreateReq.TemplateType)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, fundId, templateCreateReq.TemplateType)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -159,7 +178,7 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 	}
 
 	serverUtcOffset := utils.GetServerTimezoneOffsetMinutes()
-	template, err := a.createNewTemplateModel(uid, &templateCreateReq, maxOrderId+1)
+	template, err := a.createNewTemplateModel(uid, fundId, &templateCreateReq, maxOrderId+1)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to create new template for user \"uid:%d\", because %s", uid, err.Error())
@@ -174,7 +193,7 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 			templateId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				template, err = a.templates.GetTemplateByTemplateId(c, uid, templateId)
+				template, err = a.templates.GetTemplateByTemplateId(c, uid, fundId, templateId)
 
 				if err != nil {
 					log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to get existed template \"id:%d\" for user \"uid:%d\", because %s", templateId, uid, err.Error())
@@ -219,7 +238,13 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 	}
 
 	uid := c.GetCurrentUid()
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateModifyReq.Id)
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateModifyHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateModifyReq.Id, uid, err.Error())
@@ -237,10 +262,9 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
 
-		if *templateModifyReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateModifyReq.ScheduledFrequency != "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
-		} else if *templateModifyReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateModifyReq.ScheduledFrequency == "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
+		if err := models.ValidateScheduledFrequency(*templateModifyReq.ScheduledFrequencyType, *templateModifyReq.ScheduledFrequency); err != nil {
+			log.Warnf(c, "[transaction_templates.TemplateModifyHandler] scheduled frequency \"%s\" is invalid", *templateModifyReq.ScheduledFrequency)
+			return nil, errs.Or(err, errs.ErrScheduledTransactionFrequencyInvalid)
 		}
 	}
 
@@ -268,6 +292,13 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		newTemplate.ScheduledFrequency = a.getOrderedFrequencyValues(*templateModifyReq.ScheduledFrequency)
 		newTemplate.ScheduledAt = a.getUTCScheduledAt(*templateModifyReq.ScheduledTimezoneUtcOffset)
 		newTemplate.ScheduledTimezoneUtcOffset = *templateModifyReq.ScheduledTimezoneUtcOffset
+		newTemplate.ScheduledOccurrenceCount = templateModifyReq.ScheduledOccurrenceCount
+		newTemplate.ScheduledWeekendRule = templateModifyReq.ScheduledWeekendRule
+
+		if err := newTemplate.SetScheduledExceptionDates(templateModifyReq.ScheduledExceptionDates); err != nil {
+			log.Warnf(c, "[transaction_templates.TemplateModifyHandler] scheduled exception dates are invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrScheduledTransactionExceptionDateInvalid)
+		}
 
 		if templateModifyReq.ScheduledStartDate != nil {
 			startTime, err := utils.ParseFromLongDateFirstTime(*templateModifyReq.ScheduledStartDate, *templateModifyReq.ScheduledTimezoneUtcOffset)
@@ -316,7 +347,10 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 				newTemplate.ScheduledStartTime == template.ScheduledStartTime &&
 				newTemplate.ScheduledEndTime == template.ScheduledEndTime &&
 				newTemplate.ScheduledAt == template.ScheduledAt &&
-				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset {
+				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset &&
+				newTemplate.ScheduledOccurrenceCount == template.ScheduledOccurrenceCount &&
+				newTemplate.ScheduledExceptionDates == template.ScheduledExceptionDates &&
+				newTemplate.ScheduledWeekendRule == template.ScheduledWeekendRule {
 				return nil, errs.ErrNothingWillBeUpdated
 			}
 		}
@@ -335,6 +369,7 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 	newTemplate.TemplateType = template.TemplateType
 	newTemplate.DisplayOrder = template.DisplayOrder
 	newTemplate.Hidden = template.Hidden
+	newTemplate.ScheduledCreatedCount = template.ScheduledCreatedCount
 	templateResp := newTemplate.ToTransactionTemplateInfoResponse(serverUtcOffset)
 
 	return templateResp, nil
@@ -352,7 +387,12 @@ func (a *TransactionTemplatesApi) TemplateHideHandler(c *core.WebContext) (any,
 
 	uid := c.GetCurrentUid()
 
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateHideReq.Id)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateHideReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateHideHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateHideReq.Id, uid, err.Error())
@@ -386,8 +426,13 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 
 	uid := c.GetCurrentUid()
 
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
 	if len(templateMoveReq.NewDisplayOrders) > 0 {
-		template, err := a.templates.GetTemplateByTemplateId(c, uid, templateMoveReq.NewDisplayOrders[0].Id)
+		template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateMoveReq.NewDisplayOrders[0].Id)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_templates.TemplateMoveHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateMoveReq.NewDisplayOrders[0].Id, uid, err.Error())
@@ -423,6 +468,158 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 	return true, nil
 }
 
+// TemplateScheduledOccurrencesHandler returns the next occurrences of one specific scheduled transaction template of current user
+func (a *TransactionTemplatesApi) TemplateScheduledOccurrencesHandler(c *core.WebContext) (any, *errs.Error) {
+	var templateOccurrencesReq models.TransactionTemplateScheduledOccurrencesRequest
+	err := c.ShouldBindQuery(&templateOccurrencesReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduledOccurrencesHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateOccurrencesReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduledOccurrencesHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateOccurrencesReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if template.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		return nil, errs.ErrTransactionTemplateTypeInvalid
+	}
+
+	occurrenceTimes, err := template.GetNextScheduledOccurrenceTimes(time.Now().Unix(), templateOccurrencesReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduledOccurrencesHandler] failed to get next occurrences of template \"id:%d\" for user \"uid:%d\", because %s", templateOccurrencesReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
+	occurrenceResps := make([]*models.TransactionTemplateScheduledOccurrenceResponse, len(occurrenceTimes))
+
+	for i := 0; i < len(occurrenceTimes); i++ {
+		occurrenceResps[i] = &models.TransactionTemplateScheduledOccurrenceResponse{
+			Time:      occurrenceTimes[i],
+			UtcOffset: template.ScheduledTimezoneUtcOffset,
+			Date:      utils.FormatUnixTimeToLongDate(occurrenceTimes[i], templateTimeZone),
+		}
+	}
+
+	return occurrenceResps, nil
+}
+
+// TemplateLoanPaymentsCreateHandler saves the scheduled transaction templates of loan payments by request parameters for current user,
+// each payment period creates a transfer template for the principal and an expense template for the interest
+func (a *TransactionTemplatesApi) TemplateLoanPaymentsCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var loanPaymentsCreateReq models.TransactionTemplateLoanPaymentsCreateRequest
+	err := c.ShouldBindJSON(&loanPaymentsCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	if len(loanPaymentsCreateReq.TagIds) > maximumTagsCountOfTemplate {
+		return nil, errs.ErrTransactionTemplateHasTooManyTags
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	accounts, err := a.accounts.GetAccountsByAccountIds(c, uid, fundId, []int64{loanPaymentsCreateReq.LoanAccountId, loanPaymentsCreateReq.PaymentAccountId})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loanAccount, exists := accounts[loanPaymentsCreateReq.LoanAccountId]
+
+	if !exists {
+		return nil, errs.ErrAccountNotFound
+	}
+
+	paymentAccount, exists := accounts[loanPaymentsCreateReq.PaymentAccountId]
+
+	if !exists {
+		return nil, errs.ErrSourceAccountNotFound
+	}
+
+	loanInfo := loanAccount.GetLoanInfo()
+
+	if loanInfo == nil {
+		return nil, errs.ErrAccountHasNoLoanInfo
+	}
+
+	if paymentAccount.Currency != loanAccount.Currency {
+		return nil, errs.ErrLoanPaymentAccountCurrencyNotMatch
+	}
+
+	schedule, err := loanInfo.GetAmortizationSchedule()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] failed to get amortization schedule of account \"id:%d\" for user \"uid:%d\", because %s", loanAccount.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if int(loanPaymentsCreateReq.FromPeriod) > len(schedule) {
+		return nil, errs.ErrLoanPaymentPeriodInvalid
+	}
+
+	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, fundId, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	templates, err := a.createLoanPaymentTemplateModels(uid, fundId, &loanPaymentsCreateReq, loanAccount, schedule, maxOrderId+1)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] failed to create loan payment templates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.templates.CreateTemplates(c, uid, templates)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] failed to save loan payment templates of account \"id:%d\" for user \"uid:%d\", because %s", loanAccount.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateLoanPaymentsCreateHandler] user \"uid:%d\" has created %d loan payment templates of account \"id:%d\" successfully", uid, len(templates), loanAccount.AccountId)
+
+	serverUtcOffset := utils.GetServerTimezoneOffsetMinutes()
+	templateResps := make([]*models.TransactionTemplateInfoResponse, len(templates))
+
+	for i := 0; i < len(templates); i++ {
+		templateResps[i] = templates[i].ToTransactionTemplateInfoResponse(serverUtcOffset)
+	}
+
+	return templateResps, nil
+}
+
 // TemplateDeleteHandler deletes an existed transaction template by request parameters for current user
 func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any, *errs.Error) {
 	var templateDeleteReq models.TransactionTemplateDeleteRequest
@@ -435,7 +632,12 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 
 	uid := c.GetCurrentUid()
 
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateDeleteReq.Id)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TEMPLATE, models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, fundId, templateDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateDeleteHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateDeleteReq.Id, uid, err.Error())
@@ -457,9 +659,10 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 	return true, nil
 }
 
-func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
+func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, fundId int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
 	template := &models.TransactionTemplate{
 		Uid:                  uid,
+		FundId:               fundId,
 		TemplateType:         templateCreateReq.TemplateType,
 		Name:                 templateCreateReq.Name,
 		Type:                 templateCreateReq.Type,
@@ -479,6 +682,12 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 		template.ScheduledFrequency = a.getOrderedFrequencyValues(*templateCreateReq.ScheduledFrequency)
 		template.ScheduledAt = a.getUTCScheduledAt(*templateCreateReq.ScheduledTimezoneUtcOffset)
 		template.ScheduledTimezoneUtcOffset = *templateCreateReq.ScheduledTimezoneUtcOffset
+		template.ScheduledOccurrenceCount = templateCreateReq.ScheduledOccurrenceCount
+		template.ScheduledWeekendRule = templateCreateReq.ScheduledWeekendRule
+
+		if err := template.SetScheduledExceptionDates(templateCreateReq.ScheduledExceptionDates); err != nil {
+			return nil, err
+		}
 
 		if templateCreateReq.ScheduledStartDate != nil {
 			startTime, err := utils.ParseFromLongDateFirstTime(*templateCreateReq.ScheduledStartDate, *templateCreateReq.ScheduledTimezoneUtcOffset)
@@ -510,6 +719,76 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 	return template, nil
 }
 
+func (a *TransactionTemplatesApi) createLoanPaymentTemplateModels(uid int64, fundId int64, loanPaymentsCreateReq *models.TransactionTemplateLoanPaymentsCreateRequest, loanAccount *models.Account, schedule []*models.LoanAmortizationItemResponse, order int32) ([]*models.TransactionTemplate, error) {
+	fromIndex := int(loanPaymentsCreateReq.FromPeriod) - 1
+	toIndex := fromIndex + int(loanPaymentsCreateReq.PeriodCount)
+
+	if toIndex > len(schedule) {
+		toIndex = len(schedule)
+	}
+
+	utcOffset := loanPaymentsCreateReq.ScheduledTimezoneUtcOffset
+	templates := make([]*models.TransactionTemplate, 0, (toIndex-fromIndex)*2)
+
+	for i := fromIndex; i < toIndex; i++ {
+		item := schedule[i]
+		startTime, err := utils.ParseFromLongDateFirstTime(item.Date, utcOffset)
+
+		if err != nil {
+			return nil, err
+		}
+
+		endTime, err := utils.ParseFromLongDateLastTime(item.Date, utcOffset)
+
+		if err != nil {
+			return nil, err
+		}
+
+		startUnixTime := startTime.Unix()
+		endUnixTime := endTime.Unix()
+		name := fmt.Sprintf("%s %d/%d", utils.SubString(loanAccount.Name, 0, 48), item.Period, len(schedule))
+
+		newTemplate := func(transactionType models.TransactionType, categoryId int64, amount int64) *models.TransactionTemplate {
+			template := &models.TransactionTemplate{
+				Uid:                        uid,
+				FundId:                     fundId,
+				TemplateType:               models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+				Name:                       name,
+				Type:                       transactionType,
+				CategoryId:                 categoryId,
+				AccountId:                  loanPaymentsCreateReq.PaymentAccountId,
+				ScheduledFrequencyType:     models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+				ScheduledFrequency:         utils.IntToString(startTime.Day()),
+				ScheduledStartTime:         &startUnixTime,
+				ScheduledEndTime:           &endUnixTime,
+				ScheduledAt:                a.getUTCScheduledAt(utcOffset),
+				ScheduledTimezoneUtcOffset: utcOffset,
+				ScheduledOccurrenceCount:   1,
+				TagIds:                     strings.Join(loanPaymentsCreateReq.TagIds, ","),
+				Amount:                     amount,
+				DisplayOrder:               order,
+			}
+
+			order++
+
+			return template
+		}
+
+		if item.Principal > 0 {
+			template := newTemplate(models.TRANSACTION_TYPE_TRANSFER, loanPaymentsCreateReq.PrincipalCategoryId, item.Principal)
+			template.RelatedAccountId = loanAccount.AccountId
+			template.RelatedAccountAmount = item.Principal
+			templates = append(templates, template)
+		}
+
+		if item.Interest > 0 {
+			templates = append(templates, newTemplate(models.TRANSACTION_TYPE_EXPENSE, loanPaymentsCreateReq.InterestCategoryId, item.Interest))
+		}
+	}
+
+	return templates, nil
+}
+
 func (a *TransactionTemplatesApi) getUTCScheduledAt(scheduledTimezoneUtcOffset int16) int16 {
 	templateTimeZone := time.FixedZone("Template Timezone", int(scheduledTimezoneUtcOffset)*60)
 	transactionTime := time.Date(2020, 1, 1, 0, 0, 0, 0, templateTimeZone)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
@@ -28,12 +29,22 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionPictures   *services.TransactionPictureService
-	accounts              *services.AccountService
-	users                 *services.UserService
+	transactions            *services.TransactionService
+	transactionCategories   *services.TransactionCategoryService
+	transactionTags         *services.TransactionTagService
+	transactionPictures     *services.TransactionPictureService
+	transactionMembers      *services.TransactionMemberService
+	transactionSplits       *services.TransactionSplitService
+	transactionRevisions    *services.TransactionRevisionService
+	transactionLinks        *services.TransactionLinkService
+	transactionRules        *services.TransactionRuleService
+	reconciliations         *services.ReconciliationService
+	templates               *services.TransactionTemplateService
+	payees                  *services.PayeeService
+	accounts                *services.AccountService
+	users                   *services.UserService
+	funds                   *services.FundService
+	historicalExchangeRates *services.HistoricalExchangeRateService
 }
 
 // Initialize a transaction api singleton instance
@@ -48,12 +59,22 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionPictures:   services.TransactionPictures,
-		accounts:              services.Accounts,
-		users:                 services.Users,
+		transactions:            services.Transactions,
+		transactionCategories:   services.TransactionCategories,
+		transactionTags:         services.TransactionTags,
+		transactionPictures:     services.TransactionPictures,
+		transactionMembers:      services.TransactionMembers,
+		transactionSplits:       services.TransactionSplits,
+		transactionRevisions:    services.TransactionRevisions,
+		transactionLinks:        services.TransactionLinks,
+		transactionRules:        services.TransactionRules,
+		reconciliations:         services.Reconciliations,
+		templates:               services.TransactionTemplates,
+		payees:                  services.Payees,
+		accounts:                services.Accounts,
+		users:                   services.Users,
+		funds:                   services.Funds,
+		historicalExchangeRates: services.HistoricalExchangeRates,
 	}
 )
 
@@ -70,7 +91,7 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -101,7 +122,25 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		}
 	}
 
-	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionCountReq.TagFilterType, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
+	var utcOffset int16
+
+	if transactionCountReq.Query != "" {
+		utcOffset, err = c.GetClientTimezoneOffset()
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionCountHandler] cannot get client timezone offset, because %s", err.Error())
+			return nil, errs.ErrClientTimezoneOffsetInvalid
+		}
+	}
+
+	query, err := a.getTransactionQueryExpression(c, uid, fundId, transactionCountReq.Query, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCountHandler] parse transaction query \"%s\" failed, because %s", transactionCountReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionCountReq.TagFilterType, transactionCountReq.AmountFilter, transactionCountReq.Keyword, query)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCountHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -135,7 +174,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -176,10 +215,17 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
+	query, err := a.getTransactionQueryExpression(c, uid, fundId, transactionListReq.Query, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListHandler] parse transaction query \"%s\" failed, because %s", transactionListReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	var totalCount int64
 
 	if transactionListReq.WithCount {
-		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword)
+		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword, query)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -187,7 +233,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword, query, transactionListReq.Page, transactionListReq.Count, true, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transactions earlier than \"%d\" for user \"uid:%d\", because %s", transactionListReq.MaxTime, uid, err.Error())
@@ -245,7 +291,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -286,7 +332,14 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword)
+	query, err := a.getTransactionQueryExpression(c, uid, fundId, transactionListReq.Query, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionMonthListHandler] parse transaction query \"%s\" failed, because %s", transactionListReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword, query)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get transactions in month \"%d-%d\" for user \"uid:%d\", because %s", transactionListReq.Year, transactionListReq.Month, uid, err.Error())
@@ -328,7 +381,7 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 	uid := c.GetCurrentUid()
 
 	// Get fundId for the user
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -453,7 +506,31 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, statisticReq.Keyword, utcOffset, statisticReq.UseTransactionTimezone)
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	query, err := a.getTransactionQueryExpression(c, uid, fundId, statisticReq.Query, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsHandler] parse transaction query \"%s\" failed, because %s", statisticReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var amountsConverter *models.TransactionAmountsConverter
+
+	if statisticReq.UseHistoricalExchangeRates {
+		amountsConverter, err = a.getTransactionAmountsConverter(c, uid, fundId, statisticReq.StartTime, statisticReq.EndTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, fundId, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, statisticReq.Keyword, query, utcOffset, statisticReq.UseTransactionTimezone, statisticReq.NetRefunds, amountsConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -479,6 +556,10 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 			statisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 			statisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
 		}
+
+		if amountsConverter != nil {
+			statisticResp.Items[i].Currency = amountsConverter.DefaultCurrency
+		}
 	}
 
 	return statisticResp, nil
@@ -521,7 +602,41 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	}
 
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, statisticTrendsReq.Keyword, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	query, err := a.getTransactionQueryExpression(c, uid, fundId, statisticTrendsReq.Query, utcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsTrendsHandler] parse transaction query \"%s\" failed, because %s", statisticTrendsReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var amountsConverter *models.TransactionAmountsConverter
+
+	if statisticTrendsReq.UseHistoricalExchangeRates {
+		var startUnixTime, endUnixTime int64
+
+		if startYear > 0 && startMonth > 0 {
+			startUnixTime = time.Date(int(startYear), time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC).Unix()
+		}
+
+		if endYear > 0 && endMonth > 0 {
+			endUnixTime = time.Date(int(endYear), time.Month(endMonth)+1, 1, 0, 0, 0, 0, time.UTC).Unix() - 1
+		}
+
+		amountsConverter, err = a.getTransactionAmountsConverter(c, uid, fundId, startUnixTime, endUnixTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, fundId, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, statisticTrendsReq.Keyword, query, utcOffset, statisticTrendsReq.UseTransactionTimezone, statisticTrendsReq.NetRefunds, amountsConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -549,6 +664,113 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 				monthlyStatisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 				monthlyStatisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
 			}
+
+			if amountsConverter != nil {
+				monthlyStatisticResp.Items[i].Currency = amountsConverter.DefaultCurrency
+			}
+		}
+
+		statisticTrendsResp = append(statisticTrendsResp, monthlyStatisticResp)
+	}
+
+	sort.Sort(statisticTrendsResp)
+
+	return statisticTrendsResp, nil
+}
+
+// TransactionPayeeStatisticsHandler returns transaction statistics grouped by payee of current user
+func (a *TransactionsApi) TransactionPayeeStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticReq models.TransactionPayeeStatisticRequest
+	err := c.ShouldBindQuery(&statisticReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPayeeStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPayeeStatisticsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	totalAmounts, err := a.transactions.GetPayeesTotalIncomeAndExpense(c, uid, fundId, statisticReq.StartTime, statisticReq.EndTime, statisticReq.Type, utcOffset, statisticReq.UseTransactionTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionPayeeStatisticsHandler] failed to get payees total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	statisticResp := &models.TransactionPayeeStatisticResponse{
+		StartTime: statisticReq.StartTime,
+		EndTime:   statisticReq.EndTime,
+		Items:     make([]*models.TransactionPayeeStatisticResponseItem, len(totalAmounts)),
+	}
+
+	for i := 0; i < len(totalAmounts); i++ {
+		statisticResp.Items[i] = totalAmounts[i].ToTransactionPayeeStatisticResponseItem()
+	}
+
+	return statisticResp, nil
+}
+
+// TransactionPayeeStatisticsTrendsHandler returns transaction statistics trends grouped by payee of current user
+func (a *TransactionsApi) TransactionPayeeStatisticsTrendsHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticTrendsReq models.TransactionPayeeStatisticTrendsRequest
+	err := c.ShouldBindQuery(&statisticTrendsReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPayeeStatisticsTrendsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPayeeStatisticsTrendsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	startYear, startMonth, endYear, endMonth, err := statisticTrendsReq.GetNumericYearMonthRange()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPayeeStatisticsTrendsHandler] cannot parse year month, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetPayeesMonthlyIncomeAndExpense(c, uid, fundId, startYear, startMonth, endYear, endMonth, statisticTrendsReq.Type, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionPayeeStatisticsTrendsHandler] failed to get payees monthly income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	statisticTrendsResp := make(models.TransactionPayeeStatisticTrendsResponseItemSlice, 0, len(allMonthlyTotalAmounts))
+
+	for yearMonth, monthlyTotalAmounts := range allMonthlyTotalAmounts {
+		monthlyStatisticResp := &models.TransactionPayeeStatisticTrendsResponseItem{
+			Year:  yearMonth / 100,
+			Month: yearMonth % 100,
+			Items: make([]*models.TransactionPayeeStatisticResponseItem, len(monthlyTotalAmounts)),
+		}
+
+		for i := 0; i < len(monthlyTotalAmounts); i++ {
+			monthlyStatisticResp.Items[i] = monthlyTotalAmounts[i].ToTransactionPayeeStatisticResponseItem()
 		}
 
 		statisticTrendsResp = append(statisticTrendsResp, monthlyStatisticResp)
@@ -559,6 +781,91 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	return statisticTrendsResp, nil
 }
 
+// TransactionForecastHandler returns the day-by-day balance forecast of every account of current user
+func (a *TransactionsApi) TransactionForecastHandler(c *core.WebContext) (any, *errs.Error) {
+	var forecastReq models.TransactionForecastRequest
+	err := c.ShouldBindQuery(&forecastReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionForecastHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionForecastHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now()
+	futureTransactions, err := a.transactions.GetFutureTransactions(c, uid, fundId, now.Unix())
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get future transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var scheduledTemplates []*models.TransactionTemplate
+
+	if a.CurrentConfig().EnableScheduledTransaction {
+		scheduledTemplates, err = a.templates.GetAllTemplatesByUid(c, uid, fundId, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get scheduled templates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	var averageMonthlyAmounts map[int64]int64
+
+	if forecastReq.IncludeAverageAmounts {
+		averageMonths := forecastReq.AverageMonths
+
+		if averageMonths <= 0 {
+			averageMonths = models.DefaultTransactionForecastAverageMonths
+		}
+
+		clientTimezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+		nowInClientTimezone := now.In(clientTimezone)
+		currentMonthFirstTime := time.Date(nowInClientTimezone.Year(), nowInClientTimezone.Month(), 1, 0, 0, 0, 0, clientTimezone)
+		averageStartTime := currentMonthFirstTime.AddDate(0, -averageMonths, 0)
+
+		averageMonthlyAmounts, err = a.transactions.GetAccountsAverageMonthlyAmounts(c, uid, fundId, averageStartTime.Unix(), currentMonthFirstTime.Unix()-1, averageMonths)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get average monthly amounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	forecastResp := models.BuildTransactionForecast(&models.TransactionForecastSource{
+		Accounts:              accounts,
+		ScheduledTemplates:    scheduledTemplates,
+		FutureTransactions:    futureTransactions,
+		AverageMonthlyAmounts: averageMonthlyAmounts,
+		StartUnixTime:         now.Unix(),
+		Months:                forecastReq.Months,
+		UtcOffset:             utcOffset,
+	})
+
+	return forecastResp, nil
+}
+
 // TransactionAmountsHandler returns transaction amounts of current user
 func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *errs.Error) {
 	var transactionAmountsReq models.TransactionAmountsRequest
@@ -614,7 +921,7 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 
 	uid := c.GetCurrentUid()
 
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -731,13 +1038,25 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionGetReq.Id)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionGetReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.TransactionGetHandler] user \"uid:%d\" cannot read transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionGetReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
+	}
+
+	ownerUid := transaction.Uid
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 		transaction = a.transactions.GetRelatedTransferTransaction(transaction)
 	}
@@ -750,11 +1069,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		accountIds = utils.ToUniqueInt64Slice(accountIds)
 	}
 
-	fundId, errFund := GetFundIdFromContext(c, uid)
-	if errFund != nil {
-		return nil, errFund
-	}
-	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, fundId, accountIds)
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, ownerUid, fundId, accountIds)
 
 	if _, exists := accountMap[transaction.AccountId]; !exists {
 		log.Warnf(c, "[transactions.TransactionGetHandler] account of transaction \"id:%d\" does not exist for user \"uid:%d\"", transaction.TransactionId, uid)
@@ -768,7 +1083,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		}
 	}
 
-	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, fundId, []int64{transaction.TransactionId})
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, ownerUid, fundId, []int64{transaction.TransactionId})
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
@@ -780,7 +1095,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 	var pictureInfos []*models.TransactionPictureInfo
 
 	if !transactionGetReq.TrimCategory {
-		category, err = a.transactionCategories.GetCategoryByCategoryId(c, uid, fundId, transaction.CategoryId)
+		category, err = a.transactionCategories.GetCategoryByCategoryId(c, ownerUid, fundId, transaction.CategoryId)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions category for user \"uid:%d\", because %s", uid, err.Error())
@@ -789,7 +1104,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 	}
 
 	if !transactionGetReq.TrimTag {
-		tagMap, err = a.transactionTags.GetTagsByTagIds(c, uid, fundId, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
+		tagMap, err = a.transactionTags.GetTagsByTagIds(c, ownerUid, fundId, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -798,7 +1113,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 	}
 
 	if transactionGetReq.WithPictures && a.CurrentConfig().EnableTransactionPictures {
-		pictureInfos, err = a.transactionPictures.GetPictureInfosByTransactionId(c, uid, transaction.TransactionId)
+		pictureInfos, err = a.transactionPictures.GetPictureInfosByTransactionId(c, ownerUid, transaction.TransactionId)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions pictures for user \"uid:%d\", because %s", uid, err.Error())
@@ -806,6 +1121,20 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		}
 	}
 
+	transactionMembers, err := a.transactionMembers.GetTransactionMembersByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction members for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction split line items for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	transactionEditable := transaction.IsEditable(user, utcOffset, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
@@ -834,6 +1163,20 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 	}
 
+	if transaction.PayeeId > 0 {
+		payee, err := a.payees.GetPayeeByPayeeId(c, ownerUid, fundId, transaction.PayeeId)
+
+		if err == nil {
+			transactionResp.Payee = payee.ToPayeeInfoResponse()
+		} else if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get payee of transaction for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	a.setTransactionMemberInfoResponses(transactionResp, transaction.Amount, transactionMembers)
+	a.setTransactionSplitInfoResponses(transactionResp, transactionSplits)
+
 	return transactionResp, nil
 }
 
@@ -903,13 +1246,53 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
 	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
+	transaction.FundId = fundId
+
+	if transaction.PayeeId == 0 && transactionCreateReq.PayeeName != "" {
+		payeeNameMap, err := a.getPayeeNameMapByNames(c, uid, fundId, []string{transactionCreateReq.PayeeName})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get payee for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if payee, exists := payeeNameMap[models.NormalizePayeeName(transactionCreateReq.PayeeName)]; exists {
+			transaction.PayeeId = payee.PayeeId
+		}
+	}
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
 
 	if !transactionEditable {
 		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
 
+	transactionMembers := a.getTransactionMembersFromRequest(transactionCreateReq.MemberSplitType, transactionCreateReq.Members, transactionCreateReq.MemberIds)
+
+	if len(transactionMembers) > 0 {
+		if _, err = models.CalculateTransactionMemberAmounts(transaction.Amount, transactionMembers); err != nil {
+			log.Warnf(c, "[transactions.TransactionCreateHandler] transaction member split is invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+		}
+	}
+
+	transactionSplits, err := a.getTransactionSplitsFromRequest(transactionCreateReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] parse split line item tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	if err = models.ValidateTransactionSplits(transaction.Type, transaction.Amount, transactionSplits); err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction split line items are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+	}
+
 	var pictureInfos []*models.TransactionPictureInfo
 
 	if len(pictureIds) > 0 {
@@ -951,7 +1334,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, transactionMembers, transactionSplits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -961,42 +1344,105 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	log.Infof(c, "[transactions.TransactionCreateHandler] user \"uid:%d\" has created a new transaction \"id:%d\" successfully", uid, transaction.TransactionId)
 
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
-	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
-	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
-
-	return transactionResp, nil
-}
 
-// TransactionModifyHandler saves an existed transaction by request parameters for current user
-func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *errs.Error) {
-	var transactionModifyReq models.TransactionModifyRequest
-	err := c.ShouldBindJSON(&transactionModifyReq)
+	// The tags may be added by transaction rules
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, fundId, []int64{transaction.TransactionId})
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] parse request failed, because %s", err.Error())
-		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tagIds, err := utils.StringArrayToInt64Array(transactionModifyReq.TagIds)
+	transactionResp := transaction.ToTransactionInfoResponse(allTransactionTagIds[transaction.TransactionId], transactionEditable)
+	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
+
+	// The members may be linked by transaction rules or linked to all members of the fund
+	transactionMembers, err = a.transactionMembers.GetTransactionMembersByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get transaction members for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.setTransactionMemberInfoResponses(transactionResp, transaction.Amount, transactionMembers)
+
+	if len(transactionSplits) > 0 {
+		a.setTransactionSplitInfoResponses(transactionResp, transactionSplits)
+	}
+
+	return transactionResp, nil
+}
+
+// TransactionModifyHandler saves an existed transaction by request parameters for current user
+func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionModifyReq models.TransactionModifyRequest
+	err := c.ShouldBindJSON(&transactionModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	return a.modifyTransaction(c, &transactionModifyReq)
+}
+
+// TransactionBatchModifyHandler applies the same changes to all specified transactions for current user
+func (a *TransactionsApi) TransactionBatchModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionBatchModifyReq models.TransactionBatchModifyRequest
+	err := c.ShouldBindJSON(&transactionBatchModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(transactionBatchModifyReq.Ids)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	if len(transactionIds) > models.MaximumTransactionsCountOfBatchModification {
+		return nil, errs.ErrTooManyTransactionsToBatchModify
+	}
+
+	addTagIds, err := utils.StringArrayToInt64Array(transactionBatchModifyReq.AddTagIds)
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] parse tag ids failed, because %s", err.Error())
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] parse added tag ids failed, because %s", err.Error())
 		return nil, errs.ErrTransactionTagIdInvalid
 	}
 
-	if len(tagIds) > models.MaximumTagsCountOfTransaction {
-		return nil, errs.ErrTransactionHasTooManyTags
+	removeTagIds, err := utils.StringArrayToInt64Array(transactionBatchModifyReq.RemoveTagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] parse removed tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
 	}
 
-	pictureIds, err := utils.StringArrayToInt64Array(transactionModifyReq.PictureIds)
+	memberIds, err := utils.StringArrayToInt64Array(transactionBatchModifyReq.SetMemberIds)
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] parse picture ids failed, because %s", err.Error())
-		return nil, errs.ErrTransactionPictureIdInvalid
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] parse member ids failed, because %s", err.Error())
+		return nil, errs.ErrMemberIdInvalid
 	}
 
-	if len(pictureIds) > models.MaximumPicturesCountOfTransaction {
-		return nil, errs.ErrTransactionHasTooManyPictures
+	modification := &models.TransactionBatchModification{
+		CategoryId:    transactionBatchModifyReq.SetCategoryId,
+		AccountId:     transactionBatchModifyReq.SetAccountId,
+		AddTagIds:     addTagIds,
+		RemoveTagIds:  removeTagIds,
+		MemberIds:     memberIds,
+		Comment:       transactionBatchModifyReq.SetComment,
+		AppendComment: transactionBatchModifyReq.AppendComment,
+	}
+
+	err = modification.Validate()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] batch modification is invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
 	}
 
 	uid := c.GetCurrentUid()
@@ -1004,165 +1450,211 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	if err != nil {
 		if !errs.IsCustomError(err) {
-			log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get user, because %s", err.Error())
+			log.Errorf(c, "[transactions.TransactionBatchModifyHandler] failed to get user, because %s", err.Error())
 		}
 
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionModifyReq.Id)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	if len(transactionIds) < 1 {
+		transactionIds, err = a.getBatchModifyTransactionIdsByFilter(c, uid, fundId, &transactionBatchModifyReq)
 
-	if err != nil {
-		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
 	}
 
-	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionModifyReq.Id, uid)
-		return nil, errs.ErrTransactionTypeInvalid
+	modifiedCount, transactionErrors, err := a.transactions.BatchModifyTransactions(c, uid, fundId, transactionIds, modification, func(transaction *models.Transaction) error {
+		if !permission.IsAllowedOn(uid, transaction.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset) {
+			return errs.ErrCannotModifyTransactionWithThisTransactionTime
+		}
+
+		return nil
+	})
+
+	if err != nil && len(transactionErrors) > 0 {
+		errorResps := make([]*models.TransactionBatchModifyErrorResponse, 0, len(transactionErrors))
+
+		for _, transactionId := range transactionIds {
+			transactionErr, exists := transactionErrors[transactionId]
+
+			if !exists {
+				continue
+			}
+
+			finalErr := errs.Or(transactionErr, errs.ErrOperationFailed)
+			errorResps = append(errorResps, &models.TransactionBatchModifyErrorResponse{
+				Id:           transactionId,
+				ErrorCode:    finalErr.Code(),
+				ErrorMessage: finalErr.Message,
+			})
+		}
+
+		log.Warnf(c, "[transactions.TransactionBatchModifyHandler] %d transactions cannot be modified for user \"uid:%d\"", len(errorResps), uid)
+		return nil, errs.NewErrorWithContext(errs.ErrTransactionBatchModificationFailed, errorResps)
+	} else if err != nil {
+		log.Errorf(c, "[transactions.TransactionBatchModifyHandler] failed to batch modify transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId != 0 {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] balance modification transaction cannot set category id")
-		return nil, errs.ErrBalanceModificationTransactionCannotSetCategory
-	} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId == 0 {
-		log.Warnf(c, "[transactions.TransactionModifyHandler] non-balance modification transaction must set category id")
-		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	log.Infof(c, "[transactions.TransactionBatchModifyHandler] user \"uid:%d\" has batch modified %d transactions successfully", uid, modifiedCount)
+
+	return &models.TransactionBatchModifyResponse{
+		ModifiedCount: modifiedCount,
+	}, nil
+}
+
+// TransactionRevisionListHandler returns all revisions of a transaction with the changed fields of each modification for current user
+func (a *TransactionsApi) TransactionRevisionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionRevisionListReq models.TransactionRevisionListRequest
+	err := c.ShouldBindQuery(&transactionRevisionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionRevisionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	uid := c.GetCurrentUid()
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
 
-	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, fundId, []int64{transaction.TransactionId})
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionRevisionListReq.Id)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionRevisionListReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.TransactionRevisionListHandler] user \"uid:%d\" cannot read transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionRevisionListReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
+	}
 
-	if transactionTagIds == nil {
-		transactionTagIds = make([]int64, 0, 0)
+	ownerUid := transaction.Uid
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transaction = a.transactions.GetRelatedTransferTransaction(transaction)
 	}
 
-	transactionPictureInfos, err := a.transactionPictures.GetPictureInfosByTransactionId(c, uid, transaction.TransactionId)
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, ownerUid, fundId, []int64{transaction.TransactionId})
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction picture infos for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
+	transactionPictureInfos, err := a.transactionPictures.GetPictureInfosByTransactionId(c, ownerUid, transaction.TransactionId)
 
-	newTransaction := &models.Transaction{
-		TransactionId:     transaction.TransactionId,
-		Uid:               uid,
-		CategoryId:        transactionModifyReq.CategoryId,
-		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionModifyReq.Time),
-		TimezoneUtcOffset: transactionModifyReq.UtcOffset,
-		AccountId:         transactionModifyReq.SourceAccountId,
-		Amount:            transactionModifyReq.SourceAmount,
-		HideAmount:        transactionModifyReq.HideAmount,
-		Comment:           transactionModifyReq.Comment,
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get transaction picture infos for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-		newTransaction.RelatedAccountId = transactionModifyReq.DestinationAccountId
-		newTransaction.RelatedAccountAmount = transactionModifyReq.DestinationAmount
-	}
+	transactionMembers, err := a.transactionMembers.GetTransactionMembersByTransactionId(c, ownerUid, transaction.TransactionId)
 
-	if transactionModifyReq.GeoLocation != nil {
-		newTransaction.GeoLongitude = transactionModifyReq.GeoLocation.Longitude
-		newTransaction.GeoLatitude = transactionModifyReq.GeoLocation.Latitude
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get transaction members for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	if newTransaction.CategoryId == transaction.CategoryId &&
-		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
-		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
-		newTransaction.AccountId == transaction.AccountId &&
-		newTransaction.Amount == transaction.Amount &&
-		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
-		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
-		newTransaction.HideAmount == transaction.HideAmount &&
-		newTransaction.Comment == transaction.Comment &&
-		newTransaction.GeoLongitude == transaction.GeoLongitude &&
-		newTransaction.GeoLatitude == transaction.GeoLatitude &&
-		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
-		return nil, errs.ErrNothingWillBeUpdated
+	transactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get transaction split line items for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset)
-	newTransactionEditable := user.CanEditTransactionByTransactionTime(newTransaction.TransactionTime, transactionModifyReq.UtcOffset)
+	transactionRevisions, err := a.transactionRevisions.GetRevisionsByTransactionId(c, ownerUid, transaction.TransactionId)
 
-	if !transactionEditable || !newTransactionEditable {
-		return nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to get revisions of transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	var addTransactionTagIds []int64
-	var removeTransactionTagIds []int64
+	nextSnapshot := models.NewTransactionRevisionSnapshot(transaction, allTransactionTagIds[transaction.TransactionId], a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos), transactionMembers, transactionSplits)
+	transactionRevisionResps := make([]*models.TransactionRevisionInfoResponse, len(transactionRevisions))
 
-	if !utils.Int64SliceEquals(tagIds, transactionTagIds) {
-		removeTransactionTagIds = transactionTagIds
-		addTransactionTagIds = tagIds
-	}
+	for i := 0; i < len(transactionRevisions); i++ {
+		snapshot, err := transactionRevisions[i].GetSnapshot()
 
-	addTransactionPictureIds := utils.Int64SliceMinus(pictureIds, transactionPictureIds)
-	removeTransactionPictureIds := utils.Int64SliceMinus(transactionPictureIds, pictureIds)
-	var newPictureInfos []*models.TransactionPictureInfo
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionRevisionListHandler] failed to parse snapshot of transaction \"id:%d\" revision \"%d\", because %s", transaction.TransactionId, transactionRevisions[i].Revision, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
 
-	if !utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
-		oldAndNewPictureIds := transactionPictureIds
-		oldAndNewPictureInfoMap := a.transactionPictures.GetPictureInfoMapByList(transactionPictureInfos)
+		transactionRevisionResps[i] = transactionRevisions[i].ToTransactionRevisionInfoResponse(snapshot, nextSnapshot)
+		nextSnapshot = snapshot
+	}
 
-		if len(addTransactionPictureIds) > 0 {
-			addPictureInfos, err := a.transactionPictures.GetNewPictureInfosByPictureIds(c, uid, addTransactionPictureIds)
+	return transactionRevisionResps, nil
+}
 
-			if err != nil {
-				log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transactions pictures for user \"uid:%d\", because %s", uid, err.Error())
-				return nil, errs.Or(err, errs.ErrOperationFailed)
-			}
+// TransactionRevisionRestoreHandler restores a transaction to the state of the specified revision for current user
+func (a *TransactionsApi) TransactionRevisionRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionRevisionRestoreReq models.TransactionRevisionRestoreRequest
+	err := c.ShouldBindJSON(&transactionRevisionRestoreReq)
 
-			oldAndNewPictureIds = append(oldAndNewPictureIds, a.transactionPictures.GetTransactionPictureIds(addPictureInfos)...)
-			notExistsPictureIds := utils.Int64SliceMinus(pictureIds, oldAndNewPictureIds)
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionRevisionRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
 
-			if len(notExistsPictureIds) > 0 {
-				log.Errorf(c, "[transactions.TransactionModifyHandler] some pictures \"ids:%s\" does not exists for user \"uid:%d\"", strings.Join(utils.Int64ArrayToStringArray(notExistsPictureIds), ","), uid)
-				return nil, errs.ErrTransactionPictureNotFound
-			}
+	uid := c.GetCurrentUid()
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
 
-			for i := 0; i < len(addPictureInfos); i++ {
-				oldAndNewPictureInfoMap[addPictureInfos[i].PictureId] = addPictureInfos[i]
-			}
-		}
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionRevisionRestoreReq.Id)
 
-		for i := 0; i < len(pictureIds); i++ {
-			pictureId := pictureIds[i]
-			pictureInfo, exists := oldAndNewPictureInfoMap[pictureId]
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionRestoreHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionRevisionRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-			if exists {
-				newPictureInfos = append(newPictureInfos, pictureInfo)
-			}
-		}
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.TransactionRevisionRestoreHandler] user \"uid:%d\" cannot modify transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionRevisionRestoreReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	ownerUid := transaction.Uid
+	transactionRevision, err := a.transactionRevisions.GetRevision(c, ownerUid, transactionRevisionRestoreReq.Id, transactionRevisionRestoreReq.Revision)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionRevisionRestoreHandler] failed to get revision \"%d\" of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionRevisionRestoreReq.Revision, transactionRevisionRestoreReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	log.Infof(c, "[transactions.TransactionModifyHandler] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionModifyReq.Id)
+	snapshot, err := transactionRevision.GetSnapshot()
 
-	newTransaction.Type = transaction.Type
-	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
-	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionRestoreHandler] failed to parse snapshot of transaction \"id:%d\" revision \"%d\", because %s", transactionRevisionRestoreReq.Id, transactionRevisionRestoreReq.Revision, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
 
-	return newTransactionResp, nil
+	// Pictures removed after the revision have been deleted, so only the pictures still attached can be kept
+	transactionPictureInfos, err := a.transactionPictures.GetPictureInfosByTransactionId(c, ownerUid, transactionRevisionRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRevisionRestoreHandler] failed to get transaction picture infos for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionModifyReq := snapshot.ToTransactionModifyRequest(transactionRevisionRestoreReq.Id, a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos))
+	transactionModifyReq.UnlockReconciled = transactionRevisionRestoreReq.UnlockReconciled
+
+	return a.modifyTransaction(c, transactionModifyReq)
 }
 
 // TransactionMoveAllBetweenAccountsHandler moves all transactions from one account to another account for current user
@@ -1180,10 +1672,16 @@ func (a *TransactionsApi) TransactionMoveAllBetweenAccountsHandler(c *core.WebCo
 	}
 
 	uid := c.GetCurrentUid()
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
+
+	// Moving all transactions would change transactions created by other members
+	if permission != models.FUND_PERMISSION_ALL {
+		return nil, errs.ErrFundPermissionDenied
+	}
+
 	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, fundId, []int64{transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId})
 
 	if err != nil {
@@ -1254,13 +1752,23 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionDeleteReq.Id)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionDeleteHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.TransactionDeleteHandler] user \"uid:%d\" cannot delete transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionDeleteReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
+	}
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 		log.Warnf(c, "[transactions.TransactionDeleteHandler] cannot delete transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionDeleteReq.Id, uid)
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1272,7 +1780,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrCannotDeleteTransactionWithThisTransactionTime
 	}
 
-	err = a.transactions.DeleteTransaction(c, uid, transactionDeleteReq.Id)
+	err = a.transactions.DeleteTransaction(c, transaction.Uid, uid, transactionDeleteReq.Id, transactionDeleteReq.UnlockReconciled)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionDeleteHandler] failed to delete transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
@@ -1283,479 +1791,1330 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 	return true, nil
 }
 
-// TransactionParseImportDsvFileDataHandler returns the parsed file data by request parameters for current user
-func (a *TransactionsApi) TransactionParseImportDsvFileDataHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	form, err := c.MultipartForm()
+// TransactionClearedStatusModifyHandler sets the cleared status of an existed transaction by request parameters for current user
+func (a *TransactionsApi) TransactionClearedStatusModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionClearedStatusModifyReq models.TransactionClearedStatusModifyRequest
+	err := c.ShouldBindJSON(&transactionClearedStatusModifyReq)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrParameterInvalid
+		log.Warnf(c, "[transactions.TransactionClearedStatusModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	fileTypes := form.Value["fileType"]
+	uid := c.GetCurrentUid()
 
-	if len(fileTypes) < 1 || fileTypes[0] == "" {
-		return nil, errs.ErrImportFileTypeIsEmpty
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
 	}
 
-	fileType := fileTypes[0]
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionClearedStatusModifyReq.Id)
 
-	if !converters.IsCustomDelimiterSeparatedValuesFileType(fileType) {
-		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionClearedStatusModifyHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionClearedStatusModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	fileEncodings := form.Value["fileEncoding"]
-
-	if len(fileEncodings) < 1 || fileEncodings[0] == "" {
-		return nil, errs.ErrImportFileEncodingIsEmpty
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.TransactionClearedStatusModifyHandler] user \"uid:%d\" cannot modify transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionClearedStatusModifyReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
 	}
 
-	fileEncoding := fileEncodings[0]
-	dataParser, err := converters.CreateNewDelimiterSeparatedValuesDataParser(fileType, fileEncoding)
+	err = a.transactions.ModifyTransactionClearedStatus(c, transaction.Uid, transactionClearedStatusModifyReq.Id, transactionClearedStatusModifyReq.ClearedStatus, transactionClearedStatusModifyReq.UnlockReconciled)
 
 	if err != nil {
-		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+		log.Errorf(c, "[transactions.TransactionClearedStatusModifyHandler] failed to set cleared status of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionClearedStatusModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	importFiles := form.File["file"]
-
-	if len(importFiles) < 1 {
-		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] there is no import file in request for user \"uid:%d\"", uid)
-		return nil, errs.ErrNoFilesUpload
-	}
+	log.Infof(c, "[transactions.TransactionClearedStatusModifyHandler] user \"uid:%d\" has set cleared status of transaction \"id:%d\" to %s", uid, transactionClearedStatusModifyReq.Id, transactionClearedStatusModifyReq.ClearedStatus)
+	return true, nil
+}
 
-	if importFiles[0].Size < 1 {
-		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
-		return nil, errs.ErrUploadedFileEmpty
-	}
+// TransactionReconciliationListHandler returns reconciliation list of current user
+func (a *TransactionsApi) TransactionReconciliationListHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationListReq models.ReconciliationListRequest
+	err := c.ShouldBindQuery(&reconciliationListReq)
 
-	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
-		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
-		return nil, errs.ErrExceedMaxUploadFileSize
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	importFile, err := importFiles[0].Open()
+	uid := c.GetCurrentUid()
 
-	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
 	}
 
-	defer importFile.Close()
-	fileData, err := io.ReadAll(importFile)
+	reconciliations, err := a.reconciliations.GetAllReconciliationsByAccountId(c, uid, fundId, reconciliationListReq.AccountId)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionReconciliationListHandler] failed to get reconciliations for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allLines, err := dataParser.ParseDsvFileLines(c, fileData)
+	reconciliationResps := make([]*models.ReconciliationInfoResponse, len(reconciliations))
 
-	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to parse import file data for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+	for i := 0; i < len(reconciliations); i++ {
+		reconciliationResps[i] = reconciliations[i].ToReconciliationInfoResponse()
 	}
 
-	return allLines, nil
+	return reconciliationResps, nil
 }
 
-// TransactionParseImportFileHandler returns the parsed transaction data by request parameters for current user
-func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	form, err := c.MultipartForm()
+// TransactionReconciliationGetHandler returns one specific reconciliation with the transactions which can be ticked of current user
+func (a *TransactionsApi) TransactionReconciliationGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationGetReq models.ReconciliationGetRequest
+	err := c.ShouldBindQuery(&reconciliationGetReq)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrParameterInvalid
+		log.Warnf(c, "[transactions.TransactionReconciliationGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	utcOffset, err := c.GetClientTimezoneOffset()
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] cannot get client timezone offset, because %s", err.Error())
+		log.Warnf(c, "[transactions.TransactionReconciliationGetHandler] cannot get client timezone offset, because %s", err.Error())
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	fileTypes := form.Value["fileType"]
+	uid := c.GetCurrentUid()
 
-	if len(fileTypes) < 1 || fileTypes[0] == "" {
-		return nil, errs.ErrImportFileTypeIsEmpty
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
 	}
 
-	fileType := fileTypes[0]
-
-	var dataImporter converter.TransactionDataImporter
-
-	if converters.IsCustomDelimiterSeparatedValuesFileType(fileType) {
-		fileEncodings := form.Value["fileEncoding"]
+	user, err := a.users.GetUserById(c, uid)
 
-		if len(fileEncodings) < 1 || fileEncodings[0] == "" {
-			return nil, errs.ErrImportFileEncodingIsEmpty
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionReconciliationGetHandler] failed to get user, because %s", err.Error())
 		}
 
-		fileEncoding := fileEncodings[0]
+		return nil, errs.ErrUserNotFound
+	}
 
-		columnMappings := form.Value["columnMapping"]
+	reconciliation, err := a.reconciliations.GetReconciliationById(c, uid, fundId, reconciliationGetReq.Id)
 
-		if len(columnMappings) < 1 || columnMappings[0] == "" {
-			return nil, errs.ErrImportFileColumnMappingInvalid
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationGetHandler] failed to get reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		var columnIndexMapping = map[datatable.TransactionDataTableColumn]int{}
-		err = json.Unmarshal([]byte(columnMappings[0]), &columnIndexMapping)
+	reconciliationResp := &models.ReconciliationDetailResponse{
+		ReconciliationInfoResponse: reconciliation.ToReconciliationInfoResponse(),
+		Transactions:               models.TransactionInfoResponseSlice{},
+	}
 
-		if err != nil {
-			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse column mapping for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrImportFileColumnMappingInvalid
-		}
+	if reconciliation.IsCompleted() {
+		return reconciliationResp, nil
+	}
 
-		transactionTypeMappings := form.Value["transactionTypeMapping"]
+	clearedBalance, err := a.reconciliations.GetClearedBalance(c, uid, reconciliation)
 
-		if len(transactionTypeMappings) < 1 || transactionTypeMappings[0] == "" {
-			return nil, errs.ErrImportFileTransactionTypeMappingInvalid
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationGetHandler] failed to get cleared balance of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		var transactionTypeNameMapping = map[string]models.TransactionType{}
-		err = json.Unmarshal([]byte(transactionTypeMappings[0]), &transactionTypeNameMapping)
+	transactions, err := a.reconciliations.GetUnreconciledTransactions(c, uid, reconciliation)
 
-		if err != nil {
-			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse transaction type mapping for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrImportFileTransactionTypeMappingInvalid
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationGetHandler] failed to get transactions of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		hasHeaderLines := form.Value["hasHeaderLine"]
-		hasHeaderLine := false
+	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, utcOffset, false, true, true, true)
 
-		if len(hasHeaderLines) > 0 {
-			hasHeaderLine = hasHeaderLines[0] == "true"
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationGetHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		timeFormats := form.Value["timeFormat"]
+	reconciliationResp.ClearedBalance = clearedBalance
+	reconciliationResp.Difference = reconciliation.StatementBalance - clearedBalance
+	reconciliationResp.Transactions = transactionResult
 
-		if len(timeFormats) < 1 || timeFormats[0] == "" {
-			return nil, errs.ErrImportFileTransactionTimeFormatInvalid
-		}
+	return reconciliationResp, nil
+}
 
-		timezoneFormats := form.Value["timezoneFormat"]
-		timezoneFormat := ""
+// TransactionReconciliationCreateHandler starts a new reconciliation by request parameters for current user
+func (a *TransactionsApi) TransactionReconciliationCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationCreateReq models.ReconciliationCreateRequest
+	err := c.ShouldBindJSON(&reconciliationCreateReq)
 
-		if len(timezoneFormats) > 0 {
-			timezoneFormat = timezoneFormats[0]
-		}
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
 
-		amountDecimalSeparators := form.Value["amountDecimalSeparator"]
-		amountDecimalSeparator := ""
+	uid := c.GetCurrentUid()
 
-		if len(amountDecimalSeparators) > 0 {
-			amountDecimalSeparator = amountDecimalSeparators[0]
-		}
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
 
-		amountDigitGroupingSymbols := form.Value["amountDigitGroupingSymbol"]
-		amountDigitGroupingSymbol := ""
+	account, err := a.accounts.GetAccountByAccountId(c, uid, fundId, reconciliationCreateReq.AccountId)
 
-		if len(amountDigitGroupingSymbols) > 0 {
-			amountDigitGroupingSymbol = amountDigitGroupingSymbols[0]
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationCreateHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", reconciliationCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		geoLocationSeparators := form.Value["geoSeparator"]
-		geoLocationSeparator := ""
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[transactions.TransactionReconciliationCreateHandler] account \"id:%d\" for user \"uid:%d\" is not a single account", reconciliationCreateReq.AccountId, uid)
+		return nil, errs.ErrAccountTypeInvalid
+	}
 
-		if len(geoLocationSeparators) > 0 {
-			geoLocationSeparator = geoLocationSeparators[0]
-		}
+	reconciliation := &models.Reconciliation{
+		Uid:              uid,
+		FundId:           fundId,
+		AccountId:        account.AccountId,
+		StatementEndTime: reconciliationCreateReq.StatementEndTime,
+		StatementBalance: reconciliationCreateReq.StatementBalance,
+	}
 
-		geoLocationOrders := form.Value["geoOrder"]
-		geoLocationOrder := ""
+	err = a.reconciliations.CreateReconciliation(c, reconciliation)
 
-		if len(geoLocationOrders) > 0 {
-			geoLocationOrder = geoLocationOrders[0]
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationCreateHandler] failed to create reconciliation for account \"id:%d\" of user \"uid:%d\", because %s", reconciliationCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		transactionTagSeparators := form.Value["tagSeparator"]
-		transactionTagSeparator := ""
+	log.Infof(c, "[transactions.TransactionReconciliationCreateHandler] user \"uid:%d\" has started reconciliation \"id:%d\" for account \"id:%d\"", uid, reconciliation.ReconciliationId, reconciliation.AccountId)
 
-		if len(transactionTagSeparators) > 0 {
-			transactionTagSeparator = transactionTagSeparators[0]
-		}
+	return reconciliation.ToReconciliationInfoResponse(), nil
+}
 
-		dataImporter, err = converters.CreateNewDelimiterSeparatedValuesDataImporter(fileType, fileEncoding, columnIndexMapping, transactionTypeNameMapping, hasHeaderLine, timeFormats[0], timezoneFormat, amountDecimalSeparator, amountDigitGroupingSymbol, geoLocationSeparator, geoLocationOrder, transactionTagSeparator)
-	} else {
-		dataImporter, err = converters.GetTransactionDataImporter(fileType)
-	}
+// TransactionReconciliationMarkHandler marks the transactions in a reconciliation as cleared or uncleared for current user
+func (a *TransactionsApi) TransactionReconciliationMarkHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationMarkReq models.ReconciliationMarkRequest
+	err := c.ShouldBindJSON(&reconciliationMarkReq)
 
 	if err != nil {
-		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+		log.Warnf(c, "[transactions.TransactionReconciliationMarkHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	importFiles := form.File["file"]
+	transactionIds, err := utils.StringArrayToInt64Array(reconciliationMarkReq.TransactionIds)
 
-	if len(importFiles) < 1 {
-		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] there is no import file in request for user \"uid:%d\"", uid)
-		return nil, errs.ErrNoFilesUpload
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationMarkHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
 	}
 
-	if importFiles[0].Size < 1 {
-		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
-		return nil, errs.ErrUploadedFileEmpty
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
 	}
 
-	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
-		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
-		return nil, errs.ErrExceedMaxUploadFileSize
+	err = a.reconciliations.MarkTransactions(c, uid, fundId, reconciliationMarkReq.Id, transactionIds, reconciliationMarkReq.Cleared)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationMarkHandler] failed to mark transactions of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationMarkReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	importFile, err := importFiles[0].Open()
+	return true, nil
+}
+
+// TransactionReconciliationCompleteHandler completes a reconciliation and locks all cleared transactions for current user
+func (a *TransactionsApi) TransactionReconciliationCompleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationCompleteReq models.ReconciliationCompleteRequest
+	err := c.ShouldBindJSON(&reconciliationCompleteReq)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Warnf(c, "[transactions.TransactionReconciliationCompleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	defer importFile.Close()
-	fileData, err := io.ReadAll(importFile)
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	reconciliation, err := a.reconciliations.CompleteReconciliation(c, uid, fundId, reconciliationCompleteReq.Id)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionReconciliationCompleteHandler] failed to complete reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationCompleteReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	user, err := a.users.GetUserById(c, uid)
+	log.Infof(c, "[transactions.TransactionReconciliationCompleteHandler] user \"uid:%d\" has completed reconciliation \"id:%d\" with %d transactions reconciled", uid, reconciliation.ReconciliationId, reconciliation.ReconciledCount)
 
-	if err != nil {
-		if !errs.IsCustomError(err) {
-			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get user, because %s", err.Error())
-		}
+	return reconciliation.ToReconciliationInfoResponse(), nil
+}
 
-		return nil, errs.ErrUserNotFound
-	}
+// TransactionReconciliationDeleteHandler cancels an in-progress reconciliation for current user
+func (a *TransactionsApi) TransactionReconciliationDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconciliationDeleteReq models.ReconciliationDeleteRequest
+	err := c.ShouldBindJSON(&reconciliationDeleteReq)
 
-	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
-		return nil, errs.ErrNotPermittedToPerformThisAction
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	uid := c.GetCurrentUid()
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
 	if errFund != nil {
 		return nil, errFund
 	}
-	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid, fundId)
+
+	err = a.reconciliations.DeleteReconciliation(c, uid, fundId, reconciliationDeleteReq.Id)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get accounts for user \"uid:%d\", because %s", user.Uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionReconciliationDeleteHandler] failed to delete reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationDeleteReq.Id, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
+	log.Infof(c, "[transactions.TransactionReconciliationDeleteHandler] user \"uid:%d\" has deleted reconciliation \"id:%d\"", uid, reconciliationDeleteReq.Id)
+	return true, nil
+}
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, fundId, 0, -1)
+// TransactionLinkListHandler returns all links from or to one specific transaction of current fund
+func (a *TransactionsApi) TransactionLinkListHandler(c *core.WebContext) (any, *errs.Error) {
+	var linkListReq models.TransactionLinkListRequest
+	err := c.ShouldBindQuery(&linkListReq)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get categories for user \"uid:%d\", because %s", user.Uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+		log.Warnf(c, "[transactions.TransactionLinkListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetVisibleSubCategoryNameMapByList(categories)
+	uid := c.GetCurrentUid()
 
-	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid, fundId)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	links, err := a.transactionLinks.GetLinksByTransactionId(c, uid, fundId, linkListReq.TransactionId, func(transaction *models.Transaction) error {
+		if !permission.IsAllowedOn(uid, transaction.Uid) {
+			log.Warnf(c, "[transactions.TransactionLinkListHandler] user \"uid:%d\" cannot read transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transaction.TransactionId, fundId)
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	})
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get tags for user \"uid:%d\", because %s", user.Uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionLinkListHandler] failed to get links of transaction \"id:%d\" for user \"uid:%d\", because %s", linkListReq.TransactionId, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tagMap := a.transactionTags.GetVisibleTagNameMapByList(tags)
+	linkResps := make([]*models.TransactionLinkInfoResponse, len(links))
 
-	parsedTransactions, _, _, _, _, _, err := dataImporter.ParseImportedData(c, user, fileData, utcOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+	for i := 0; i < len(links); i++ {
+		linkResps[i] = links[i].ToTransactionLinkInfoResponse()
+	}
+
+	return linkResps, nil
+}
+
+// TransactionLinkCreateHandler links a transaction to another transaction by request parameters for current user
+func (a *TransactionsApi) TransactionLinkCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var linkCreateReq models.TransactionLinkCreateRequest
+	err := c.ShouldBindJSON(&linkCreateReq)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse imported data for user \"uid:%d\", because %s", user.Uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+		log.Warnf(c, "[transactions.TransactionLinkCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
+	uid := c.GetCurrentUid()
 
-	if len(parsedTransactionRespsList) < 1 {
-		return nil, errs.ErrNoDataToImport
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
 	}
 
-	parsedTransactionResps := &models.ImportTransactionResponsePageWrapper{
-		Items:      parsedTransactionRespsList,
-		TotalCount: int64(len(parsedTransactionRespsList)),
+	link := &models.TransactionLink{
+		Uid:                 uid,
+		FundId:              fundId,
+		TransactionId:       linkCreateReq.TransactionId,
+		LinkedTransactionId: linkCreateReq.LinkedTransactionId,
+		LinkType:            linkCreateReq.LinkType,
+		Comment:             linkCreateReq.Comment,
 	}
 
-	return parsedTransactionResps, nil
+	err = a.transactionLinks.CreateLink(c, link, func(transaction *models.Transaction) error {
+		if !permission.IsAllowedOn(uid, transaction.Uid) {
+			log.Warnf(c, "[transactions.TransactionLinkCreateHandler] user \"uid:%d\" cannot link transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transaction.TransactionId, fundId)
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionLinkCreateHandler] failed to link transaction \"id:%d\" to transaction \"id:%d\" for user \"uid:%d\", because %s", linkCreateReq.TransactionId, linkCreateReq.LinkedTransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionLinkCreateHandler] user \"uid:%d\" has created a new transaction link \"id:%d\" successfully", uid, link.LinkId)
+
+	return link.ToTransactionLinkInfoResponse(), nil
 }
 
-// TransactionImportHandler imports transactions by request parameters for current user
-func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *errs.Error) {
-	var transactionImportReq models.TransactionImportRequest
-	err := c.ShouldBindJSON(&transactionImportReq)
+// TransactionLinkDeleteHandler deletes an existed transaction link by request parameters for current user
+func (a *TransactionsApi) TransactionLinkDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var linkDeleteReq models.TransactionLinkDeleteRequest
+	err := c.ShouldBindJSON(&linkDeleteReq)
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionImportHandler] parse request failed, because %s", err.Error())
+		log.Warnf(c, "[transactions.TransactionLinkDeleteHandler] parse request failed, because %s", err.Error())
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
 	uid := c.GetCurrentUid()
 
-	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionImportReq.ClientSessionId != "" {
-		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
 
-		if found {
-			items := strings.Split(remark, ":")
+	err = a.transactionLinks.DeleteLink(c, uid, fundId, linkDeleteReq.Id, func(link *models.TransactionLink) error {
+		if !permission.IsAllowedOn(uid, link.Uid) {
+			log.Warnf(c, "[transactions.TransactionLinkDeleteHandler] user \"uid:%d\" cannot delete transaction link \"id:%d\" created by other user in fund \"id:%d\"", uid, link.LinkId, fundId)
+			return errs.ErrFundPermissionDenied
+		}
 
-			if len(items) >= 2 {
-				if items[0] == "finished" {
-					log.Infof(c, "[transactions.TransactionImportHandler] another \"%s\" transactions has been imported for user \"uid:%d\"", items[1], uid)
-					count, err := utils.StringToInt(items[1])
+		return nil
+	})
 
-					if err == nil {
-						return count, nil
-					}
-				} else if items[0] == "processing" {
-					return nil, errs.ErrRepeatedRequest
-				}
-			} else {
-				log.Warnf(c, "[transactions.TransactionImportHandler] another transaction import task may be executing, but remark \"%s\" is invalid", remark)
-			}
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionLinkDeleteHandler] failed to delete transaction link \"id:%d\" for user \"uid:%d\", because %s", linkDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionLinkDeleteHandler] user \"uid:%d\" has deleted transaction link \"id:%d\"", uid, linkDeleteReq.Id)
+	return true, nil
+}
+
+// TransactionParseImportDsvFileDataHandler returns the parsed file data by request parameters for current user
+func (a *TransactionsApi) TransactionParseImportDsvFileDataHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	fileTypes := form.Value["fileType"]
+
+	if len(fileTypes) < 1 || fileTypes[0] == "" {
+		return nil, errs.ErrImportFileTypeIsEmpty
+	}
+
+	fileType := fileTypes[0]
+
+	if !converters.IsCustomDelimiterSeparatedValuesFileType(fileType) {
+		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	}
+
+	fileEncodings := form.Value["fileEncoding"]
+
+	if len(fileEncodings) < 1 || fileEncodings[0] == "" {
+		return nil, errs.ErrImportFileEncodingIsEmpty
+	}
+
+	fileEncoding := fileEncodings[0]
+	dataParser, err := converters.CreateNewDelimiterSeparatedValuesDataParser(fileType, fileEncoding)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	}
+
+	importFiles := form.File["file"]
+
+	if len(importFiles) < 1 {
+		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] there is no import file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if importFiles[0].Size < 1 {
+		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[transactions.TransactionParseImportDsvFileDataHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	importFile, err := importFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer importFile.Close()
+	fileData, err := io.ReadAll(importFile)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allLines, err := dataParser.ParseDsvFileLines(c, fileData)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportDsvFileDataHandler] failed to parse import file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return allLines, nil
+}
+
+// TransactionParseImportFileHandler returns the parsed transaction data by request parameters for current user
+func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	fileTypes := form.Value["fileType"]
+
+	if len(fileTypes) < 1 || fileTypes[0] == "" {
+		return nil, errs.ErrImportFileTypeIsEmpty
+	}
+
+	fileType := fileTypes[0]
+
+	var dataImporter converter.TransactionDataImporter
+
+	if converters.IsCustomDelimiterSeparatedValuesFileType(fileType) {
+		fileEncodings := form.Value["fileEncoding"]
+
+		if len(fileEncodings) < 1 || fileEncodings[0] == "" {
+			return nil, errs.ErrImportFileEncodingIsEmpty
+		}
+
+		fileEncoding := fileEncodings[0]
+
+		columnMappings := form.Value["columnMapping"]
+
+		if len(columnMappings) < 1 || columnMappings[0] == "" {
+			return nil, errs.ErrImportFileColumnMappingInvalid
+		}
+
+		var columnIndexMapping = map[datatable.TransactionDataTableColumn]int{}
+		err = json.Unmarshal([]byte(columnMappings[0]), &columnIndexMapping)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse column mapping for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrImportFileColumnMappingInvalid
+		}
+
+		transactionTypeMappings := form.Value["transactionTypeMapping"]
+
+		if len(transactionTypeMappings) < 1 || transactionTypeMappings[0] == "" {
+			return nil, errs.ErrImportFileTransactionTypeMappingInvalid
+		}
+
+		var transactionTypeNameMapping = map[string]models.TransactionType{}
+		err = json.Unmarshal([]byte(transactionTypeMappings[0]), &transactionTypeNameMapping)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse transaction type mapping for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrImportFileTransactionTypeMappingInvalid
+		}
+
+		hasHeaderLines := form.Value["hasHeaderLine"]
+		hasHeaderLine := false
+
+		if len(hasHeaderLines) > 0 {
+			hasHeaderLine = hasHeaderLines[0] == "true"
+		}
+
+		timeFormats := form.Value["timeFormat"]
+
+		if len(timeFormats) < 1 || timeFormats[0] == "" {
+			return nil, errs.ErrImportFileTransactionTimeFormatInvalid
+		}
+
+		timezoneFormats := form.Value["timezoneFormat"]
+		timezoneFormat := ""
+
+		if len(timezoneFormats) > 0 {
+			timezoneFormat = timezoneFormats[0]
+		}
+
+		amountDecimalSeparators := form.Value["amountDecimalSeparator"]
+		amountDecimalSeparator := ""
+
+		if len(amountDecimalSeparators) > 0 {
+			amountDecimalSeparator = amountDecimalSeparators[0]
+		}
+
+		amountDigitGroupingSymbols := form.Value["amountDigitGroupingSymbol"]
+		amountDigitGroupingSymbol := ""
+
+		if len(amountDigitGroupingSymbols) > 0 {
+			amountDigitGroupingSymbol = amountDigitGroupingSymbols[0]
+		}
+
+		geoLocationSeparators := form.Value["geoSeparator"]
+		geoLocationSeparator := ""
+
+		if len(geoLocationSeparators) > 0 {
+			geoLocationSeparator = geoLocationSeparators[0]
+		}
+
+		geoLocationOrders := form.Value["geoOrder"]
+		geoLocationOrder := ""
+
+		if len(geoLocationOrders) > 0 {
+			geoLocationOrder = geoLocationOrders[0]
+		}
+
+		transactionTagSeparators := form.Value["tagSeparator"]
+		transactionTagSeparator := ""
+
+		if len(transactionTagSeparators) > 0 {
+			transactionTagSeparator = transactionTagSeparators[0]
+		}
+
+		dataImporter, err = converters.CreateNewDelimiterSeparatedValuesDataImporter(fileType, fileEncoding, columnIndexMapping, transactionTypeNameMapping, hasHeaderLine, timeFormats[0], timezoneFormat, amountDecimalSeparator, amountDigitGroupingSymbol, geoLocationSeparator, geoLocationOrder, transactionTagSeparator)
+	} else {
+		dataImporter, err = converters.GetTransactionDataImporter(fileType)
+	}
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	}
+
+	importFiles := form.File["file"]
+
+	if len(importFiles) < 1 {
+		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] there is no import file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if importFiles[0].Size < 1 {
+		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[transactions.TransactionParseImportFileHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	importFile, err := importFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer importFile.Close()
+	fileData, err := io.ReadAll(importFile)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get accounts for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, fundId, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get categories for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetVisibleSubCategoryNameMapByList(categories)
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get tags for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagMap := a.transactionTags.GetVisibleTagNameMapByList(tags)
+
+	parsedTransactions, _, _, _, _, _, err := dataImporter.ParseImportedData(c, user, fileData, utcOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse imported data for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleEngine, err := a.transactionRules.GetRuleEngine(c, user.Uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get transaction rules for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payees, err := a.payees.GetAllPayeesByUid(c, user.Uid, fundId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get payees for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryIdMap := a.transactionCategories.GetCategoryMapByList(categories)
+	parsedTransactions.FillPayees(a.payees.GetPayeeNameMapByList(payees), categoryIdMap)
+	parsedTransactions.ApplyTransactionRules(ruleEngine, categoryIdMap, a.transactionTags.GetTagMapByList(tags))
+
+	existingTransactions, err := a.transactions.GetDuplicateCandidateTransactions(c, user.Uid, fundId, parsedTransactions.ToTransactionsList())
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get existing transactions for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	parsedTransactions.FillDuplicateMatches(existingTransactions)
+
+	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
+
+	if len(parsedTransactionRespsList) < 1 {
+		return nil, errs.ErrNoDataToImport
+	}
+
+	parsedTransactionResps := &models.ImportTransactionResponsePageWrapper{
+		Items:      parsedTransactionRespsList,
+		TotalCount: int64(len(parsedTransactionRespsList)),
+	}
+
+	return parsedTransactionResps, nil
+}
+
+// TransactionImportHandler imports transactions by request parameters for current user
+func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionImportReq models.TransactionImportRequest
+	err := c.ShouldBindJSON(&transactionImportReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionImportHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionImportReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
+
+		if found {
+			items := strings.Split(remark, ":")
+
+			if len(items) >= 2 {
+				if items[0] == "finished" {
+					log.Infof(c, "[transactions.TransactionImportHandler] another \"%s\" transactions has been imported for user \"uid:%d\"", items[1], uid)
+					count, err := utils.StringToInt(items[1])
+
+					if err == nil {
+						return count, nil
+					}
+				} else if items[0] == "processing" {
+					return nil, errs.ErrRepeatedRequest
+				}
+			} else {
+				log.Warnf(c, "[transactions.TransactionImportHandler] another transaction import task may be executing, but remark \"%s\" is invalid", remark)
+			}
+		}
+	}
+
+	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	newTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
+
+	for i := 0; i < len(transactionImportReq.Transactions); i++ {
+		transactionCreateReq := transactionImportReq.Transactions[i]
+		tagIds, err := utils.StringArrayToInt64Array(transactionCreateReq.TagIds)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionImportHandler] parse tag ids failed of transaction \"index:%d\", because %s", i, err.Error())
+			return nil, errs.ErrTransactionTagIdInvalid
+		}
+
+		if len(tagIds) > models.MaximumTagsCountOfTransaction {
+			return nil, errs.ErrTransactionHasTooManyTags
+		}
+
+		if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
+			log.Warnf(c, "[transactions.TransactionImportHandler] transaction type of transaction \"index:%d\" is invalid", i)
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		if transactionCreateReq.Type == models.TRANSACTION_TYPE_MODIFY_BALANCE && transactionCreateReq.CategoryId != 0 {
+			log.Warnf(c, "[transactions.TransactionImportHandler] balance modification transaction \"index:%d\" cannot set category id", i)
+			return nil, errs.ErrBalanceModificationTransactionCannotSetCategory
+		}
+
+		if transactionCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.DestinationAccountId != 0 {
+			log.Warnf(c, "[transactions.TransactionImportHandler] non-transfer transaction \"index:%d\" destination account cannot be set", i)
+			return nil, errs.ErrTransactionDestinationAccountCannotBeSet
+		} else if transactionCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.SourceAccountId == transactionCreateReq.DestinationAccountId {
+			log.Warnf(c, "[transactions.TransactionImportHandler] transfer transaction \"index:%d\" source account must not be destination account", i)
+			return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+		}
+
+		if transactionCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.DestinationAmount != 0 {
+			log.Warnf(c, "[transactions.TransactionImportHandler] non-transfer transaction \"index:%d\" destination amount cannot be set", i)
+			return nil, errs.ErrTransactionDestinationAmountCannotBeSet
+		}
+
+		transactionSplits, err := a.getTransactionSplitsFromRequest(transactionCreateReq.Splits)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionImportHandler] parse split line item tag ids failed of transaction \"index:%d\", because %s", i, err.Error())
+			return nil, errs.ErrTransactionTagIdInvalid
+		}
+
+		newTransactionTagIdsMap[i] = tagIds
+
+		if len(transactionSplits) > 0 {
+			newTransactionSplitsMap[i] = transactionSplits
+		}
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionImportHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_CREATE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	payeeNames := make([]string, 0, len(transactionImportReq.Transactions))
+
+	for i := 0; i < len(transactionImportReq.Transactions); i++ {
+		if transactionImportReq.Transactions[i].PayeeId == 0 && transactionImportReq.Transactions[i].PayeeName != "" {
+			payeeNames = append(payeeNames, transactionImportReq.Transactions[i].PayeeName)
+		}
+	}
+
+	payeeNameMap, err := a.getPayeeNameMapByNames(c, uid, fundId, payeeNames)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionImportHandler] failed to get payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newTransactions := make([]*models.Transaction, len(transactionImportReq.Transactions))
+
+	for i := 0; i < len(transactionImportReq.Transactions); i++ {
+		transactionCreateReq := transactionImportReq.Transactions[i]
+		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction.FundId = fundId
+
+		if payee, exists := payeeNameMap[models.NormalizePayeeName(transactionCreateReq.PayeeName)]; exists && transaction.PayeeId == 0 {
+			transaction.PayeeId = payee.PayeeId
+		}
+		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
+
+		if !transactionEditable {
+			return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+		}
+
+		if err = models.ValidateTransactionSplits(transaction.Type, transaction.Amount, newTransactionSplitsMap[i]); err != nil {
+			log.Warnf(c, "[transactions.TransactionImportHandler] split line items of transaction \"index:%d\" are invalid, because %s", i, err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+		}
+
+		newTransactions[i] = transaction
+	}
+
+	if transactionImportReq.SkipDuplicates {
+		existingTransactions, err := a.transactions.GetDuplicateCandidateTransactions(c, uid, fundId, newTransactions)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionImportHandler] failed to get existing transactions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		duplicateMatches := models.FindDuplicateTransactions(newTransactions, existingTransactions)
+		nonDuplicateTransactions := make([]*models.Transaction, 0, len(newTransactions))
+		nonDuplicateTransactionTagIdsMap := make(map[int][]int64, len(newTransactions))
+		nonDuplicateTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
+
+		for i := 0; i < len(newTransactions); i++ {
+			if duplicateMatches[i] != nil && !transactionImportReq.Transactions[i].ForceImport {
+				log.Infof(c, "[transactions.TransactionImportHandler] skip transaction \"index:%d\" which duplicates transaction \"id:%d\" for user \"uid:%d\"", i, duplicateMatches[i].TransactionId, uid)
+				continue
+			}
+
+			newIndex := len(nonDuplicateTransactions)
+			nonDuplicateTransactions = append(nonDuplicateTransactions, newTransactions[i])
+			nonDuplicateTransactionTagIdsMap[newIndex] = newTransactionTagIdsMap[i]
+
+			if transactionSplits, exists := newTransactionSplitsMap[i]; exists {
+				nonDuplicateTransactionSplitsMap[newIndex] = transactionSplits
+			}
+		}
+
+		newTransactions = nonDuplicateTransactions
+		newTransactionTagIdsMap = nonDuplicateTransactionTagIdsMap
+		newTransactionSplitsMap = nonDuplicateTransactionSplitsMap
+
+		if len(newTransactions) < 1 {
+			log.Infof(c, "[transactions.TransactionImportHandler] all transactions are duplicated for user \"uid:%d\"", uid)
+			a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, "finished:0")
+			return 0, nil
+		}
+	}
+
+	// Transaction rules have been applied when parsing the import file, so the category and tags confirmed by user are kept
+	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newTransactionSplitsMap, false, func(currentProcess float64) {
+		a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("processing:%.2f", currentProcess))
+	})
+	count := len(newTransactions)
+
+	if err != nil {
+		a.RemoveSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
+		log.Errorf(c, "[transactions.TransactionImportHandler] failed to import %d transactions for user \"uid:%d\", because %s", count, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully", uid, count)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("finished:%d", count))
+
+	return count, nil
+}
+
+// TransactionImportProcessHandler returns the process of specified transaction import task by request parameters for current user
+func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionImportProcessReq models.TransactionImportProcessRequest
+	err := c.ShouldBindQuery(&transactionImportProcessReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionImportProcessHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	if !a.CurrentConfig().EnableDuplicateSubmissionsCheck {
+		return nil, nil
+	}
+
+	found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportProcessReq.ClientSessionId)
+
+	if !found {
+		return nil, nil
+	}
+
+	items := strings.Split(remark, ":")
+
+	if len(items) < 2 {
+		return nil, nil
+	}
+
+	if items[0] == "finished" {
+		return 100, nil
+	} else if items[0] != "processing" {
+		return nil, nil
+	}
+
+	process, err := utils.StringToFloat64(items[1])
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionImportProcessHandler] parse process failed, because %s", err.Error())
+		return nil, nil
+	}
+
+	if process < 0 {
+		return nil, nil
+	} else if process >= 100 {
+		process = 100
+	}
+
+	return process, nil
+}
+
+// modifyTransaction modifies an existed transaction by the modification request for current user and saves the state
+// of transaction before modification as a new revision
+func (a *TransactionsApi) modifyTransaction(c *core.WebContext, transactionModifyReq *models.TransactionModifyRequest) (any, *errs.Error) {
+	tagIds, err := utils.StringArrayToInt64Array(transactionModifyReq.TagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.modifyTransaction] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	if len(tagIds) > models.MaximumTagsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManyTags
+	}
+
+	pictureIds, err := utils.StringArrayToInt64Array(transactionModifyReq.PictureIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.modifyTransaction] parse picture ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionPictureIdInvalid
+	}
+
+	if len(pictureIds) > models.MaximumPicturesCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManyPictures
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.modifyTransaction] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_MODIFY)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	transaction, err := a.transactions.GetTransactionByFundIdAndTransactionId(c, uid, fundId, transactionModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !permission.IsAllowedOn(uid, transaction.Uid) {
+		log.Warnf(c, "[transactions.modifyTransaction] user \"uid:%d\" cannot modify transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transactionModifyReq.Id, fundId)
+		return nil, errs.ErrFundPermissionDenied
+	}
+
+	ownerUid := transaction.Uid
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		log.Warnf(c, "[transactions.modifyTransaction] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionModifyReq.Id, uid)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId != 0 {
+		log.Warnf(c, "[transactions.modifyTransaction] balance modification transaction cannot set category id")
+		return nil, errs.ErrBalanceModificationTransactionCannotSetCategory
+	} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId == 0 {
+		log.Warnf(c, "[transactions.modifyTransaction] non-balance modification transaction must set category id")
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, ownerUid, fundId, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
+
+	if transactionTagIds == nil {
+		transactionTagIds = make([]int64, 0, 0)
+	}
+
+	transactionPictureInfos, err := a.transactionPictures.GetPictureInfosByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to get transaction picture infos for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
+
+	existedTransactionMembers, err := a.transactionMembers.GetTransactionMembersByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to get transaction members for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	existedTransactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, ownerUid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to get transaction split line items for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	oldTransactionSnapshot := models.NewTransactionRevisionSnapshot(transaction, transactionTagIds, transactionPictureIds, existedTransactionMembers, existedTransactionSplits)
+
+	newTransaction := &models.Transaction{
+		TransactionId:     transaction.TransactionId,
+		Uid:               ownerUid,
+		CategoryId:        transactionModifyReq.CategoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionModifyReq.Time),
+		TimezoneUtcOffset: transactionModifyReq.UtcOffset,
+		AccountId:         transactionModifyReq.SourceAccountId,
+		PayeeId:           transactionModifyReq.PayeeId,
+		Amount:            transactionModifyReq.SourceAmount,
+		HideAmount:        transactionModifyReq.HideAmount,
+		Comment:           transactionModifyReq.Comment,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		newTransaction.RelatedAccountId = transactionModifyReq.DestinationAccountId
+		newTransaction.RelatedAccountAmount = transactionModifyReq.DestinationAmount
+	}
+
+	if transactionModifyReq.GeoLocation != nil {
+		newTransaction.GeoLongitude = transactionModifyReq.GeoLocation.Longitude
+		newTransaction.GeoLatitude = transactionModifyReq.GeoLocation.Latitude
+	}
+
+	transactionMembers := a.getTransactionMembersFromRequest(transactionModifyReq.MemberSplitType, transactionModifyReq.Members, transactionModifyReq.MemberIds)
+
+	if len(transactionMembers) > 0 {
+		if _, err = models.CalculateTransactionMemberAmounts(newTransaction.Amount, transactionMembers); err != nil {
+			log.Warnf(c, "[transactions.modifyTransaction] transaction member split is invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+		}
+	} else if transactionMembers == nil && newTransaction.Amount != transaction.Amount && len(existedTransactionMembers) > 0 {
+		if _, err = models.CalculateTransactionMemberAmounts(newTransaction.Amount, existedTransactionMembers); err != nil {
+			log.Warnf(c, "[transactions.modifyTransaction] existed transaction member split does not match the new amount, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
 		}
 	}
 
-	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	transactionSplits, err := a.getTransactionSplitsFromRequest(transactionModifyReq.Splits)
 
-	for i := 0; i < len(transactionImportReq.Transactions); i++ {
-		transactionCreateReq := transactionImportReq.Transactions[i]
-		tagIds, err := utils.StringArrayToInt64Array(transactionCreateReq.TagIds)
+	if err != nil {
+		log.Warnf(c, "[transactions.modifyTransaction] parse split line item tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
 
-		if err != nil {
-			log.Warnf(c, "[transactions.TransactionImportHandler] parse tag ids failed of transaction \"index:%d\", because %s", i, err.Error())
-			return nil, errs.ErrTransactionTagIdInvalid
+	if transactionSplits != nil {
+		if err = models.ValidateTransactionSplits(transaction.Type, newTransaction.Amount, transactionSplits); err != nil {
+			log.Warnf(c, "[transactions.modifyTransaction] transaction split line items are invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
 		}
-
-		if len(tagIds) > models.MaximumTagsCountOfTransaction {
-			return nil, errs.ErrTransactionHasTooManyTags
+	} else if newTransaction.Amount != transaction.Amount && len(existedTransactionSplits) > 0 {
+		if err = models.ValidateTransactionSplits(transaction.Type, newTransaction.Amount, existedTransactionSplits); err != nil {
+			log.Warnf(c, "[transactions.modifyTransaction] existed transaction split line items do not match the new amount, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
 		}
+	}
 
-		if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
-			log.Warnf(c, "[transactions.TransactionImportHandler] transaction type of transaction \"index:%d\" is invalid", i)
-			return nil, errs.ErrTransactionTypeInvalid
-		}
+	transactionChanged := newTransaction.CategoryId != transaction.CategoryId ||
+		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) ||
+		newTransaction.TimezoneUtcOffset != transaction.TimezoneUtcOffset ||
+		newTransaction.AccountId != transaction.AccountId ||
+		newTransaction.PayeeId != transaction.PayeeId ||
+		newTransaction.Amount != transaction.Amount ||
+		(transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && newTransaction.RelatedAccountId != transaction.RelatedAccountId) ||
+		(transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && newTransaction.RelatedAccountAmount != transaction.RelatedAccountAmount) ||
+		newTransaction.HideAmount != transaction.HideAmount ||
+		newTransaction.Comment != transaction.Comment ||
+		newTransaction.GeoLongitude != transaction.GeoLongitude ||
+		newTransaction.GeoLatitude != transaction.GeoLatitude ||
+		!utils.Int64SliceEquals(tagIds, transactionTagIds) ||
+		!utils.Int64SliceEquals(pictureIds, transactionPictureIds)
 
-		if transactionCreateReq.Type == models.TRANSACTION_TYPE_MODIFY_BALANCE && transactionCreateReq.CategoryId != 0 {
-			log.Warnf(c, "[transactions.TransactionImportHandler] balance modification transaction \"index:%d\" cannot set category id", i)
-			return nil, errs.ErrBalanceModificationTransactionCannotSetCategory
-		}
+	if !transactionChanged && transactionMembers == nil && transactionSplits == nil {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
 
-		if transactionCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.DestinationAccountId != 0 {
-			log.Warnf(c, "[transactions.TransactionImportHandler] non-transfer transaction \"index:%d\" destination account cannot be set", i)
-			return nil, errs.ErrTransactionDestinationAccountCannotBeSet
-		} else if transactionCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.SourceAccountId == transactionCreateReq.DestinationAccountId {
-			log.Warnf(c, "[transactions.TransactionImportHandler] transfer transaction \"index:%d\" source account must not be destination account", i)
-			return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
-		}
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset)
+	newTransactionEditable := user.CanEditTransactionByTransactionTime(newTransaction.TransactionTime, transactionModifyReq.UtcOffset)
 
-		if transactionCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && transactionCreateReq.DestinationAmount != 0 {
-			log.Warnf(c, "[transactions.TransactionImportHandler] non-transfer transaction \"index:%d\" destination amount cannot be set", i)
-			return nil, errs.ErrTransactionDestinationAmountCannotBeSet
-		}
+	if !transactionEditable || !newTransactionEditable {
+		return nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+	}
 
-		newTransactionTagIdsMap[i] = tagIds
+	var addTransactionTagIds []int64
+	var removeTransactionTagIds []int64
+
+	if !utils.Int64SliceEquals(tagIds, transactionTagIds) {
+		removeTransactionTagIds = transactionTagIds
+		addTransactionTagIds = tagIds
 	}
 
-	user, err := a.users.GetUserById(c, uid)
+	addTransactionPictureIds := utils.Int64SliceMinus(pictureIds, transactionPictureIds)
+	removeTransactionPictureIds := utils.Int64SliceMinus(transactionPictureIds, pictureIds)
+	var newPictureInfos []*models.TransactionPictureInfo
 
-	if err != nil {
-		if !errs.IsCustomError(err) {
-			log.Errorf(c, "[transactions.TransactionImportHandler] failed to get user, because %s", err.Error())
+	if !utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		oldAndNewPictureIds := transactionPictureIds
+		oldAndNewPictureInfoMap := a.transactionPictures.GetPictureInfoMapByList(transactionPictureInfos)
+
+		if len(addTransactionPictureIds) > 0 {
+			addPictureInfos, err := a.transactionPictures.GetNewPictureInfosByPictureIds(c, ownerUid, addTransactionPictureIds)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.modifyTransaction] failed to get transactions pictures for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			oldAndNewPictureIds = append(oldAndNewPictureIds, a.transactionPictures.GetTransactionPictureIds(addPictureInfos)...)
+			notExistsPictureIds := utils.Int64SliceMinus(pictureIds, oldAndNewPictureIds)
+
+			if len(notExistsPictureIds) > 0 {
+				log.Errorf(c, "[transactions.modifyTransaction] some pictures \"ids:%s\" does not exists for user \"uid:%d\"", strings.Join(utils.Int64ArrayToStringArray(notExistsPictureIds), ","), uid)
+				return nil, errs.ErrTransactionPictureNotFound
+			}
+
+			for i := 0; i < len(addPictureInfos); i++ {
+				oldAndNewPictureInfoMap[addPictureInfos[i].PictureId] = addPictureInfos[i]
+			}
 		}
 
-		return nil, errs.ErrUserNotFound
-	}
+		for i := 0; i < len(pictureIds); i++ {
+			pictureId := pictureIds[i]
+			pictureInfo, exists := oldAndNewPictureInfoMap[pictureId]
 
-	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
-		return nil, errs.ErrNotPermittedToPerformThisAction
+			if exists {
+				newPictureInfos = append(newPictureInfos, pictureInfo)
+			}
+		}
 	}
 
-	newTransactions := make([]*models.Transaction, len(transactionImportReq.Transactions))
+	transactionRevision, err := models.NewTransactionRevision(transaction.TransactionId, ownerUid, transaction.FundId, oldTransactionSnapshot)
 
-	for i := 0; i < len(transactionImportReq.Transactions); i++ {
-		transactionCreateReq := transactionImportReq.Transactions[i]
-		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
-		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
+	if err != nil {
+		log.Errorf(c, "[transactions.modifyTransaction] failed to create revision of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
 
-		if !transactionEditable {
-			return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+	if transactionChanged {
+		err = a.transactions.ModifyTransaction(c, newTransaction, uid, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, transactionMembers, transactionSplits, transactionRevision, transactionModifyReq.UnlockReconciled)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.modifyTransaction] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
 		}
+	} else {
+		err = a.transactions.ModifyTransactionMembersAndSplits(c, ownerUid, transaction.TransactionId, transactionMembers, transactionSplits, transactionRevision, transactionModifyReq.UnlockReconciled)
 
-		newTransactions[i] = transaction
+		if err != nil {
+			log.Errorf(c, "[transactions.modifyTransaction] failed to update members and split line items of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
 	}
 
-	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, func(currentProcess float64) {
-		a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("processing:%.2f", currentProcess))
-	})
-	count := len(newTransactions)
+	if transactionMembers != nil {
+		existedTransactionMembers, err = a.transactionMembers.GetTransactionMembersByTransactionId(c, ownerUid, transaction.TransactionId)
 
-	if err != nil {
-		a.RemoveSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
-		log.Errorf(c, "[transactions.TransactionImportHandler] failed to import %d transactions for user \"uid:%d\", because %s", count, uid, err.Error())
-		return nil, errs.Or(err, errs.ErrOperationFailed)
+		if err != nil {
+			log.Errorf(c, "[transactions.modifyTransaction] failed to get transaction members for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
 	}
 
-	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully", uid, count)
+	if transactionSplits != nil {
+		existedTransactionSplits = transactionSplits
+	}
 
-	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("finished:%d", count))
+	log.Infof(c, "[transactions.modifyTransaction] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionModifyReq.Id)
 
-	return count, nil
+	newTransaction.Type = transaction.Type
+	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
+	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
+	a.setTransactionMemberInfoResponses(newTransactionResp, newTransaction.Amount, existedTransactionMembers)
+	a.setTransactionSplitInfoResponses(newTransactionResp, existedTransactionSplits)
+
+	return newTransactionResp, nil
 }
 
-// TransactionImportProcessHandler returns the process of specified transaction import task by request parameters for current user
-func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (any, *errs.Error) {
-	var transactionImportProcessReq models.TransactionImportProcessRequest
-	err := c.ShouldBindQuery(&transactionImportProcessReq)
+func (a *TransactionsApi) getBatchModifyTransactionIdsByFilter(c *core.WebContext, uid int64, fundId int64, transactionBatchModifyReq *models.TransactionBatchModifyRequest) ([]int64, error) {
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionBatchModifyReq.AccountIds, uid, fundId)
 
 	if err != nil {
-		log.Warnf(c, "[transactions.TransactionImportProcessHandler] parse request failed, because %s", err.Error())
-		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		log.Warnf(c, "[transactions.getBatchModifyTransactionIdsByFilter] get account error, because %s", err.Error())
+		return nil, err
 	}
 
-	uid := c.GetCurrentUid()
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionBatchModifyReq.CategoryIds, uid, fundId)
 
-	if !a.CurrentConfig().EnableDuplicateSubmissionsCheck {
-		return nil, nil
+	if err != nil {
+		log.Warnf(c, "[transactions.getBatchModifyTransactionIdsByFilter] get transaction category error, because %s", err.Error())
+		return nil, err
 	}
 
-	found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportProcessReq.ClientSessionId)
-
-	if !found {
-		return nil, nil
-	}
+	var allTagIds []int64
+	noTags := transactionBatchModifyReq.TagIds == "none"
 
-	items := strings.Split(remark, ":")
+	if !noTags {
+		allTagIds, err = a.transactionTags.GetTagIds(transactionBatchModifyReq.TagIds)
 
-	if len(items) < 2 {
-		return nil, nil
+		if err != nil {
+			log.Warnf(c, "[transactions.getBatchModifyTransactionIdsByFilter] get transaction tag ids error, because %s", err.Error())
+			return nil, err
+		}
 	}
 
-	if items[0] == "finished" {
-		return 100, nil
-	} else if items[0] != "processing" {
-		return nil, nil
+	transactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, transactionBatchModifyReq.MaxTime, transactionBatchModifyReq.MinTime, transactionBatchModifyReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionBatchModifyReq.TagFilterType, transactionBatchModifyReq.AmountFilter, transactionBatchModifyReq.Keyword, nil, models.MaximumTransactionsCountOfBatchModification, true)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getBatchModifyTransactionIdsByFilter] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
 	}
 
-	process, err := utils.StringToFloat64(items[1])
+	transactionIds := make([]int64, 0, len(transactions))
 
-	if err != nil {
-		log.Warnf(c, "[transactions.TransactionImportProcessHandler] parse process failed, because %s", err.Error())
-		return nil, nil
+	for _, transaction := range transactions {
+		if transaction.FundId == fundId {
+			transactionIds = append(transactionIds, transaction.TransactionId)
+		}
 	}
 
-	if process < 0 {
-		return nil, nil
-	} else if process >= 100 {
-		process = 100
+	if len(transactionIds) > models.MaximumTransactionsCountOfBatchModification {
+		return nil, errs.ErrTooManyTransactionsToBatchModify
 	}
 
-	return process, nil
+	return transactionIds, nil
 }
 
 func (a *TransactionsApi) filterTransactions(c *core.WebContext, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account) []*models.Transaction {
@@ -1782,6 +3141,84 @@ func (a *TransactionsApi) filterTransactions(c *core.WebContext, uid int64, tran
 	return finalTransactions
 }
 
+func (a *TransactionsApi) getTransactionMembersFromRequest(splitType models.TransactionMemberSplitType, memberReqs []*models.TransactionMemberSplitRequest, memberIds []int64) []*models.TransactionMember {
+	if memberReqs != nil {
+		if splitType == 0 {
+			splitType = models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL
+		}
+
+		transactionMembers := make([]*models.TransactionMember, len(memberReqs))
+
+		for i, memberReq := range memberReqs {
+			transactionMembers[i] = &models.TransactionMember{
+				MemberId:   memberReq.MemberId,
+				SplitType:  splitType,
+				SplitValue: memberReq.SplitValue,
+			}
+		}
+
+		return transactionMembers
+	}
+
+	if memberIds != nil {
+		transactionMembers := make([]*models.TransactionMember, len(memberIds))
+
+		for i, memberId := range memberIds {
+			transactionMembers[i] = &models.TransactionMember{
+				MemberId:  memberId,
+				SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL,
+			}
+		}
+
+		return transactionMembers
+	}
+
+	return nil
+}
+
+func (a *TransactionsApi) setTransactionMemberInfoResponses(transactionResp *models.TransactionInfoResponse, amount int64, transactionMembers []*models.TransactionMember) {
+	if len(transactionMembers) < 1 {
+		return
+	}
+
+	transactionResp.MemberSplitType = transactionMembers[0].SplitType
+	transactionResp.Members = models.ToTransactionMemberInfoResponses(amount, transactionMembers)
+}
+
+func (a *TransactionsApi) getTransactionSplitsFromRequest(splitReqs []*models.TransactionSplitRequest) ([]*models.TransactionSplit, error) {
+	if splitReqs == nil {
+		return nil, nil
+	}
+
+	transactionSplits := make([]*models.TransactionSplit, len(splitReqs))
+
+	for i, splitReq := range splitReqs {
+		tagIds, err := utils.StringArrayToInt64Array(splitReq.TagIds)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transactionSplits[i] = &models.TransactionSplit{
+			LineIndex:  int32(i),
+			CategoryId: splitReq.CategoryId,
+			Amount:     splitReq.Amount,
+			TagIds:     strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(tagIds)), ","),
+			Comment:    splitReq.Comment,
+		}
+	}
+
+	return transactionSplits, nil
+}
+
+func (a *TransactionsApi) setTransactionSplitInfoResponses(transactionResp *models.TransactionInfoResponse, transactionSplits []*models.TransactionSplit) {
+	if len(transactionSplits) < 1 {
+		return
+	}
+
+	transactionResp.Splits = models.ToTransactionSplitInfoResponses(transactionSplits)
+}
+
 func (a *TransactionsApi) getTransactionTagInfoResponses(tagIds []int64, allTransactionTags map[int64]*models.TransactionTag) []*models.TransactionTagInfoResponse {
 	allTags := make([]*models.TransactionTagInfoResponse, 0, len(tagIds))
 
@@ -1821,7 +3258,7 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		categoryIds = append(categoryIds, transactions[i].CategoryId)
 	}
 
-	fundId, errFund := GetFundIdFromContext(c, uid)
+	fundId, _, errFund := GetFundIdFromContextWithPermission(c, uid, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
 	if errFund != nil {
 		return nil, errFund
 	}
@@ -1919,6 +3356,95 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
+// getTransactionQueryExpression parses the transaction query and resolves the names in the query, returns nil if the query is empty
+func (a *TransactionsApi) getTransactionQueryExpression(c *core.WebContext, uid int64, fundId int64, query string, utcOffset int16) (*models.TransactionQueryExpression, error) {
+	expression, err := models.ParseTransactionQuery(query, utcOffset)
+
+	if err != nil || expression == nil {
+		return nil, err
+	}
+
+	err = a.transactions.ResolveTransactionQueryNames(c, uid, fundId, expression)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expression, nil
+}
+
+// getTransactionAmountsConverter returns a converter which converts the amounts of transactions in the time range to the default currency of user
+// by the stored historical exchange rates, the start time and end time can be 0
+func (a *TransactionsApi) getTransactionAmountsConverter(c *core.WebContext, uid int64, fundId int64, startUnixTime int64, endUnixTime int64) (*models.TransactionAmountsConverter, error) {
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+
+	if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, fundId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := []string{user.DefaultCurrency}
+	currenciesMap := map[string]bool{user.DefaultCurrency: true}
+
+	for i := 0; i < len(accounts); i++ {
+		if !currenciesMap[accounts[i].Currency] {
+			currencies = append(currencies, accounts[i].Currency)
+			currenciesMap[accounts[i].Currency] = true
+		}
+	}
+
+	if endUnixTime <= 0 {
+		endUnixTime = time.Now().Unix()
+	}
+
+	// the dates of transactions may be one day earlier or later than the dates in utc when the transaction timezone is used
+	startDate := ""
+
+	if startUnixTime > 0 {
+		startDate = utils.FormatUnixTimeToLongDate(startUnixTime-24*3600, time.UTC)
+	}
+
+	endDate := utils.FormatUnixTimeToLongDate(endUnixTime+24*3600, time.UTC)
+	rates, err := a.historicalExchangeRates.GetExchangeRatesForConversion(c, dataSource, startDate, endDate, currencies)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewTransactionAmountsConverter(user.DefaultCurrency, accounts, models.NewHistoricalExchangeRatesConverter(rates)), nil
+}
+
+// getPayeeNameMapByNames returns the payees matched by the specified names or aliases, and the payees which are not
+// matched are created if current user can create payees in the fund
+func (a *TransactionsApi) getPayeeNameMapByNames(c *core.WebContext, uid int64, fundId int64, payeeNames []string) (map[string]*models.Payee, error) {
+	if len(payeeNames) < 1 {
+		return make(map[string]*models.Payee), nil
+	}
+
+	if _, err := a.funds.GetUserPermissionInFund(c, uid, fundId, models.FUND_RESOURCE_PAYEE, models.FUND_ACTION_CREATE); err == nil {
+		return a.payees.GetOrCreatePayeesByNames(c, uid, fundId, payeeNames)
+	}
+
+	payees, err := a.payees.GetAllPayeesByUid(c, uid, fundId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.payees.GetPayeeNameMapByList(payees), nil
+}
+
 func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
@@ -1939,9 +3465,11 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionCreateReq.Time),
 		TimezoneUtcOffset: transactionCreateReq.UtcOffset,
 		AccountId:         transactionCreateReq.SourceAccountId,
+		PayeeId:           transactionCreateReq.PayeeId,
 		Amount:            transactionCreateReq.SourceAmount,
 		HideAmount:        transactionCreateReq.HideAmount,
 		Comment:           transactionCreateReq.Comment,
+		ExternalId:        transactionCreateReq.ExternalId,
 		CreatedIp:         clientIp,
 	}
 
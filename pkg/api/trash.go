@@ -0,0 +1,158 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+var allTrashItemTypes = []models.TrashItemType{models.TRASH_ITEM_TYPE_TRANSACTION, models.TRASH_ITEM_TYPE_ACCOUNT, models.TRASH_ITEM_TYPE_CATEGORY}
+
+// TrashApi represents trash bin api
+type TrashApi struct {
+	trash        *services.TrashService
+	transactions *services.TransactionService
+	funds        *services.FundService
+}
+
+// Initialize a trash bin api singleton instance
+var (
+	Trash = &TrashApi{
+		trash:        services.Trash,
+		transactions: services.Transactions,
+		funds:        services.Funds,
+	}
+)
+
+// TrashListHandler returns the deleted items of current fund which are visible to current user
+func (a *TrashApi) TrashListHandler(c *core.WebContext) (any, *errs.Error) {
+	var trashListReq models.TrashListRequest
+	err := c.ShouldBindQuery(&trashListReq)
+
+	if err != nil {
+		log.Warnf(c, "[trash.TrashListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, errFund := GetFundIdFromContext(c, uid)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	trashItemResps := make(models.TrashItemInfoResponseSlice, 0, trashListReq.Count)
+
+	for _, itemType := range allTrashItemTypes {
+		if trashListReq.Type != 0 && trashListReq.Type != itemType {
+			continue
+		}
+
+		permission, err := a.funds.GetUserPermissionInFund(c, uid, fundId, itemType.ToFundResource(), models.FUND_ACTION_READ)
+
+		if err != nil || !permission.IsAllowed() {
+			if trashListReq.Type != 0 {
+				log.Warnf(c, "[trash.TrashListHandler] user \"uid:%d\" cannot read deleted items of type \"%s\" in fund \"id:%d\"", uid, itemType, fundId)
+				return nil, errs.ErrFundPermissionDenied
+			}
+
+			continue
+		}
+
+		switch itemType {
+		case models.TRASH_ITEM_TYPE_TRANSACTION:
+			creatorUid := int64(0)
+
+			if permission != models.FUND_PERMISSION_ALL {
+				creatorUid = uid
+			}
+
+			transactions, err := a.trash.GetDeletedTransactions(c, uid, fundId, creatorUid, trashListReq.MaxTime, trashListReq.Count)
+
+			if err != nil {
+				log.Errorf(c, "[trash.TrashListHandler] failed to get deleted transactions of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			for i := 0; i < len(transactions); i++ {
+				trashItemResps = append(trashItemResps, transactions[i].ToTrashItemInfoResponse())
+			}
+		case models.TRASH_ITEM_TYPE_ACCOUNT:
+			accounts, err := a.trash.GetDeletedAccounts(c, uid, fundId, trashListReq.MaxTime, trashListReq.Count)
+
+			if err != nil {
+				log.Errorf(c, "[trash.TrashListHandler] failed to get deleted accounts of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			for i := 0; i < len(accounts); i++ {
+				trashItemResps = append(trashItemResps, accounts[i].ToTrashItemInfoResponse())
+			}
+		case models.TRASH_ITEM_TYPE_CATEGORY:
+			categories, err := a.trash.GetDeletedCategories(c, uid, fundId, trashListReq.MaxTime, trashListReq.Count)
+
+			if err != nil {
+				log.Errorf(c, "[trash.TrashListHandler] failed to get deleted categories of fund \"id:%d\" for user \"uid:%d\", because %s", fundId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			for i := 0; i < len(categories); i++ {
+				trashItemResps = append(trashItemResps, categories[i].ToTrashItemInfoResponse())
+			}
+		}
+	}
+
+	sort.Sort(trashItemResps)
+
+	if len(trashItemResps) > int(trashListReq.Count) {
+		trashItemResps = trashItemResps[:trashListReq.Count]
+	}
+
+	return trashItemResps, nil
+}
+
+// TrashRestoreHandler restores a deleted item of current fund
+func (a *TrashApi) TrashRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var trashRestoreReq models.TrashRestoreRequest
+	err := c.ShouldBindJSON(&trashRestoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[trash.TrashRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	fundId, permission, errFund := GetFundIdFromContextWithPermission(c, uid, trashRestoreReq.Type.ToFundResource(), models.FUND_ACTION_DELETE)
+	if errFund != nil {
+		return nil, errFund
+	}
+
+	switch trashRestoreReq.Type {
+	case models.TRASH_ITEM_TYPE_TRANSACTION:
+		err = a.transactions.RestoreTransaction(c, uid, fundId, trashRestoreReq.Id, func(transaction *models.Transaction) error {
+			if !permission.IsAllowedOn(uid, transaction.Uid) {
+				log.Warnf(c, "[trash.TrashRestoreHandler] user \"uid:%d\" cannot restore transaction \"id:%d\" created by other user in fund \"id:%d\"", uid, transaction.TransactionId, fundId)
+				return errs.ErrFundPermissionDenied
+			}
+
+			return nil
+		})
+	case models.TRASH_ITEM_TYPE_ACCOUNT:
+		err = a.trash.RestoreAccount(c, uid, fundId, trashRestoreReq.Id)
+	case models.TRASH_ITEM_TYPE_CATEGORY:
+		err = a.trash.RestoreCategory(c, uid, fundId, trashRestoreReq.Id)
+	default:
+		return nil, errs.ErrTrashItemTypeInvalid
+	}
+
+	if err != nil {
+		log.Errorf(c, "[trash.TrashRestoreHandler] failed to restore %s \"id:%d\" of fund \"id:%d\" for user \"uid:%d\", because %s", trashRestoreReq.Type, trashRestoreReq.Id, fundId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[trash.TrashRestoreHandler] user \"uid:%d\" has restored %s \"id:%d\" successfully", uid, trashRestoreReq.Type, trashRestoreReq.Id)
+	return true, nil
+}
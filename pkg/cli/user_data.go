@@ -25,6 +25,7 @@ type UserDataCli struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	transactionSplits       *services.TransactionSplitService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
@@ -41,6 +42,7 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		transactionSplits:       services.TransactionSplits,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
@@ -775,13 +777,20 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, err
 	}
 
+	allSplits, err := l.transactionSplits.GetSplitsByTransactionIds(c, uid, l.transactions.GetTransactionIds(allTransactions))
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction split line items for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
 		return nil, errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap, allSplits)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
@@ -867,7 +876,23 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 		return errs.ErrOperationFailed
 	}
 
-	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil)
+	newTransactionSplitsMap, err := parsedTransactions.ToTransactionSplitsMap()
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ImportTransaction] failed to get transaction split line items map, because %s", err.Error())
+		return errs.ErrOperationFailed
+	}
+
+	for index, splits := range newTransactionSplitsMap {
+		err = models.ValidateTransactionSplits(newTransactions[index].Type, newTransactions[index].Amount, splits)
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.ImportTransaction] split line items of transaction \"index:%d\" are invalid, because %s", index, err.Error())
+			return err
+		}
+	}
+
+	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newTransactionSplitsMap, true, nil)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ImportTransaction] failed to create transaction, because %s", err.Error())
@@ -528,6 +528,37 @@ func TestAlipayCsvFileImporterParseImportedData_ParseRelatedAccount(t *testing.T
 	assert.Equal(t, "CNY", allNewAccounts[5].Currency)
 }
 
+func TestAlipayCsvFileImporterParseImportedData_ParsePayee(t *testing.T) {
+	converter := AlipayAppTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data1, err := simplifiedchinese.GB18030.NewEncoder().String("------------------------------------------------------------------------------------\n" +
+		"导出信息：\n" +
+		"姓名：xxx\n" +
+		"支付宝账户：xxx@xxx.xxx\n" +
+		"起始时间：[2024-01-01 00:00:00]    终止时间：[2024-09-01 23:59:59]\n" +
+		"导出交易类型：[全部]\n" +
+		"------------------------支付宝（中国）网络技术有限公司  电子客户回单------------------------\n" +
+		"交易时间,交易对方,商品说明,收/支,金额,收/付款方式,交易状态,\n" +
+		"2024-09-01 01:23:45, Test Payer ,xxxx,收入,0.12,Test Account,交易成功,\n" +
+		"2024-09-01 12:34:56,Test Shop,xxxx,支出,123.45,Test Account,交易成功,\n" +
+		"2024-09-01 23:59:59,xxx,充值-普通充值,不计收支,0.05,Test Account,交易成功,\n")
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(data1), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, "Test Payer", allNewTransactions[0].OriginalPayeeName)
+	assert.Equal(t, "Test Shop", allNewTransactions[1].OriginalPayeeName)
+	assert.Equal(t, "", allNewTransactions[2].OriginalPayeeName)
+}
+
 func TestAlipayCsvFileImporterParseImportedData_ParseDescription(t *testing.T) {
 	converter := AlipayWebTransactionDataCsvFileImporter
 	context := core.NewNullContext()
@@ -84,6 +84,12 @@ func (p *alipayTransactionDataRowParser) Parse(ctx core.Context, user *models.Us
 		relatedAccountName = dataRow.GetData(p.columns.relatedAccountColumnName)
 	}
 
+	targetName := ""
+
+	if p.hasOriginalColumn(p.columns.targetNameColumnName) {
+		targetName = dataRow.GetData(p.columns.targetNameColumnName)
+	}
+
 	statusName := ""
 
 	if p.hasOriginalColumn(p.columns.statusColumnName) {
@@ -114,19 +120,16 @@ func (p *alipayTransactionDataRowParser) Parse(ctx core.Context, user *models.Us
 				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = ""
 				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ""
 			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = targetName
 		} else if dataRow.GetData(p.columns.typeColumnName) == alipayTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER] {
 			if statusName == alipayTransactionDataStatusClosedName {
 				log.Warnf(ctx, "[alipay_transaction_data_row_parser.Parse] skip parsing transaction in row \"%s\", because non-income/expense transaction is closed", rowId)
 				return nil, false, nil
 			}
 
-			targetName := ""
 			productName := ""
 
-			if p.hasOriginalColumn(p.columns.targetNameColumnName) {
-				targetName = dataRow.GetData(p.columns.targetNameColumnName)
-			}
-
 			if p.hasOriginalColumn(p.columns.productNameColumnName) {
 				productName = dataRow.GetData(p.columns.productNameColumnName)
 			}
@@ -180,6 +183,7 @@ func (p *alipayTransactionDataRowParser) Parse(ctx core.Context, user *models.Us
 		} else {
 			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedAccountName
 			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ""
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = targetName
 		}
 	}
 
@@ -1,6 +1,9 @@
 package camt
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+)
 
 type camtCreditDebitIndicator string
 
@@ -30,6 +33,8 @@ type camtAccount struct {
 }
 
 type camtEntry struct {
+	EntryReference             string                   `xml:"NtryRef"`
+	AccountServicerReference   string                   `xml:"AcctSvcrRef"`
 	Amount                     *camtAmount              `xml:"Amt"`
 	CreditDebitIndicator       camtCreditDebitIndicator `xml:"CdtDbtInd"`
 	BookingDate                *camtDate                `xml:"BookgDt"`
@@ -52,11 +57,28 @@ type camtEntryDetails struct {
 }
 
 type camtTransactionDetails struct {
+	References                       *camtTransactionReferences `xml:"Refs"`
 	AmountDetails                    *camtAmountDetails         `xml:"AmtDtls"`
+	RelatedParties                   *camtRelatedParties        `xml:"RltdPties"`
 	RemittanceInformation            *camtRemittanceInformation `xml:"RmtInf"`
 	AdditionalTransactionInformation string                     `xml:"AddtlTxInf"`
 }
 
+type camtTransactionReferences struct {
+	AccountServicerReference string `xml:"AcctSvcrRef"`
+	TransactionId            string `xml:"TxId"`
+}
+
+type camtRelatedParties struct {
+	Debtor   *camtParty `xml:"Dbtr"`
+	Creditor *camtParty `xml:"Cdtr"`
+}
+
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
 type camtAmountDetails struct {
 	InstructedAmount  *camtAmount `xml:"InstdAmt>Amt"`
 	TransactionAmount *camtAmount `xml:"TxAmt>Amt"`
@@ -65,3 +87,38 @@ type camtAmountDetails struct {
 type camtRemittanceInformation struct {
 	Unstructured []string `xml:"Ustrd"`
 }
+
+// GetTransactionReference returns the reference assigned by the bank which uniquely identifies the transaction details,
+// or the reference of the entry if the entry has no more than one transaction details
+func (e *camtEntry) GetTransactionReference(transactionDetails *camtTransactionDetails) string {
+	if transactionDetails != nil && transactionDetails.References != nil {
+		if transactionDetails.References.AccountServicerReference != "" {
+			return strings.TrimSpace(transactionDetails.References.AccountServicerReference)
+		} else if transactionDetails.References.TransactionId != "" {
+			return strings.TrimSpace(transactionDetails.References.TransactionId)
+		}
+	}
+
+	if e.EntryDetails != nil && len(e.EntryDetails.TransactionDetails) > 1 {
+		return ""
+	}
+
+	if e.AccountServicerReference != "" {
+		return strings.TrimSpace(e.AccountServicerReference)
+	}
+
+	return strings.TrimSpace(e.EntryReference)
+}
+
+// GetName returns the name of the party
+func (p *camtParty) GetName() string {
+	if p == nil {
+		return ""
+	}
+
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.PartyName
+}
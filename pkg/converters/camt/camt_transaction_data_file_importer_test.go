@@ -641,6 +641,154 @@ func TestCamt053TransactionDataFileParseImportedData_ParseDescription(t *testing
 	assert.Equal(t, "Test Entry", allNewTransactions[0].Comment)
 }
 
+func TestCamt053TransactionDataFileParseImportedData_ParsePayee(t *testing.T) {
+	converter := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Dbtr>
+										<Nm>Test Debtor</Nm>
+									</Dbtr>
+									<Cdtr>
+										<Nm>Test Creditor</Nm>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:57+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">12.34</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Cdtr>
+										<Pty>
+											<Nm>Test Shop</Nm>
+										</Pty>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:58+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">1.23</Amt>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, "Test Debtor", allNewTransactions[0].OriginalPayeeName)
+	assert.Equal(t, "Test Shop", allNewTransactions[1].OriginalPayeeName)
+	assert.Equal(t, "", allNewTransactions[2].OriginalPayeeName)
+}
+
+func TestCamt053TransactionDataFileParseImportedData_ParseExternalId(t *testing.T) {
+	converter := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<NtryRef>ENTRY001</NtryRef>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<AcctSvcrRef>SERVICER001</AcctSvcrRef>
+						<NtryDtls>
+							<TxDtls>
+								<Refs>
+									<AcctSvcrRef>DETAIL001</AcctSvcrRef>
+									<TxId>TX001</TxId>
+								</Refs>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+					<Ntry>
+						<NtryRef>ENTRY002</NtryRef>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:57+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">12.34</Amt>
+						<AcctSvcrRef>SERVICER002</AcctSvcrRef>
+					</Ntry>
+					<Ntry>
+						<NtryRef>ENTRY003</NtryRef>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:58+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">1.23</Amt>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:59+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">0.12</Amt>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, "DETAIL001", allNewTransactions[0].ExternalId)
+	assert.Equal(t, "SERVICER002", allNewTransactions[1].ExternalId)
+	assert.Equal(t, "ENTRY003", allNewTransactions[2].ExternalId)
+	assert.Equal(t, "", allNewTransactions[3].ExternalId)
+}
+
 func TestCamt053TransactionDataFileParseImportedData_MissingAccountNode(t *testing.T) {
 	converter := Camt053TransactionDataImporter
 	context := core.NewNullContext()
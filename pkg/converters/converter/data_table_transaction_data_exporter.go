@@ -18,8 +18,9 @@ type DataTableTransactionDataExporter struct {
 	transactionTagSeparator string
 }
 
-// BuildExportedContent writes the exported transaction data to the data table builder
-func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) error {
+// BuildExportedContent writes the exported transaction data to the data table builder, the line items of split transaction
+// are written as the rows following the parent transaction
+func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64][]*models.TransactionSplit) error {
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 
@@ -48,8 +49,33 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION] = c.getExportedGeographicLocation(transaction)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, transaction.TransactionId, allTagIndexes, tagMap)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(transaction.Comment)
+		dataRowMap[datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE] = ""
 
 		dataTableBuilder.AppendTransaction(dataRowMap)
+
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		splits := allSplits[transaction.TransactionId]
+
+		for j := 0; j < len(splits); j++ {
+			split := splits[j]
+			splitDataRowMap := make(map[datatable.TransactionDataTableColumn]string, 15)
+
+			for column, value := range dataRowMap {
+				splitDataRowMap[column] = value
+			}
+
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = c.getExportedTransactionCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = c.getExportedTransactionSubCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(split.Amount)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTagNames(dataTableBuilder, split.GetTagIds(), tagMap)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(split.Comment)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE] = utils.IntToString(j + 1)
+
+			dataTableBuilder.AppendTransaction(splitDataRowMap)
+		}
 	}
 
 	return nil
@@ -136,6 +162,10 @@ func (c *DataTableTransactionDataExporter) getExportedTags(dataTableBuilder data
 		return ""
 	}
 
+	return c.getExportedTagNames(dataTableBuilder, tagIndexes, tagMap)
+}
+
+func (c *DataTableTransactionDataExporter) getExportedTagNames(dataTableBuilder datatable.TransactionDataTableBuilder, tagIndexes []int64, tagMap map[int64]*models.TransactionTag) string {
 	var ret strings.Builder
 
 	for i := 0; i < len(tagIndexes); i++ {
@@ -342,6 +342,45 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			description = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_DESCRIPTION)
 		}
 
+		payeeName := ""
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_PAYEE) {
+			payeeName = strings.TrimSpace(dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE))
+		}
+
+		externalId := ""
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID) {
+			externalId = strings.TrimSpace(dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID))
+		}
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE) && dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE) != "" {
+			if len(allNewTransactions) < 1 {
+				log.Errorf(ctx, "[data_table_transaction_data_importer.ParseImportedData] split line item in data row \"index:%d\" does not follow any transaction for user \"uid:%d\"", dataRowIndex, user.Uid)
+				return nil, nil, nil, nil, nil, nil, errs.ErrSplitLineWithoutParentTransaction
+			}
+
+			parentTransaction := allNewTransactions[len(allNewTransactions)-1]
+
+			if (transactionDbType != models.TRANSACTION_DB_TYPE_INCOME && transactionDbType != models.TRANSACTION_DB_TYPE_EXPENSE) ||
+				parentTransaction.Type != transactionDbType ||
+				parentTransaction.OriginalSourceAccountName != accountName {
+				log.Errorf(ctx, "[data_table_transaction_data_importer.ParseImportedData] split line item in data row \"index:%d\" does not match the previous transaction for user \"uid:%d\"", dataRowIndex, user.Uid)
+				return nil, nil, nil, nil, nil, nil, errs.ErrSplitLineWithoutParentTransaction
+			}
+
+			parentTransaction.Splits = append(parentTransaction.Splits, &models.ImportTransactionSplit{
+				CategoryId:           categoryId,
+				Amount:               amount,
+				TagIds:               tagIds,
+				Comment:              description,
+				OriginalCategoryName: subCategoryName,
+				OriginalTagNames:     tagNames,
+			})
+
+			continue
+		}
+
 		transaction := &models.ImportTransaction{
 			Transaction: &models.Transaction{
 				Uid:                  user.Uid,
@@ -355,6 +394,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 				RelatedAccountId:     relatedAccountId,
 				RelatedAccountAmount: relatedAccountAmount,
 				Comment:              description,
+				ExternalId:           externalId,
 				GeoLongitude:         geoLongitude,
 				GeoLatitude:          geoLatitude,
 				CreatedIp:            "127.0.0.1",
@@ -366,6 +406,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			OriginalDestinationAccountName:     account2Name,
 			OriginalDestinationAccountCurrency: account2Currency,
 			OriginalTagNames:                   tagNames,
+			OriginalPayeeName:                  payeeName,
 		}
 
 		allNewTransactions = append(allNewTransactions, transaction)
@@ -72,6 +72,9 @@ const (
 	TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION      TransactionDataTableColumn = 12
 	TRANSACTION_DATA_TABLE_TAGS                     TransactionDataTableColumn = 13
 	TRANSACTION_DATA_TABLE_DESCRIPTION              TransactionDataTableColumn = 14
+	TRANSACTION_DATA_TABLE_SPLIT_LINE               TransactionDataTableColumn = 15 // Line number of a split line item, empty for the parent transaction
+	TRANSACTION_DATA_TABLE_PAYEE                    TransactionDataTableColumn = 16 // Name of the counterparty of the transaction
+	TRANSACTION_DATA_TABLE_EXTERNAL_ID              TransactionDataTableColumn = 17 // Unique identifier of the transaction assigned by the bank
 )
 
 // TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE represents the constant for timezone not available
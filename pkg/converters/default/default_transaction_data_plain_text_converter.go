@@ -32,6 +32,7 @@ var ezbookkeepingDataColumnNameMapping = map[datatable.TransactionDataTableColum
 	datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION:      "Geographic Location",
 	datatable.TRANSACTION_DATA_TABLE_TAGS:                     "Tags",
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              "Description",
+	datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE:               "Split Line",
 }
 
 var ezbookkeepingTransactionTypeNameMapping = map[models.TransactionType]string{
@@ -58,11 +59,19 @@ var ezbookkeepingDataColumns = []datatable.TransactionDataTableColumn{
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
 }
 
+var ezbookkeepingDataColumnsWithSplitLine = append(append([]datatable.TransactionDataTableColumn{}, ezbookkeepingDataColumns...), datatable.TRANSACTION_DATA_TABLE_SPLIT_LINE)
+
 // ToExportedContent returns the exported transaction plain text data
-func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	dataColumns := ezbookkeepingDataColumns
+
+	if c.hasSplitTransactions(transactions, allSplits) {
+		dataColumns = ezbookkeepingDataColumnsWithSplitLine
+	}
+
 	dataTableBuilder := createNewDefaultTransactionPlainTextDataTableBuilder(
 		len(transactions),
-		ezbookkeepingDataColumns,
+		dataColumns,
 		ezbookkeepingDataColumnNameMapping,
 		c.columnSeparator,
 		ezbookkeepingLineSeparator,
@@ -74,7 +83,7 @@ func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Co
 		ezbookkeepingTagSeparator,
 	)
 
-	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
 
 	if err != nil {
 		return nil, err
@@ -106,3 +115,17 @@ func (c *defaultTransactionDataPlainTextConverter) ParseImportedData(ctx core.Co
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
+
+func (c *defaultTransactionDataPlainTextConverter) hasSplitTransactions(transactions []*models.Transaction, allSplits map[int64][]*models.TransactionSplit) bool {
+	if len(allSplits) < 1 {
+		return false
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		if len(allSplits[transactions[i].TransactionId]) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -119,7 +119,71 @@ func TestDefaultTransactionDataCSVFileConverterToExportedContent(t *testing.T) {
 		"2024-09-01 12:34:56,+08:00,Income,Test Category,Test Sub Category,Test Account,CNY,123.45,,,,123.450000 45.670000,Test Tag;Test Tag2,Hello World\n" +
 		"2024-09-01 12:34:56,+00:00,Expense,Test Category2,Test Sub Category2,Test Account,CNY,-0.10,,,,,Test Tag,Foo#Bar\n" +
 		"2024-09-01 12:34:56,-05:00,Transfer,Test Category3,Test Sub Category3,Test Account,CNY,123.45,Test Account2,USD,17.35,,Test Tag2,T\te s t test\n"
-	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestDefaultTransactionDataCSVFileConverterToExportedContent_SplitTransaction(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 1)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            10000,
+		Comment:           "Supermarket",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Daily",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Groceries",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId:       3,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Household",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 1)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+
+	allSplits := make(map[int64][]*models.TransactionSplit, 1)
+	allSplits[1] = []*models.TransactionSplit{
+		{TransactionId: 1, LineIndex: 0, CategoryId: 2, Amount: 6000, Comment: "Food"},
+		{TransactionId: 1, LineIndex: 1, CategoryId: 3, Amount: 4000, TagIds: "1", Comment: "Soap"},
+	}
+
+	expectedContent := "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description,Split Line\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Daily,Groceries,Test Account,CNY,100.00,,,,,,Supermarket,\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Daily,Groceries,Test Account,CNY,60.00,,,,,,Food,1\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Daily,Household,Test Account,CNY,40.00,,,,,Test Tag,Soap,2\n"
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, nil, allSplits)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedContent, string(actualContent))
@@ -466,6 +530,66 @@ func TestDefaultTransactionDataCSVFileConverterParseImportedData_ParseDescriptio
 	assert.Equal(t, "foo    bar\t#test", allNewTransactions[0].Comment)
 }
 
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_ParseSplitLines(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, allNewTags, err := converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Tags,Description,Split Line\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account,100.00,,,,Supermarket,\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account,60.00,,,,Food,1\n"+
+		"2024-09-01 12:34:56,Expense,Household,Test Account,40.00,,,Test Tag,Soap,2\n"+
+		"2024-09-01 23:59:59,Expense,Household,Test Account,5.00,,,,,"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, int64(10000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Supermarket", allNewTransactions[0].Comment)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, "Groceries", allNewTransactions[0].Splits[0].OriginalCategoryName)
+	assert.Equal(t, int64(6000), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Food", allNewTransactions[0].Splits[0].Comment)
+	assert.Equal(t, "Household", allNewTransactions[0].Splits[1].OriginalCategoryName)
+	assert.Equal(t, int64(4000), allNewTransactions[0].Splits[1].Amount)
+	assert.Equal(t, []string{"Test Tag"}, allNewTransactions[0].Splits[1].OriginalTagNames)
+	assert.Equal(t, "Soap", allNewTransactions[0].Splits[1].Comment)
+
+	assert.Equal(t, int64(500), allNewTransactions[1].Amount)
+	assert.Equal(t, 0, len(allNewTransactions[1].Splits))
+}
+
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_ParseSplitLineWithoutParent(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Split Line\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account,60.00,,,1"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrSplitLineWithoutParentTransaction.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Split Line\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account,100.00,,,\n"+
+		"2024-09-01 12:34:56,Income,Salary,Test Account,60.00,,,1"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrSplitLineWithoutParentTransaction.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Split Line\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account,100.00,,,\n"+
+		"2024-09-01 12:34:56,Expense,Groceries,Test Account2,60.00,,,1"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrSplitLineWithoutParentTransaction.Message)
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_MissingFileHeader(t *testing.T) {
 	converter := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
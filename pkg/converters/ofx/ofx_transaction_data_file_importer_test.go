@@ -546,6 +546,7 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "foo    bar\t#test", allNewTransactions[0].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
 
 	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
 		"<OFX>\n"+
@@ -600,6 +601,49 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "Test", allNewTransactions[0].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
+}
+
+func TestOFXTransactionDataFileParseImportedData_ParseExternalId(t *testing.T) {
+	converter := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"<OFX>\n"+
+			"  <BANKMSGSRSV1>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>CNY</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>123</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"        <BANKTRANLIST>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEP</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>123.45</TRNAMT>\n"+
+			"            <FITID> 20240901001 </FITID>\n"+
+			"          </STMTTRN>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEBIT</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901123456.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>-0.12</TRNAMT>\n"+
+			"          </STMTTRN>\n"+
+			"        </BANKTRANLIST>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"  </BANKMSGSRSV1>\n"+
+			"</OFX>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, "20240901001", allNewTransactions[0].ExternalId)
+	assert.Equal(t, "", allNewTransactions[1].ExternalId)
 }
 
 func TestOFXTransactionDataFileParseImportedData_MissingAccountFromNode(t *testing.T) {
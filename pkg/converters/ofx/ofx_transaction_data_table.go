@@ -24,6 +24,8 @@ var ofxTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bo
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+	datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID:              true,
 }
 
 // ofxTransactionData defines the structure of open financial exchange (ofx) transaction data
@@ -220,6 +222,18 @@ func (t *ofxTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] == ofxTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER] {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	} else if ofxTransaction.Name != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ofxTransaction.Name
+	} else if ofxTransaction.Payee != nil {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ofxTransaction.Payee.Name
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID] = strings.TrimSpace(ofxTransaction.TransactionId)
+
 	return data, nil
 }
 
@@ -17,6 +17,7 @@ const wechatPayTransactionDataHeaderStartContentBeginning = "-------------------
 
 const wechatPayTransactionTimeColumnName = "交易时间"
 const wechatPayTransactionCategoryColumnName = "交易类型"
+const wechatPayTransactionTargetNameColumnName = "交易对方"
 const wechatPayTransactionProductNameColumnName = "商品"
 const wechatPayTransactionTypeColumnName = "收/支"
 const wechatPayTransactionAmountColumnName = "金额(元)"
@@ -38,6 +39,7 @@ var wechatPayTransactionSupportedColumns = map[datatable.TransactionDataTableCol
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 var wechatPayTransactionTypeNameMapping = map[models.TransactionType]string{
@@ -89,6 +91,13 @@ func (p *weChatPayTransactionDataRowParser) Parse(ctx core.Context, user *models
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if p.hasOriginalColumn(wechatPayTransactionTargetNameColumnName) && dataRow.GetData(wechatPayTransactionTargetNameColumnName) != "/" &&
+		dataRow.GetData(wechatPayTransactionTypeColumnName) != wechatPayTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER] {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = dataRow.GetData(wechatPayTransactionTargetNameColumnName)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	relatedAccountName := ""
 
 	if p.hasOriginalColumn(wechatPayTransactionRelatedAccountColumnName) {
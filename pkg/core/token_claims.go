@@ -18,6 +18,7 @@ const (
 	USER_TOKEN_TYPE_MCP                            TokenType = 5
 	USER_TOKEN_TYPE_OAUTH2_CALLBACK_REQUIRE_VERIFY TokenType = 6
 	USER_TOKEN_TYPE_OAUTH2_CALLBACK                TokenType = 7
+	USER_TOKEN_TYPE_FUND_INVITATION                TokenType = 8
 )
 
 // UserTokenClaims represents user token
@@ -84,6 +84,18 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 	if config.EnableCreateScheduledTransaction {
 		Container.registerIntervalJob(ctx, CreateScheduledTransactionJob)
 	}
+
+	if config.EnablePurgeExpiredTrash {
+		Container.registerIntervalJob(ctx, PurgeExpiredTrashJob)
+	}
+
+	if config.EnableSendCreditCardDueReminders {
+		Container.registerIntervalJob(ctx, SendCreditCardDueRemindersJob)
+	}
+
+	if config.EnableStoreExchangeRatesHistory && config.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource {
+		Container.registerIntervalJob(ctx, StoreExchangeRatesHistoryJob)
+	}
 }
 
 func (c *CronJobSchedulerContainer) registerIntervalJob(ctx core.Context, job *CronJob) {
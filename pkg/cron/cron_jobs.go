@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -30,3 +32,40 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// PurgeExpiredTrashJob represents the cron job which periodically purge the deleted data older than the retention period from the database
+var PurgeExpiredTrashJob = &CronJob{
+	Name:        "PurgeExpiredTrash",
+	Description: "Periodically purge the deleted data older than the retention period from the database.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		retentionDays := settings.Container.GetCurrentConfig().TrashRetentionDays
+		return services.Trash.PurgeExpiredItems(c, time.Now().Unix()-int64(retentionDays)*24*3600)
+	},
+}
+
+// SendCreditCardDueRemindersJob represents the cron job which periodically send reminder emails of unpaid credit card statements before the due date
+var SendCreditCardDueRemindersJob = &CronJob{
+	Name:        "SendCreditCardDueReminders",
+	Description: "Periodically send reminder emails of unpaid credit card statements before the due date.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 9,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.CreditCardStatements.SendDueReminders(c, time.Now().Unix())
+	},
+}
+
+// StoreExchangeRatesHistoryJob represents the cron job which periodically store the daily exchange rates of the current exchange rates data source
+var StoreExchangeRatesHistoryJob = &CronJob{
+	Name:        "StoreExchangeRatesHistory",
+	Description: "Periodically store the daily exchange rates of the current exchange rates data source.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 23,
+	},
+	Run: func(c *core.CronContext) error {
+		return exchangerates.Container.StoreExchangeRatesHistory(c, settings.Container.GetCurrentConfig(), time.Now())
+	},
+}
@@ -4,26 +4,44 @@ import "net/http"
 
 // Error codes related to accounts
 var (
-	ErrAccountIdInvalid                       = NewNormalError(NormalSubcategoryAccount, 0, http.StatusBadRequest, "account id is invalid")
-	ErrAccountNotFound                        = NewNormalError(NormalSubcategoryAccount, 1, http.StatusBadRequest, "account not found")
-	ErrAccountTypeInvalid                     = NewNormalError(NormalSubcategoryAccount, 2, http.StatusBadRequest, "account type is invalid")
-	ErrAccountCurrencyInvalid                 = NewNormalError(NormalSubcategoryAccount, 3, http.StatusBadRequest, "account currency is invalid")
-	ErrAccountHaveNoSubAccount                = NewNormalError(NormalSubcategoryAccount, 4, http.StatusBadRequest, "account must have at least one sub-account")
-	ErrAccountCannotHaveSubAccounts           = NewNormalError(NormalSubcategoryAccount, 5, http.StatusBadRequest, "account cannot have sub-accounts")
-	ErrParentAccountCannotSetCurrency         = NewNormalError(NormalSubcategoryAccount, 6, http.StatusBadRequest, "parent account cannot set currency")
-	ErrParentAccountCannotSetBalance          = NewNormalError(NormalSubcategoryAccount, 7, http.StatusBadRequest, "parent account cannot set balance")
-	ErrSubAccountCategoryNotEqualsToParent    = NewNormalError(NormalSubcategoryAccount, 8, http.StatusBadRequest, "sub-account category not equals to parent")
-	ErrSubAccountTypeInvalid                  = NewNormalError(NormalSubcategoryAccount, 9, http.StatusBadRequest, "sub-account type invalid")
-	ErrSourceAccountNotFound                  = NewNormalError(NormalSubcategoryAccount, 11, http.StatusBadRequest, "source account not found")
-	ErrDestinationAccountNotFound             = NewNormalError(NormalSubcategoryAccount, 12, http.StatusBadRequest, "destination account not found")
-	ErrAccountInUseCannotBeDeleted            = NewNormalError(NormalSubcategoryAccount, 13, http.StatusBadRequest, "account is in use and cannot be deleted")
-	ErrAccountCategoryInvalid                 = NewNormalError(NormalSubcategoryAccount, 14, http.StatusBadRequest, "account category is invalid")
-	ErrAccountBalanceTimeNotSet               = NewNormalError(NormalSubcategoryAccount, 15, http.StatusBadRequest, "account balance time is not set")
-	ErrCannotSetStatementDateForNonCreditCard = NewNormalError(NormalSubcategoryAccount, 16, http.StatusBadRequest, "cannot set statement date for non credit card account")
-	ErrCannotSetStatementDateForSubAccount    = NewNormalError(NormalSubcategoryAccount, 17, http.StatusBadRequest, "cannot set statement date for sub account")
-	ErrSubAccountNotFound                     = NewNormalError(NormalSubcategoryAccount, 18, http.StatusBadRequest, "sub-account not found")
-	ErrSubAccountInUseCannotBeDeleted         = NewNormalError(NormalSubcategoryAccount, 19, http.StatusBadRequest, "sub-account is in use and cannot be deleted")
-	ErrNotSupportedChangeCurrency             = NewNormalError(NormalSubcategoryAccount, 20, http.StatusBadRequest, "not supported to modify account currency")
-	ErrNotSupportedChangeBalance              = NewNormalError(NormalSubcategoryAccount, 21, http.StatusBadRequest, "not supported to modify account balance")
-	ErrNotSupportedChangeBalanceTime          = NewNormalError(NormalSubcategoryAccount, 22, http.StatusBadRequest, "not supported to modify account balance time")
+	ErrAccountIdInvalid                         = NewNormalError(NormalSubcategoryAccount, 0, http.StatusBadRequest, "account id is invalid")
+	ErrAccountNotFound                          = NewNormalError(NormalSubcategoryAccount, 1, http.StatusBadRequest, "account not found")
+	ErrAccountTypeInvalid                       = NewNormalError(NormalSubcategoryAccount, 2, http.StatusBadRequest, "account type is invalid")
+	ErrAccountCurrencyInvalid                   = NewNormalError(NormalSubcategoryAccount, 3, http.StatusBadRequest, "account currency is invalid")
+	ErrAccountHaveNoSubAccount                  = NewNormalError(NormalSubcategoryAccount, 4, http.StatusBadRequest, "account must have at least one sub-account")
+	ErrAccountCannotHaveSubAccounts             = NewNormalError(NormalSubcategoryAccount, 5, http.StatusBadRequest, "account cannot have sub-accounts")
+	ErrParentAccountCannotSetCurrency           = NewNormalError(NormalSubcategoryAccount, 6, http.StatusBadRequest, "parent account cannot set currency")
+	ErrParentAccountCannotSetBalance            = NewNormalError(NormalSubcategoryAccount, 7, http.StatusBadRequest, "parent account cannot set balance")
+	ErrSubAccountCategoryNotEqualsToParent      = NewNormalError(NormalSubcategoryAccount, 8, http.StatusBadRequest, "sub-account category not equals to parent")
+	ErrSubAccountTypeInvalid                    = NewNormalError(NormalSubcategoryAccount, 9, http.StatusBadRequest, "sub-account type invalid")
+	ErrSourceAccountNotFound                    = NewNormalError(NormalSubcategoryAccount, 11, http.StatusBadRequest, "source account not found")
+	ErrDestinationAccountNotFound               = NewNormalError(NormalSubcategoryAccount, 12, http.StatusBadRequest, "destination account not found")
+	ErrAccountInUseCannotBeDeleted              = NewNormalError(NormalSubcategoryAccount, 13, http.StatusBadRequest, "account is in use and cannot be deleted")
+	ErrAccountCategoryInvalid                   = NewNormalError(NormalSubcategoryAccount, 14, http.StatusBadRequest, "account category is invalid")
+	ErrAccountBalanceTimeNotSet                 = NewNormalError(NormalSubcategoryAccount, 15, http.StatusBadRequest, "account balance time is not set")
+	ErrCannotSetStatementDateForNonCreditCard   = NewNormalError(NormalSubcategoryAccount, 16, http.StatusBadRequest, "cannot set statement date for non credit card account")
+	ErrCannotSetStatementDateForSubAccount      = NewNormalError(NormalSubcategoryAccount, 17, http.StatusBadRequest, "cannot set statement date for sub account")
+	ErrSubAccountNotFound                       = NewNormalError(NormalSubcategoryAccount, 18, http.StatusBadRequest, "sub-account not found")
+	ErrSubAccountInUseCannotBeDeleted           = NewNormalError(NormalSubcategoryAccount, 19, http.StatusBadRequest, "sub-account is in use and cannot be deleted")
+	ErrNotSupportedChangeCurrency               = NewNormalError(NormalSubcategoryAccount, 20, http.StatusBadRequest, "not supported to modify account currency")
+	ErrNotSupportedChangeBalance                = NewNormalError(NormalSubcategoryAccount, 21, http.StatusBadRequest, "not supported to modify account balance")
+	ErrNotSupportedChangeBalanceTime            = NewNormalError(NormalSubcategoryAccount, 22, http.StatusBadRequest, "not supported to modify account balance time")
+	ErrCannotSetCreditLimitForNonCreditCard     = NewNormalError(NormalSubcategoryAccount, 23, http.StatusBadRequest, "cannot set credit limit for non credit card account")
+	ErrCannotSetCreditLimitForSubAccount        = NewNormalError(NormalSubcategoryAccount, 24, http.StatusBadRequest, "cannot set credit limit for sub account")
+	ErrCannotSetLoanInfoForNonDebtAccount       = NewNormalError(NormalSubcategoryAccount, 25, http.StatusBadRequest, "cannot set loan info for non debt account")
+	ErrCannotSetLoanInfoForSubAccount           = NewNormalError(NormalSubcategoryAccount, 26, http.StatusBadRequest, "cannot set loan info for sub account")
+	ErrLoanInfoInvalid                          = NewNormalError(NormalSubcategoryAccount, 27, http.StatusBadRequest, "loan info is invalid")
+	ErrLoanStartDateInvalid                     = NewNormalError(NormalSubcategoryAccount, 28, http.StatusBadRequest, "loan start date is invalid")
+	ErrLoanCompoundingTypeInvalid               = NewNormalError(NormalSubcategoryAccount, 29, http.StatusBadRequest, "loan compounding type is invalid")
+	ErrAccountHasNoLoanInfo                     = NewNormalError(NormalSubcategoryAccount, 30, http.StatusBadRequest, "account has no loan info")
+	ErrLoanPaymentPeriodInvalid                 = NewNormalError(NormalSubcategoryAccount, 31, http.StatusBadRequest, "loan payment period is invalid")
+	ErrLoanPaymentAccountCurrencyNotMatch       = NewNormalError(NormalSubcategoryAccount, 32, http.StatusBadRequest, "currency of loan payment account does not match loan account")
+	ErrCannotSetPaymentSettingsForNonCreditCard = NewNormalError(NormalSubcategoryAccount, 33, http.StatusBadRequest, "cannot set payment settings for non credit card account")
+	ErrCannotSetPaymentSettingsForSubAccount    = NewNormalError(NormalSubcategoryAccount, 34, http.StatusBadRequest, "cannot set payment settings for sub account")
+	ErrCreditCardDueReminderDaysInvalid         = NewNormalError(NormalSubcategoryAccount, 35, http.StatusBadRequest, "credit card due reminder days must be less than payment due days")
+	ErrCreditCardStatementDateNotSet            = NewNormalError(NormalSubcategoryAccount, 36, http.StatusBadRequest, "credit card statement date is not set")
+	ErrAccountBalanceHistoryPeriodTypeInvalid   = NewNormalError(NormalSubcategoryAccount, 37, http.StatusBadRequest, "account balance history period type is invalid")
+	ErrAccountBalanceHistoryTimeRangeInvalid    = NewNormalError(NormalSubcategoryAccount, 38, http.StatusBadRequest, "account balance history time range is invalid")
+	ErrTooManyAccountBalanceHistoryPeriods      = NewNormalError(NormalSubcategoryAccount, 39, http.StatusBadRequest, "too many account balance history periods")
+	ErrAccountExchangeRateNotFound              = NewNormalError(NormalSubcategoryAccount, 40, http.StatusBadRequest, "exchange rate of account currency not found")
 )
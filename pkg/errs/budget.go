@@ -0,0 +1,13 @@
+package errs
+
+import "net/http"
+
+// Error codes related to budgets
+var (
+	ErrBudgetIdInvalid            = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
+	ErrBudgetNotFound             = NewNormalError(NormalSubcategoryBudget, 1, http.StatusBadRequest, "budget not found")
+	ErrBudgetScopeInvalid         = NewNormalError(NormalSubcategoryBudget, 2, http.StatusBadRequest, "budget scope is invalid")
+	ErrBudgetPeriodTypeInvalid    = NewNormalError(NormalSubcategoryBudget, 3, http.StatusBadRequest, "budget period type is invalid")
+	ErrBudgetCategoryTypeInvalid  = NewNormalError(NormalSubcategoryBudget, 4, http.StatusBadRequest, "budget category must be an expense category")
+	ErrBudgetExchangeRateNotFound = NewNormalError(NormalSubcategoryBudget, 5, http.StatusBadRequest, "exchange rate to budget currency not found")
+)
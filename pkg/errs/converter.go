@@ -31,4 +31,5 @@ var (
 	ErrInvalidXmlFile                      = NewNormalError(NormalSubcategoryConverter, 24, http.StatusBadRequest, "invalid xml file")
 	ErrInvalidMT940File                    = NewNormalError(NormalSubcategoryConverter, 25, http.StatusBadRequest, "invalid mt940 file")
 	ErrInvalidJSONFile                     = NewNormalError(NormalSubcategoryConverter, 26, http.StatusBadRequest, "invalid json file")
+	ErrSplitLineWithoutParentTransaction   = NewNormalError(NormalSubcategoryConverter, 27, http.StatusBadRequest, "split line item does not follow a matched income or expense transaction")
 )
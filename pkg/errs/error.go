@@ -44,6 +44,14 @@ const (
 	NormalSubcategoryUserExternalAuth       = 16
 	NormalSubcategoryOAuth2                 = 17
 	NormalSubcategoryFund                   = 18
+	NormalSubcategoryBudget                 = 19
+	NormalSubcategoryTrash                  = 20
+	NormalSubcategoryTransactionRule        = 21
+	NormalSubcategoryPayee                  = 22
+	NormalSubcategoryReconciliation         = 23
+	NormalSubcategoryTransactionLink        = 24
+	NormalSubcategoryInvestment             = 25
+	NormalSubcategoryHistoricalExchangeRate = 26
 )
 
 // Error represents the specific error returned to user
@@ -4,14 +4,30 @@ import "net/http"
 
 // Error codes related to funds
 var (
-	ErrFundIdInvalid        = NewNormalError(NormalSubcategoryFund, 0, http.StatusBadRequest, "fund id is invalid")
-	ErrFundNotFound         = NewNormalError(NormalSubcategoryFund, 1, http.StatusBadRequest, "fund not found")
-	ErrFundAccessDenied     = NewNormalError(NormalSubcategoryFund, 2, http.StatusForbidden, "fund access denied")
-	ErrFundNameExists       = NewNormalError(NormalSubcategoryFund, 3, http.StatusBadRequest, "fund name already exists")
-	ErrMemberIdInvalid      = NewNormalError(NormalSubcategoryFund, 4, http.StatusBadRequest, "member id is invalid")
-	ErrMemberNotFound       = NewNormalError(NormalSubcategoryFund, 5, http.StatusBadRequest, "member not found")
-	ErrCannotRemoveOwner    = NewNormalError(NormalSubcategoryFund, 6, http.StatusBadRequest, "cannot remove fund owner")
-	ErrInvalidFundRole      = NewNormalError(NormalSubcategoryFund, 7, http.StatusBadRequest, "invalid fund role")
-	ErrMemberAlreadyLinked  = NewNormalError(NormalSubcategoryFund, 8, http.StatusBadRequest, "member already linked to user")
-	ErrCannotLinkToSelf     = NewNormalError(NormalSubcategoryFund, 9, http.StatusBadRequest, "cannot link member to self")
-)
\ No newline at end of file
+	ErrFundIdInvalid                        = NewNormalError(NormalSubcategoryFund, 0, http.StatusBadRequest, "fund id is invalid")
+	ErrFundNotFound                         = NewNormalError(NormalSubcategoryFund, 1, http.StatusBadRequest, "fund not found")
+	ErrFundAccessDenied                     = NewNormalError(NormalSubcategoryFund, 2, http.StatusForbidden, "fund access denied")
+	ErrFundNameExists                       = NewNormalError(NormalSubcategoryFund, 3, http.StatusBadRequest, "fund name already exists")
+	ErrMemberIdInvalid                      = NewNormalError(NormalSubcategoryFund, 4, http.StatusBadRequest, "member id is invalid")
+	ErrMemberNotFound                       = NewNormalError(NormalSubcategoryFund, 5, http.StatusBadRequest, "member not found")
+	ErrCannotRemoveOwner                    = NewNormalError(NormalSubcategoryFund, 6, http.StatusBadRequest, "cannot remove fund owner")
+	ErrInvalidFundRole                      = NewNormalError(NormalSubcategoryFund, 7, http.StatusBadRequest, "invalid fund role")
+	ErrMemberAlreadyLinked                  = NewNormalError(NormalSubcategoryFund, 8, http.StatusBadRequest, "member already linked to user")
+	ErrCannotLinkToSelf                     = NewNormalError(NormalSubcategoryFund, 9, http.StatusBadRequest, "cannot link member to self")
+	ErrFundInvitationIdInvalid              = NewNormalError(NormalSubcategoryFund, 10, http.StatusBadRequest, "fund invitation id is invalid")
+	ErrFundInvitationNotFound               = NewNormalError(NormalSubcategoryFund, 11, http.StatusBadRequest, "fund invitation not found")
+	ErrFundInvitationExpired                = NewNormalError(NormalSubcategoryFund, 12, http.StatusBadRequest, "fund invitation has expired")
+	ErrFundInvitationNotPending             = NewNormalError(NormalSubcategoryFund, 13, http.StatusBadRequest, "fund invitation is not pending")
+	ErrFundInvitationTokenInvalid           = NewNormalError(NormalSubcategoryFund, 14, http.StatusBadRequest, "fund invitation token is invalid or expired")
+	ErrFundInvitationEmailIsEmpty           = NewNormalError(NormalSubcategoryFund, 15, http.StatusBadRequest, "fund member email is empty")
+	ErrFundInviteeNotFound                  = NewNormalError(NormalSubcategoryFund, 16, http.StatusBadRequest, "no user is registered with the fund member email")
+	ErrFundInvitationRequireIdOrToken       = NewNormalError(NormalSubcategoryFund, 17, http.StatusBadRequest, "fund invitation id or token is required")
+	ErrFundPermissionDenied                 = NewNormalError(NormalSubcategoryFund, 18, http.StatusForbidden, "you are not permitted to perform this action in this fund")
+	ErrFundSettlementTimeInvalid            = NewNormalError(NormalSubcategoryFund, 19, http.StatusBadRequest, "settlement time must be later than last settlement time and not in the future")
+	ErrFundSettlementRepaymentsMismatch     = NewNormalError(NormalSubcategoryFund, 20, http.StatusBadRequest, "settlement repayments do not match current member balances")
+	ErrFundSettlementAccountCurrencyInvalid = NewNormalError(NormalSubcategoryFund, 21, http.StatusBadRequest, "settlement account currency does not match repayment currency")
+	ErrFundOwnerCannotLeave                 = NewNormalError(NormalSubcategoryFund, 22, http.StatusBadRequest, "fund owner cannot leave fund, please transfer ownership first")
+	ErrCannotChangeOwnerRole                = NewNormalError(NormalSubcategoryFund, 23, http.StatusBadRequest, "cannot change role of fund owner, please transfer ownership instead")
+	ErrFundMemberNotLinked                  = NewNormalError(NormalSubcategoryFund, 24, http.StatusBadRequest, "fund member is not linked to any user")
+	ErrCannotTransferOwnershipToSelf        = NewNormalError(NormalSubcategoryFund, 25, http.StatusBadRequest, "cannot transfer fund ownership to self")
+)
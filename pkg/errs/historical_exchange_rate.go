@@ -0,0 +1,12 @@
+package errs
+
+import "net/http"
+
+// Error codes related to historical exchange rates
+var (
+	ErrHistoricalExchangeRatesNotSupported    = NewNormalError(NormalSubcategoryHistoricalExchangeRate, 0, http.StatusBadRequest, "current exchange rates data source does not support historical exchange rates")
+	ErrHistoricalExchangeRateDateInvalid      = NewNormalError(NormalSubcategoryHistoricalExchangeRate, 1, http.StatusBadRequest, "historical exchange rate date is invalid")
+	ErrHistoricalExchangeRateDateRangeInvalid = NewNormalError(NormalSubcategoryHistoricalExchangeRate, 2, http.StatusBadRequest, "historical exchange rate date range is invalid")
+	ErrHistoricalExchangeRateDateRangeTooLong = NewNormalError(NormalSubcategoryHistoricalExchangeRate, 3, http.StatusBadRequest, "historical exchange rate date range is too long")
+	ErrHistoricalExchangeRateNotFound         = NewNormalError(NormalSubcategoryHistoricalExchangeRate, 4, http.StatusBadRequest, "historical exchange rate data not found")
+)
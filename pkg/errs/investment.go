@@ -0,0 +1,26 @@
+package errs
+
+import "net/http"
+
+// Error codes related to investments
+var (
+	ErrSecurityIdInvalid                        = NewNormalError(NormalSubcategoryInvestment, 0, http.StatusBadRequest, "security id is invalid")
+	ErrSecurityNotFound                         = NewNormalError(NormalSubcategoryInvestment, 1, http.StatusBadRequest, "security not found")
+	ErrSecuritySymbolAlreadyExists              = NewNormalError(NormalSubcategoryInvestment, 2, http.StatusBadRequest, "security symbol already exists")
+	ErrSecurityInUseCannotBeDeleted             = NewNormalError(NormalSubcategoryInvestment, 3, http.StatusBadRequest, "security is in use and cannot be deleted")
+	ErrSecurityCostBasisMethodInvalid           = NewNormalError(NormalSubcategoryInvestment, 4, http.StatusBadRequest, "security cost basis method is invalid")
+	ErrInvestmentTradeIdInvalid                 = NewNormalError(NormalSubcategoryInvestment, 5, http.StatusBadRequest, "investment trade id is invalid")
+	ErrInvestmentTradeNotFound                  = NewNormalError(NormalSubcategoryInvestment, 6, http.StatusBadRequest, "investment trade not found")
+	ErrInvestmentTradeTypeInvalid               = NewNormalError(NormalSubcategoryInvestment, 7, http.StatusBadRequest, "investment trade type is invalid")
+	ErrInvestmentTradeQuantityInvalid           = NewNormalError(NormalSubcategoryInvestment, 8, http.StatusBadRequest, "investment trade quantity is invalid")
+	ErrInvestmentSellQuantityExceedsHolding     = NewNormalError(NormalSubcategoryInvestment, 9, http.StatusBadRequest, "sell quantity exceeds the holding quantity")
+	ErrInvestmentAccountInvalid                 = NewNormalError(NormalSubcategoryInvestment, 10, http.StatusBadRequest, "account must be an investment account without sub-accounts")
+	ErrSecurityCurrencyNotMatchAccount          = NewNormalError(NormalSubcategoryInvestment, 11, http.StatusBadRequest, "security currency does not match account currency")
+	ErrSecurityPriceDateInvalid                 = NewNormalError(NormalSubcategoryInvestment, 12, http.StatusBadRequest, "security price date is invalid")
+	ErrTooManySecurityPricesToImport            = NewNormalError(NormalSubcategoryInvestment, 13, http.StatusBadRequest, "too many security prices to import in one request")
+	ErrSecurityCurrencyCannotBeChangedWhenInUse = NewNormalError(NormalSubcategoryInvestment, 14, http.StatusBadRequest, "security currency cannot be changed because it is in use")
+	ErrInvestmentDividendAmountInvalid          = NewNormalError(NormalSubcategoryInvestment, 15, http.StatusBadRequest, "investment dividend amount is invalid")
+	ErrInvestmentTradeFeeExceedsAmount          = NewNormalError(NormalSubcategoryInvestment, 16, http.StatusBadRequest, "investment trade fee exceeds the amount")
+	ErrCannotModifyInvestmentTradeTransaction   = NewNormalError(NormalSubcategoryInvestment, 17, http.StatusBadRequest, "cannot change account or amount of the transaction of investment trade")
+	ErrCannotDeleteInvestmentTradeTransaction   = NewNormalError(NormalSubcategoryInvestment, 18, http.StatusBadRequest, "cannot delete the transaction of investment trade, please delete the investment trade instead")
+)
@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to payees
+var (
+	ErrPayeeIdInvalid                  = NewNormalError(NormalSubcategoryPayee, 0, http.StatusBadRequest, "payee id is invalid")
+	ErrPayeeNotFound                   = NewNormalError(NormalSubcategoryPayee, 1, http.StatusBadRequest, "payee not found")
+	ErrPayeeNameIsEmpty                = NewNormalError(NormalSubcategoryPayee, 2, http.StatusBadRequest, "payee name is empty")
+	ErrPayeeNameAlreadyExists          = NewNormalError(NormalSubcategoryPayee, 3, http.StatusBadRequest, "payee name or alias already exists")
+	ErrPayeeAliasInvalid               = NewNormalError(NormalSubcategoryPayee, 4, http.StatusBadRequest, "payee alias is invalid")
+	ErrPayeeDefaultCategoryTypeInvalid = NewNormalError(NormalSubcategoryPayee, 5, http.StatusBadRequest, "payee default category must be an income or expense category")
+	ErrPayeeInUseCannotBeDeleted       = NewNormalError(NormalSubcategoryPayee, 6, http.StatusBadRequest, "payee is in use and cannot be deleted")
+)
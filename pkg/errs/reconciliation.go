@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to reconciliations
+var (
+	ErrReconciliationIdInvalid          = NewNormalError(NormalSubcategoryReconciliation, 0, http.StatusBadRequest, "reconciliation id is invalid")
+	ErrReconciliationNotFound           = NewNormalError(NormalSubcategoryReconciliation, 1, http.StatusBadRequest, "reconciliation not found")
+	ErrReconciliationAlreadyInProgress  = NewNormalError(NormalSubcategoryReconciliation, 2, http.StatusBadRequest, "there is already a reconciliation in progress for this account")
+	ErrReconciliationAlreadyCompleted   = NewNormalError(NormalSubcategoryReconciliation, 3, http.StatusBadRequest, "reconciliation has already been completed")
+	ErrReconciliationBalanceNotMatch    = NewNormalError(NormalSubcategoryReconciliation, 4, http.StatusBadRequest, "cleared balance does not match statement balance")
+	ErrReconciliationStatementTimeEarly = NewNormalError(NormalSubcategoryReconciliation, 5, http.StatusBadRequest, "statement end time cannot be earlier than last reconciliation")
+	ErrTooManyTransactionsToReconcile   = NewNormalError(NormalSubcategoryReconciliation, 6, http.StatusBadRequest, "too many transactions to mark in one request")
+)
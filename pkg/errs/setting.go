@@ -30,4 +30,6 @@ var (
 	ErrInvalidOAuth2UserIdentifier                    = NewSystemError(SystemSubcategorySetting, 23, http.StatusInternalServerError, "invalid oauth 2.0 user identifier")
 	ErrInvalidOAuth2Provider                          = NewSystemError(SystemSubcategorySetting, 24, http.StatusInternalServerError, "invalid oauth 2.0 provider")
 	ErrInvalidOAuth2StateExpiredTime                  = NewSystemError(SystemSubcategorySetting, 25, http.StatusInternalServerError, "invalid oauth 2.0 state expired time")
+	ErrInvalidFundInvitationTokenExpiredTime          = NewSystemError(SystemSubcategorySetting, 26, http.StatusInternalServerError, "invalid fund invitation token expired time")
+	ErrInvalidTrashRetentionDays                      = NewSystemError(SystemSubcategorySetting, 27, http.StatusInternalServerError, "invalid trash retention days")
 )
@@ -45,4 +45,23 @@ var (
 	ErrCannotMoveTransactionFromOrToHiddenAccount                  = NewNormalError(NormalSubcategoryTransaction, 38, http.StatusBadRequest, "cannot move transaction from or to hidden account")
 	ErrCannotMoveTransactionFromOrToParentAccount                  = NewNormalError(NormalSubcategoryTransaction, 39, http.StatusBadRequest, "cannot move transaction from or to parent account")
 	ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies = NewNormalError(NormalSubcategoryTransaction, 40, http.StatusBadRequest, "cannot move transaction between accounts with different currencies")
+	ErrTransactionMemberSplitTypeInvalid                           = NewNormalError(NormalSubcategoryTransaction, 41, http.StatusBadRequest, "transaction member split type is invalid")
+	ErrTransactionMemberSplitValueInvalid                          = NewNormalError(NormalSubcategoryTransaction, 42, http.StatusBadRequest, "transaction member split value is invalid")
+	ErrTransactionMemberSplitPercentageNotEqualTo100               = NewNormalError(NormalSubcategoryTransaction, 43, http.StatusBadRequest, "sum of transaction member split percentages must be 100%")
+	ErrTransactionMemberSplitAmountNotEqualToTransactionAmount     = NewNormalError(NormalSubcategoryTransaction, 44, http.StatusBadRequest, "sum of transaction member split amounts must be equal to transaction amount")
+	ErrTransactionMemberDuplicated                                 = NewNormalError(NormalSubcategoryTransaction, 45, http.StatusBadRequest, "transaction member is duplicated")
+	ErrTransactionSplitNotSupported                                = NewNormalError(NormalSubcategoryTransaction, 46, http.StatusBadRequest, "only income and expense transaction can be split")
+	ErrTransactionSplitLinesTooFew                                 = NewNormalError(NormalSubcategoryTransaction, 47, http.StatusBadRequest, "split transaction must have at least two line items")
+	ErrTransactionHasTooManySplitLines                             = NewNormalError(NormalSubcategoryTransaction, 48, http.StatusBadRequest, "transaction has too many split line items")
+	ErrTransactionSplitAmountNotEqualToTransactionAmount           = NewNormalError(NormalSubcategoryTransaction, 49, http.StatusBadRequest, "sum of split line item amounts must be equal to transaction amount")
+	ErrTransactionRevisionInvalid                                  = NewNormalError(NormalSubcategoryTransaction, 50, http.StatusBadRequest, "transaction revision is invalid")
+	ErrTransactionRevisionNotFound                                 = NewNormalError(NormalSubcategoryTransaction, 51, http.StatusBadRequest, "transaction revision not found")
+	ErrTransactionBatchModificationFailed                          = NewNormalError(NormalSubcategoryTransaction, 52, http.StatusBadRequest, "some transactions cannot be modified")
+	ErrTooManyTransactionsToBatchModify                            = NewNormalError(NormalSubcategoryTransaction, 53, http.StatusBadRequest, "too many transactions to batch modify")
+	ErrTransactionCommentTooLong                                   = NewNormalError(NormalSubcategoryTransaction, 54, http.StatusBadRequest, "transaction comment is too long")
+	ErrTransactionTagCannotBeAddedAndRemoved                       = NewNormalError(NormalSubcategoryTransaction, 55, http.StatusBadRequest, "transaction tag cannot be added and removed at the same time")
+	ErrTransactionQuerySyntaxInvalid                               = NewNormalError(NormalSubcategoryTransaction, 56, http.StatusBadRequest, "transaction query syntax is invalid")
+	ErrTransactionClearedStatusInvalid                             = NewNormalError(NormalSubcategoryTransaction, 57, http.StatusBadRequest, "transaction cleared status is invalid")
+	ErrCannotModifyReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 58, http.StatusBadRequest, "cannot modify reconciled transaction")
+	ErrCannotDeleteReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 59, http.StatusBadRequest, "cannot delete reconciled transaction")
 )
@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction links
+var (
+	ErrTransactionLinkIdInvalid               = NewNormalError(NormalSubcategoryTransactionLink, 0, http.StatusBadRequest, "transaction link id is invalid")
+	ErrTransactionLinkNotFound                = NewNormalError(NormalSubcategoryTransactionLink, 1, http.StatusBadRequest, "transaction link not found")
+	ErrTransactionLinkTypeInvalid             = NewNormalError(NormalSubcategoryTransactionLink, 2, http.StatusBadRequest, "transaction link type is invalid")
+	ErrTransactionCannotLinkToItself          = NewNormalError(NormalSubcategoryTransactionLink, 3, http.StatusBadRequest, "transaction cannot be linked to itself")
+	ErrTransactionLinkAlreadyExists           = NewNormalError(NormalSubcategoryTransactionLink, 4, http.StatusBadRequest, "transaction link already exists")
+	ErrTransactionLinkTypeNotMatchTransaction = NewNormalError(NormalSubcategoryTransactionLink, 5, http.StatusBadRequest, "transaction types do not match the link type")
+	ErrTransactionAlreadyLinkedToOriginal     = NewNormalError(NormalSubcategoryTransactionLink, 6, http.StatusBadRequest, "transaction has already been linked to an original transaction")
+	ErrLinkedTransactionNotFound              = NewNormalError(NormalSubcategoryTransactionLink, 7, http.StatusBadRequest, "linked transaction not found")
+)
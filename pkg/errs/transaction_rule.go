@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid                       = NewNormalError(NormalSubcategoryTransactionRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound                        = NewNormalError(NormalSubcategoryTransactionRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleScopeInvalid                    = NewNormalError(NormalSubcategoryTransactionRule, 2, http.StatusBadRequest, "transaction rule scope is invalid")
+	ErrTransactionRuleCommentPatternInvalid           = NewNormalError(NormalSubcategoryTransactionRule, 3, http.StatusBadRequest, "transaction rule comment pattern is invalid")
+	ErrTransactionRuleAmountRangeInvalid              = NewNormalError(NormalSubcategoryTransactionRule, 4, http.StatusBadRequest, "transaction rule amount range is invalid")
+	ErrTransactionRuleCounterpartyRequiresTransfer    = NewNormalError(NormalSubcategoryTransactionRule, 5, http.StatusBadRequest, "transaction rule with counterparty account must match transfer transactions")
+	ErrTransactionRuleCategoryRequiresTransactionType = NewNormalError(NormalSubcategoryTransactionRule, 6, http.StatusBadRequest, "transaction rule which sets category must match a transaction type")
+	ErrTransactionRuleCategoryTypeInvalid             = NewNormalError(NormalSubcategoryTransactionRule, 7, http.StatusBadRequest, "transaction rule category type does not match transaction type")
+	ErrTransactionRuleHasNoAction                     = NewNormalError(NormalSubcategoryTransactionRule, 8, http.StatusBadRequest, "transaction rule has no action")
+	ErrTooManyTransactionsMatchedByRule               = NewNormalError(NormalSubcategoryTransactionRule, 9, http.StatusBadRequest, "too many transactions are matched by transaction rule")
+)
@@ -0,0 +1,9 @@
+package errs
+
+import "net/http"
+
+// Error codes related to trash bin
+var (
+	ErrTrashItemTypeInvalid       = NewNormalError(NormalSubcategoryTrash, 0, http.StatusBadRequest, "trash item type is invalid")
+	ErrTrashItemParentNotRestored = NewNormalError(NormalSubcategoryTrash, 1, http.StatusBadRequest, "parent of trash item must be restored first")
+)
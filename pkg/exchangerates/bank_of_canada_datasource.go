@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrlFormat = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=%s&end_date=%s"
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
@@ -23,6 +25,9 @@ const bankOfCanadaBaseCurrency = "CAD"
 const bankOfCanadaDataUpdateDateFormat = "2006-01-02 15:04"
 const bankOfCanadaDataUpdateDateTimezone = "America/Toronto"
 
+// The exchange rates are not published on weekends and holidays, so request the observations of several previous days to find the latest one
+const bankOfCanadaHistoricalExchangeRateLookBackDays = 7
+
 // BankOfCanadaDataSource defines the structure of exchange rates data source of bank of Canada
 type BankOfCanadaDataSource struct {
 	HttpExchangeRatesDataSource
@@ -141,6 +146,19 @@ func (e *BankOfCanadaDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsForDate returns the bank of Canada exchange rates http requests of the observations not later than the specified date
+func (e *BankOfCanadaDataSource) BuildRequestsForDate(date time.Time) ([]*http.Request, error) {
+	startDate := utils.FormatUnixTimeToLongDate(date.AddDate(0, 0, -bankOfCanadaHistoricalExchangeRateLookBackDays).Unix(), time.UTC)
+	endDate := utils.FormatUnixTimeToLongDate(date.Unix(), time.UTC)
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfCanadaHistoricalExchangeRateUrlFormat, startDate, endDate), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the bank of Canada data source raw response
 func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
@@ -160,3 +178,9 @@ func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseForDate returns the common response entity of the latest exchange rates published not later than the specified date according to the bank of Canada data source raw response,
+// the later observations in the response overwrite the earlier ones
+func (e *BankOfCanadaDataSource) ParseForDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	return e.Parse(c, content)
+}
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -195,3 +196,11 @@ func TestBankOfCanadaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfCanadaDataSource_BuildRequestsForDate(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+
+	requests, err := dataSource.BuildRequestsForDate(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=2024-02-26&end_date=2024-03-04", requests[0].URL.String())
+}
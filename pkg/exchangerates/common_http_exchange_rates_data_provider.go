@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -22,6 +23,15 @@ type HttpExchangeRatesDataSource interface {
 	Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
 
+// HttpHistoricalExchangeRatesDataSource defines the structure of http exchange rates data source which supports historical exchange rates
+type HttpHistoricalExchangeRatesDataSource interface {
+	// BuildRequestsForDate returns the http requests of the exchange rates of the specified date
+	BuildRequestsForDate(date time.Time) ([]*http.Request, error)
+
+	// ParseForDate returns the common response entity of the latest exchange rates published not later than the specified date according to the data source raw response
+	ParseForDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error)
+}
+
 // CommonHttpExchangeRatesDataProvider defines the structure of common http exchange rates data provider
 type CommonHttpExchangeRatesDataProvider struct {
 	ExchangeRatesDataProvider
@@ -37,31 +47,70 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 		return nil, errs.ErrFailedToRequestRemoteApi
 	}
 
-	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
+	return e.requestExchangeRates(c, uid, requests, nil, e.dataSource.Parse)
+}
 
-	for i := 0; i < len(requests); i++ {
-		req := requests[i]
-		resp, err := e.httpClient.Do(req)
+// SupportsHistoricalExchangeRates returns whether the data source supports requesting the exchange rates of a specified date
+func (e *CommonHttpExchangeRatesDataProvider) SupportsHistoricalExchangeRates() bool {
+	_, ok := e.dataSource.(HttpHistoricalExchangeRatesDataSource)
+	return ok
+}
+
+// GetExchangeRatesForDates returns the latest exchange rates published not later than each specified date,
+// the same url is requested only once for all the dates (e.g. the full history file of some data sources)
+func (e *CommonHttpExchangeRatesDataProvider) GetExchangeRatesForDates(c core.Context, uid int64, currentConfig *settings.Config, dates []time.Time) (map[string]*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := e.dataSource.(HttpHistoricalExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	responseContents := make(map[string][]byte)
+	exchangeRateResps := make(map[string]*models.LatestExchangeRateResponse, len(dates))
+
+	for i := 0; i < len(dates); i++ {
+		date := dates[i]
+		rateDate := utils.FormatUnixTimeToLongDate(date.Unix(), time.UTC)
+		requests, err := historicalDataSource.BuildRequestsForDate(date)
+
+		if err != nil {
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetExchangeRatesForDates] failed to build requests of date \"%s\" for user \"uid:%d\", because %s", rateDate, uid, err.Error())
+			continue
+		}
+
+		exchangeRateResp, err := e.requestExchangeRates(c, uid, requests, responseContents, func(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+			return historicalDataSource.ParseForDate(c, content, date)
+		})
 
 		if err != nil {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrFailedToRequestRemoteApi
+			log.Warnf(c, "[common_http_exchange_rates_data_provider.GetExchangeRatesForDates] failed to get exchange rates of date \"%s\" for user \"uid:%d\", because %s", rateDate, uid, err.Error())
+			continue
 		}
 
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
+		exchangeRateResps[rateDate] = exchangeRateResp
+	}
+
+	return exchangeRateResps, nil
+}
 
-		log.Debugf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] response#%d is %s", i, body)
+// requestExchangeRates requests all the requests and merges the parsed responses, the response contents are reused by url if the cache map is not nil
+func (e *CommonHttpExchangeRatesDataProvider) requestExchangeRates(c core.Context, uid int64, requests []*http.Request, responseContents map[string][]byte, parse func(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)) (*models.LatestExchangeRateResponse, error) {
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
 
-		if resp.StatusCode != 200 {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to get latest exchange rate data response for user \"uid:%d\", because response code is %d", uid, resp.StatusCode)
-			return nil, errs.ErrFailedToRequestRemoteApi
+	for i := 0; i < len(requests); i++ {
+		req := requests[i]
+		body, err := e.requestContent(c, uid, req, responseContents)
+
+		if err != nil {
+			return nil, err
 		}
 
-		exchangeRateResp, err := e.dataSource.Parse(c, body)
+		log.Debugf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] response#%d is %s", i, body)
+
+		exchangeRateResp, err := parse(c, body)
 
 		if err != nil {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
 			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 		}
 
@@ -103,6 +152,40 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 	return finalExchangeRateResponse, nil
 }
 
+func (e *CommonHttpExchangeRatesDataProvider) requestContent(c core.Context, uid int64, req *http.Request, responseContents map[string][]byte) ([]byte, error) {
+	url := req.URL.String()
+
+	if body, exists := responseContents[url]; exists {
+		return body, nil
+	}
+
+	resp, err := e.httpClient.Do(req)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestContent] failed to request exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestContent] failed to read exchange rate data response for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.requestContent] failed to get exchange rate data response for user \"uid:%d\", because response code is %d", uid, resp.StatusCode)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if responseContents != nil {
+		responseContents[url] = body
+	}
+
+	return body, nil
+}
+
 func newCommonHttpExchangeRatesDataProvider(config *settings.Config, dataSource HttpExchangeRatesDataSource) *CommonHttpExchangeRatesDataProvider {
 	return &CommonHttpExchangeRatesDataProvider{
 		dataSource: dataSource,
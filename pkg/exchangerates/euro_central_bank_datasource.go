@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/net/html/charset"
@@ -17,6 +18,8 @@ import (
 )
 
 const euroCentralBankExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const euroCentralBankRecentHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+const euroCentralBankAllHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
 const euroCentralBankExchangeRateReferenceUrl = "https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html"
 const euroCentralBankDataSource = "European Central Bank"
 const euroCentralBankBaseCurrency = "EUR"
@@ -24,6 +27,9 @@ const euroCentralBankBaseCurrency = "EUR"
 const euroCentralBankDataUpdateDateFormat = "2006-01-02 15"
 const euroCentralBankDataUpdateDateTimezone = "Europe/Berlin"
 
+// The recent historical data contains the exchange rates of the last 90 days, use a smaller value to make sure that the previous working day is included
+const euroCentralBankRecentHistoricalExchangeRateDays = 85
+
 // EuroCentralBankDataSource defines the structure of exchange rates data source of euro central bank
 type EuroCentralBankDataSource struct {
 	HttpExchangeRatesDataSource
@@ -103,6 +109,34 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c core.Co
 	return latestExchangeRateResp
 }
 
+// ToExchangeRateResponseForDate returns a view-object of the latest exchange rates published not later than the specified date according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToExchangeRateResponseForDate(c core.Context, date string) *models.LatestExchangeRateResponse {
+	var exchangeRatesForDate *EuroCentralBankExchangeRates
+
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		exchangeRates := e.AllExchangeRates[i]
+
+		if strings.Compare(exchangeRates.Date, date) > 0 {
+			continue
+		}
+
+		if exchangeRatesForDate == nil || strings.Compare(exchangeRates.Date, exchangeRatesForDate.Date) > 0 {
+			exchangeRatesForDate = exchangeRates
+		}
+	}
+
+	if exchangeRatesForDate == nil {
+		log.Errorf(c, "[euro_central_bank_datasource.ToExchangeRateResponseForDate] no exchange rates published not later than %s", date)
+		return nil
+	}
+
+	exchangeRateData := &EuroCentralBankExchangeRateData{
+		AllExchangeRates: []*EuroCentralBankExchangeRates{exchangeRatesForDate},
+	}
+
+	return exchangeRateData.ToLatestExchangeRateResponse(c)
+}
+
 // ToLatestExchangeRate returns a data pair according to original data from euro central bank
 func (e *EuroCentralBankExchangeRate) ToLatestExchangeRate() *models.LatestExchangeRate {
 	return &models.LatestExchangeRate{
@@ -122,17 +156,29 @@ func (e *EuroCentralBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsForDate returns the euro central bank historical exchange rates http requests which contain the specified date
+func (e *EuroCentralBankDataSource) BuildRequestsForDate(date time.Time) ([]*http.Request, error) {
+	url := euroCentralBankAllHistoricalExchangeRateUrl
+
+	if time.Since(date) < euroCentralBankRecentHistoricalExchangeRateDays*24*time.Hour {
+		url = euroCentralBankRecentHistoricalExchangeRateUrl
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the euro central bank data source raw response
 func (e *EuroCentralBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
-	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
-	xmlDecoder.CharsetReader = charset.NewReaderLabel
-
-	euroCentralBankData := &EuroCentralBankExchangeRateData{}
-	err := xmlDecoder.Decode(euroCentralBankData)
+	euroCentralBankData, err := e.parseExchangeRateData(c, content)
 
 	if err != nil {
-		log.Errorf(c, "[euro_central_bank_datasource.Parse] failed to parse xml data, content is %s, because %s", string(content), err.Error())
-		return nil, errs.ErrFailedToRequestRemoteApi
+		return nil, err
 	}
 
 	latestExchangeRateResponse := euroCentralBankData.ToLatestExchangeRateResponse(c)
@@ -144,3 +190,36 @@ func (e *EuroCentralBankDataSource) Parse(c core.Context, content []byte) (*mode
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseForDate returns the common response entity of the latest exchange rates published not later than the specified date according to the euro central bank data source raw response
+func (e *EuroCentralBankDataSource) ParseForDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	euroCentralBankData, err := e.parseExchangeRateData(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRateResponse := euroCentralBankData.ToExchangeRateResponseForDate(c, utils.FormatUnixTimeToLongDate(date.Unix(), time.UTC))
+
+	if exchangeRateResponse == nil {
+		log.Errorf(c, "[euro_central_bank_datasource.ParseForDate] failed to parse exchange rate data of date, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return exchangeRateResponse, nil
+}
+
+func (e *EuroCentralBankDataSource) parseExchangeRateData(c core.Context, content []byte) (*EuroCentralBankExchangeRateData, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	euroCentralBankData := &EuroCentralBankExchangeRateData{}
+	err := xmlDecoder.Decode(euroCentralBankData)
+
+	if err != nil {
+		log.Errorf(c, "[euro_central_bank_datasource.parseExchangeRateData] failed to parse xml data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return euroCentralBankData, nil
+}
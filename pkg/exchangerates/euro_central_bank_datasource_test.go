@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -138,3 +139,46 @@ func TestEuroCentralBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestEuroCentralBankDataSource_ParseForDate(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+	content := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+		"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">\n" +
+		"  <Cube>\n" +
+		"    <Cube time=\"2021-04-06\">\n" +
+		"      <Cube currency=\"USD\" rate=\"1.1812\" />\n" +
+		"    </Cube>\n" +
+		"    <Cube time=\"2021-04-01\">\n" +
+		"      <Cube currency=\"USD\" rate=\"1.1746\" />\n" +
+		"    </Cube>\n" +
+		"    <Cube time=\"2021-03-31\">\n" +
+		"      <Cube currency=\"USD\" rate=\"1.1725\" />\n" +
+		"    </Cube>\n" +
+		"  </Cube>\n" +
+		"</gesmes:Envelope>"
+
+	actualExchangeRateResponse, err := dataSource.ParseForDate(context, []byte(content), time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1617285600), actualExchangeRateResponse.UpdateTime)
+	assert.Equal(t, models.LatestExchangeRateSlice{{Currency: "USD", Rate: "1.1746"}}, actualExchangeRateResponse.ExchangeRates)
+
+	actualExchangeRateResponse, err = dataSource.ParseForDate(context, []byte(content), time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.LatestExchangeRateSlice{{Currency: "USD", Rate: "1.1725"}}, actualExchangeRateResponse.ExchangeRates)
+
+	_, err = dataSource.ParseForDate(context, []byte(content), time.Date(2021, 3, 30, 0, 0, 0, 0, time.UTC))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEuroCentralBankDataSource_BuildRequestsForDate(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+
+	requests, err := dataSource.BuildRequestsForDate(time.Now().AddDate(0, 0, -7))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankRecentHistoricalExchangeRateUrl, requests[0].URL.String())
+
+	requests, err = dataSource.BuildRequestsForDate(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankAllHistoricalExchangeRateUrl, requests[0].URL.String())
+}
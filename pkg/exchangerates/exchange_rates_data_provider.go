@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -11,3 +13,13 @@ type ExchangeRatesDataProvider interface {
 	// GetLatestExchangeRates returns the common response entities
 	GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataProvider defines the structure of exchange rates data provider which supports historical exchange rates
+type HistoricalExchangeRatesDataProvider interface {
+	// SupportsHistoricalExchangeRates returns whether the exchange rates of a specified date can be requested
+	SupportsHistoricalExchangeRates() bool
+
+	// GetExchangeRatesForDates returns the common response entities of the latest exchange rates published not later than each specified date,
+	// the key of the returned map is the date in yyyy-MM-dd format, and the dates failed to request are not contained in the map
+	GetExchangeRatesForDates(c core.Context, uid int64, currentConfig *settings.Config, dates []time.Time) (map[string]*models.LatestExchangeRateResponse, error)
+}
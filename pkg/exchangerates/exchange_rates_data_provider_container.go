@@ -1,88 +1,263 @@
 package exchangerates
 
 import (
+	"sync"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
-// ExchangeRatesDataProviderContainer contains the current exchange rates data provider
+// ExchangeRatesDataProviderContainer contains the current exchange rates data provider and the fallback data providers
 type ExchangeRatesDataProviderContainer struct {
-	current ExchangeRatesDataProvider
+	current         ExchangeRatesDataProvider
+	dataSources     []string
+	providers       []ExchangeRatesDataProvider
+	cacheEnabled    bool
+	cacheExpiration time.Duration
+	cachedResponse  *models.LatestExchangeRateResponse
+	cacheUpdateTime time.Time
+	cacheMutex      sync.RWMutex
+	refreshMutex    sync.Mutex
+	metrics         *exchangeRatesMetrics
 }
 
 // Initialize a exchange rates data provider container singleton instance
 var (
-	Container = &ExchangeRatesDataProviderContainer{}
+	Container = &ExchangeRatesDataProviderContainer{
+		metrics: newExchangeRatesMetrics(nil),
+	}
 )
 
-// InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
+// InitializeExchangeRatesDataSource initializes the current exchange rates data source and the fallback data sources according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfAustraliaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.DanmarksNationalbankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfGeorgiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfHungaryDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfIsraelDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfMyanmarDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NorgesBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfRomaniaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfRussiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.SwissNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfUkraineDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfUzbekistanDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.InternationalMonetaryFundDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &InternationalMonetaryFundDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
-		Container.current = newUserCustomExchangeRatesDataProvider()
-		return nil
+	dataSources := make([]string, 0, len(config.ExchangeRatesFallbackDataSources)+1)
+	dataSources = append(dataSources, config.ExchangeRatesDataSource)
+	dataSources = append(dataSources, config.ExchangeRatesFallbackDataSources...)
+
+	providers := make([]ExchangeRatesDataProvider, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		provider := newExchangeRatesDataProvider(config, dataSources[i])
+
+		if provider == nil {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		providers = append(providers, provider)
 	}
 
-	return errs.ErrInvalidExchangeRatesDataSource
+	cacheEnabled := config.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource && config.ExchangeRatesCacheExpiredTime > 0
+	Container.setDataProviders(dataSources, providers, cacheEnabled, config.ExchangeRatesCacheExpiredTimeDuration)
+
+	return nil
 }
 
-// GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source
+// GetLatestExchangeRates returns the latest exchange rates data from the cache or the configured exchange rates data sources in order,
+// the currencies which are not provided by the first available data source are supplemented from the following data sources,
+// and the expired cached data is returned if all data sources fail
 func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
-	if Container.current == nil {
+	if e.current == nil {
 		return nil, errs.ErrInvalidExchangeRatesDataSource
 	}
 
-	return e.current.GetLatestExchangeRates(c, uid, currentConfig)
+	if !e.cacheEnabled {
+		return e.requestLatestExchangeRates(c, uid, currentConfig)
+	}
+
+	if exchangeRates := e.getCachedExchangeRates(time.Now()); exchangeRates != nil {
+		e.metrics.addCacheHit()
+		return exchangeRates, nil
+	}
+
+	e.refreshMutex.Lock()
+	defer e.refreshMutex.Unlock()
+
+	if exchangeRates := e.getCachedExchangeRates(time.Now()); exchangeRates != nil {
+		e.metrics.addCacheHit()
+		return exchangeRates, nil
+	}
+
+	e.metrics.addCacheMiss()
+	exchangeRates, err := e.requestLatestExchangeRates(c, uid, currentConfig)
+
+	if err != nil {
+		e.cacheMutex.RLock()
+		cachedResponse := e.cachedResponse
+		e.cacheMutex.RUnlock()
+
+		if cachedResponse == nil {
+			return nil, err
+		}
+
+		e.metrics.addStaleResponse()
+		log.Warnf(c, "[exchange_rates_data_provider_container.GetLatestExchangeRates] all exchange rates data sources are unavailable, return the expired cached exchange rates updated at %d", cachedResponse.UpdateTime)
+
+		return cachedResponse.Clone(), nil
+	}
+
+	e.cacheMutex.Lock()
+	e.cachedResponse = exchangeRates.Clone()
+	e.cacheUpdateTime = time.Now()
+	e.cacheMutex.Unlock()
+
+	return exchangeRates, nil
+}
+
+// GetMetrics returns the metrics of requesting latest exchange rates
+func (e *ExchangeRatesDataProviderContainer) GetMetrics() *models.ExchangeRatesMetricsResponse {
+	return e.metrics.ToExchangeRatesMetricsResponse()
+}
+
+// SupportsHistoricalExchangeRates returns whether the current exchange rates data source supports requesting the exchange rates of a specified date
+func (e *ExchangeRatesDataProviderContainer) SupportsHistoricalExchangeRates() bool {
+	provider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+	return ok && provider.SupportsHistoricalExchangeRates()
+}
+
+// GetExchangeRatesForDates returns the latest exchange rates published not later than each specified date from the current exchange rates data source
+func (e *ExchangeRatesDataProviderContainer) GetExchangeRatesForDates(c core.Context, uid int64, currentConfig *settings.Config, dates []time.Time) (map[string]*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	provider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+
+	if !ok || !provider.SupportsHistoricalExchangeRates() {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	return provider.GetExchangeRatesForDates(c, uid, currentConfig, dates)
+}
+
+func (e *ExchangeRatesDataProviderContainer) setDataProviders(dataSources []string, providers []ExchangeRatesDataProvider, cacheEnabled bool, cacheExpiration time.Duration) {
+	e.refreshMutex.Lock()
+	defer e.refreshMutex.Unlock()
+
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	e.current = nil
+
+	if len(providers) > 0 {
+		e.current = providers[0]
+	}
+
+	e.dataSources = dataSources
+	e.providers = providers
+	e.cacheEnabled = cacheEnabled
+	e.cacheExpiration = cacheExpiration
+	e.cachedResponse = nil
+	e.cacheUpdateTime = time.Time{}
+	e.metrics = newExchangeRatesMetrics(dataSources)
+}
+
+func (e *ExchangeRatesDataProviderContainer) getCachedExchangeRates(currentTime time.Time) *models.LatestExchangeRateResponse {
+	e.cacheMutex.RLock()
+	defer e.cacheMutex.RUnlock()
+
+	if e.cachedResponse == nil || currentTime.Sub(e.cacheUpdateTime) >= e.cacheExpiration {
+		return nil
+	}
+
+	return e.cachedResponse.Clone()
+}
+
+func (e *ExchangeRatesDataProviderContainer) requestLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	var exchangeRates *models.LatestExchangeRateResponse
+	var firstErr error
+	servedDataSource := ""
+	mergedDataSources := make([]string, 0)
+
+	for i := 0; i < len(e.providers); i++ {
+		dataSource := e.dataSources[i]
+		response, err := e.providers[i].GetLatestExchangeRates(c, uid, currentConfig)
+
+		if err == nil && response == nil {
+			err = errs.ErrFailedToRequestRemoteApi
+		}
+
+		if err != nil {
+			e.metrics.addFailedRequest(dataSource)
+
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			if len(e.providers) > 1 {
+				log.Warnf(c, "[exchange_rates_data_provider_container.requestLatestExchangeRates] failed to request latest exchange rates from \"%s\", because %s", dataSource, err.Error())
+			}
+
+			continue
+		}
+
+		e.metrics.addSucceededRequest(dataSource)
+
+		if exchangeRates == nil {
+			exchangeRates = response
+			servedDataSource = dataSource
+		} else if mergedCount := exchangeRates.MergeExchangeRates(response); mergedCount > 0 {
+			e.metrics.addMergedResponse(dataSource)
+			mergedDataSources = append(mergedDataSources, dataSource)
+			log.Debugf(c, "[exchange_rates_data_provider_container.requestLatestExchangeRates] %d currencies are supplemented from \"%s\"", mergedCount, dataSource)
+		}
+	}
+
+	if exchangeRates == nil {
+		e.metrics.addFailedResponse()
+		return nil, firstErr
+	}
+
+	e.metrics.addServedResponse(servedDataSource)
+
+	if len(e.providers) > 1 {
+		log.Infof(c, "[exchange_rates_data_provider_container.requestLatestExchangeRates] latest exchange rates are served by \"%s\", supplemented by %v", servedDataSource, mergedDataSources)
+	}
+
+	return exchangeRates, nil
+}
+
+func newExchangeRatesDataProvider(config *settings.Config, dataSource string) ExchangeRatesDataProvider {
+	if dataSource == settings.ReserveBankOfAustraliaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfAustraliaDataSource{})
+	} else if dataSource == settings.BankOfCanadaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{})
+	} else if dataSource == settings.CzechNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{})
+	} else if dataSource == settings.DanmarksNationalbankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{})
+	} else if dataSource == settings.EuroCentralBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{})
+	} else if dataSource == settings.NationalBankOfGeorgiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{})
+	} else if dataSource == settings.CentralBankOfHungaryDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{})
+	} else if dataSource == settings.BankOfIsraelDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{})
+	} else if dataSource == settings.CentralBankOfMyanmarDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{})
+	} else if dataSource == settings.NorgesBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{})
+	} else if dataSource == settings.NationalBankOfPolandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{})
+	} else if dataSource == settings.NationalBankOfRomaniaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{})
+	} else if dataSource == settings.BankOfRussiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{})
+	} else if dataSource == settings.SwissNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{})
+	} else if dataSource == settings.NationalBankOfUkraineDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{})
+	} else if dataSource == settings.CentralBankOfUzbekistanDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{})
+	} else if dataSource == settings.InternationalMonetaryFundDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &InternationalMonetaryFundDataSource{})
+	} else if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return newUserCustomExchangeRatesDataProvider()
+	}
+
+	return nil
 }
@@ -0,0 +1,183 @@
+package exchangerates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type testExchangeRatesDataProvider struct {
+	response      *models.LatestExchangeRateResponse
+	err           error
+	requestsCount int
+}
+
+func (p *testExchangeRatesDataProvider) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	p.requestsCount++
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.response.Clone(), nil
+}
+
+func newTestExchangeRatesDataProviderContainer(providers []ExchangeRatesDataProvider, cacheEnabled bool) *ExchangeRatesDataProviderContainer {
+	dataSources := []string{"primary", "secondary", "tertiary"}[:len(providers)]
+	container := &ExchangeRatesDataProviderContainer{}
+	container.setDataProviders(dataSources, providers, cacheEnabled, time.Hour)
+
+	return container
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_Fallback(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{err: errs.ErrFailedToRequestRemoteApi}
+	secondary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "secondary",
+		BaseCurrency: "USD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "0.8"},
+		},
+	}}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary, secondary}, false)
+	exchangeRates, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", exchangeRates.DataSource)
+	assert.Equal(t, "USD", exchangeRates.BaseCurrency)
+
+	metrics := container.GetMetrics()
+	assert.Equal(t, int64(1), metrics.DataSources[0].FailedRequests)
+	assert.Equal(t, int64(0), metrics.DataSources[0].ServedResponses)
+	assert.Equal(t, int64(1), metrics.DataSources[1].SucceededRequests)
+	assert.Equal(t, int64(1), metrics.DataSources[1].ServedResponses)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_MergeMissingCurrencies(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "primary",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	}}
+	secondary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "secondary",
+		BaseCurrency: "USD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "0.5"},
+			{Currency: "UZS", Rate: "10000"},
+		},
+	}}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary, secondary}, false)
+	exchangeRates, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", exchangeRates.DataSource)
+	assert.Equal(t, "EUR", exchangeRates.BaseCurrency)
+	assert.Equal(t, models.LatestExchangeRateSlice{
+		{Currency: "USD", Rate: "1.25"},
+		{Currency: "UZS", Rate: "20000"},
+	}, exchangeRates.ExchangeRates)
+
+	metrics := container.GetMetrics()
+	assert.Equal(t, int64(1), metrics.DataSources[0].ServedResponses)
+	assert.Equal(t, int64(1), metrics.DataSources[1].MergedResponses)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_AllFailed(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{err: errs.ErrFailedToRequestRemoteApi}
+	secondary := &testExchangeRatesDataProvider{err: errs.ErrOperationFailed}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary, secondary}, true)
+	_, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+
+	metrics := container.GetMetrics()
+	assert.Equal(t, int64(1), metrics.CacheMisses)
+	assert.Equal(t, int64(1), metrics.FailedResponses)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_Cache(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "primary",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	}}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary}, true)
+	exchangeRates, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	exchangeRates.ExchangeRates[0].Rate = "2"
+
+	exchangeRates, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.25", exchangeRates.ExchangeRates[0].Rate)
+	assert.Equal(t, 1, primary.requestsCount)
+
+	container.cacheUpdateTime = time.Now().Add(-2 * time.Hour)
+	primary.response.ExchangeRates[0].Rate = "1.5"
+
+	exchangeRates, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.5", exchangeRates.ExchangeRates[0].Rate)
+	assert.Equal(t, 2, primary.requestsCount)
+
+	metrics := container.GetMetrics()
+	assert.Equal(t, int64(1), metrics.CacheHits)
+	assert.Equal(t, int64(2), metrics.CacheMisses)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_StaleCacheWhenAllFailed(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "primary",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	}}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary}, true)
+	_, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+
+	container.cacheUpdateTime = time.Now().Add(-2 * time.Hour)
+	primary.err = errs.ErrFailedToRequestRemoteApi
+
+	exchangeRates, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.25", exchangeRates.ExchangeRates[0].Rate)
+	assert.Equal(t, 2, primary.requestsCount)
+
+	metrics := container.GetMetrics()
+	assert.Equal(t, int64(1), metrics.StaleResponses)
+	assert.Equal(t, int64(1), metrics.FailedResponses)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_CacheDisabled(t *testing.T) {
+	primary := &testExchangeRatesDataProvider{response: &models.LatestExchangeRateResponse{
+		DataSource:   "primary",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	}}
+
+	container := newTestExchangeRatesDataProviderContainer([]ExchangeRatesDataProvider{primary}, false)
+
+	for i := 0; i < 3; i++ {
+		_, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, &settings.Config{})
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, 3, primary.requestsCount)
+	assert.Equal(t, int64(0), container.GetMetrics().CacheMisses)
+}
@@ -0,0 +1,108 @@
+package exchangerates
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// maxExchangeRatesHistoryBackfillRequestsCount represents the maximum count of dates to request the missing exchange rates history at a time,
+// the remaining dates are requested in the next runs, the dates sharing the same remote file are requested only once
+const maxExchangeRatesHistoryBackfillRequestsCount = 31
+
+// StoreExchangeRatesHistory stores the latest exchange rates of the current data source as the exchange rates of the date of the specified time,
+// and requests the missing exchange rates of the previous days if the data source supports requesting the exchange rates of a specified date
+func (e *ExchangeRatesDataProviderContainer) StoreExchangeRatesHistory(c core.Context, currentConfig *settings.Config, currentTime time.Time) error {
+	if e.current == nil {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if currentConfig.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
+		return errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	dataSource := currentConfig.ExchangeRatesDataSource
+	today := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.UTC)
+	todayDate := utils.FormatUnixTimeToLongDate(today.Unix(), time.UTC)
+
+	latestExchangeRates, err := e.current.GetLatestExchangeRates(c, 0, currentConfig)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_history.StoreExchangeRatesHistory] failed to get latest exchange rates of \"%s\", because %s", dataSource, err.Error())
+		return err
+	}
+
+	err = services.HistoricalExchangeRates.SaveExchangeRates(c, dataSource, todayDate, latestExchangeRates)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_history.StoreExchangeRatesHistory] failed to save exchange rates of \"%s\" on %s, because %s", dataSource, todayDate, err.Error())
+		return err
+	}
+
+	log.Infof(c, "[exchange_rates_history.StoreExchangeRatesHistory] exchange rates of \"%s\" on %s has been saved", dataSource, todayDate)
+
+	if currentConfig.ExchangeRatesHistoryBackfillDays < 1 || !e.SupportsHistoricalExchangeRates() {
+		return nil
+	}
+
+	startTime := today.AddDate(0, 0, -int(currentConfig.ExchangeRatesHistoryBackfillDays))
+	yesterday := today.AddDate(0, 0, -1)
+	existedRateDates, err := services.HistoricalExchangeRates.GetRateDatesByDateRange(c, dataSource, utils.FormatUnixTimeToLongDate(startTime.Unix(), time.UTC), utils.FormatUnixTimeToLongDate(yesterday.Unix(), time.UTC))
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_history.StoreExchangeRatesHistory] failed to get existed exchange rates dates of \"%s\", because %s", dataSource, err.Error())
+		return err
+	}
+
+	missingDates := make([]time.Time, 0, maxExchangeRatesHistoryBackfillRequestsCount)
+
+	for date := yesterday; !date.Before(startTime) && len(missingDates) < maxExchangeRatesHistoryBackfillRequestsCount; date = date.AddDate(0, 0, -1) {
+		rateDate := utils.FormatUnixTimeToLongDate(date.Unix(), time.UTC)
+
+		if existedRateDates[rateDate] {
+			continue
+		}
+
+		missingDates = append(missingDates, date)
+	}
+
+	if len(missingDates) < 1 {
+		return nil
+	}
+
+	allExchangeRates, err := e.GetExchangeRatesForDates(c, 0, currentConfig, missingDates)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_history.StoreExchangeRatesHistory] failed to get missing exchange rates history of \"%s\", because %s", dataSource, err.Error())
+		return err
+	}
+
+	failedCount := 0
+
+	for i := 0; i < len(missingDates); i++ {
+		rateDate := utils.FormatUnixTimeToLongDate(missingDates[i].Unix(), time.UTC)
+		exchangeRates, exists := allExchangeRates[rateDate]
+
+		if !exists {
+			failedCount++
+			continue
+		}
+
+		err = services.HistoricalExchangeRates.SaveExchangeRates(c, dataSource, rateDate, exchangeRates)
+
+		if err != nil {
+			failedCount++
+			log.Warnf(c, "[exchange_rates_history.StoreExchangeRatesHistory] failed to store exchange rates of \"%s\" on %s, because %s", dataSource, rateDate, err.Error())
+			continue
+		}
+	}
+
+	log.Infof(c, "[exchange_rates_history.StoreExchangeRatesHistory] %d missing exchange rates history of \"%s\" has been stored, %d failed", len(missingDates)-failedCount, dataSource, failedCount)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package exchangerates
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// exchangeRatesMetrics represents the in-memory metrics of requesting latest exchange rates
+type exchangeRatesMetrics struct {
+	cacheHits         int64
+	cacheMisses       int64
+	staleResponses    int64
+	failedResponses   int64
+	dataSources       []string
+	dataSourceMetrics map[string]*models.ExchangeRatesDataSourceMetricsResponse
+	mutex             sync.Mutex
+}
+
+func newExchangeRatesMetrics(dataSources []string) *exchangeRatesMetrics {
+	metrics := &exchangeRatesMetrics{
+		dataSources:       dataSources,
+		dataSourceMetrics: make(map[string]*models.ExchangeRatesDataSourceMetricsResponse, len(dataSources)),
+	}
+
+	for i := 0; i < len(dataSources); i++ {
+		metrics.dataSourceMetrics[dataSources[i]] = &models.ExchangeRatesDataSourceMetricsResponse{
+			DataSource: dataSources[i],
+		}
+	}
+
+	return metrics
+}
+
+func (m *exchangeRatesMetrics) addCacheHit() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cacheHits++
+}
+
+func (m *exchangeRatesMetrics) addCacheMiss() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cacheMisses++
+}
+
+func (m *exchangeRatesMetrics) addStaleResponse() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.staleResponses++
+}
+
+func (m *exchangeRatesMetrics) addFailedResponse() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.failedResponses++
+}
+
+func (m *exchangeRatesMetrics) addServedResponse(dataSource string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if dataSourceMetrics, exists := m.dataSourceMetrics[dataSource]; exists {
+		dataSourceMetrics.ServedResponses++
+	}
+}
+
+func (m *exchangeRatesMetrics) addMergedResponse(dataSource string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if dataSourceMetrics, exists := m.dataSourceMetrics[dataSource]; exists {
+		dataSourceMetrics.MergedResponses++
+	}
+}
+
+func (m *exchangeRatesMetrics) addSucceededRequest(dataSource string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if dataSourceMetrics, exists := m.dataSourceMetrics[dataSource]; exists {
+		dataSourceMetrics.SucceededRequests++
+		dataSourceMetrics.LastSuccessTime = time.Now().Unix()
+	}
+}
+
+func (m *exchangeRatesMetrics) addFailedRequest(dataSource string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if dataSourceMetrics, exists := m.dataSourceMetrics[dataSource]; exists {
+		dataSourceMetrics.FailedRequests++
+		dataSourceMetrics.LastFailureTime = time.Now().Unix()
+	}
+}
+
+// ToExchangeRatesMetricsResponse returns a view-object of the current metrics
+func (m *exchangeRatesMetrics) ToExchangeRatesMetricsResponse() *models.ExchangeRatesMetricsResponse {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	metricsResp := &models.ExchangeRatesMetricsResponse{
+		CacheHits:       m.cacheHits,
+		CacheMisses:     m.cacheMisses,
+		StaleResponses:  m.staleResponses,
+		FailedResponses: m.failedResponses,
+		DataSources:     make([]*models.ExchangeRatesDataSourceMetricsResponse, 0, len(m.dataSources)),
+	}
+
+	for i := 0; i < len(m.dataSources); i++ {
+		dataSourceMetrics := *m.dataSourceMetrics[m.dataSources[i]]
+		metricsResp.DataSources = append(metricsResp.DataSources, &dataSourceMetrics)
+	}
+
+	return metricsResp
+}
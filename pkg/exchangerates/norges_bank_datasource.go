@@ -3,6 +3,7 @@ package exchangerates
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"math"
 	"net/http"
 	"time"
@@ -18,6 +19,7 @@ import (
 )
 
 const norgesBankExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&lastNObservations=1"
+const norgesBankHistoricalExchangeRateUrlFormat = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&startPeriod=%s&endPeriod=%s&lastNObservations=1"
 const norgesBankExchangeRateReferenceUrl = "https://www.norges-bank.no/en/topics/Statistics/exchange_rates/"
 const norgesBankDataSource = "Norges Bank"
 const norgesBankBaseCurrency = "NOK"
@@ -25,6 +27,9 @@ const norgesBankBaseCurrency = "NOK"
 const norgesBankUpdateDateFormat = "2006-01-02 15"
 const norgesBankUpdateDateTimezone = "Europe/Oslo"
 
+// The exchange rates are not published on weekends and holidays, so request the observations of several previous days to find the latest one
+const norgesBankHistoricalExchangeRateLookBackDays = 7
+
 // NorgesBankDataSource defines the structure of exchange rates data source of Norges Bank
 type NorgesBankDataSource struct {
 	HttpExchangeRatesDataSource
@@ -170,6 +175,19 @@ func (e *NorgesBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsForDate returns the Norges Bank exchange rates http requests of the last observations not later than the specified date
+func (e *NorgesBankDataSource) BuildRequestsForDate(date time.Time) ([]*http.Request, error) {
+	startPeriod := utils.FormatUnixTimeToLongDate(date.AddDate(0, 0, -norgesBankHistoricalExchangeRateLookBackDays).Unix(), time.UTC)
+	endPeriod := utils.FormatUnixTimeToLongDate(date.Unix(), time.UTC)
+	req, err := http.NewRequest("GET", fmt.Sprintf(norgesBankHistoricalExchangeRateUrlFormat, startPeriod, endPeriod), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the Norges Bank data source raw response
 func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
@@ -192,3 +210,8 @@ func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.La
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseForDate returns the common response entity of the latest exchange rates published not later than the specified date according to the Norges Bank data source raw response
+func (e *NorgesBankDataSource) ParseForDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	return e.Parse(c, content)
+}
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -220,3 +221,11 @@ func TestNorgesBankDataSource_InvalidUnit(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestNorgesBankDataSource_BuildRequestsForDate(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+
+	requests, err := dataSource.BuildRequestsForDate(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&startPeriod=2024-02-26&endPeriod=2024-03-04&lastNObservations=1", requests[0].URL.String())
+}
@@ -6,10 +6,12 @@ import (
 
 // LocaleTextItems represents all text items need to be translated
 type LocaleTextItems struct {
-	DefaultTypes                *DefaultTypes
-	DataConverterTextItems      *DataConverterTextItems
-	VerifyEmailTextItems        *VerifyEmailTextItems
-	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	DefaultTypes                       *DefaultTypes
+	DataConverterTextItems             *DataConverterTextItems
+	VerifyEmailTextItems               *VerifyEmailTextItems
+	ForgetPasswordMailTextItems        *ForgetPasswordMailTextItems
+	FundInvitationMailTextItems        *FundInvitationMailTextItems
+	CreditCardDueReminderMailTextItems *CreditCardDueReminderMailTextItems
 }
 
 // DefaultTypes represents default types for the language
@@ -41,3 +43,22 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// FundInvitationMailTextItems represents text items need to be translated in fund invitation mail
+type FundInvitationMailTextItems struct {
+	Title                     string
+	SalutationFormat          string
+	DescriptionAboveBtnFormat string
+	ViewInvitation            string
+	DescriptionBelowBtnFormat string
+}
+
+// CreditCardDueReminderMailTextItems represents text items need to be translated in credit card due reminder mail
+type CreditCardDueReminderMailTextItems struct {
+	Title                string
+	SalutationFormat     string
+	DescriptionFormat    string
+	RemainingDueFormat   string
+	MinimumPaymentFormat string
+	Disregard            string
+}
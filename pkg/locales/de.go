@@ -27,4 +27,19 @@ var de = &LocaleTextItems{
 		ResetPassword:             "Passwort zurücksetzen",
 		DescriptionBelowBtnFormat: "Wenn Sie nicht angefordert haben, Ihr Passwort zurückzusetzen, ignorieren Sie bitte diese E-Mail. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Link zum Zurücksetzen des Passworts wird nach %v Minuten ablaufen.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Fonds-Einladung",
+		SalutationFormat:          "Hallo %s,",
+		DescriptionAboveBtnFormat: "%s hat Sie eingeladen, dem Fonds \"%s\" beizutreten. Klicken Sie auf den untenstehenden Link, um die Einladung anzuzeigen und anzunehmen.",
+		ViewInvitation:            "Einladung anzeigen",
+		DescriptionBelowBtnFormat: "Wenn Sie diesem Fonds nicht beitreten möchten, ignorieren Sie bitte diese E-Mail oder lehnen Sie die Einladung nach der Anmeldung ab. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Einladungslink wird nach %v Stunden ablaufen.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Zahlungserinnerung für Kreditkarte",
+		SalutationFormat:     "Hallo %s,",
+		DescriptionFormat:    "Die am %[2]s erstellte Abrechnung Ihres Kreditkartenkontos \"%[1]s\" ist am %[3]s fällig und wurde noch nicht vollständig bezahlt.",
+		RemainingDueFormat:   "Verbleibender fälliger Betrag: %s",
+		MinimumPaymentFormat: "Mindestzahlung: %s",
+		Disregard:            "Wenn Sie die Abrechnung bereits bezahlt haben, ignorieren Sie diese E-Mail bitte einfach.",
+	},
 }
@@ -27,4 +27,19 @@ var en = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Fund Invitation",
+		SalutationFormat:          "Hi %s,",
+		DescriptionAboveBtnFormat: "%s invited you to join the fund \"%s\". You can click the link below to view and accept the invitation.",
+		ViewInvitation:            "View Invitation",
+		DescriptionBelowBtnFormat: "If you do not want to join this fund, please simply disregard this email or decline the invitation after signing in. If you cannot click the link above, please copy the above url and paste it into your browser. The invitation link will be expired after %v hours.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Credit Card Payment Reminder",
+		SalutationFormat:     "Hi %s,",
+		DescriptionFormat:    "The statement of your credit card account \"%s\" issued on %s is due on %s and has not been paid off yet.",
+		RemainingDueFormat:   "Remaining amount due: %s",
+		MinimumPaymentFormat: "Minimum payment: %s",
+		Disregard:            "If you have already paid the statement, please simply disregard this email.",
+	},
 }
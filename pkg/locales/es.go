@@ -27,4 +27,19 @@ var es = &LocaleTextItems{
 		ResetPassword:             "Restablecer Contraseña",
 		DescriptionBelowBtnFormat: "Si no solicitó un restablecimiento de contraseña, simplemente descarte este correo. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegadror. El enlace de restablecimiento de contraseña expira pasados %v minutos.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Invitación a un Fondo",
+		SalutationFormat:          "Hola %s,",
+		DescriptionAboveBtnFormat: "%s le ha invitado a unirse al fondo \"%s\". Haga click en el link de abajo para ver y aceptar la invitación.",
+		ViewInvitation:            "Ver Invitación",
+		DescriptionBelowBtnFormat: "Si no desea unirse a este fondo, simplemente descarte este correo o rechace la invitación después de iniciar sesión. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegador. El enlace de invitación expira pasadas %v horas.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Recordatorio de pago de tarjeta de crédito",
+		SalutationFormat:     "Hola %s,",
+		DescriptionFormat:    "El extracto de su cuenta de tarjeta de crédito \"%s\" emitido el %s vence el %s y aún no se ha pagado por completo.",
+		RemainingDueFormat:   "Importe pendiente: %s",
+		MinimumPaymentFormat: "Pago mínimo: %s",
+		Disregard:            "Si ya ha pagado el extracto, simplemente ignore este correo electrónico.",
+	},
 }
@@ -27,4 +27,19 @@ var fr = &LocaleTextItems{
 		ResetPassword:             "Réinitialiser le mot de passe",
 		DescriptionBelowBtnFormat: "Si vous n'avez pas demandé la réinitialisation de votre mot de passe, vous pouvez ignorer cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien de réinitialisation du mot de passe expire après %v minutes.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Invitation à un fonds",
+		SalutationFormat:          "Bonjour %s,",
+		DescriptionAboveBtnFormat: "%s vous a invité à rejoindre le fonds \"%s\". Vous pouvez cliquer sur le lien ci-dessous pour consulter et accepter l'invitation.",
+		ViewInvitation:            "Voir l'invitation",
+		DescriptionBelowBtnFormat: "Si vous ne souhaitez pas rejoindre ce fonds, vous pouvez ignorer cet e-mail ou refuser l'invitation après vous être connecté. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien d'invitation expire après %v heures.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Rappel de paiement de carte de crédit",
+		SalutationFormat:     "Bonjour %s,",
+		DescriptionFormat:    "Le relevé de votre compte de carte de crédit \"%s\" émis le %s est dû le %s et n'a pas encore été entièrement payé.",
+		RemainingDueFormat:   "Montant restant dû : %s",
+		MinimumPaymentFormat: "Paiement minimum : %s",
+		Disregard:            "Si vous avez déjà payé le relevé, veuillez simplement ignorer cet e-mail.",
+	},
 }
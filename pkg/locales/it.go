@@ -27,4 +27,19 @@ var it = &LocaleTextItems{
 		ResetPassword:             "Reimposta password",
 		DescriptionBelowBtnFormat: "Se non hai chiesto alcun cambio della password, puoi ignorare questa mail. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di verifica scadrà tra %v minuti.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Invito al fondo",
+		SalutationFormat:          "Ciao %s,",
+		DescriptionAboveBtnFormat: "%s ti ha invitato a unirti al fondo \"%s\". Clicca sul link qui sotto per visualizzare e accettare l'invito.",
+		ViewInvitation:            "Visualizza invito",
+		DescriptionBelowBtnFormat: "Se non vuoi unirti a questo fondo, puoi ignorare questa mail o rifiutare l'invito dopo aver effettuato l'accesso. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di invito scadrà tra %v ore.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Promemoria di pagamento della carta di credito",
+		SalutationFormat:     "Ciao %s,",
+		DescriptionFormat:    "L'estratto conto del tuo conto carta di credito \"%s\" emesso il %s scade il %s e non è ancora stato saldato.",
+		RemainingDueFormat:   "Importo residuo dovuto: %s",
+		MinimumPaymentFormat: "Pagamento minimo: %s",
+		Disregard:            "Se hai già pagato l'estratto conto, ignora semplicemente questa email.",
+	},
 }
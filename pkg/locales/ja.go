@@ -27,4 +27,19 @@ var ja = &LocaleTextItems{
 		ResetPassword:             "パスワードをリセット",
 		DescriptionBelowBtnFormat: "パスワードのリセットをリクエストしていない場合はこのメールを無視してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。パスワードリセットのリンクは%v分後に期限切れになります。",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "ファンドへの招待",
+		SalutationFormat:          "こんにちは%s,",
+		DescriptionAboveBtnFormat: "%sさんからファンド「%s」への招待が届いています。下のリンクをクリックして招待を確認し、承諾してください。",
+		ViewInvitation:            "招待を表示",
+		DescriptionBelowBtnFormat: "このファンドに参加しない場合は、このメールを無視するか、ログイン後に招待を辞退してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。招待リンクは%v時間後に期限切れになります。",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "クレジットカードの支払いリマインダー",
+		SalutationFormat:     "こんにちは%s,",
+		DescriptionFormat:    "%[2]s に発行されたクレジットカード口座「%[1]s」の明細の支払期日は %[3]s ですが、まだ全額支払われていません。",
+		RemainingDueFormat:   "残りの支払額：%s",
+		MinimumPaymentFormat: "最低支払額：%s",
+		Disregard:            "すでに明細をお支払い済みの場合は、このメールを無視してください。",
+	},
 }
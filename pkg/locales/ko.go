@@ -27,4 +27,19 @@ var ko = &LocaleTextItems{
 		ResetPassword:             "비밀번호 재설정",
 		DescriptionBelowBtnFormat: "비밀번호 재설정을 요청하지 않으셨다면 이 이메일을 무시해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 비밀번호 재설정 링크는 %v분 후에 만료됩니다.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "펀드 초대",
+		SalutationFormat:          "안녕하세요 %s님,",
+		DescriptionAboveBtnFormat: "%s님이 펀드 \"%s\"에 참여하도록 초대했습니다. 아래 링크를 클릭하여 초대를 확인하고 수락해주세요.",
+		ViewInvitation:            "초대 보기",
+		DescriptionBelowBtnFormat: "이 펀드에 참여하지 않으려면 이 이메일을 무시하거나 로그인 후 초대를 거절해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 초대 링크는 %v시간 후에 만료됩니다.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "신용카드 결제 알림",
+		SalutationFormat:     "안녕하세요 %s님,",
+		DescriptionFormat:    "%[2]s에 발행된 신용카드 계좌 \"%[1]s\"의 명세서 결제일은 %[3]s이며 아직 전액 결제되지 않았습니다.",
+		RemainingDueFormat:   "남은 결제 금액: %s",
+		MinimumPaymentFormat: "최소 결제 금액: %s",
+		Disregard:            "이미 명세서를 결제하셨다면 이 이메일을 무시하십시오.",
+	},
 }
@@ -27,4 +27,19 @@ var nl = &LocaleTextItems{
 		ResetPassword:             "Wachtwoord opnieuw instellen",
 		DescriptionBelowBtnFormat: "Als je geen verzoek hebt gedaan om je wachtwoord te resetten, kun je deze e-mail negeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De link voor het opnieuw instellen van het wachtwoord verloopt na  %v minuten.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Uitnodiging voor fonds",
+		SalutationFormat:          "Hallo %s,",
+		DescriptionAboveBtnFormat: "%s heeft je uitgenodigd om lid te worden van het fonds \"%s\". Klik op de onderstaande link om de uitnodiging te bekijken en te accepteren.",
+		ViewInvitation:            "Uitnodiging bekijken",
+		DescriptionBelowBtnFormat: "Als je geen lid wilt worden van dit fonds, kun je deze e-mail negeren of de uitnodiging na het inloggen weigeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De uitnodigingslink verloopt na %v uur.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Betalingsherinnering creditcard",
+		SalutationFormat:     "Hallo %s,",
+		DescriptionFormat:    "Het overzicht van uw creditcardrekening \"%s\" van %s moet uiterlijk %s worden betaald en is nog niet volledig voldaan.",
+		RemainingDueFormat:   "Resterend verschuldigd bedrag: %s",
+		MinimumPaymentFormat: "Minimale betaling: %s",
+		Disregard:            "Als u het overzicht al heeft betaald, kunt u deze e-mail negeren.",
+	},
 }
@@ -27,4 +27,19 @@ var ptBR = &LocaleTextItems{
 		ResetPassword:             "Redefinir Senha",
 		DescriptionBelowBtnFormat: "Se você não solicitou a redefinição de senha, basta ignorar este e-mail. Se não conseguir clicar no link acima, copie a URL acima e cole no seu navegador. O link de redefinição de senha expirará após %v minutos.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Convite para Fundo",
+		SalutationFormat:          "Olá %s,",
+		DescriptionAboveBtnFormat: "%s convidou você para participar do fundo \"%s\". Clique no link abaixo para ver e aceitar o convite.",
+		ViewInvitation:            "Ver Convite",
+		DescriptionBelowBtnFormat: "Se você não deseja participar deste fundo, basta ignorar este e-mail ou recusar o convite após entrar. Se não conseguir clicar no link acima, copie a URL acima e cole no seu navegador. O link de convite expirará após %v horas.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Lembrete de pagamento do cartão de crédito",
+		SalutationFormat:     "Olá %s,",
+		DescriptionFormat:    "A fatura da sua conta de cartão de crédito \"%s\" emitida em %s vence em %s e ainda não foi totalmente paga.",
+		RemainingDueFormat:   "Valor restante devido: %s",
+		MinimumPaymentFormat: "Pagamento mínimo: %s",
+		Disregard:            "Se você já pagou a fatura, simplesmente ignore este e-mail.",
+	},
 }
@@ -27,4 +27,19 @@ var ru = &LocaleTextItems{
 		ResetPassword:             "Сбросить пароль",
 		DescriptionBelowBtnFormat: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка для сброса пароля истечет через %v минут.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Приглашение в фонд",
+		SalutationFormat:          "Здравствуйте %s,",
+		DescriptionAboveBtnFormat: "%s пригласил вас присоединиться к фонду \"%s\". Нажмите на ссылку ниже, чтобы просмотреть и принять приглашение.",
+		ViewInvitation:            "Просмотреть приглашение",
+		DescriptionBelowBtnFormat: "Если вы не хотите присоединяться к этому фонду, просто проигнорируйте это письмо или отклоните приглашение после входа. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка приглашения истечет через %v часов.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Напоминание об оплате кредитной карты",
+		SalutationFormat:     "Здравствуйте %s,",
+		DescriptionFormat:    "Выписка по вашему счёту кредитной карты \"%s\" от %s должна быть оплачена до %s и ещё не погашена полностью.",
+		RemainingDueFormat:   "Оставшаяся сумма к оплате: %s",
+		MinimumPaymentFormat: "Минимальный платёж: %s",
+		Disregard:            "Если вы уже оплатили выписку, просто проигнорируйте это письмо.",
+	},
 }
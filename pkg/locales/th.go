@@ -27,4 +27,19 @@ var th = &LocaleTextItems{
 		ResetPassword:             "ตั้งรหัสผ่านใหม่",
 		DescriptionBelowBtnFormat: "หากคุณไม่ได้ร้องขอให้รีเซ็ตรหัสผ่าน โปรดละเว้นอีเมลนี้ หากคุณไม่สามารถคลิกลิงก์ด้านบน โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ ลิงก์รีเซ็ตรหัสผ่านจะหมดอายุหลังจาก %v นาที",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "คำเชิญเข้าร่วมกองทุน",
+		SalutationFormat:          "สวัสดี %s,",
+		DescriptionAboveBtnFormat: "%s ได้เชิญคุณเข้าร่วมกองทุน \"%s\" โปรดคลิกลิงก์ด้านล่างเพื่อดูและยอมรับคำเชิญ",
+		ViewInvitation:            "ดูคำเชิญ",
+		DescriptionBelowBtnFormat: "หากคุณไม่ต้องการเข้าร่วมกองทุนนี้ โปรดละเว้นอีเมลนี้หรือปฏิเสธคำเชิญหลังจากเข้าสู่ระบบ หากคุณไม่สามารถคลิกลิงก์ด้านบน โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ ลิงก์คำเชิญจะหมดอายุหลังจาก %v ชั่วโมง",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "การแจ้งเตือนการชำระบัตรเครดิต",
+		SalutationFormat:     "สวัสดี %s,",
+		DescriptionFormat:    "ใบแจ้งยอดของบัญชีบัตรเครดิต \"%s\" ที่ออกเมื่อ %s ครบกำหนดชำระในวันที่ %s และยังไม่ได้ชำระเต็มจำนวน",
+		RemainingDueFormat:   "ยอดค้างชำระคงเหลือ: %s",
+		MinimumPaymentFormat: "ยอดชำระขั้นต่ำ: %s",
+		Disregard:            "หากคุณชำระใบแจ้งยอดแล้ว โปรดเพิกเฉยต่ออีเมลนี้",
+	},
 }
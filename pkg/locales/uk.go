@@ -27,4 +27,19 @@ var uk = &LocaleTextItems{
 		ResetPassword:             "Скинути пароль",
 		DescriptionBelowBtnFormat: "Якщо ви не надсилали запит на скидання пароля, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання для скидання пароля буде дійсне протягом %v хвилин.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Запрошення до фонду",
+		SalutationFormat:          "Вітаємо, %s!",
+		DescriptionAboveBtnFormat: "%s запросив вас приєднатися до фонду \"%s\". Натисніть на посилання нижче, щоб переглянути та прийняти запрошення.",
+		ViewInvitation:            "Переглянути запрошення",
+		DescriptionBelowBtnFormat: "Якщо ви не хочете приєднуватися до цього фонду, просто проігноруйте цей лист або відхиліть запрошення після входу. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання запрошення буде дійсне протягом %v годин.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Нагадування про оплату кредитної картки",
+		SalutationFormat:     "Вітаємо, %s!",
+		DescriptionFormat:    "Виписка за вашим рахунком кредитної картки \"%s\" від %s має бути сплачена до %s і ще не погашена повністю.",
+		RemainingDueFormat:   "Залишок суми до сплати: %s",
+		MinimumPaymentFormat: "Мінімальний платіж: %s",
+		Disregard:            "Якщо ви вже сплатили виписку, просто проігноруйте цей лист.",
+	},
 }
@@ -27,4 +27,19 @@ var vi = &LocaleTextItems{
 		ResetPassword:             "Đặt lại Mật khẩu",
 		DescriptionBelowBtnFormat: "Nếu bạn không yêu cầu đặt lại mật khẩu, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết đặt lại mật khẩu sẽ hết hạn sau %v phút.",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "Lời mời tham gia Quỹ",
+		SalutationFormat:          "Chào %s,",
+		DescriptionAboveBtnFormat: "%s đã mời bạn tham gia quỹ \"%s\". Vui lòng nhấp vào liên kết bên dưới để xem và chấp nhận lời mời.",
+		ViewInvitation:            "Xem Lời mời",
+		DescriptionBelowBtnFormat: "Nếu bạn không muốn tham gia quỹ này, vui lòng bỏ qua email này hoặc từ chối lời mời sau khi đăng nhập. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết mời sẽ hết hạn sau %v giờ.",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "Nhắc nhở thanh toán thẻ tín dụng",
+		SalutationFormat:     "Chào %s,",
+		DescriptionFormat:    "Sao kê của tài khoản thẻ tín dụng \"%s\" phát hành ngày %s đến hạn thanh toán vào ngày %s và chưa được thanh toán hết.",
+		RemainingDueFormat:   "Số tiền còn phải trả: %s",
+		MinimumPaymentFormat: "Thanh toán tối thiểu: %s",
+		Disregard:            "Nếu bạn đã thanh toán sao kê, vui lòng bỏ qua email này.",
+	},
 }
@@ -27,4 +27,19 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "基金邀请",
+		SalutationFormat:          "%s 您好，",
+		DescriptionAboveBtnFormat: "%s 邀请您加入基金“%s”。您可以点击下方的链接查看并接受邀请。",
+		ViewInvitation:            "查看邀请",
+		DescriptionBelowBtnFormat: "如果您不想加入该基金，请直接忽略本邮件，或在登录后拒绝邀请。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。邀请链接将在 %v 小时后过期。",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "信用卡还款提醒",
+		SalutationFormat:     "%s 您好，",
+		DescriptionFormat:    "您的信用卡账户“%[1]s”于 %[2]s 出具的账单将于 %[3]s 到期，目前尚未还清。",
+		RemainingDueFormat:   "剩余应还金额：%s",
+		MinimumPaymentFormat: "最低还款额：%s",
+		Disregard:            "如果您已经还清该账单，请直接忽略本邮件。",
+	},
 }
@@ -27,4 +27,19 @@ var zhHant = &LocaleTextItems{
 		ResetPassword:             "重設密碼",
 		DescriptionBelowBtnFormat: "如果您沒有請求重設密碼，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。重設密碼連結將在 %v 分鐘後過期。",
 	},
+	FundInvitationMailTextItems: &FundInvitationMailTextItems{
+		Title:                     "基金邀請",
+		SalutationFormat:          "%s 您好，",
+		DescriptionAboveBtnFormat: "%s 邀請您加入基金「%s」。您可以點擊下方的連結檢視並接受邀請。",
+		ViewInvitation:            "檢視邀請",
+		DescriptionBelowBtnFormat: "如果您不想加入該基金，請直接忽略本郵件，或在登入後拒絕邀請。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。邀請連結將在 %v 小時後過期。",
+	},
+	CreditCardDueReminderMailTextItems: &CreditCardDueReminderMailTextItems{
+		Title:                "信用卡還款提醒",
+		SalutationFormat:     "%s 您好，",
+		DescriptionFormat:    "您的信用卡帳戶「%[1]s」於 %[2]s 出具的帳單將於 %[3]s 到期，目前尚未還清。",
+		RemainingDueFormat:   "剩餘應還金額：%s",
+		MinimumPaymentFormat: "最低還款額：%s",
+		Disregard:            "如果您已經還清該帳單，請直接忽略本郵件。",
+	},
 }
@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ExchangeRatesMetricsIpLimit limits access to the exchange rates metrics based on IP address.
+func ExchangeRatesMetricsIpLimit(config *settings.Config) core.MiddlewareHandlerFunc {
+	return func(c *core.WebContext) {
+		for i := 0; i < len(config.ExchangeRatesMetricsAllowedRemoteIPs); i++ {
+			if config.ExchangeRatesMetricsAllowedRemoteIPs[i].Match(c.ClientIP()) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.PrintJsonErrorResult(c, errs.ErrIPForbidden)
+	}
+}
@@ -91,40 +91,58 @@ type Account struct {
 
 // AccountExtend represents account extend data stored in database
 type AccountExtend struct {
-	CreditCardStatementDate *int `json:"creditCardStatementDate"`
+	CreditCardStatementDate        *int             `json:"creditCardStatementDate"`
+	CreditCardLimit                *int64           `json:"creditCardLimit,omitempty"`
+	CreditCardPaymentDueDays       *int             `json:"creditCardPaymentDueDays,omitempty"`
+	CreditCardMinimumPaymentRate   *int64           `json:"creditCardMinimumPaymentRate,omitempty"`
+	CreditCardMinimumPaymentAmount *int64           `json:"creditCardMinimumPaymentAmount,omitempty"`
+	CreditCardDueReminderDays      *int             `json:"creditCardDueReminderDays,omitempty"`
+	Loan                           *AccountLoanInfo `json:"loan,omitempty"`
 }
 
 // AccountCreateRequest represents all parameters of account creation request
 type AccountCreateRequest struct {
-	Name                    string                  `json:"name" binding:"required,notBlank,max=64"`
-	Category                AccountCategory         `json:"category" binding:"required"`
-	Type                    AccountType             `json:"type" binding:"required"`
-	Icon                    int64                   `json:"icon,string" binding:"required,min=1"`
-	Color                   string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
-	Currency                string                  `json:"currency" binding:"required,len=3,validCurrency"`
-	Balance                 int64                   `json:"balance"`
-	BalanceTime             int64                   `json:"balanceTime"`
-	Comment                 string                  `json:"comment" binding:"max=255"`
-	CreditCardStatementDate int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
-	SubAccounts             []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
-	ClientSessionId         string                  `json:"clientSessionId"`
+	Name                           string                  `json:"name" binding:"required,notBlank,max=64"`
+	Category                       AccountCategory         `json:"category" binding:"required"`
+	Type                           AccountType             `json:"type" binding:"required"`
+	Icon                           int64                   `json:"icon,string" binding:"required,min=1"`
+	Color                          string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
+	Currency                       string                  `json:"currency" binding:"required,len=3,validCurrency"`
+	Balance                        int64                   `json:"balance"`
+	BalanceTime                    int64                   `json:"balanceTime"`
+	Comment                        string                  `json:"comment" binding:"max=255"`
+	CreditCardStatementDate        int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
+	CreditCardLimit                int64                   `json:"creditCardLimit" binding:"min=0,max=99999999999"`
+	CreditCardPaymentDueDays       int                     `json:"creditCardPaymentDueDays" binding:"min=0,max=60"`
+	CreditCardMinimumPaymentRate   int64                   `json:"creditCardMinimumPaymentRate" binding:"min=0,max=10000"`
+	CreditCardMinimumPaymentAmount int64                   `json:"creditCardMinimumPaymentAmount" binding:"min=0,max=99999999999"`
+	CreditCardDueReminderDays      int                     `json:"creditCardDueReminderDays" binding:"min=0,max=30"`
+	Loan                           *AccountLoanRequest     `json:"loan" binding:"omitempty"`
+	SubAccounts                    []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
+	ClientSessionId                string                  `json:"clientSessionId"`
 }
 
 // AccountModifyRequest represents all parameters of account modification request
 type AccountModifyRequest struct {
-	Id                      int64                   `json:"id,string" binding:"required,min=0"`
-	Name                    string                  `json:"name" binding:"required,notBlank,max=64"`
-	Category                AccountCategory         `json:"category" binding:"required"`
-	Icon                    int64                   `json:"icon,string" binding:"min=1"`
-	Color                   string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
-	Currency                *string                 `json:"currency" binding:"omitempty,len=3,validCurrency"`
-	Balance                 *int64                  `json:"balance" binding:"omitempty"`
-	BalanceTime             *int64                  `json:"balanceTime" binding:"omitempty"`
-	Comment                 string                  `json:"comment" binding:"max=255"`
-	CreditCardStatementDate int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
-	Hidden                  bool                    `json:"hidden"`
-	SubAccounts             []*AccountModifyRequest `json:"subAccounts" binding:"omitempty"`
-	ClientSessionId         string                  `json:"clientSessionId"`
+	Id                             int64                   `json:"id,string" binding:"required,min=0"`
+	Name                           string                  `json:"name" binding:"required,notBlank,max=64"`
+	Category                       AccountCategory         `json:"category" binding:"required"`
+	Icon                           int64                   `json:"icon,string" binding:"min=1"`
+	Color                          string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
+	Currency                       *string                 `json:"currency" binding:"omitempty,len=3,validCurrency"`
+	Balance                        *int64                  `json:"balance" binding:"omitempty"`
+	BalanceTime                    *int64                  `json:"balanceTime" binding:"omitempty"`
+	Comment                        string                  `json:"comment" binding:"max=255"`
+	CreditCardStatementDate        int                     `json:"creditCardStatementDate" binding:"min=0,max=28"`
+	CreditCardLimit                int64                   `json:"creditCardLimit" binding:"min=0,max=99999999999"`
+	CreditCardPaymentDueDays       int                     `json:"creditCardPaymentDueDays" binding:"min=0,max=60"`
+	CreditCardMinimumPaymentRate   int64                   `json:"creditCardMinimumPaymentRate" binding:"min=0,max=10000"`
+	CreditCardMinimumPaymentAmount int64                   `json:"creditCardMinimumPaymentAmount" binding:"min=0,max=99999999999"`
+	CreditCardDueReminderDays      int                     `json:"creditCardDueReminderDays" binding:"min=0,max=30"`
+	Loan                           *AccountLoanRequest     `json:"loan" binding:"omitempty"`
+	Hidden                         bool                    `json:"hidden"`
+	SubAccounts                    []*AccountModifyRequest `json:"subAccounts" binding:"omitempty"`
+	ClientSessionId                string                  `json:"clientSessionId"`
 }
 
 // AccountListRequest represents all parameters of account listing request
@@ -161,53 +179,155 @@ type AccountDeleteRequest struct {
 
 // AccountInfoResponse represents a view-object of account
 type AccountInfoResponse struct {
-	Id                      int64                    `json:"id,string"`
-	Name                    string                   `json:"name"`
-	ParentId                int64                    `json:"parentId,string"`
-	Category                AccountCategory          `json:"category"`
-	Type                    AccountType              `json:"type"`
-	Icon                    int64                    `json:"icon,string"`
-	Color                   string                   `json:"color"`
-	Currency                string                   `json:"currency"`
-	Balance                 int64                    `json:"balance"`
-	Comment                 string                   `json:"comment"`
-	CreditCardStatementDate *int                     `json:"creditCardStatementDate,omitempty"`
-	DisplayOrder            int32                    `json:"displayOrder"`
-	IsAsset                 bool                     `json:"isAsset,omitempty"`
-	IsLiability             bool                     `json:"isLiability,omitempty"`
-	Hidden                  bool                     `json:"hidden"`
-	SubAccounts             AccountInfoResponseSlice `json:"subAccounts,omitempty"`
+	Id                             int64                    `json:"id,string"`
+	Name                           string                   `json:"name"`
+	ParentId                       int64                    `json:"parentId,string"`
+	Category                       AccountCategory          `json:"category"`
+	Type                           AccountType              `json:"type"`
+	Icon                           int64                    `json:"icon,string"`
+	Color                          string                   `json:"color"`
+	Currency                       string                   `json:"currency"`
+	Balance                        int64                    `json:"balance"`
+	Comment                        string                   `json:"comment"`
+	CreditCardStatementDate        *int                     `json:"creditCardStatementDate,omitempty"`
+	CreditCardLimit                *int64                   `json:"creditCardLimit,omitempty"`
+	CreditCardPaymentDueDays       *int                     `json:"creditCardPaymentDueDays,omitempty"`
+	CreditCardMinimumPaymentRate   *int64                   `json:"creditCardMinimumPaymentRate,omitempty"`
+	CreditCardMinimumPaymentAmount *int64                   `json:"creditCardMinimumPaymentAmount,omitempty"`
+	CreditCardDueReminderDays      *int                     `json:"creditCardDueReminderDays,omitempty"`
+	Loan                           *AccountLoanInfo         `json:"loan,omitempty"`
+	DisplayOrder                   int32                    `json:"displayOrder"`
+	IsAsset                        bool                     `json:"isAsset,omitempty"`
+	IsLiability                    bool                     `json:"isLiability,omitempty"`
+	Hidden                         bool                     `json:"hidden"`
+	SubAccounts                    AccountInfoResponseSlice `json:"subAccounts,omitempty"`
 }
 
 // ToAccountInfoResponse returns a view-object according to database model
 func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	var creditCardStatementDate *int
+	var creditCardLimit *int64
+	var creditCardPaymentDueDays *int
+	var creditCardMinimumPaymentRate *int64
+	var creditCardMinimumPaymentAmount *int64
+	var creditCardDueReminderDays *int
+	var loanInfo *AccountLoanInfo
 
 	if a.ParentAccountId == LevelOneAccountParentId && a.Category == ACCOUNT_CATEGORY_CREDIT_CARD {
 		if a.Extend != nil {
 			creditCardStatementDate = a.Extend.CreditCardStatementDate
+			creditCardLimit = a.Extend.CreditCardLimit
+			creditCardPaymentDueDays = a.Extend.CreditCardPaymentDueDays
+			creditCardMinimumPaymentRate = a.Extend.CreditCardMinimumPaymentRate
+			creditCardMinimumPaymentAmount = a.Extend.CreditCardMinimumPaymentAmount
+			creditCardDueReminderDays = a.Extend.CreditCardDueReminderDays
 		} else {
 			creditCardStatementDate = &defaultCreditCardAccountStatementDate
 		}
 	}
 
+	if a.ParentAccountId == LevelOneAccountParentId && a.Category == ACCOUNT_CATEGORY_DEBT {
+		loanInfo = a.GetLoanInfo()
+	}
+
 	return &AccountInfoResponse{
-		Id:                      a.AccountId,
-		Name:                    a.Name,
-		ParentId:                a.ParentAccountId,
-		Category:                a.Category,
-		Type:                    a.Type,
-		Icon:                    a.Icon,
-		Color:                   a.Color,
-		Currency:                a.Currency,
-		Balance:                 a.Balance,
-		Comment:                 a.Comment,
-		CreditCardStatementDate: creditCardStatementDate,
-		DisplayOrder:            a.DisplayOrder,
-		IsAsset:                 assetAccountCategory[a.Category],
-		IsLiability:             liabilityAccountCategory[a.Category],
-		Hidden:                  a.Hidden,
+		Id:                             a.AccountId,
+		Name:                           a.Name,
+		ParentId:                       a.ParentAccountId,
+		Category:                       a.Category,
+		Type:                           a.Type,
+		Icon:                           a.Icon,
+		Color:                          a.Color,
+		Currency:                       a.Currency,
+		Balance:                        a.Balance,
+		Comment:                        a.Comment,
+		CreditCardStatementDate:        creditCardStatementDate,
+		CreditCardLimit:                creditCardLimit,
+		CreditCardPaymentDueDays:       creditCardPaymentDueDays,
+		CreditCardMinimumPaymentRate:   creditCardMinimumPaymentRate,
+		CreditCardMinimumPaymentAmount: creditCardMinimumPaymentAmount,
+		CreditCardDueReminderDays:      creditCardDueReminderDays,
+		Loan:                           loanInfo,
+		DisplayOrder:                   a.DisplayOrder,
+		IsAsset:                        assetAccountCategory[a.Category],
+		IsLiability:                    liabilityAccountCategory[a.Category],
+		Hidden:                         a.Hidden,
+	}
+}
+
+// HasCreditCardPaymentSettings returns whether any payment setting of credit card is set in the account creation request
+func (r *AccountCreateRequest) HasCreditCardPaymentSettings() bool {
+	return r.CreditCardPaymentDueDays != 0 || r.CreditCardMinimumPaymentRate != 0 || r.CreditCardMinimumPaymentAmount != 0 || r.CreditCardDueReminderDays != 0
+}
+
+// HasCreditCardPaymentSettings returns whether any payment setting of credit card is set in the account modification request
+func (r *AccountModifyRequest) HasCreditCardPaymentSettings() bool {
+	return r.CreditCardPaymentDueDays != 0 || r.CreditCardMinimumPaymentRate != 0 || r.CreditCardMinimumPaymentAmount != 0 || r.CreditCardDueReminderDays != 0
+}
+
+// GetCreditCardLimit returns the credit limit of the credit card account, or zero if it is not set
+func (a *Account) GetCreditCardLimit() int64 {
+	if a.Category != ACCOUNT_CATEGORY_CREDIT_CARD || a.Extend == nil || a.Extend.CreditCardLimit == nil {
+		return 0
 	}
+
+	return *a.Extend.CreditCardLimit
+}
+
+// GetCreditCardStatementDate returns the statement date of the credit card account, or zero if it is not set
+func (a *Account) GetCreditCardStatementDate() int {
+	if a.Category != ACCOUNT_CATEGORY_CREDIT_CARD || a.Extend == nil || a.Extend.CreditCardStatementDate == nil {
+		return 0
+	}
+
+	return *a.Extend.CreditCardStatementDate
+}
+
+// GetCreditCardPaymentDueDays returns the days between the statement date and the payment due date of the credit card account, or zero if it is not set
+func (a *Account) GetCreditCardPaymentDueDays() int {
+	if a.Category != ACCOUNT_CATEGORY_CREDIT_CARD || a.Extend == nil || a.Extend.CreditCardPaymentDueDays == nil {
+		return 0
+	}
+
+	return *a.Extend.CreditCardPaymentDueDays
+}
+
+// GetCreditCardMinimumPayment returns the minimum payment rate and the minimum payment amount of the credit card account, or zero if they are not set
+func (a *Account) GetCreditCardMinimumPayment() (int64, int64) {
+	if a.Category != ACCOUNT_CATEGORY_CREDIT_CARD || a.Extend == nil {
+		return 0, 0
+	}
+
+	minimumPaymentRate := int64(0)
+	minimumPaymentAmount := int64(0)
+
+	if a.Extend.CreditCardMinimumPaymentRate != nil {
+		minimumPaymentRate = *a.Extend.CreditCardMinimumPaymentRate
+	}
+
+	if a.Extend.CreditCardMinimumPaymentAmount != nil {
+		minimumPaymentAmount = *a.Extend.CreditCardMinimumPaymentAmount
+	}
+
+	return minimumPaymentRate, minimumPaymentAmount
+}
+
+// GetCreditCardDueReminderDays returns the days before the payment due date to send reminder of the credit card account, or zero if it is not set
+func (a *Account) GetCreditCardDueReminderDays() int {
+	if a.Category != ACCOUNT_CATEGORY_CREDIT_CARD || a.Extend == nil || a.Extend.CreditCardDueReminderDays == nil {
+		return 0
+	}
+
+	return *a.Extend.CreditCardDueReminderDays
+}
+
+// GetLoanInfo returns the loan info of the debt account, or nil if it is not set
+func (a *Account) GetLoanInfo() *AccountLoanInfo {
+	if a.Category != ACCOUNT_CATEGORY_DEBT || a.Extend == nil {
+		return nil
+	}
+
+	return a.Extend.Loan
 }
 
 // FromDB fills the fields from the data stored in database
@@ -0,0 +1,239 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// AccountBalanceHistoryPeriodType represents the period type of account balance history
+type AccountBalanceHistoryPeriodType byte
+
+// Account balance history period types
+const (
+	ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY   AccountBalanceHistoryPeriodType = 1
+	ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_WEEKLY  AccountBalanceHistoryPeriodType = 2
+	ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_MONTHLY AccountBalanceHistoryPeriodType = 3
+)
+
+// MaximumAccountBalanceHistoryPeriodsCount represents the maximum count of periods in account balance history request
+const MaximumAccountBalanceHistoryPeriodsCount = 400
+
+// AccountBalanceHistoryRequest represents all parameters of account balance history request
+type AccountBalanceHistoryRequest struct {
+	StartTime  int64                           `form:"start_time" binding:"required,min=1"` // Unix time
+	EndTime    int64                           `form:"end_time" binding:"required,min=1"`   // Unix time
+	PeriodType AccountBalanceHistoryPeriodType `form:"period_type" binding:"required,min=1,max=3"`
+}
+
+// AccountBalanceHistoryPeriod represents one period of account balance history
+type AccountBalanceHistoryPeriod struct {
+	EndDate string
+	EndTime int64
+}
+
+// AccountBalanceHistoryItemResponse represents a view-object of the end-of-period balances of one account
+type AccountBalanceHistoryItemResponse struct {
+	AccountId int64   `json:"accountId,string"`
+	Currency  string  `json:"currency"`
+	Balances  []int64 `json:"balances"`
+}
+
+// AccountBalanceHistoryResponse represents a view-object of the end-of-period balances of all accounts and the net worth in default currency
+type AccountBalanceHistoryResponse struct {
+	DefaultCurrency  string                               `json:"defaultCurrency"`
+	PeriodEndDates   []string                             `json:"periodEndDates"`
+	Accounts         []*AccountBalanceHistoryItemResponse `json:"accounts"`
+	TotalAssets      []int64                              `json:"totalAssets"`
+	TotalLiabilities []int64                              `json:"totalLiabilities"`
+	NetWorth         []int64                              `json:"netWorth"`
+}
+
+// AccountBalanceHistoryBuilder represents a builder which computes the end-of-period balances of accounts
+// by walking the transactions backwards from the current balances
+type AccountBalanceHistoryBuilder struct {
+	periods         []*AccountBalanceHistoryPeriod
+	balances        map[int64]int64
+	history         map[int64][]int64
+	nextPeriodIndex int
+}
+
+// GetAccountBalanceHistoryPeriods returns all periods which overlap the specified time range in ascending order,
+// weekly periods start from the specified first day of week
+func GetAccountBalanceHistoryPeriods(startTime int64, endTime int64, periodType AccountBalanceHistoryPeriodType, firstDayOfWeek core.WeekDay, timezone *time.Location) ([]*AccountBalanceHistoryPeriod, error) {
+	if startTime > endTime {
+		return nil, errs.ErrAccountBalanceHistoryTimeRangeInvalid
+	}
+
+	startDateTime := time.Unix(startTime, 0).In(timezone)
+	periodStartTime := time.Date(startDateTime.Year(), startDateTime.Month(), startDateTime.Day(), 0, 0, 0, 0, timezone)
+
+	switch periodType {
+	case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY:
+	case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_WEEKLY:
+		periodStartTime = periodStartTime.AddDate(0, 0, -((int(periodStartTime.Weekday()) - int(firstDayOfWeek) + 7) % 7))
+	case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_MONTHLY:
+		periodStartTime = time.Date(periodStartTime.Year(), periodStartTime.Month(), 1, 0, 0, 0, 0, timezone)
+	default:
+		return nil, errs.ErrAccountBalanceHistoryPeriodTypeInvalid
+	}
+
+	periods := make([]*AccountBalanceHistoryPeriod, 0)
+
+	for periodStartTime.Unix() <= endTime {
+		if len(periods) >= MaximumAccountBalanceHistoryPeriodsCount {
+			return nil, errs.ErrTooManyAccountBalanceHistoryPeriods
+		}
+
+		var nextPeriodStartTime time.Time
+
+		switch periodType {
+		case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY:
+			nextPeriodStartTime = periodStartTime.AddDate(0, 0, 1)
+		case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_WEEKLY:
+			nextPeriodStartTime = periodStartTime.AddDate(0, 0, 7)
+		case ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_MONTHLY:
+			nextPeriodStartTime = periodStartTime.AddDate(0, 1, 0)
+		}
+
+		periodEndTime := nextPeriodStartTime.Unix() - 1
+
+		periods = append(periods, &AccountBalanceHistoryPeriod{
+			EndDate: utils.FormatUnixTimeToLongDate(periodEndTime, timezone),
+			EndTime: periodEndTime,
+		})
+
+		periodStartTime = nextPeriodStartTime
+	}
+
+	return periods, nil
+}
+
+// NewAccountBalanceHistoryBuilder returns a new account balance history builder which starts from the current balances of the specified accounts
+func NewAccountBalanceHistoryBuilder(accounts []*Account, periods []*AccountBalanceHistoryPeriod) *AccountBalanceHistoryBuilder {
+	builder := &AccountBalanceHistoryBuilder{
+		periods:         periods,
+		balances:        make(map[int64]int64, len(accounts)),
+		history:         make(map[int64][]int64, len(accounts)),
+		nextPeriodIndex: len(periods) - 1,
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		builder.balances[accounts[i].AccountId] = accounts[i].Balance
+		builder.history[accounts[i].AccountId] = make([]int64, len(periods))
+	}
+
+	return builder
+}
+
+// GetMinUnixTime returns the earliest unix time of the transactions which affect the balance history
+func (b *AccountBalanceHistoryBuilder) GetMinUnixTime() int64 {
+	if len(b.periods) < 1 {
+		return 0
+	}
+
+	return b.periods[0].EndTime + 1
+}
+
+// Walk reverts the transactions from the current balances and records the balances at the end of each passed period,
+// the transactions must be sorted by transaction time in descending order and be later than all the walked transactions,
+// returns whether the earlier transactions are still required
+func (b *AccountBalanceHistoryBuilder) Walk(transactions []*Transaction) bool {
+	for i := 0; i < len(transactions) && b.nextPeriodIndex >= 0; i++ {
+		transaction := transactions[i]
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+		for b.nextPeriodIndex >= 0 && transactionUnixTime <= b.periods[b.nextPeriodIndex].EndTime {
+			b.recordBalances()
+		}
+
+		if b.nextPeriodIndex < 0 {
+			break
+		}
+
+		if balance, exists := b.balances[transaction.AccountId]; exists {
+			b.balances[transaction.AccountId] = balance - transaction.GetAccountBalanceChangedAmount()
+		}
+	}
+
+	return b.nextPeriodIndex >= 0
+}
+
+// Finish records the balances of all the remaining periods and returns the end-of-period balances of each account
+func (b *AccountBalanceHistoryBuilder) Finish() map[int64][]int64 {
+	for b.nextPeriodIndex >= 0 {
+		b.recordBalances()
+	}
+
+	return b.history
+}
+
+func (b *AccountBalanceHistoryBuilder) recordBalances() {
+	for accountId, balance := range b.balances {
+		b.history[accountId][b.nextPeriodIndex] = balance
+	}
+
+	b.nextPeriodIndex--
+}
+
+// ToAccountBalanceHistoryResponse returns a view-object of the end-of-period balances of the accounts, the net worth is converted
+// to the default currency by the exchange rates, which can be nil if all accounts use the default currency
+func ToAccountBalanceHistoryResponse(periods []*AccountBalanceHistoryPeriod, accounts []*Account, history map[int64][]int64, defaultCurrency string, exchangeRates *LatestExchangeRateResponse) (*AccountBalanceHistoryResponse, error) {
+	historyResp := &AccountBalanceHistoryResponse{
+		DefaultCurrency:  defaultCurrency,
+		PeriodEndDates:   make([]string, len(periods)),
+		Accounts:         make([]*AccountBalanceHistoryItemResponse, 0, len(accounts)),
+		TotalAssets:      make([]int64, len(periods)),
+		TotalLiabilities: make([]int64, len(periods)),
+		NetWorth:         make([]int64, len(periods)),
+	}
+
+	for i := 0; i < len(periods); i++ {
+		historyResp.PeriodEndDates[i] = periods[i].EndDate
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		balances, exists := history[account.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		historyResp.Accounts = append(historyResp.Accounts, &AccountBalanceHistoryItemResponse{
+			AccountId: account.AccountId,
+			Currency:  account.Currency,
+			Balances:  balances,
+		})
+
+		for j := 0; j < len(balances); j++ {
+			balance := balances[j]
+
+			if account.Currency != defaultCurrency {
+				if exchangeRates == nil {
+					return nil, errs.ErrAccountExchangeRateNotFound
+				}
+
+				balance, exists = exchangeRates.ConvertAmount(balance, account.Currency, defaultCurrency)
+
+				if !exists {
+					return nil, errs.ErrAccountExchangeRateNotFound
+				}
+			}
+
+			if account.Category.IsAsset() {
+				historyResp.TotalAssets[j] += balance
+			} else if account.Category.IsLiability() {
+				historyResp.TotalLiabilities[j] -= balance
+			}
+		}
+	}
+
+	for i := 0; i < len(periods); i++ {
+		historyResp.NetWorth[i] = historyResp.TotalAssets[i] - historyResp.TotalLiabilities[i]
+	}
+
+	return historyResp, nil
+}
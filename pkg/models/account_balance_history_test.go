@@ -0,0 +1,133 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGetAccountBalanceHistoryPeriods_Daily(t *testing.T) {
+	startTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC).Unix()
+
+	periods, err := GetAccountBalanceHistoryPeriods(startTime, endTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(periods))
+
+	assert.Equal(t, "2024-03-01", periods[0].EndDate)
+	assert.Equal(t, time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC).Unix(), periods[0].EndTime)
+	assert.Equal(t, "2024-03-02", periods[1].EndDate)
+	assert.Equal(t, "2024-03-03", periods[2].EndDate)
+}
+
+func TestGetAccountBalanceHistoryPeriods_Weekly(t *testing.T) {
+	startTime := time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC).Unix()
+
+	periods, err := GetAccountBalanceHistoryPeriods(startTime, endTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_WEEKLY, core.WEEKDAY_MONDAY, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(periods))
+	assert.Equal(t, "2024-03-10", periods[0].EndDate)
+	assert.Equal(t, "2024-03-17", periods[1].EndDate)
+	assert.Equal(t, "2024-03-24", periods[2].EndDate)
+
+	periods, err = GetAccountBalanceHistoryPeriods(startTime, endTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_WEEKLY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(periods))
+	assert.Equal(t, "2024-03-09", periods[0].EndDate)
+	assert.Equal(t, "2024-03-23", periods[2].EndDate)
+}
+
+func TestGetAccountBalanceHistoryPeriods_Monthly(t *testing.T) {
+	startTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC).Unix()
+
+	periods, err := GetAccountBalanceHistoryPeriods(startTime, endTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_MONTHLY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(periods))
+	assert.Equal(t, "2024-01-31", periods[0].EndDate)
+	assert.Equal(t, "2024-02-29", periods[1].EndDate)
+	assert.Equal(t, "2024-03-31", periods[2].EndDate)
+}
+
+func TestGetAccountBalanceHistoryPeriods_InvalidParameters(t *testing.T) {
+	startTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	_, err := GetAccountBalanceHistoryPeriods(endTime, startTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Equal(t, errs.ErrAccountBalanceHistoryTimeRangeInvalid, err)
+
+	_, err = GetAccountBalanceHistoryPeriods(startTime, endTime, 0, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Equal(t, errs.ErrAccountBalanceHistoryPeriodTypeInvalid, err)
+
+	_, err = GetAccountBalanceHistoryPeriods(startTime, endTime, ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Equal(t, errs.ErrTooManyAccountBalanceHistoryPeriods, err)
+}
+
+func TestAccountBalanceHistoryBuilder(t *testing.T) {
+	accounts := []*Account{
+		{AccountId: 1, Category: ACCOUNT_CATEGORY_CASH, Currency: "USD", Balance: 1000},
+		{AccountId: 2, Category: ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "EUR", Balance: -300},
+	}
+
+	periods, err := GetAccountBalanceHistoryPeriods(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC).Unix(), ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_DAILY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Nil(t, err)
+
+	transactionTime := func(month time.Month, day int, hour int) int64 {
+		return utils.GetMinTransactionTimeFromUnixTime(time.Date(2024, month, day, hour, 0, 0, 0, time.UTC).Unix())
+	}
+
+	builder := NewAccountBalanceHistoryBuilder(accounts, periods)
+	assert.Equal(t, periods[0].EndTime+1, builder.GetMinUnixTime())
+
+	assert.True(t, builder.Walk([]*Transaction{
+		{AccountId: 1, Type: TRANSACTION_DB_TYPE_INCOME, TransactionTime: transactionTime(3, 4, 12), Amount: 100},
+		{AccountId: 2, Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: transactionTime(3, 3, 10), Amount: 50},
+	}))
+
+	assert.False(t, builder.Walk([]*Transaction{
+		{AccountId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: transactionTime(3, 2, 10), Amount: 200},
+		{AccountId: 99, Type: TRANSACTION_DB_TYPE_INCOME, TransactionTime: transactionTime(3, 2, 9), Amount: 999},
+		{AccountId: 1, Type: TRANSACTION_DB_TYPE_INCOME, TransactionTime: transactionTime(3, 1, 10), Amount: 300},
+	}))
+
+	history := builder.Finish()
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, []int64{1100, 900, 900}, history[1])
+	assert.Equal(t, []int64{-250, -250, -300}, history[2])
+
+	exchangeRates := &LatestExchangeRateResponse{
+		BaseCurrency:  "USD",
+		ExchangeRates: LatestExchangeRateSlice{{Currency: "EUR", Rate: "0.5"}},
+	}
+
+	historyResp, err := ToAccountBalanceHistoryResponse(periods, accounts, history, "USD", exchangeRates)
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", historyResp.DefaultCurrency)
+	assert.Equal(t, []string{"2024-03-01", "2024-03-02", "2024-03-03"}, historyResp.PeriodEndDates)
+	assert.Equal(t, 2, len(historyResp.Accounts))
+	assert.Equal(t, []int64{1100, 900, 900}, historyResp.TotalAssets)
+	assert.Equal(t, []int64{500, 500, 600}, historyResp.TotalLiabilities)
+	assert.Equal(t, []int64{600, 400, 300}, historyResp.NetWorth)
+
+	_, err = ToAccountBalanceHistoryResponse(periods, accounts, history, "USD", nil)
+	assert.Equal(t, errs.ErrAccountExchangeRateNotFound, err)
+}
+
+func TestAccountBalanceHistoryBuilder_NoTransactions(t *testing.T) {
+	accounts := []*Account{
+		{AccountId: 1, Category: ACCOUNT_CATEGORY_CASH, Currency: "USD", Balance: 1000},
+	}
+
+	periods, err := GetAccountBalanceHistoryPeriods(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Unix(), ACCOUNT_BALANCE_HISTORY_PERIOD_TYPE_MONTHLY, core.WEEKDAY_SUNDAY, time.UTC)
+	assert.Nil(t, err)
+
+	builder := NewAccountBalanceHistoryBuilder(accounts, periods)
+	assert.True(t, builder.Walk(nil))
+	assert.Equal(t, []int64{1000, 1000}, builder.Finish()[1])
+}
@@ -49,3 +49,53 @@ func TestAccountInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(5), accountRespSlice[4].Id)
 	assert.Equal(t, int64(3), accountRespSlice[5].Id)
 }
+
+func TestAccountGetCreditCardLimit(t *testing.T) {
+	account := &Account{Category: ACCOUNT_CATEGORY_CREDIT_CARD}
+	assert.Equal(t, int64(0), account.GetCreditCardLimit())
+
+	account.Extend = &AccountExtend{}
+	assert.Equal(t, int64(0), account.GetCreditCardLimit())
+
+	creditCardLimit := int64(500000)
+	account.Extend.CreditCardLimit = &creditCardLimit
+	assert.Equal(t, int64(500000), account.GetCreditCardLimit())
+
+	account.Category = ACCOUNT_CATEGORY_CASH
+	assert.Equal(t, int64(0), account.GetCreditCardLimit())
+}
+
+func TestAccountGetLoanInfo(t *testing.T) {
+	account := &Account{Category: ACCOUNT_CATEGORY_DEBT}
+	assert.Nil(t, account.GetLoanInfo())
+
+	loanInfo := &AccountLoanInfo{Principal: 100000, InterestRate: 42500, TermMonths: 360, StartDate: "2024-01-15", PaymentDay: 5, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	account.Extend = &AccountExtend{Loan: loanInfo}
+	assert.Equal(t, loanInfo, account.GetLoanInfo())
+	assert.Equal(t, loanInfo, account.ToAccountInfoResponse().Loan)
+
+	account.Category = ACCOUNT_CATEGORY_CREDIT_CARD
+	assert.Nil(t, account.GetLoanInfo())
+	assert.Nil(t, account.ToAccountInfoResponse().Loan)
+}
+
+func TestAccountGetCreditCardPaymentSettings(t *testing.T) {
+	account := &Account{Category: ACCOUNT_CATEGORY_CREDIT_CARD}
+	assert.Equal(t, 0, account.GetCreditCardPaymentDueDays())
+	assert.Equal(t, 0, account.GetCreditCardDueReminderDays())
+
+	paymentDueDays := 20
+	minimumPaymentRate := int64(500)
+	dueReminderDays := 3
+	account.Extend = &AccountExtend{CreditCardPaymentDueDays: &paymentDueDays, CreditCardMinimumPaymentRate: &minimumPaymentRate, CreditCardDueReminderDays: &dueReminderDays}
+	assert.Equal(t, 20, account.GetCreditCardPaymentDueDays())
+	assert.Equal(t, 3, account.GetCreditCardDueReminderDays())
+
+	rate, amount := account.GetCreditCardMinimumPayment()
+	assert.Equal(t, int64(500), rate)
+	assert.Equal(t, int64(0), amount)
+
+	account.Category = ACCOUNT_CATEGORY_DEBT
+	assert.Equal(t, 0, account.GetCreditCardPaymentDueDays())
+	assert.Nil(t, account.ToAccountInfoResponse().CreditCardPaymentDueDays)
+}
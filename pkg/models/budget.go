@@ -0,0 +1,303 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// BudgetScope represents whose transactions are counted in a budget
+type BudgetScope byte
+
+// Budget scopes
+const (
+	BUDGET_SCOPE_PERSONAL BudgetScope = 1 // Only visible to the creator and counts the transactions of the creator
+	BUDGET_SCOPE_FUND     BudgetScope = 2 // Visible to all fund members and counts the transactions of all linked fund members
+)
+
+// String returns a textual representation of the budget scope enum
+func (s BudgetScope) String() string {
+	switch s {
+	case BUDGET_SCOPE_PERSONAL:
+		return "Personal"
+	case BUDGET_SCOPE_FUND:
+		return "Fund"
+	default:
+		return "Unknown"
+	}
+}
+
+// BudgetPeriodType represents the length of a budget period
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_MONTHLY   BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_QUARTERLY BudgetPeriodType = 2
+	BUDGET_PERIOD_TYPE_YEARLY    BudgetPeriodType = 3
+)
+
+// String returns a textual representation of the budget period type enum
+func (t BudgetPeriodType) String() string {
+	switch t {
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		return "Monthly"
+	case BUDGET_PERIOD_TYPE_QUARTERLY:
+		return "Quarterly"
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		return "Yearly"
+	default:
+		return "Unknown"
+	}
+}
+
+// GetPeriodRange returns the first and the last unix time of the budget period which contains the specified unix time,
+// monthly periods are calendar months and quarterly and yearly periods start from the fiscal year start date
+func (t BudgetPeriodType) GetPeriodRange(unixTime int64, fiscalYearStart core.FiscalYearStart, location *time.Location) (int64, int64, error) {
+	current := time.Unix(unixTime, 0).In(location)
+
+	var periodMonths int
+
+	switch t {
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		startTime := getBudgetPeriodStartTime(current.Year(), int(current.Month()), 1, location)
+		nextStartTime := getBudgetPeriodStartTime(current.Year(), int(current.Month())+1, 1, location)
+		return startTime.Unix(), nextStartTime.Unix() - 1, nil
+	case BUDGET_PERIOD_TYPE_QUARTERLY:
+		periodMonths = 3
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		periodMonths = 12
+	default:
+		return 0, 0, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	fiscalYearStartMonth, fiscalYearStartDay, err := fiscalYearStart.GetMonthDay()
+
+	if err != nil {
+		fiscalYearStartMonth, fiscalYearStartDay, _ = core.FISCAL_YEAR_START_DEFAULT.GetMonthDay()
+	}
+
+	fiscalYear := current.Year()
+	fiscalYearStartTime := getBudgetPeriodStartTime(fiscalYear, int(fiscalYearStartMonth), int(fiscalYearStartDay), location)
+
+	if current.Before(fiscalYearStartTime) {
+		fiscalYear--
+	}
+
+	var startTime, nextStartTime time.Time
+
+	for months := 0; months < 12; months += periodMonths {
+		periodStartTime := getBudgetPeriodStartTime(fiscalYear, int(fiscalYearStartMonth)+months, int(fiscalYearStartDay), location)
+
+		if periodStartTime.After(current) {
+			break
+		}
+
+		startTime = periodStartTime
+		nextStartTime = getBudgetPeriodStartTime(fiscalYear, int(fiscalYearStartMonth)+months+periodMonths, int(fiscalYearStartDay), location)
+	}
+
+	return startTime.Unix(), nextStartTime.Unix() - 1, nil
+}
+
+// getBudgetPeriodStartTime returns the beginning of the specified date, the month can exceed 12 and the day is
+// limited to the last day of the month
+func getBudgetPeriodStartTime(year int, month int, day int, location *time.Location) time.Time {
+	year = year + (month-1)/12
+	month = (month-1)%12 + 1
+
+	lastDay := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, location).Day()
+
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, location)
+}
+
+// Budget represents budget data stored in database
+type Budget struct {
+	BudgetId        int64            `xorm:"PK"`
+	Uid             int64            `xorm:"INDEX(IDX_budget_fund_id_deleted_uid) NOT NULL"`
+	FundId          int64            `xorm:"INDEX(IDX_budget_fund_id_deleted_uid) NOT NULL"`
+	Deleted         bool             `xorm:"INDEX(IDX_budget_fund_id_deleted_uid) NOT NULL"`
+	Scope           BudgetScope      `xorm:"TINYINT NOT NULL"`
+	Name            string           `xorm:"VARCHAR(64) NOT NULL"`
+	CategoryId      int64            `xorm:"NOT NULL"` // The budget of a primary category also contains all its secondary categories
+	PeriodType      BudgetPeriodType `xorm:"TINYINT NOT NULL"`
+	Currency        string           `xorm:"VARCHAR(3) NOT NULL"`
+	Amount          int64            `xorm:"NOT NULL"`
+	Rollover        bool             `xorm:"NOT NULL"` // Whether the unspent amount of previous periods is added to current period
+	Comment         string           `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	// FundId will be retrieved from URL context parameter
+	Scope      BudgetScope      `json:"scope" binding:"required,min=1,max=2"`
+	Name       string           `json:"name" binding:"required,notBlank,max=64"`
+	CategoryId int64            `json:"categoryId,string" binding:"required,min=1"`
+	PeriodType BudgetPeriodType `json:"periodType" binding:"required,min=1,max=3"`
+	Currency   string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount     int64            `json:"amount" binding:"required,min=1,max=99999999999"`
+	Rollover   bool             `json:"rollover"`
+	Comment    string           `json:"comment" binding:"max=255"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id         int64            `json:"id,string" binding:"required,min=1"`
+	Name       string           `json:"name" binding:"required,notBlank,max=64"`
+	CategoryId int64            `json:"categoryId,string" binding:"required,min=1"`
+	PeriodType BudgetPeriodType `json:"periodType" binding:"required,min=1,max=3"`
+	Currency   string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount     int64            `json:"amount" binding:"required,min=1,max=99999999999"`
+	Rollover   bool             `json:"rollover"`
+	Comment    string           `json:"comment" binding:"max=255"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetProgressRequest represents all parameters of budget progress getting request
+type BudgetProgressRequest struct {
+	Id   int64 `form:"id,string" binding:"required,min=1"`
+	Time int64 `form:"time" binding:"min=0"` // Any unix time in the requested period, current period is used if it is zero
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id         int64            `json:"id,string"`
+	Scope      BudgetScope      `json:"scope"`
+	Name       string           `json:"name"`
+	CategoryId int64            `json:"categoryId,string"`
+	PeriodType BudgetPeriodType `json:"periodType"`
+	Currency   string           `json:"currency"`
+	Amount     int64            `json:"amount"`
+	Rollover   bool             `json:"rollover"`
+	Comment    string           `json:"comment"`
+	CreatorUid int64            `json:"creatorUid,string"`
+}
+
+// BudgetProgressResponse represents a view-object of the spending of a budget in one period
+type BudgetProgressResponse struct {
+	BudgetId        int64  `json:"budgetId,string"`
+	StartTime       int64  `json:"startTime"`
+	EndTime         int64  `json:"endTime"`
+	Currency        string `json:"currency"`
+	Amount          int64  `json:"amount"`
+	RolloverAmount  int64  `json:"rolloverAmount"`
+	AvailableAmount int64  `json:"availableAmount"`
+	SpentAmount     int64  `json:"spentAmount"`
+	RemainingAmount int64  `json:"remainingAmount"`
+}
+
+// BudgetInfoResponseSlice represents the slice data structure of BudgetInfoResponse
+type BudgetInfoResponseSlice []*BudgetInfoResponse
+
+// Len returns the count of items
+func (s BudgetInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Scope != s[j].Scope {
+		return s[i].Scope < s[j].Scope
+	}
+
+	if s[i].PeriodType != s[j].PeriodType {
+		return s[i].PeriodType < s[j].PeriodType
+	}
+
+	return s[i].Id < s[j].Id
+}
+
+// GetRolloverPeriods returns the start unix time of the first budget period and the count of budget periods
+// before the budget period which starts from the specified unix time
+func (b *Budget) GetRolloverPeriods(periodStartUnixTime int64, fiscalYearStart core.FiscalYearStart, location *time.Location) (int64, int32, error) {
+	firstPeriodStartUnixTime, _, err := b.PeriodType.GetPeriodRange(b.CreatedUnixTime, fiscalYearStart, location)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	periodCount := int32(0)
+
+	for startUnixTime := firstPeriodStartUnixTime; startUnixTime < periodStartUnixTime; periodCount++ {
+		_, endUnixTime, err := b.PeriodType.GetPeriodRange(startUnixTime, fiscalYearStart, location)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		startUnixTime = endUnixTime + 1
+	}
+
+	return firstPeriodStartUnixTime, periodCount, nil
+}
+
+// GetRolloverAmount returns the unspent amount of the specified count of previous budget periods, overspending
+// is not carried over so the result is never negative
+func (b *Budget) GetRolloverAmount(periodCount int32, spentAmount int64) int64 {
+	if !b.Rollover || periodCount < 1 {
+		return 0
+	}
+
+	unspentAmount := b.Amount*int64(periodCount) - spentAmount
+
+	if unspentAmount < 0 {
+		return 0
+	}
+
+	return unspentAmount
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:         b.BudgetId,
+		Scope:      b.Scope,
+		Name:       b.Name,
+		CategoryId: b.CategoryId,
+		PeriodType: b.PeriodType,
+		Currency:   b.Currency,
+		Amount:     b.Amount,
+		Rollover:   b.Rollover,
+		Comment:    b.Comment,
+		CreatorUid: b.Uid,
+	}
+}
+
+// ToBudgetProgressResponse returns a view-object of the budget spending in the specified period
+func (b *Budget) ToBudgetProgressResponse(startTime int64, endTime int64, rolloverAmount int64, spentAmount int64) *BudgetProgressResponse {
+	availableAmount := b.Amount + rolloverAmount
+
+	return &BudgetProgressResponse{
+		BudgetId:        b.BudgetId,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		Currency:        b.Currency,
+		Amount:          b.Amount,
+		RolloverAmount:  rolloverAmount,
+		AvailableAmount: availableAmount,
+		SpentAmount:     spentAmount,
+		RemainingAmount: availableAmount - spentAmount,
+	}
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestBudgetPeriodTypeGetPeriodRange_Monthly(t *testing.T) {
+	location := time.FixedZone("Test Timezone", 8*3600)
+	unixTime := time.Date(2024, 2, 15, 10, 30, 0, 0, location).Unix()
+
+	startTime, endTime, err := BUDGET_PERIOD_TYPE_MONTHLY.GetPeriodRange(unixTime, core.FISCAL_YEAR_START_DEFAULT, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, location).Unix()-1, endTime)
+
+	unixTime = time.Date(2024, 12, 31, 23, 59, 59, 0, location).Unix()
+
+	startTime, endTime, err = BUDGET_PERIOD_TYPE_MONTHLY.GetPeriodRange(unixTime, core.FISCAL_YEAR_START_DEFAULT, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 12, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, location).Unix()-1, endTime)
+}
+
+func TestBudgetPeriodTypeGetPeriodRange_Quarterly(t *testing.T) {
+	location := time.UTC
+	fiscalYearStart, _ := core.NewFiscalYearStart(4, 6)
+
+	unixTime := time.Date(2024, 3, 1, 0, 0, 0, 0, location).Unix()
+	startTime, endTime, err := BUDGET_PERIOD_TYPE_QUARTERLY.GetPeriodRange(unixTime, fiscalYearStart, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 6, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 4, 6, 0, 0, 0, 0, location).Unix()-1, endTime)
+
+	unixTime = time.Date(2024, 4, 6, 0, 0, 0, 0, location).Unix()
+	startTime, endTime, err = BUDGET_PERIOD_TYPE_QUARTERLY.GetPeriodRange(unixTime, fiscalYearStart, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 4, 6, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 7, 6, 0, 0, 0, 0, location).Unix()-1, endTime)
+
+	unixTime = time.Date(2024, 12, 31, 0, 0, 0, 0, location).Unix()
+	startTime, endTime, err = BUDGET_PERIOD_TYPE_QUARTERLY.GetPeriodRange(unixTime, fiscalYearStart, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 10, 6, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, location).Unix()-1, endTime)
+}
+
+func TestBudgetPeriodTypeGetPeriodRange_QuarterlyEndOfMonth(t *testing.T) {
+	location := time.UTC
+	fiscalYearStart, _ := core.NewFiscalYearStart(8, 31)
+
+	unixTime := time.Date(2024, 12, 15, 0, 0, 0, 0, location).Unix()
+	startTime, endTime, err := BUDGET_PERIOD_TYPE_QUARTERLY.GetPeriodRange(unixTime, fiscalYearStart, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 11, 30, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, location).Unix()-1, endTime)
+}
+
+func TestBudgetPeriodTypeGetPeriodRange_Yearly(t *testing.T) {
+	location := time.UTC
+
+	unixTime := time.Date(2024, 7, 1, 0, 0, 0, 0, location).Unix()
+	startTime, endTime, err := BUDGET_PERIOD_TYPE_YEARLY.GetPeriodRange(unixTime, core.FISCAL_YEAR_START_DEFAULT, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, location).Unix()-1, endTime)
+
+	fiscalYearStart, _ := core.NewFiscalYearStart(7, 1)
+	unixTime = time.Date(2024, 6, 30, 23, 59, 59, 0, location).Unix()
+	startTime, endTime, err = BUDGET_PERIOD_TYPE_YEARLY.GetPeriodRange(unixTime, fiscalYearStart, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, location).Unix()-1, endTime)
+
+	startTime, endTime, err = BUDGET_PERIOD_TYPE_YEARLY.GetPeriodRange(unixTime, core.FiscalYearStart(0), location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, location).Unix()-1, endTime)
+}
+
+func TestBudgetPeriodTypeGetPeriodRange_InvalidPeriodType(t *testing.T) {
+	_, _, err := BudgetPeriodType(0).GetPeriodRange(0, core.FISCAL_YEAR_START_DEFAULT, time.UTC)
+	assert.Equal(t, errs.ErrBudgetPeriodTypeInvalid, err)
+}
+
+func TestBudgetGetRolloverPeriods(t *testing.T) {
+	location := time.UTC
+	budget := &Budget{
+		PeriodType:      BUDGET_PERIOD_TYPE_MONTHLY,
+		CreatedUnixTime: time.Date(2024, 1, 20, 0, 0, 0, 0, location).Unix(),
+	}
+
+	startTime, periodCount, err := budget.GetRolloverPeriods(time.Date(2024, 4, 1, 0, 0, 0, 0, location).Unix(), core.FISCAL_YEAR_START_DEFAULT, location)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, location).Unix(), startTime)
+	assert.Equal(t, int32(3), periodCount)
+
+	_, periodCount, err = budget.GetRolloverPeriods(time.Date(2024, 1, 1, 0, 0, 0, 0, location).Unix(), core.FISCAL_YEAR_START_DEFAULT, location)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), periodCount)
+}
+
+func TestBudgetGetRolloverAmount(t *testing.T) {
+	budget := &Budget{Amount: 1000, Rollover: true}
+
+	assert.Equal(t, int64(500), budget.GetRolloverAmount(2, 1500))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount(2, 2500))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount(0, 0))
+
+	budget.Rollover = false
+	assert.Equal(t, int64(0), budget.GetRolloverAmount(2, 1500))
+}
+
+func TestBudgetToBudgetProgressResponse(t *testing.T) {
+	budget := &Budget{BudgetId: 1001, Currency: "USD", Amount: 1000}
+
+	progressResp := budget.ToBudgetProgressResponse(100, 200, 300, 1500)
+	assert.Equal(t, int64(1001), progressResp.BudgetId)
+	assert.Equal(t, int64(100), progressResp.StartTime)
+	assert.Equal(t, int64(200), progressResp.EndTime)
+	assert.Equal(t, "USD", progressResp.Currency)
+	assert.Equal(t, int64(1000), progressResp.Amount)
+	assert.Equal(t, int64(300), progressResp.RolloverAmount)
+	assert.Equal(t, int64(1300), progressResp.AvailableAmount)
+	assert.Equal(t, int64(1500), progressResp.SpentAmount)
+	assert.Equal(t, int64(-200), progressResp.RemainingAmount)
+}
+
+func TestBudgetScopeAndPeriodType_String(t *testing.T) {
+	assert.Equal(t, "Personal", BUDGET_SCOPE_PERSONAL.String())
+	assert.Equal(t, "Fund", BUDGET_SCOPE_FUND.String())
+	assert.Equal(t, "Unknown", BudgetScope(0).String())
+
+	assert.Equal(t, "Monthly", BUDGET_PERIOD_TYPE_MONTHLY.String())
+	assert.Equal(t, "Quarterly", BUDGET_PERIOD_TYPE_QUARTERLY.String())
+	assert.Equal(t, "Yearly", BUDGET_PERIOD_TYPE_YEARLY.String())
+	assert.Equal(t, "Unknown", BudgetPeriodType(0).String())
+}
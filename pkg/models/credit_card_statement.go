@@ -0,0 +1,172 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// CreditCardMinimumPaymentRateScale represents the scale of minimum payment rate of credit card, e.g. the rate 2.5% is stored as 250
+const CreditCardMinimumPaymentRateScale = 100
+
+// MaximumCreditCardStatementsCount represents the maximum count of statement cycles returned at a time
+const MaximumCreditCardStatementsCount = 24
+
+// CreditCardStatementsRequest represents all parameters of credit card statements request
+type CreditCardStatementsRequest struct {
+	Id    int64 `form:"id,string" binding:"required,min=1"`
+	Count int   `form:"count" binding:"omitempty,min=1,max=24"`
+}
+
+// CreditCardStatementResponse represents a view-object of one statement cycle of credit card account
+type CreditCardStatementResponse struct {
+	StartDate              string `json:"startDate"`
+	StatementDate          string `json:"statementDate"`
+	DueDate                string `json:"dueDate,omitempty"`
+	StatementBalance       int64  `json:"statementBalance"`
+	NewCharges             int64  `json:"newCharges"`
+	MinimumPayment         int64  `json:"minimumPayment"`
+	PaymentsSinceStatement int64  `json:"paymentsSinceStatement"`
+	RemainingDue           int64  `json:"remainingDue"`
+	Paid                   bool   `json:"paid"`
+}
+
+// CreditCardStatementsResponse represents a view-object of the statement cycles of credit card account
+type CreditCardStatementsResponse struct {
+	AccountId       int64                          `json:"accountId,string"`
+	Currency        string                         `json:"currency"`
+	CreditLimit     int64                          `json:"creditLimit"`
+	CurrentBalance  int64                          `json:"currentBalance"`
+	AvailableCredit int64                          `json:"availableCredit"`
+	Statements      []*CreditCardStatementResponse `json:"statements"`
+}
+
+// CreditCardStatementCycle represents the time range of one statement cycle of credit card account
+type CreditCardStatementCycle struct {
+	StartTime     time.Time
+	StatementTime time.Time
+}
+
+// GetCreditCardStatementCycles returns the latest statement cycles ending not later than the specified time, from the latest to the earliest,
+// each cycle starts from the day after the previous statement date and ends at the last second of the statement date
+func GetCreditCardStatementCycles(statementDate int, currentUnixTime int64, timezone *time.Location, count int) ([]*CreditCardStatementCycle, error) {
+	if statementDate < 1 || statementDate > 28 {
+		return nil, errs.ErrCreditCardStatementDateNotSet
+	}
+
+	currentTime := time.Unix(currentUnixTime, 0).In(timezone)
+	latestStatementMonth := currentTime.Month()
+
+	if currentTime.Day() <= statementDate {
+		latestStatementMonth--
+	}
+
+	cycles := make([]*CreditCardStatementCycle, 0, count)
+
+	for i := 0; i < count; i++ {
+		month := latestStatementMonth - time.Month(i)
+
+		cycles = append(cycles, &CreditCardStatementCycle{
+			StartTime:     time.Date(currentTime.Year(), month-1, statementDate+1, 0, 0, 0, 0, timezone),
+			StatementTime: time.Date(currentTime.Year(), month, statementDate+1, 0, 0, 0, 0, timezone).Add(-time.Second),
+		})
+	}
+
+	return cycles, nil
+}
+
+// GetCreditCardMinimumPayment returns the minimum payment of the statement balance, which is the larger one of the minimum payment rate
+// of statement balance and the minimum payment amount, and is not greater than the statement balance
+func GetCreditCardMinimumPayment(statementBalance int64, minimumPaymentRate int64, minimumPaymentAmount int64) int64 {
+	if statementBalance <= 0 {
+		return 0
+	}
+
+	minimumPayment := int64(math.Round(float64(statementBalance) * float64(minimumPaymentRate) / CreditCardMinimumPaymentRateScale / 100))
+
+	if minimumPayment < minimumPaymentAmount {
+		minimumPayment = minimumPaymentAmount
+	}
+
+	if minimumPayment > statementBalance || minimumPayment <= 0 {
+		minimumPayment = statementBalance
+	}
+
+	return minimumPayment
+}
+
+// ToCreditCardStatementsResponse returns a view-object of the statement cycles of the credit card account, the balance is the total balance
+// of the account and all its sub-accounts, and the transactions must contain all the transactions of the account and all its sub-accounts
+// after the start time of the earliest cycle except the transfers between them
+func (a *Account) ToCreditCardStatementsResponse(balance int64, cycles []*CreditCardStatementCycle, transactions []*Transaction, timezone *time.Location) *CreditCardStatementsResponse {
+	statementsResp := &CreditCardStatementsResponse{
+		AccountId:       a.AccountId,
+		Currency:        a.Currency,
+		CreditLimit:     a.GetCreditCardLimit(),
+		CurrentBalance:  -balance,
+		AvailableCredit: a.GetCreditCardLimit() + balance,
+		Statements:      make([]*CreditCardStatementResponse, 0, len(cycles)),
+	}
+
+	paymentDueDays := a.GetCreditCardPaymentDueDays()
+	minimumPaymentRate, minimumPaymentAmount := a.GetCreditCardMinimumPayment()
+
+	for i := 0; i < len(cycles); i++ {
+		cycle := cycles[i]
+		cycleStartUnixTime := cycle.StartTime.Unix()
+		statementUnixTime := cycle.StatementTime.Unix()
+		nextStatementUnixTime := int64(math.MaxInt64)
+
+		if i > 0 {
+			nextStatementUnixTime = cycles[i-1].StatementTime.Unix()
+		}
+
+		balanceAfterStatement := int64(0)
+		newCharges := int64(0)
+		payments := int64(0)
+
+		for j := 0; j < len(transactions); j++ {
+			transaction := transactions[j]
+			transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+			changedAmount := transaction.GetAccountBalanceChangedAmount()
+
+			if transactionUnixTime > statementUnixTime {
+				balanceAfterStatement += changedAmount
+
+				if transactionUnixTime <= nextStatementUnixTime && transaction.Type != TRANSACTION_DB_TYPE_MODIFY_BALANCE && changedAmount > 0 {
+					payments += changedAmount
+				}
+			} else if transactionUnixTime >= cycleStartUnixTime && transaction.Type != TRANSACTION_DB_TYPE_MODIFY_BALANCE && changedAmount < 0 {
+				newCharges -= changedAmount
+			}
+		}
+
+		statementBalance := -(balance - balanceAfterStatement)
+		remainingDue := statementBalance - payments
+
+		if remainingDue < 0 {
+			remainingDue = 0
+		}
+
+		statementResp := &CreditCardStatementResponse{
+			StartDate:              utils.FormatUnixTimeToLongDate(cycleStartUnixTime, timezone),
+			StatementDate:          utils.FormatUnixTimeToLongDate(statementUnixTime, timezone),
+			StatementBalance:       statementBalance,
+			NewCharges:             newCharges,
+			MinimumPayment:         GetCreditCardMinimumPayment(statementBalance, minimumPaymentRate, minimumPaymentAmount),
+			PaymentsSinceStatement: payments,
+			RemainingDue:           remainingDue,
+			Paid:                   remainingDue == 0,
+		}
+
+		if paymentDueDays > 0 {
+			statementResp.DueDate = utils.FormatUnixTimeToLongDate(cycle.StatementTime.AddDate(0, 0, paymentDueDays).Unix(), timezone)
+		}
+
+		statementsResp.Statements = append(statementsResp.Statements, statementResp)
+	}
+
+	return statementsResp
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGetCreditCardStatementCycles(t *testing.T) {
+	currentUnixTime := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC).Unix()
+
+	cycles, err := GetCreditCardStatementCycles(10, currentUnixTime, time.UTC, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cycles))
+
+	assert.Equal(t, time.Date(2024, 2, 11, 0, 0, 0, 0, time.UTC), cycles[0].StartTime)
+	assert.Equal(t, time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC), cycles[0].StatementTime)
+	assert.Equal(t, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), cycles[1].StartTime)
+	assert.Equal(t, time.Date(2024, 2, 10, 23, 59, 59, 0, time.UTC), cycles[1].StatementTime)
+}
+
+func TestGetCreditCardStatementCycles_OnStatementDateAndAcrossYear(t *testing.T) {
+	currentUnixTime := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC).Unix()
+
+	cycles, err := GetCreditCardStatementCycles(10, currentUnixTime, time.UTC, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cycles))
+
+	assert.Equal(t, time.Date(2023, 11, 11, 0, 0, 0, 0, time.UTC), cycles[0].StartTime)
+	assert.Equal(t, time.Date(2023, 12, 10, 23, 59, 59, 0, time.UTC), cycles[0].StatementTime)
+}
+
+func TestGetCreditCardStatementCycles_StatementDateNotSet(t *testing.T) {
+	_, err := GetCreditCardStatementCycles(0, time.Now().Unix(), time.UTC, 1)
+	assert.Equal(t, errs.ErrCreditCardStatementDateNotSet, err)
+}
+
+func TestGetCreditCardMinimumPayment(t *testing.T) {
+	assert.Equal(t, int64(2250), GetCreditCardMinimumPayment(45000, 500, 2000))
+	assert.Equal(t, int64(2000), GetCreditCardMinimumPayment(10000, 500, 2000))
+	assert.Equal(t, int64(1500), GetCreditCardMinimumPayment(1500, 500, 2000))
+	assert.Equal(t, int64(1500), GetCreditCardMinimumPayment(1500, 0, 0))
+	assert.Equal(t, int64(0), GetCreditCardMinimumPayment(-1500, 500, 2000))
+}
+
+func TestAccountToCreditCardStatementsResponse(t *testing.T) {
+	statementDate := 10
+	creditLimit := int64(100000)
+	paymentDueDays := 20
+	minimumPaymentRate := int64(500)
+	minimumPaymentAmount := int64(2000)
+
+	account := &Account{
+		AccountId: 1,
+		Category:  ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+		Balance:   -40000,
+		Extend: &AccountExtend{
+			CreditCardStatementDate:        &statementDate,
+			CreditCardLimit:                &creditLimit,
+			CreditCardPaymentDueDays:       &paymentDueDays,
+			CreditCardMinimumPaymentRate:   &minimumPaymentRate,
+			CreditCardMinimumPaymentAmount: &minimumPaymentAmount,
+		},
+	}
+
+	transactionTime := func(year int, month time.Month, day int) int64 {
+		return utils.GetMinTransactionTimeFromUnixTime(time.Date(year, month, day, 12, 0, 0, 0, time.UTC).Unix())
+	}
+
+	transactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: transactionTime(2024, 2, 20), Amount: 10000},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: transactionTime(2024, 3, 5), Amount: 25000},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, TransactionTime: transactionTime(2024, 3, 12), Amount: 15000},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: transactionTime(2024, 3, 14), Amount: 10000},
+	}
+
+	cycles, err := GetCreditCardStatementCycles(statementDate, time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC).Unix(), time.UTC, 2)
+	assert.Nil(t, err)
+
+	statementsResp := account.ToCreditCardStatementsResponse(account.Balance, cycles, transactions, time.UTC)
+	assert.Equal(t, int64(1), statementsResp.AccountId)
+	assert.Equal(t, "USD", statementsResp.Currency)
+	assert.Equal(t, int64(100000), statementsResp.CreditLimit)
+	assert.Equal(t, int64(40000), statementsResp.CurrentBalance)
+	assert.Equal(t, int64(60000), statementsResp.AvailableCredit)
+	assert.Equal(t, 2, len(statementsResp.Statements))
+
+	assert.Equal(t, "2024-02-11", statementsResp.Statements[0].StartDate)
+	assert.Equal(t, "2024-03-10", statementsResp.Statements[0].StatementDate)
+	assert.Equal(t, "2024-03-30", statementsResp.Statements[0].DueDate)
+	assert.Equal(t, int64(45000), statementsResp.Statements[0].StatementBalance)
+	assert.Equal(t, int64(35000), statementsResp.Statements[0].NewCharges)
+	assert.Equal(t, int64(2250), statementsResp.Statements[0].MinimumPayment)
+	assert.Equal(t, int64(15000), statementsResp.Statements[0].PaymentsSinceStatement)
+	assert.Equal(t, int64(30000), statementsResp.Statements[0].RemainingDue)
+	assert.False(t, statementsResp.Statements[0].Paid)
+
+	assert.Equal(t, "2024-02-10", statementsResp.Statements[1].StatementDate)
+	assert.Equal(t, "2024-03-01", statementsResp.Statements[1].DueDate)
+	assert.Equal(t, int64(10000), statementsResp.Statements[1].StatementBalance)
+	assert.Equal(t, int64(0), statementsResp.Statements[1].NewCharges)
+	assert.Equal(t, int64(2000), statementsResp.Statements[1].MinimumPayment)
+	assert.Equal(t, int64(0), statementsResp.Statements[1].PaymentsSinceStatement)
+	assert.Equal(t, int64(10000), statementsResp.Statements[1].RemainingDue)
+}
+
+func TestAccountToCreditCardStatementsResponse_PaidOff(t *testing.T) {
+	statementDate := 10
+	account := &Account{
+		AccountId: 1,
+		Category:  ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+		Balance:   0,
+		Extend:    &AccountExtend{CreditCardStatementDate: &statementDate},
+	}
+
+	transactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).Unix()), Amount: 5000},
+		{Type: TRANSACTION_DB_TYPE_INCOME, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC).Unix()), Amount: 5000},
+	}
+
+	cycles, err := GetCreditCardStatementCycles(statementDate, time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC).Unix(), time.UTC, 1)
+	assert.Nil(t, err)
+
+	statementsResp := account.ToCreditCardStatementsResponse(account.Balance, cycles, transactions, time.UTC)
+	assert.Equal(t, int64(5000), statementsResp.Statements[0].StatementBalance)
+	assert.Equal(t, "", statementsResp.Statements[0].DueDate)
+	assert.Equal(t, int64(0), statementsResp.Statements[0].RemainingDue)
+	assert.True(t, statementsResp.Statements[0].Paid)
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -45,6 +47,26 @@ type LatestExchangeRateResponse struct {
 	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
 }
 
+// ExchangeRatesMetricsResponse represents a view-object of the metrics of requesting latest exchange rates
+type ExchangeRatesMetricsResponse struct {
+	CacheHits       int64                                     `json:"cacheHits"`
+	CacheMisses     int64                                     `json:"cacheMisses"`
+	StaleResponses  int64                                     `json:"staleResponses"`
+	FailedResponses int64                                     `json:"failedResponses"`
+	DataSources     []*ExchangeRatesDataSourceMetricsResponse `json:"dataSources"`
+}
+
+// ExchangeRatesDataSourceMetricsResponse represents a view-object of the metrics of one exchange rates data source
+type ExchangeRatesDataSourceMetricsResponse struct {
+	DataSource        string `json:"dataSource"`
+	ServedResponses   int64  `json:"servedResponses"`
+	MergedResponses   int64  `json:"mergedResponses"`
+	SucceededRequests int64  `json:"succeededRequests"`
+	FailedRequests    int64  `json:"failedRequests"`
+	LastSuccessTime   int64  `json:"lastSuccessTime"`
+	LastFailureTime   int64  `json:"lastFailureTime"`
+}
+
 // LatestExchangeRate represents a data pair of currency and exchange rate
 type LatestExchangeRate struct {
 	Currency string `json:"currency"`
@@ -115,3 +137,114 @@ func (s LatestExchangeRateSlice) Swap(i, j int) {
 func (s LatestExchangeRateSlice) Less(i, j int) bool {
 	return strings.Compare(s[i].Currency, s[j].Currency) < 0
 }
+
+// ConvertAmount returns the amount converted from one currency to another by the exchange rates, and whether the
+// exchange rates of both currencies exist
+func (r *LatestExchangeRateResponse) ConvertAmount(amount int64, fromCurrency string, toCurrency string) (int64, bool) {
+	if fromCurrency == toCurrency {
+		return amount, true
+	}
+
+	fromRate, exists := r.getExchangeRate(fromCurrency)
+
+	if !exists {
+		return 0, false
+	}
+
+	toRate, exists := r.getExchangeRate(toCurrency)
+
+	if !exists {
+		return 0, false
+	}
+
+	return int64(math.Round(float64(amount) / fromRate * toRate)), true
+}
+
+// Clone returns a copy of the exchange rates response which can be modified without affecting the original one
+func (r *LatestExchangeRateResponse) Clone() *LatestExchangeRateResponse {
+	clonedResponse := *r
+	clonedResponse.ExchangeRates = make(LatestExchangeRateSlice, len(r.ExchangeRates))
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		exchangeRate := *r.ExchangeRates[i]
+		clonedResponse.ExchangeRates[i] = &exchangeRate
+	}
+
+	return &clonedResponse
+}
+
+// MergeExchangeRates adds the exchange rates of the currencies which only exist in the other response, the exchange rates are rebased
+// onto the base currency of this response by the cross rates, returns the count of merged currencies
+func (r *LatestExchangeRateResponse) MergeExchangeRates(other *LatestExchangeRateResponse) int {
+	baseCurrencyRate, exists := other.getExchangeRate(r.BaseCurrency)
+
+	if !exists {
+		return 0
+	}
+
+	existedCurrencies := make(map[string]bool, len(r.ExchangeRates)+1)
+	existedCurrencies[r.BaseCurrency] = true
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		existedCurrencies[r.ExchangeRates[i].Currency] = true
+	}
+
+	otherCurrencies := make([]string, 0, len(other.ExchangeRates)+1)
+	otherCurrencies = append(otherCurrencies, other.BaseCurrency)
+
+	for i := 0; i < len(other.ExchangeRates); i++ {
+		otherCurrencies = append(otherCurrencies, other.ExchangeRates[i].Currency)
+	}
+
+	mergedCount := 0
+
+	for i := 0; i < len(otherCurrencies); i++ {
+		currency := otherCurrencies[i]
+
+		if existedCurrencies[currency] {
+			continue
+		}
+
+		rate, exists := other.getExchangeRate(currency)
+
+		if !exists {
+			continue
+		}
+
+		r.ExchangeRates = append(r.ExchangeRates, &LatestExchangeRate{
+			Currency: currency,
+			Rate:     utils.Float64ToString(rate / baseCurrencyRate),
+		})
+
+		existedCurrencies[currency] = true
+		mergedCount++
+	}
+
+	if mergedCount > 0 {
+		sort.Sort(r.ExchangeRates)
+	}
+
+	return mergedCount
+}
+
+func (r *LatestExchangeRateResponse) getExchangeRate(currency string) (float64, bool) {
+	if currency == r.BaseCurrency {
+		return 1, true
+	}
+
+	for _, exchangeRate := range r.ExchangeRates {
+		if exchangeRate.Currency != currency {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			return 0, false
+		}
+
+		return rate, true
+	}
+
+	return 0, false
+}
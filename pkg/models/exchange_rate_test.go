@@ -25,3 +25,98 @@ func TestLatestExchangeRateSliceLess(t *testing.T) {
 	assert.Equal(t, "EUR", latestExchangeRateSlice[1].Currency)
 	assert.Equal(t, "USD", latestExchangeRateSlice[2].Currency)
 }
+
+func TestLatestExchangeRateResponseConvertAmount(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		BaseCurrency: "USD",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "EUR", Rate: "0.8"},
+			&LatestExchangeRate{Currency: "CNY", Rate: "7.2"},
+			&LatestExchangeRate{Currency: "JPY", Rate: "invalid"},
+		},
+	}
+
+	amount, exists := exchangeRateResponse.ConvertAmount(1000, "USD", "EUR")
+	assert.True(t, exists)
+	assert.Equal(t, int64(800), amount)
+
+	amount, exists = exchangeRateResponse.ConvertAmount(800, "EUR", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, exists = exchangeRateResponse.ConvertAmount(800, "EUR", "CNY")
+	assert.True(t, exists)
+	assert.Equal(t, int64(7200), amount)
+
+	amount, exists = exchangeRateResponse.ConvertAmount(123, "GBP", "GBP")
+	assert.True(t, exists)
+	assert.Equal(t, int64(123), amount)
+
+	_, exists = exchangeRateResponse.ConvertAmount(1000, "GBP", "USD")
+	assert.False(t, exists)
+
+	_, exists = exchangeRateResponse.ConvertAmount(1000, "USD", "JPY")
+	assert.False(t, exists)
+}
+
+func TestLatestExchangeRateResponseClone(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		DataSource:   "euro_central_bank",
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "USD", Rate: "1.25"},
+		},
+	}
+
+	clonedResponse := exchangeRateResponse.Clone()
+	assert.Equal(t, exchangeRateResponse, clonedResponse)
+
+	clonedResponse.ExchangeRates[0].Rate = "2"
+	clonedResponse.ExchangeRates = append(clonedResponse.ExchangeRates, &LatestExchangeRate{Currency: "CNY", Rate: "8"})
+	assert.Equal(t, 1, len(exchangeRateResponse.ExchangeRates))
+	assert.Equal(t, "1.25", exchangeRateResponse.ExchangeRates[0].Rate)
+}
+
+func TestLatestExchangeRateResponseMergeExchangeRates(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "USD", Rate: "1.25"},
+		},
+	}
+
+	otherResponse := &LatestExchangeRateResponse{
+		BaseCurrency: "USD",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "EUR", Rate: "0.5"},
+			&LatestExchangeRate{Currency: "CNY", Rate: "4"},
+			&LatestExchangeRate{Currency: "JPY", Rate: "invalid"},
+		},
+	}
+
+	assert.Equal(t, 1, exchangeRateResponse.MergeExchangeRates(otherResponse))
+	assert.Equal(t, LatestExchangeRateSlice{
+		&LatestExchangeRate{Currency: "CNY", Rate: "8"},
+		&LatestExchangeRate{Currency: "USD", Rate: "1.25"},
+	}, exchangeRateResponse.ExchangeRates)
+
+	otherResponse = &LatestExchangeRateResponse{
+		BaseCurrency: "GBP",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "EUR", Rate: "1.25"},
+		},
+	}
+
+	assert.Equal(t, 1, exchangeRateResponse.MergeExchangeRates(otherResponse))
+	assert.Equal(t, &LatestExchangeRate{Currency: "GBP", Rate: "0.8"}, exchangeRateResponse.ExchangeRates[1])
+
+	otherResponse = &LatestExchangeRateResponse{
+		BaseCurrency: "JPY",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "HKD", Rate: "0.05"},
+		},
+	}
+
+	assert.Equal(t, 0, exchangeRateResponse.MergeExchangeRates(otherResponse))
+	assert.Equal(t, 3, len(exchangeRateResponse.ExchangeRates))
+}
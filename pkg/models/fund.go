@@ -5,8 +5,10 @@ type FundRole byte
 
 // Fund roles
 const (
-	FUND_ROLE_OWNER  FundRole = 1 // Full access
-	FUND_ROLE_MEMBER FundRole = 2 // Read-only
+	FUND_ROLE_OWNER       FundRole = 1 // Full access
+	FUND_ROLE_MEMBER      FundRole = 2 // Read-only
+	FUND_ROLE_EDITOR      FundRole = 3 // Can manage transactions, tags and templates
+	FUND_ROLE_CONTRIBUTOR FundRole = 4 // Can only add transactions and change their own ones
 )
 
 // String returns a textual representation of the fund role enum
@@ -16,6 +18,10 @@ func (r FundRole) String() string {
 		return "Owner"
 	case FUND_ROLE_MEMBER:
 		return "Member"
+	case FUND_ROLE_EDITOR:
+		return "Editor"
+	case FUND_ROLE_CONTRIBUTOR:
+		return "Contributor"
 	default:
 		return "Unknown"
 	}
@@ -48,8 +54,10 @@ type FundMember struct {
 
 // TransactionMember represents transaction-member relationship stored in database
 type TransactionMember struct {
-	TransactionId   int64 `xorm:"PK"`
-	MemberId        int64 `xorm:"PK INDEX(IDX_transaction_member_member_id)"` // FK to fund_member
+	TransactionId   int64                      `xorm:"PK"`
+	MemberId        int64                      `xorm:"PK INDEX(IDX_transaction_member_member_id)"` // FK to fund_member
+	SplitType       TransactionMemberSplitType `xorm:"TINYINT NOT NULL DEFAULT 1"`
+	SplitValue      int64                      `xorm:"NOT NULL DEFAULT 0"` // Weight, hundredths of a percent or exact amount according to split type
 	CreatedUnixTime int64
 }
 
@@ -85,9 +93,10 @@ type FundMemberListRequest struct {
 // FundMemberCreateRequest represents all parameters of fund member creation request
 type FundMemberCreateRequest struct {
 	// FundId          int64  `json:"fundId,string" binding:"required,min=1"`
-	Name            string `json:"name" binding:"required,notBlank,max=64"`
-	Email           string `json:"email" binding:"omitempty,max=100,validEmail"`
-	ClientSessionId string `json:"clientSessionId"`
+	Name            string   `json:"name" binding:"required,notBlank,max=64"`
+	Email           string   `json:"email" binding:"omitempty,max=100,validEmail"`
+	Role            FundRole `json:"role" binding:"omitempty,min=2,max=4"`
+	ClientSessionId string   `json:"clientSessionId"`
 }
 
 // FundMemberLinkRequest represents all parameters of fund member linking request
@@ -96,6 +105,24 @@ type FundMemberLinkRequest struct {
 	LinkedUid int64 `json:"linkedUid,string" binding:"required,min=1"`
 }
 
+// FundMemberRoleModifyRequest represents all parameters of fund member role modification request
+type FundMemberRoleModifyRequest struct {
+	// FundId will be retrieved from URL context parameter
+	MemberId int64    `json:"memberId,string" binding:"required,min=1"`
+	Role     FundRole `json:"role" binding:"required,min=2,max=4"`
+}
+
+// FundOwnershipTransferRequest represents all parameters of fund ownership transfer request
+type FundOwnershipTransferRequest struct {
+	// FundId will be retrieved from URL context parameter
+	MemberId int64 `json:"memberId,string" binding:"required,min=1"`
+}
+
+// FundLeaveRequest represents all parameters of fund leaving request
+type FundLeaveRequest struct {
+	// FundId will be retrieved from URL context parameter
+}
+
 // FundMemberDeleteRequest represents all parameters of fund member deleting request
 type FundMemberDeleteRequest struct {
 	// FundId will be retrieved from URL context parameter
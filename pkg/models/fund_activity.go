@@ -0,0 +1,239 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FundActivityEntityType represents the type of entity changed in a fund activity
+type FundActivityEntityType byte
+
+// Fund activity entity types
+const (
+	FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION FundActivityEntityType = 1
+	FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT     FundActivityEntityType = 2
+	FUND_ACTIVITY_ENTITY_TYPE_CATEGORY    FundActivityEntityType = 3
+	FUND_ACTIVITY_ENTITY_TYPE_FUND        FundActivityEntityType = 4
+	FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER FundActivityEntityType = 5
+)
+
+// String returns a textual representation of the fund activity entity type enum
+func (t FundActivityEntityType) String() string {
+	switch t {
+	case FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION:
+		return "Transaction"
+	case FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT:
+		return "Account"
+	case FUND_ACTIVITY_ENTITY_TYPE_CATEGORY:
+		return "Category"
+	case FUND_ACTIVITY_ENTITY_TYPE_FUND:
+		return "Fund"
+	case FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER:
+		return "FundMember"
+	default:
+		return "Unknown"
+	}
+}
+
+// FundActivityAction represents the action performed in a fund activity
+type FundActivityAction byte
+
+// Fund activity actions
+const (
+	FUND_ACTIVITY_ACTION_CREATE  FundActivityAction = 1
+	FUND_ACTIVITY_ACTION_MODIFY  FundActivityAction = 2
+	FUND_ACTIVITY_ACTION_DELETE  FundActivityAction = 3
+	FUND_ACTIVITY_ACTION_RESTORE FundActivityAction = 4
+)
+
+// String returns a textual representation of the fund activity action enum
+func (a FundActivityAction) String() string {
+	switch a {
+	case FUND_ACTIVITY_ACTION_CREATE:
+		return "Create"
+	case FUND_ACTIVITY_ACTION_MODIFY:
+		return "Modify"
+	case FUND_ACTIVITY_ACTION_DELETE:
+		return "Delete"
+	case FUND_ACTIVITY_ACTION_RESTORE:
+		return "Restore"
+	default:
+		return "Unknown"
+	}
+}
+
+// FundActivity represents fund activity data stored in database, fund activities are append-only
+type FundActivity struct {
+	ActivityId      int64                  `xorm:"PK"`
+	FundId          int64                  `xorm:"INDEX(IDX_fund_activity_fund_id_created_unix_time) NOT NULL"`
+	Uid             int64                  `xorm:"NOT NULL"` // The user who performed the action
+	EntityType      FundActivityEntityType `xorm:"TINYINT NOT NULL"`
+	EntityId        int64                  `xorm:"NOT NULL"`
+	Action          FundActivityAction     `xorm:"TINYINT NOT NULL"`
+	Changes         string                 `xorm:"TEXT"` // JSON object of changed fields with values before and after the action
+	CreatedUnixTime int64                  `xorm:"INDEX(IDX_fund_activity_fund_id_created_unix_time)"`
+}
+
+// FundActivityFieldChange represents the values of a field before and after the action
+type FundActivityFieldChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// FundActivityListRequest represents all parameters of fund activity list request
+type FundActivityListRequest struct {
+	// FundId will be retrieved from URL context parameter
+	ActorUid   int64                  `form:"actor_uid" binding:"min=0"`
+	EntityType FundActivityEntityType `form:"entity_type" binding:"min=0,max=5"`
+	MaxTime    int64                  `form:"max_time" binding:"min=0"` // Unix time
+	MinTime    int64                  `form:"min_time" binding:"min=0"` // Unix time
+	Page       int32                  `form:"page" binding:"min=0"`
+	Count      int32                  `form:"count" binding:"required,min=1,max=50"`
+}
+
+// FundActivityInfoResponse represents a view-object of fund activity
+type FundActivityInfoResponse struct {
+	Id         int64                               `json:"id,string"`
+	FundId     int64                               `json:"fundId,string"`
+	ActorUid   int64                               `json:"actorUid,string"`
+	EntityType FundActivityEntityType              `json:"entityType"`
+	EntityId   int64                               `json:"entityId,string"`
+	Action     FundActivityAction                  `json:"action"`
+	Changes    map[string]*FundActivityFieldChange `json:"changes"`
+	CreatedAt  int64                               `json:"createdAt"`
+}
+
+// FundActivityInfoPageWrapperResponse represents a response of fund activities which contains items and count
+type FundActivityInfoPageWrapperResponse struct {
+	Items      []*FundActivityInfoResponse `json:"items"`
+	TotalCount int64                       `json:"totalCount"`
+}
+
+// NewFundActivity returns a new fund activity model whose changes contain the specified fields of the entity
+// before and after the action, before should be nil for creation and after should be nil for deletion
+func NewFundActivity(fundId int64, uid int64, entityType FundActivityEntityType, entityId int64, action FundActivityAction, before any, after any, fields []string) *FundActivity {
+	activity := &FundActivity{
+		FundId:     fundId,
+		Uid:        uid,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Action:     action,
+	}
+
+	changes := GetFundActivityChanges(before, after, fields)
+
+	if len(changes) > 0 {
+		changesJson, err := json.Marshal(changes)
+
+		if err == nil {
+			activity.Changes = string(changesJson)
+		}
+	}
+
+	return activity
+}
+
+// GetFundActivityChanges returns the specified fields whose values are different between before and after,
+// the keys of result are field names in lower camel case
+func GetFundActivityChanges(before any, after any, fields []string) map[string]*FundActivityFieldChange {
+	changes := make(map[string]*FundActivityFieldChange)
+	beforeValue := getFundActivityEntityValue(before)
+	afterValue := getFundActivityEntityValue(after)
+
+	for _, field := range fields {
+		beforeFieldValue := getFundActivityFieldValue(beforeValue, field)
+		afterFieldValue := getFundActivityFieldValue(afterValue, field)
+
+		if beforeFieldValue == nil && afterFieldValue == nil {
+			continue
+		}
+
+		if reflect.DeepEqual(beforeFieldValue, afterFieldValue) {
+			continue
+		}
+
+		// Ids are serialized as strings, so that they would not lose precision in javascript
+		if isFundActivityIdField(field) {
+			beforeFieldValue = getFundActivityIdFieldValue(beforeFieldValue)
+			afterFieldValue = getFundActivityIdFieldValue(afterFieldValue)
+		}
+
+		changes[strings.ToLower(field[:1])+field[1:]] = &FundActivityFieldChange{
+			Before: beforeFieldValue,
+			After:  afterFieldValue,
+		}
+	}
+
+	return changes
+}
+
+// ToFundActivityInfoResponse returns a view-object according to database model
+func (a *FundActivity) ToFundActivityInfoResponse() *FundActivityInfoResponse {
+	changes := make(map[string]*FundActivityFieldChange)
+
+	if a.Changes != "" {
+		_ = json.Unmarshal([]byte(a.Changes), &changes)
+	}
+
+	return &FundActivityInfoResponse{
+		Id:         a.ActivityId,
+		FundId:     a.FundId,
+		ActorUid:   a.Uid,
+		EntityType: a.EntityType,
+		EntityId:   a.EntityId,
+		Action:     a.Action,
+		Changes:    changes,
+		CreatedAt:  a.CreatedUnixTime,
+	}
+}
+
+func getFundActivityEntityValue(entity any) reflect.Value {
+	if entity == nil {
+		return reflect.Value{}
+	}
+
+	value := reflect.ValueOf(entity)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	return value
+}
+
+func getFundActivityFieldValue(entity reflect.Value, field string) any {
+	if !entity.IsValid() {
+		return nil
+	}
+
+	fieldValue := entity.FieldByName(field)
+
+	if !fieldValue.IsValid() || !fieldValue.CanInterface() {
+		return nil
+	}
+
+	return fieldValue.Interface()
+}
+
+func isFundActivityIdField(field string) bool {
+	return strings.HasSuffix(field, "Id") || strings.HasSuffix(field, "Uid")
+}
+
+func getFundActivityIdFieldValue(value any) any {
+	switch id := value.(type) {
+	case int64:
+		return strconv.FormatInt(id, 10)
+	default:
+		return value
+	}
+}
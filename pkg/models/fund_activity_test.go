@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFundActivityChanges_Modify(t *testing.T) {
+	before := &Transaction{TransactionId: 1, Amount: 100, Comment: "lunch", CategoryId: 10}
+	after := &Transaction{TransactionId: 1, Amount: 120, Comment: "lunch", CategoryId: 11}
+
+	changes := GetFundActivityChanges(before, after, []string{"Amount", "Comment", "CategoryId"})
+
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, &FundActivityFieldChange{Before: int64(100), After: int64(120)}, changes["amount"])
+	assert.Equal(t, &FundActivityFieldChange{Before: "10", After: "11"}, changes["categoryId"])
+}
+
+func TestGetFundActivityChanges_LargeIds(t *testing.T) {
+	before := &Transaction{TransactionId: 1, AccountId: 3775081932386418689}
+	after := &Transaction{TransactionId: 1, AccountId: 3775081932386418690}
+
+	changes := GetFundActivityChanges(before, after, []string{"AccountId"})
+	assert.Equal(t, &FundActivityFieldChange{Before: "3775081932386418689", After: "3775081932386418690"}, changes["accountId"])
+
+	activity := &FundActivity{Changes: `{"accountId":{"before":"3775081932386418689","after":"3775081932386418690"},"amount":{"before":100}}`}
+	activityResp := activity.ToFundActivityInfoResponse()
+	assert.Equal(t, "3775081932386418689", activityResp.Changes["accountId"].Before)
+	assert.Equal(t, "3775081932386418690", activityResp.Changes["accountId"].After)
+	assert.Equal(t, float64(100), activityResp.Changes["amount"].Before)
+}
+
+func TestGetFundActivityChanges_CreateAndDelete(t *testing.T) {
+	account := &Account{AccountId: 1, Name: "Cash", Currency: "USD"}
+
+	changes := GetFundActivityChanges(nil, account, []string{"Name", "Currency"})
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, &FundActivityFieldChange{After: "Cash"}, changes["name"])
+
+	changes = GetFundActivityChanges(account, nil, []string{"Name", "Currency"})
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, &FundActivityFieldChange{Before: "USD"}, changes["currency"])
+}
+
+func TestGetFundActivityChanges_UnknownField(t *testing.T) {
+	changes := GetFundActivityChanges(&Account{Name: "a"}, &Account{Name: "b"}, []string{"NotExistedField"})
+	assert.Equal(t, 0, len(changes))
+}
+
+func TestNewFundActivity_NoChanges(t *testing.T) {
+	member := &FundMember{MemberId: 1, Name: "Alice", Role: FUND_ROLE_EDITOR}
+	activity := NewFundActivity(1001, 2001, FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, 1, FUND_ACTIVITY_ACTION_MODIFY, member, member, []string{"Name", "Role"})
+
+	assert.Equal(t, "", activity.Changes)
+}
+
+func TestFundActivity_ToFundActivityInfoResponse(t *testing.T) {
+	before := &FundMember{MemberId: 1, Role: FUND_ROLE_MEMBER}
+	after := &FundMember{MemberId: 1, Role: FUND_ROLE_EDITOR}
+	activity := NewFundActivity(1001, 2001, FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, 1, FUND_ACTIVITY_ACTION_MODIFY, before, after, []string{"Name", "Role"})
+	activity.ActivityId = 3001
+	activity.CreatedUnixTime = 1700000000
+
+	activityResp := activity.ToFundActivityInfoResponse()
+
+	assert.Equal(t, int64(3001), activityResp.Id)
+	assert.Equal(t, int64(1001), activityResp.FundId)
+	assert.Equal(t, int64(2001), activityResp.ActorUid)
+	assert.Equal(t, FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, activityResp.EntityType)
+	assert.Equal(t, FUND_ACTIVITY_ACTION_MODIFY, activityResp.Action)
+	assert.Equal(t, int64(1700000000), activityResp.CreatedAt)
+	assert.Equal(t, 1, len(activityResp.Changes))
+	assert.Equal(t, float64(FUND_ROLE_MEMBER), activityResp.Changes["role"].Before)
+	assert.Equal(t, float64(FUND_ROLE_EDITOR), activityResp.Changes["role"].After)
+}
+
+func TestFundActivityEntityType_String(t *testing.T) {
+	assert.Equal(t, "Transaction", FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION.String())
+	assert.Equal(t, "Account", FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT.String())
+	assert.Equal(t, "Category", FUND_ACTIVITY_ENTITY_TYPE_CATEGORY.String())
+	assert.Equal(t, "Fund", FUND_ACTIVITY_ENTITY_TYPE_FUND.String())
+	assert.Equal(t, "FundMember", FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER.String())
+	assert.Equal(t, "Unknown", FundActivityEntityType(0).String())
+}
+
+func TestFundActivityAction_String(t *testing.T) {
+	assert.Equal(t, "Create", FUND_ACTIVITY_ACTION_CREATE.String())
+	assert.Equal(t, "Modify", FUND_ACTIVITY_ACTION_MODIFY.String())
+	assert.Equal(t, "Delete", FUND_ACTIVITY_ACTION_DELETE.String())
+	assert.Equal(t, "Restore", FUND_ACTIVITY_ACTION_RESTORE.String())
+	assert.Equal(t, "Unknown", FundActivityAction(0).String())
+}
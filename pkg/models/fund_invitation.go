@@ -0,0 +1,121 @@
+package models
+
+// FundInvitationStatus represents fund invitation status
+type FundInvitationStatus byte
+
+// Fund invitation statuses
+const (
+	FUND_INVITATION_STATUS_PENDING  FundInvitationStatus = 1
+	FUND_INVITATION_STATUS_ACCEPTED FundInvitationStatus = 2
+	FUND_INVITATION_STATUS_DECLINED FundInvitationStatus = 3
+	FUND_INVITATION_STATUS_REVOKED  FundInvitationStatus = 4
+)
+
+// String returns a textual representation of the fund invitation status enum
+func (s FundInvitationStatus) String() string {
+	switch s {
+	case FUND_INVITATION_STATUS_PENDING:
+		return "Pending"
+	case FUND_INVITATION_STATUS_ACCEPTED:
+		return "Accepted"
+	case FUND_INVITATION_STATUS_DECLINED:
+		return "Declined"
+	case FUND_INVITATION_STATUS_REVOKED:
+		return "Revoked"
+	default:
+		return "Unknown"
+	}
+}
+
+// FundInvitation represents fund invitation data stored in database
+type FundInvitation struct {
+	InvitationId    int64                `xorm:"PK"`
+	FundId          int64                `xorm:"INDEX(IDX_fund_invitation_fund_id_status) NOT NULL"`
+	MemberId        int64                `xorm:"INDEX(IDX_fund_invitation_member_id) NOT NULL"` // FK to fund_member
+	InviterUid      int64                `xorm:"NOT NULL"`                                      // FK to inviting user
+	InviteeUid      int64                `xorm:"INDEX(IDX_fund_invitation_invitee_uid_status) NOT NULL"`
+	Email           string               `xorm:"VARCHAR(100) NOT NULL"`
+	Status          FundInvitationStatus `xorm:"INDEX(IDX_fund_invitation_fund_id_status) INDEX(IDX_fund_invitation_invitee_uid_status) TINYINT NOT NULL"`
+	ExpiredUnixTime int64
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// FundInvitationTokenContext represents the context data of fund invitation token
+type FundInvitationTokenContext struct {
+	InvitationId int64 `json:"invitationId,string"`
+}
+
+// FundInvitationListRequest represents all parameters of fund invitation list request
+type FundInvitationListRequest struct {
+	// FundId will be retrieved from URL context parameter
+}
+
+// FundInvitationCreateRequest represents all parameters of fund invitation creation request
+type FundInvitationCreateRequest struct {
+	// FundId will be retrieved from URL context parameter
+	MemberId int64 `json:"memberId,string" binding:"required,min=1"`
+}
+
+// FundInvitationRevokeRequest represents all parameters of fund invitation revoking request
+type FundInvitationRevokeRequest struct {
+	// FundId will be retrieved from URL context parameter
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// FundInvitationReplyRequest represents all parameters of fund invitation accepting or declining request
+type FundInvitationReplyRequest struct {
+	Id    int64  `json:"id,string" binding:"omitempty,min=1"`
+	Token string `json:"token" binding:"omitempty"`
+}
+
+// FundInvitationInfoResponse represents a view-object of fund invitation
+type FundInvitationInfoResponse struct {
+	Id         int64                `json:"id,string"`
+	FundId     int64                `json:"fundId,string"`
+	FundName   string               `json:"fundName,omitempty"`
+	MemberId   int64                `json:"memberId,string"`
+	InviterUid int64                `json:"inviterUid,string"`
+	Email      string               `json:"email"`
+	Status     FundInvitationStatus `json:"status"`
+	ExpiredAt  int64                `json:"expiredAt"`
+	CreatedAt  int64                `json:"createdAt"`
+}
+
+// IsExpired returns whether the invitation has expired at the specified unix time
+func (i *FundInvitation) IsExpired(now int64) bool {
+	return i.ExpiredUnixTime <= now
+}
+
+// ToFundInvitationInfoResponse returns a view-object according to database model
+func (i *FundInvitation) ToFundInvitationInfoResponse(fundName string) *FundInvitationInfoResponse {
+	return &FundInvitationInfoResponse{
+		Id:         i.InvitationId,
+		FundId:     i.FundId,
+		FundName:   fundName,
+		MemberId:   i.MemberId,
+		InviterUid: i.InviterUid,
+		Email:      i.Email,
+		Status:     i.Status,
+		ExpiredAt:  i.ExpiredUnixTime,
+		CreatedAt:  i.CreatedUnixTime,
+	}
+}
+
+// FundInvitationInfoResponseSlice represents the slice data structure of FundInvitationInfoResponse
+type FundInvitationInfoResponseSlice []*FundInvitationInfoResponse
+
+// Len returns the count of items
+func (s FundInvitationInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s FundInvitationInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s FundInvitationInfoResponseSlice) Less(i, j int) bool {
+	return s[i].CreatedAt > s[j].CreatedAt
+}
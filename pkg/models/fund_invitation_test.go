@@ -0,0 +1,66 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundInvitationStatus_String(t *testing.T) {
+	assert.Equal(t, "Pending", FUND_INVITATION_STATUS_PENDING.String())
+	assert.Equal(t, "Accepted", FUND_INVITATION_STATUS_ACCEPTED.String())
+	assert.Equal(t, "Declined", FUND_INVITATION_STATUS_DECLINED.String())
+	assert.Equal(t, "Revoked", FUND_INVITATION_STATUS_REVOKED.String())
+	assert.Equal(t, "Unknown", FundInvitationStatus(99).String())
+}
+
+func TestFundInvitation_IsExpired(t *testing.T) {
+	invitation := &FundInvitation{
+		ExpiredUnixTime: 1234567890,
+	}
+
+	assert.False(t, invitation.IsExpired(1234567889))
+	assert.True(t, invitation.IsExpired(1234567890))
+	assert.True(t, invitation.IsExpired(1234567891))
+}
+
+func TestFundInvitation_ToFundInvitationInfoResponse(t *testing.T) {
+	invitation := &FundInvitation{
+		InvitationId:    1001,
+		FundId:          2001,
+		MemberId:        3001,
+		InviterUid:      4001,
+		InviteeUid:      5001,
+		Email:           "jane@example.com",
+		Status:          FUND_INVITATION_STATUS_PENDING,
+		ExpiredUnixTime: 1235172690,
+		CreatedUnixTime: 1234567890,
+	}
+
+	response := invitation.ToFundInvitationInfoResponse("Household")
+
+	assert.Equal(t, int64(1001), response.Id)
+	assert.Equal(t, int64(2001), response.FundId)
+	assert.Equal(t, "Household", response.FundName)
+	assert.Equal(t, int64(3001), response.MemberId)
+	assert.Equal(t, int64(4001), response.InviterUid)
+	assert.Equal(t, "jane@example.com", response.Email)
+	assert.Equal(t, FUND_INVITATION_STATUS_PENDING, response.Status)
+	assert.Equal(t, int64(1235172690), response.ExpiredAt)
+	assert.Equal(t, int64(1234567890), response.CreatedAt)
+}
+
+func TestFundInvitationInfoResponseSlice_Sort(t *testing.T) {
+	responses := FundInvitationInfoResponseSlice{
+		{Id: 1, CreatedAt: 100},
+		{Id: 2, CreatedAt: 300},
+		{Id: 3, CreatedAt: 200},
+	}
+
+	sort.Sort(responses)
+
+	assert.Equal(t, int64(2), responses[0].Id)
+	assert.Equal(t, int64(3), responses[1].Id)
+	assert.Equal(t, int64(1), responses[2].Id)
+}
@@ -0,0 +1,207 @@
+package models
+
+// FundResource represents the kind of fund data which a permission applies to
+type FundResource byte
+
+// Fund resources
+const (
+	FUND_RESOURCE_ACCOUNT          FundResource = 1
+	FUND_RESOURCE_CATEGORY         FundResource = 2
+	FUND_RESOURCE_TAG              FundResource = 3
+	FUND_RESOURCE_TEMPLATE         FundResource = 4
+	FUND_RESOURCE_TRANSACTION      FundResource = 5
+	FUND_RESOURCE_BUDGET           FundResource = 6
+	FUND_RESOURCE_TRANSACTION_RULE FundResource = 7
+	FUND_RESOURCE_PAYEE            FundResource = 8
+	FUND_RESOURCE_INVESTMENT       FundResource = 9
+)
+
+// String returns a textual representation of the fund resource enum
+func (r FundResource) String() string {
+	switch r {
+	case FUND_RESOURCE_ACCOUNT:
+		return "Account"
+	case FUND_RESOURCE_CATEGORY:
+		return "Category"
+	case FUND_RESOURCE_TAG:
+		return "Tag"
+	case FUND_RESOURCE_TEMPLATE:
+		return "Template"
+	case FUND_RESOURCE_TRANSACTION:
+		return "Transaction"
+	case FUND_RESOURCE_BUDGET:
+		return "Budget"
+	case FUND_RESOURCE_TRANSACTION_RULE:
+		return "TransactionRule"
+	case FUND_RESOURCE_PAYEE:
+		return "Payee"
+	case FUND_RESOURCE_INVESTMENT:
+		return "Investment"
+	default:
+		return "Unknown"
+	}
+}
+
+// FundAction represents the operation which is performed on fund data
+type FundAction byte
+
+// Fund actions
+const (
+	FUND_ACTION_READ   FundAction = 1
+	FUND_ACTION_CREATE FundAction = 2
+	FUND_ACTION_MODIFY FundAction = 3
+	FUND_ACTION_DELETE FundAction = 4
+)
+
+// String returns a textual representation of the fund action enum
+func (a FundAction) String() string {
+	switch a {
+	case FUND_ACTION_READ:
+		return "Read"
+	case FUND_ACTION_CREATE:
+		return "Create"
+	case FUND_ACTION_MODIFY:
+		return "Modify"
+	case FUND_ACTION_DELETE:
+		return "Delete"
+	default:
+		return "Unknown"
+	}
+}
+
+// FundPermission represents the scope of data which a fund member can perform an action on
+type FundPermission byte
+
+// Fund permissions
+const (
+	FUND_PERMISSION_NONE FundPermission = 0 // Not allowed
+	FUND_PERMISSION_OWN  FundPermission = 1 // Allowed on data created by the member only
+	FUND_PERMISSION_ALL  FundPermission = 2 // Allowed on all data of the fund
+)
+
+// String returns a textual representation of the fund permission enum
+func (p FundPermission) String() string {
+	switch p {
+	case FUND_PERMISSION_NONE:
+		return "None"
+	case FUND_PERMISSION_OWN:
+		return "Own"
+	case FUND_PERMISSION_ALL:
+		return "All"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsAllowed returns whether the action is allowed at all
+func (p FundPermission) IsAllowed() bool {
+	return p == FUND_PERMISSION_OWN || p == FUND_PERMISSION_ALL
+}
+
+// IsAllowedOn returns whether the action is allowed on the data created by the specified user
+func (p FundPermission) IsAllowedOn(uid int64, creatorUid int64) bool {
+	if p == FUND_PERMISSION_ALL {
+		return true
+	}
+
+	return p == FUND_PERMISSION_OWN && uid == creatorUid
+}
+
+type fundResourcePermissions struct {
+	read   FundPermission
+	create FundPermission
+	modify FundPermission
+	delete FundPermission
+}
+
+var (
+	fundResourceFullAccess = fundResourcePermissions{read: FUND_PERMISSION_ALL, create: FUND_PERMISSION_ALL, modify: FUND_PERMISSION_ALL, delete: FUND_PERMISSION_ALL}
+	fundResourceReadOnly   = fundResourcePermissions{read: FUND_PERMISSION_ALL}
+)
+
+// fundPermissionMatrix defines what each fund role can do on each kind of fund data
+var fundPermissionMatrix = map[FundRole]map[FundResource]fundResourcePermissions{
+	FUND_ROLE_OWNER: {
+		FUND_RESOURCE_ACCOUNT:          fundResourceFullAccess,
+		FUND_RESOURCE_CATEGORY:         fundResourceFullAccess,
+		FUND_RESOURCE_TAG:              fundResourceFullAccess,
+		FUND_RESOURCE_TEMPLATE:         fundResourceFullAccess,
+		FUND_RESOURCE_TRANSACTION:      fundResourceFullAccess,
+		FUND_RESOURCE_BUDGET:           fundResourceFullAccess,
+		FUND_RESOURCE_TRANSACTION_RULE: fundResourceFullAccess,
+		FUND_RESOURCE_PAYEE:            fundResourceFullAccess,
+		FUND_RESOURCE_INVESTMENT:       fundResourceFullAccess,
+	},
+	FUND_ROLE_EDITOR: {
+		FUND_RESOURCE_ACCOUNT:          fundResourceReadOnly,
+		FUND_RESOURCE_CATEGORY:         fundResourceReadOnly,
+		FUND_RESOURCE_TAG:              fundResourceFullAccess,
+		FUND_RESOURCE_TEMPLATE:         fundResourceFullAccess,
+		FUND_RESOURCE_TRANSACTION:      fundResourceFullAccess,
+		FUND_RESOURCE_BUDGET:           fundResourceFullAccess,
+		FUND_RESOURCE_TRANSACTION_RULE: fundResourceFullAccess,
+		FUND_RESOURCE_PAYEE:            fundResourceFullAccess,
+		FUND_RESOURCE_INVESTMENT:       fundResourceFullAccess,
+	},
+	FUND_ROLE_CONTRIBUTOR: {
+		FUND_RESOURCE_ACCOUNT:  fundResourceReadOnly,
+		FUND_RESOURCE_CATEGORY: fundResourceReadOnly,
+		FUND_RESOURCE_TAG:      fundResourceReadOnly,
+		FUND_RESOURCE_TEMPLATE: fundResourceReadOnly,
+		FUND_RESOURCE_TRANSACTION: {
+			read:   FUND_PERMISSION_ALL,
+			create: FUND_PERMISSION_ALL,
+			modify: FUND_PERMISSION_OWN,
+			delete: FUND_PERMISSION_OWN,
+		},
+		FUND_RESOURCE_BUDGET:           fundResourceReadOnly,
+		FUND_RESOURCE_TRANSACTION_RULE: fundResourceReadOnly,
+		FUND_RESOURCE_PAYEE:            fundResourceReadOnly,
+		FUND_RESOURCE_INVESTMENT:       fundResourceReadOnly,
+	},
+	FUND_ROLE_MEMBER: {
+		FUND_RESOURCE_ACCOUNT:          fundResourceReadOnly,
+		FUND_RESOURCE_CATEGORY:         fundResourceReadOnly,
+		FUND_RESOURCE_TAG:              fundResourceReadOnly,
+		FUND_RESOURCE_TEMPLATE:         fundResourceReadOnly,
+		FUND_RESOURCE_TRANSACTION:      fundResourceReadOnly,
+		FUND_RESOURCE_BUDGET:           fundResourceReadOnly,
+		FUND_RESOURCE_TRANSACTION_RULE: fundResourceReadOnly,
+		FUND_RESOURCE_PAYEE:            fundResourceReadOnly,
+		FUND_RESOURCE_INVESTMENT:       fundResourceReadOnly,
+	},
+}
+
+// GetPermission returns the permission of the fund role for performing the action on the fund resource
+func (r FundRole) GetPermission(resource FundResource, action FundAction) FundPermission {
+	resourcePermissions, exists := fundPermissionMatrix[r]
+
+	if !exists {
+		return FUND_PERMISSION_NONE
+	}
+
+	permissions, exists := resourcePermissions[resource]
+
+	if !exists {
+		return FUND_PERMISSION_NONE
+	}
+
+	switch action {
+	case FUND_ACTION_READ:
+		return permissions.read
+	case FUND_ACTION_CREATE:
+		return permissions.create
+	case FUND_ACTION_MODIFY:
+		return permissions.modify
+	case FUND_ACTION_DELETE:
+		return permissions.delete
+	default:
+		return FUND_PERMISSION_NONE
+	}
+}
+
+// IsValid returns whether the fund role is a known role
+func (r FundRole) IsValid() bool {
+	_, exists := fundPermissionMatrix[r]
+	return exists
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allFundResources = []FundResource{
+	FUND_RESOURCE_ACCOUNT,
+	FUND_RESOURCE_CATEGORY,
+	FUND_RESOURCE_TAG,
+	FUND_RESOURCE_TEMPLATE,
+	FUND_RESOURCE_TRANSACTION,
+	FUND_RESOURCE_BUDGET,
+	FUND_RESOURCE_TRANSACTION_RULE,
+	FUND_RESOURCE_PAYEE,
+	FUND_RESOURCE_INVESTMENT,
+}
+
+var allFundActions = []FundAction{
+	FUND_ACTION_READ,
+	FUND_ACTION_CREATE,
+	FUND_ACTION_MODIFY,
+	FUND_ACTION_DELETE,
+}
+
+func assertFundRolePermissions(t *testing.T, role FundRole, expected map[FundResource][]FundPermission) {
+	for _, resource := range allFundResources {
+		expectedPermissions, exists := expected[resource]
+		assert.True(t, exists, "missing expected permissions of resource %s", resource)
+
+		for i, action := range allFundActions {
+			assert.Equal(t, expectedPermissions[i], role.GetPermission(resource, action), "role %s, resource %s, action %s", role, resource, action)
+		}
+	}
+}
+
+func TestFundRole_GetPermission_Owner(t *testing.T) {
+	all := []FundPermission{FUND_PERMISSION_ALL, FUND_PERMISSION_ALL, FUND_PERMISSION_ALL, FUND_PERMISSION_ALL}
+
+	assertFundRolePermissions(t, FUND_ROLE_OWNER, map[FundResource][]FundPermission{
+		FUND_RESOURCE_ACCOUNT:          all,
+		FUND_RESOURCE_CATEGORY:         all,
+		FUND_RESOURCE_TAG:              all,
+		FUND_RESOURCE_TEMPLATE:         all,
+		FUND_RESOURCE_TRANSACTION:      all,
+		FUND_RESOURCE_BUDGET:           all,
+		FUND_RESOURCE_TRANSACTION_RULE: all,
+		FUND_RESOURCE_PAYEE:            all,
+		FUND_RESOURCE_INVESTMENT:       all,
+	})
+}
+
+func TestFundRole_GetPermission_Editor(t *testing.T) {
+	all := []FundPermission{FUND_PERMISSION_ALL, FUND_PERMISSION_ALL, FUND_PERMISSION_ALL, FUND_PERMISSION_ALL}
+	readOnly := []FundPermission{FUND_PERMISSION_ALL, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE}
+
+	assertFundRolePermissions(t, FUND_ROLE_EDITOR, map[FundResource][]FundPermission{
+		FUND_RESOURCE_ACCOUNT:          readOnly,
+		FUND_RESOURCE_CATEGORY:         readOnly,
+		FUND_RESOURCE_TAG:              all,
+		FUND_RESOURCE_TEMPLATE:         all,
+		FUND_RESOURCE_TRANSACTION:      all,
+		FUND_RESOURCE_BUDGET:           all,
+		FUND_RESOURCE_TRANSACTION_RULE: all,
+		FUND_RESOURCE_PAYEE:            all,
+		FUND_RESOURCE_INVESTMENT:       all,
+	})
+}
+
+func TestFundRole_GetPermission_Contributor(t *testing.T) {
+	readOnly := []FundPermission{FUND_PERMISSION_ALL, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE}
+
+	assertFundRolePermissions(t, FUND_ROLE_CONTRIBUTOR, map[FundResource][]FundPermission{
+		FUND_RESOURCE_ACCOUNT:          readOnly,
+		FUND_RESOURCE_CATEGORY:         readOnly,
+		FUND_RESOURCE_TAG:              readOnly,
+		FUND_RESOURCE_TEMPLATE:         readOnly,
+		FUND_RESOURCE_TRANSACTION:      {FUND_PERMISSION_ALL, FUND_PERMISSION_ALL, FUND_PERMISSION_OWN, FUND_PERMISSION_OWN},
+		FUND_RESOURCE_BUDGET:           readOnly,
+		FUND_RESOURCE_TRANSACTION_RULE: readOnly,
+		FUND_RESOURCE_PAYEE:            readOnly,
+		FUND_RESOURCE_INVESTMENT:       readOnly,
+	})
+}
+
+func TestFundRole_GetPermission_Member(t *testing.T) {
+	readOnly := []FundPermission{FUND_PERMISSION_ALL, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE, FUND_PERMISSION_NONE}
+
+	assertFundRolePermissions(t, FUND_ROLE_MEMBER, map[FundResource][]FundPermission{
+		FUND_RESOURCE_ACCOUNT:          readOnly,
+		FUND_RESOURCE_CATEGORY:         readOnly,
+		FUND_RESOURCE_TAG:              readOnly,
+		FUND_RESOURCE_TEMPLATE:         readOnly,
+		FUND_RESOURCE_TRANSACTION:      readOnly,
+		FUND_RESOURCE_BUDGET:           readOnly,
+		FUND_RESOURCE_TRANSACTION_RULE: readOnly,
+		FUND_RESOURCE_PAYEE:            readOnly,
+		FUND_RESOURCE_INVESTMENT:       readOnly,
+	})
+}
+
+func TestFundRole_GetPermission_UnknownRoleResourceOrAction(t *testing.T) {
+	for _, resource := range allFundResources {
+		for _, action := range allFundActions {
+			assert.Equal(t, FUND_PERMISSION_NONE, FundRole(0).GetPermission(resource, action))
+			assert.Equal(t, FUND_PERMISSION_NONE, FundRole(99).GetPermission(resource, action))
+		}
+	}
+
+	assert.Equal(t, FUND_PERMISSION_NONE, FUND_ROLE_OWNER.GetPermission(FundResource(99), FUND_ACTION_READ))
+	assert.Equal(t, FUND_PERMISSION_NONE, FUND_ROLE_OWNER.GetPermission(FUND_RESOURCE_TRANSACTION, FundAction(99)))
+}
+
+func TestFundRole_IsValid(t *testing.T) {
+	assert.True(t, FUND_ROLE_OWNER.IsValid())
+	assert.True(t, FUND_ROLE_MEMBER.IsValid())
+	assert.True(t, FUND_ROLE_EDITOR.IsValid())
+	assert.True(t, FUND_ROLE_CONTRIBUTOR.IsValid())
+	assert.False(t, FundRole(0).IsValid())
+	assert.False(t, FundRole(99).IsValid())
+}
+
+func TestFundPermission_IsAllowed(t *testing.T) {
+	assert.False(t, FUND_PERMISSION_NONE.IsAllowed())
+	assert.True(t, FUND_PERMISSION_OWN.IsAllowed())
+	assert.True(t, FUND_PERMISSION_ALL.IsAllowed())
+	assert.False(t, FundPermission(99).IsAllowed())
+}
+
+func TestFundPermission_IsAllowedOn(t *testing.T) {
+	assert.False(t, FUND_PERMISSION_NONE.IsAllowedOn(1001, 1001))
+	assert.False(t, FUND_PERMISSION_NONE.IsAllowedOn(1001, 1002))
+	assert.True(t, FUND_PERMISSION_OWN.IsAllowedOn(1001, 1001))
+	assert.False(t, FUND_PERMISSION_OWN.IsAllowedOn(1001, 1002))
+	assert.True(t, FUND_PERMISSION_ALL.IsAllowedOn(1001, 1001))
+	assert.True(t, FUND_PERMISSION_ALL.IsAllowedOn(1001, 1002))
+}
+
+func TestFundResourceAndActionAndPermission_String(t *testing.T) {
+	assert.Equal(t, "Account", FUND_RESOURCE_ACCOUNT.String())
+	assert.Equal(t, "Category", FUND_RESOURCE_CATEGORY.String())
+	assert.Equal(t, "Tag", FUND_RESOURCE_TAG.String())
+	assert.Equal(t, "Template", FUND_RESOURCE_TEMPLATE.String())
+	assert.Equal(t, "Transaction", FUND_RESOURCE_TRANSACTION.String())
+	assert.Equal(t, "Budget", FUND_RESOURCE_BUDGET.String())
+	assert.Equal(t, "TransactionRule", FUND_RESOURCE_TRANSACTION_RULE.String())
+	assert.Equal(t, "Payee", FUND_RESOURCE_PAYEE.String())
+	assert.Equal(t, "Investment", FUND_RESOURCE_INVESTMENT.String())
+	assert.Equal(t, "Unknown", FundResource(99).String())
+
+	assert.Equal(t, "Read", FUND_ACTION_READ.String())
+	assert.Equal(t, "Create", FUND_ACTION_CREATE.String())
+	assert.Equal(t, "Modify", FUND_ACTION_MODIFY.String())
+	assert.Equal(t, "Delete", FUND_ACTION_DELETE.String())
+	assert.Equal(t, "Unknown", FundAction(99).String())
+
+	assert.Equal(t, "None", FUND_PERMISSION_NONE.String())
+	assert.Equal(t, "Own", FUND_PERMISSION_OWN.String())
+	assert.Equal(t, "All", FUND_PERMISSION_ALL.String())
+	assert.Equal(t, "Unknown", FundPermission(99).String())
+}
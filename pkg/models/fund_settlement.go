@@ -0,0 +1,274 @@
+package models
+
+import "sort"
+
+// FundSettlement represents fund settlement data stored in database
+type FundSettlement struct {
+	FundId          int64  `xorm:"PK"`
+	SettledTime     int64  `xorm:"PK"` // All shared expenses not later than this unix time are settled
+	Uid             int64  `xorm:"NOT NULL"`
+	TransactionIds  string `xorm:"TEXT NOT NULL"` // Comma separated ids of the repayment transfer transactions
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// FundSharedExpense represents an expense which is paid by one fund member and shared by some fund members
+type FundSharedExpense struct {
+	TransactionId int64
+	PayerMemberId int64
+	Currency      string
+	Amount        int64
+	MemberAmounts map[int64]int64 // The amount each linked member shares
+}
+
+// FundMemberBalance represents the paid and owed amounts of a fund member in one currency
+type FundMemberBalance struct {
+	MemberId int64
+	Currency string
+	Paid     int64
+	Owed     int64
+}
+
+// FundSettlementRepayment represents a repayment from one fund member to another
+type FundSettlementRepayment struct {
+	FromMemberId int64
+	ToMemberId   int64
+	Currency     string
+	Amount       int64
+}
+
+// FundSettlementGetRequest represents all parameters of fund settlement getting request
+type FundSettlementGetRequest struct {
+	// FundId will be retrieved from URL context parameter
+	EndTime int64 `form:"end_time" binding:"min=0"`
+}
+
+// FundSettlementRepaymentRequest represents all parameters of a repayment in fund settlement creation request
+type FundSettlementRepaymentRequest struct {
+	FromMemberId         int64  `json:"fromMemberId,string" binding:"required,min=1"`
+	ToMemberId           int64  `json:"toMemberId,string" binding:"required,min=1"`
+	Currency             string `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount               int64  `json:"amount" binding:"required,min=1"`
+	SourceAccountId      int64  `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64  `json:"destinationAccountId,string" binding:"required,min=1"`
+}
+
+// FundSettlementCreateRequest represents all parameters of fund settlement creation request
+type FundSettlementCreateRequest struct {
+	// FundId will be retrieved from URL context parameter
+	EndTime    int64                             `json:"endTime" binding:"required,min=1"`
+	CategoryId int64                             `json:"categoryId,string" binding:"required,min=1"`
+	UtcOffset  int16                             `json:"utcOffset" binding:"min=-720,max=840"`
+	Repayments []*FundSettlementRepaymentRequest `json:"repayments" binding:"omitempty,dive"`
+	Comment    string                            `json:"comment" binding:"max=255"`
+}
+
+// FundMemberBalanceResponse represents a view-object of fund member balance
+type FundMemberBalanceResponse struct {
+	MemberId int64  `json:"memberId,string"`
+	Currency string `json:"currency"`
+	Paid     int64  `json:"paid"`
+	Owed     int64  `json:"owed"`
+	Balance  int64  `json:"balance"`
+}
+
+// FundSettlementRepaymentResponse represents a view-object of fund settlement repayment
+type FundSettlementRepaymentResponse struct {
+	FromMemberId int64  `json:"fromMemberId,string"`
+	ToMemberId   int64  `json:"toMemberId,string"`
+	Currency     string `json:"currency"`
+	Amount       int64  `json:"amount"`
+}
+
+// FundSettlementResponse represents a view-object of unsettled fund balances
+type FundSettlementResponse struct {
+	FundId     int64                              `json:"fundId,string"`
+	StartTime  int64                              `json:"startTime"`
+	EndTime    int64                              `json:"endTime"`
+	Balances   []*FundMemberBalanceResponse       `json:"balances"`
+	Repayments []*FundSettlementRepaymentResponse `json:"repayments"`
+}
+
+// FundSettlementInfoResponse represents a view-object of fund settlement
+type FundSettlementInfoResponse struct {
+	FundId         int64    `json:"fundId,string"`
+	SettledTime    int64    `json:"settledTime"`
+	TransactionIds []string `json:"transactionIds"`
+	Comment        string   `json:"comment"`
+	CreatedAt      int64    `json:"createdAt"`
+}
+
+// Balance returns the amount which the fund member should receive (positive) or pay (negative)
+func (b *FundMemberBalance) Balance() int64 {
+	return b.Paid - b.Owed
+}
+
+// ToFundMemberBalanceResponse returns a view-object according to fund member balance
+func (b *FundMemberBalance) ToFundMemberBalanceResponse() *FundMemberBalanceResponse {
+	return &FundMemberBalanceResponse{
+		MemberId: b.MemberId,
+		Currency: b.Currency,
+		Paid:     b.Paid,
+		Owed:     b.Owed,
+		Balance:  b.Balance(),
+	}
+}
+
+// ToFundSettlementRepaymentResponse returns a view-object according to fund settlement repayment
+func (r *FundSettlementRepayment) ToFundSettlementRepaymentResponse() *FundSettlementRepaymentResponse {
+	return &FundSettlementRepaymentResponse{
+		FromMemberId: r.FromMemberId,
+		ToMemberId:   r.ToMemberId,
+		Currency:     r.Currency,
+		Amount:       r.Amount,
+	}
+}
+
+// ToFundSettlementInfoResponse returns a view-object according to database model
+func (s *FundSettlement) ToFundSettlementInfoResponse(transactionIds []string) *FundSettlementInfoResponse {
+	return &FundSettlementInfoResponse{
+		FundId:         s.FundId,
+		SettledTime:    s.SettledTime,
+		TransactionIds: transactionIds,
+		Comment:        s.Comment,
+		CreatedAt:      s.CreatedUnixTime,
+	}
+}
+
+// CalculateFundMemberBalances returns the balances of all fund members involved in the shared expenses,
+// the result is ordered by currency and member id
+func CalculateFundMemberBalances(expenses []*FundSharedExpense) []*FundMemberBalance {
+	type balanceKey struct {
+		currency string
+		memberId int64
+	}
+
+	balanceMap := make(map[balanceKey]*FundMemberBalance)
+	getBalance := func(currency string, memberId int64) *FundMemberBalance {
+		key := balanceKey{currency: currency, memberId: memberId}
+		balance, exists := balanceMap[key]
+
+		if !exists {
+			balance = &FundMemberBalance{
+				MemberId: memberId,
+				Currency: currency,
+			}
+			balanceMap[key] = balance
+		}
+
+		return balance
+	}
+
+	for _, expense := range expenses {
+		if len(expense.MemberAmounts) < 1 {
+			continue
+		}
+
+		getBalance(expense.Currency, expense.PayerMemberId).Paid += expense.Amount
+
+		for memberId, amount := range expense.MemberAmounts {
+			getBalance(expense.Currency, memberId).Owed += amount
+		}
+	}
+
+	balances := make([]*FundMemberBalance, 0, len(balanceMap))
+
+	for _, balance := range balanceMap {
+		balances = append(balances, balance)
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Currency != balances[j].Currency {
+			return balances[i].Currency < balances[j].Currency
+		}
+
+		return balances[i].MemberId < balances[j].MemberId
+	})
+
+	return balances
+}
+
+// SimplifyFundMemberBalances returns the repayments which settle all the balances, the largest debtor always pays
+// the largest creditor first, so there are at most n-1 repayments for n members in each currency
+func SimplifyFundMemberBalances(balances []*FundMemberBalance) []*FundSettlementRepayment {
+	type memberAmount struct {
+		memberId int64
+		amount   int64
+	}
+
+	debtors := make(map[string][]*memberAmount)
+	creditors := make(map[string][]*memberAmount)
+	currencies := make([]string, 0)
+
+	for _, balance := range balances {
+		amount := balance.Balance()
+
+		if amount == 0 {
+			continue
+		}
+
+		if _, exists := debtors[balance.Currency]; !exists {
+			if _, exists := creditors[balance.Currency]; !exists {
+				currencies = append(currencies, balance.Currency)
+			}
+		}
+
+		if amount < 0 {
+			debtors[balance.Currency] = append(debtors[balance.Currency], &memberAmount{memberId: balance.MemberId, amount: -amount})
+		} else {
+			creditors[balance.Currency] = append(creditors[balance.Currency], &memberAmount{memberId: balance.MemberId, amount: amount})
+		}
+	}
+
+	sort.Strings(currencies)
+
+	sortMemberAmounts := func(items []*memberAmount) {
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].amount != items[j].amount {
+				return items[i].amount > items[j].amount
+			}
+
+			return items[i].memberId < items[j].memberId
+		})
+	}
+
+	repayments := make([]*FundSettlementRepayment, 0)
+
+	for _, currency := range currencies {
+		currencyDebtors := debtors[currency]
+		currencyCreditors := creditors[currency]
+
+		sortMemberAmounts(currencyDebtors)
+		sortMemberAmounts(currencyCreditors)
+
+		for i, j := 0, 0; i < len(currencyDebtors) && j < len(currencyCreditors); {
+			debtor := currencyDebtors[i]
+			creditor := currencyCreditors[j]
+			amount := debtor.amount
+
+			if creditor.amount < amount {
+				amount = creditor.amount
+			}
+
+			repayments = append(repayments, &FundSettlementRepayment{
+				FromMemberId: debtor.memberId,
+				ToMemberId:   creditor.memberId,
+				Currency:     currency,
+				Amount:       amount,
+			})
+
+			debtor.amount -= amount
+			creditor.amount -= amount
+
+			if debtor.amount == 0 {
+				i++
+			}
+
+			if creditor.amount == 0 {
+				j++
+			}
+		}
+	}
+
+	return repayments
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateFundMemberBalances(t *testing.T) {
+	expenses := []*FundSharedExpense{
+		{PayerMemberId: 1, Currency: "USD", Amount: 1000, MemberAmounts: map[int64]int64{3: 333, 2: 333, 1: 334}},
+		{PayerMemberId: 2, Currency: "USD", Amount: 300, MemberAmounts: map[int64]int64{1: 150, 2: 150}},
+		{PayerMemberId: 1, Currency: "EUR", Amount: 500, MemberAmounts: map[int64]int64{2: 500}},
+		{PayerMemberId: 3, Currency: "USD", Amount: 700, MemberAmounts: map[int64]int64{}},
+	}
+
+	balances := CalculateFundMemberBalances(expenses)
+
+	assert.Equal(t, 5, len(balances))
+
+	assert.Equal(t, "EUR", balances[0].Currency)
+	assert.Equal(t, int64(1), balances[0].MemberId)
+	assert.Equal(t, int64(500), balances[0].Balance())
+
+	assert.Equal(t, "EUR", balances[1].Currency)
+	assert.Equal(t, int64(2), balances[1].MemberId)
+	assert.Equal(t, int64(-500), balances[1].Balance())
+
+	assert.Equal(t, "USD", balances[2].Currency)
+	assert.Equal(t, int64(1), balances[2].MemberId)
+	assert.Equal(t, int64(1000), balances[2].Paid)
+	assert.Equal(t, int64(334+150), balances[2].Owed)
+
+	assert.Equal(t, int64(2), balances[3].MemberId)
+	assert.Equal(t, int64(300), balances[3].Paid)
+	assert.Equal(t, int64(333+150), balances[3].Owed)
+
+	assert.Equal(t, int64(3), balances[4].MemberId)
+	assert.Equal(t, int64(0), balances[4].Paid)
+	assert.Equal(t, int64(333), balances[4].Owed)
+
+	total := int64(0)
+
+	for i := 2; i < len(balances); i++ {
+		total += balances[i].Balance()
+	}
+
+	assert.Equal(t, int64(0), total)
+}
+
+func TestSimplifyFundMemberBalances(t *testing.T) {
+	balances := []*FundMemberBalance{
+		{MemberId: 1, Currency: "USD", Paid: 3000, Owed: 1000},
+		{MemberId: 2, Currency: "USD", Paid: 0, Owed: 1000},
+		{MemberId: 3, Currency: "USD", Paid: 0, Owed: 1000},
+		{MemberId: 4, Currency: "USD", Paid: 500, Owed: 500},
+		{MemberId: 1, Currency: "EUR", Paid: 0, Owed: 200},
+		{MemberId: 2, Currency: "EUR", Paid: 200, Owed: 0},
+	}
+
+	repayments := SimplifyFundMemberBalances(balances)
+
+	assert.Equal(t, 3, len(repayments))
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 1, ToMemberId: 2, Currency: "EUR", Amount: 200}, repayments[0])
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 2, ToMemberId: 1, Currency: "USD", Amount: 1000}, repayments[1])
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 3, ToMemberId: 1, Currency: "USD", Amount: 1000}, repayments[2])
+}
+
+func TestSimplifyFundMemberBalances_PartialRepayments(t *testing.T) {
+	balances := []*FundMemberBalance{
+		{MemberId: 1, Currency: "USD", Paid: 700, Owed: 0},
+		{MemberId: 2, Currency: "USD", Paid: 300, Owed: 0},
+		{MemberId: 3, Currency: "USD", Paid: 0, Owed: 600},
+		{MemberId: 4, Currency: "USD", Paid: 0, Owed: 400},
+	}
+
+	repayments := SimplifyFundMemberBalances(balances)
+
+	assert.Equal(t, 3, len(repayments))
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 3, ToMemberId: 1, Currency: "USD", Amount: 600}, repayments[0])
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 4, ToMemberId: 1, Currency: "USD", Amount: 100}, repayments[1])
+	assert.Equal(t, &FundSettlementRepayment{FromMemberId: 4, ToMemberId: 2, Currency: "USD", Amount: 300}, repayments[2])
+}
+
+func TestSimplifyFundMemberBalances_AllSettled(t *testing.T) {
+	balances := []*FundMemberBalance{
+		{MemberId: 1, Currency: "USD", Paid: 500, Owed: 500},
+		{MemberId: 2, Currency: "USD", Paid: 0, Owed: 0},
+	}
+
+	repayments := SimplifyFundMemberBalances(balances)
+
+	assert.Equal(t, 0, len(repayments))
+}
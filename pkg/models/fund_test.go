@@ -10,6 +10,8 @@ import (
 func TestFundRole_String(t *testing.T) {
 	assert.Equal(t, "Owner", FUND_ROLE_OWNER.String())
 	assert.Equal(t, "Member", FUND_ROLE_MEMBER.String())
+	assert.Equal(t, "Editor", FUND_ROLE_EDITOR.String())
+	assert.Equal(t, "Contributor", FUND_ROLE_CONTRIBUTOR.String())
 	assert.Equal(t, "Unknown", FundRole(99).String())
 }
 
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumHistoricalExchangeRatesDays represents the maximum count of days in historical exchange rates request
+const MaximumHistoricalExchangeRatesDays = 366
+
+// HistoricalExchangeRate represents the exchange rate of a currency applicable to a date stored in database
+type HistoricalExchangeRate struct {
+	DataSource      string `xorm:"PK VARCHAR(64)"`
+	RateDate        string `xorm:"PK VARCHAR(10)"`
+	Currency        string `xorm:"PK VARCHAR(3)"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	UpdateTime      int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// HistoricalExchangeRatesRequest represents all parameters of historical exchange rates request
+type HistoricalExchangeRatesRequest struct {
+	StartDate string `form:"start_date" binding:"required,len=10"`
+	EndDate   string `form:"end_date" binding:"required,len=10"`
+	Currency  string `form:"currency" binding:"omitempty,len=3,validCurrency"`
+}
+
+// HistoricalExchangeRatesResponse represents a view-object of the exchange rates applicable to one date
+type HistoricalExchangeRatesResponse struct {
+	Date          string                  `json:"date"`
+	UpdateTime    int64                   `json:"updateTime"`
+	BaseCurrency  string                  `json:"baseCurrency"`
+	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+}
+
+// HistoricalExchangeRatesConverter converts amounts between currencies by the exchange rates applicable to the specified dates
+type HistoricalExchangeRatesConverter struct {
+	rateDates     []string
+	exchangeRates map[string]*LatestExchangeRateResponse
+}
+
+// TransactionAmountsConverter converts the amounts of transactions from the currencies of their accounts to the default currency
+// by the exchange rates applicable to the transaction dates
+type TransactionAmountsConverter struct {
+	DefaultCurrency   string
+	accountCurrencies map[int64]string
+	exchangeRates     *HistoricalExchangeRatesConverter
+}
+
+// ValidateHistoricalExchangeRatesDateRange returns whether the start date and end date are valid dates and the range is not too long
+func ValidateHistoricalExchangeRatesDateRange(startDate string, endDate string) error {
+	startTime, err := utils.ParseFromLongDateFirstTime(startDate, 0)
+
+	if err != nil {
+		return errs.ErrHistoricalExchangeRateDateInvalid
+	}
+
+	endTime, err := utils.ParseFromLongDateFirstTime(endDate, 0)
+
+	if err != nil {
+		return errs.ErrHistoricalExchangeRateDateInvalid
+	}
+
+	if startTime.After(endTime) {
+		return errs.ErrHistoricalExchangeRateDateRangeInvalid
+	}
+
+	if startTime.AddDate(0, 0, MaximumHistoricalExchangeRatesDays).Before(endTime.AddDate(0, 0, 1)) {
+		return errs.ErrHistoricalExchangeRateDateRangeTooLong
+	}
+
+	return nil
+}
+
+// CreateHistoricalExchangeRates returns the historical exchange rate database models of the specified date according to the exchange rates response
+func CreateHistoricalExchangeRates(dataSource string, rateDate string, exchangeRates *LatestExchangeRateResponse) []*HistoricalExchangeRate {
+	rates := make([]*HistoricalExchangeRate, 0, len(exchangeRates.ExchangeRates))
+
+	for i := 0; i < len(exchangeRates.ExchangeRates); i++ {
+		exchangeRate := exchangeRates.ExchangeRates[i]
+
+		rates = append(rates, &HistoricalExchangeRate{
+			DataSource:   dataSource,
+			RateDate:     rateDate,
+			Currency:     exchangeRate.Currency,
+			BaseCurrency: exchangeRates.BaseCurrency,
+			Rate:         exchangeRate.Rate,
+			UpdateTime:   exchangeRates.UpdateTime,
+		})
+	}
+
+	return rates
+}
+
+// ToHistoricalExchangeRatesResponses returns the view-objects of the exchange rates grouped by date in ascending order
+func ToHistoricalExchangeRatesResponses(rates []*HistoricalExchangeRate) []*HistoricalExchangeRatesResponse {
+	responsesMap := make(map[string]*HistoricalExchangeRatesResponse)
+	responses := make([]*HistoricalExchangeRatesResponse, 0)
+
+	for i := 0; i < len(rates); i++ {
+		rate := rates[i]
+		response, exists := responsesMap[rate.RateDate]
+
+		if !exists {
+			response = &HistoricalExchangeRatesResponse{
+				Date:          rate.RateDate,
+				UpdateTime:    rate.UpdateTime,
+				BaseCurrency:  rate.BaseCurrency,
+				ExchangeRates: make(LatestExchangeRateSlice, 0),
+			}
+
+			responsesMap[rate.RateDate] = response
+			responses = append(responses, response)
+		}
+
+		response.ExchangeRates = append(response.ExchangeRates, &LatestExchangeRate{
+			Currency: rate.Currency,
+			Rate:     rate.Rate,
+		})
+	}
+
+	sort.Slice(responses, func(i, j int) bool {
+		return strings.Compare(responses[i].Date, responses[j].Date) < 0
+	})
+
+	for i := 0; i < len(responses); i++ {
+		sort.Sort(responses[i].ExchangeRates)
+	}
+
+	return responses
+}
+
+// NewHistoricalExchangeRatesConverter returns a new converter of the specified historical exchange rates
+func NewHistoricalExchangeRatesConverter(rates []*HistoricalExchangeRate) *HistoricalExchangeRatesConverter {
+	converter := &HistoricalExchangeRatesConverter{
+		rateDates:     make([]string, 0),
+		exchangeRates: make(map[string]*LatestExchangeRateResponse),
+	}
+
+	for _, exchangeRates := range ToHistoricalExchangeRatesResponses(rates) {
+		converter.rateDates = append(converter.rateDates, exchangeRates.Date)
+		converter.exchangeRates[exchangeRates.Date] = &LatestExchangeRateResponse{
+			UpdateTime:    exchangeRates.UpdateTime,
+			BaseCurrency:  exchangeRates.BaseCurrency,
+			ExchangeRates: exchangeRates.ExchangeRates,
+		}
+	}
+
+	return converter
+}
+
+// ConvertAmount returns the amount converted by the latest exchange rates not later than the specified date, the earliest exchange rates are used
+// if the date is earlier than all of them, and whether the exchange rates of both currencies exist
+func (c *HistoricalExchangeRatesConverter) ConvertAmount(amount int64, fromCurrency string, toCurrency string, date string) (int64, bool) {
+	if fromCurrency == toCurrency {
+		return amount, true
+	}
+
+	if len(c.rateDates) < 1 {
+		return 0, false
+	}
+
+	index := sort.SearchStrings(c.rateDates, date)
+
+	if (index >= len(c.rateDates) || c.rateDates[index] != date) && index > 0 {
+		index--
+	}
+
+	return c.exchangeRates[c.rateDates[index]].ConvertAmount(amount, fromCurrency, toCurrency)
+}
+
+// NewTransactionAmountsConverter returns a new converter which converts the amounts of transactions in the specified accounts to the default currency
+func NewTransactionAmountsConverter(defaultCurrency string, accounts []*Account, exchangeRates *HistoricalExchangeRatesConverter) *TransactionAmountsConverter {
+	converter := &TransactionAmountsConverter{
+		DefaultCurrency:   defaultCurrency,
+		accountCurrencies: make(map[int64]string, len(accounts)),
+		exchangeRates:     exchangeRates,
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		converter.accountCurrencies[accounts[i].AccountId] = accounts[i].Currency
+	}
+
+	return converter
+}
+
+// ConvertAmount returns the amount of the transaction in the specified account converted to the default currency by the exchange rates applicable to the specified date
+func (c *TransactionAmountsConverter) ConvertAmount(accountId int64, amount int64, date string) (int64, error) {
+	currency, exists := c.accountCurrencies[accountId]
+
+	if !exists {
+		return 0, errs.ErrAccountNotFound
+	}
+
+	convertedAmount, exists := c.exchangeRates.ConvertAmount(amount, currency, c.DefaultCurrency, date)
+
+	if !exists {
+		return 0, errs.ErrHistoricalExchangeRateNotFound
+	}
+
+	return convertedAmount, nil
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestValidateHistoricalExchangeRatesDateRange(t *testing.T) {
+	assert.Nil(t, ValidateHistoricalExchangeRatesDateRange("2024-01-01", "2024-01-01"))
+	assert.Nil(t, ValidateHistoricalExchangeRatesDateRange("2024-01-01", "2024-12-31"))
+
+	assert.Equal(t, errs.ErrHistoricalExchangeRateDateInvalid, ValidateHistoricalExchangeRatesDateRange("2024/01/01", "2024-01-31"))
+	assert.Equal(t, errs.ErrHistoricalExchangeRateDateInvalid, ValidateHistoricalExchangeRatesDateRange("2024-01-01", "2024-02-30"))
+	assert.Equal(t, errs.ErrHistoricalExchangeRateDateRangeInvalid, ValidateHistoricalExchangeRatesDateRange("2024-01-02", "2024-01-01"))
+	assert.Equal(t, errs.ErrHistoricalExchangeRateDateRangeTooLong, ValidateHistoricalExchangeRatesDateRange("2024-01-01", "2025-01-01"))
+}
+
+func TestCreateHistoricalExchangeRates(t *testing.T) {
+	exchangeRates := &LatestExchangeRateResponse{
+		UpdateTime:   1617285600,
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1"},
+			{Currency: "USD", Rate: "1.1746"},
+		},
+	}
+
+	rates := CreateHistoricalExchangeRates("euro_central_bank", "2021-04-01", exchangeRates)
+	assert.Equal(t, 2, len(rates))
+	assert.Equal(t, &HistoricalExchangeRate{DataSource: "euro_central_bank", RateDate: "2021-04-01", Currency: "USD", BaseCurrency: "EUR", Rate: "1.1746", UpdateTime: 1617285600}, rates[1])
+}
+
+func TestToHistoricalExchangeRatesResponses(t *testing.T) {
+	rates := []*HistoricalExchangeRate{
+		{RateDate: "2021-04-02", Currency: "USD", BaseCurrency: "EUR", Rate: "1.1762", UpdateTime: 1617372000},
+		{RateDate: "2021-04-01", Currency: "USD", BaseCurrency: "EUR", Rate: "1.1746", UpdateTime: 1617285600},
+		{RateDate: "2021-04-01", Currency: "CNY", BaseCurrency: "EUR", Rate: "7.7195", UpdateTime: 1617285600},
+	}
+
+	responses := ToHistoricalExchangeRatesResponses(rates)
+	assert.Equal(t, 2, len(responses))
+
+	assert.Equal(t, "2021-04-01", responses[0].Date)
+	assert.Equal(t, int64(1617285600), responses[0].UpdateTime)
+	assert.Equal(t, "EUR", responses[0].BaseCurrency)
+	assert.Equal(t, LatestExchangeRateSlice{{Currency: "CNY", Rate: "7.7195"}, {Currency: "USD", Rate: "1.1746"}}, responses[0].ExchangeRates)
+
+	assert.Equal(t, "2021-04-02", responses[1].Date)
+	assert.Equal(t, LatestExchangeRateSlice{{Currency: "USD", Rate: "1.1762"}}, responses[1].ExchangeRates)
+}
+
+func TestHistoricalExchangeRatesConverterConvertAmount(t *testing.T) {
+	converter := NewHistoricalExchangeRatesConverter([]*HistoricalExchangeRate{
+		{RateDate: "2021-04-01", Currency: "USD", BaseCurrency: "EUR", Rate: "1.25"},
+		{RateDate: "2021-04-05", Currency: "USD", BaseCurrency: "EUR", Rate: "2"},
+	})
+
+	amount, exists := converter.ConvertAmount(1000, "USD", "EUR", "2021-04-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(800), amount)
+
+	amount, exists = converter.ConvertAmount(1000, "USD", "EUR", "2021-04-04")
+	assert.True(t, exists)
+	assert.Equal(t, int64(800), amount)
+
+	amount, exists = converter.ConvertAmount(1000, "EUR", "USD", "2021-04-05")
+	assert.True(t, exists)
+	assert.Equal(t, int64(2000), amount)
+
+	amount, exists = converter.ConvertAmount(1000, "USD", "EUR", "2022-01-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(500), amount)
+
+	amount, exists = converter.ConvertAmount(1000, "USD", "EUR", "2020-01-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(800), amount)
+
+	_, exists = converter.ConvertAmount(1000, "CNY", "EUR", "2021-04-01")
+	assert.False(t, exists)
+
+	amount, exists = NewHistoricalExchangeRatesConverter(nil).ConvertAmount(1000, "CNY", "CNY", "2021-04-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(1000), amount)
+
+	_, exists = NewHistoricalExchangeRatesConverter(nil).ConvertAmount(1000, "USD", "EUR", "2021-04-01")
+	assert.False(t, exists)
+}
+
+func TestTransactionAmountsConverterConvertAmount(t *testing.T) {
+	accounts := []*Account{
+		{AccountId: 1, Currency: "EUR"},
+		{AccountId: 2, Currency: "USD"},
+		{AccountId: 3, Currency: "CNY"},
+	}
+
+	exchangeRates := NewHistoricalExchangeRatesConverter([]*HistoricalExchangeRate{
+		{RateDate: "2021-04-01", Currency: "USD", BaseCurrency: "EUR", Rate: "1.25"},
+	})
+
+	converter := NewTransactionAmountsConverter("EUR", accounts, exchangeRates)
+
+	amount, err := converter.ConvertAmount(1, 1000, "2021-04-01")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, err = converter.ConvertAmount(2, 1000, "2021-04-01")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(800), amount)
+
+	_, err = converter.ConvertAmount(3, 1000, "2021-04-01")
+	assert.Equal(t, errs.ErrHistoricalExchangeRateNotFound, err)
+
+	_, err = converter.ConvertAmount(4, 1000, "2021-04-01")
+	assert.Equal(t, errs.ErrAccountNotFound, err)
+}
@@ -1,6 +1,35 @@
 package models
 
-import "github.com/mayswind/ezbookkeeping/pkg/utils"
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ImportTransactionDuplicateTimeWindow is the maximum difference in seconds between the time of an imported transaction and
+// the time of an existing transaction which is likely to be the same transaction
+const ImportTransactionDuplicateTimeWindow = 3 * 24 * 60 * 60
+
+// ImportTransactionDuplicateMinimumSimilarity is the minimum similarity of the descriptions of an imported transaction and
+// an existing transaction which is likely to be the same transaction, when both descriptions are not empty
+const ImportTransactionDuplicateMinimumSimilarity = 0.5
+
+// ImportTransactionDuplicateType represents how an imported transaction matches an existing transaction
+type ImportTransactionDuplicateType byte
+
+// Import transaction duplicate types
+const (
+	IMPORT_TRANSACTION_DUPLICATE_TYPE_NONE        ImportTransactionDuplicateType = 0
+	IMPORT_TRANSACTION_DUPLICATE_TYPE_EXTERNAL_ID ImportTransactionDuplicateType = 1 // Exactly matched by the unique identifier assigned by the bank
+	IMPORT_TRANSACTION_DUPLICATE_TYPE_LIKELY      ImportTransactionDuplicateType = 2 // Matched by account, amount, time and description
+)
+
+// TransactionDuplicateMatch represents an existing transaction which a new transaction duplicates
+type TransactionDuplicateMatch struct {
+	TransactionId int64
+	Type          ImportTransactionDuplicateType
+	Similarity    float64
+}
 
 // ImportTransaction represents the imported transaction data
 type ImportTransaction struct {
@@ -12,6 +41,19 @@ type ImportTransaction struct {
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	OriginalPayeeName                  string
+	Splits                             []*ImportTransactionSplit
+	DuplicateMatch                     *TransactionDuplicateMatch
+}
+
+// ImportTransactionSplit represents the imported line item of split transaction
+type ImportTransactionSplit struct {
+	CategoryId           int64
+	Amount               int64
+	TagIds               []string
+	Comment              string
+	OriginalCategoryName string
+	OriginalTagNames     []string
 }
 
 // ImportTransactionRequest represents all parameters of the imported transaction data
@@ -36,23 +78,39 @@ type ImportTransactionRequestItem struct {
 
 // ImportTransactionResponse represents a view-object of the imported transaction data
 type ImportTransactionResponse struct {
-	Type                               TransactionType                 `json:"type"`
-	CategoryId                         int64                           `json:"categoryId,string"`
-	OriginalCategoryName               string                          `json:"originalCategoryName"`
-	Time                               int64                           `json:"time"`
-	UtcOffset                          int16                           `json:"utcOffset"`
-	SourceAccountId                    int64                           `json:"sourceAccountId,string"`
-	OriginalSourceAccountName          string                          `json:"originalSourceAccountName"`
-	OriginalSourceAccountCurrency      string                          `json:"originalSourceAccountCurrency"`
-	DestinationAccountId               int64                           `json:"destinationAccountId,string,omitempty"`
-	OriginalDestinationAccountName     string                          `json:"originalDestinationAccountName,omitempty"`
-	OriginalDestinationAccountCurrency string                          `json:"originalDestinationAccountCurrency,omitempty"`
-	SourceAmount                       int64                           `json:"sourceAmount"`
-	DestinationAmount                  int64                           `json:"destinationAmount,omitempty"`
-	TagIds                             []string                        `json:"tagIds"`
-	OriginalTagNames                   []string                        `json:"originalTagNames"`
-	Comment                            string                          `json:"comment"`
-	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+	Type                               TransactionType                   `json:"type"`
+	CategoryId                         int64                             `json:"categoryId,string"`
+	OriginalCategoryName               string                            `json:"originalCategoryName"`
+	Time                               int64                             `json:"time"`
+	UtcOffset                          int16                             `json:"utcOffset"`
+	SourceAccountId                    int64                             `json:"sourceAccountId,string"`
+	OriginalSourceAccountName          string                            `json:"originalSourceAccountName"`
+	OriginalSourceAccountCurrency      string                            `json:"originalSourceAccountCurrency"`
+	DestinationAccountId               int64                             `json:"destinationAccountId,string,omitempty"`
+	OriginalDestinationAccountName     string                            `json:"originalDestinationAccountName,omitempty"`
+	OriginalDestinationAccountCurrency string                            `json:"originalDestinationAccountCurrency,omitempty"`
+	SourceAmount                       int64                             `json:"sourceAmount"`
+	DestinationAmount                  int64                             `json:"destinationAmount,omitempty"`
+	TagIds                             []string                          `json:"tagIds"`
+	OriginalTagNames                   []string                          `json:"originalTagNames"`
+	PayeeId                            int64                             `json:"payeeId,string,omitempty"`
+	OriginalPayeeName                  string                            `json:"originalPayeeName,omitempty"`
+	Comment                            string                            `json:"comment"`
+	ExternalId                         string                            `json:"externalId,omitempty"`
+	GeoLocation                        *TransactionGeoLocationResponse   `json:"geoLocation,omitempty"`
+	Splits                             []*ImportTransactionSplitResponse `json:"splits,omitempty"`
+	DuplicateType                      ImportTransactionDuplicateType    `json:"duplicateType,omitempty"`
+	DuplicateTransactionId             int64                             `json:"duplicateTransactionId,string,omitempty"`
+}
+
+// ImportTransactionSplitResponse represents a view-object of the imported line item of split transaction
+type ImportTransactionSplitResponse struct {
+	CategoryId           int64    `json:"categoryId,string"`
+	OriginalCategoryName string   `json:"originalCategoryName"`
+	Amount               int64    `json:"amount"`
+	TagIds               []string `json:"tagIds"`
+	OriginalTagNames     []string `json:"originalTagNames"`
+	Comment              string   `json:"comment"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -78,6 +136,27 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		geoLocation = nil
 	}
 
+	var splitResps []*ImportTransactionSplitResponse
+
+	for i := 0; i < len(t.Splits); i++ {
+		splitResps = append(splitResps, &ImportTransactionSplitResponse{
+			CategoryId:           t.Splits[i].CategoryId,
+			OriginalCategoryName: t.Splits[i].OriginalCategoryName,
+			Amount:               t.Splits[i].Amount,
+			TagIds:               t.Splits[i].TagIds,
+			OriginalTagNames:     t.Splits[i].OriginalTagNames,
+			Comment:              t.Splits[i].Comment,
+		})
+	}
+
+	duplicateType := IMPORT_TRANSACTION_DUPLICATE_TYPE_NONE
+	duplicateTransactionId := int64(0)
+
+	if t.DuplicateMatch != nil {
+		duplicateType = t.DuplicateMatch.Type
+		duplicateTransactionId = t.DuplicateMatch.TransactionId
+	}
+
 	return &ImportTransactionResponse{
 		Type:                               transactionType,
 		CategoryId:                         t.CategoryId,
@@ -94,8 +173,14 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		DestinationAmount:                  t.RelatedAccountAmount,
 		TagIds:                             t.TagIds,
 		OriginalTagNames:                   t.OriginalTagNames,
+		PayeeId:                            t.PayeeId,
+		OriginalPayeeName:                  t.OriginalPayeeName,
 		Comment:                            t.Comment,
+		ExternalId:                         t.ExternalId,
 		GeoLocation:                        geoLocation,
+		Splits:                             splitResps,
+		DuplicateType:                      duplicateType,
+		DuplicateTransactionId:             duplicateTransactionId,
 	}
 }
 
@@ -177,6 +262,127 @@ func (s ImportedTransactionSlice) ToTransactionTagIdsMap() (map[int][]int64, err
 	return transactionTagIdsMap, nil
 }
 
+// ToTransactionSplitsMap returns a map of line items of split transactions, the key of map is the index of transaction
+func (s ImportedTransactionSlice) ToTransactionSplitsMap() (map[int][]*TransactionSplit, error) {
+	transactionSplitsMap := make(map[int][]*TransactionSplit)
+
+	for i := 0; i < s.Len(); i++ {
+		if len(s[i].Splits) < 1 {
+			continue
+		}
+
+		splits := make([]*TransactionSplit, len(s[i].Splits))
+
+		for j := 0; j < len(s[i].Splits); j++ {
+			tagIds, err := utils.StringArrayToInt64Array(s[i].Splits[j].TagIds)
+
+			if err != nil {
+				return nil, err
+			}
+
+			splits[j] = &TransactionSplit{
+				LineIndex:  int32(j),
+				Uid:        s[i].Uid,
+				CategoryId: s[i].Splits[j].CategoryId,
+				Amount:     s[i].Splits[j].Amount,
+				TagIds:     strings.Join(utils.Int64ArrayToStringArray(tagIds), ","),
+				Comment:    s[i].Splits[j].Comment,
+			}
+		}
+
+		transactionSplitsMap[i] = splits
+	}
+
+	return transactionSplitsMap, nil
+}
+
+// FillDuplicateMatches fills the existing transactions which the imported transactions duplicate
+func (s ImportedTransactionSlice) FillDuplicateMatches(existingTransactions []*Transaction) {
+	duplicateMatches := FindDuplicateTransactions(s.ToTransactionsList(), existingTransactions)
+
+	for i := 0; i < s.Len(); i++ {
+		s[i].DuplicateMatch = duplicateMatches[i]
+	}
+}
+
+// FillPayees fills the matched payees of the imported transactions according to the original payee names, and fills the
+// default categories of the payees for income and expense transactions without category
+func (s ImportedTransactionSlice) FillPayees(payeeNameMap map[string]*Payee, categoryMap map[int64]*TransactionCategory) {
+	if len(payeeNameMap) < 1 {
+		return
+	}
+
+	for i := 0; i < s.Len(); i++ {
+		importedTransaction := s[i]
+
+		if importedTransaction.OriginalPayeeName == "" {
+			continue
+		}
+
+		payee, exists := payeeNameMap[NormalizePayeeName(importedTransaction.OriginalPayeeName)]
+
+		if !exists {
+			continue
+		}
+
+		importedTransaction.PayeeId = payee.PayeeId
+
+		if importedTransaction.CategoryId != 0 || payee.DefaultCategoryId == 0 || len(importedTransaction.Splits) > 0 {
+			continue
+		}
+
+		category, exists := categoryMap[payee.DefaultCategoryId]
+
+		if !exists {
+			continue
+		}
+
+		if (importedTransaction.Type == TRANSACTION_DB_TYPE_INCOME && category.Type == CATEGORY_TYPE_INCOME) ||
+			(importedTransaction.Type == TRANSACTION_DB_TYPE_EXPENSE && category.Type == CATEGORY_TYPE_EXPENSE) {
+			importedTransaction.CategoryId = category.CategoryId
+			importedTransaction.OriginalCategoryName = category.Name
+		}
+	}
+}
+
+// ApplyTransactionRules applies the matched transaction rules to the imported transactions, the names of categories
+// and tags set by rules are filled according to the specified category map and tag map
+func (s ImportedTransactionSlice) ApplyTransactionRules(ruleEngine *TransactionRuleEngine, categoryMap map[int64]*TransactionCategory, tagMap map[int64]*TransactionTag) {
+	if ruleEngine.IsEmpty() {
+		return
+	}
+
+	for i := 0; i < s.Len(); i++ {
+		importedTransaction := s[i]
+		result := ruleEngine.Evaluate(importedTransaction.Transaction)
+
+		if !result.IsMatched() {
+			continue
+		}
+
+		if category, exists := categoryMap[result.CategoryId]; exists {
+			importedTransaction.OriginalCategoryName = category.Name
+		}
+
+		currentTagIds := make([]int64, 0, len(importedTransaction.TagIds))
+
+		for _, tagId := range importedTransaction.TagIds {
+			if id, err := utils.StringToInt64(tagId); err == nil && id > 0 {
+				currentTagIds = append(currentTagIds, id)
+			}
+		}
+
+		newTagIds := result.ApplyTo(importedTransaction.Transaction, currentTagIds)
+
+		for _, tagId := range utils.Int64SliceMinus(newTagIds, currentTagIds) {
+			if tag, exists := tagMap[tagId]; exists {
+				importedTransaction.TagIds = append(importedTransaction.TagIds, utils.Int64ToString(tagId))
+				importedTransaction.OriginalTagNames = append(importedTransaction.OriginalTagNames, tag.Name)
+			}
+		}
+	}
+}
+
 // ToImportTransactionResponseList returns the a list of view-objects according to imported transaction data
 func (s ImportedTransactionSlice) ToImportTransactionResponseList() []*ImportTransactionResponse {
 	transactionResps := make([]*ImportTransactionResponse, 0, s.Len())
@@ -194,3 +400,117 @@ func (s ImportedTransactionSlice) ToImportTransactionResponseList() []*ImportTra
 
 	return transactionResps
 }
+
+// FindDuplicateTransactions returns the existing transaction which each new transaction duplicates, or nil if it does not
+// duplicate any existing transaction. A new transaction exactly matches the existing transaction with the same account
+// and external id, otherwise it likely matches the existing transaction with the same account, type and amount within
+// the time window, whose description is similar enough or whose payee is the same. Each existing transaction can be
+// matched by at most one new transaction.
+func FindDuplicateTransactions(newTransactions []*Transaction, existingTransactions []*Transaction) []*TransactionDuplicateMatch {
+	type externalIdKey struct {
+		accountId  int64
+		externalId string
+	}
+
+	duplicateMatches := make([]*TransactionDuplicateMatch, len(newTransactions))
+	matchedTransactionIds := make(map[int64]bool, len(newTransactions))
+	existingTransactionsByExternalId := make(map[externalIdKey]*Transaction)
+	existingTransactionsByAccountId := make(map[int64][]*Transaction)
+
+	for _, existingTransaction := range existingTransactions {
+		if existingTransaction.ExternalId != "" {
+			existingTransactionsByExternalId[externalIdKey{accountId: existingTransaction.AccountId, externalId: existingTransaction.ExternalId}] = existingTransaction
+		}
+
+		existingTransactionsByAccountId[existingTransaction.AccountId] = append(existingTransactionsByAccountId[existingTransaction.AccountId], existingTransaction)
+	}
+
+	for i, newTransaction := range newTransactions {
+		if newTransaction.AccountId <= 0 || newTransaction.ExternalId == "" {
+			continue
+		}
+
+		existingTransaction, exists := existingTransactionsByExternalId[externalIdKey{accountId: newTransaction.AccountId, externalId: newTransaction.ExternalId}]
+
+		if exists && !matchedTransactionIds[existingTransaction.TransactionId] {
+			matchedTransactionIds[existingTransaction.TransactionId] = true
+			duplicateMatches[i] = &TransactionDuplicateMatch{
+				TransactionId: existingTransaction.TransactionId,
+				Type:          IMPORT_TRANSACTION_DUPLICATE_TYPE_EXTERNAL_ID,
+				Similarity:    1,
+			}
+		}
+	}
+
+	for i, newTransaction := range newTransactions {
+		if duplicateMatches[i] != nil || newTransaction.AccountId <= 0 || newTransaction.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			continue
+		}
+
+		newTransactionUnixTime := utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime)
+		var bestMatchedTransaction *Transaction
+		bestSimilarity := float64(0)
+		bestTimeDifference := int64(0)
+
+		for _, existingTransaction := range existingTransactionsByAccountId[newTransaction.AccountId] {
+			if matchedTransactionIds[existingTransaction.TransactionId] ||
+				existingTransaction.Type != newTransaction.Type ||
+				existingTransaction.Amount != newTransaction.Amount {
+				continue
+			}
+
+			// Both transactions are from bank but with different identifiers, so they are not the same transaction
+			if newTransaction.ExternalId != "" && existingTransaction.ExternalId != "" {
+				continue
+			}
+
+			timeDifference := utils.GetUnixTimeFromTransactionTime(existingTransaction.TransactionTime) - newTransactionUnixTime
+
+			if timeDifference < 0 {
+				timeDifference = -timeDifference
+			}
+
+			if timeDifference > ImportTransactionDuplicateTimeWindow {
+				continue
+			}
+
+			similarity := getTransactionDescriptionSimilarity(newTransaction, existingTransaction)
+
+			if similarity < ImportTransactionDuplicateMinimumSimilarity {
+				continue
+			}
+
+			if bestMatchedTransaction == nil || similarity > bestSimilarity || (similarity == bestSimilarity && timeDifference < bestTimeDifference) {
+				bestMatchedTransaction = existingTransaction
+				bestSimilarity = similarity
+				bestTimeDifference = timeDifference
+			}
+		}
+
+		if bestMatchedTransaction != nil {
+			matchedTransactionIds[bestMatchedTransaction.TransactionId] = true
+			duplicateMatches[i] = &TransactionDuplicateMatch{
+				TransactionId: bestMatchedTransaction.TransactionId,
+				Type:          IMPORT_TRANSACTION_DUPLICATE_TYPE_LIKELY,
+				Similarity:    bestSimilarity,
+			}
+		}
+	}
+
+	return duplicateMatches
+}
+
+// getTransactionDescriptionSimilarity returns the similarity of the descriptions of two transactions, transactions with
+// the same payee are treated as the same description, and the similarity is regarded as the minimum similarity when
+// any description is empty because there is nothing to compare
+func getTransactionDescriptionSimilarity(transaction1 *Transaction, transaction2 *Transaction) float64 {
+	if transaction1.PayeeId > 0 && transaction1.PayeeId == transaction2.PayeeId {
+		return 1
+	}
+
+	if strings.TrimSpace(transaction1.Comment) == "" || strings.TrimSpace(transaction2.Comment) == "" {
+		return ImportTransactionDuplicateMinimumSimilarity
+	}
+
+	return utils.GetStringSimilarity(transaction1.Comment, transaction2.Comment)
+}
@@ -395,3 +395,229 @@ func TestImportTransactionSliceLess_SameTransactionTimeSameTypeSameSubCategoryNa
 	assert.Equal(t, int64(5), transactionSlice[6].TransactionId)
 	assert.Equal(t, int64(1), transactionSlice[7].TransactionId)
 }
+
+func TestImportTransactionSliceToTransactionSplitsMap(t *testing.T) {
+	var transactionSlice ImportedTransactionSlice
+	transactionSlice = append(transactionSlice, &ImportTransaction{
+		Transaction: &Transaction{
+			Uid:    1,
+			Type:   TRANSACTION_DB_TYPE_EXPENSE,
+			Amount: 100,
+		},
+	})
+	transactionSlice = append(transactionSlice, &ImportTransaction{
+		Transaction: &Transaction{
+			Uid:    1,
+			Type:   TRANSACTION_DB_TYPE_EXPENSE,
+			Amount: 1000,
+		},
+		Splits: []*ImportTransactionSplit{
+			{CategoryId: 11, Amount: 600, Comment: "Food"},
+			{CategoryId: 12, Amount: 400, TagIds: []string{"2", "3"}, Comment: "Soap"},
+		},
+	})
+
+	splitsMap, err := transactionSlice.ToTransactionSplitsMap()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(splitsMap))
+	assert.Equal(t, 2, len(splitsMap[1]))
+
+	assert.Equal(t, int32(0), splitsMap[1][0].LineIndex)
+	assert.Equal(t, int64(1), splitsMap[1][0].Uid)
+	assert.Equal(t, int64(11), splitsMap[1][0].CategoryId)
+	assert.Equal(t, int64(600), splitsMap[1][0].Amount)
+	assert.Equal(t, "", splitsMap[1][0].TagIds)
+	assert.Equal(t, "Food", splitsMap[1][0].Comment)
+
+	assert.Equal(t, int32(1), splitsMap[1][1].LineIndex)
+	assert.Equal(t, int64(12), splitsMap[1][1].CategoryId)
+	assert.Equal(t, int64(400), splitsMap[1][1].Amount)
+	assert.Equal(t, "2,3", splitsMap[1][1].TagIds)
+	assert.Equal(t, "Soap", splitsMap[1][1].Comment)
+
+	transactionSlice[1].Splits[1].TagIds = []string{"a"}
+	_, err = transactionSlice.ToTransactionSplitsMap()
+	assert.NotNil(t, err)
+}
+
+func TestImportedTransactionSliceApplyTransactionRules(t *testing.T) {
+	ruleEngine := NewTransactionRuleEngine([]*TransactionRule{
+		{RuleId: 1, TransactionType: TRANSACTION_TYPE_EXPENSE, CommentPattern: "(?i)coffee", SetCategoryId: 3001, AddTagIds: "4001,4002"},
+	})
+
+	categoryMap := map[int64]*TransactionCategory{
+		3001: {CategoryId: 3001, Name: "Drinks"},
+	}
+
+	tagMap := map[int64]*TransactionTag{
+		4001: {TagId: 4001, Name: "Daily"},
+		4002: {TagId: 4002, Name: "Cafe"},
+	}
+
+	transactions := ImportedTransactionSlice{
+		{
+			Transaction:          &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 0, Comment: "Coffee shop"},
+			TagIds:               []string{"4001", "0"},
+			OriginalCategoryName: "Unknown",
+			OriginalTagNames:     []string{"Daily", "NewTag"},
+		},
+		{
+			Transaction:          &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 3002, Comment: "Lunch"},
+			OriginalCategoryName: "Food",
+		},
+	}
+
+	transactions.ApplyTransactionRules(ruleEngine, categoryMap, tagMap)
+
+	assert.Equal(t, int64(3001), transactions[0].CategoryId)
+	assert.Equal(t, "Drinks", transactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"4001", "0", "4002"}, transactions[0].TagIds)
+	assert.Equal(t, []string{"Daily", "NewTag", "Cafe"}, transactions[0].OriginalTagNames)
+
+	assert.Equal(t, int64(3002), transactions[1].CategoryId)
+	assert.Equal(t, "Food", transactions[1].OriginalCategoryName)
+	assert.Equal(t, 0, len(transactions[1].TagIds))
+}
+
+func TestImportedTransactionSliceFillPayees(t *testing.T) {
+	payee := &Payee{PayeeId: 5001, Name: "Coffee House", Aliases: "COFFEE HSE", DefaultCategoryId: 3001}
+
+	payeeNameMap := map[string]*Payee{
+		"coffee house": payee,
+		"coffee hse":   payee,
+	}
+
+	categoryMap := map[int64]*TransactionCategory{
+		3001: {CategoryId: 3001, Name: "Drinks", Type: CATEGORY_TYPE_EXPENSE},
+	}
+
+	transactions := ImportedTransactionSlice{
+		{
+			Transaction:       &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 0},
+			OriginalPayeeName: "coffee  hse",
+		},
+		{
+			Transaction:          &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 3002},
+			OriginalPayeeName:    "Coffee House",
+			OriginalCategoryName: "Food",
+		},
+		{
+			Transaction:       &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 0},
+			OriginalPayeeName: "Coffee House",
+		},
+		{
+			Transaction:       &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 0},
+			OriginalPayeeName: "Unknown Shop",
+		},
+	}
+
+	transactions.FillPayees(payeeNameMap, categoryMap)
+
+	assert.Equal(t, int64(5001), transactions[0].PayeeId)
+	assert.Equal(t, int64(3001), transactions[0].CategoryId)
+	assert.Equal(t, "Drinks", transactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(5001), transactions[1].PayeeId)
+	assert.Equal(t, int64(3002), transactions[1].CategoryId)
+	assert.Equal(t, "Food", transactions[1].OriginalCategoryName)
+
+	assert.Equal(t, int64(5001), transactions[2].PayeeId)
+	assert.Equal(t, int64(0), transactions[2].CategoryId)
+
+	assert.Equal(t, int64(0), transactions[3].PayeeId)
+	assert.Equal(t, int64(0), transactions[3].CategoryId)
+}
+
+func TestFindDuplicateTransactions_ExternalId(t *testing.T) {
+	existingTransactions := []*Transaction{
+		{TransactionId: 1001, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A001"},
+		{TransactionId: 1002, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2002, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A002"},
+	}
+
+	newTransactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 2001, TransactionTime: 1735689600000, Amount: 5678, ExternalId: "A001"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A002"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2002, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A002"},
+	}
+
+	duplicateMatches := FindDuplicateTransactions(newTransactions, existingTransactions)
+
+	assert.Equal(t, 3, len(duplicateMatches))
+	assert.Equal(t, int64(1001), duplicateMatches[0].TransactionId)
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_EXTERNAL_ID, duplicateMatches[0].Type)
+	assert.Nil(t, duplicateMatches[1])
+	assert.Equal(t, int64(1002), duplicateMatches[2].TransactionId)
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_EXTERNAL_ID, duplicateMatches[2].Type)
+}
+
+func TestFindDuplicateTransactions_Likely(t *testing.T) {
+	existingTransactions := []*Transaction{
+		{TransactionId: 1001, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, Comment: "COFFEE HOUSE #123"},
+		{TransactionId: 1002, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 5678, Comment: "Grocery Store"},
+		{TransactionId: 1003, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 9999, PayeeId: 3001, Comment: "Online Order"},
+		{TransactionId: 1004, Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 2001, TransactionTime: 1725165296000, Amount: 100},
+	}
+
+	newTransactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725251696000, Amount: 1234, Comment: "Coffee House 123"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, Comment: "Coffee House 123"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 5678, Comment: "Gas Station"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 9999, PayeeId: 3001, Comment: "Amazon"},
+		{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 2001, TransactionTime: 1725165296000 + ImportTransactionDuplicateTimeWindow*1000 + 1000, Amount: 100},
+		{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 2002, TransactionTime: 1725165296000, Amount: 100},
+	}
+
+	duplicateMatches := FindDuplicateTransactions(newTransactions, existingTransactions)
+
+	assert.Equal(t, 6, len(duplicateMatches))
+	assert.Equal(t, int64(1001), duplicateMatches[0].TransactionId)
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_LIKELY, duplicateMatches[0].Type)
+	assert.Equal(t, float64(1), duplicateMatches[0].Similarity)
+	assert.Nil(t, duplicateMatches[1])
+	assert.Nil(t, duplicateMatches[2])
+	assert.Equal(t, int64(1003), duplicateMatches[3].TransactionId)
+	assert.Equal(t, float64(1), duplicateMatches[3].Similarity)
+	assert.Nil(t, duplicateMatches[4])
+	assert.Nil(t, duplicateMatches[5])
+}
+
+func TestFindDuplicateTransactions_DifferentExternalId(t *testing.T) {
+	existingTransactions := []*Transaction{
+		{TransactionId: 1001, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A001"},
+	}
+
+	newTransactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A002"},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234},
+	}
+
+	duplicateMatches := FindDuplicateTransactions(newTransactions, existingTransactions)
+
+	assert.Equal(t, 2, len(duplicateMatches))
+	assert.Nil(t, duplicateMatches[0])
+	assert.Equal(t, int64(1001), duplicateMatches[1].TransactionId)
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_LIKELY, duplicateMatches[1].Type)
+}
+
+func TestImportedTransactionSliceFillDuplicateMatches(t *testing.T) {
+	existingTransactions := []*Transaction{
+		{TransactionId: 1001, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A001"},
+	}
+
+	transactions := ImportedTransactionSlice{
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234, ExternalId: "A001"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1725165296000, Amount: 1234}},
+	}
+
+	transactions.FillDuplicateMatches(existingTransactions)
+
+	assert.Equal(t, int64(1001), transactions[0].DuplicateMatch.TransactionId)
+	assert.Nil(t, transactions[1].DuplicateMatch)
+
+	transactionResps := transactions.ToImportTransactionResponseList()
+
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_EXTERNAL_ID, transactionResps[0].DuplicateType)
+	assert.Equal(t, int64(1001), transactionResps[0].DuplicateTransactionId)
+	assert.Equal(t, IMPORT_TRANSACTION_DUPLICATE_TYPE_NONE, transactionResps[1].DuplicateType)
+	assert.Equal(t, int64(0), transactionResps[1].DuplicateTransactionId)
+}
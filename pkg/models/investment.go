@@ -0,0 +1,529 @@
+package models
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentQuantityScale represents the scale of the quantity of security, e.g. 15000 means 1.5 shares
+const InvestmentQuantityScale = 10000
+
+// MaximumSecurityPricesCountOfImport represents the maximum count of security prices which can be imported in one request
+const MaximumSecurityPricesCountOfImport = 1000
+
+const securityPriceDateFormat = "2006-01-02"
+
+// InvestmentCostBasisMethod represents the method of matching sold shares with bought shares
+type InvestmentCostBasisMethod byte
+
+// Investment cost basis methods
+const (
+	INVESTMENT_COST_BASIS_METHOD_FIFO         InvestmentCostBasisMethod = 1
+	INVESTMENT_COST_BASIS_METHOD_AVERAGE_COST InvestmentCostBasisMethod = 2
+)
+
+// InvestmentTradeType represents the type of investment trade
+type InvestmentTradeType byte
+
+// Investment trade types
+const (
+	INVESTMENT_TRADE_TYPE_BUY      InvestmentTradeType = 1
+	INVESTMENT_TRADE_TYPE_SELL     InvestmentTradeType = 2
+	INVESTMENT_TRADE_TYPE_DIVIDEND InvestmentTradeType = 3
+)
+
+// SecurityPriceSource represents where the security price comes from
+type SecurityPriceSource byte
+
+// Security price sources
+const (
+	SECURITY_PRICE_SOURCE_MANUAL   SecurityPriceSource = 1
+	SECURITY_PRICE_SOURCE_IMPORTED SecurityPriceSource = 2
+)
+
+// Security represents security (stock, fund, bond, etc.) data stored in database
+type Security struct {
+	SecurityId      int64                     `xorm:"PK"`
+	Uid             int64                     `xorm:"INDEX(IDX_security_uid_deleted_fund_id) NOT NULL"`
+	Deleted         bool                      `xorm:"INDEX(IDX_security_uid_deleted_fund_id) NOT NULL"`
+	FundId          int64                     `xorm:"INDEX(IDX_security_uid_deleted_fund_id) NOT NULL"`
+	Symbol          string                    `xorm:"VARCHAR(32) NOT NULL"`
+	Name            string                    `xorm:"VARCHAR(128) NOT NULL"`
+	Currency        string                    `xorm:"VARCHAR(3) NOT NULL"`
+	CostBasisMethod InvestmentCostBasisMethod `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvestmentTrade represents a buy, sell or dividend of a security in an investment account stored in database,
+// the balance of account is changed by the linked transaction which is created along with the trade
+type InvestmentTrade struct {
+	TradeId         int64               `xorm:"PK"`
+	Uid             int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_fund_id_account_id) INDEX(IDX_investment_trade_uid_deleted_fund_id_security_id) INDEX(IDX_investment_trade_uid_deleted_transaction_id) NOT NULL"`
+	Deleted         bool                `xorm:"INDEX(IDX_investment_trade_uid_deleted_fund_id_account_id) INDEX(IDX_investment_trade_uid_deleted_fund_id_security_id) INDEX(IDX_investment_trade_uid_deleted_transaction_id) NOT NULL"`
+	FundId          int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_fund_id_account_id) INDEX(IDX_investment_trade_uid_deleted_fund_id_security_id) NOT NULL"`
+	AccountId       int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_fund_id_account_id) NOT NULL"`
+	SecurityId      int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_fund_id_security_id) NOT NULL"`
+	Type            InvestmentTradeType `xorm:"NOT NULL"`
+	TradeTime       int64               `xorm:"NOT NULL"`
+	Quantity        int64               `xorm:"NOT NULL"` // Scaled by InvestmentQuantityScale
+	UnitPrice       int64               `xorm:"NOT NULL"`
+	Amount          int64               `xorm:"NOT NULL"` // Quantity multiplied by unit price for buy and sell, or the dividend amount
+	Fee             int64               `xorm:"NOT NULL"`
+	Comment         string              `xorm:"VARCHAR(255) NOT NULL"`
+	TransactionId   int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_transaction_id) NOT NULL DEFAULT 0"` // The transaction which changes the balance of account
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// SecurityPrice represents the closing price of a security on a date stored in database
+type SecurityPrice struct {
+	SecurityId      int64               `xorm:"PK"`
+	PriceDate       string              `xorm:"PK VARCHAR(10)"`
+	Uid             int64               `xorm:"INDEX(IDX_security_price_uid) NOT NULL"`
+	Price           int64               `xorm:"NOT NULL"`
+	Source          SecurityPriceSource `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// SecurityCreateRequest represents all parameters of security creation request
+type SecurityCreateRequest struct {
+	Symbol          string                    `json:"symbol" binding:"required,notBlank,max=32"`
+	Name            string                    `json:"name" binding:"max=128"`
+	Currency        string                    `json:"currency" binding:"required,len=3,validCurrency"`
+	CostBasisMethod InvestmentCostBasisMethod `json:"costBasisMethod" binding:"required,min=1,max=2"`
+}
+
+// SecurityModifyRequest represents all parameters of security modification request
+type SecurityModifyRequest struct {
+	Id              int64                     `json:"id,string" binding:"required,min=1"`
+	Symbol          string                    `json:"symbol" binding:"required,notBlank,max=32"`
+	Name            string                    `json:"name" binding:"max=128"`
+	Currency        string                    `json:"currency" binding:"required,len=3,validCurrency"`
+	CostBasisMethod InvestmentCostBasisMethod `json:"costBasisMethod" binding:"required,min=1,max=2"`
+}
+
+// SecurityDeleteRequest represents all parameters of security deleting request
+type SecurityDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// SecurityInfoResponse represents a view-object of security
+type SecurityInfoResponse struct {
+	Id              int64                     `json:"id,string"`
+	Symbol          string                    `json:"symbol"`
+	Name            string                    `json:"name"`
+	Currency        string                    `json:"currency"`
+	CostBasisMethod InvestmentCostBasisMethod `json:"costBasisMethod"`
+}
+
+// InvestmentTradeListRequest represents all parameters of investment trade listing request
+type InvestmentTradeListRequest struct {
+	AccountId  int64 `form:"account_id,string" binding:"min=0"`
+	SecurityId int64 `form:"security_id,string" binding:"min=0"`
+}
+
+// InvestmentTradeCreateRequest represents all parameters of investment trade creation request
+type InvestmentTradeCreateRequest struct {
+	AccountId  int64               `json:"accountId,string" binding:"required,min=1"`
+	SecurityId int64               `json:"securityId,string" binding:"required,min=1"`
+	Type       InvestmentTradeType `json:"type" binding:"required,min=1,max=3"`
+	TradeTime  int64               `json:"tradeTime" binding:"required,min=1"`
+	Quantity   int64               `json:"quantity" binding:"min=0,max=99999999999999"`
+	UnitPrice  int64               `json:"unitPrice" binding:"min=0,max=99999999999"`
+	Amount     int64               `json:"amount" binding:"min=0,max=99999999999"`
+	Fee        int64               `json:"fee" binding:"min=0,max=99999999999"`
+	Comment    string              `json:"comment" binding:"max=255"`
+	CategoryId int64               `json:"categoryId,string" binding:"required,min=1"` // The category of the transaction which changes the balance of account
+	UtcOffset  int16               `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// InvestmentTradeDeleteRequest represents all parameters of investment trade deleting request
+type InvestmentTradeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentTradeInfoResponse represents a view-object of investment trade
+type InvestmentTradeInfoResponse struct {
+	Id            int64               `json:"id,string"`
+	AccountId     int64               `json:"accountId,string"`
+	SecurityId    int64               `json:"securityId,string"`
+	Type          InvestmentTradeType `json:"type"`
+	TradeTime     int64               `json:"tradeTime"`
+	Quantity      int64               `json:"quantity"`
+	UnitPrice     int64               `json:"unitPrice"`
+	Amount        int64               `json:"amount"`
+	Fee           int64               `json:"fee"`
+	Comment       string              `json:"comment"`
+	TransactionId int64               `json:"transactionId,string"`
+}
+
+// SecurityPriceListRequest represents all parameters of security price listing request
+type SecurityPriceListRequest struct {
+	SecurityId int64 `form:"security_id,string" binding:"required,min=1"`
+}
+
+// SecurityPriceRequest represents the price of a security on a date in security price setting request
+type SecurityPriceRequest struct {
+	Date  string `json:"date" binding:"required,len=10"`
+	Price int64  `json:"price" binding:"required,min=1,max=99999999999"`
+}
+
+// SecurityPriceAddRequest represents all parameters of manually adding a security price request
+type SecurityPriceAddRequest struct {
+	SecurityId int64 `json:"securityId,string" binding:"required,min=1"`
+	SecurityPriceRequest
+}
+
+// SecurityPriceImportRequest represents all parameters of importing security price history request
+type SecurityPriceImportRequest struct {
+	SecurityId int64                   `json:"securityId,string" binding:"required,min=1"`
+	Prices     []*SecurityPriceRequest `json:"prices" binding:"required,min=1,dive"`
+}
+
+// SecurityPriceInfoResponse represents a view-object of security price
+type SecurityPriceInfoResponse struct {
+	Date   string              `json:"date"`
+	Price  int64               `json:"price"`
+	Source SecurityPriceSource `json:"source"`
+}
+
+// InvestmentHoldingsRequest represents all parameters of investment holdings request
+type InvestmentHoldingsRequest struct {
+	AccountId int64  `form:"account_id,string" binding:"min=0"`
+	Date      string `form:"date" binding:"omitempty,len=10"` // Today if empty
+}
+
+// InvestmentHoldingResponse represents a view-object of the holding of a security in an investment account
+type InvestmentHoldingResponse struct {
+	SecurityId      int64  `json:"securityId,string"`
+	Quantity        int64  `json:"quantity"`
+	CostBasis       int64  `json:"costBasis"`
+	MarketPrice     int64  `json:"marketPrice"`
+	MarketPriceDate string `json:"marketPriceDate,omitempty"` // Empty if the market price comes from the last trade
+	MarketValue     int64  `json:"marketValue"`
+	UnrealizedGain  int64  `json:"unrealizedGain"`
+	RealizedGain    int64  `json:"realizedGain"`
+	Dividends       int64  `json:"dividends"`
+}
+
+// InvestmentAccountHoldingsResponse represents a view-object of all holdings of an investment account
+type InvestmentAccountHoldingsResponse struct {
+	AccountId      int64                        `json:"accountId,string"`
+	Currency       string                       `json:"currency"`
+	CostBasis      int64                        `json:"costBasis"`
+	MarketValue    int64                        `json:"marketValue"`
+	UnrealizedGain int64                        `json:"unrealizedGain"`
+	RealizedGain   int64                        `json:"realizedGain"`
+	Dividends      int64                        `json:"dividends"`
+	Holdings       []*InvestmentHoldingResponse `json:"holdings"`
+}
+
+// InvestmentHolding represents the shares of a security held by an investment account which are built from all trades
+type InvestmentHolding struct {
+	AccountId      int64
+	SecurityId     int64
+	Quantity       int64
+	CostBasis      int64
+	RealizedGain   int64
+	Dividends      int64
+	LastTradePrice int64
+	lots           []*investmentLot
+}
+
+type investmentLot struct {
+	quantity int64
+	cost     int64
+}
+
+// IsValid returns whether the cost basis method is a known method
+func (m InvestmentCostBasisMethod) IsValid() bool {
+	return m == INVESTMENT_COST_BASIS_METHOD_FIFO || m == INVESTMENT_COST_BASIS_METHOD_AVERAGE_COST
+}
+
+// ToSecurityInfoResponse returns a view-object according to database model
+func (s *Security) ToSecurityInfoResponse() *SecurityInfoResponse {
+	return &SecurityInfoResponse{
+		Id:              s.SecurityId,
+		Symbol:          s.Symbol,
+		Name:            s.Name,
+		Currency:        s.Currency,
+		CostBasisMethod: s.CostBasisMethod,
+	}
+}
+
+// ToInvestmentTradeInfoResponse returns a view-object according to database model
+func (t *InvestmentTrade) ToInvestmentTradeInfoResponse() *InvestmentTradeInfoResponse {
+	return &InvestmentTradeInfoResponse{
+		Id:            t.TradeId,
+		AccountId:     t.AccountId,
+		SecurityId:    t.SecurityId,
+		Type:          t.Type,
+		TradeTime:     t.TradeTime,
+		Quantity:      t.Quantity,
+		UnitPrice:     t.UnitPrice,
+		Amount:        t.Amount,
+		Fee:           t.Fee,
+		Comment:       t.Comment,
+		TransactionId: t.TransactionId,
+	}
+}
+
+// ToLedgerTransaction returns a new transaction which changes the balance of account by the trade, the amount and fee
+// of a buy trade are paid as an expense, and the amount minus fee of a sell or dividend trade is received as an income
+func (t *InvestmentTrade) ToLedgerTransaction(categoryId int64, utcOffset int16) (*Transaction, error) {
+	transaction := &Transaction{
+		Uid:               t.Uid,
+		FundId:            t.FundId,
+		CategoryId:        categoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(t.TradeTime),
+		TimezoneUtcOffset: utcOffset,
+		AccountId:         t.AccountId,
+		Comment:           t.Comment,
+	}
+
+	switch t.Type {
+	case INVESTMENT_TRADE_TYPE_BUY:
+		transaction.Type = TRANSACTION_DB_TYPE_EXPENSE
+		transaction.Amount = t.Amount + t.Fee
+	case INVESTMENT_TRADE_TYPE_SELL, INVESTMENT_TRADE_TYPE_DIVIDEND:
+		if t.Fee > t.Amount {
+			return nil, errs.ErrInvestmentTradeFeeExceedsAmount
+		}
+
+		transaction.Type = TRANSACTION_DB_TYPE_INCOME
+		transaction.Amount = t.Amount - t.Fee
+	default:
+		return nil, errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	return transaction, nil
+}
+
+// ToSecurityPriceInfoResponse returns a view-object according to database model
+func (p *SecurityPrice) ToSecurityPriceInfoResponse() *SecurityPriceInfoResponse {
+	return &SecurityPriceInfoResponse{
+		Date:   p.PriceDate,
+		Price:  p.Price,
+		Source: p.Source,
+	}
+}
+
+// ValidateInvestmentTrade checks whether the quantity, unit price and amount are set correctly for the trade type,
+// and sets the amount of buy or sell trade to the quantity multiplied by the unit price
+func ValidateInvestmentTrade(trade *InvestmentTrade) error {
+	switch trade.Type {
+	case INVESTMENT_TRADE_TYPE_BUY, INVESTMENT_TRADE_TYPE_SELL:
+		if trade.Quantity <= 0 {
+			return errs.ErrInvestmentTradeQuantityInvalid
+		}
+
+		trade.Amount = multiplyAndDivide(trade.Quantity, trade.UnitPrice, InvestmentQuantityScale)
+	case INVESTMENT_TRADE_TYPE_DIVIDEND:
+		if trade.Quantity != 0 {
+			return errs.ErrInvestmentTradeQuantityInvalid
+		}
+
+		if trade.Amount <= 0 {
+			return errs.ErrInvestmentDividendAmountInvalid
+		}
+
+		trade.UnitPrice = 0
+	default:
+		return errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	return nil
+}
+
+// ValidateSecurityPriceDate checks whether the price date is in the format of yyyy-MM-dd
+func ValidateSecurityPriceDate(date string) error {
+	if _, err := time.Parse(securityPriceDateFormat, date); err != nil {
+		return errs.ErrSecurityPriceDateInvalid
+	}
+
+	return nil
+}
+
+// BuildInvestmentHoldings replays the trades in time order and returns the holding of each security in each account,
+// sold shares are matched with the earliest bought lots for FIFO method, or with the pooled lot for average cost method
+func BuildInvestmentHoldings(trades []*InvestmentTrade, securities map[int64]*Security) ([]*InvestmentHolding, error) {
+	sortedTrades := make([]*InvestmentTrade, len(trades))
+	copy(sortedTrades, trades)
+
+	sort.SliceStable(sortedTrades, func(i, j int) bool {
+		if sortedTrades[i].TradeTime != sortedTrades[j].TradeTime {
+			return sortedTrades[i].TradeTime < sortedTrades[j].TradeTime
+		}
+
+		return sortedTrades[i].TradeId < sortedTrades[j].TradeId
+	})
+
+	holdings := make([]*InvestmentHolding, 0)
+	holdingsMap := make(map[int64]map[int64]*InvestmentHolding)
+
+	for _, trade := range sortedTrades {
+		accountHoldings, exists := holdingsMap[trade.AccountId]
+
+		if !exists {
+			accountHoldings = make(map[int64]*InvestmentHolding)
+			holdingsMap[trade.AccountId] = accountHoldings
+		}
+
+		holding, exists := accountHoldings[trade.SecurityId]
+
+		if !exists {
+			holding = &InvestmentHolding{
+				AccountId:  trade.AccountId,
+				SecurityId: trade.SecurityId,
+			}
+
+			accountHoldings[trade.SecurityId] = holding
+			holdings = append(holdings, holding)
+		}
+
+		costBasisMethod := INVESTMENT_COST_BASIS_METHOD_FIFO
+
+		if security, exists := securities[trade.SecurityId]; exists && security.CostBasisMethod.IsValid() {
+			costBasisMethod = security.CostBasisMethod
+		}
+
+		err := holding.applyTrade(trade, costBasisMethod)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return holdings, nil
+}
+
+// ToInvestmentHoldingResponse returns a view-object of the holding valued at the specified price,
+// or at the unit price of last trade if the price is nil
+func (h *InvestmentHolding) ToInvestmentHoldingResponse(price *SecurityPrice) *InvestmentHoldingResponse {
+	holdingResp := &InvestmentHoldingResponse{
+		SecurityId:   h.SecurityId,
+		Quantity:     h.Quantity,
+		CostBasis:    h.CostBasis,
+		MarketPrice:  h.LastTradePrice,
+		RealizedGain: h.RealizedGain,
+		Dividends:    h.Dividends,
+	}
+
+	if price != nil {
+		holdingResp.MarketPrice = price.Price
+		holdingResp.MarketPriceDate = price.PriceDate
+	}
+
+	holdingResp.MarketValue = multiplyAndDivide(h.Quantity, holdingResp.MarketPrice, InvestmentQuantityScale)
+	holdingResp.UnrealizedGain = holdingResp.MarketValue - h.CostBasis
+
+	return holdingResp
+}
+
+// ToInvestmentAccountHoldingsResponses returns the view-objects of holdings grouped by account, the key of prices map is security id
+func ToInvestmentAccountHoldingsResponses(holdings []*InvestmentHolding, accounts map[int64]*Account, prices map[int64]*SecurityPrice) []*InvestmentAccountHoldingsResponse {
+	accountHoldingsResps := make([]*InvestmentAccountHoldingsResponse, 0)
+	accountHoldingsRespsMap := make(map[int64]*InvestmentAccountHoldingsResponse)
+
+	for _, holding := range holdings {
+		accountHoldingsResp, exists := accountHoldingsRespsMap[holding.AccountId]
+
+		if !exists {
+			accountHoldingsResp = &InvestmentAccountHoldingsResponse{
+				AccountId: holding.AccountId,
+				Holdings:  make([]*InvestmentHoldingResponse, 0),
+			}
+
+			if account, exists := accounts[holding.AccountId]; exists {
+				accountHoldingsResp.Currency = account.Currency
+			}
+
+			accountHoldingsRespsMap[holding.AccountId] = accountHoldingsResp
+			accountHoldingsResps = append(accountHoldingsResps, accountHoldingsResp)
+		}
+
+		holdingResp := holding.ToInvestmentHoldingResponse(prices[holding.SecurityId])
+
+		accountHoldingsResp.CostBasis += holdingResp.CostBasis
+		accountHoldingsResp.MarketValue += holdingResp.MarketValue
+		accountHoldingsResp.UnrealizedGain += holdingResp.UnrealizedGain
+		accountHoldingsResp.RealizedGain += holdingResp.RealizedGain
+		accountHoldingsResp.Dividends += holdingResp.Dividends
+		accountHoldingsResp.Holdings = append(accountHoldingsResp.Holdings, holdingResp)
+	}
+
+	return accountHoldingsResps
+}
+
+func (h *InvestmentHolding) applyTrade(trade *InvestmentTrade, costBasisMethod InvestmentCostBasisMethod) error {
+	switch trade.Type {
+	case INVESTMENT_TRADE_TYPE_BUY:
+		cost := trade.Amount + trade.Fee
+
+		if costBasisMethod == INVESTMENT_COST_BASIS_METHOD_AVERAGE_COST && len(h.lots) > 0 {
+			h.lots[0].quantity += trade.Quantity
+			h.lots[0].cost += cost
+		} else {
+			h.lots = append(h.lots, &investmentLot{
+				quantity: trade.Quantity,
+				cost:     cost,
+			})
+		}
+
+		h.Quantity += trade.Quantity
+		h.CostBasis += cost
+		h.LastTradePrice = trade.UnitPrice
+	case INVESTMENT_TRADE_TYPE_SELL:
+		if trade.Quantity > h.Quantity {
+			return errs.ErrInvestmentSellQuantityExceedsHolding
+		}
+
+		soldCost := int64(0)
+		remainingQuantity := trade.Quantity
+
+		for remainingQuantity > 0 && len(h.lots) > 0 {
+			lot := h.lots[0]
+
+			if lot.quantity <= remainingQuantity {
+				soldCost += lot.cost
+				remainingQuantity -= lot.quantity
+				h.lots = h.lots[1:]
+				continue
+			}
+
+			partialCost := multiplyAndDivide(lot.cost, remainingQuantity, lot.quantity)
+			lot.quantity -= remainingQuantity
+			lot.cost -= partialCost
+			soldCost += partialCost
+			remainingQuantity = 0
+		}
+
+		h.Quantity -= trade.Quantity
+		h.CostBasis -= soldCost
+		h.RealizedGain += trade.Amount - trade.Fee - soldCost
+		h.LastTradePrice = trade.UnitPrice
+	case INVESTMENT_TRADE_TYPE_DIVIDEND:
+		h.Dividends += trade.Amount - trade.Fee
+	default:
+		return errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	return nil
+}
+
+// multiplyAndDivide returns a * b / c rounded half up for non-negative values without overflow of the intermediate product
+func multiplyAndDivide(a int64, b int64, c int64) int64 {
+	result := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	result.Add(result, big.NewInt(c/2))
+	result.Quo(result, big.NewInt(c))
+
+	return result.Int64()
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestValidateInvestmentTrade_BuyAndSell(t *testing.T) {
+	trade := &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_BUY, Quantity: 25000, UnitPrice: 1234, Amount: 1}
+	assert.Nil(t, ValidateInvestmentTrade(trade))
+	assert.Equal(t, int64(3085), trade.Amount)
+
+	trade = &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_SELL, Quantity: 0, UnitPrice: 1234}
+	assert.Equal(t, errs.ErrInvestmentTradeQuantityInvalid, ValidateInvestmentTrade(trade))
+}
+
+func TestValidateInvestmentTrade_Dividend(t *testing.T) {
+	trade := &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_DIVIDEND, UnitPrice: 100, Amount: 500}
+	assert.Nil(t, ValidateInvestmentTrade(trade))
+	assert.Equal(t, int64(500), trade.Amount)
+	assert.Equal(t, int64(0), trade.UnitPrice)
+
+	trade = &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_DIVIDEND, Quantity: 10000, Amount: 500}
+	assert.Equal(t, errs.ErrInvestmentTradeQuantityInvalid, ValidateInvestmentTrade(trade))
+
+	trade = &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_DIVIDEND, Amount: 0}
+	assert.Equal(t, errs.ErrInvestmentDividendAmountInvalid, ValidateInvestmentTrade(trade))
+
+	trade = &InvestmentTrade{Type: 0, Quantity: 10000}
+	assert.Equal(t, errs.ErrInvestmentTradeTypeInvalid, ValidateInvestmentTrade(trade))
+}
+
+func TestInvestmentTradeToLedgerTransaction(t *testing.T) {
+	trade := &InvestmentTrade{Uid: 1001, FundId: 2001, AccountId: 3001, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 1725194096, Amount: 10000, Fee: 100, Comment: "buy"}
+	transaction, err := trade.ToLedgerTransaction(4001, 480)
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+	assert.Equal(t, int64(10100), transaction.Amount)
+	assert.Equal(t, int64(3001), transaction.AccountId)
+	assert.Equal(t, int64(4001), transaction.CategoryId)
+	assert.Equal(t, int64(2001), transaction.FundId)
+	assert.Equal(t, int16(480), transaction.TimezoneUtcOffset)
+	assert.Equal(t, "buy", transaction.Comment)
+
+	trade = &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_SELL, Amount: 10000, Fee: 100}
+	transaction, err = trade.ToLedgerTransaction(4002, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_DB_TYPE_INCOME, transaction.Type)
+	assert.Equal(t, int64(9900), transaction.Amount)
+
+	trade = &InvestmentTrade{Type: INVESTMENT_TRADE_TYPE_DIVIDEND, Amount: 50, Fee: 100}
+	_, err = trade.ToLedgerTransaction(4002, 0)
+	assert.Equal(t, errs.ErrInvestmentTradeFeeExceedsAmount, err)
+}
+
+func TestValidateSecurityPriceDate(t *testing.T) {
+	assert.Nil(t, ValidateSecurityPriceDate("2024-05-01"))
+	assert.Equal(t, errs.ErrSecurityPriceDateInvalid, ValidateSecurityPriceDate("2024-5-1"))
+	assert.Equal(t, errs.ErrSecurityPriceDateInvalid, ValidateSecurityPriceDate("2024-02-30"))
+}
+
+func TestBuildInvestmentHoldings_Fifo(t *testing.T) {
+	securities := map[int64]*Security{
+		1: {SecurityId: 1, CostBasisMethod: INVESTMENT_COST_BASIS_METHOD_FIFO},
+	}
+
+	trades := []*InvestmentTrade{
+		{TradeId: 3, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_SELL, TradeTime: 300, Quantity: 150000, UnitPrice: 3000, Amount: 45000},
+		{TradeId: 1, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 100, Quantity: 100000, UnitPrice: 1000, Amount: 10000},
+		{TradeId: 2, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 200, Quantity: 100000, UnitPrice: 2000, Amount: 20000},
+	}
+
+	holdings, err := BuildInvestmentHoldings(trades, securities)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(holdings))
+
+	assert.Equal(t, int64(50000), holdings[0].Quantity)
+	assert.Equal(t, int64(10000), holdings[0].CostBasis)
+	assert.Equal(t, int64(25000), holdings[0].RealizedGain)
+	assert.Equal(t, int64(3000), holdings[0].LastTradePrice)
+}
+
+func TestBuildInvestmentHoldings_AverageCost(t *testing.T) {
+	securities := map[int64]*Security{
+		1: {SecurityId: 1, CostBasisMethod: INVESTMENT_COST_BASIS_METHOD_AVERAGE_COST},
+	}
+
+	trades := []*InvestmentTrade{
+		{TradeId: 1, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 100, Quantity: 100000, UnitPrice: 1000, Amount: 10000},
+		{TradeId: 2, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 200, Quantity: 100000, UnitPrice: 2000, Amount: 20000},
+		{TradeId: 3, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_SELL, TradeTime: 300, Quantity: 150000, UnitPrice: 3000, Amount: 45000},
+	}
+
+	holdings, err := BuildInvestmentHoldings(trades, securities)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(holdings))
+
+	assert.Equal(t, int64(50000), holdings[0].Quantity)
+	assert.Equal(t, int64(7500), holdings[0].CostBasis)
+	assert.Equal(t, int64(22500), holdings[0].RealizedGain)
+}
+
+func TestBuildInvestmentHoldings_FeesAndDividends(t *testing.T) {
+	trades := []*InvestmentTrade{
+		{TradeId: 1, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 100, Quantity: 100000, UnitPrice: 1000, Amount: 10000, Fee: 100},
+		{TradeId: 2, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_DIVIDEND, TradeTime: 200, Amount: 500, Fee: 20},
+		{TradeId: 3, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_SELL, TradeTime: 300, Quantity: 100000, UnitPrice: 1200, Amount: 12000, Fee: 100},
+	}
+
+	holdings, err := BuildInvestmentHoldings(trades, map[int64]*Security{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(holdings))
+
+	assert.Equal(t, int64(0), holdings[0].Quantity)
+	assert.Equal(t, int64(0), holdings[0].CostBasis)
+	assert.Equal(t, int64(1800), holdings[0].RealizedGain)
+	assert.Equal(t, int64(480), holdings[0].Dividends)
+}
+
+func TestBuildInvestmentHoldings_SellQuantityExceedsHolding(t *testing.T) {
+	trades := []*InvestmentTrade{
+		{TradeId: 1, AccountId: 10, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_BUY, TradeTime: 100, Quantity: 100000, UnitPrice: 1000, Amount: 10000},
+		{TradeId: 2, AccountId: 11, SecurityId: 1, Type: INVESTMENT_TRADE_TYPE_SELL, TradeTime: 200, Quantity: 50000, UnitPrice: 1000, Amount: 5000},
+	}
+
+	_, err := BuildInvestmentHoldings(trades, map[int64]*Security{})
+	assert.Equal(t, errs.ErrInvestmentSellQuantityExceedsHolding, err)
+}
+
+func TestInvestmentHoldingToInvestmentHoldingResponse(t *testing.T) {
+	holding := &InvestmentHolding{SecurityId: 1, Quantity: 50000, CostBasis: 10000, RealizedGain: 300, Dividends: 40, LastTradePrice: 3000}
+
+	holdingResp := holding.ToInvestmentHoldingResponse(nil)
+	assert.Equal(t, int64(3000), holdingResp.MarketPrice)
+	assert.Equal(t, "", holdingResp.MarketPriceDate)
+	assert.Equal(t, int64(15000), holdingResp.MarketValue)
+	assert.Equal(t, int64(5000), holdingResp.UnrealizedGain)
+	assert.Equal(t, int64(300), holdingResp.RealizedGain)
+	assert.Equal(t, int64(40), holdingResp.Dividends)
+
+	holdingResp = holding.ToInvestmentHoldingResponse(&SecurityPrice{SecurityId: 1, PriceDate: "2024-05-01", Price: 1500})
+	assert.Equal(t, int64(1500), holdingResp.MarketPrice)
+	assert.Equal(t, "2024-05-01", holdingResp.MarketPriceDate)
+	assert.Equal(t, int64(7500), holdingResp.MarketValue)
+	assert.Equal(t, int64(-2500), holdingResp.UnrealizedGain)
+}
+
+func TestToInvestmentAccountHoldingsResponses(t *testing.T) {
+	holdings := []*InvestmentHolding{
+		{AccountId: 10, SecurityId: 1, Quantity: 10000, CostBasis: 1000, LastTradePrice: 1000},
+		{AccountId: 11, SecurityId: 1, Quantity: 20000, CostBasis: 1800, LastTradePrice: 1000},
+		{AccountId: 10, SecurityId: 2, Quantity: 10000, CostBasis: 2000, RealizedGain: 100, Dividends: 50, LastTradePrice: 2000},
+	}
+
+	accounts := map[int64]*Account{
+		10: {AccountId: 10, Currency: "USD"},
+		11: {AccountId: 11, Currency: "EUR"},
+	}
+
+	prices := map[int64]*SecurityPrice{
+		2: {SecurityId: 2, PriceDate: "2024-05-01", Price: 2500},
+	}
+
+	accountHoldingsResps := ToInvestmentAccountHoldingsResponses(holdings, accounts, prices)
+	assert.Equal(t, 2, len(accountHoldingsResps))
+
+	assert.Equal(t, int64(10), accountHoldingsResps[0].AccountId)
+	assert.Equal(t, "USD", accountHoldingsResps[0].Currency)
+	assert.Equal(t, 2, len(accountHoldingsResps[0].Holdings))
+	assert.Equal(t, int64(3000), accountHoldingsResps[0].CostBasis)
+	assert.Equal(t, int64(3500), accountHoldingsResps[0].MarketValue)
+	assert.Equal(t, int64(500), accountHoldingsResps[0].UnrealizedGain)
+	assert.Equal(t, int64(100), accountHoldingsResps[0].RealizedGain)
+	assert.Equal(t, int64(50), accountHoldingsResps[0].Dividends)
+
+	assert.Equal(t, int64(11), accountHoldingsResps[1].AccountId)
+	assert.Equal(t, "EUR", accountHoldingsResps[1].Currency)
+	assert.Equal(t, int64(2000), accountHoldingsResps[1].MarketValue)
+	assert.Equal(t, int64(200), accountHoldingsResps[1].UnrealizedGain)
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoanCompoundingType represents how often the interest of loan is compounded
+type LoanCompoundingType byte
+
+// Loan compounding types
+const (
+	LOAN_COMPOUNDING_TYPE_MONTHLY       LoanCompoundingType = 1
+	LOAN_COMPOUNDING_TYPE_DAILY         LoanCompoundingType = 2
+	LOAN_COMPOUNDING_TYPE_SEMI_ANNUALLY LoanCompoundingType = 3
+	LOAN_COMPOUNDING_TYPE_ANNUALLY      LoanCompoundingType = 4
+)
+
+// LoanInterestRateScale represents the scale of loan interest rate, e.g. the annual interest rate 4.25% is stored as 42500
+const LoanInterestRateScale = 10000
+
+// MaximumLoanTermMonths represents the maximum term in months of a loan
+const MaximumLoanTermMonths = 600
+
+// MaximumLoanPaymentTemplatesPeriodCount represents the maximum count of payment periods to create scheduled transaction templates at a time
+const MaximumLoanPaymentTemplatesPeriodCount = 24
+
+// AccountLoanInfo represents the loan info of debt account stored in account extend data
+type AccountLoanInfo struct {
+	Principal    int64               `json:"principal"`
+	InterestRate int64               `json:"interestRate"`
+	TermMonths   int32               `json:"termMonths"`
+	StartDate    string              `json:"startDate"`
+	PaymentDay   int                 `json:"paymentDay"`
+	Compounding  LoanCompoundingType `json:"compounding"`
+}
+
+// AccountLoanRequest represents all parameters of the loan info in account creation or modification request
+type AccountLoanRequest struct {
+	Principal    int64               `json:"principal" binding:"required,min=1,max=99999999999"`
+	InterestRate int64               `json:"interestRate" binding:"min=0,max=1000000"`
+	TermMonths   int32               `json:"termMonths" binding:"required,min=1,max=600"`
+	StartDate    string              `json:"startDate" binding:"required,len=10"`
+	PaymentDay   int                 `json:"paymentDay" binding:"required,min=1,max=28"`
+	Compounding  LoanCompoundingType `json:"compounding" binding:"required,min=1,max=4"`
+}
+
+// LoanAmortizationRequest represents all parameters of loan amortization schedule request
+type LoanAmortizationRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionTemplateLoanPaymentsCreateRequest represents all parameters of loan payment scheduled transaction templates creation request
+type TransactionTemplateLoanPaymentsCreateRequest struct {
+	LoanAccountId              int64    `json:"loanAccountId,string" binding:"required,min=1"`
+	PaymentAccountId           int64    `json:"paymentAccountId,string" binding:"required,min=1"`
+	PrincipalCategoryId        int64    `json:"principalCategoryId,string" binding:"required,min=1"`
+	InterestCategoryId         int64    `json:"interestCategoryId,string" binding:"required,min=1"`
+	FromPeriod                 int32    `json:"fromPeriod" binding:"required,min=1,max=600"`
+	PeriodCount                int32    `json:"periodCount" binding:"required,min=1,max=24"`
+	TagIds                     []string `json:"tagIds"`
+	ScheduledTimezoneUtcOffset int16    `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// LoanAmortizationItemResponse represents a view-object of one payment period in loan amortization schedule
+type LoanAmortizationItemResponse struct {
+	Period           int32  `json:"period"`
+	Date             string `json:"date"`
+	Payment          int64  `json:"payment"`
+	Principal        int64  `json:"principal"`
+	Interest         int64  `json:"interest"`
+	RemainingBalance int64  `json:"remainingBalance"`
+}
+
+// LoanAmortizationResponse represents a view-object of loan amortization schedule
+type LoanAmortizationResponse struct {
+	AccountId     int64                           `json:"accountId,string"`
+	Currency      string                          `json:"currency"`
+	Payment       int64                           `json:"payment"`
+	TotalPayment  int64                           `json:"totalPayment"`
+	TotalInterest int64                           `json:"totalInterest"`
+	Schedule      []*LoanAmortizationItemResponse `json:"schedule"`
+}
+
+// ToAccountLoanInfo returns the loan info according to the request, or an error if the start date or compounding type is invalid
+func (r *AccountLoanRequest) ToAccountLoanInfo() (*AccountLoanInfo, error) {
+	loanInfo := &AccountLoanInfo{
+		Principal:    r.Principal,
+		InterestRate: r.InterestRate,
+		TermMonths:   r.TermMonths,
+		StartDate:    r.StartDate,
+		PaymentDay:   r.PaymentDay,
+		Compounding:  r.Compounding,
+	}
+
+	if err := loanInfo.Validate(); err != nil {
+		return nil, err
+	}
+
+	return loanInfo, nil
+}
+
+// Validate returns whether all fields of the loan info are valid
+func (l *AccountLoanInfo) Validate() error {
+	if l.Principal <= 0 || l.InterestRate < 0 || l.TermMonths < 1 || l.TermMonths > MaximumLoanTermMonths || l.PaymentDay < 1 || l.PaymentDay > 28 {
+		return errs.ErrLoanInfoInvalid
+	}
+
+	if l.Compounding < LOAN_COMPOUNDING_TYPE_MONTHLY || l.Compounding > LOAN_COMPOUNDING_TYPE_ANNUALLY {
+		return errs.ErrLoanCompoundingTypeInvalid
+	}
+
+	if _, err := utils.ParseFromLongDateFirstTime(l.StartDate, 0); err != nil {
+		return errs.ErrLoanStartDateInvalid
+	}
+
+	return nil
+}
+
+// Equals returns whether the loan info is the same as the other one, nil values are equal to each other
+func (l *AccountLoanInfo) Equals(other *AccountLoanInfo) bool {
+	if l == nil || other == nil {
+		return l == nil && other == nil
+	}
+
+	return *l == *other
+}
+
+// GetMonthlyInterestRate returns the effective interest rate of each monthly payment period according to the compounding type
+func (l *AccountLoanInfo) GetMonthlyInterestRate() float64 {
+	annualRate := float64(l.InterestRate) / LoanInterestRateScale / 100
+
+	switch l.Compounding {
+	case LOAN_COMPOUNDING_TYPE_DAILY:
+		return math.Pow(1+annualRate/365, 365.0/12) - 1
+	case LOAN_COMPOUNDING_TYPE_SEMI_ANNUALLY:
+		return math.Pow(1+annualRate/2, 1.0/6) - 1
+	case LOAN_COMPOUNDING_TYPE_ANNUALLY:
+		return math.Pow(1+annualRate, 1.0/12) - 1
+	default:
+		return annualRate / 12
+	}
+}
+
+// GetMonthlyPayment returns the fixed payment of each period which pays off the loan at the end of term
+func (l *AccountLoanInfo) GetMonthlyPayment() int64 {
+	rate := l.GetMonthlyInterestRate()
+
+	if rate <= 0 {
+		return int64(math.Ceil(float64(l.Principal) / float64(l.TermMonths)))
+	}
+
+	return int64(math.Round(float64(l.Principal) * rate / (1 - math.Pow(1+rate, -float64(l.TermMonths)))))
+}
+
+// GetAmortizationSchedule returns all payment periods of the loan, the first payment is on the payment day of the month after start date,
+// and the last payment pays off all the remaining balance
+func (l *AccountLoanInfo) GetAmortizationSchedule() ([]*LoanAmortizationItemResponse, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	startDate, _ := utils.ParseFromLongDateFirstTime(l.StartDate, 0)
+	rate := l.GetMonthlyInterestRate()
+	payment := l.GetMonthlyPayment()
+	balance := l.Principal
+
+	schedule := make([]*LoanAmortizationItemResponse, 0, l.TermMonths)
+
+	for period := int32(1); period <= l.TermMonths && balance > 0; period++ {
+		paymentDate := time.Date(startDate.Year(), startDate.Month()+time.Month(period), l.PaymentDay, 0, 0, 0, 0, time.UTC)
+		interest := int64(math.Round(float64(balance) * rate))
+		principal := payment - interest
+
+		if principal > balance || period == l.TermMonths {
+			principal = balance
+		} else if principal < 0 {
+			principal = 0
+		}
+
+		balance -= principal
+
+		schedule = append(schedule, &LoanAmortizationItemResponse{
+			Period:           period,
+			Date:             utils.FormatUnixTimeToLongDate(paymentDate.Unix(), time.UTC),
+			Payment:          principal + interest,
+			Principal:        principal,
+			Interest:         interest,
+			RemainingBalance: balance,
+		})
+	}
+
+	return schedule, nil
+}
+
+// ToLoanAmortizationResponse returns a view-object of the amortization schedule of the debt account
+func (a *Account) ToLoanAmortizationResponse(schedule []*LoanAmortizationItemResponse) *LoanAmortizationResponse {
+	amortizationResp := &LoanAmortizationResponse{
+		AccountId: a.AccountId,
+		Currency:  a.Currency,
+		Schedule:  schedule,
+	}
+
+	if loanInfo := a.GetLoanInfo(); loanInfo != nil {
+		amortizationResp.Payment = loanInfo.GetMonthlyPayment()
+	}
+
+	for _, item := range schedule {
+		amortizationResp.TotalPayment += item.Payment
+		amortizationResp.TotalInterest += item.Interest
+	}
+
+	return amortizationResp
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestAccountLoanInfoValidate(t *testing.T) {
+	loanInfo := &AccountLoanInfo{Principal: 100000, InterestRate: 42500, TermMonths: 360, StartDate: "2024-01-15", PaymentDay: 5, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	assert.Nil(t, loanInfo.Validate())
+
+	loanInfo.StartDate = "2024/01/15"
+	assert.Equal(t, errs.ErrLoanStartDateInvalid, loanInfo.Validate())
+
+	loanInfo.StartDate = "2024-01-15"
+	loanInfo.Compounding = 5
+	assert.Equal(t, errs.ErrLoanCompoundingTypeInvalid, loanInfo.Validate())
+
+	loanInfo.Compounding = LOAN_COMPOUNDING_TYPE_MONTHLY
+	loanInfo.TermMonths = MaximumLoanTermMonths + 1
+	assert.Equal(t, errs.ErrLoanInfoInvalid, loanInfo.Validate())
+}
+
+func TestAccountLoanInfoEquals(t *testing.T) {
+	var nilLoanInfo *AccountLoanInfo
+	loanInfo := &AccountLoanInfo{Principal: 100000, InterestRate: 42500, TermMonths: 360, StartDate: "2024-01-15", PaymentDay: 5, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	sameLoanInfo := *loanInfo
+
+	assert.True(t, nilLoanInfo.Equals(nil))
+	assert.False(t, nilLoanInfo.Equals(loanInfo))
+	assert.False(t, loanInfo.Equals(nil))
+	assert.True(t, loanInfo.Equals(&sameLoanInfo))
+
+	sameLoanInfo.PaymentDay = 6
+	assert.False(t, loanInfo.Equals(&sameLoanInfo))
+}
+
+func TestAccountLoanInfoGetMonthlyInterestRate(t *testing.T) {
+	loanInfo := &AccountLoanInfo{InterestRate: 120000, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	assert.InDelta(t, 0.01, loanInfo.GetMonthlyInterestRate(), 1e-12)
+
+	loanInfo.Compounding = LOAN_COMPOUNDING_TYPE_ANNUALLY
+	assert.InDelta(t, 0.0094887929, loanInfo.GetMonthlyInterestRate(), 1e-9)
+
+	loanInfo.Compounding = LOAN_COMPOUNDING_TYPE_SEMI_ANNUALLY
+	assert.InDelta(t, 0.0097587942, loanInfo.GetMonthlyInterestRate(), 1e-9)
+
+	loanInfo.Compounding = LOAN_COMPOUNDING_TYPE_DAILY
+	assert.InDelta(t, 0.0100485071, loanInfo.GetMonthlyInterestRate(), 1e-9)
+}
+
+func TestAccountLoanInfoGetAmortizationSchedule(t *testing.T) {
+	loanInfo := &AccountLoanInfo{Principal: 100000, InterestRate: 120000, TermMonths: 12, StartDate: "2024-01-15", PaymentDay: 5, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	assert.Equal(t, int64(8885), loanInfo.GetMonthlyPayment())
+
+	schedule, err := loanInfo.GetAmortizationSchedule()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(schedule))
+
+	assert.Equal(t, int32(1), schedule[0].Period)
+	assert.Equal(t, "2024-02-05", schedule[0].Date)
+	assert.Equal(t, int64(8885), schedule[0].Payment)
+	assert.Equal(t, int64(1000), schedule[0].Interest)
+	assert.Equal(t, int64(7885), schedule[0].Principal)
+	assert.Equal(t, int64(92115), schedule[0].RemainingBalance)
+
+	assert.Equal(t, "2024-03-05", schedule[1].Date)
+	assert.Equal(t, int64(921), schedule[1].Interest)
+	assert.Equal(t, int64(7964), schedule[1].Principal)
+
+	assert.Equal(t, "2025-01-05", schedule[11].Date)
+	assert.Equal(t, int64(0), schedule[11].RemainingBalance)
+
+	totalPrincipal := int64(0)
+
+	for _, item := range schedule {
+		assert.Equal(t, item.Payment, item.Principal+item.Interest)
+		totalPrincipal += item.Principal
+	}
+
+	assert.Equal(t, loanInfo.Principal, totalPrincipal)
+}
+
+func TestAccountLoanInfoGetAmortizationSchedule_ZeroInterestRate(t *testing.T) {
+	loanInfo := &AccountLoanInfo{Principal: 1000, InterestRate: 0, TermMonths: 3, StartDate: "2024-11-30", PaymentDay: 28, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+
+	schedule, err := loanInfo.GetAmortizationSchedule()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(schedule))
+
+	assert.Equal(t, "2024-12-28", schedule[0].Date)
+	assert.Equal(t, int64(334), schedule[0].Principal)
+	assert.Equal(t, int64(0), schedule[0].Interest)
+	assert.Equal(t, "2025-01-28", schedule[1].Date)
+	assert.Equal(t, int64(334), schedule[1].Principal)
+	assert.Equal(t, "2025-02-28", schedule[2].Date)
+	assert.Equal(t, int64(332), schedule[2].Principal)
+	assert.Equal(t, int64(0), schedule[2].RemainingBalance)
+}
+
+func TestAccountToLoanAmortizationResponse(t *testing.T) {
+	loanInfo := &AccountLoanInfo{Principal: 1000, InterestRate: 0, TermMonths: 3, StartDate: "2024-11-30", PaymentDay: 28, Compounding: LOAN_COMPOUNDING_TYPE_MONTHLY}
+	account := &Account{AccountId: 1, Category: ACCOUNT_CATEGORY_DEBT, Currency: "USD", Extend: &AccountExtend{Loan: loanInfo}}
+
+	schedule, err := loanInfo.GetAmortizationSchedule()
+	assert.Nil(t, err)
+
+	amortizationResp := account.ToLoanAmortizationResponse(schedule)
+	assert.Equal(t, int64(1), amortizationResp.AccountId)
+	assert.Equal(t, "USD", amortizationResp.Currency)
+	assert.Equal(t, int64(334), amortizationResp.Payment)
+	assert.Equal(t, int64(1000), amortizationResp.TotalPayment)
+	assert.Equal(t, int64(0), amortizationResp.TotalInterest)
+}
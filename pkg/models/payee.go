@@ -0,0 +1,255 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+const MaximumAliasesCountOfPayee = 20
+
+const payeeAliasesSeparator = "\n"
+
+// Payee represents payee (merchant or counterparty) data stored in database
+type Payee struct {
+	PayeeId           int64   `xorm:"PK"`
+	Uid               int64   `xorm:"INDEX(IDX_payee_fund_uid_deleted_name) NOT NULL"`
+	FundId            int64   `xorm:"INDEX(IDX_payee_fund_uid_deleted_name) NOT NULL"`
+	Deleted           bool    `xorm:"INDEX(IDX_payee_fund_uid_deleted_name) NOT NULL"`
+	Name              string  `xorm:"INDEX(IDX_payee_fund_uid_deleted_name) VARCHAR(128) NOT NULL"`
+	Aliases           string  `xorm:"VARCHAR(2600) NOT NULL"`
+	DefaultCategoryId int64   `xorm:"NOT NULL"`
+	Address           string  `xorm:"VARCHAR(255) NOT NULL"`
+	GeoLongitude      float64 `xorm:"NOT NULL"`
+	GeoLatitude       float64 `xorm:"NOT NULL"`
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	DeletedUnixTime   int64
+}
+
+// PayeeGetRequest represents all parameters of payee getting request
+type PayeeGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// PayeeCreateRequest represents all parameters of payee creation request
+type PayeeCreateRequest struct {
+	Name              string                         `json:"name" binding:"required,notBlank,max=128"`
+	Aliases           []string                       `json:"aliases" binding:"max=20,dive,notBlank,max=128"`
+	DefaultCategoryId int64                          `json:"defaultCategoryId,string" binding:"min=0"`
+	Address           string                         `json:"address" binding:"max=255"`
+	GeoLocation       *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+}
+
+// PayeeModifyRequest represents all parameters of payee modification request
+type PayeeModifyRequest struct {
+	Id                int64                          `json:"id,string" binding:"required,min=1"`
+	Name              string                         `json:"name" binding:"required,notBlank,max=128"`
+	Aliases           []string                       `json:"aliases" binding:"max=20,dive,notBlank,max=128"`
+	DefaultCategoryId int64                          `json:"defaultCategoryId,string" binding:"min=0"`
+	Address           string                         `json:"address" binding:"max=255"`
+	GeoLocation       *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+}
+
+// PayeeDeleteRequest represents all parameters of payee deleting request
+type PayeeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// PayeeInfoResponse represents a view-object of payee
+type PayeeInfoResponse struct {
+	Id                int64                           `json:"id,string"`
+	Name              string                          `json:"name"`
+	Aliases           []string                        `json:"aliases"`
+	DefaultCategoryId int64                           `json:"defaultCategoryId,string"`
+	Address           string                          `json:"address"`
+	GeoLocation       *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+}
+
+// TransactionPayeeStatisticRequest represents all parameters of transaction statistic grouped by payee request
+type TransactionPayeeStatisticRequest struct {
+	Type                   TransactionType `form:"type" binding:"min=0,max=3"` // 0 means both income and expense
+	StartTime              int64           `form:"start_time" binding:"min=0"`
+	EndTime                int64           `form:"end_time" binding:"min=0"`
+	UseTransactionTimezone bool            `form:"use_transaction_timezone"`
+}
+
+// TransactionPayeeStatisticTrendsRequest represents all parameters of transaction statistic trends grouped by payee request
+type TransactionPayeeStatisticTrendsRequest struct {
+	YearMonthRangeRequest
+	Type                   TransactionType `form:"type" binding:"min=0,max=3"` // 0 means both income and expense
+	UseTransactionTimezone bool            `form:"use_transaction_timezone"`
+}
+
+// PayeeTotalAmount represents the total amount of transactions of a payee in one account
+type PayeeTotalAmount struct {
+	PayeeId   int64
+	AccountId int64
+	Type      TransactionDbType
+	Amount    int64
+	Count     int64
+}
+
+// TransactionPayeeStatisticResponse represents transaction statistic grouped by payee response
+type TransactionPayeeStatisticResponse struct {
+	StartTime int64                                    `json:"startTime"`
+	EndTime   int64                                    `json:"endTime"`
+	Items     []*TransactionPayeeStatisticResponseItem `json:"items"`
+}
+
+// TransactionPayeeStatisticResponseItem represents total amount item of a payee for a response
+type TransactionPayeeStatisticResponseItem struct {
+	PayeeId     int64           `json:"payeeId,string"` // 0 means transactions without payee
+	AccountId   int64           `json:"accountId,string"`
+	Type        TransactionType `json:"type"`
+	TotalAmount int64           `json:"amount"`
+	Count       int64           `json:"count"`
+}
+
+// TransactionPayeeStatisticTrendsResponseItem represents the payee statistic data within each statistic interval
+type TransactionPayeeStatisticTrendsResponseItem struct {
+	Year  int32                                    `json:"year"`
+	Month int32                                    `json:"month"`
+	Items []*TransactionPayeeStatisticResponseItem `json:"items"`
+}
+
+// NormalizePayeeName returns the name used for matching payee names and aliases, which ignores case and redundant spaces
+func NormalizePayeeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// GetAliases returns all the aliases of the payee
+func (p *Payee) GetAliases() []string {
+	if p.Aliases == "" {
+		return []string{}
+	}
+
+	return strings.Split(p.Aliases, payeeAliasesSeparator)
+}
+
+// SetAliases sets the aliases of the payee, empty aliases are ignored and returns error if aliases are duplicated with each other or the payee name
+func (p *Payee) SetAliases(aliases []string) error {
+	if len(aliases) > MaximumAliasesCountOfPayee {
+		return errs.ErrPayeeAliasInvalid
+	}
+
+	existedNames := make(map[string]bool, len(aliases)+1)
+	existedNames[NormalizePayeeName(p.Name)] = true
+	finalAliases := make([]string, 0, len(aliases))
+
+	for i := 0; i < len(aliases); i++ {
+		alias := strings.TrimSpace(aliases[i])
+
+		if alias == "" {
+			continue
+		}
+
+		if strings.Contains(alias, payeeAliasesSeparator) {
+			return errs.ErrPayeeAliasInvalid
+		}
+
+		normalizedAlias := NormalizePayeeName(alias)
+
+		if existedNames[normalizedAlias] {
+			return errs.ErrPayeeAliasInvalid
+		}
+
+		existedNames[normalizedAlias] = true
+		finalAliases = append(finalAliases, alias)
+	}
+
+	p.Aliases = strings.Join(finalAliases, payeeAliasesSeparator)
+
+	return nil
+}
+
+// GetNormalizedNames returns the normalized name and aliases of the payee
+func (p *Payee) GetNormalizedNames() []string {
+	aliases := p.GetAliases()
+	names := make([]string, 0, len(aliases)+1)
+	names = append(names, NormalizePayeeName(p.Name))
+
+	for i := 0; i < len(aliases); i++ {
+		names = append(names, NormalizePayeeName(aliases[i]))
+	}
+
+	return names
+}
+
+// ToPayeeInfoResponse returns a view-object according to database model
+func (p *Payee) ToPayeeInfoResponse() *PayeeInfoResponse {
+	var geoLocation *TransactionGeoLocationResponse
+
+	if p.GeoLongitude != 0 || p.GeoLatitude != 0 {
+		geoLocation = &TransactionGeoLocationResponse{
+			Longitude: p.GeoLongitude,
+			Latitude:  p.GeoLatitude,
+		}
+	}
+
+	return &PayeeInfoResponse{
+		Id:                p.PayeeId,
+		Name:              p.Name,
+		Aliases:           p.GetAliases(),
+		DefaultCategoryId: p.DefaultCategoryId,
+		Address:           p.Address,
+		GeoLocation:       geoLocation,
+	}
+}
+
+// ToTransactionPayeeStatisticResponseItem returns a view-object according to payee total amount
+func (a *PayeeTotalAmount) ToTransactionPayeeStatisticResponseItem() *TransactionPayeeStatisticResponseItem {
+	transactionType, _ := a.Type.ToTransactionType()
+
+	return &TransactionPayeeStatisticResponseItem{
+		PayeeId:     a.PayeeId,
+		AccountId:   a.AccountId,
+		Type:        transactionType,
+		TotalAmount: a.Amount,
+		Count:       a.Count,
+	}
+}
+
+// PayeeInfoResponseSlice represents the slice data structure of PayeeInfoResponse
+type PayeeInfoResponseSlice []*PayeeInfoResponse
+
+// Len returns the count of items
+func (s PayeeInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s PayeeInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s PayeeInfoResponseSlice) Less(i, j int) bool {
+	if NormalizePayeeName(s[i].Name) != NormalizePayeeName(s[j].Name) {
+		return NormalizePayeeName(s[i].Name) < NormalizePayeeName(s[j].Name)
+	}
+
+	return s[i].Id < s[j].Id
+}
+
+// TransactionPayeeStatisticTrendsResponseItemSlice represents the slice data structure of TransactionPayeeStatisticTrendsResponseItem
+type TransactionPayeeStatisticTrendsResponseItemSlice []*TransactionPayeeStatisticTrendsResponseItem
+
+// Len returns the count of items
+func (s TransactionPayeeStatisticTrendsResponseItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionPayeeStatisticTrendsResponseItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionPayeeStatisticTrendsResponseItemSlice) Less(i, j int) bool {
+	if s[i].Year != s[j].Year {
+		return s[i].Year < s[j].Year
+	}
+
+	return s[i].Month < s[j].Month
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestNormalizePayeeName(t *testing.T) {
+	assert.Equal(t, "coffee house", NormalizePayeeName("Coffee House"))
+	assert.Equal(t, "coffee house", NormalizePayeeName("  COFFEE   house "))
+	assert.Equal(t, "", NormalizePayeeName("   "))
+}
+
+func TestPayeeSetAliases(t *testing.T) {
+	payee := &Payee{Name: "Coffee House"}
+
+	err := payee.SetAliases([]string{" Coffee HSE ", "", "CH Store"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Coffee HSE\nCH Store", payee.Aliases)
+	assert.Equal(t, []string{"Coffee HSE", "CH Store"}, payee.GetAliases())
+	assert.Equal(t, []string{"coffee house", "coffee hse", "ch store"}, payee.GetNormalizedNames())
+
+	err = payee.SetAliases(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", payee.Aliases)
+	assert.Equal(t, []string{}, payee.GetAliases())
+}
+
+func TestPayeeSetAliases_InvalidAliases(t *testing.T) {
+	payee := &Payee{Name: "Coffee House"}
+
+	err := payee.SetAliases([]string{"coffee  HOUSE"})
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+
+	err = payee.SetAliases([]string{"CH", "ch"})
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+
+	err = payee.SetAliases([]string{"Coffee\nHSE"})
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+
+	aliases := make([]string, MaximumAliasesCountOfPayee+1)
+
+	for i := 0; i < len(aliases); i++ {
+		aliases[i] = string(rune('a' + i))
+	}
+
+	err = payee.SetAliases(aliases)
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+}
+
+func TestPayeeTotalAmountToTransactionPayeeStatisticResponseItem(t *testing.T) {
+	totalAmount := &PayeeTotalAmount{
+		PayeeId:   5001,
+		AccountId: 1001,
+		Type:      TRANSACTION_DB_TYPE_EXPENSE,
+		Amount:    1200,
+		Count:     3,
+	}
+
+	item := totalAmount.ToTransactionPayeeStatisticResponseItem()
+	assert.Equal(t, int64(5001), item.PayeeId)
+	assert.Equal(t, int64(1001), item.AccountId)
+	assert.Equal(t, TRANSACTION_TYPE_EXPENSE, item.Type)
+	assert.Equal(t, int64(1200), item.TotalAmount)
+	assert.Equal(t, int64(3), item.Count)
+}
+
+func TestPayeeInfoResponseSliceLess(t *testing.T) {
+	var payeeRespSlice PayeeInfoResponseSlice
+	payeeRespSlice = append(payeeRespSlice, &PayeeInfoResponse{
+		Id:   1,
+		Name: "Supermarket",
+	})
+	payeeRespSlice = append(payeeRespSlice, &PayeeInfoResponse{
+		Id:   2,
+		Name: "bakery",
+	})
+	payeeRespSlice = append(payeeRespSlice, &PayeeInfoResponse{
+		Id:   3,
+		Name: "Coffee House",
+	})
+
+	sort.Sort(payeeRespSlice)
+
+	assert.Equal(t, int64(2), payeeRespSlice[0].Id)
+	assert.Equal(t, int64(3), payeeRespSlice[1].Id)
+	assert.Equal(t, int64(1), payeeRespSlice[2].Id)
+}
+
+func TestTransactionPayeeStatisticTrendsResponseItemSliceLess(t *testing.T) {
+	var trendsSlice TransactionPayeeStatisticTrendsResponseItemSlice
+	trendsSlice = append(trendsSlice, &TransactionPayeeStatisticTrendsResponseItem{
+		Year:  2024,
+		Month: 3,
+	})
+	trendsSlice = append(trendsSlice, &TransactionPayeeStatisticTrendsResponseItem{
+		Year:  2023,
+		Month: 12,
+	})
+	trendsSlice = append(trendsSlice, &TransactionPayeeStatisticTrendsResponseItem{
+		Year:  2024,
+		Month: 1,
+	})
+
+	sort.Sort(trendsSlice)
+
+	assert.Equal(t, int32(2023), trendsSlice[0].Year)
+	assert.Equal(t, int32(1), trendsSlice[1].Month)
+	assert.Equal(t, int32(3), trendsSlice[2].Month)
+}
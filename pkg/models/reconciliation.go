@@ -0,0 +1,125 @@
+package models
+
+// MaximumTransactionsCountOfReconciliationMark is the maximum count of transactions which can be marked in one request
+const MaximumTransactionsCountOfReconciliationMark = 1000
+
+// ReconciliationStatus represents the status of a reconciliation
+type ReconciliationStatus byte
+
+// Reconciliation statuses
+const (
+	RECONCILIATION_STATUS_IN_PROGRESS ReconciliationStatus = 1
+	RECONCILIATION_STATUS_COMPLETED   ReconciliationStatus = 2
+)
+
+// Reconciliation represents a session of matching the transactions of an account with its bank statement stored in database,
+// each account can have at most one reconciliation in progress
+type Reconciliation struct {
+	ReconciliationId  int64                `xorm:"PK"`
+	Uid               int64                `xorm:"INDEX(IDX_reconciliation_uid_deleted_fund_id_account_id) NOT NULL"`
+	Deleted           bool                 `xorm:"INDEX(IDX_reconciliation_uid_deleted_fund_id_account_id) NOT NULL"`
+	FundId            int64                `xorm:"INDEX(IDX_reconciliation_uid_deleted_fund_id_account_id) NOT NULL"`
+	AccountId         int64                `xorm:"INDEX(IDX_reconciliation_uid_deleted_fund_id_account_id) NOT NULL"`
+	Status            ReconciliationStatus `xorm:"NOT NULL"`
+	StatementEndTime  int64                `xorm:"NOT NULL"` // Unix time, transactions not later than this time are included
+	StatementBalance  int64                `xorm:"NOT NULL"`
+	ClearedBalance    int64                `xorm:"NOT NULL"` // Only set after the reconciliation is completed
+	ReconciledCount   int32                `xorm:"NOT NULL"` // Only set after the reconciliation is completed
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	CompletedUnixTime int64
+	DeletedUnixTime   int64
+}
+
+// ReconciliationListRequest represents all parameters of reconciliation listing request
+type ReconciliationListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"min=0"`
+}
+
+// ReconciliationGetRequest represents all parameters of reconciliation getting request
+type ReconciliationGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// ReconciliationCreateRequest represents all parameters of reconciliation creation request
+type ReconciliationCreateRequest struct {
+	AccountId        int64 `json:"accountId,string" binding:"required,min=1"`
+	StatementEndTime int64 `json:"statementEndTime" binding:"required,min=1"`
+	StatementBalance int64 `json:"statementBalance" binding:"min=-99999999999,max=99999999999"`
+}
+
+// ReconciliationMarkRequest represents all parameters of marking transactions as cleared or uncleared in a reconciliation
+type ReconciliationMarkRequest struct {
+	Id             int64    `json:"id,string" binding:"required,min=1"`
+	TransactionIds []string `json:"transactionIds" binding:"required,min=1"`
+	Cleared        bool     `json:"cleared"`
+}
+
+// ReconciliationCompleteRequest represents all parameters of reconciliation completing request
+type ReconciliationCompleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// ReconciliationDeleteRequest represents all parameters of reconciliation deleting request
+type ReconciliationDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// ReconciliationInfoResponse represents a view-object of reconciliation
+type ReconciliationInfoResponse struct {
+	Id               int64                `json:"id,string"`
+	AccountId        int64                `json:"accountId,string"`
+	Status           ReconciliationStatus `json:"status"`
+	StatementEndTime int64                `json:"statementEndTime"`
+	StatementBalance int64                `json:"statementBalance"`
+	ClearedBalance   int64                `json:"clearedBalance"`
+	ReconciledCount  int32                `json:"reconciledCount"`
+	CreatedAt        int64                `json:"createdAt"`
+	CompletedAt      int64                `json:"completedAt,omitempty"`
+}
+
+// ReconciliationDetailResponse represents a view-object of reconciliation with the transactions which can be ticked
+type ReconciliationDetailResponse struct {
+	*ReconciliationInfoResponse
+	Difference   int64                        `json:"difference"`
+	Transactions TransactionInfoResponseSlice `json:"transactions"`
+}
+
+// IsCompleted returns whether the reconciliation has been completed
+func (r *Reconciliation) IsCompleted() bool {
+	return r.Status == RECONCILIATION_STATUS_COMPLETED
+}
+
+// ToReconciliationInfoResponse returns a view-object according to database model
+func (r *Reconciliation) ToReconciliationInfoResponse() *ReconciliationInfoResponse {
+	return &ReconciliationInfoResponse{
+		Id:               r.ReconciliationId,
+		AccountId:        r.AccountId,
+		Status:           r.Status,
+		StatementEndTime: r.StatementEndTime,
+		StatementBalance: r.StatementBalance,
+		ClearedBalance:   r.ClearedBalance,
+		ReconciledCount:  r.ReconciledCount,
+		CreatedAt:        r.CreatedUnixTime,
+		CompletedAt:      r.CompletedUnixTime,
+	}
+}
+
+// IsTransactionCountedInClearedBalance returns whether the transaction is counted in the cleared balance of its account,
+// balance modification transactions are always counted because they are set by user instead of coming from bank
+func IsTransactionCountedInClearedBalance(transaction *Transaction) bool {
+	return transaction.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE || transaction.ClearedStatus != TRANSACTION_CLEARED_STATUS_UNCLEARED
+}
+
+// CalculateClearedBalance returns the sum of balance changes of all the transactions counted in the cleared balance
+func CalculateClearedBalance(transactions []*Transaction) int64 {
+	clearedBalance := int64(0)
+
+	for _, transaction := range transactions {
+		if IsTransactionCountedInClearedBalance(transaction) {
+			clearedBalance += transaction.GetAccountBalanceChangedAmount()
+		}
+	}
+
+	return clearedBalance
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionGetAccountBalanceChangedAmount(t *testing.T) {
+	assert.Equal(t, int64(-500), (&Transaction{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, Amount: 1000, RelatedAccountAmount: -500}).GetAccountBalanceChangedAmount())
+	assert.Equal(t, int64(1000), (&Transaction{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 1000}).GetAccountBalanceChangedAmount())
+	assert.Equal(t, int64(-1000), (&Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 1000}).GetAccountBalanceChangedAmount())
+	assert.Equal(t, int64(-1000), (&Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Amount: 1000, RelatedAccountAmount: 900}).GetAccountBalanceChangedAmount())
+	assert.Equal(t, int64(900), (&Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, Amount: 900, RelatedAccountAmount: 1000}).GetAccountBalanceChangedAmount())
+}
+
+func TestCalculateClearedBalance(t *testing.T) {
+	transactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, RelatedAccountAmount: 10000, ClearedStatus: TRANSACTION_CLEARED_STATUS_UNCLEARED},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 1200, ClearedStatus: TRANSACTION_CLEARED_STATUS_RECONCILED},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 300, ClearedStatus: TRANSACTION_CLEARED_STATUS_CLEARED},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 700, ClearedStatus: TRANSACTION_CLEARED_STATUS_UNCLEARED},
+		{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 2000, ClearedStatus: TRANSACTION_CLEARED_STATUS_CLEARED},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Amount: 500, ClearedStatus: TRANSACTION_CLEARED_STATUS_CLEARED},
+	}
+
+	assert.Equal(t, int64(10000-1200-300+2000-500), CalculateClearedBalance(transactions))
+	assert.Equal(t, int64(0), CalculateClearedBalance(nil))
+}
+
+func TestReconciliationToReconciliationInfoResponse(t *testing.T) {
+	reconciliation := &Reconciliation{
+		ReconciliationId:  1001,
+		AccountId:         2001,
+		Status:            RECONCILIATION_STATUS_COMPLETED,
+		StatementEndTime:  1700000000,
+		StatementBalance:  12345,
+		ClearedBalance:    12345,
+		ReconciledCount:   3,
+		CreatedUnixTime:   1700000100,
+		CompletedUnixTime: 1700000200,
+	}
+
+	assert.True(t, reconciliation.IsCompleted())
+
+	resp := reconciliation.ToReconciliationInfoResponse()
+	assert.Equal(t, int64(1001), resp.Id)
+	assert.Equal(t, int64(2001), resp.AccountId)
+	assert.Equal(t, RECONCILIATION_STATUS_COMPLETED, resp.Status)
+	assert.Equal(t, int64(1700000000), resp.StatementEndTime)
+	assert.Equal(t, int64(12345), resp.StatementBalance)
+	assert.Equal(t, int64(12345), resp.ClearedBalance)
+	assert.Equal(t, int32(3), resp.ReconciledCount)
+	assert.Equal(t, int64(1700000100), resp.CreatedAt)
+	assert.Equal(t, int64(1700000200), resp.CompletedAt)
+}
+
+func TestTransactionClearedStatusString(t *testing.T) {
+	assert.Equal(t, "Uncleared", TRANSACTION_CLEARED_STATUS_UNCLEARED.String())
+	assert.Equal(t, "Cleared", TRANSACTION_CLEARED_STATUS_CLEARED.String())
+	assert.Equal(t, "Reconciled", TRANSACTION_CLEARED_STATUS_RECONCILED.String())
+	assert.Equal(t, "Invalid(3)", TransactionClearedStatus(3).String())
+}
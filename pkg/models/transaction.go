@@ -115,26 +115,54 @@ const (
 	TRANSACTION_TAG_FILTER_NOT_HAS_ALL TransactionTagFilterType = 3
 )
 
+// TransactionClearedStatus represents whether a transaction has been matched with bank statement
+type TransactionClearedStatus byte
+
+// Transaction cleared statuses
+const (
+	TRANSACTION_CLEARED_STATUS_UNCLEARED  TransactionClearedStatus = 0
+	TRANSACTION_CLEARED_STATUS_CLEARED    TransactionClearedStatus = 1
+	TRANSACTION_CLEARED_STATUS_RECONCILED TransactionClearedStatus = 2
+)
+
+// String returns a textual representation of the transaction cleared status enum
+func (s TransactionClearedStatus) String() string {
+	switch s {
+	case TRANSACTION_CLEARED_STATUS_UNCLEARED:
+		return "Uncleared"
+	case TRANSACTION_CLEARED_STATUS_CLEARED:
+		return "Cleared"
+	case TRANSACTION_CLEARED_STATUS_RECONCILED:
+		return "Reconciled"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(s))
+	}
+}
+
 // Transaction represents transaction data stored in database
 type Transaction struct {
-	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) NOT NULL"`
-	FundId               int64             `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) NOT NULL"`
-	CategoryId           int64             `xorm:"INDEX(IDX_transaction_fund_uid_deleted_category_id_time) NOT NULL"`
-	AccountId            int64             `xorm:"INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) NOT NULL"`
-	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) NOT NULL"`
-	TimezoneUtcOffset    int16             `xorm:"NOT NULL"`
-	Amount               int64             `xorm:"NOT NULL"`
-	RelatedId            int64             `xorm:"NOT NULL"`
-	RelatedAccountId     int64             `xorm:"NOT NULL"`
-	RelatedAccountAmount int64             `xorm:"NOT NULL"`
-	HideAmount           bool              `xorm:"NOT NULL"`
-	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
-	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	TransactionId        int64                    `xorm:"PK"`
+	Uid                  int64                    `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_payee_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_account_id_external_id) NOT NULL"`
+	FundId               int64                    `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_payee_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Deleted              bool                     `xorm:"INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_payee_id_time) INDEX(IDX_transaction_fund_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_account_id_external_id) NOT NULL"`
+	Type                 TransactionDbType        `xorm:"INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) NOT NULL"`
+	CategoryId           int64                    `xorm:"INDEX(IDX_transaction_fund_uid_deleted_category_id_time) NOT NULL"`
+	AccountId            int64                    `xorm:"INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_account_id_external_id) NOT NULL"`
+	PayeeId              int64                    `xorm:"INDEX(IDX_transaction_fund_uid_deleted_payee_id_time) NOT NULL DEFAULT 0"`
+	TransactionTime      int64                    `xorm:"UNIQUE(UQE_transaction_fund_uid_time) INDEX(IDX_transaction_fund_uid_deleted_time) INDEX(IDX_transaction_fund_uid_deleted_type_time) INDEX(IDX_transaction_fund_uid_deleted_type_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_category_id_time) INDEX(IDX_transaction_fund_uid_deleted_account_id_time) INDEX(IDX_transaction_fund_uid_deleted_payee_id_time) NOT NULL"`
+	TimezoneUtcOffset    int16                    `xorm:"NOT NULL"`
+	Amount               int64                    `xorm:"NOT NULL"`
+	RelatedId            int64                    `xorm:"NOT NULL"`
+	RelatedAccountId     int64                    `xorm:"NOT NULL"`
+	RelatedAccountAmount int64                    `xorm:"NOT NULL"`
+	HideAmount           bool                     `xorm:"NOT NULL"`
+	Comment              string                   `xorm:"VARCHAR(255) NOT NULL"`
+	ClearedStatus        TransactionClearedStatus `xorm:"NOT NULL DEFAULT 0"`                                                                         // The status of this row only, both rows of a transfer are reconciled separately with their own account
+	ExternalId           string                   `xorm:"VARCHAR(255) INDEX(IDX_transaction_uid_deleted_account_id_external_id) NOT NULL DEFAULT ''"` // Unique identifier assigned by the bank, e.g. OFX FITID
+	SettledTime          int64                    `xorm:"NOT NULL DEFAULT 0"`                                                                         // The settled time of the fund settlement which includes this shared expense, zero if it has not been settled yet
+	GeoLongitude         float64                  `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	GeoLatitude          float64                  `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	CreatedIp            string                   `xorm:"VARCHAR(39)"`
 	ScheduledCreated     bool
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
@@ -156,44 +184,57 @@ type TransactionGeoLocationRequest struct {
 
 // TransactionCreateRequest represents all parameters of transaction creation request
 type TransactionCreateRequest struct {
-	Type                 TransactionType                `json:"type" binding:"required"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	MemberIds            []int64                        `json:"memberIds"` // Empty = all members
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
-	ClientSessionId      string                         `json:"clientSessionId"`
+	Type                 TransactionType                  `json:"type" binding:"required"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                             `json:"hideAmount"`
+	PayeeId              int64                            `json:"payeeId,string" binding:"min=0"`
+	PayeeName            string                           `json:"payeeName" binding:"max=128"` // Used to match or create payee when payee id is not set
+	ExternalId           string                           `json:"externalId" binding:"max=255"`
+	ForceImport          bool                             `json:"forceImport"` // Used to import the transaction even if it is a duplicate when importing transactions
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	MemberIds            []int64                          `json:"memberIds"` // Empty = all members
+	MemberSplitType      TransactionMemberSplitType       `json:"memberSplitType" binding:"omitempty,min=1,max=4"`
+	Members              []*TransactionMemberSplitRequest `json:"members" binding:"omitempty,dive"` // Takes precedence over MemberIds
+	Splits               []*TransactionSplitRequest       `json:"splits" binding:"omitempty,dive"`  // Line items of split transaction
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
+	ClientSessionId      string                           `json:"clientSessionId"`
 }
 
 // TransactionModifyRequest represents all parameters of transaction modification request
 type TransactionModifyRequest struct {
-	Id                   int64                          `json:"id,string" binding:"required,min=1"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	MemberIds            []int64                        `json:"memberIds"` // Empty = all members
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Id                   int64                            `json:"id,string" binding:"required,min=1"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                             `json:"hideAmount"`
+	PayeeId              int64                            `json:"payeeId,string" binding:"min=0"`
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	MemberIds            []int64                          `json:"memberIds"` // null = no change, empty = no members
+	MemberSplitType      TransactionMemberSplitType       `json:"memberSplitType" binding:"omitempty,min=1,max=4"`
+	Members              []*TransactionMemberSplitRequest `json:"members" binding:"omitempty,dive"` // Takes precedence over MemberIds, null = no change, empty = no members
+	Splits               []*TransactionSplitRequest       `json:"splits" binding:"omitempty,dive"`  // Line items of split transaction, null = no change, empty = not split
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
+	UnlockReconciled     bool                             `json:"unlockReconciled"` // Must be set to modify a reconciled transaction
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
 type TransactionImportRequest struct {
 	Transactions    []*TransactionCreateRequest `json:"transactions"`
+	SkipDuplicates  bool                        `json:"skipDuplicates"` // Skip the transactions which duplicate existing transactions unless force import is set
 	ClientSessionId string                      `json:"clientSessionId"`
 }
 
@@ -211,6 +252,7 @@ type TransactionCountRequest struct {
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	MaxTime       int64                    `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime       int64                    `form:"min_time" binding:"min=0"` // Transaction time sequence id
 }
@@ -225,6 +267,7 @@ type TransactionListByMaxTimeRequest struct {
 	MemberId      int64                    `form:"member_id,string"` // Filter by specific member
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	MaxTime       int64                    `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime       int64                    `form:"min_time" binding:"min=0"` // Transaction time sequence id
 	Page          int32                    `form:"page" binding:"min=0"`
@@ -247,6 +290,7 @@ type TransactionListInMonthByPageRequest struct {
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	WithPictures  bool                     `form:"with_pictures"`
 	TrimAccount   bool                     `form:"trim_account"`
 	TrimCategory  bool                     `form:"trim_category"`
@@ -262,21 +306,27 @@ type TransactionReconciliationStatementRequest struct {
 
 // TransactionStatisticRequest represents all parameters of transaction statistic request
 type TransactionStatisticRequest struct {
-	StartTime              int64                    `form:"start_time" binding:"min=0"`
-	EndTime                int64                    `form:"end_time" binding:"min=0"`
-	TagIds                 string                   `form:"tag_ids"`
-	TagFilterType          TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
-	Keyword                string                   `form:"keyword"`
-	UseTransactionTimezone bool                     `form:"use_transaction_timezone"`
+	StartTime                  int64                    `form:"start_time" binding:"min=0"`
+	EndTime                    int64                    `form:"end_time" binding:"min=0"`
+	TagIds                     string                   `form:"tag_ids"`
+	TagFilterType              TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	Keyword                    string                   `form:"keyword"`
+	Query                      string                   `form:"query" binding:"max=1000"`
+	UseTransactionTimezone     bool                     `form:"use_transaction_timezone"`
+	NetRefunds                 bool                     `form:"net_refunds"`
+	UseHistoricalExchangeRates bool                     `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
 type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
-	TagIds                 string                   `form:"tag_ids"`
-	TagFilterType          TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
-	Keyword                string                   `form:"keyword"`
-	UseTransactionTimezone bool                     `form:"use_transaction_timezone"`
+	TagIds                     string                   `form:"tag_ids"`
+	TagFilterType              TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	Keyword                    string                   `form:"keyword"`
+	Query                      string                   `form:"query" binding:"max=1000"`
+	UseTransactionTimezone     bool                     `form:"use_transaction_timezone"`
+	NetRefunds                 bool                     `form:"net_refunds"`
+	UseHistoricalExchangeRates bool                     `form:"use_historical_exchange_rates"`
 }
 
 // TransactionAmountsRequest represents all parameters of transaction amounts request
@@ -309,9 +359,17 @@ type TransactionMoveBetweenAccountsRequest struct {
 	ToAccountId   int64 `json:"toAccountId,string" binding:"required,min=1"`
 }
 
+// TransactionClearedStatusModifyRequest represents all parameters of transaction cleared status modification request
+type TransactionClearedStatusModifyRequest struct {
+	Id               int64                    `json:"id,string" binding:"required,min=1"`
+	ClearedStatus    TransactionClearedStatus `json:"clearedStatus" binding:"min=0,max=1"` // Transactions can only be reconciled by completing a reconciliation
+	UnlockReconciled bool                     `json:"unlockReconciled"`
+}
+
 // TransactionDeleteRequest represents all parameters of transaction deleting request
 type TransactionDeleteRequest struct {
-	Id int64 `json:"id,string" binding:"required,min=1"`
+	Id               int64 `json:"id,string" binding:"required,min=1"`
+	UnlockReconciled bool  `json:"unlockReconciled"`
 }
 
 // YearMonthRangeRequest represents all parameters of a request with year and month range
@@ -342,11 +400,17 @@ type TransactionInfoResponse struct {
 	SourceAmount         int64                                    `json:"sourceAmount"`
 	DestinationAmount    int64                                    `json:"destinationAmount,omitempty"`
 	HideAmount           bool                                     `json:"hideAmount"`
+	PayeeId              int64                                    `json:"payeeId,string,omitempty"`
+	Payee                *PayeeInfoResponse                       `json:"payee,omitempty"`
 	TagIds               []string                                 `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
 	Comment              string                                   `json:"comment"`
+	ClearedStatus        TransactionClearedStatus                 `json:"clearedStatus"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	MemberSplitType      TransactionMemberSplitType               `json:"memberSplitType,omitempty"`
+	Members              []*TransactionMemberInfoResponse         `json:"members,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
@@ -398,6 +462,7 @@ type TransactionStatisticResponseItem struct {
 	RelatedAccountId   int64                         `json:"relatedAccountId,string,omitempty"`
 	RelatedAccountType TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
 	TotalAmount        int64                         `json:"amount"`
+	Currency           string                        `json:"currency,omitempty"`
 }
 
 // TransactionStatisticTrendsResponseItem represents the data within each statistic interval
@@ -447,6 +512,20 @@ func (t *Transaction) IsEditable(currentUser *User, utcOffset int16, account *Ac
 	return true
 }
 
+// GetAccountBalanceChangedAmount returns the amount by which this transaction changes the balance of its account
+func (t *Transaction) GetAccountBalanceChangedAmount() int64 {
+	switch t.Type {
+	case TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return t.RelatedAccountAmount
+	case TRANSACTION_DB_TYPE_INCOME, TRANSACTION_DB_TYPE_TRANSFER_IN:
+		return t.Amount
+	case TRANSACTION_DB_TYPE_EXPENSE, TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		return -t.Amount
+	default:
+		return 0
+	}
+}
+
 // ToTransactionInfoResponse returns a view-object according to database model
 func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *TransactionInfoResponse {
 	transactionType, err := t.Type.ToTransactionType()
@@ -493,8 +572,10 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		SourceAmount:         sourceAmount,
 		DestinationAmount:    destinationAmount,
 		HideAmount:           t.HideAmount,
+		PayeeId:              t.PayeeId,
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
+		ClearedStatus:        t.ClearedStatus,
 		GeoLocation:          geoLocation,
 		Editable:             editable,
 	}
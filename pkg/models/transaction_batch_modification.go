@@ -0,0 +1,138 @@
+package models
+
+import (
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumTransactionsCountOfBatchModification represents the maximum count of transactions in one batch modification
+const MaximumTransactionsCountOfBatchModification = 1000
+
+// MaximumCommentLengthOfTransaction represents the maximum length of transaction comment
+const MaximumCommentLengthOfTransaction = 255
+
+// TransactionBatchModifyRequest represents all parameters of transaction batch modification request,
+// the transactions are specified by ids, or by the same filter conditions as transaction listing when ids are empty
+type TransactionBatchModifyRequest struct {
+	Ids           []string                 `json:"ids"`
+	Type          TransactionType          `json:"type" binding:"min=0,max=4"`
+	CategoryIds   string                   `json:"categoryIds"`
+	AccountIds    string                   `json:"accountIds"`
+	TagIds        string                   `json:"tagIds"`
+	TagFilterType TransactionTagFilterType `json:"tagFilterType" binding:"min=0,max=3"`
+	AmountFilter  string                   `json:"amountFilter" binding:"validAmountFilter"`
+	Keyword       string                   `json:"keyword"`
+	MaxTime       int64                    `json:"maxTime" binding:"min=0"` // Transaction time sequence id
+	MinTime       int64                    `json:"minTime" binding:"min=0"` // Transaction time sequence id
+	SetCategoryId int64                    `json:"setCategoryId,string" binding:"min=0"`
+	SetAccountId  int64                    `json:"setAccountId,string" binding:"min=0"`
+	AddTagIds     []string                 `json:"addTagIds"`
+	RemoveTagIds  []string                 `json:"removeTagIds"`
+	SetMemberIds  []string                 `json:"setMemberIds"`
+	SetComment    string                   `json:"setComment" binding:"max=255"`
+	AppendComment string                   `json:"appendComment" binding:"max=255"`
+}
+
+// TransactionBatchModifyResponse represents the result of transaction batch modification
+type TransactionBatchModifyResponse struct {
+	ModifiedCount int `json:"modifiedCount"`
+}
+
+// TransactionBatchModifyErrorResponse represents the error of one transaction in transaction batch modification
+type TransactionBatchModifyErrorResponse struct {
+	Id           int64  `json:"id,string"`
+	ErrorCode    int32  `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// TransactionBatchModification represents the changes which are applied to every transaction in batch modification
+type TransactionBatchModification struct {
+	CategoryId    int64
+	AccountId     int64
+	AddTagIds     []int64
+	RemoveTagIds  []int64
+	MemberIds     []int64 // The linked members are replaced by these members with equal split when not empty
+	Comment       string  // The comment is replaced by this comment when not empty
+	AppendComment string
+}
+
+// IsEmpty returns whether the batch modification changes nothing
+func (m *TransactionBatchModification) IsEmpty() bool {
+	return m.CategoryId == 0 && m.AccountId == 0 && len(m.AddTagIds) == 0 && len(m.RemoveTagIds) == 0 && len(m.MemberIds) == 0 && m.Comment == "" && m.AppendComment == ""
+}
+
+// Validate checks whether the changes of batch modification are consistent
+func (m *TransactionBatchModification) Validate() error {
+	if m.IsEmpty() {
+		return errs.ErrNothingWillBeUpdated
+	}
+
+	if len(m.AddTagIds) > MaximumTagsCountOfTransaction {
+		return errs.ErrTransactionHasTooManyTags
+	}
+
+	if len(utils.Int64SliceMinus(m.AddTagIds, m.RemoveTagIds)) != len(m.AddTagIds) {
+		return errs.ErrTransactionTagCannotBeAddedAndRemoved
+	}
+
+	return nil
+}
+
+// GetNewComment returns the comment after replacing the specified comment and then appending the comment of batch modification
+func (m *TransactionBatchModification) GetNewComment(comment string) (string, error) {
+	if m.Comment != "" {
+		comment = m.Comment
+	}
+
+	if m.AppendComment == "" {
+		return comment, nil
+	}
+
+	newComment := m.AppendComment
+
+	if comment != "" {
+		newComment = comment + " " + m.AppendComment
+	}
+
+	if utf8.RuneCountInString(newComment) > MaximumCommentLengthOfTransaction {
+		return "", errs.ErrTransactionCommentTooLong
+	}
+
+	return newComment, nil
+}
+
+// IsMembersChanged returns whether the linked members are changed by batch modification according to the specified current members
+func (m *TransactionBatchModification) IsMembersChanged(currentMembers []*TransactionMember) bool {
+	if len(m.MemberIds) < 1 {
+		return false
+	}
+
+	memberIds := utils.ToUniqueInt64Slice(m.MemberIds)
+
+	if len(memberIds) != len(currentMembers) {
+		return true
+	}
+
+	for _, member := range currentMembers {
+		if member.SplitType != TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL || len(utils.Int64SliceMinus([]int64{member.MemberId}, memberIds)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetNewTagIds returns the tag ids which should be added and removed according to the specified current tag ids
+func (m *TransactionBatchModification) GetNewTagIds(currentTagIds []int64) (addTagIds []int64, removeTagIds []int64, err error) {
+	keptTagIds := utils.Int64SliceMinus(currentTagIds, m.RemoveTagIds)
+	removeTagIds = utils.Int64SliceMinus(currentTagIds, keptTagIds)
+	addTagIds = utils.Int64SliceMinus(utils.ToUniqueInt64Slice(m.AddTagIds), currentTagIds)
+
+	if len(keptTagIds)+len(addTagIds) > MaximumTagsCountOfTransaction {
+		return nil, nil, errs.ErrTransactionHasTooManyTags
+	}
+
+	return addTagIds, removeTagIds, nil
+}
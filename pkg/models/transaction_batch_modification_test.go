@@ -0,0 +1,103 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionBatchModificationValidate(t *testing.T) {
+	modification := &TransactionBatchModification{}
+	assert.True(t, modification.IsEmpty())
+	assert.Equal(t, errs.ErrNothingWillBeUpdated, modification.Validate())
+
+	modification = &TransactionBatchModification{CategoryId: 1001}
+	assert.False(t, modification.IsEmpty())
+	assert.Nil(t, modification.Validate())
+
+	modification = &TransactionBatchModification{AddTagIds: []int64{1, 2}, RemoveTagIds: []int64{2, 3}}
+	assert.Equal(t, errs.ErrTransactionTagCannotBeAddedAndRemoved, modification.Validate())
+
+	modification = &TransactionBatchModification{AddTagIds: make([]int64, MaximumTagsCountOfTransaction+1)}
+	assert.Equal(t, errs.ErrTransactionHasTooManyTags, modification.Validate())
+}
+
+func TestTransactionBatchModificationGetNewComment(t *testing.T) {
+	modification := &TransactionBatchModification{}
+	comment, err := modification.GetNewComment("old")
+	assert.Nil(t, err)
+	assert.Equal(t, "old", comment)
+
+	modification = &TransactionBatchModification{AppendComment: "new"}
+	comment, err = modification.GetNewComment("")
+	assert.Nil(t, err)
+	assert.Equal(t, "new", comment)
+
+	comment, err = modification.GetNewComment("old")
+	assert.Nil(t, err)
+	assert.Equal(t, "old new", comment)
+
+	_, err = modification.GetNewComment(strings.Repeat("a", MaximumCommentLengthOfTransaction-3))
+	assert.Equal(t, errs.ErrTransactionCommentTooLong, err)
+}
+
+func TestTransactionBatchModificationGetNewTagIds(t *testing.T) {
+	modification := &TransactionBatchModification{AddTagIds: []int64{3, 4, 4}, RemoveTagIds: []int64{1, 5}}
+
+	addTagIds, removeTagIds, err := modification.GetNewTagIds([]int64{1, 2, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{4}, addTagIds)
+	assert.Equal(t, []int64{1}, removeTagIds)
+
+	addTagIds, removeTagIds, err = modification.GetNewTagIds(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 4}, addTagIds)
+	assert.Equal(t, 0, len(removeTagIds))
+
+	currentTagIds := make([]int64, MaximumTagsCountOfTransaction)
+
+	for i := 0; i < len(currentTagIds); i++ {
+		currentTagIds[i] = int64(100 + i)
+	}
+
+	_, _, err = modification.GetNewTagIds(currentTagIds)
+	assert.Equal(t, errs.ErrTransactionHasTooManyTags, err)
+}
+
+func TestTransactionBatchModificationGetNewComment_ReplaceComment(t *testing.T) {
+	modification := &TransactionBatchModification{Comment: "replaced"}
+	assert.False(t, modification.IsEmpty())
+
+	comment, err := modification.GetNewComment("old")
+	assert.Nil(t, err)
+	assert.Equal(t, "replaced", comment)
+
+	modification = &TransactionBatchModification{Comment: "replaced", AppendComment: "new"}
+	comment, err = modification.GetNewComment("old")
+	assert.Nil(t, err)
+	assert.Equal(t, "replaced new", comment)
+}
+
+func TestTransactionBatchModificationIsMembersChanged(t *testing.T) {
+	modification := &TransactionBatchModification{}
+	assert.False(t, modification.IsMembersChanged([]*TransactionMember{{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL}}))
+
+	modification = &TransactionBatchModification{MemberIds: []int64{1, 2, 2}}
+	assert.False(t, modification.IsEmpty())
+	assert.False(t, modification.IsMembersChanged([]*TransactionMember{
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}))
+	assert.True(t, modification.IsMembersChanged(nil))
+	assert.True(t, modification.IsMembersChanged([]*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 3, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}))
+	assert.True(t, modification.IsMembersChanged([]*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 2},
+	}))
+}
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumTransactionForecastMonths represents the maximum months of cash-flow forecast
+const MaximumTransactionForecastMonths = 24
+
+// DefaultTransactionForecastAverageMonths represents the default count of past months to calculate the average amounts
+const DefaultTransactionForecastAverageMonths = 3
+
+// TransactionForecastAlertType represents the type of cash-flow forecast alert
+type TransactionForecastAlertType byte
+
+// Transaction forecast alert types
+const (
+	TRANSACTION_FORECAST_ALERT_TYPE_NEGATIVE_BALANCE      TransactionForecastAlertType = 1
+	TRANSACTION_FORECAST_ALERT_TYPE_CREDIT_LIMIT_EXCEEDED TransactionForecastAlertType = 2
+)
+
+// TransactionForecastRequest represents all parameters of cash-flow forecast request
+type TransactionForecastRequest struct {
+	Months                int  `form:"months" binding:"required,min=1,max=24"`
+	IncludeAverageAmounts bool `form:"include_average_amounts"`
+	AverageMonths         int  `form:"average_months" binding:"min=0,max=12"`
+}
+
+// TransactionForecastSource represents all data which the cash-flow forecast is built from
+type TransactionForecastSource struct {
+	Accounts              []*Account
+	ScheduledTemplates    []*TransactionTemplate
+	FutureTransactions    []*Transaction
+	AverageMonthlyAmounts map[int64]int64
+	StartUnixTime         int64
+	Months                int
+	UtcOffset             int16
+}
+
+// TransactionForecastResponse represents a view-object of cash-flow forecast
+type TransactionForecastResponse struct {
+	StartDate string                                `json:"startDate"`
+	EndDate   string                                `json:"endDate"`
+	Accounts  []*TransactionForecastAccountResponse `json:"accounts"`
+}
+
+// TransactionForecastAccountResponse represents a view-object of the cash-flow forecast of an account
+type TransactionForecastAccountResponse struct {
+	AccountId       int64                                      `json:"accountId,string"`
+	Currency        string                                     `json:"currency"`
+	CurrentBalance  int64                                      `json:"currentBalance"`
+	CreditCardLimit int64                                      `json:"creditCardLimit,omitempty"`
+	DailyBalances   []*TransactionForecastDailyBalanceResponse `json:"dailyBalances"`
+	Alerts          []*TransactionForecastAlertResponse        `json:"alerts"`
+}
+
+// TransactionForecastDailyBalanceResponse represents a view-object of the forecasted balance of an account at the end of a day
+type TransactionForecastDailyBalanceResponse struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+// TransactionForecastAlertResponse represents a view-object of the date when the forecasted balance of an account becomes abnormal
+type TransactionForecastAlertResponse struct {
+	Type    TransactionForecastAlertType `json:"type"`
+	Date    string                       `json:"date"`
+	Balance int64                        `json:"balance"`
+}
+
+// BuildTransactionForecast projects the balance of every account day-by-day from the start date, by applying the future transactions,
+// the occurrences of the scheduled transaction templates and the average monthly amounts spread evenly over the days of each month.
+// Only the accounts which have no sub-accounts are projected, and the credit limit of a credit card account with sub-accounts is
+// checked against the total balance of all its sub-accounts.
+func BuildTransactionForecast(source *TransactionForecastSource) *TransactionForecastResponse {
+	clientTimezone := time.FixedZone("Client Timezone", int(source.UtcOffset)*60)
+	startTime := time.Unix(source.StartUnixTime, 0).In(clientTimezone)
+	startDate := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, clientTimezone)
+	endDate := startDate.AddDate(0, source.Months, 0)
+	days := int(endDate.Sub(startDate).Hours()/24 + 0.5)
+
+	allAccountIds := make(map[int64]bool, len(source.Accounts))
+	accountIndexes := make(map[int64]int, len(source.Accounts))
+	accounts := make([]*Account, 0, len(source.Accounts))
+	dailyChanges := make([][]int64, 0, len(source.Accounts))
+	initialBalances := make([]int64, 0, len(source.Accounts))
+
+	for i := 0; i < len(source.Accounts); i++ {
+		account := source.Accounts[i]
+		allAccountIds[account.AccountId] = true
+
+		if account.Type != ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			continue
+		}
+
+		accountIndexes[account.AccountId] = len(accounts)
+		accounts = append(accounts, account)
+		dailyChanges = append(dailyChanges, make([]int64, days))
+		initialBalances = append(initialBalances, account.Balance)
+	}
+
+	getDayIndex := func(unixTime int64) int {
+		date := time.Unix(unixTime, 0).In(clientTimezone)
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clientTimezone)
+		return int(date.Sub(startDate).Hours()/24 + 0.5)
+	}
+
+	addChange := func(accountId int64, dayIndex int, amount int64) {
+		index, exists := accountIndexes[accountId]
+
+		if !exists || dayIndex < 0 || dayIndex >= days {
+			return
+		}
+
+		dailyChanges[index][dayIndex] += amount
+	}
+
+	// the balance of account has already included the future transactions, so move them back to the days when they happen
+	for i := 0; i < len(source.FutureTransactions); i++ {
+		transaction := source.FutureTransactions[i]
+		index, exists := accountIndexes[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		amount := transaction.GetAccountBalanceChangedAmount()
+		dayIndex := getDayIndex(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		if dayIndex < 1 {
+			continue
+		}
+
+		initialBalances[index] -= amount
+		addChange(transaction.AccountId, dayIndex, amount)
+	}
+
+	// the transactions of today may have been created, so all the estimated amounts are applied from tomorrow
+	for i := 0; i < len(source.ScheduledTemplates); i++ {
+		template := source.ScheduledTemplates[i]
+
+		// the templates referring to the accounts which are not in the specified accounts would only change one side of the balance
+		if !allAccountIds[template.AccountId] || (template.Type == TRANSACTION_TYPE_TRANSFER && !allAccountIds[template.RelatedAccountId]) {
+			continue
+		}
+
+		occurrenceTimes, err := template.GetNextScheduledOccurrenceTimes(startDate.AddDate(0, 0, 1).Unix(), days)
+
+		if err != nil {
+			continue
+		}
+
+		for j := 0; j < len(occurrenceTimes); j++ {
+			dayIndex := getDayIndex(occurrenceTimes[j])
+
+			if template.Type == TRANSACTION_TYPE_INCOME {
+				addChange(template.AccountId, dayIndex, template.Amount)
+			} else if template.Type == TRANSACTION_TYPE_EXPENSE {
+				addChange(template.AccountId, dayIndex, -template.Amount)
+			} else if template.Type == TRANSACTION_TYPE_TRANSFER {
+				addChange(template.AccountId, dayIndex, -template.Amount)
+				addChange(template.RelatedAccountId, dayIndex, template.RelatedAccountAmount)
+			}
+		}
+	}
+
+	for accountId, monthlyAmount := range source.AverageMonthlyAmounts {
+		for dayIndex := 1; dayIndex < days; dayIndex++ {
+			date := startDate.AddDate(0, 0, dayIndex)
+			daysInMonth := int64(time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, clientTimezone).Day())
+			day := int64(date.Day())
+
+			// spread the monthly amount over the days of month without losing the remainder
+			addChange(accountId, dayIndex, monthlyAmount*day/daysInMonth-monthlyAmount*(day-1)/daysInMonth)
+		}
+	}
+
+	creditCardLimits := make(map[int64]int64)
+	creditCardBalances := make(map[int64][]int64)
+	dailyBalances := make([][]int64, len(accounts))
+
+	for i := 0; i < len(source.Accounts); i++ {
+		account := source.Accounts[i]
+
+		if account.ParentAccountId == LevelOneAccountParentId && account.GetCreditCardLimit() > 0 {
+			creditCardLimits[account.AccountId] = account.GetCreditCardLimit()
+			creditCardBalances[account.AccountId] = make([]int64, days)
+		}
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		balance := initialBalances[i]
+		dailyBalances[i] = make([]int64, days)
+
+		creditCardAccountId := account.AccountId
+
+		if account.ParentAccountId != LevelOneAccountParentId {
+			creditCardAccountId = account.ParentAccountId
+		}
+
+		for dayIndex := 0; dayIndex < days; dayIndex++ {
+			balance += dailyChanges[i][dayIndex]
+			dailyBalances[i][dayIndex] = balance
+
+			if totalBalances, exists := creditCardBalances[creditCardAccountId]; exists {
+				totalBalances[dayIndex] += balance
+			}
+		}
+	}
+
+	response := &TransactionForecastResponse{
+		StartDate: utils.FormatUnixTimeToLongDate(startDate.Unix(), clientTimezone),
+		EndDate:   utils.FormatUnixTimeToLongDate(startDate.AddDate(0, 0, days-1).Unix(), clientTimezone),
+		Accounts:  make([]*TransactionForecastAccountResponse, len(accounts)),
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		accountResp := &TransactionForecastAccountResponse{
+			AccountId:      account.AccountId,
+			Currency:       account.Currency,
+			CurrentBalance: account.Balance,
+			DailyBalances:  make([]*TransactionForecastDailyBalanceResponse, days),
+			Alerts:         make([]*TransactionForecastAlertResponse, 0),
+		}
+
+		creditCardAccountId := account.AccountId
+
+		if account.ParentAccountId != LevelOneAccountParentId {
+			creditCardAccountId = account.ParentAccountId
+		}
+
+		creditCardLimit, hasCreditCardLimit := creditCardLimits[creditCardAccountId]
+		accountResp.CreditCardLimit = creditCardLimit
+		wasNegative := false
+		wasExceeded := false
+
+		for dayIndex := 0; dayIndex < days; dayIndex++ {
+			date := utils.FormatUnixTimeToLongDate(startDate.AddDate(0, 0, dayIndex).Unix(), clientTimezone)
+			balance := dailyBalances[i][dayIndex]
+
+			accountResp.DailyBalances[dayIndex] = &TransactionForecastDailyBalanceResponse{
+				Date:    date,
+				Balance: balance,
+			}
+
+			// only the first day of each period when the balance is abnormal is flagged
+			isNegative := account.Category.IsAsset() && balance < 0
+
+			if isNegative && !wasNegative {
+				accountResp.Alerts = append(accountResp.Alerts, &TransactionForecastAlertResponse{
+					Type:    TRANSACTION_FORECAST_ALERT_TYPE_NEGATIVE_BALANCE,
+					Date:    date,
+					Balance: balance,
+				})
+			}
+
+			wasNegative = isNegative
+
+			if hasCreditCardLimit {
+				totalBalance := creditCardBalances[creditCardAccountId][dayIndex]
+				isExceeded := -totalBalance > creditCardLimit
+
+				if isExceeded && !wasExceeded {
+					accountResp.Alerts = append(accountResp.Alerts, &TransactionForecastAlertResponse{
+						Type:    TRANSACTION_FORECAST_ALERT_TYPE_CREDIT_LIMIT_EXCEEDED,
+						Date:    date,
+						Balance: totalBalance,
+					})
+				}
+
+				wasExceeded = isExceeded
+			}
+		}
+
+		response.Accounts[i] = accountResp
+	}
+
+	return response
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestBuildTransactionForecast_ScheduledTemplatesAndNegativeBalance(t *testing.T) {
+	startUnixTime := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC).Unix()
+
+	accounts := []*Account{
+		{AccountId: 1001, Category: ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 10000},
+		{AccountId: 1002, Category: ACCOUNT_CATEGORY_SAVINGS_ACCOUNT, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 0},
+	}
+
+	templates := []*TransactionTemplate{
+		{Type: TRANSACTION_TYPE_EXPENSE, AccountId: 1001, Amount: 6000, ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "10"},
+		{Type: TRANSACTION_TYPE_TRANSFER, AccountId: 1001, RelatedAccountId: 1002, Amount: 1000, RelatedAccountAmount: 1000, ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "20"},
+	}
+
+	forecast := BuildTransactionForecast(&TransactionForecastSource{
+		Accounts:           accounts,
+		ScheduledTemplates: templates,
+		StartUnixTime:      startUnixTime,
+		Months:             2,
+	})
+
+	assert.Equal(t, "2024-09-01", forecast.StartDate)
+	assert.Equal(t, "2024-10-31", forecast.EndDate)
+	assert.Equal(t, 2, len(forecast.Accounts))
+
+	checkingAccount := forecast.Accounts[0]
+	assert.Equal(t, int64(1001), checkingAccount.AccountId)
+	assert.Equal(t, 61, len(checkingAccount.DailyBalances))
+	assert.Equal(t, int64(10000), checkingAccount.DailyBalances[8].Balance)
+	assert.Equal(t, int64(4000), checkingAccount.DailyBalances[9].Balance)
+	assert.Equal(t, int64(3000), checkingAccount.DailyBalances[19].Balance)
+	assert.Equal(t, int64(-3000), checkingAccount.DailyBalances[39].Balance)
+	assert.Equal(t, int64(-4000), checkingAccount.DailyBalances[60].Balance)
+
+	assert.Equal(t, 1, len(checkingAccount.Alerts))
+	assert.Equal(t, TRANSACTION_FORECAST_ALERT_TYPE_NEGATIVE_BALANCE, checkingAccount.Alerts[0].Type)
+	assert.Equal(t, "2024-10-10", checkingAccount.Alerts[0].Date)
+	assert.Equal(t, int64(-3000), checkingAccount.Alerts[0].Balance)
+
+	savingsAccount := forecast.Accounts[1]
+	assert.Equal(t, int64(1000), savingsAccount.DailyBalances[19].Balance)
+	assert.Equal(t, int64(2000), savingsAccount.DailyBalances[60].Balance)
+	assert.Equal(t, 0, len(savingsAccount.Alerts))
+}
+
+func TestBuildTransactionForecast_ScheduledTemplatesOfOtherAccounts(t *testing.T) {
+	startUnixTime := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC).Unix()
+
+	accounts := []*Account{
+		{AccountId: 1001, Category: ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 10000},
+	}
+
+	templates := []*TransactionTemplate{
+		{Type: TRANSACTION_TYPE_EXPENSE, AccountId: 2001, Amount: 6000, ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "10"},
+		{Type: TRANSACTION_TYPE_TRANSFER, AccountId: 1001, RelatedAccountId: 2002, Amount: 1000, RelatedAccountAmount: 1000, ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "20"},
+	}
+
+	forecast := BuildTransactionForecast(&TransactionForecastSource{
+		Accounts:           accounts,
+		ScheduledTemplates: templates,
+		StartUnixTime:      startUnixTime,
+		Months:             2,
+	})
+
+	assert.Equal(t, 1, len(forecast.Accounts))
+	assert.Equal(t, int64(10000), forecast.Accounts[0].DailyBalances[60].Balance)
+}
+
+func TestBuildTransactionForecast_FutureTransactionsAndAverageAmounts(t *testing.T) {
+	startUnixTime := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC).Unix()
+
+	accounts := []*Account{
+		{AccountId: 1001, Category: ACCOUNT_CATEGORY_CASH, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 500},
+	}
+
+	futureTransactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 1001, Amount: 200, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2024, 9, 5, 8, 0, 0, 0, time.UTC).Unix())},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1001, Amount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC).Unix())},
+	}
+
+	forecast := BuildTransactionForecast(&TransactionForecastSource{
+		Accounts:              accounts,
+		FutureTransactions:    futureTransactions,
+		AverageMonthlyAmounts: map[int64]int64{1001: -3000},
+		StartUnixTime:         startUnixTime,
+		Months:                1,
+	})
+
+	cashAccount := forecast.Accounts[0]
+	assert.Equal(t, int64(500), cashAccount.CurrentBalance)
+	assert.Equal(t, 30, len(cashAccount.DailyBalances))
+
+	// the balance of today excludes the future transactions
+	assert.Equal(t, int64(400), cashAccount.DailyBalances[0].Balance)
+	assert.Equal(t, int64(300), cashAccount.DailyBalances[1].Balance)
+	assert.Equal(t, int64(100), cashAccount.DailyBalances[3].Balance)
+	assert.Equal(t, int64(200), cashAccount.DailyBalances[4].Balance)
+	assert.Equal(t, int64(-2300), cashAccount.DailyBalances[29].Balance)
+
+	assert.Equal(t, 1, len(cashAccount.Alerts))
+	assert.Equal(t, "2024-09-08", cashAccount.Alerts[0].Date)
+}
+
+func TestBuildTransactionForecast_CreditCardLimit(t *testing.T) {
+	startUnixTime := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC).Unix()
+	creditCardLimit := int64(5000)
+
+	accounts := []*Account{
+		{AccountId: 1001, Category: ACCOUNT_CATEGORY_CREDIT_CARD, Type: ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Extend: &AccountExtend{CreditCardLimit: &creditCardLimit}},
+		{AccountId: 1002, ParentAccountId: 1001, Category: ACCOUNT_CATEGORY_CREDIT_CARD, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: -2000},
+		{AccountId: 1003, ParentAccountId: 1001, Category: ACCOUNT_CATEGORY_CREDIT_CARD, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: -2000},
+	}
+
+	templates := []*TransactionTemplate{
+		{Type: TRANSACTION_TYPE_EXPENSE, AccountId: 1003, Amount: 1500, ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "15"},
+	}
+
+	forecast := BuildTransactionForecast(&TransactionForecastSource{
+		Accounts:           accounts,
+		ScheduledTemplates: templates,
+		StartUnixTime:      startUnixTime,
+		Months:             1,
+	})
+
+	assert.Equal(t, 2, len(forecast.Accounts))
+
+	for i := 0; i < len(forecast.Accounts); i++ {
+		assert.Equal(t, creditCardLimit, forecast.Accounts[i].CreditCardLimit)
+		assert.Equal(t, 1, len(forecast.Accounts[i].Alerts))
+		assert.Equal(t, TRANSACTION_FORECAST_ALERT_TYPE_CREDIT_LIMIT_EXCEEDED, forecast.Accounts[i].Alerts[0].Type)
+		assert.Equal(t, "2024-09-15", forecast.Accounts[i].Alerts[0].Date)
+		assert.Equal(t, int64(-5500), forecast.Accounts[i].Alerts[0].Balance)
+	}
+}
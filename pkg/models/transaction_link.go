@@ -0,0 +1,140 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/errs"
+
+// TransactionLinkType represents the relation between a transaction and the transaction it is linked to
+type TransactionLinkType byte
+
+// Transaction link types
+const (
+	TRANSACTION_LINK_TYPE_REFUND_OF        TransactionLinkType = 1
+	TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF TransactionLinkType = 2
+	TRANSACTION_LINK_TYPE_INSTALLMENT_OF   TransactionLinkType = 3
+	TRANSACTION_LINK_TYPE_CUSTOM           TransactionLinkType = 4
+)
+
+// IsValid returns whether the link type is a known type
+func (t TransactionLinkType) IsValid() bool {
+	return t >= TRANSACTION_LINK_TYPE_REFUND_OF && t <= TRANSACTION_LINK_TYPE_CUSTOM
+}
+
+// IsRefund returns whether the linking transaction returns the money of the linked transaction,
+// which can be netted against the category of the linked transaction in statistics
+func (t TransactionLinkType) IsRefund() bool {
+	return t == TRANSACTION_LINK_TYPE_REFUND_OF || t == TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF
+}
+
+// IsLinkedToOriginal returns whether the linking transaction belongs to only one original transaction
+func (t TransactionLinkType) IsLinkedToOriginal() bool {
+	return t != TRANSACTION_LINK_TYPE_CUSTOM
+}
+
+// TransactionLink represents a typed link from a transaction (e.g. a refund) to another transaction (e.g. the original purchase)
+// stored in database, a transfer is always linked by its transfer out transaction
+type TransactionLink struct {
+	LinkId              int64               `xorm:"PK"`
+	Uid                 int64               `xorm:"NOT NULL"`
+	Deleted             bool                `xorm:"INDEX(IDX_transaction_link_deleted_fund_id_transaction_id) INDEX(IDX_transaction_link_deleted_fund_id_linked_transaction_id) NOT NULL"`
+	FundId              int64               `xorm:"INDEX(IDX_transaction_link_deleted_fund_id_transaction_id) INDEX(IDX_transaction_link_deleted_fund_id_linked_transaction_id) NOT NULL"`
+	TransactionId       int64               `xorm:"INDEX(IDX_transaction_link_deleted_fund_id_transaction_id) NOT NULL"`
+	LinkedTransactionId int64               `xorm:"INDEX(IDX_transaction_link_deleted_fund_id_linked_transaction_id) NOT NULL"`
+	LinkType            TransactionLinkType `xorm:"NOT NULL"`
+	Comment             string              `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime     int64
+	DeletedUnixTime     int64
+}
+
+// TransactionLinkListRequest represents all parameters of transaction link listing request
+type TransactionLinkListRequest struct {
+	TransactionId int64 `form:"transaction_id,string" binding:"required,min=1"`
+}
+
+// TransactionLinkCreateRequest represents all parameters of transaction link creation request
+type TransactionLinkCreateRequest struct {
+	TransactionId       int64               `json:"transactionId,string" binding:"required,min=1"`
+	LinkedTransactionId int64               `json:"linkedTransactionId,string" binding:"required,min=1"`
+	LinkType            TransactionLinkType `json:"linkType" binding:"required,min=1,max=4"`
+	Comment             string              `json:"comment" binding:"max=255"`
+}
+
+// TransactionLinkDeleteRequest represents all parameters of transaction link deleting request
+type TransactionLinkDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionLinkInfoResponse represents a view-object of transaction link
+type TransactionLinkInfoResponse struct {
+	Id                  int64               `json:"id,string"`
+	TransactionId       int64               `json:"transactionId,string"`
+	LinkedTransactionId int64               `json:"linkedTransactionId,string"`
+	LinkType            TransactionLinkType `json:"linkType"`
+	Comment             string              `json:"comment"`
+	CreatedAt           int64               `json:"createdAt"`
+}
+
+// ToTransactionLinkInfoResponse returns a view-object according to database model
+func (l *TransactionLink) ToTransactionLinkInfoResponse() *TransactionLinkInfoResponse {
+	return &TransactionLinkInfoResponse{
+		Id:                  l.LinkId,
+		TransactionId:       l.TransactionId,
+		LinkedTransactionId: l.LinkedTransactionId,
+		LinkType:            l.LinkType,
+		Comment:             l.Comment,
+		CreatedAt:           l.CreatedUnixTime,
+	}
+}
+
+// ValidateTransactionLink checks whether the transaction can be linked to the linked transaction with the specified link type,
+// refunds and reimbursements must be incomes linked to expenses, and installments must be expenses or transfers linked to expenses
+func ValidateTransactionLink(linkType TransactionLinkType, transaction *Transaction, linkedTransaction *Transaction) error {
+	if !linkType.IsValid() {
+		return errs.ErrTransactionLinkTypeInvalid
+	}
+
+	if transaction.TransactionId == linkedTransaction.TransactionId {
+		return errs.ErrTransactionCannotLinkToItself
+	}
+
+	if transaction.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE || linkedTransaction.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return errs.ErrTransactionLinkTypeNotMatchTransaction
+	}
+
+	if linkType.IsRefund() && (transaction.Type != TRANSACTION_DB_TYPE_INCOME || linkedTransaction.Type != TRANSACTION_DB_TYPE_EXPENSE) {
+		return errs.ErrTransactionLinkTypeNotMatchTransaction
+	}
+
+	if linkType == TRANSACTION_LINK_TYPE_INSTALLMENT_OF &&
+		((transaction.Type != TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != TRANSACTION_DB_TYPE_TRANSFER_OUT) || linkedTransaction.Type != TRANSACTION_DB_TYPE_EXPENSE) {
+		return errs.ErrTransactionLinkTypeNotMatchTransaction
+	}
+
+	return nil
+}
+
+// NetRefundTransactions returns the transactions in which every refund or reimbursement income is replaced by a negative expense
+// in the category of its original expense, so that the refunded amount reduces the original category instead of counting as income,
+// the key of original transactions map is the transaction id of refund
+func NetRefundTransactions(transactions []*Transaction, originalTransactions map[int64]*Transaction) []*Transaction {
+	if len(originalTransactions) < 1 {
+		return transactions
+	}
+
+	nettedTransactions := make([]*Transaction, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		originalTransaction, exists := originalTransactions[transaction.TransactionId]
+
+		if !exists || transaction.Type != TRANSACTION_DB_TYPE_INCOME || originalTransaction.Type != TRANSACTION_DB_TYPE_EXPENSE {
+			nettedTransactions = append(nettedTransactions, transaction)
+			continue
+		}
+
+		nettedTransaction := *transaction
+		nettedTransaction.Type = TRANSACTION_DB_TYPE_EXPENSE
+		nettedTransaction.CategoryId = originalTransaction.CategoryId
+		nettedTransaction.Amount = -transaction.Amount
+		nettedTransactions = append(nettedTransactions, &nettedTransaction)
+	}
+
+	return nettedTransactions
+}
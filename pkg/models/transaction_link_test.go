@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestValidateTransactionLink_Refund(t *testing.T) {
+	income := &Transaction{TransactionId: 1, Type: TRANSACTION_DB_TYPE_INCOME}
+	expense := &Transaction{TransactionId: 2, Type: TRANSACTION_DB_TYPE_EXPENSE}
+	transfer := &Transaction{TransactionId: 3, Type: TRANSACTION_DB_TYPE_TRANSFER_OUT}
+
+	assert.Nil(t, ValidateTransactionLink(TRANSACTION_LINK_TYPE_REFUND_OF, income, expense))
+	assert.Nil(t, ValidateTransactionLink(TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF, income, expense))
+	assert.Equal(t, errs.ErrTransactionLinkTypeNotMatchTransaction, ValidateTransactionLink(TRANSACTION_LINK_TYPE_REFUND_OF, expense, income))
+	assert.Equal(t, errs.ErrTransactionLinkTypeNotMatchTransaction, ValidateTransactionLink(TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF, transfer, expense))
+}
+
+func TestValidateTransactionLink_Installment(t *testing.T) {
+	purchase := &Transaction{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE}
+	payment := &Transaction{TransactionId: 2, Type: TRANSACTION_DB_TYPE_TRANSFER_OUT}
+	income := &Transaction{TransactionId: 3, Type: TRANSACTION_DB_TYPE_INCOME}
+
+	assert.Nil(t, ValidateTransactionLink(TRANSACTION_LINK_TYPE_INSTALLMENT_OF, payment, purchase))
+	assert.Equal(t, errs.ErrTransactionLinkTypeNotMatchTransaction, ValidateTransactionLink(TRANSACTION_LINK_TYPE_INSTALLMENT_OF, income, purchase))
+	assert.Equal(t, errs.ErrTransactionLinkTypeNotMatchTransaction, ValidateTransactionLink(TRANSACTION_LINK_TYPE_INSTALLMENT_OF, purchase, payment))
+}
+
+func TestValidateTransactionLink_InvalidLinks(t *testing.T) {
+	expense := &Transaction{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE}
+	income := &Transaction{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME}
+	modifyBalance := &Transaction{TransactionId: 3, Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE}
+
+	assert.Nil(t, ValidateTransactionLink(TRANSACTION_LINK_TYPE_CUSTOM, expense, income))
+	assert.Equal(t, errs.ErrTransactionLinkTypeInvalid, ValidateTransactionLink(0, expense, income))
+	assert.Equal(t, errs.ErrTransactionLinkTypeInvalid, ValidateTransactionLink(5, expense, income))
+	assert.Equal(t, errs.ErrTransactionCannotLinkToItself, ValidateTransactionLink(TRANSACTION_LINK_TYPE_CUSTOM, expense, expense))
+	assert.Equal(t, errs.ErrTransactionLinkTypeNotMatchTransaction, ValidateTransactionLink(TRANSACTION_LINK_TYPE_CUSTOM, modifyBalance, income))
+}
+
+func TestNetRefundTransactions(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1001, Amount: 5000},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, AccountId: 1001, Amount: 2000},
+		{TransactionId: 3, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 202, AccountId: 1001, Amount: 3000},
+	}
+
+	originalTransactions := map[int64]*Transaction{
+		2: {TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101},
+	}
+
+	actualTransactions := NetRefundTransactions(transactions, originalTransactions)
+	assert.Equal(t, 3, len(actualTransactions))
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, actualTransactions[1].Type)
+	assert.Equal(t, int64(101), actualTransactions[1].CategoryId)
+	assert.Equal(t, int64(1001), actualTransactions[1].AccountId)
+	assert.Equal(t, int64(-2000), actualTransactions[1].Amount)
+
+	assert.Equal(t, TRANSACTION_DB_TYPE_INCOME, actualTransactions[2].Type)
+	assert.Equal(t, int64(202), actualTransactions[2].CategoryId)
+
+	// the original transactions are not modified
+	assert.Equal(t, TRANSACTION_DB_TYPE_INCOME, transactions[1].Type)
+	assert.Equal(t, int64(2000), transactions[1].Amount)
+}
+
+func TestNetRefundTransactions_NoOriginalTransactions(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_INCOME, CategoryId: 201, Amount: 2000},
+	}
+
+	actualTransactions := NetRefundTransactions(transactions, nil)
+	assert.Equal(t, transactions, actualTransactions)
+}
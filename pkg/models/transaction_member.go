@@ -0,0 +1,214 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// TransactionMemberSplitPercentageTotal represents the sum of all percentages of a percentage split, percentages are stored in hundredths of a percent
+const TransactionMemberSplitPercentageTotal = 10000
+
+// TransactionMemberMaxSplitShares represents the maximum weight of one member in a shares split
+const TransactionMemberMaxSplitShares = 10000
+
+// TransactionMemberSplitType represents how the transaction amount is split among linked members
+type TransactionMemberSplitType byte
+
+// Transaction member split types
+const (
+	TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL      TransactionMemberSplitType = 1
+	TRANSACTION_MEMBER_SPLIT_TYPE_SHARES     TransactionMemberSplitType = 2
+	TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE TransactionMemberSplitType = 3
+	TRANSACTION_MEMBER_SPLIT_TYPE_EXACT      TransactionMemberSplitType = 4
+)
+
+// String returns a textual representation of the transaction member split type enum
+func (t TransactionMemberSplitType) String() string {
+	switch t {
+	case TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL:
+		return "Equal"
+	case TRANSACTION_MEMBER_SPLIT_TYPE_SHARES:
+		return "Shares"
+	case TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE:
+		return "Percentage"
+	case TRANSACTION_MEMBER_SPLIT_TYPE_EXACT:
+		return "Exact"
+	default:
+		return "Unknown"
+	}
+}
+
+// TransactionMemberSplitRequest represents a member and its split value in transaction creation or modification request
+type TransactionMemberSplitRequest struct {
+	MemberId   int64 `json:"memberId,string" binding:"required,min=1"`
+	SplitValue int64 `json:"splitValue" binding:"min=0,max=99999999999"`
+}
+
+// TransactionMemberInfoResponse represents a view-object of transaction member
+type TransactionMemberInfoResponse struct {
+	MemberId   int64 `json:"memberId,string"`
+	SplitValue int64 `json:"splitValue"`
+	Amount     int64 `json:"amount"`
+}
+
+// CalculateTransactionMemberAmounts validates the split of all members linked to one transaction and returns
+// the amount each member shares, the rounding remainder is assigned one unit at a time to the members with
+// the largest fractional parts and then to the members with the smallest member id
+func CalculateTransactionMemberAmounts(totalAmount int64, members []*TransactionMember) (map[int64]int64, error) {
+	amounts := make(map[int64]int64, len(members))
+
+	if len(members) < 1 {
+		return amounts, nil
+	}
+
+	splitType := members[0].SplitType
+	memberIds := make(map[int64]bool, len(members))
+	valueSum := int64(0)
+
+	for _, member := range members {
+		if member.SplitType != splitType {
+			return nil, errs.ErrTransactionMemberSplitTypeInvalid
+		}
+
+		if memberIds[member.MemberId] {
+			return nil, errs.ErrTransactionMemberDuplicated
+		}
+
+		memberIds[member.MemberId] = true
+
+		switch splitType {
+		case TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL:
+		case TRANSACTION_MEMBER_SPLIT_TYPE_SHARES:
+			if member.SplitValue < 1 || member.SplitValue > TransactionMemberMaxSplitShares {
+				return nil, errs.ErrTransactionMemberSplitValueInvalid
+			}
+		case TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE:
+			if member.SplitValue < 1 || member.SplitValue > TransactionMemberSplitPercentageTotal {
+				return nil, errs.ErrTransactionMemberSplitValueInvalid
+			}
+		case TRANSACTION_MEMBER_SPLIT_TYPE_EXACT:
+			if member.SplitValue < 0 {
+				return nil, errs.ErrTransactionMemberSplitValueInvalid
+			}
+		default:
+			return nil, errs.ErrTransactionMemberSplitTypeInvalid
+		}
+
+		valueSum += member.SplitValue
+	}
+
+	if splitType == TRANSACTION_MEMBER_SPLIT_TYPE_EXACT {
+		if valueSum != totalAmount {
+			return nil, errs.ErrTransactionMemberSplitAmountNotEqualToTransactionAmount
+		}
+
+		for _, member := range members {
+			amounts[member.MemberId] = member.SplitValue
+		}
+
+		return amounts, nil
+	}
+
+	if splitType == TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE && valueSum != TransactionMemberSplitPercentageTotal {
+		return nil, errs.ErrTransactionMemberSplitPercentageNotEqualTo100
+	}
+
+	weights := make([]int64, len(members))
+
+	for i, member := range members {
+		if splitType == TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL {
+			weights[i] = 1
+		} else {
+			weights[i] = member.SplitValue
+		}
+	}
+
+	shares := splitAmountByWeights(totalAmount, members, weights)
+
+	for i, member := range members {
+		amounts[member.MemberId] = shares[i]
+	}
+
+	return amounts, nil
+}
+
+func splitAmountByWeights(totalAmount int64, members []*TransactionMember, weights []int64) []int64 {
+	type memberRemainder struct {
+		index     int
+		memberId  int64
+		remainder int64
+	}
+
+	negative := totalAmount < 0
+	absAmount := totalAmount
+
+	if negative {
+		absAmount = -totalAmount
+	}
+
+	weightSum := int64(0)
+
+	for _, weight := range weights {
+		weightSum += weight
+	}
+
+	shares := make([]int64, len(members))
+	remainders := make([]*memberRemainder, len(members))
+	allocated := int64(0)
+
+	for i, member := range members {
+		shares[i] = absAmount * weights[i] / weightSum
+		remainders[i] = &memberRemainder{
+			index:     i,
+			memberId:  member.MemberId,
+			remainder: absAmount * weights[i] % weightSum,
+		}
+		allocated += shares[i]
+	}
+
+	sort.Slice(remainders, func(i, j int) bool {
+		if remainders[i].remainder != remainders[j].remainder {
+			return remainders[i].remainder > remainders[j].remainder
+		}
+
+		return remainders[i].memberId < remainders[j].memberId
+	})
+
+	for i := 0; int64(i) < absAmount-allocated; i++ {
+		shares[remainders[i].index]++
+	}
+
+	if negative {
+		for i := 0; i < len(shares); i++ {
+			shares[i] = -shares[i]
+		}
+	}
+
+	return shares
+}
+
+// ToTransactionMemberInfoResponses returns the view-objects of all members linked to one transaction, ordered by member id
+func ToTransactionMemberInfoResponses(totalAmount int64, members []*TransactionMember) []*TransactionMemberInfoResponse {
+	amounts, err := CalculateTransactionMemberAmounts(totalAmount, members)
+
+	if err != nil {
+		amounts = make(map[int64]int64)
+	}
+
+	memberResps := make([]*TransactionMemberInfoResponse, len(members))
+
+	for i, member := range members {
+		memberResps[i] = &TransactionMemberInfoResponse{
+			MemberId:   member.MemberId,
+			SplitValue: member.SplitValue,
+			Amount:     amounts[member.MemberId],
+		}
+	}
+
+	sort.Slice(memberResps, func(i, j int) bool {
+		return memberResps[i].MemberId < memberResps[j].MemberId
+	})
+
+	return memberResps
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestCalculateTransactionMemberAmounts_Equal(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 3, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}
+
+	amounts, err := CalculateTransactionMemberAmounts(100, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: 34, 2: 33, 3: 33}, amounts)
+
+	amounts, err = CalculateTransactionMemberAmounts(101, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: 34, 2: 34, 3: 33}, amounts)
+}
+
+func TestCalculateTransactionMemberAmounts_EqualNegativeAmount(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 3, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}
+
+	amounts, err := CalculateTransactionMemberAmounts(-100, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: -34, 2: -33, 3: -33}, amounts)
+}
+
+func TestCalculateTransactionMemberAmounts_Shares(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 2},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 1},
+		{MemberId: 3, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 1},
+	}
+
+	amounts, err := CalculateTransactionMemberAmounts(1001, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: 501, 2: 250, 3: 250}, amounts)
+}
+
+func TestCalculateTransactionMemberAmounts_Percentage(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: 3333},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: 3333},
+		{MemberId: 3, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: 3334},
+	}
+
+	amounts, err := CalculateTransactionMemberAmounts(1000, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: 333, 2: 333, 3: 334}, amounts)
+}
+
+func TestCalculateTransactionMemberAmounts_PercentageNotEqualTo100(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: 5000},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: 4000},
+	}
+
+	_, err := CalculateTransactionMemberAmounts(1000, members)
+	assert.Equal(t, errs.ErrTransactionMemberSplitPercentageNotEqualTo100, err)
+}
+
+func TestCalculateTransactionMemberAmounts_Exact(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: 700},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: 300},
+	}
+
+	amounts, err := CalculateTransactionMemberAmounts(1000, members)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]int64{1: 700, 2: 300}, amounts)
+
+	_, err = CalculateTransactionMemberAmounts(999, members)
+	assert.Equal(t, errs.ErrTransactionMemberSplitAmountNotEqualToTransactionAmount, err)
+}
+
+func TestCalculateTransactionMemberAmounts_InvalidSplit(t *testing.T) {
+	_, err := CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 1},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberSplitTypeInvalid, err)
+
+	_, err = CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: 0},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberSplitTypeInvalid, err)
+
+	_, err = CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberDuplicated, err)
+
+	_, err = CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 0},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberSplitValueInvalid, err)
+
+	_, err = CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE, SplitValue: TransactionMemberSplitPercentageTotal + 1},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberSplitValueInvalid, err)
+
+	_, err = CalculateTransactionMemberAmounts(1000, []*TransactionMember{
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: 1100},
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: -100},
+	})
+	assert.Equal(t, errs.ErrTransactionMemberSplitValueInvalid, err)
+}
+
+func TestCalculateTransactionMemberAmounts_NoMembers(t *testing.T) {
+	amounts, err := CalculateTransactionMemberAmounts(1000, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(amounts))
+}
+
+func TestToTransactionMemberInfoResponses(t *testing.T) {
+	members := []*TransactionMember{
+		{MemberId: 2, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 1},
+		{MemberId: 1, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 3},
+	}
+
+	memberResps := ToTransactionMemberInfoResponses(400, members)
+	assert.Equal(t, 2, len(memberResps))
+	assert.Equal(t, &TransactionMemberInfoResponse{MemberId: 1, SplitValue: 3, Amount: 300}, memberResps[0])
+	assert.Equal(t, &TransactionMemberInfoResponse{MemberId: 2, SplitValue: 1, Amount: 100}, memberResps[1])
+}
+
+func TestTransactionMemberSplitType_String(t *testing.T) {
+	assert.Equal(t, "Equal", TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL.String())
+	assert.Equal(t, "Shares", TRANSACTION_MEMBER_SPLIT_TYPE_SHARES.String())
+	assert.Equal(t, "Percentage", TRANSACTION_MEMBER_SPLIT_TYPE_PERCENTAGE.String())
+	assert.Equal(t, "Exact", TRANSACTION_MEMBER_SPLIT_TYPE_EXACT.String())
+	assert.Equal(t, "Unknown", TransactionMemberSplitType(0).String())
+}
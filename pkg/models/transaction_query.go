@@ -0,0 +1,616 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const MaximumTermCountOfTransactionQuery = 50
+const MaximumNestingDepthOfTransactionQuery = 10
+
+// TransactionQueryNodeType represents the node type of transaction query expression
+type TransactionQueryNodeType byte
+
+// Transaction query node types
+const (
+	TRANSACTION_QUERY_NODE_TYPE_TERM TransactionQueryNodeType = 1
+	TRANSACTION_QUERY_NODE_TYPE_AND  TransactionQueryNodeType = 2
+	TRANSACTION_QUERY_NODE_TYPE_OR   TransactionQueryNodeType = 3
+	TRANSACTION_QUERY_NODE_TYPE_NOT  TransactionQueryNodeType = 4
+)
+
+// TransactionQueryField represents the field of transaction query term
+type TransactionQueryField string
+
+// Transaction query fields
+const (
+	TRANSACTION_QUERY_FIELD_KEYWORD  TransactionQueryField = ""
+	TRANSACTION_QUERY_FIELD_CATEGORY TransactionQueryField = "category"
+	TRANSACTION_QUERY_FIELD_ACCOUNT  TransactionQueryField = "account"
+	TRANSACTION_QUERY_FIELD_TAG      TransactionQueryField = "tag"
+	TRANSACTION_QUERY_FIELD_PAYEE    TransactionQueryField = "payee"
+	TRANSACTION_QUERY_FIELD_COMMENT  TransactionQueryField = "comment"
+	TRANSACTION_QUERY_FIELD_AMOUNT   TransactionQueryField = "amount"
+	TRANSACTION_QUERY_FIELD_TYPE     TransactionQueryField = "type"
+	TRANSACTION_QUERY_FIELD_AFTER    TransactionQueryField = "after"
+	TRANSACTION_QUERY_FIELD_BEFORE   TransactionQueryField = "before"
+)
+
+// TransactionQueryOperator represents the operator of transaction query term
+type TransactionQueryOperator string
+
+// Transaction query operators
+const (
+	TRANSACTION_QUERY_OPERATOR_MATCH                 TransactionQueryOperator = ":"
+	TRANSACTION_QUERY_OPERATOR_EQUAL                 TransactionQueryOperator = "="
+	TRANSACTION_QUERY_OPERATOR_NOT_EQUAL             TransactionQueryOperator = "!="
+	TRANSACTION_QUERY_OPERATOR_GREATER_THAN          TransactionQueryOperator = ">"
+	TRANSACTION_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL TransactionQueryOperator = ">="
+	TRANSACTION_QUERY_OPERATOR_LESS_THAN             TransactionQueryOperator = "<"
+	TRANSACTION_QUERY_OPERATOR_LESS_THAN_OR_EQUAL    TransactionQueryOperator = "<="
+)
+
+var transactionQueryFieldOperators = map[TransactionQueryField][]TransactionQueryOperator{
+	TRANSACTION_QUERY_FIELD_CATEGORY: {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_ACCOUNT:  {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_TAG:      {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_PAYEE:    {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_COMMENT:  {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_AMOUNT: {
+		TRANSACTION_QUERY_OPERATOR_MATCH,
+		TRANSACTION_QUERY_OPERATOR_EQUAL,
+		TRANSACTION_QUERY_OPERATOR_NOT_EQUAL,
+		TRANSACTION_QUERY_OPERATOR_GREATER_THAN,
+		TRANSACTION_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL,
+		TRANSACTION_QUERY_OPERATOR_LESS_THAN,
+		TRANSACTION_QUERY_OPERATOR_LESS_THAN_OR_EQUAL,
+	},
+	TRANSACTION_QUERY_FIELD_TYPE:   {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_AFTER:  {TRANSACTION_QUERY_OPERATOR_MATCH},
+	TRANSACTION_QUERY_FIELD_BEFORE: {TRANSACTION_QUERY_OPERATOR_MATCH},
+}
+
+var transactionQueryTypeNames = map[string]TransactionType{
+	"balance":  TRANSACTION_TYPE_MODIFY_BALANCE,
+	"income":   TRANSACTION_TYPE_INCOME,
+	"expense":  TRANSACTION_TYPE_EXPENSE,
+	"transfer": TRANSACTION_TYPE_TRANSFER,
+}
+
+// TransactionQueryExpression represents a node of parsed transaction query
+type TransactionQueryExpression struct {
+	NodeType    TransactionQueryNodeType
+	Children    []*TransactionQueryExpression
+	Field       TransactionQueryField
+	Operator    TransactionQueryOperator
+	Value       string
+	Position    int
+	AmountValue int64
+	TimeValue   int64
+	TypeValue   TransactionType
+	ResolvedIds []int64
+}
+
+// TransactionQuerySyntaxErrorContext represents the context of transaction query syntax error returned to client
+type TransactionQuerySyntaxErrorContext struct {
+	Position int    `json:"position"`
+	Reason   string `json:"reason"`
+}
+
+// GetTermsByField returns all the terms of the specified field in the expression
+func (e *TransactionQueryExpression) GetTermsByField(field TransactionQueryField) []*TransactionQueryExpression {
+	terms := make([]*TransactionQueryExpression, 0)
+
+	if e == nil {
+		return terms
+	}
+
+	if e.NodeType == TRANSACTION_QUERY_NODE_TYPE_TERM {
+		if e.Field == field {
+			terms = append(terms, e)
+		}
+
+		return terms
+	}
+
+	for i := 0; i < len(e.Children); i++ {
+		terms = append(terms, e.Children[i].GetTermsByField(field)...)
+	}
+
+	return terms
+}
+
+// String returns a textual representation of the expression
+func (e *TransactionQueryExpression) String() string {
+	if e == nil {
+		return ""
+	}
+
+	switch e.NodeType {
+	case TRANSACTION_QUERY_NODE_TYPE_TERM:
+		if e.Field == TRANSACTION_QUERY_FIELD_KEYWORD {
+			return fmt.Sprintf("%q", e.Value)
+		}
+
+		return fmt.Sprintf("%s%s%q", e.Field, e.Operator, e.Value)
+	case TRANSACTION_QUERY_NODE_TYPE_NOT:
+		return "NOT " + e.Children[0].String()
+	case TRANSACTION_QUERY_NODE_TYPE_AND, TRANSACTION_QUERY_NODE_TYPE_OR:
+		separator := " AND "
+
+		if e.NodeType == TRANSACTION_QUERY_NODE_TYPE_OR {
+			separator = " OR "
+		}
+
+		children := make([]string, len(e.Children))
+
+		for i := 0; i < len(e.Children); i++ {
+			children[i] = e.Children[i].String()
+		}
+
+		return "(" + strings.Join(children, separator) + ")"
+	default:
+		return ""
+	}
+}
+
+type transactionQueryTokenType byte
+
+const (
+	transactionQueryTokenTypeEnd transactionQueryTokenType = iota
+	transactionQueryTokenTypeTerm
+	transactionQueryTokenTypeAnd
+	transactionQueryTokenTypeOr
+	transactionQueryTokenTypeNot
+	transactionQueryTokenTypeLeftParenthesis
+	transactionQueryTokenTypeRightParenthesis
+)
+
+type transactionQueryToken struct {
+	tokenType transactionQueryTokenType
+	position  int
+	text      string
+	term      *TransactionQueryExpression
+}
+
+type transactionQueryParser struct {
+	runes     []rune
+	offset    int
+	utcOffset int16
+	current   *transactionQueryToken
+	termCount int
+	depth     int
+}
+
+// ParseTransactionQuery parses the transaction query (e.g. category:Food tag:trip amount>50 -comment:refund) and returns
+// the expression tree, the dates in query are parsed in the specified timezone, and returns nil if the query is empty
+func ParseTransactionQuery(query string, utcOffset int16) (*TransactionQueryExpression, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	parser := &transactionQueryParser{
+		runes:     []rune(query),
+		utcOffset: utcOffset,
+	}
+
+	err := parser.next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	expression, err := parser.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.current.tokenType == transactionQueryTokenTypeRightParenthesis {
+		return nil, newTransactionQuerySyntaxError(parser.current.position, "unexpected \")\"")
+	} else if parser.current.tokenType != transactionQueryTokenTypeEnd {
+		return nil, newTransactionQuerySyntaxError(parser.current.position, fmt.Sprintf("unexpected \"%s\"", parser.current.text))
+	}
+
+	return expression, nil
+}
+
+func (p *transactionQueryParser) parseOr() (*TransactionQueryExpression, error) {
+	left, err := p.parseAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*TransactionQueryExpression{left}
+
+	for p.current.tokenType == transactionQueryTokenTypeOr {
+		err = p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, right)
+	}
+
+	return p.newLogicalExpression(TRANSACTION_QUERY_NODE_TYPE_OR, children), nil
+}
+
+func (p *transactionQueryParser) parseAnd() (*TransactionQueryExpression, error) {
+	left, err := p.parseUnary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*TransactionQueryExpression{left}
+
+	for {
+		if p.current.tokenType == transactionQueryTokenTypeAnd {
+			err = p.next()
+
+			if err != nil {
+				return nil, err
+			}
+		} else if p.current.tokenType != transactionQueryTokenTypeTerm &&
+			p.current.tokenType != transactionQueryTokenTypeNot &&
+			p.current.tokenType != transactionQueryTokenTypeLeftParenthesis {
+			break
+		}
+
+		right, err := p.parseUnary()
+
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, right)
+	}
+
+	return p.newLogicalExpression(TRANSACTION_QUERY_NODE_TYPE_AND, children), nil
+}
+
+func (p *transactionQueryParser) parseUnary() (*TransactionQueryExpression, error) {
+	token := p.current
+
+	switch token.tokenType {
+	case transactionQueryTokenTypeNot:
+		err := p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		operand, err := p.parseUnary()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &TransactionQueryExpression{
+			NodeType: TRANSACTION_QUERY_NODE_TYPE_NOT,
+			Children: []*TransactionQueryExpression{operand},
+			Position: token.position,
+		}, nil
+	case transactionQueryTokenTypeLeftParenthesis:
+		p.depth++
+
+		if p.depth > MaximumNestingDepthOfTransactionQuery {
+			return nil, newTransactionQuerySyntaxError(token.position, "too many nested parentheses")
+		}
+
+		err := p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expression, err := p.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p.current.tokenType != transactionQueryTokenTypeRightParenthesis {
+			return nil, newTransactionQuerySyntaxError(token.position, "missing closing parenthesis")
+		}
+
+		p.depth--
+		err = p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return expression, nil
+	case transactionQueryTokenTypeTerm:
+		p.termCount++
+
+		if p.termCount > MaximumTermCountOfTransactionQuery {
+			return nil, newTransactionQuerySyntaxError(token.position, "too many terms")
+		}
+
+		err := p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return token.term, nil
+	case transactionQueryTokenTypeEnd:
+		return nil, newTransactionQuerySyntaxError(token.position, "unexpected end of query")
+	default:
+		return nil, newTransactionQuerySyntaxError(token.position, fmt.Sprintf("unexpected \"%s\"", token.text))
+	}
+}
+
+func (p *transactionQueryParser) newLogicalExpression(nodeType TransactionQueryNodeType, children []*TransactionQueryExpression) *TransactionQueryExpression {
+	if len(children) == 1 {
+		return children[0]
+	}
+
+	return &TransactionQueryExpression{
+		NodeType: nodeType,
+		Children: children,
+		Position: children[0].Position,
+	}
+}
+
+func (p *transactionQueryParser) next() error {
+	for p.offset < len(p.runes) && unicode.IsSpace(p.runes[p.offset]) {
+		p.offset++
+	}
+
+	position := p.offset + 1
+
+	if p.offset >= len(p.runes) {
+		p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeEnd, position: position}
+		return nil
+	}
+
+	ch := p.runes[p.offset]
+
+	if ch == '(' {
+		p.offset++
+		p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeLeftParenthesis, position: position, text: "("}
+		return nil
+	} else if ch == ')' {
+		p.offset++
+		p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeRightParenthesis, position: position, text: ")"}
+		return nil
+	} else if ch == '-' && p.offset+1 < len(p.runes) && !unicode.IsSpace(p.runes[p.offset+1]) && p.runes[p.offset+1] != ')' {
+		p.offset++
+		p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeNot, position: position, text: "-"}
+		return nil
+	} else if ch == '"' {
+		value, err := p.readQuotedString()
+
+		if err != nil {
+			return err
+		}
+
+		p.current = &transactionQueryToken{
+			tokenType: transactionQueryTokenTypeTerm,
+			position:  position,
+			text:      value,
+			term: &TransactionQueryExpression{
+				NodeType: TRANSACTION_QUERY_NODE_TYPE_TERM,
+				Field:    TRANSACTION_QUERY_FIELD_KEYWORD,
+				Value:    value,
+				Position: position,
+			},
+		}
+
+		return nil
+	}
+
+	start := p.offset
+
+	for p.offset < len(p.runes) && (unicode.IsLetter(p.runes[p.offset]) || p.runes[p.offset] == '_') {
+		p.offset++
+	}
+
+	fieldName := string(p.runes[start:p.offset])
+	operator := p.readOperator()
+
+	if fieldName == "" || operator == "" {
+		p.offset = start
+		word := p.readWord()
+
+		switch word {
+		case "AND":
+			p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeAnd, position: position, text: word}
+		case "OR":
+			p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeOr, position: position, text: word}
+		case "NOT":
+			p.current = &transactionQueryToken{tokenType: transactionQueryTokenTypeNot, position: position, text: word}
+		default:
+			p.current = &transactionQueryToken{
+				tokenType: transactionQueryTokenTypeTerm,
+				position:  position,
+				text:      word,
+				term: &TransactionQueryExpression{
+					NodeType: TRANSACTION_QUERY_NODE_TYPE_TERM,
+					Field:    TRANSACTION_QUERY_FIELD_KEYWORD,
+					Value:    word,
+					Position: position,
+				},
+			}
+		}
+
+		return nil
+	}
+
+	field := TransactionQueryField(strings.ToLower(fieldName))
+	allowedOperators, exists := transactionQueryFieldOperators[field]
+
+	if !exists {
+		return newTransactionQuerySyntaxError(position, fmt.Sprintf("unknown field \"%s\"", fieldName))
+	}
+
+	if !isTransactionQueryOperatorAllowed(allowedOperators, operator) {
+		return newTransactionQuerySyntaxError(position+len([]rune(fieldName)), fmt.Sprintf("operator \"%s\" is not supported for field \"%s\"", operator, field))
+	}
+
+	valuePosition := p.offset + 1
+	var value string
+
+	if p.offset < len(p.runes) && p.runes[p.offset] == '"' {
+		quotedValue, err := p.readQuotedString()
+
+		if err != nil {
+			return err
+		}
+
+		value = quotedValue
+	} else {
+		value = p.readWord()
+	}
+
+	if value == "" {
+		return newTransactionQuerySyntaxError(valuePosition, fmt.Sprintf("missing value for field \"%s\"", field))
+	}
+
+	term := &TransactionQueryExpression{
+		NodeType: TRANSACTION_QUERY_NODE_TYPE_TERM,
+		Field:    field,
+		Operator: operator,
+		Value:    value,
+		Position: position,
+	}
+
+	err := p.fillTermValue(term, valuePosition)
+
+	if err != nil {
+		return err
+	}
+
+	p.current = &transactionQueryToken{
+		tokenType: transactionQueryTokenTypeTerm,
+		position:  position,
+		text:      string(p.runes[start:p.offset]),
+		term:      term,
+	}
+
+	return nil
+}
+
+func (p *transactionQueryParser) fillTermValue(term *TransactionQueryExpression, valuePosition int) error {
+	switch term.Field {
+	case TRANSACTION_QUERY_FIELD_AMOUNT:
+		amount, err := utils.ParseAmount(term.Value)
+
+		if err != nil {
+			return newTransactionQuerySyntaxError(valuePosition, fmt.Sprintf("invalid amount \"%s\"", term.Value))
+		}
+
+		term.AmountValue = amount
+	case TRANSACTION_QUERY_FIELD_TYPE:
+		transactionType, exists := transactionQueryTypeNames[strings.ToLower(term.Value)]
+
+		if !exists {
+			return newTransactionQuerySyntaxError(valuePosition, fmt.Sprintf("invalid transaction type \"%s\"", term.Value))
+		}
+
+		term.TypeValue = transactionType
+	case TRANSACTION_QUERY_FIELD_AFTER, TRANSACTION_QUERY_FIELD_BEFORE:
+		date, err := time.Parse("2006-01-02", term.Value)
+
+		if err != nil {
+			return newTransactionQuerySyntaxError(valuePosition, fmt.Sprintf("invalid date \"%s\"", term.Value))
+		}
+
+		term.TimeValue = utils.GetMinTransactionTimeFromUnixTime(date.Unix() - int64(p.utcOffset)*60)
+	}
+
+	return nil
+}
+
+func (p *transactionQueryParser) readOperator() TransactionQueryOperator {
+	if p.offset >= len(p.runes) {
+		return ""
+	}
+
+	ch := p.runes[p.offset]
+	var nextCh rune
+
+	if p.offset+1 < len(p.runes) {
+		nextCh = p.runes[p.offset+1]
+	}
+
+	if (ch == '>' || ch == '<' || ch == '!') && nextCh == '=' {
+		p.offset += 2
+		return TransactionQueryOperator(string([]rune{ch, nextCh}))
+	} else if ch == ':' || ch == '=' || ch == '>' || ch == '<' {
+		p.offset++
+		return TransactionQueryOperator(string(ch))
+	}
+
+	return ""
+}
+
+func (p *transactionQueryParser) readWord() string {
+	start := p.offset
+
+	for p.offset < len(p.runes) && !unicode.IsSpace(p.runes[p.offset]) && p.runes[p.offset] != '(' && p.runes[p.offset] != ')' {
+		p.offset++
+	}
+
+	return string(p.runes[start:p.offset])
+}
+
+func (p *transactionQueryParser) readQuotedString() (string, error) {
+	position := p.offset + 1
+	p.offset++
+
+	var value strings.Builder
+
+	for p.offset < len(p.runes) {
+		ch := p.runes[p.offset]
+
+		if ch == '\\' && p.offset+1 < len(p.runes) && (p.runes[p.offset+1] == '"' || p.runes[p.offset+1] == '\\') {
+			value.WriteRune(p.runes[p.offset+1])
+			p.offset += 2
+			continue
+		}
+
+		p.offset++
+
+		if ch == '"' {
+			return value.String(), nil
+		}
+
+		value.WriteRune(ch)
+	}
+
+	return "", newTransactionQuerySyntaxError(position, "unterminated quoted string")
+}
+
+func isTransactionQueryOperatorAllowed(allowedOperators []TransactionQueryOperator, operator TransactionQueryOperator) bool {
+	for i := 0; i < len(allowedOperators); i++ {
+		if allowedOperators[i] == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newTransactionQuerySyntaxError(position int, reason string) error {
+	return errs.NewErrorWithContext(errs.ErrTransactionQuerySyntaxInvalid, &TransactionQuerySyntaxErrorContext{
+		Position: position,
+		Reason:   reason,
+	})
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestParseTransactionQuery_EmptyQuery(t *testing.T) {
+	expression, err := ParseTransactionQuery("", 0)
+	assert.Nil(t, err)
+	assert.Nil(t, expression)
+
+	expression, err = ParseTransactionQuery("   ", 0)
+	assert.Nil(t, err)
+	assert.Nil(t, expression)
+}
+
+func TestParseTransactionQuery_ImplicitAnd(t *testing.T) {
+	expression, err := ParseTransactionQuery("category:Food tag:trip amount>50 -comment:refund after:2025-01-01 account:\"Visa Card\"", 480)
+	assert.Nil(t, err)
+	assert.Equal(t, `(category:"Food" AND tag:"trip" AND amount>"50" AND NOT comment:"refund" AND after:"2025-01-01" AND account:"Visa Card")`, expression.String())
+
+	assert.Equal(t, TRANSACTION_QUERY_NODE_TYPE_AND, expression.NodeType)
+	assert.Equal(t, 6, len(expression.Children))
+
+	assert.Equal(t, TRANSACTION_QUERY_FIELD_AMOUNT, expression.Children[2].Field)
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_GREATER_THAN, expression.Children[2].Operator)
+	assert.Equal(t, int64(5000), expression.Children[2].AmountValue)
+
+	assert.Equal(t, TRANSACTION_QUERY_NODE_TYPE_NOT, expression.Children[3].NodeType)
+	assert.Equal(t, 34, expression.Children[3].Position)
+
+	assert.Equal(t, utils.GetMinTransactionTimeFromUnixTime(1735660800), expression.Children[4].TimeValue)
+	assert.Equal(t, "Visa Card", expression.Children[5].Value)
+}
+
+func TestParseTransactionQuery_LogicalOperators(t *testing.T) {
+	expression, err := ParseTransactionQuery("coffee OR tea AND NOT type:income", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, `("coffee" OR ("tea" AND NOT type:"income"))`, expression.String())
+	assert.Equal(t, TRANSACTION_TYPE_INCOME, expression.Children[1].Children[1].Children[0].TypeValue)
+
+	expression, err = ParseTransactionQuery("(coffee OR tea) AND -(amount<=10 OR amount>=100)", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, `(("coffee" OR "tea") AND NOT (amount<="10" OR amount>="100"))`, expression.String())
+
+	expression, err = ParseTransactionQuery("\"and\" or \"a \\\"quoted\\\" text\"", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, `("and" AND "or" AND "a \"quoted\" text")`, expression.String())
+}
+
+func TestParseTransactionQuery_AmountOperators(t *testing.T) {
+	expression, err := ParseTransactionQuery("amount:1.5 amount=2 amount!=3 amount<4 amount>=-5.25", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(expression.Children))
+
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_MATCH, expression.Children[0].Operator)
+	assert.Equal(t, int64(150), expression.Children[0].AmountValue)
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_EQUAL, expression.Children[1].Operator)
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_NOT_EQUAL, expression.Children[2].Operator)
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_LESS_THAN, expression.Children[3].Operator)
+	assert.Equal(t, TRANSACTION_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL, expression.Children[4].Operator)
+	assert.Equal(t, int64(-525), expression.Children[4].AmountValue)
+}
+
+func TestParseTransactionQuery_SyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		query    string
+		position int
+		reason   string
+	}{
+		{"foo:bar", 1, "unknown field \"foo\""},
+		{"category>Food", 9, "operator \">\" is not supported for field \"category\""},
+		{"tag: trip", 5, "missing value for field \"tag\""},
+		{"amount>abc", 8, "invalid amount \"abc\""},
+		{"after:2025-13-01", 7, "invalid date \"2025-13-01\""},
+		{"type:refund", 6, "invalid transaction type \"refund\""},
+		{"comment:\"refund", 9, "unterminated quoted string"},
+		{"(coffee OR tea", 1, "missing closing parenthesis"},
+		{"coffee)", 7, "unexpected \")\""},
+		{"coffee OR", 10, "unexpected end of query"},
+		{"AND coffee", 1, "unexpected \"AND\""},
+		{"coffee OR OR tea", 11, "unexpected \"OR\""},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		testCase := testCases[i]
+		expression, err := ParseTransactionQuery(testCase.query, 0)
+		assert.Nil(t, expression, testCase.query)
+		assert.NotNil(t, err, testCase.query)
+
+		customErr, ok := err.(*errs.Error)
+		assert.True(t, ok, testCase.query)
+		assert.Equal(t, errs.ErrTransactionQuerySyntaxInvalid.Code(), customErr.Code(), testCase.query)
+
+		errorContext, ok := customErr.Context.(*TransactionQuerySyntaxErrorContext)
+		assert.True(t, ok, testCase.query)
+		assert.Equal(t, testCase.position, errorContext.Position, testCase.query)
+		assert.Equal(t, testCase.reason, errorContext.Reason, testCase.query)
+	}
+}
+
+func TestParseTransactionQuery_TooManyTerms(t *testing.T) {
+	query := ""
+
+	for i := 0; i <= MaximumTermCountOfTransactionQuery; i++ {
+		query += "a "
+	}
+
+	_, err := ParseTransactionQuery(query, 0)
+	assert.NotNil(t, err)
+	assert.Equal(t, "too many terms", err.(*errs.Error).Context.(*TransactionQuerySyntaxErrorContext).Reason)
+}
+
+func TestTransactionQueryExpressionGetTermsByField(t *testing.T) {
+	expression, err := ParseTransactionQuery("category:Food OR (tag:trip -category:Drinks)", 0)
+	assert.Nil(t, err)
+
+	categoryTerms := expression.GetTermsByField(TRANSACTION_QUERY_FIELD_CATEGORY)
+	assert.Equal(t, 2, len(categoryTerms))
+	assert.Equal(t, "Food", categoryTerms[0].Value)
+	assert.Equal(t, "Drinks", categoryTerms[1].Value)
+
+	tagTerms := expression.GetTermsByField(TRANSACTION_QUERY_FIELD_TAG)
+	assert.Equal(t, 1, len(tagTerms))
+
+	var nilExpression *TransactionQueryExpression
+	assert.Equal(t, 0, len(nilExpression.GetTermsByField(TRANSACTION_QUERY_FIELD_TAG)))
+}
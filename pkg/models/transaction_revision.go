@@ -0,0 +1,212 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var transactionRevisionSnapshotFields = []string{"CategoryId", "TransactionTime", "TimezoneUtcOffset", "AccountId", "PayeeId", "Amount", "RelatedAccountId", "RelatedAccountAmount", "HideAmount", "Comment", "GeoLongitude", "GeoLatitude", "TagIds", "PictureIds", "Members", "Splits"}
+
+// TransactionRevision represents the state of a transaction before one modification stored in database,
+// revisions are append-only and numbered from 1 for each transaction
+type TransactionRevision struct {
+	TransactionId   int64  `xorm:"PK"`
+	Revision        int32  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_revision_uid) NOT NULL"`
+	FundId          int64  `xorm:"NOT NULL DEFAULT 0"`
+	Snapshot        string `xorm:"TEXT"` // JSON object of transaction revision snapshot
+	CreatedUnixTime int64
+}
+
+// TransactionRevisionSnapshot represents a full snapshot of a transaction including its tags, pictures, members and line items
+type TransactionRevisionSnapshot struct {
+	CategoryId           int64                                `json:"categoryId,string"`
+	TransactionTime      int64                                `json:"time"` // Unix time
+	TimezoneUtcOffset    int16                                `json:"utcOffset"`
+	AccountId            int64                                `json:"sourceAccountId,string"`
+	PayeeId              int64                                `json:"payeeId,string"`
+	Amount               int64                                `json:"sourceAmount"`
+	RelatedAccountId     int64                                `json:"destinationAccountId,string"`
+	RelatedAccountAmount int64                                `json:"destinationAmount"`
+	HideAmount           bool                                 `json:"hideAmount"`
+	Comment              string                               `json:"comment"`
+	GeoLongitude         float64                              `json:"geoLongitude"`
+	GeoLatitude          float64                              `json:"geoLatitude"`
+	TagIds               []string                             `json:"tagIds"`
+	PictureIds           []string                             `json:"pictureIds"`
+	Members              []*TransactionRevisionSnapshotMember `json:"members"`
+	Splits               []*TransactionSplitInfoResponse      `json:"splits"`
+}
+
+// TransactionRevisionSnapshotMember represents a member linked to transaction in transaction revision snapshot
+type TransactionRevisionSnapshotMember struct {
+	MemberId   int64                      `json:"memberId,string"`
+	SplitType  TransactionMemberSplitType `json:"splitType"`
+	SplitValue int64                      `json:"splitValue"`
+}
+
+// TransactionRevisionListRequest represents all parameters of transaction revision list request
+type TransactionRevisionListRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRevisionRestoreRequest represents all parameters of transaction revision restore request
+type TransactionRevisionRestoreRequest struct {
+	Id               int64 `json:"id,string" binding:"required,min=1"`
+	Revision         int32 `json:"revision" binding:"required,min=1"`
+	UnlockReconciled bool  `json:"unlockReconciled"`
+}
+
+// TransactionRevisionInfoResponse represents a view-object of transaction revision
+type TransactionRevisionInfoResponse struct {
+	TransactionId int64                               `json:"transactionId,string"`
+	Revision      int32                               `json:"revision"`
+	Snapshot      *TransactionRevisionSnapshot        `json:"snapshot"`
+	Changes       map[string]*FundActivityFieldChange `json:"changes"`
+	CreatedAt     int64                               `json:"createdAt"`
+}
+
+// NewTransactionRevisionSnapshot returns a snapshot of the specified transaction with its tag ids, picture ids,
+// linked members and line items
+func NewTransactionRevisionSnapshot(transaction *Transaction, tagIds []int64, pictureIds []int64, members []*TransactionMember, splits []*TransactionSplit) *TransactionRevisionSnapshot {
+	snapshot := &TransactionRevisionSnapshot{
+		CategoryId:           transaction.CategoryId,
+		TransactionTime:      utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime),
+		TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+		AccountId:            transaction.AccountId,
+		PayeeId:              transaction.PayeeId,
+		Amount:               transaction.Amount,
+		RelatedAccountId:     transaction.RelatedAccountId,
+		RelatedAccountAmount: transaction.RelatedAccountAmount,
+		HideAmount:           transaction.HideAmount,
+		Comment:              transaction.Comment,
+		GeoLongitude:         transaction.GeoLongitude,
+		GeoLatitude:          transaction.GeoLatitude,
+		TagIds:               utils.Int64ArrayToStringArray(tagIds),
+		PictureIds:           utils.Int64ArrayToStringArray(pictureIds),
+		Members:              make([]*TransactionRevisionSnapshotMember, 0, len(members)),
+		Splits:               ToTransactionSplitInfoResponses(splits),
+	}
+
+	for _, member := range members {
+		snapshot.Members = append(snapshot.Members, &TransactionRevisionSnapshotMember{
+			MemberId:   member.MemberId,
+			SplitType:  member.SplitType,
+			SplitValue: member.SplitValue,
+		})
+	}
+
+	return snapshot
+}
+
+// NewTransactionRevision returns a new transaction revision model which contains the specified snapshot
+func NewTransactionRevision(transactionId int64, uid int64, fundId int64, snapshot *TransactionRevisionSnapshot) (*TransactionRevision, error) {
+	snapshotJson, err := json.Marshal(snapshot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionRevision{
+		TransactionId: transactionId,
+		Uid:           uid,
+		FundId:        fundId,
+		Snapshot:      string(snapshotJson),
+	}, nil
+}
+
+// GetSnapshot returns the snapshot of transaction stored in the revision
+func (r *TransactionRevision) GetSnapshot() (*TransactionRevisionSnapshot, error) {
+	snapshot := &TransactionRevisionSnapshot{}
+	err := json.Unmarshal([]byte(r.Snapshot), snapshot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// ToTransactionRevisionInfoResponse returns a view-object according to database model, next is the snapshot of
+// transaction after the modification, which is the snapshot of the next revision or the current state of transaction
+func (r *TransactionRevision) ToTransactionRevisionInfoResponse(snapshot *TransactionRevisionSnapshot, next *TransactionRevisionSnapshot) *TransactionRevisionInfoResponse {
+	return &TransactionRevisionInfoResponse{
+		TransactionId: r.TransactionId,
+		Revision:      r.Revision,
+		Snapshot:      snapshot,
+		Changes:       GetTransactionRevisionChanges(snapshot, next),
+		CreatedAt:     r.CreatedUnixTime,
+	}
+}
+
+// ToTransactionModifyRequest returns a transaction modification request which restores the transaction to the snapshot,
+// the pictures which are no longer in available picture ids cannot be restored
+func (s *TransactionRevisionSnapshot) ToTransactionModifyRequest(transactionId int64, availablePictureIds []int64) *TransactionModifyRequest {
+	modifyReq := &TransactionModifyRequest{
+		Id:                   transactionId,
+		CategoryId:           s.CategoryId,
+		Time:                 s.TransactionTime,
+		UtcOffset:            s.TimezoneUtcOffset,
+		SourceAccountId:      s.AccountId,
+		PayeeId:              s.PayeeId,
+		DestinationAccountId: s.RelatedAccountId,
+		SourceAmount:         s.Amount,
+		DestinationAmount:    s.RelatedAccountAmount,
+		HideAmount:           s.HideAmount,
+		TagIds:               s.TagIds,
+		PictureIds:           make([]string, 0, len(s.PictureIds)),
+		Splits:               make([]*TransactionSplitRequest, 0, len(s.Splits)),
+		Comment:              s.Comment,
+	}
+
+	availablePictureIdsMap := make(map[string]bool, len(availablePictureIds))
+
+	for _, pictureId := range utils.Int64ArrayToStringArray(availablePictureIds) {
+		availablePictureIdsMap[pictureId] = true
+	}
+
+	for _, pictureId := range s.PictureIds {
+		if availablePictureIdsMap[pictureId] {
+			modifyReq.PictureIds = append(modifyReq.PictureIds, pictureId)
+		}
+	}
+
+	// Members are always specified, so the members linked after the revision are removed if there were no members in the revision
+	modifyReq.Members = make([]*TransactionMemberSplitRequest, len(s.Members))
+
+	if len(s.Members) > 0 {
+		modifyReq.MemberSplitType = s.Members[0].SplitType
+	}
+
+	for i, member := range s.Members {
+		modifyReq.Members[i] = &TransactionMemberSplitRequest{
+			MemberId:   member.MemberId,
+			SplitValue: member.SplitValue,
+		}
+	}
+
+	for _, split := range s.Splits {
+		modifyReq.Splits = append(modifyReq.Splits, &TransactionSplitRequest{
+			CategoryId: split.CategoryId,
+			Amount:     split.Amount,
+			TagIds:     split.TagIds,
+			Comment:    split.Comment,
+		})
+	}
+
+	if s.GeoLongitude != 0 || s.GeoLatitude != 0 {
+		modifyReq.GeoLocation = &TransactionGeoLocationRequest{
+			Longitude: s.GeoLongitude,
+			Latitude:  s.GeoLatitude,
+		}
+	}
+
+	return modifyReq
+}
+
+// GetTransactionRevisionChanges returns the fields of transaction whose values are different between two snapshots,
+// the keys of result are field names in lower camel case
+func GetTransactionRevisionChanges(before *TransactionRevisionSnapshot, after *TransactionRevisionSnapshot) map[string]*FundActivityFieldChange {
+	return GetFundActivityChanges(before, after, transactionRevisionSnapshotFields)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransactionRevisionSnapshot(t *testing.T) {
+	transaction := &Transaction{
+		CategoryId:        1001,
+		TransactionTime:   1700000000123,
+		TimezoneUtcOffset: 480,
+		AccountId:         2001,
+		Amount:            1000,
+		Comment:           "comment",
+	}
+	members := []*TransactionMember{
+		{TransactionId: 3001, MemberId: 4001, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, SplitValue: 2},
+	}
+	splits := []*TransactionSplit{
+		{LineIndex: 1, CategoryId: 1003, Amount: 400},
+		{LineIndex: 0, CategoryId: 1002, Amount: 600, TagIds: "5001"},
+	}
+
+	snapshot := NewTransactionRevisionSnapshot(transaction, []int64{5001, 5002}, nil, members, splits)
+	assert.Equal(t, int64(1001), snapshot.CategoryId)
+	assert.Equal(t, int64(1700000000), snapshot.TransactionTime)
+	assert.Equal(t, int16(480), snapshot.TimezoneUtcOffset)
+	assert.Equal(t, int64(1000), snapshot.Amount)
+	assert.Equal(t, []string{"5001", "5002"}, snapshot.TagIds)
+	assert.Equal(t, []string{}, snapshot.PictureIds)
+	assert.Equal(t, 1, len(snapshot.Members))
+	assert.Equal(t, int64(4001), snapshot.Members[0].MemberId)
+	assert.Equal(t, TRANSACTION_MEMBER_SPLIT_TYPE_SHARES, snapshot.Members[0].SplitType)
+	assert.Equal(t, 2, len(snapshot.Splits))
+	assert.Equal(t, int64(1002), snapshot.Splits[0].CategoryId)
+	assert.Equal(t, []string{"5001"}, snapshot.Splits[0].TagIds)
+}
+
+func TestTransactionRevisionGetSnapshot(t *testing.T) {
+	transaction := &Transaction{CategoryId: 1001, AccountId: 2001, Amount: 1000}
+	snapshot := NewTransactionRevisionSnapshot(transaction, []int64{5001}, []int64{6001}, nil, nil)
+
+	revision, err := NewTransactionRevision(3001, 1, 7001, snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3001), revision.TransactionId)
+	assert.Equal(t, int64(7001), revision.FundId)
+
+	actualSnapshot, err := revision.GetSnapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, snapshot, actualSnapshot)
+	assert.Equal(t, 0, len(GetTransactionRevisionChanges(snapshot, actualSnapshot)))
+
+	revision.Snapshot = "invalid"
+	_, err = revision.GetSnapshot()
+	assert.NotNil(t, err)
+}
+
+func TestGetTransactionRevisionChanges(t *testing.T) {
+	before := NewTransactionRevisionSnapshot(&Transaction{CategoryId: 1001, AccountId: 2001, Amount: 1000, Comment: "before"}, []int64{5001}, nil, nil, nil)
+	after := NewTransactionRevisionSnapshot(&Transaction{CategoryId: 1001, AccountId: 2001, Amount: 1200, Comment: "after"}, []int64{5001, 5002}, nil, nil, nil)
+
+	changes := GetTransactionRevisionChanges(before, after)
+	assert.Equal(t, 3, len(changes))
+	assert.Equal(t, int64(1000), changes["amount"].Before)
+	assert.Equal(t, int64(1200), changes["amount"].After)
+	assert.Equal(t, "before", changes["comment"].Before)
+	assert.Equal(t, "after", changes["comment"].After)
+	assert.Equal(t, []string{"5001"}, changes["tagIds"].Before)
+	assert.Equal(t, []string{"5001", "5002"}, changes["tagIds"].After)
+}
+
+func TestTransactionRevisionSnapshotToTransactionModifyRequest(t *testing.T) {
+	transaction := &Transaction{
+		CategoryId:      1001,
+		TransactionTime: 1700000000000,
+		AccountId:       2001,
+		Amount:          1000,
+		GeoLongitude:    120.5,
+		GeoLatitude:     30.5,
+	}
+	members := []*TransactionMember{
+		{MemberId: 4001, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: 300},
+		{MemberId: 4002, SplitType: TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, SplitValue: 700},
+	}
+
+	snapshot := NewTransactionRevisionSnapshot(transaction, []int64{5001}, []int64{6001, 6002}, members, nil)
+	modifyReq := snapshot.ToTransactionModifyRequest(3001, []int64{6002, 6003})
+
+	assert.Equal(t, int64(3001), modifyReq.Id)
+	assert.Equal(t, int64(1001), modifyReq.CategoryId)
+	assert.Equal(t, int64(1700000000), modifyReq.Time)
+	assert.Equal(t, int64(2001), modifyReq.SourceAccountId)
+	assert.Equal(t, int64(1000), modifyReq.SourceAmount)
+	assert.Equal(t, []string{"5001"}, modifyReq.TagIds)
+	assert.Equal(t, []string{"6002"}, modifyReq.PictureIds)
+	assert.Equal(t, TRANSACTION_MEMBER_SPLIT_TYPE_EXACT, modifyReq.MemberSplitType)
+	assert.Equal(t, 2, len(modifyReq.Members))
+	assert.Equal(t, int64(700), modifyReq.Members[1].SplitValue)
+	assert.NotNil(t, modifyReq.Splits)
+	assert.Equal(t, 0, len(modifyReq.Splits))
+	assert.Equal(t, 120.5, modifyReq.GeoLocation.Longitude)
+	assert.Equal(t, 30.5, modifyReq.GeoLocation.Latitude)
+
+	snapshot = NewTransactionRevisionSnapshot(&Transaction{CategoryId: 1001, AccountId: 2001}, nil, nil, nil, nil)
+	modifyReq = snapshot.ToTransactionModifyRequest(3001, nil)
+	assert.NotNil(t, modifyReq.Members)
+	assert.Equal(t, 0, len(modifyReq.Members))
+	assert.Nil(t, modifyReq.GeoLocation)
+}
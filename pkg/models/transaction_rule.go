@@ -0,0 +1,481 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRuleScope represents whose transactions a transaction rule is applied to
+type TransactionRuleScope byte
+
+// Transaction rule scopes
+const (
+	TRANSACTION_RULE_SCOPE_PERSONAL TransactionRuleScope = 1 // Only visible to the creator and applied to the transactions of the creator
+	TRANSACTION_RULE_SCOPE_FUND     TransactionRuleScope = 2 // Visible to all fund members and applied to the transactions of all fund members
+)
+
+// String returns a textual representation of the transaction rule scope enum
+func (s TransactionRuleScope) String() string {
+	switch s {
+	case TRANSACTION_RULE_SCOPE_PERSONAL:
+		return "Personal"
+	case TRANSACTION_RULE_SCOPE_FUND:
+		return "Fund"
+	default:
+		return "Unknown"
+	}
+}
+
+// TransactionRule represents an auto-categorization rule stored in database, all the non-empty conditions of
+// a rule must be matched, and the actions of all matched rules are applied in display order
+type TransactionRule struct {
+	RuleId                int64                `xorm:"PK"`
+	Uid                   int64                `xorm:"INDEX(IDX_transaction_rule_fund_id_deleted_uid) NOT NULL"`
+	FundId                int64                `xorm:"INDEX(IDX_transaction_rule_fund_id_deleted_uid) NOT NULL"`
+	Deleted               bool                 `xorm:"INDEX(IDX_transaction_rule_fund_id_deleted_uid) NOT NULL"`
+	Scope                 TransactionRuleScope `xorm:"TINYINT NOT NULL"`
+	Name                  string               `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder          int32                `xorm:"NOT NULL"`
+	Disabled              bool                 `xorm:"NOT NULL"`
+	TransactionType       TransactionType      `xorm:"TINYINT NOT NULL"` // 0 means transactions of all types
+	CommentPattern        string               `xorm:"VARCHAR(255) NOT NULL"`
+	MinAmount             int64                `xorm:"NOT NULL"` // 0 means no lower bound
+	MaxAmount             int64                `xorm:"NOT NULL"` // 0 means no upper bound
+	AccountId             int64                `xorm:"NOT NULL"`
+	CounterpartyAccountId int64                `xorm:"NOT NULL"` // The destination account of transfer transaction
+	SetCategoryId         int64                `xorm:"NOT NULL"`
+	AddTagIds             string               `xorm:"VARCHAR(255) NOT NULL"`
+	SetMemberIds          string               `xorm:"VARCHAR(255) NOT NULL"`
+	SetComment            string               `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime       int64
+	UpdatedUnixTime       int64
+	DeletedUnixTime       int64
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	// FundId will be retrieved from URL context parameter
+	Scope                 TransactionRuleScope `json:"scope" binding:"required,min=1,max=2"`
+	Name                  string               `json:"name" binding:"required,notBlank,max=64"`
+	Disabled              bool                 `json:"disabled"`
+	TransactionType       TransactionType      `json:"transactionType" binding:"min=0,max=4"`
+	CommentPattern        string               `json:"commentPattern" binding:"max=255"`
+	MinAmount             int64                `json:"minAmount" binding:"min=0,max=99999999999"`
+	MaxAmount             int64                `json:"maxAmount" binding:"min=0,max=99999999999"`
+	AccountId             int64                `json:"accountId,string" binding:"min=0"`
+	CounterpartyAccountId int64                `json:"counterpartyAccountId,string" binding:"min=0"`
+	SetCategoryId         int64                `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds             []string             `json:"addTagIds"`
+	SetMemberIds          []string             `json:"setMemberIds"`
+	SetComment            string               `json:"setComment" binding:"max=255"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id                    int64           `json:"id,string" binding:"required,min=1"`
+	Name                  string          `json:"name" binding:"required,notBlank,max=64"`
+	Disabled              bool            `json:"disabled"`
+	TransactionType       TransactionType `json:"transactionType" binding:"min=0,max=4"`
+	CommentPattern        string          `json:"commentPattern" binding:"max=255"`
+	MinAmount             int64           `json:"minAmount" binding:"min=0,max=99999999999"`
+	MaxAmount             int64           `json:"maxAmount" binding:"min=0,max=99999999999"`
+	AccountId             int64           `json:"accountId,string" binding:"min=0"`
+	CounterpartyAccountId int64           `json:"counterpartyAccountId,string" binding:"min=0"`
+	SetCategoryId         int64           `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds             []string        `json:"addTagIds"`
+	SetMemberIds          []string        `json:"setMemberIds"`
+	SetComment            string          `json:"setComment" binding:"max=255"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleTestRequest represents all parameters of testing transaction rule against existing transactions request
+type TransactionRuleTestRequest struct {
+	Id      int64 `form:"id,string" binding:"required,min=1"`
+	MaxTime int64 `form:"max_time" binding:"min=0"` // Transaction time sequence id
+	MinTime int64 `form:"min_time" binding:"min=0"` // Transaction time sequence id
+	Count   int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// TransactionRuleApplyRequest represents all parameters of applying transaction rule to existing transactions request
+type TransactionRuleApplyRequest struct {
+	Id      int64 `json:"id,string" binding:"required,min=1"`
+	MaxTime int64 `json:"maxTime" binding:"min=0"` // Transaction time sequence id
+	MinTime int64 `json:"minTime" binding:"min=0"` // Transaction time sequence id
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id                    int64                `json:"id,string"`
+	Scope                 TransactionRuleScope `json:"scope"`
+	Name                  string               `json:"name"`
+	DisplayOrder          int32                `json:"displayOrder"`
+	Disabled              bool                 `json:"disabled"`
+	TransactionType       TransactionType      `json:"transactionType"`
+	CommentPattern        string               `json:"commentPattern"`
+	MinAmount             int64                `json:"minAmount"`
+	MaxAmount             int64                `json:"maxAmount"`
+	AccountId             int64                `json:"accountId,string"`
+	CounterpartyAccountId int64                `json:"counterpartyAccountId,string"`
+	SetCategoryId         int64                `json:"setCategoryId,string"`
+	AddTagIds             []string             `json:"addTagIds"`
+	SetMemberIds          []string             `json:"setMemberIds"`
+	SetComment            string               `json:"setComment"`
+	CreatorUid            int64                `json:"creatorUid,string"`
+}
+
+// TransactionRuleTestResponse represents the existing transactions which are matched by a transaction rule
+type TransactionRuleTestResponse struct {
+	Items        []*TransactionRuleMatchedTransactionResponse `json:"items"`
+	MatchedCount int64                                        `json:"matchedCount"`
+}
+
+// TransactionRuleMatchedTransactionResponse represents a view-object of an existing transaction matched by transaction rule
+// and the values after the rule is applied
+type TransactionRuleMatchedTransactionResponse struct {
+	Id            int64           `json:"id,string"`
+	Time          int64           `json:"time"`
+	Type          TransactionType `json:"type"`
+	AccountId     int64           `json:"sourceAccountId,string"`
+	Amount        int64           `json:"sourceAmount"`
+	CategoryId    int64           `json:"categoryId,string"`
+	Comment       string          `json:"comment"`
+	NewCategoryId int64           `json:"newCategoryId,string"`
+	NewComment    string          `json:"newComment"`
+	AddTagIds     []string        `json:"addTagIds"`
+	SetMemberIds  []string        `json:"setMemberIds"`
+}
+
+// TransactionRuleApplyResponse represents the result of applying transaction rule to existing transactions
+type TransactionRuleApplyResponse struct {
+	MatchedCount  int `json:"matchedCount"`
+	ModifiedCount int `json:"modifiedCount"`
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	if s[i].DisplayOrder != s[j].DisplayOrder {
+		return s[i].DisplayOrder < s[j].DisplayOrder
+	}
+
+	return s[i].Id < s[j].Id
+}
+
+// GetAddTagIds returns the ids of tags which are added to the matched transactions
+func (r *TransactionRule) GetAddTagIds() []int64 {
+	return getTransactionRuleIds(r.AddTagIds)
+}
+
+// GetSetMemberIds returns the ids of members which are linked to the matched transactions
+func (r *TransactionRule) GetSetMemberIds() []int64 {
+	return getTransactionRuleIds(r.SetMemberIds)
+}
+
+// HasAction returns whether the transaction rule changes anything of the matched transactions
+func (r *TransactionRule) HasAction() bool {
+	return r.SetCategoryId > 0 || r.AddTagIds != "" || r.SetMemberIds != "" || r.SetComment != ""
+}
+
+// Validate checks whether the conditions and the actions of transaction rule are valid, the category type of
+// the category set by rule is checked separately because it is not stored in the rule
+func (r *TransactionRule) Validate() error {
+	if r.Scope != TRANSACTION_RULE_SCOPE_PERSONAL && r.Scope != TRANSACTION_RULE_SCOPE_FUND {
+		return errs.ErrTransactionRuleScopeInvalid
+	}
+
+	if r.TransactionType == TRANSACTION_TYPE_MODIFY_BALANCE || r.TransactionType > TRANSACTION_TYPE_TRANSFER {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	if r.CommentPattern != "" {
+		if _, err := regexp.Compile(r.CommentPattern); err != nil {
+			return errs.ErrTransactionRuleCommentPatternInvalid
+		}
+	}
+
+	if r.MinAmount < 0 || r.MaxAmount < 0 || (r.MaxAmount > 0 && r.MinAmount > r.MaxAmount) {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	if r.CounterpartyAccountId > 0 && r.TransactionType != TRANSACTION_TYPE_TRANSFER {
+		return errs.ErrTransactionRuleCounterpartyRequiresTransfer
+	}
+
+	if r.SetCategoryId > 0 && r.TransactionType == 0 {
+		return errs.ErrTransactionRuleCategoryRequiresTransactionType
+	}
+
+	if len(r.GetAddTagIds()) > MaximumTagsCountOfTransaction {
+		return errs.ErrTransactionHasTooManyTags
+	}
+
+	if !r.HasAction() {
+		return errs.ErrTransactionRuleHasNoAction
+	}
+
+	return nil
+}
+
+// GetCategoryType returns the category type which the category set by transaction rule should be
+func (r *TransactionRule) GetCategoryType() (TransactionCategoryType, error) {
+	switch r.TransactionType {
+	case TRANSACTION_TYPE_INCOME:
+		return CATEGORY_TYPE_INCOME, nil
+	case TRANSACTION_TYPE_EXPENSE:
+		return CATEGORY_TYPE_EXPENSE, nil
+	case TRANSACTION_TYPE_TRANSFER:
+		return CATEGORY_TYPE_TRANSFER, nil
+	default:
+		return 0, errs.ErrTransactionRuleCategoryRequiresTransactionType
+	}
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	return &TransactionRuleInfoResponse{
+		Id:                    r.RuleId,
+		Scope:                 r.Scope,
+		Name:                  r.Name,
+		DisplayOrder:          r.DisplayOrder,
+		Disabled:              r.Disabled,
+		TransactionType:       r.TransactionType,
+		CommentPattern:        r.CommentPattern,
+		MinAmount:             r.MinAmount,
+		MaxAmount:             r.MaxAmount,
+		AccountId:             r.AccountId,
+		CounterpartyAccountId: r.CounterpartyAccountId,
+		SetCategoryId:         r.SetCategoryId,
+		AddTagIds:             utils.Int64ArrayToStringArray(r.GetAddTagIds()),
+		SetMemberIds:          utils.Int64ArrayToStringArray(r.GetSetMemberIds()),
+		SetComment:            r.SetComment,
+		CreatorUid:            r.Uid,
+	}
+}
+
+// TransactionRuleResult represents the changes which are made to a transaction by all matched transaction rules
+type TransactionRuleResult struct {
+	MatchedRuleIds []int64
+	CategoryId     int64 // 0 means the category is not changed
+	AddTagIds      []int64
+	MemberIds      []int64 // Empty means the linked members are not changed
+	Comment        *string // Nil means the comment is not changed
+}
+
+// IsMatched returns whether any transaction rule is matched
+func (r *TransactionRuleResult) IsMatched() bool {
+	return len(r.MatchedRuleIds) > 0
+}
+
+// ApplyTo sets the changed category and comment to the specified transaction, and returns the tag ids after
+// adding the tags of matched rules, the tags exceeding the maximum count of transaction are ignored
+func (r *TransactionRuleResult) ApplyTo(transaction *Transaction, tagIds []int64) []int64 {
+	if r.CategoryId > 0 {
+		transaction.CategoryId = r.CategoryId
+	}
+
+	if r.Comment != nil {
+		transaction.Comment = *r.Comment
+	}
+
+	return r.appendTagIds(tagIds)
+}
+
+// FillTo sets the changed category and comment to the specified transaction only when the transaction has no
+// category or comment, and returns the tag ids after adding the tags of matched rules
+func (r *TransactionRuleResult) FillTo(transaction *Transaction, tagIds []int64) []int64 {
+	if r.CategoryId > 0 && transaction.CategoryId <= 0 {
+		transaction.CategoryId = r.CategoryId
+	}
+
+	if r.Comment != nil && transaction.Comment == "" {
+		transaction.Comment = *r.Comment
+	}
+
+	return r.appendTagIds(tagIds)
+}
+
+func (r *TransactionRuleResult) appendTagIds(tagIds []int64) []int64 {
+	newTagIds := utils.ToUniqueInt64Slice(tagIds)
+
+	for _, tagId := range utils.Int64SliceMinus(r.AddTagIds, newTagIds) {
+		if len(newTagIds) >= MaximumTagsCountOfTransaction {
+			break
+		}
+
+		newTagIds = append(newTagIds, tagId)
+	}
+
+	return newTagIds
+}
+
+// TransactionRuleEngine evaluates a list of transaction rules against transactions
+type TransactionRuleEngine struct {
+	rules           []*TransactionRule
+	commentPatterns []*regexp.Regexp
+}
+
+// NewTransactionRuleEngine returns a new transaction rule engine which contains the enabled rules in the specified
+// rules ordered by display order, the rules whose comment pattern is invalid are ignored
+func NewTransactionRuleEngine(rules []*TransactionRule) *TransactionRuleEngine {
+	engine := &TransactionRuleEngine{
+		rules:           make([]*TransactionRule, 0, len(rules)),
+		commentPatterns: make([]*regexp.Regexp, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+		if rule.Disabled {
+			continue
+		}
+
+		var commentPattern *regexp.Regexp
+
+		if rule.CommentPattern != "" {
+			pattern, err := regexp.Compile(rule.CommentPattern)
+
+			if err != nil {
+				continue
+			}
+
+			commentPattern = pattern
+		}
+
+		engine.rules = append(engine.rules, rule)
+		engine.commentPatterns = append(engine.commentPatterns, commentPattern)
+	}
+
+	for i := 1; i < len(engine.rules); i++ {
+		for j := i; j > 0 && isTransactionRuleBefore(engine.rules[j], engine.rules[j-1]); j-- {
+			engine.rules[j], engine.rules[j-1] = engine.rules[j-1], engine.rules[j]
+			engine.commentPatterns[j], engine.commentPatterns[j-1] = engine.commentPatterns[j-1], engine.commentPatterns[j]
+		}
+	}
+
+	return engine
+}
+
+// IsEmpty returns whether the engine contains no rule
+func (e *TransactionRuleEngine) IsEmpty() bool {
+	return len(e.rules) < 1
+}
+
+// Evaluate returns the changes which all matched rules make to the specified transaction, the actions of later rules
+// override the category, members and comment set by earlier rules, and the tags of all matched rules are added
+func (e *TransactionRuleEngine) Evaluate(transaction *Transaction) *TransactionRuleResult {
+	result := &TransactionRuleResult{}
+
+	for i, rule := range e.rules {
+		if !e.isMatched(rule, e.commentPatterns[i], transaction) {
+			continue
+		}
+
+		result.MatchedRuleIds = append(result.MatchedRuleIds, rule.RuleId)
+
+		if rule.SetCategoryId > 0 {
+			result.CategoryId = rule.SetCategoryId
+		}
+
+		result.AddTagIds = append(result.AddTagIds, utils.Int64SliceMinus(rule.GetAddTagIds(), result.AddTagIds)...)
+
+		if memberIds := rule.GetSetMemberIds(); len(memberIds) > 0 {
+			result.MemberIds = memberIds
+		}
+
+		if rule.SetComment != "" {
+			comment := rule.SetComment
+			result.Comment = &comment
+		}
+	}
+
+	return result
+}
+
+func (e *TransactionRuleEngine) isMatched(rule *TransactionRule, commentPattern *regexp.Regexp, transaction *Transaction) bool {
+	if transaction.Type != TRANSACTION_DB_TYPE_INCOME && transaction.Type != TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return false
+	}
+
+	if rule.TransactionType > 0 {
+		transactionType, err := transaction.Type.ToTransactionType()
+
+		if err != nil || transactionType != rule.TransactionType {
+			return false
+		}
+	}
+
+	if commentPattern != nil && !commentPattern.MatchString(transaction.Comment) {
+		return false
+	}
+
+	if rule.MinAmount > 0 && transaction.Amount < rule.MinAmount {
+		return false
+	}
+
+	if rule.MaxAmount > 0 && transaction.Amount > rule.MaxAmount {
+		return false
+	}
+
+	if rule.AccountId > 0 && transaction.AccountId != rule.AccountId {
+		return false
+	}
+
+	if rule.CounterpartyAccountId > 0 && transaction.RelatedAccountId != rule.CounterpartyAccountId {
+		return false
+	}
+
+	return true
+}
+
+func isTransactionRuleBefore(rule1 *TransactionRule, rule2 *TransactionRule) bool {
+	if rule1.DisplayOrder != rule2.DisplayOrder {
+		return rule1.DisplayOrder < rule2.DisplayOrder
+	}
+
+	return rule1.RuleId < rule2.RuleId
+}
+
+func getTransactionRuleIds(ids string) []int64 {
+	items := make([]string, 0)
+
+	if ids != "" {
+		items = strings.Split(ids, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(items)
+
+	return result
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionRuleScopeString(t *testing.T) {
+	assert.Equal(t, "Personal", TRANSACTION_RULE_SCOPE_PERSONAL.String())
+	assert.Equal(t, "Fund", TRANSACTION_RULE_SCOPE_FUND.String())
+	assert.Equal(t, "Unknown", TransactionRuleScope(99).String())
+}
+
+func TestTransactionRuleValidate(t *testing.T) {
+	rule := &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_PERSONAL, SetComment: "comment"}
+	assert.Nil(t, rule.Validate())
+
+	rule = &TransactionRule{Scope: 0, SetComment: "comment"}
+	assert.Equal(t, errs.ErrTransactionRuleScopeInvalid, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, TransactionType: TRANSACTION_TYPE_MODIFY_BALANCE, SetComment: "comment"}
+	assert.Equal(t, errs.ErrTransactionTypeInvalid, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, CommentPattern: "(", SetComment: "comment"}
+	assert.Equal(t, errs.ErrTransactionRuleCommentPatternInvalid, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, MinAmount: 200, MaxAmount: 100, SetComment: "comment"}
+	assert.Equal(t, errs.ErrTransactionRuleAmountRangeInvalid, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, MinAmount: 200, SetComment: "comment"}
+	assert.Nil(t, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, TransactionType: TRANSACTION_TYPE_EXPENSE, CounterpartyAccountId: 1001, SetComment: "comment"}
+	assert.Equal(t, errs.ErrTransactionRuleCounterpartyRequiresTransfer, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, SetCategoryId: 1001}
+	assert.Equal(t, errs.ErrTransactionRuleCategoryRequiresTransactionType, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, TransactionType: TRANSACTION_TYPE_EXPENSE, SetCategoryId: 1001}
+	assert.Nil(t, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, AddTagIds: "1,2,3,4,5,6,7,8,9,10,11"}
+	assert.Equal(t, errs.ErrTransactionHasTooManyTags, rule.Validate())
+
+	rule = &TransactionRule{Scope: TRANSACTION_RULE_SCOPE_FUND, CommentPattern: "coffee"}
+	assert.Equal(t, errs.ErrTransactionRuleHasNoAction, rule.Validate())
+}
+
+func TestTransactionRuleGetCategoryType(t *testing.T) {
+	categoryType, err := (&TransactionRule{TransactionType: TRANSACTION_TYPE_INCOME}).GetCategoryType()
+	assert.Nil(t, err)
+	assert.Equal(t, CATEGORY_TYPE_INCOME, categoryType)
+
+	categoryType, err = (&TransactionRule{TransactionType: TRANSACTION_TYPE_EXPENSE}).GetCategoryType()
+	assert.Nil(t, err)
+	assert.Equal(t, CATEGORY_TYPE_EXPENSE, categoryType)
+
+	categoryType, err = (&TransactionRule{TransactionType: TRANSACTION_TYPE_TRANSFER}).GetCategoryType()
+	assert.Nil(t, err)
+	assert.Equal(t, CATEGORY_TYPE_TRANSFER, categoryType)
+
+	_, err = (&TransactionRule{}).GetCategoryType()
+	assert.Equal(t, errs.ErrTransactionRuleCategoryRequiresTransactionType, err)
+}
+
+func TestTransactionRuleToTransactionRuleInfoResponse(t *testing.T) {
+	rule := &TransactionRule{
+		RuleId:          1001,
+		Uid:             2001,
+		Scope:           TRANSACTION_RULE_SCOPE_FUND,
+		Name:            "Coffee",
+		DisplayOrder:    3,
+		TransactionType: TRANSACTION_TYPE_EXPENSE,
+		CommentPattern:  "(?i)coffee",
+		SetCategoryId:   3001,
+		AddTagIds:       "4001,4002",
+		SetMemberIds:    "",
+	}
+
+	ruleResp := rule.ToTransactionRuleInfoResponse()
+	assert.Equal(t, int64(1001), ruleResp.Id)
+	assert.Equal(t, int64(2001), ruleResp.CreatorUid)
+	assert.Equal(t, TRANSACTION_RULE_SCOPE_FUND, ruleResp.Scope)
+	assert.Equal(t, "Coffee", ruleResp.Name)
+	assert.Equal(t, int32(3), ruleResp.DisplayOrder)
+	assert.Equal(t, TRANSACTION_TYPE_EXPENSE, ruleResp.TransactionType)
+	assert.Equal(t, "(?i)coffee", ruleResp.CommentPattern)
+	assert.Equal(t, int64(3001), ruleResp.SetCategoryId)
+	assert.Equal(t, []string{"4001", "4002"}, ruleResp.AddTagIds)
+	assert.Equal(t, []string{}, ruleResp.SetMemberIds)
+}
+
+func TestTransactionRuleInfoResponseSliceSort(t *testing.T) {
+	ruleResps := TransactionRuleInfoResponseSlice{
+		{Id: 3, DisplayOrder: 2},
+		{Id: 2, DisplayOrder: 1},
+		{Id: 1, DisplayOrder: 2},
+	}
+
+	sort.Sort(ruleResps)
+
+	assert.Equal(t, int64(2), ruleResps[0].Id)
+	assert.Equal(t, int64(1), ruleResps[1].Id)
+	assert.Equal(t, int64(3), ruleResps[2].Id)
+}
+
+func TestTransactionRuleEngineEvaluate_MatchConditions(t *testing.T) {
+	engine := NewTransactionRuleEngine([]*TransactionRule{
+		{RuleId: 1, TransactionType: TRANSACTION_TYPE_EXPENSE, CommentPattern: "(?i)coffee", MinAmount: 100, MaxAmount: 1000, AccountId: 1001, SetComment: "Coffee"},
+	})
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "Morning COFFEE", Amount: 500, AccountId: 1001}
+	assert.True(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, Comment: "Morning COFFEE", Amount: 500, AccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "Lunch", Amount: 500, AccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "Coffee", Amount: 99, AccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "Coffee", Amount: 1001, AccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "Coffee", Amount: 500, AccountId: 1002}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+}
+
+func TestTransactionRuleEngineEvaluate_TransferTransactions(t *testing.T) {
+	engine := NewTransactionRuleEngine([]*TransactionRule{
+		{RuleId: 1, TransactionType: TRANSACTION_TYPE_TRANSFER, CounterpartyAccountId: 1002, SetComment: "Savings"},
+	})
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1001, RelatedAccountId: 1002}
+	assert.True(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1002, RelatedAccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1001, RelatedAccountId: 1003}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+
+	engine = NewTransactionRuleEngine([]*TransactionRule{{RuleId: 1, SetComment: "Any"}})
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1001}
+	assert.False(t, engine.Evaluate(transaction).IsMatched())
+}
+
+func TestTransactionRuleEngineEvaluate_RulesInDisplayOrder(t *testing.T) {
+	engine := NewTransactionRuleEngine([]*TransactionRule{
+		{RuleId: 3, DisplayOrder: 3, TransactionType: TRANSACTION_TYPE_EXPENSE, SetCategoryId: 3003, AddTagIds: "4002,4003"},
+		{RuleId: 1, DisplayOrder: 1, TransactionType: TRANSACTION_TYPE_EXPENSE, SetCategoryId: 3001, AddTagIds: "4001", SetMemberIds: "5001,5002", SetComment: "first"},
+		{RuleId: 2, DisplayOrder: 2, SetComment: "second", SetMemberIds: "5003"},
+		{RuleId: 4, DisplayOrder: 4, SetComment: "disabled", Disabled: true},
+		{RuleId: 5, DisplayOrder: 5, CommentPattern: "(", SetComment: "invalid"},
+	})
+
+	result := engine.Evaluate(&Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE})
+	assert.Equal(t, []int64{1, 2, 3}, result.MatchedRuleIds)
+	assert.Equal(t, int64(3003), result.CategoryId)
+	assert.Equal(t, []int64{4001, 4002, 4003}, result.AddTagIds)
+	assert.Equal(t, []int64{5003}, result.MemberIds)
+	assert.Equal(t, "second", *result.Comment)
+
+	result = engine.Evaluate(&Transaction{Type: TRANSACTION_DB_TYPE_INCOME})
+	assert.Equal(t, []int64{2}, result.MatchedRuleIds)
+	assert.Equal(t, int64(0), result.CategoryId)
+}
+
+func TestTransactionRuleEngineIsEmpty(t *testing.T) {
+	assert.True(t, NewTransactionRuleEngine(nil).IsEmpty())
+	assert.True(t, NewTransactionRuleEngine([]*TransactionRule{{RuleId: 1, Disabled: true}}).IsEmpty())
+	assert.False(t, NewTransactionRuleEngine([]*TransactionRule{{RuleId: 1}}).IsEmpty())
+}
+
+func TestTransactionRuleResultApplyTo(t *testing.T) {
+	comment := "new comment"
+	result := &TransactionRuleResult{
+		MatchedRuleIds: []int64{1},
+		CategoryId:     3001,
+		AddTagIds:      []int64{4002, 4003},
+		Comment:        &comment,
+	}
+
+	transaction := &Transaction{CategoryId: 3000, Comment: "old comment"}
+	tagIds := result.ApplyTo(transaction, []int64{4001, 4002})
+	assert.Equal(t, int64(3001), transaction.CategoryId)
+	assert.Equal(t, "new comment", transaction.Comment)
+	assert.Equal(t, []int64{4001, 4002, 4003}, tagIds)
+
+	result = &TransactionRuleResult{MatchedRuleIds: []int64{1}, AddTagIds: []int64{4011}}
+	transaction = &Transaction{CategoryId: 3000, Comment: "old comment"}
+	tagIds = result.ApplyTo(transaction, []int64{4001, 4002, 4003, 4004, 4005, 4006, 4007, 4008, 4009, 4010})
+	assert.Equal(t, int64(3000), transaction.CategoryId)
+	assert.Equal(t, "old comment", transaction.Comment)
+	assert.Equal(t, MaximumTagsCountOfTransaction, len(tagIds))
+	assert.NotContains(t, tagIds, int64(4011))
+}
+
+func TestTransactionRuleResultFillTo(t *testing.T) {
+	comment := "new comment"
+	result := &TransactionRuleResult{
+		MatchedRuleIds: []int64{1},
+		CategoryId:     3001,
+		AddTagIds:      []int64{4002, 4003},
+		Comment:        &comment,
+	}
+
+	transaction := &Transaction{CategoryId: 3000, Comment: "old comment"}
+	tagIds := result.FillTo(transaction, []int64{4001, 4002})
+	assert.Equal(t, int64(3000), transaction.CategoryId)
+	assert.Equal(t, "old comment", transaction.Comment)
+	assert.Equal(t, []int64{4001, 4002, 4003}, tagIds)
+
+	transaction = &Transaction{}
+	tagIds = result.FillTo(transaction, nil)
+	assert.Equal(t, int64(3001), transaction.CategoryId)
+	assert.Equal(t, "new comment", transaction.Comment)
+	assert.Equal(t, []int64{4002, 4003}, tagIds)
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MinimumSplitLinesCountOfTransaction represents the minimum count of line items of a split transaction
+const MinimumSplitLinesCountOfTransaction = 2
+
+// MaximumSplitLinesCountOfTransaction represents the maximum count of line items of a split transaction
+const MaximumSplitLinesCountOfTransaction = 50
+
+// TransactionSplit represents a line item of split transaction stored in database, line items have no deleted flag
+// and follow the parent transaction
+type TransactionSplit struct {
+	TransactionId   int64  `xorm:"PK"`
+	LineIndex       int32  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_split_uid) NOT NULL"`
+	CategoryId      int64  `xorm:"NOT NULL"`
+	Amount          int64  `xorm:"NOT NULL"`
+	TagIds          string `xorm:"VARCHAR(255) NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// TransactionSplitRequest represents a line item in transaction creation or modification request
+type TransactionSplitRequest struct {
+	CategoryId int64    `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64    `json:"amount" binding:"min=-99999999999,max=99999999999"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment" binding:"max=255"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split line item
+type TransactionSplitInfoResponse struct {
+	CategoryId int64    `json:"categoryId,string"`
+	Amount     int64    `json:"amount"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment"`
+}
+
+// GetTagIds returns all tag ids of the line item
+func (s *TransactionSplit) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if s.TagIds != "" {
+		tagIds = strings.Split(s.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		TagIds:     utils.Int64ArrayToStringArray(s.GetTagIds()),
+		Comment:    s.Comment,
+	}
+}
+
+// ValidateTransactionSplits checks whether the line items can be set to a transaction with the specified type and amount,
+// empty line items mean the transaction is not split
+func ValidateTransactionSplits(transactionType TransactionDbType, totalAmount int64, splits []*TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if transactionType != TRANSACTION_DB_TYPE_INCOME && transactionType != TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitNotSupported
+	}
+
+	if len(splits) < MinimumSplitLinesCountOfTransaction {
+		return errs.ErrTransactionSplitLinesTooFew
+	}
+
+	if len(splits) > MaximumSplitLinesCountOfTransaction {
+		return errs.ErrTransactionHasTooManySplitLines
+	}
+
+	amountSum := int64(0)
+
+	for _, split := range splits {
+		if split.CategoryId <= 0 {
+			return errs.ErrTransactionCategoryIdInvalid
+		}
+
+		if len(split.GetTagIds()) > MaximumTagsCountOfTransaction {
+			return errs.ErrTransactionHasTooManyTags
+		}
+
+		amountSum += split.Amount
+	}
+
+	if amountSum != totalAmount {
+		return errs.ErrTransactionSplitAmountNotEqualToTransactionAmount
+	}
+
+	return nil
+}
+
+// ToTransactionSplitInfoResponses returns the view-objects of all line items of one transaction, ordered by line index
+func ToTransactionSplitInfoResponses(splits []*TransactionSplit) []*TransactionSplitInfoResponse {
+	splitResps := make([]*TransactionSplitInfoResponse, 0, len(splits))
+	sortedSplits := make([]*TransactionSplit, len(splits))
+	copy(sortedSplits, splits)
+
+	sort.Slice(sortedSplits, func(i, j int) bool {
+		return sortedSplits[i].LineIndex < sortedSplits[j].LineIndex
+	})
+
+	for _, split := range sortedSplits {
+		splitResps = append(splitResps, split.ToTransactionSplitInfoResponse())
+	}
+
+	return splitResps
+}
+
+// ExpandSplitTransactions returns the transactions in which every split transaction is replaced by one copy per line item
+// carrying the category and amount of the line item, so that amounts can be attributed to the category of each line
+func ExpandSplitTransactions(transactions []*Transaction, allSplits map[int64][]*TransactionSplit) []*Transaction {
+	if len(allSplits) < 1 {
+		return transactions
+	}
+
+	expandedTransactions := make([]*Transaction, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		splits := allSplits[transaction.TransactionId]
+
+		if len(splits) < 1 || (transaction.Type != TRANSACTION_DB_TYPE_INCOME && transaction.Type != TRANSACTION_DB_TYPE_EXPENSE) {
+			expandedTransactions = append(expandedTransactions, transaction)
+			continue
+		}
+
+		for _, split := range splits {
+			lineTransaction := *transaction
+			lineTransaction.CategoryId = split.CategoryId
+			lineTransaction.Amount = split.Amount
+			expandedTransactions = append(expandedTransactions, &lineTransaction)
+		}
+	}
+
+	return expandedTransactions
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestValidateTransactionSplits(t *testing.T) {
+	splits := []*TransactionSplit{
+		{CategoryId: 1, Amount: 600},
+		{CategoryId: 2, Amount: 400, TagIds: "1,2"},
+	}
+
+	assert.Nil(t, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, 1000, splits))
+	assert.Nil(t, ValidateTransactionSplits(TRANSACTION_DB_TYPE_INCOME, 1000, splits))
+	assert.Nil(t, ValidateTransactionSplits(TRANSACTION_DB_TYPE_TRANSFER_OUT, 1000, nil))
+}
+
+func TestValidateTransactionSplits_InvalidSplits(t *testing.T) {
+	splits := []*TransactionSplit{
+		{CategoryId: 1, Amount: 600},
+		{CategoryId: 2, Amount: 400},
+	}
+
+	assert.Equal(t, errs.ErrTransactionSplitNotSupported, ValidateTransactionSplits(TRANSACTION_DB_TYPE_TRANSFER_OUT, 1000, splits))
+	assert.Equal(t, errs.ErrTransactionSplitNotSupported, ValidateTransactionSplits(TRANSACTION_DB_TYPE_MODIFY_BALANCE, 1000, splits))
+	assert.Equal(t, errs.ErrTransactionSplitAmountNotEqualToTransactionAmount, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, 999, splits))
+	assert.Equal(t, errs.ErrTransactionSplitLinesTooFew, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, 600, splits[:1]))
+
+	splits[1].CategoryId = 0
+	assert.Equal(t, errs.ErrTransactionCategoryIdInvalid, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, 1000, splits))
+
+	splits[1].CategoryId = 2
+	splits[1].TagIds = "1,2,3,4,5,6,7,8,9,10,11"
+	assert.Equal(t, errs.ErrTransactionHasTooManyTags, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, 1000, splits))
+
+	tooManySplits := make([]*TransactionSplit, MaximumSplitLinesCountOfTransaction+1)
+
+	for i := 0; i < len(tooManySplits); i++ {
+		tooManySplits[i] = &TransactionSplit{CategoryId: 1, Amount: 1}
+	}
+
+	assert.Equal(t, errs.ErrTransactionHasTooManySplitLines, ValidateTransactionSplits(TRANSACTION_DB_TYPE_EXPENSE, int64(len(tooManySplits)), tooManySplits))
+}
+
+func TestToTransactionSplitInfoResponses(t *testing.T) {
+	splits := []*TransactionSplit{
+		{LineIndex: 1, CategoryId: 2, Amount: 400, TagIds: "3,1", Comment: "Soap"},
+		{LineIndex: 0, CategoryId: 1, Amount: 600, Comment: "Food"},
+	}
+
+	splitResps := ToTransactionSplitInfoResponses(splits)
+	assert.Equal(t, 2, len(splitResps))
+	assert.Equal(t, int64(1), splitResps[0].CategoryId)
+	assert.Equal(t, int64(600), splitResps[0].Amount)
+	assert.Equal(t, []string{}, splitResps[0].TagIds)
+	assert.Equal(t, "Food", splitResps[0].Comment)
+	assert.Equal(t, int64(2), splitResps[1].CategoryId)
+	assert.Equal(t, int64(400), splitResps[1].Amount)
+	assert.Equal(t, []string{"3", "1"}, splitResps[1].TagIds)
+	assert.Equal(t, "Soap", splitResps[1].Comment)
+}
+
+func TestExpandSplitTransactions(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 10, AccountId: 100, Amount: 1000},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 10, AccountId: 100, Amount: 500},
+		{TransactionId: 3, Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 30, AccountId: 100, Amount: 200},
+	}
+
+	allSplits := map[int64][]*TransactionSplit{
+		1: {
+			{TransactionId: 1, LineIndex: 0, CategoryId: 11, Amount: 600},
+			{TransactionId: 1, LineIndex: 1, CategoryId: 12, Amount: 400},
+		},
+		3: {
+			{TransactionId: 3, LineIndex: 0, CategoryId: 11, Amount: 100},
+			{TransactionId: 3, LineIndex: 1, CategoryId: 12, Amount: 100},
+		},
+	}
+
+	expandedTransactions := ExpandSplitTransactions(transactions, allSplits)
+	assert.Equal(t, 4, len(expandedTransactions))
+
+	assert.Equal(t, int64(1), expandedTransactions[0].TransactionId)
+	assert.Equal(t, int64(11), expandedTransactions[0].CategoryId)
+	assert.Equal(t, int64(100), expandedTransactions[0].AccountId)
+	assert.Equal(t, int64(600), expandedTransactions[0].Amount)
+
+	assert.Equal(t, int64(1), expandedTransactions[1].TransactionId)
+	assert.Equal(t, int64(12), expandedTransactions[1].CategoryId)
+	assert.Equal(t, int64(400), expandedTransactions[1].Amount)
+
+	assert.Equal(t, transactions[1], expandedTransactions[2])
+	assert.Equal(t, transactions[2], expandedTransactions[3])
+
+	assert.Equal(t, int64(10), transactions[0].CategoryId)
+	assert.Equal(t, int64(1000), transactions[0].Amount)
+}
+
+func TestExpandSplitTransactions_NoSplits(t *testing.T) {
+	transactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 10, Amount: 1000},
+	}
+
+	assert.Equal(t, transactions, ExpandSplitTransactions(transactions, nil))
+}
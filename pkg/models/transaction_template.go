@@ -1,9 +1,11 @@
 package models
 
 import (
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
@@ -24,8 +26,34 @@ const (
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED TransactionScheduleFrequencyType = 0
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY   TransactionScheduleFrequencyType = 1
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY  TransactionScheduleFrequencyType = 2
+	// TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY uses frequency values like "21" (the 2nd Monday of month)
+	// or "-15" (the last Friday of month), the last digit is the day of week and the others are the ordinal
+	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY TransactionScheduleFrequencyType = 3
 )
 
+// TransactionScheduleLastDayOfMonth represents the last day of month in the frequency values of monthly scheduled transaction
+const TransactionScheduleLastDayOfMonth = -1
+
+// TransactionScheduleWeekendRule represents how to deal with the scheduled transaction which falls on weekend
+type TransactionScheduleWeekendRule byte
+
+// Transaction template schedule weekend rules
+const (
+	TRANSACTION_SCHEDULE_WEEKEND_RULE_NONE                      TransactionScheduleWeekendRule = 0
+	TRANSACTION_SCHEDULE_WEEKEND_RULE_SKIP                      TransactionScheduleWeekendRule = 1
+	TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_PREVIOUS_WORKDAY TransactionScheduleWeekendRule = 2
+	TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_NEXT_WORKDAY     TransactionScheduleWeekendRule = 3
+)
+
+// MaximumScheduledExceptionDatesCountOfTemplate represents the maximum count of exception dates of a scheduled transaction template
+const MaximumScheduledExceptionDatesCountOfTemplate = 50
+
+// MaximumScheduledOccurrencesPreviewCount represents the maximum count of occurrences of scheduled transaction template preview
+const MaximumScheduledOccurrencesPreviewCount = 100
+
+// maximumScheduledOccurrencesSearchDays represents the maximum days to search for the next occurrences of scheduled transaction template
+const maximumScheduledOccurrencesSearchDays = 10 * 366
+
 // TransactionTemplate represents transaction template stored in database
 type TransactionTemplate struct {
 	TemplateId                 int64                            `xorm:"PK"`
@@ -43,14 +71,18 @@ type TransactionTemplate struct {
 	ScheduledEndTime           *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
 	ScheduledAt                int16                            `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
 	ScheduledTimezoneUtcOffset int16
-	TagIds                     string `xorm:"VARCHAR(255) NOT NULL"`
-	Amount                     int64  `xorm:"NOT NULL"`
-	RelatedAccountId           int64  `xorm:"NOT NULL"`
-	RelatedAccountAmount       int64  `xorm:"NOT NULL"`
-	HideAmount                 bool   `xorm:"NOT NULL"`
-	Comment                    string `xorm:"VARCHAR(255) NOT NULL"`
-	DisplayOrder               int32  `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
-	Hidden                     bool   `xorm:"NOT NULL"`
+	ScheduledOccurrenceCount   int32                          `xorm:"NOT NULL DEFAULT 0"`
+	ScheduledCreatedCount      int32                          `xorm:"NOT NULL DEFAULT 0"`
+	ScheduledExceptionDates    string                         `xorm:"VARCHAR(550) NOT NULL DEFAULT ''"`
+	ScheduledWeekendRule       TransactionScheduleWeekendRule `xorm:"NOT NULL DEFAULT 0"`
+	TagIds                     string                         `xorm:"VARCHAR(255) NOT NULL"`
+	Amount                     int64                          `xorm:"NOT NULL"`
+	RelatedAccountId           int64                          `xorm:"NOT NULL"`
+	RelatedAccountAmount       int64                          `xorm:"NOT NULL"`
+	HideAmount                 bool                           `xorm:"NOT NULL"`
+	Comment                    string                         `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder               int32                          `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
+	Hidden                     bool                           `xorm:"NOT NULL"`
 	CreatedUnixTime            int64
 	UpdatedUnixTime            int64
 	DeletedUnixTime            int64
@@ -84,6 +116,9 @@ type TransactionTemplateCreateRequest struct {
 	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
 	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	ScheduledOccurrenceCount   int32                             `json:"scheduledOccurrenceCount" binding:"min=0,max=10000"`
+	ScheduledExceptionDates    []string                          `json:"scheduledExceptionDates" binding:"omitempty,max=50"`
+	ScheduledWeekendRule       TransactionScheduleWeekendRule    `json:"scheduledWeekendRule" binding:"min=0,max=3"`
 	ClientSessionId            string                            `json:"clientSessionId"`
 }
 
@@ -111,6 +146,9 @@ type TransactionTemplateModifyRequest struct {
 	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
 	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	ScheduledOccurrenceCount   int32                             `json:"scheduledOccurrenceCount" binding:"min=0,max=10000"`
+	ScheduledExceptionDates    []string                          `json:"scheduledExceptionDates" binding:"omitempty,max=50"`
+	ScheduledWeekendRule       TransactionScheduleWeekendRule    `json:"scheduledWeekendRule" binding:"min=0,max=3"`
 }
 
 // TransactionTemplateHideRequest represents all parameters of transaction template hiding request
@@ -130,22 +168,39 @@ type TransactionTemplateNewDisplayOrderRequest struct {
 	DisplayOrder int32 `json:"displayOrder"`
 }
 
+// TransactionTemplateScheduledOccurrencesRequest represents all parameters of scheduled transaction template occurrences preview request
+type TransactionTemplateScheduledOccurrencesRequest struct {
+	Id    int64 `form:"id,string" binding:"required,min=1"`
+	Count int   `form:"count" binding:"required,min=1,max=100"`
+}
+
 // TransactionTemplateDeleteRequest represents all parameters of transaction template deleting request
 type TransactionTemplateDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTemplateScheduledOccurrenceResponse represents a view-object of the occurrence of scheduled transaction template
+type TransactionTemplateScheduledOccurrenceResponse struct {
+	Time      int64  `json:"time"`
+	UtcOffset int16  `json:"utcOffset"`
+	Date      string `json:"date"`
+}
+
 type TransactionTemplateInfoResponse struct {
 	*TransactionInfoResponse
-	TemplateType           TransactionTemplateType           `json:"templateType"`
-	Name                   string                            `json:"name"`
-	ScheduledFrequencyType *TransactionScheduleFrequencyType `json:"scheduledFrequencyType,omitempty"`
-	ScheduledFrequency     *string                           `json:"scheduledFrequency,omitempty"`
-	ScheduledStartDate     *string                           `json:"scheduledStartDate" binding:"omitempty"`
-	ScheduledEndDate       *string                           `json:"scheduledEndDate" binding:"omitempty"`
-	ScheduledAt            *int16                            `json:"scheduledAt,omitempty"`
-	DisplayOrder           int32                             `json:"displayOrder"`
-	Hidden                 bool                              `json:"hidden"`
+	TemplateType             TransactionTemplateType           `json:"templateType"`
+	Name                     string                            `json:"name"`
+	ScheduledFrequencyType   *TransactionScheduleFrequencyType `json:"scheduledFrequencyType,omitempty"`
+	ScheduledFrequency       *string                           `json:"scheduledFrequency,omitempty"`
+	ScheduledStartDate       *string                           `json:"scheduledStartDate" binding:"omitempty"`
+	ScheduledEndDate         *string                           `json:"scheduledEndDate" binding:"omitempty"`
+	ScheduledAt              *int16                            `json:"scheduledAt,omitempty"`
+	ScheduledOccurrenceCount *int32                            `json:"scheduledOccurrenceCount,omitempty"`
+	ScheduledCreatedCount    *int32                            `json:"scheduledCreatedCount,omitempty"`
+	ScheduledExceptionDates  []string                          `json:"scheduledExceptionDates,omitempty"`
+	ScheduledWeekendRule     *TransactionScheduleWeekendRule   `json:"scheduledWeekendRule,omitempty"`
+	DisplayOrder             int32                             `json:"displayOrder"`
+	Hidden                   bool                              `json:"hidden"`
 }
 
 // GetTagIds returns all tag ids of the transaction template
@@ -161,6 +216,108 @@ func (t *TransactionTemplate) GetTagIds() []int64 {
 	return result
 }
 
+// GetScheduledExceptionDates returns all exception dates (in long date format) of the scheduled transaction template
+func (t *TransactionTemplate) GetScheduledExceptionDates() []string {
+	if t.ScheduledExceptionDates == "" {
+		return make([]string, 0)
+	}
+
+	return strings.Split(t.ScheduledExceptionDates, ",")
+}
+
+// SetScheduledExceptionDates sets the exception dates of the scheduled transaction template, all dates must be in long date format
+func (t *TransactionTemplate) SetScheduledExceptionDates(exceptionDates []string) error {
+	if len(exceptionDates) > MaximumScheduledExceptionDatesCountOfTemplate {
+		return errs.ErrScheduledTransactionExceptionDateInvalid
+	}
+
+	uniqueDates := make([]string, 0, len(exceptionDates))
+	dateExistMap := make(map[string]bool, len(exceptionDates))
+
+	for i := 0; i < len(exceptionDates); i++ {
+		date := strings.TrimSpace(exceptionDates[i])
+
+		if _, err := utils.ParseFromLongDateFirstTime(date, 0); err != nil {
+			return errs.ErrScheduledTransactionExceptionDateInvalid
+		}
+
+		if !dateExistMap[date] {
+			uniqueDates = append(uniqueDates, date)
+			dateExistMap[date] = true
+		}
+	}
+
+	sort.Strings(uniqueDates)
+	t.ScheduledExceptionDates = strings.Join(uniqueDates, ",")
+
+	return nil
+}
+
+// IsScheduledOccurrenceCountReached returns whether the scheduled transaction template has created all occurrences
+func (t *TransactionTemplate) IsScheduledOccurrenceCountReached() bool {
+	return t.ScheduledOccurrenceCount > 0 && t.ScheduledCreatedCount >= t.ScheduledOccurrenceCount
+}
+
+// IsScheduledOnDate returns whether the scheduled transaction template should create transaction on the specified date,
+// the date is in the timezone of the template, and the start time, end time and occurrence count are not checked
+func (t *TransactionTemplate) IsScheduledOnDate(date time.Time) (bool, error) {
+	frequencyValueSet, err := t.getScheduledFrequencyValueSet()
+
+	if err != nil {
+		return false, err
+	}
+
+	return t.isScheduledOnDate(date, frequencyValueSet, t.getScheduledExceptionDateSet()), nil
+}
+
+// GetNextScheduledOccurrenceTimes returns the unix times of the next occurrences of the scheduled transaction template
+// which are not earlier than the specified unix time
+func (t *TransactionTemplate) GetNextScheduledOccurrenceTimes(fromUnixTime int64, count int) ([]int64, error) {
+	frequencyValueSet, err := t.getScheduledFrequencyValueSet()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.ScheduledOccurrenceCount > 0 && int(t.ScheduledOccurrenceCount-t.ScheduledCreatedCount) < count {
+		count = int(t.ScheduledOccurrenceCount - t.ScheduledCreatedCount)
+	}
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || count < 1 {
+		return make([]int64, 0), nil
+	}
+
+	occurrenceTimes := make([]int64, 0, count)
+	exceptionDateSet := t.getScheduledExceptionDateSet()
+
+	templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+	fromTime := time.Unix(fromUnixTime, 0).In(templateTimeZone)
+
+	if t.ScheduledStartTime != nil && *t.ScheduledStartTime > fromUnixTime {
+		fromTime = time.Unix(*t.ScheduledStartTime, 0).In(templateTimeZone)
+	}
+
+	date := time.Date(fromTime.Year(), fromTime.Month(), fromTime.Day(), 0, 0, 0, 0, templateTimeZone)
+
+	if date.Unix() < fromTime.Unix() {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	for i := 0; i < maximumScheduledOccurrencesSearchDays && len(occurrenceTimes) < count; i++ {
+		if t.ScheduledEndTime != nil && date.Unix() > *t.ScheduledEndTime {
+			break
+		}
+
+		if t.isScheduledOnDate(date, frequencyValueSet, exceptionDateSet) {
+			occurrenceTimes = append(occurrenceTimes, date.Unix())
+		}
+
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return occurrenceTimes, nil
+}
+
 // ToTransactionTemplateInfoResponse returns a view-object according to database model
 func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset int16) *TransactionTemplateInfoResponse {
 	utcOffset := serverUtcOffset
@@ -181,6 +338,10 @@ func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset
 		response.ScheduledFrequencyType = &t.ScheduledFrequencyType
 		response.ScheduledFrequency = &t.ScheduledFrequency
 		response.ScheduledAt = &t.ScheduledAt
+		response.ScheduledOccurrenceCount = &t.ScheduledOccurrenceCount
+		response.ScheduledCreatedCount = &t.ScheduledCreatedCount
+		response.ScheduledExceptionDates = t.GetScheduledExceptionDates()
+		response.ScheduledWeekendRule = &t.ScheduledWeekendRule
 
 		templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
 
@@ -241,3 +402,146 @@ func (s TransactionTemplateInfoResponseSlice) Swap(i, j int) {
 func (s TransactionTemplateInfoResponseSlice) Less(i, j int) bool {
 	return s[i].DisplayOrder < s[j].DisplayOrder
 }
+
+// ValidateScheduledFrequency returns whether the frequency values are valid for the specified frequency type
+func ValidateScheduledFrequency(frequencyType TransactionScheduleFrequencyType, frequency string) error {
+	if frequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+		if frequency != "" {
+			return errs.ErrScheduledTransactionFrequencyInvalid
+		}
+
+		return nil
+	}
+
+	if frequency == "" {
+		return errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(frequency, ","))
+
+	if err != nil {
+		return errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	for _, value := range frequencyValues {
+		if frequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY {
+			if value < int64(time.Sunday) || value > int64(time.Saturday) {
+				return errs.ErrScheduledTransactionFrequencyInvalid
+			}
+		} else if frequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY {
+			if value != TransactionScheduleLastDayOfMonth && (value < 1 || value > 31) {
+				return errs.ErrScheduledTransactionFrequencyInvalid
+			}
+		} else if frequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY {
+			ordinal := value / 10
+			weekday := value % 10
+
+			if ordinal < 0 {
+				weekday = -weekday
+			}
+
+			if (ordinal != -1 && (ordinal < 1 || ordinal > 5)) || weekday < int64(time.Sunday) || weekday > int64(time.Saturday) {
+				return errs.ErrScheduledTransactionFrequencyInvalid
+			}
+		} else {
+			return errs.ErrScheduledTransactionFrequencyInvalid
+		}
+	}
+
+	return nil
+}
+
+func (t *TransactionTemplate) getScheduledFrequencyValueSet() (map[int64]bool, error) {
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+		return make(map[int64]bool), nil
+	}
+
+	if err := ValidateScheduledFrequency(t.ScheduledFrequencyType, t.ScheduledFrequency); err != nil {
+		return nil, err
+	}
+
+	frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(t.ScheduledFrequency, ","))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ToSet(frequencyValues), nil
+}
+
+func (t *TransactionTemplate) getScheduledExceptionDateSet() map[string]bool {
+	exceptionDates := t.GetScheduledExceptionDates()
+	exceptionDateSet := make(map[string]bool, len(exceptionDates))
+
+	for i := 0; i < len(exceptionDates); i++ {
+		exceptionDateSet[exceptionDates[i]] = true
+	}
+
+	return exceptionDateSet
+}
+
+func (t *TransactionTemplate) isScheduledOnDate(date time.Time, frequencyValueSet map[int64]bool, exceptionDateSet map[string]bool) bool {
+	if exceptionDateSet[utils.FormatUnixTimeToLongDate(date.Unix(), date.Location())] {
+		return false
+	}
+
+	// the occurrence on weekend may be shifted at most two days to the previous friday or the next monday
+	for offset := -2; offset <= 2; offset++ {
+		originalDate := date.AddDate(0, 0, offset)
+
+		if !t.isOriginalScheduledDate(originalDate, frequencyValueSet) || exceptionDateSet[utils.FormatUnixTimeToLongDate(originalDate.Unix(), originalDate.Location())] {
+			continue
+		}
+
+		adjustedDate, valid := t.getWeekendAdjustedDate(originalDate)
+
+		if valid && adjustedDate.Year() == date.Year() && adjustedDate.YearDay() == date.YearDay() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *TransactionTemplate) isOriginalScheduledDate(date time.Time, frequencyValueSet map[int64]bool) bool {
+	lastDayOfMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location()).Day()
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY {
+		return frequencyValueSet[int64(date.Weekday())]
+	} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY {
+		return frequencyValueSet[int64(date.Day())] || (date.Day() == lastDayOfMonth && frequencyValueSet[TransactionScheduleLastDayOfMonth])
+	} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY {
+		ordinal := int64((date.Day()-1)/7 + 1)
+		weekday := int64(date.Weekday())
+		isLastWeekdayOfMonth := date.Day()+7 > lastDayOfMonth
+
+		return frequencyValueSet[ordinal*10+weekday] || (isLastWeekdayOfMonth && frequencyValueSet[-10-weekday])
+	}
+
+	return false
+}
+
+func (t *TransactionTemplate) getWeekendAdjustedDate(date time.Time) (time.Time, bool) {
+	if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+		return date, true
+	}
+
+	switch t.ScheduledWeekendRule {
+	case TRANSACTION_SCHEDULE_WEEKEND_RULE_SKIP:
+		return date, false
+	case TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_PREVIOUS_WORKDAY:
+		if date.Weekday() == time.Saturday {
+			return date.AddDate(0, 0, -1), true
+		}
+
+		return date.AddDate(0, 0, -2), true
+	case TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_NEXT_WORKDAY:
+		if date.Weekday() == time.Saturday {
+			return date.AddDate(0, 0, 2), true
+		}
+
+		return date.AddDate(0, 0, 1), true
+	}
+
+	return date, true
+}
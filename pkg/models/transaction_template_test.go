@@ -3,8 +3,11 @@ package models
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
 func TestTransactionTemplateGetTagIds(t *testing.T) {
@@ -43,3 +46,168 @@ func TestTransactionTemplateInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(3), transactionTemplateRespSlice[1].Id)
 	assert.Equal(t, int64(1), transactionTemplateRespSlice[2].Id)
 }
+
+func TestValidateScheduledFrequency(t *testing.T) {
+	assert.Nil(t, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, ""))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, "1"))
+
+	assert.Nil(t, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, "0,6"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, ""))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, "7"))
+
+	assert.Nil(t, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, "1,31,-1"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, "0"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, "-2"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, "a"))
+
+	assert.Nil(t, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, "10,21,56,-15"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, "5"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, "61"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, "17"))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, ValidateScheduledFrequency(TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, "-25"))
+}
+
+func TestTransactionTemplateSetScheduledExceptionDates(t *testing.T) {
+	template := &TransactionTemplate{}
+
+	err := template.SetScheduledExceptionDates([]string{"2024-12-25", "2024-01-01", "2024-12-25"})
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-01,2024-12-25", template.ScheduledExceptionDates)
+	assert.EqualValues(t, []string{"2024-01-01", "2024-12-25"}, template.GetScheduledExceptionDates())
+
+	err = template.SetScheduledExceptionDates([]string{"2024/12/25"})
+	assert.Equal(t, errs.ErrScheduledTransactionExceptionDateInvalid, err)
+
+	err = template.SetScheduledExceptionDates(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", template.ScheduledExceptionDates)
+	assert.Equal(t, 0, len(template.GetScheduledExceptionDates()))
+}
+
+func TestTransactionTemplateIsScheduledOnDate_LastDayOfMonth(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "-1",
+	}
+
+	actualValue, err := template.IsScheduledOnDate(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, actualValue)
+
+	actualValue, err = template.IsScheduledOnDate(time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.False(t, actualValue)
+
+	actualValue, err = template.IsScheduledOnDate(time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, actualValue)
+}
+
+func TestTransactionTemplateIsScheduledOnDate_NthWeekday(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY,
+		ScheduledFrequency:     "21,-15",
+	}
+
+	// 2024-09-09 is the 2nd Monday of September 2024
+	actualValue, err := template.IsScheduledOnDate(time.Date(2024, 9, 9, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, actualValue)
+
+	actualValue, err = template.IsScheduledOnDate(time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.False(t, actualValue)
+
+	// 2024-09-27 is the last Friday of September 2024
+	actualValue, err = template.IsScheduledOnDate(time.Date(2024, 9, 27, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, actualValue)
+
+	actualValue, err = template.IsScheduledOnDate(time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.False(t, actualValue)
+}
+
+func TestTransactionTemplateIsScheduledOnDate_WeekendRule(t *testing.T) {
+	// 2024-09-15 is Sunday
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "15",
+		ScheduledWeekendRule:   TRANSACTION_SCHEDULE_WEEKEND_RULE_NONE,
+	}
+
+	actualValue, _ := template.IsScheduledOnDate(time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC))
+	assert.True(t, actualValue)
+
+	template.ScheduledWeekendRule = TRANSACTION_SCHEDULE_WEEKEND_RULE_SKIP
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, actualValue)
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC))
+	assert.False(t, actualValue)
+
+	template.ScheduledWeekendRule = TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_PREVIOUS_WORKDAY
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 13, 0, 0, 0, 0, time.UTC))
+	assert.True(t, actualValue)
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, actualValue)
+
+	template.ScheduledWeekendRule = TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_NEXT_WORKDAY
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC))
+	assert.True(t, actualValue)
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, actualValue)
+
+	template.ScheduledExceptionDates = "2024-09-15"
+	actualValue, _ = template.IsScheduledOnDate(time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC))
+	assert.False(t, actualValue)
+}
+
+func TestTransactionTemplateIsScheduledOnDate_InvalidFrequency(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+		ScheduledFrequency:     "8",
+	}
+
+	_, err := template.IsScheduledOnDate(time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, errs.ErrScheduledTransactionFrequencyInvalid, err)
+}
+
+func TestTransactionTemplateGetNextScheduledOccurrenceTimes(t *testing.T) {
+	startTime := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC).Unix()
+	endTime := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC).Unix()
+
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:  TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:      "15",
+		ScheduledStartTime:      &startTime,
+		ScheduledEndTime:        &endTime,
+		ScheduledWeekendRule:    TRANSACTION_SCHEDULE_WEEKEND_RULE_SHIFT_TO_NEXT_WORKDAY,
+		ScheduledExceptionDates: "2024-10-15",
+	}
+
+	actualValue, err := template.GetNextScheduledOccurrenceTimes(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix(), 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int64{
+		time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC).Unix(),
+	}, actualValue)
+
+	actualValue, err = template.GetNextScheduledOccurrenceTimes(time.Date(2024, 9, 16, 0, 0, 1, 0, time.UTC).Unix(), 1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int64{time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC).Unix()}, actualValue)
+
+	template.ScheduledOccurrenceCount = 3
+	template.ScheduledCreatedCount = 2
+
+	actualValue, err = template.GetNextScheduledOccurrenceTimes(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix(), 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int64{time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC).Unix()}, actualValue)
+
+	template.ScheduledCreatedCount = 3
+	assert.True(t, template.IsScheduledOccurrenceCountReached())
+
+	actualValue, err = template.GetNextScheduledOccurrenceTimes(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualValue))
+}
@@ -0,0 +1,118 @@
+package models
+
+// TrashItemType represents the type of deleted item in trash bin
+type TrashItemType byte
+
+// Trash item types
+const (
+	TRASH_ITEM_TYPE_TRANSACTION TrashItemType = 1
+	TRASH_ITEM_TYPE_ACCOUNT     TrashItemType = 2
+	TRASH_ITEM_TYPE_CATEGORY    TrashItemType = 3
+)
+
+// String returns a textual representation of the trash item type enum
+func (t TrashItemType) String() string {
+	switch t {
+	case TRASH_ITEM_TYPE_TRANSACTION:
+		return "Transaction"
+	case TRASH_ITEM_TYPE_ACCOUNT:
+		return "Account"
+	case TRASH_ITEM_TYPE_CATEGORY:
+		return "Category"
+	default:
+		return "Unknown"
+	}
+}
+
+// ToFundResource returns the fund resource whose permission is required to access the trash item
+func (t TrashItemType) ToFundResource() FundResource {
+	switch t {
+	case TRASH_ITEM_TYPE_TRANSACTION:
+		return FUND_RESOURCE_TRANSACTION
+	case TRASH_ITEM_TYPE_ACCOUNT:
+		return FUND_RESOURCE_ACCOUNT
+	case TRASH_ITEM_TYPE_CATEGORY:
+		return FUND_RESOURCE_CATEGORY
+	default:
+		return 0
+	}
+}
+
+// TrashListRequest represents all parameters of trash bin list request
+type TrashListRequest struct {
+	Type    TrashItemType `form:"type" binding:"min=0,max=3"` // 0 = all types
+	MaxTime int64         `form:"max_time" binding:"min=0"`   // Unix time, only items deleted before this time are returned
+	Count   int32         `form:"count" binding:"required,min=1,max=50"`
+}
+
+// TrashRestoreRequest represents all parameters of trash item restore request
+type TrashRestoreRequest struct {
+	Type TrashItemType `json:"type" binding:"required,min=1,max=3"`
+	Id   int64         `json:"id,string" binding:"required,min=1"`
+}
+
+// TrashItemInfoResponse represents a view-object of deleted item in trash bin
+type TrashItemInfoResponse struct {
+	Type        TrashItemType                    `json:"type"`
+	Id          int64                            `json:"id,string"`
+	Transaction *TransactionInfoResponse         `json:"transaction,omitempty"`
+	Account     *AccountInfoResponse             `json:"account,omitempty"`
+	Category    *TransactionCategoryInfoResponse `json:"category,omitempty"`
+	DeletedAt   int64                            `json:"deletedAt"`
+}
+
+// TrashItemInfoResponseSlice represents the slice data structure of TrashItemInfoResponse
+type TrashItemInfoResponseSlice []*TrashItemInfoResponse
+
+// Len returns the count of items
+func (s TrashItemInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TrashItemInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TrashItemInfoResponseSlice) Less(i, j int) bool {
+	if s[i].DeletedAt != s[j].DeletedAt {
+		return s[i].DeletedAt > s[j].DeletedAt
+	}
+
+	if s[i].Type != s[j].Type {
+		return s[i].Type < s[j].Type
+	}
+
+	return s[i].Id < s[j].Id
+}
+
+// ToTrashItemInfoResponse returns a view-object of trash item according to the deleted transaction
+func (t *Transaction) ToTrashItemInfoResponse() *TrashItemInfoResponse {
+	return &TrashItemInfoResponse{
+		Type:        TRASH_ITEM_TYPE_TRANSACTION,
+		Id:          t.TransactionId,
+		Transaction: t.ToTransactionInfoResponse(nil, false),
+		DeletedAt:   t.DeletedUnixTime,
+	}
+}
+
+// ToTrashItemInfoResponse returns a view-object of trash item according to the deleted account
+func (a *Account) ToTrashItemInfoResponse() *TrashItemInfoResponse {
+	return &TrashItemInfoResponse{
+		Type:      TRASH_ITEM_TYPE_ACCOUNT,
+		Id:        a.AccountId,
+		Account:   a.ToAccountInfoResponse(),
+		DeletedAt: a.DeletedUnixTime,
+	}
+}
+
+// ToTrashItemInfoResponse returns a view-object of trash item according to the deleted transaction category
+func (c *TransactionCategory) ToTrashItemInfoResponse() *TrashItemInfoResponse {
+	return &TrashItemInfoResponse{
+		Type:      TRASH_ITEM_TYPE_CATEGORY,
+		Id:        c.CategoryId,
+		Category:  c.ToTransactionCategoryInfoResponse(),
+		DeletedAt: c.DeletedUnixTime,
+	}
+}
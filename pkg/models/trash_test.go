@@ -0,0 +1,61 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashItemType_String(t *testing.T) {
+	assert.Equal(t, "Transaction", TRASH_ITEM_TYPE_TRANSACTION.String())
+	assert.Equal(t, "Account", TRASH_ITEM_TYPE_ACCOUNT.String())
+	assert.Equal(t, "Category", TRASH_ITEM_TYPE_CATEGORY.String())
+	assert.Equal(t, "Unknown", TrashItemType(0).String())
+}
+
+func TestTrashItemTypeToFundResource(t *testing.T) {
+	assert.Equal(t, FUND_RESOURCE_TRANSACTION, TRASH_ITEM_TYPE_TRANSACTION.ToFundResource())
+	assert.Equal(t, FUND_RESOURCE_ACCOUNT, TRASH_ITEM_TYPE_ACCOUNT.ToFundResource())
+	assert.Equal(t, FUND_RESOURCE_CATEGORY, TRASH_ITEM_TYPE_CATEGORY.ToFundResource())
+	assert.Equal(t, FundResource(0), TrashItemType(0).ToFundResource())
+}
+
+func TestToTrashItemInfoResponse(t *testing.T) {
+	transaction := &Transaction{TransactionId: 1001, Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 100, DeletedUnixTime: 1700000000}
+	transactionItem := transaction.ToTrashItemInfoResponse()
+	assert.Equal(t, TRASH_ITEM_TYPE_TRANSACTION, transactionItem.Type)
+	assert.Equal(t, int64(1001), transactionItem.Id)
+	assert.Equal(t, int64(100), transactionItem.Transaction.SourceAmount)
+	assert.Equal(t, int64(1700000000), transactionItem.DeletedAt)
+	assert.Nil(t, transactionItem.Account)
+
+	account := &Account{AccountId: 2001, Name: "account", DeletedUnixTime: 1700000001}
+	accountItem := account.ToTrashItemInfoResponse()
+	assert.Equal(t, TRASH_ITEM_TYPE_ACCOUNT, accountItem.Type)
+	assert.Equal(t, int64(2001), accountItem.Id)
+	assert.Equal(t, "account", accountItem.Account.Name)
+
+	category := &TransactionCategory{CategoryId: 3001, Name: "category", DeletedUnixTime: 1700000002}
+	categoryItem := category.ToTrashItemInfoResponse()
+	assert.Equal(t, TRASH_ITEM_TYPE_CATEGORY, categoryItem.Type)
+	assert.Equal(t, int64(3001), categoryItem.Id)
+	assert.Equal(t, "category", categoryItem.Category.Name)
+}
+
+func TestTrashItemInfoResponseSliceLess(t *testing.T) {
+	var trashItemRespSlice TrashItemInfoResponseSlice
+	trashItemRespSlice = append(trashItemRespSlice, &TrashItemInfoResponse{Type: TRASH_ITEM_TYPE_CATEGORY, Id: 1, DeletedAt: 100})
+	trashItemRespSlice = append(trashItemRespSlice, &TrashItemInfoResponse{Type: TRASH_ITEM_TYPE_TRANSACTION, Id: 3, DeletedAt: 200})
+	trashItemRespSlice = append(trashItemRespSlice, &TrashItemInfoResponse{Type: TRASH_ITEM_TYPE_ACCOUNT, Id: 2, DeletedAt: 100})
+	trashItemRespSlice = append(trashItemRespSlice, &TrashItemInfoResponse{Type: TRASH_ITEM_TYPE_ACCOUNT, Id: 1, DeletedAt: 100})
+
+	sort.Sort(trashItemRespSlice)
+
+	assert.Equal(t, int64(3), trashItemRespSlice[0].Id)
+	assert.Equal(t, TRASH_ITEM_TYPE_ACCOUNT, trashItemRespSlice[1].Type)
+	assert.Equal(t, int64(1), trashItemRespSlice[1].Id)
+	assert.Equal(t, TRASH_ITEM_TYPE_ACCOUNT, trashItemRespSlice[2].Type)
+	assert.Equal(t, int64(2), trashItemRespSlice[2].Id)
+	assert.Equal(t, TRASH_ITEM_TYPE_CATEGORY, trashItemRespSlice[3].Type)
+}
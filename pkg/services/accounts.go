@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -203,6 +204,44 @@ func (s *AccountService) GetAccountsByAccountIds(c core.Context, uid int64, fund
 	return accountMap, err
 }
 
+// GetAccountBalanceHistory returns the end-of-period balances of the specified accounts, which are computed by walking
+// the transactions backwards page by page from the current balances
+func (s *AccountService) GetAccountBalanceHistory(c core.Context, uid int64, fundId int64, accounts []*models.Account, periods []*models.AccountBalanceHistoryPeriod) (map[int64][]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	builder := models.NewAccountBalanceHistoryBuilder(accounts, periods)
+
+	if len(accounts) < 1 || len(periods) < 1 {
+		return builder.Finish(), nil
+	}
+
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(builder.GetMinUnixTime())
+	maxTransactionTime := int64(math.MaxInt64)
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("type, account_id, transaction_time, amount, related_account_amount").Where("uid=? AND fund_id=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", uid, fundId, false, minTransactionTime, maxTransactionTime).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !builder.Walk(transactions) || len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return builder.Finish(), nil
+}
+
 // GetMaxDisplayOrder returns the max display order according to account category
 func (s *AccountService) GetMaxDisplayOrder(c core.Context, uid int64, fundId int64, category models.AccountCategory) (int32, error) {
 	if uid <= 0 {
@@ -347,6 +386,12 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 			if err != nil {
 				return err
 			}
+
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(account.FundId, account.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_CREATE, nil, account, accountActivityFields))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		for i := 0; i < len(allInitTransactions); i++ {
@@ -489,15 +534,37 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 		// update accounts
 		for i := 0; i < len(updateAccounts); i++ {
 			account := updateAccounts[i]
-			fmt.Println(account.FundId)
+			oldAccount := &models.Account{}
+			has, err := sess.ID(account.AccountId).Where("uid=? AND fund_id=? AND deleted=?", account.Uid, account.FundId, false).Get(oldAccount)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrAccountNotFound
+			}
+
 			updatedRows, err := sess.ID(account.AccountId).Cols("name", "category", "icon", "color", "comment", "extend", "hidden", "updated_unix_time").Where("uid=? AND fund_id=? AND deleted=?", account.Uid, account.FundId, false).Update(account)
-			fmt.Println("update row ", updatedRows)
 
 			if err != nil {
 				return err
 			} else if updatedRows < 1 {
 				return errs.ErrAccountNotFound
 			}
+
+			newAccount := &models.Account{}
+			has, err = sess.ID(account.AccountId).Where("uid=? AND fund_id=? AND deleted=?", account.Uid, account.FundId, false).Get(newAccount)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrAccountNotFound
+			}
+
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(account.FundId, account.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_MODIFY, oldAccount, newAccount, accountActivityFields))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		// add new sub accounts
@@ -508,6 +575,12 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 			if err != nil {
 				return err
 			}
+
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(account.FundId, account.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_CREATE, nil, account, accountActivityFields))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		// add init transaction for new sub accounts
@@ -603,6 +676,13 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 				DeletedUnixTime: now,
 			}
 
+			var removeSubAccounts []*models.Account
+			err = sess.Where("uid=? AND fund_id=? AND deleted=?", mainAccount.Uid, mainAccount.FundId, false).In("account_id", removeSubAccountIds).Find(&removeSubAccounts)
+
+			if err != nil {
+				return err
+			}
+
 			deletedRows, err := sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", mainAccount.Uid, mainAccount.FundId, false).In("account_id", removeSubAccountIds).Update(deleteAccountUpdateModel)
 
 			if err != nil {
@@ -611,6 +691,15 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 				return errs.ErrSubAccountNotFound
 			}
 
+			for i := 0; i < len(removeSubAccounts); i++ {
+				account := removeSubAccounts[i]
+				err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(account.FundId, mainAccount.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_DELETE, account, nil, accountActivityFields))
+
+				if err != nil {
+					return err
+				}
+			}
+
 			if len(relatedTransactionsByAccount) > 0 {
 				updateTransaction := &models.Transaction{
 					Deleted:         true,
@@ -795,6 +884,15 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, fundId int64,
 			return errs.ErrAccountNotFound
 		}
 
+		for i := 0; i < len(accountAndSubAccounts); i++ {
+			account := accountAndSubAccounts[i]
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_DELETE, account, nil, accountActivityFields))
+
+			if err != nil {
+				return err
+			}
+		}
+
 		if len(relatedTransactionsByAccount) > 0 {
 			updateTransaction := &models.Transaction{
 				Deleted:         true,
@@ -841,7 +939,7 @@ func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, fundId int6
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		account := &models.Account{}
-		has, err := sess.Cols("account_id", "uid", "fund_id", "deleted", "parent_account_id").Where("uid=? AND fund_id=? AND deleted=? AND account_id=? AND parent_account_id<>?", uid, fundId, false, accountId, models.LevelOneAccountParentId).Limit(1).Get(account)
+		has, err := sess.Where("uid=? AND fund_id=? AND deleted=? AND account_id=? AND parent_account_id<>?", uid, fundId, false, accountId, models.LevelOneAccountParentId).Limit(1).Get(account)
 
 		if err != nil {
 			return err
@@ -888,6 +986,12 @@ func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, fundId int6
 			return errs.ErrSubAccountNotFound
 		}
 
+		err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, account.AccountId, models.FUND_ACTIVITY_ACTION_DELETE, account, nil, accountActivityFields))
+
+		if err != nil {
+			return err
+		}
+
 		if len(relatedTransactionsByAccount) > 0 {
 			updateTransaction := &models.Transaction{
 				Deleted:         true,
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
 
@@ -216,3 +217,13 @@ func TestGetAccountOrSubAccountIdsByAccountName_MatchMultiple(t *testing.T) {
 	assert.NotContains(t, actualAccountMap, int64(3001))
 	assert.NotContains(t, actualAccountMap, int64(4001))
 }
+
+func TestAccountService_GetAccountBalanceHistory_InvalidParameters(t *testing.T) {
+	service := &AccountService{}
+
+	_, err := service.GetAccountBalanceHistory(nil, 0, 1001, nil, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, err = service.GetAccountBalanceHistory(nil, 1001, 0, nil, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+}
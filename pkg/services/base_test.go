@@ -0,0 +1,45 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// initializeTestDataStore initializes an empty sqlite database and the uuid generator for the tests which require database
+func initializeTestDataStore(t *testing.T) core.Context {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType: settings.Sqlite3DbType,
+			DatabasePath: filepath.Join(t.TempDir(), "ezbookkeeping.db"),
+		},
+		UuidGeneratorType: settings.InternalUuidGeneratorType,
+	}
+
+	assert.Nil(t, datastore.InitializeDataStore(config))
+	assert.Nil(t, uuid.InitializeUuidGenerator(config))
+
+	err := datastore.Container.UserDataStore.SyncStructs(new(models.Fund), new(models.FundMember), new(models.FundActivity),
+		new(models.Account), new(models.Transaction), new(models.TransactionCategory), new(models.TransactionTag), new(models.TransactionTagIndex),
+		new(models.TransactionPictureInfo), new(models.TransactionMember), new(models.TransactionSplit), new(models.TransactionLink),
+		new(models.TransactionRevision), new(models.TransactionRule), new(models.TransactionTemplate), new(models.Payee),
+		new(models.Security), new(models.InvestmentTrade))
+	assert.Nil(t, err)
+
+	return core.NewNullContext()
+}
+
+// insertTestRows inserts the specified rows into the test database directly
+func insertTestRows(t *testing.T, c core.Context, rows ...any) {
+	for _, row := range rows {
+		_, err := datastore.Container.UserDataStore.Choose(0).NewSession(c).Insert(row)
+		assert.Nil(t, err)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestBudgetService_GetBudgetByBudgetId_InvalidParameters(t *testing.T) {
+	service := &BudgetService{}
+
+	_, err := service.GetBudgetByBudgetId(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetBudgetByBudgetId(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetBudgetByBudgetId(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "budget id is invalid")
+}
+
+func TestBudgetService_CreateBudget_InvalidParameters(t *testing.T) {
+	service := &BudgetService{}
+
+	err := service.CreateBudget(nil, &models.Budget{Uid: 0, FundId: 1001, Scope: models.BUDGET_SCOPE_PERSONAL})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreateBudget(nil, &models.Budget{Uid: 1001, FundId: 0, Scope: models.BUDGET_SCOPE_PERSONAL})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.CreateBudget(nil, &models.Budget{Uid: 1001, FundId: 1001, Scope: 0})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "budget scope is invalid")
+}
+
+func TestBudgetService_DeleteBudget_InvalidParameters(t *testing.T) {
+	service := &BudgetService{}
+
+	err := service.DeleteBudget(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.DeleteBudget(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
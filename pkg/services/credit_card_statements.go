@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// creditCardDueReminderStatementsCount represents the count of latest statement cycles to check when sending due reminders,
+// the due date of an earlier statement may be later than the latest statement date when the payment due days is long
+const creditCardDueReminderStatementsCount = 3
+
+// CreditCardStatementService represents credit card statement service
+type CreditCardStatementService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+}
+
+// Initialize a credit card statement service singleton instance
+var (
+	CreditCardStatements = &CreditCardStatementService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+	}
+)
+
+// GetStatements returns the latest statement cycles of the credit card account ending not later than the specified time,
+// the transactions and balances of all sub-accounts are included if the account has sub-accounts
+func (s *CreditCardStatementService) GetStatements(c core.Context, account *models.Account, currentUnixTime int64, timezone *time.Location, count int) (*models.CreditCardStatementsResponse, error) {
+	if account.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		return nil, errs.ErrAccountCategoryInvalid
+	}
+
+	cycles, err := models.GetCreditCardStatementCycles(account.GetCreditCardStatementDate(), currentUnixTime, timezone, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountIds := map[int64]bool{account.AccountId: true}
+	balance := account.Balance
+
+	if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		var subAccounts []*models.Account
+		err = s.UserDataDB(account.Uid).NewSession(c).Where("uid=? AND deleted=? AND parent_account_id=?", account.Uid, false, account.AccountId).Find(&subAccounts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(subAccounts); i++ {
+			accountIds[subAccounts[i].AccountId] = true
+			balance += subAccounts[i].Balance
+		}
+	}
+
+	allAccountIds := make([]int64, 0, len(accountIds))
+
+	for accountId := range accountIds {
+		allAccountIds = append(allAccountIds, accountId)
+	}
+
+	var allTransactions []*models.Transaction
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(cycles[len(cycles)-1].StartTime.Unix())
+	err = s.UserDataDB(account.Uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_time>=?", account.Uid, false, minTransactionTime).In("account_id", allAccountIds).Find(&allTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*models.Transaction, 0, len(allTransactions))
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+
+		// the transfers between the sub-accounts of the same credit card are neither charges nor payments
+		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && accountIds[transaction.RelatedAccountId] {
+			continue
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return account.ToCreditCardStatementsResponse(balance, cycles, transactions, timezone), nil
+}
+
+// SendDueReminders sends reminder emails of all credit card accounts whose statement is unpaid and the reminder date is the day of the specified time
+func (s *CreditCardStatementService) SendDueReminders(c core.Context, currentUnixTime int64) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	var allAccounts []*models.Account
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var accounts []*models.Account
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND category=? AND parent_account_id=?", false, models.ACCOUNT_CATEGORY_CREDIT_CARD, models.LevelOneAccountParentId).Find(&accounts)
+
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < len(accounts); j++ {
+			if accounts[j].GetCreditCardDueReminderDays() > 0 && accounts[j].GetCreditCardPaymentDueDays() > 0 {
+				allAccounts = append(allAccounts, accounts[j])
+			}
+		}
+	}
+
+	if len(allAccounts) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[credit_card_statements.SendDueReminders] should check %d credit card accounts now", len(allAccounts))
+
+	today := utils.FormatUnixTimeToLongDate(currentUnixTime, time.Local)
+	sentCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allAccounts); i++ {
+		account := allAccounts[i]
+		statementsResp, err := s.GetStatements(c, account, currentUnixTime, time.Local, creditCardDueReminderStatementsCount)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[credit_card_statements.SendDueReminders] failed to get statements of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, account.Uid, err.Error())
+			continue
+		}
+
+		for j := 0; j < len(statementsResp.Statements); j++ {
+			statement := statementsResp.Statements[j]
+
+			if statement.Paid || s.getReminderDate(statement, account.GetCreditCardDueReminderDays()) != today {
+				continue
+			}
+
+			user, err := Users.GetUserById(c, account.Uid)
+
+			if err == nil {
+				err = s.SendDueReminderEmail(c, user, account, statement)
+			}
+
+			if err != nil {
+				failedCount++
+				log.Errorf(c, "[credit_card_statements.SendDueReminders] failed to send due reminder of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, account.Uid, err.Error())
+				continue
+			}
+
+			sentCount++
+			log.Infof(c, "[credit_card_statements.SendDueReminders] due reminder of account \"id:%d\" statement date \"%s\" has been sent to user \"uid:%d\"", account.AccountId, statement.StatementDate, account.Uid)
+		}
+	}
+
+	log.Infof(c, "[credit_card_statements.SendDueReminders] %d due reminders has been sent successfully, %d failed", sentCount, failedCount)
+
+	return nil
+}
+
+// SendDueReminderEmail sends credit card due reminder email according to specified parameters
+func (s *CreditCardStatementService) SendDueReminderEmail(c core.Context, user *models.User, account *models.Account, statement *models.CreditCardStatementResponse) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(user.Language)
+	dueReminderTextItems := localeTextItems.CreditCardDueReminderMailTextItems
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_CREDIT_CARD_DUE_REMINDER)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": s.CurrentConfig().AppName,
+		"CreditCardDueReminderMail": map[string]any{
+			"Title":          dueReminderTextItems.Title,
+			"Salutation":     fmt.Sprintf(dueReminderTextItems.SalutationFormat, user.Nickname),
+			"Description":    fmt.Sprintf(dueReminderTextItems.DescriptionFormat, account.Name, statement.StatementDate, statement.DueDate),
+			"StatementDate":  statement.StatementDate,
+			"DueDate":        statement.DueDate,
+			"RemainingDue":   fmt.Sprintf(dueReminderTextItems.RemainingDueFormat, s.formatAmount(statement.RemainingDue, account.Currency)),
+			"MinimumPayment": fmt.Sprintf(dueReminderTextItems.MinimumPaymentFormat, s.formatAmount(statement.MinimumPayment, account.Currency)),
+			"Disregard":      dueReminderTextItems.Disregard,
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: dueReminderTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
+func (s *CreditCardStatementService) getReminderDate(statement *models.CreditCardStatementResponse, dueReminderDays int) string {
+	dueDate, err := utils.ParseFromLongDateFirstTime(statement.DueDate, 0)
+
+	if err != nil {
+		return ""
+	}
+
+	return utils.FormatUnixTimeToLongDate(dueDate.AddDate(0, 0, -dueReminderDays).Unix(), time.UTC)
+}
+
+func (s *CreditCardStatementService) formatAmount(amount int64, currency string) string {
+	return fmt.Sprintf("%s %s", utils.FormatAmount(amount), currency)
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestCreditCardStatementService_GetStatements_InvalidParameters(t *testing.T) {
+	service := &CreditCardStatementService{}
+
+	_, err := service.GetStatements(nil, &models.Account{Uid: 0, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD}, time.Now().Unix(), time.UTC, 1)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, err = service.GetStatements(nil, &models.Account{Uid: 1001, Category: models.ACCOUNT_CATEGORY_DEBT}, time.Now().Unix(), time.UTC, 1)
+	assert.Equal(t, errs.ErrAccountCategoryInvalid, err)
+
+	_, err = service.GetStatements(nil, &models.Account{Uid: 1001, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD}, time.Now().Unix(), time.UTC, 1)
+	assert.Equal(t, errs.ErrCreditCardStatementDateNotSet, err)
+}
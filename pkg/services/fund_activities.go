@@ -0,0 +1,140 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// Fields of each entity which are recorded in fund activities
+var (
+	transactionActivityFields = []string{"Type", "CategoryId", "TransactionTime", "TimezoneUtcOffset", "AccountId", "PayeeId", "Amount", "RelatedAccountId", "RelatedAccountAmount", "HideAmount", "Comment", "GeoLongitude", "GeoLatitude"}
+	accountActivityFields     = []string{"ParentAccountId", "Category", "Type", "Name", "Icon", "Color", "Currency", "Balance", "Comment", "Extend", "Hidden"}
+	categoryActivityFields    = []string{"ParentCategoryId", "Type", "Name", "Icon", "Color", "Comment", "Hidden"}
+	fundActivityFields        = []string{"Name", "OwnerUid", "DefaultCurrency"}
+	fundMemberActivityFields  = []string{"Name", "Email", "Role", "LinkedUid"}
+)
+
+// FundActivityService represents fund activity service
+type FundActivityService struct {
+	ServiceUsingDB
+}
+
+// Initialize a fund activity service singleton instance
+var (
+	FundActivities = &FundActivityService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetActivityCountByFundId returns the count of fund activities which match the filters
+func (s *FundActivityService) GetActivityCountByFundId(c core.Context, uid int64, fundId int64, actorUid int64, entityType models.FundActivityEntityType, maxTime int64, minTime int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return 0, errs.ErrFundIdInvalid
+	}
+
+	condition, conditionParams := s.buildActivityQueryCondition(fundId, actorUid, entityType, maxTime, minTime)
+
+	return s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Count(&models.FundActivity{})
+}
+
+// GetActivitiesByFundId returns fund activity models which match the filters, the latest activities come first
+func (s *FundActivityService) GetActivitiesByFundId(c core.Context, uid int64, fundId int64, actorUid int64, entityType models.FundActivityEntityType, maxTime int64, minTime int64, page int32, count int32) ([]*models.FundActivity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if page < 0 {
+		return nil, errs.ErrPageIndexInvalid
+	} else if page == 0 {
+		page = 1
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	condition, conditionParams := s.buildActivityQueryCondition(fundId, actorUid, entityType, maxTime, minTime)
+
+	var activities []*models.FundActivity
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Limit(int(count), int(count*(page-1))).OrderBy("created_unix_time desc, activity_id desc").Find(&activities)
+
+	return activities, err
+}
+
+func (s *FundActivityService) buildActivityQueryCondition(fundId int64, actorUid int64, entityType models.FundActivityEntityType, maxTime int64, minTime int64) (string, []any) {
+	condition := "fund_id=?"
+	conditionParams := make([]any, 0, 5)
+	conditionParams = append(conditionParams, fundId)
+
+	if actorUid > 0 {
+		condition = condition + " AND uid=?"
+		conditionParams = append(conditionParams, actorUid)
+	}
+
+	if entityType > 0 {
+		condition = condition + " AND entity_type=?"
+		conditionParams = append(conditionParams, entityType)
+	}
+
+	if maxTime > 0 {
+		condition = condition + " AND created_unix_time<=?"
+		conditionParams = append(conditionParams, maxTime)
+	}
+
+	if minTime > 0 {
+		condition = condition + " AND created_unix_time>=?"
+		conditionParams = append(conditionParams, minTime)
+	}
+
+	return condition, conditionParams
+}
+
+// newTransactionFundActivity returns a fund activity of the transaction performed by the actor, who may not be the creator of the transaction
+func newTransactionFundActivity(actorUid int64, transaction *models.Transaction, action models.FundActivityAction, before *models.Transaction, after *models.Transaction) *models.FundActivity {
+	return models.NewFundActivity(transaction.FundId, actorUid, models.FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION, transaction.TransactionId, action, before, after, transactionActivityFields)
+}
+
+// insertFundActivity appends a fund activity in the database session of the change it records,
+// modification without any changed field is not recorded
+func insertFundActivity(c core.Context, sess *xorm.Session, activityId int64, activity *models.FundActivity) error {
+	if activity.FundId <= 0 {
+		return nil
+	}
+
+	if activity.Action == models.FUND_ACTIVITY_ACTION_MODIFY && activity.Changes == "" {
+		return nil
+	}
+
+	if activityId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	activity.ActivityId = activityId
+	activity.CreatedUnixTime = time.Now().Unix()
+
+	_, err := sess.Insert(activity)
+
+	if err != nil {
+		log.Errorf(c, "[fund_activities.insertFundActivity] failed to insert activity of %s \"id:%d\" in fund \"id:%d\", because %s", activity.EntityType, activity.EntityId, activity.FundId, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestFundActivityService_GetActivityCountByFundId_InvalidParameters(t *testing.T) {
+	service := &FundActivityService{}
+
+	_, err := service.GetActivityCountByFundId(nil, 0, 1001, 0, 0, 0, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetActivityCountByFundId(nil, 1001, 0, 0, 0, 0, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestFundActivityService_GetActivitiesByFundId_InvalidParameters(t *testing.T) {
+	service := &FundActivityService{}
+
+	_, err := service.GetActivitiesByFundId(nil, 0, 1001, 0, 0, 0, 0, 1, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetActivitiesByFundId(nil, 1001, 0, 0, 0, 0, 0, 1, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetActivitiesByFundId(nil, 1001, 1001, 0, 0, 0, 0, -1, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "page index is invalid")
+
+	_, err = service.GetActivitiesByFundId(nil, 1001, 1001, 0, 0, 0, 0, 1, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "page count is invalid")
+}
+
+func TestNewTransactionFundActivity_ActorIsNotCreator(t *testing.T) {
+	oldTransaction := &models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 2001, Amount: 100}
+	newTransaction := &models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 2001, Amount: 200}
+
+	activity := newTransactionFundActivity(1002, oldTransaction, models.FUND_ACTIVITY_ACTION_MODIFY, oldTransaction, newTransaction)
+	assert.Equal(t, int64(1002), activity.Uid)
+	assert.Equal(t, int64(2001), activity.FundId)
+	assert.Equal(t, int64(3001), activity.EntityId)
+	assert.Equal(t, models.FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION, activity.EntityType)
+	assert.Equal(t, `{"amount":{"before":100,"after":200}}`, activity.Changes)
+
+	activity = newTransactionFundActivity(1002, oldTransaction, models.FUND_ACTIVITY_ACTION_DELETE, oldTransaction, nil)
+	assert.Equal(t, int64(1002), activity.Uid)
+	assert.Equal(t, models.FUND_ACTIVITY_ACTION_DELETE, activity.Action)
+}
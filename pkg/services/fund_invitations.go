@@ -0,0 +1,428 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const fundInvitationUrlFormat = "%sdesktop/#/funds/invitation?token=%s"
+
+// FundInvitationService represents fund invitation service
+type FundInvitationService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+	ServiceUsingUuid
+}
+
+// Initialize a fund invitation service singleton instance
+var (
+	FundInvitations = &FundInvitationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetPendingInvitationsByFundId returns all unexpired pending invitations of a fund
+func (s *FundInvitationService) GetPendingInvitationsByFundId(c core.Context, uid int64, fundId int64) ([]*models.FundInvitation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if !s.isFundOwner(c, uid, fundId) {
+		return nil, errs.ErrFundAccessDenied
+	}
+
+	now := time.Now().Unix()
+
+	var invitations []*models.FundInvitation
+	err := s.UserDataDB(uid).NewSession(c).Where("fund_id=? AND status=? AND expired_unix_time>?", fundId, models.FUND_INVITATION_STATUS_PENDING, now).OrderBy("created_unix_time desc").Find(&invitations)
+
+	return invitations, err
+}
+
+// GetPendingInvitationsByInviteeUid returns all unexpired pending invitations sent to current user
+func (s *FundInvitationService) GetPendingInvitationsByInviteeUid(c core.Context, uid int64) ([]*models.FundInvitation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	var invitations []*models.FundInvitation
+	err := s.UserDataDB(uid).NewSession(c).Where("invitee_uid=? AND status=? AND expired_unix_time>?", uid, models.FUND_INVITATION_STATUS_PENDING, now).OrderBy("created_unix_time desc").Find(&invitations)
+
+	return invitations, err
+}
+
+// GetInvitedFundsByInvitations returns a map of the funds which the given invitations of current user refer to
+func (s *FundInvitationService) GetInvitedFundsByInvitations(c core.Context, uid int64, invitations []*models.FundInvitation) (map[int64]*models.Fund, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	fundIds := make([]int64, 0, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		if invitations[i].InviteeUid != uid {
+			return nil, errs.ErrFundInvitationNotFound
+		}
+
+		fundIds = append(fundIds, invitations[i].FundId)
+	}
+
+	fundMap := make(map[int64]*models.Fund, len(fundIds))
+
+	if len(fundIds) < 1 {
+		return fundMap, nil
+	}
+
+	var funds []*models.Fund
+	err := s.UserDataDB(uid).NewSession(c).In("fund_id", fundIds).Where("deleted=?", false).Find(&funds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(funds); i++ {
+		fundMap[funds[i].FundId] = funds[i]
+	}
+
+	return fundMap, nil
+}
+
+// CreateInvitation saves a new pending invitation of the specified fund member for the invitee and revokes the previous ones
+func (s *FundInvitationService) CreateInvitation(c core.Context, uid int64, member *models.FundMember, inviteeUid int64) (*models.FundInvitation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if member == nil || member.MemberId <= 0 {
+		return nil, errs.ErrMemberIdInvalid
+	}
+
+	if inviteeUid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if inviteeUid == uid {
+		return nil, errs.ErrCannotLinkToSelf
+	}
+
+	if member.Email == "" {
+		return nil, errs.ErrFundInvitationEmailIsEmpty
+	}
+
+	if member.LinkedUid > 0 {
+		return nil, errs.ErrMemberAlreadyLinked
+	}
+
+	if !s.isFundOwner(c, uid, member.FundId) {
+		return nil, errs.ErrFundAccessDenied
+	}
+
+	invitationId := s.GenerateUuid(uuid.UUID_TYPE_FUND_INVITATION)
+
+	if invitationId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	now := time.Now()
+
+	invitation := &models.FundInvitation{
+		InvitationId:    invitationId,
+		FundId:          member.FundId,
+		MemberId:        member.MemberId,
+		InviterUid:      uid,
+		InviteeUid:      inviteeUid,
+		Email:           member.Email,
+		Status:          models.FUND_INVITATION_STATUS_PENDING,
+		ExpiredUnixTime: now.Add(s.CurrentConfig().FundInvitationTokenExpiredTimeDuration).Unix(),
+		CreatedUnixTime: now.Unix(),
+		UpdatedUnixTime: now.Unix(),
+	}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("member_id").Where("fund_id=? AND linked_uid=?", member.FundId, inviteeUid).Exist(&models.FundMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrMemberAlreadyLinked
+		}
+
+		_, err = sess.Cols("status", "updated_unix_time").Where("member_id=? AND status=?", member.MemberId, models.FUND_INVITATION_STATUS_PENDING).Update(&models.FundInvitation{
+			Status:          models.FUND_INVITATION_STATUS_REVOKED,
+			UpdatedUnixTime: now.Unix(),
+		})
+
+		if err != nil {
+			log.Errorf(c, "[fund_invitations.CreateInvitation] failed to revoke previous invitations of member \"member_id:%d\", because %s", member.MemberId, err.Error())
+			return err
+		}
+
+		_, err = sess.Insert(invitation)
+
+		if err != nil {
+			log.Errorf(c, "[fund_invitations.CreateInvitation] failed to insert fund invitation \"invitation_id:%d\", because %s", invitation.InvitationId, err.Error())
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// RevokeInvitation revokes a pending invitation of the specified fund
+func (s *FundInvitationService) RevokeInvitation(c core.Context, uid int64, fundId int64, invitationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if invitationId <= 0 {
+		return errs.ErrFundInvitationIdInvalid
+	}
+
+	if !s.isFundOwner(c, uid, fundId) {
+		return errs.ErrFundAccessDenied
+	}
+
+	updatedRows, err := s.UserDataDB(uid).NewSession(c).Cols("status", "updated_unix_time").Where("invitation_id=? AND fund_id=? AND status=?", invitationId, fundId, models.FUND_INVITATION_STATUS_PENDING).Update(&models.FundInvitation{
+		Status:          models.FUND_INVITATION_STATUS_REVOKED,
+		UpdatedUnixTime: time.Now().Unix(),
+	})
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.RevokeInvitation] failed to revoke fund invitation \"invitation_id:%d\", because %s", invitationId, err.Error())
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrFundInvitationNotFound
+	}
+
+	return nil
+}
+
+// AcceptInvitation links the invited fund member to current user and returns the updated member
+func (s *FundInvitationService) AcceptInvitation(c core.Context, uid int64, invitationId int64) (*models.FundMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if invitationId <= 0 {
+		return nil, errs.ErrFundInvitationIdInvalid
+	}
+
+	member := &models.FundMember{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		invitation, err := s.getPendingInvitation(sess, uid, invitationId)
+
+		if err != nil {
+			return err
+		}
+
+		has, err := sess.Where("member_id=? AND fund_id=?", invitation.MemberId, invitation.FundId).Get(member)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrMemberNotFound
+		} else if member.LinkedUid > 0 {
+			return errs.ErrMemberAlreadyLinked
+		}
+
+		exists, err := sess.Cols("member_id").Where("fund_id=? AND linked_uid=?", invitation.FundId, uid).Exist(&models.FundMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrMemberAlreadyLinked
+		}
+
+		now := time.Now().Unix()
+		oldMember := *member
+		member.LinkedUid = uid
+		member.UpdatedUnixTime = now
+
+		updatedRows, err := sess.Cols("linked_uid", "updated_unix_time").Where("member_id=? AND linked_uid=?", member.MemberId, 0).Update(member)
+
+		if err != nil {
+			log.Errorf(c, "[fund_invitations.AcceptInvitation] failed to link member \"member_id:%d\" to user \"uid:%d\", because %s", member.MemberId, uid, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrMemberAlreadyLinked
+		}
+
+		err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(member.FundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, member.MemberId, models.FUND_ACTIVITY_ACTION_MODIFY, &oldMember, member, fundMemberActivityFields))
+
+		if err != nil {
+			return err
+		}
+
+		return s.updateInvitationStatus(c, sess, invitation, models.FUND_INVITATION_STATUS_ACCEPTED, now)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// DeclineInvitation declines a pending invitation sent to current user
+func (s *FundInvitationService) DeclineInvitation(c core.Context, uid int64, invitationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if invitationId <= 0 {
+		return errs.ErrFundInvitationIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		invitation, err := s.getPendingInvitation(sess, uid, invitationId)
+
+		if err != nil {
+			return err
+		}
+
+		return s.updateInvitationStatus(c, sess, invitation, models.FUND_INVITATION_STATUS_DECLINED, time.Now().Unix())
+	})
+}
+
+// SendInvitationEmail sends fund invitation email according to specified parameters
+func (s *FundInvitationService) SendInvitationEmail(c core.Context, inviter *models.User, invitee *models.User, fund *models.Fund, invitationToken string, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := invitee.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	fundInvitationTextItems := localeTextItems.FundInvitationMailTextItems
+
+	expireTimeInHours := s.CurrentConfig().FundInvitationTokenExpiredTimeDuration.Hours()
+	invitationUrl := fmt.Sprintf(fundInvitationUrlFormat, s.CurrentConfig().RootUrl, url.QueryEscape(invitationToken))
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_FUND_INVITATION)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": s.CurrentConfig().AppName,
+		"FundInvitationMail": map[string]any{
+			"Title":               fundInvitationTextItems.Title,
+			"Salutation":          fmt.Sprintf(fundInvitationTextItems.SalutationFormat, invitee.Nickname),
+			"DescriptionAboveBtn": fmt.Sprintf(fundInvitationTextItems.DescriptionAboveBtnFormat, inviter.Nickname, fund.Name),
+			"ViewInvitationUrl":   invitationUrl,
+			"ViewInvitation":      fundInvitationTextItems.ViewInvitation,
+			"DescriptionBelowBtn": fmt.Sprintf(fundInvitationTextItems.DescriptionBelowBtnFormat, expireTimeInHours),
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      invitee.Email,
+		Subject: fundInvitationTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
+func (s *FundInvitationService) getPendingInvitation(sess *xorm.Session, uid int64, invitationId int64) (*models.FundInvitation, error) {
+	invitation := &models.FundInvitation{}
+	has, err := sess.Where("invitation_id=? AND invitee_uid=?", invitationId, uid).Get(invitation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrFundInvitationNotFound
+	} else if invitation.Status != models.FUND_INVITATION_STATUS_PENDING {
+		return nil, errs.ErrFundInvitationNotPending
+	} else if invitation.IsExpired(time.Now().Unix()) {
+		return nil, errs.ErrFundInvitationExpired
+	}
+
+	return invitation, nil
+}
+
+func (s *FundInvitationService) updateInvitationStatus(c core.Context, sess *xorm.Session, invitation *models.FundInvitation, status models.FundInvitationStatus, now int64) error {
+	invitation.Status = status
+	invitation.UpdatedUnixTime = now
+
+	updatedRows, err := sess.Cols("status", "updated_unix_time").Where("invitation_id=? AND status=?", invitation.InvitationId, models.FUND_INVITATION_STATUS_PENDING).Update(invitation)
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.updateInvitationStatus] failed to update fund invitation \"invitation_id:%d\" to status %s, because %s", invitation.InvitationId, status, err.Error())
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrFundInvitationNotPending
+	}
+
+	return nil
+}
+
+// isFundOwner checks if user is the owner of a fund
+func (s *FundInvitationService) isFundOwner(c core.Context, uid int64, fundId int64) bool {
+	count, err := s.UserDataDB(uid).NewSession(c).Where("fund_id=? AND linked_uid=? AND role=?", fundId, uid, models.FUND_ROLE_OWNER).Count(&models.FundMember{})
+
+	if err != nil {
+		log.Errorf(c, "[fund_invitations.isFundOwner] failed to check fund owner for user \"uid:%d\" and fund \"fund_id:%d\", because %s", uid, fundId, err.Error())
+		return false
+	}
+
+	return count > 0
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestFundInvitationService_GetPendingInvitationsByFundId_InvalidUserId(t *testing.T) {
+	service := &FundInvitationService{}
+
+	_, err := service.GetPendingInvitationsByFundId(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetPendingInvitationsByFundId(nil, -1, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+}
+
+func TestFundInvitationService_GetPendingInvitationsByFundId_InvalidFundId(t *testing.T) {
+	service := &FundInvitationService{}
+
+	_, err := service.GetPendingInvitationsByFundId(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetPendingInvitationsByFundId(nil, 1001, -1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestFundInvitationService_GetPendingInvitationsByInviteeUid_InvalidUserId(t *testing.T) {
+	service := &FundInvitationService{}
+
+	_, err := service.GetPendingInvitationsByInviteeUid(nil, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+}
+
+func TestFundInvitationService_GetInvitedFundsByInvitations_OtherInvitee(t *testing.T) {
+	service := &FundInvitationService{}
+
+	_, err := service.GetInvitedFundsByInvitations(nil, 1001, []*models.FundInvitation{
+		{InvitationId: 3001, FundId: 2001, InviteeUid: 1002},
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund invitation not found")
+}
+
+func TestFundInvitationService_GetInvitedFundsByInvitations_EmptyInvitations(t *testing.T) {
+	service := &FundInvitationService{}
+
+	fundMap, err := service.GetInvitedFundsByInvitations(nil, 1001, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(fundMap))
+}
+
+func TestFundInvitationService_CreateInvitation_InvalidParameters(t *testing.T) {
+	service := &FundInvitationService{}
+	member := &models.FundMember{MemberId: 2001, FundId: 3001, Email: "jane@example.com"}
+
+	_, err := service.CreateInvitation(nil, 0, member, 1002)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.CreateInvitation(nil, 1001, nil, 1002)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "member id is invalid")
+
+	_, err = service.CreateInvitation(nil, 1001, member, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.CreateInvitation(nil, 1001, member, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot link member to self")
+}
+
+func TestFundInvitationService_CreateInvitation_MemberWithoutEmail(t *testing.T) {
+	service := &FundInvitationService{}
+	member := &models.FundMember{MemberId: 2001, FundId: 3001}
+
+	_, err := service.CreateInvitation(nil, 1001, member, 1002)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund member email is empty")
+}
+
+func TestFundInvitationService_CreateInvitation_LinkedMember(t *testing.T) {
+	service := &FundInvitationService{}
+	member := &models.FundMember{MemberId: 2001, FundId: 3001, Email: "jane@example.com", LinkedUid: 1003}
+
+	_, err := service.CreateInvitation(nil, 1001, member, 1002)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "member already linked to user")
+}
+
+func TestFundInvitationService_RevokeInvitation_InvalidParameters(t *testing.T) {
+	service := &FundInvitationService{}
+
+	err := service.RevokeInvitation(nil, 0, 2001, 3001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.RevokeInvitation(nil, 1001, 0, 3001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.RevokeInvitation(nil, 1001, 2001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund invitation id is invalid")
+}
+
+func TestFundInvitationService_AcceptInvitation_InvalidParameters(t *testing.T) {
+	service := &FundInvitationService{}
+
+	_, err := service.AcceptInvitation(nil, 0, 3001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.AcceptInvitation(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund invitation id is invalid")
+}
+
+func TestFundInvitationService_DeclineInvitation_InvalidParameters(t *testing.T) {
+	service := &FundInvitationService{}
+
+	err := service.DeclineInvitation(nil, 0, 3001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.DeclineInvitation(nil, 1001, -1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund invitation id is invalid")
+}
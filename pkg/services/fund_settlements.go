@@ -0,0 +1,234 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// FundSettlementService represents fund settlement service
+type FundSettlementService struct {
+	ServiceUsingDB
+}
+
+// Initialize a fund settlement service singleton instance
+var (
+	FundSettlements = &FundSettlementService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetLastSettledTime returns the settled time of the latest settlement of a fund, or zero if the fund has never been settled
+func (s *FundSettlementService) GetLastSettledTime(c core.Context, uid int64, fundId int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return 0, errs.ErrFundIdInvalid
+	}
+
+	settlement := &models.FundSettlement{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("fund_id=?", fundId).OrderBy("settled_time desc").Limit(1).Get(settlement)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, nil
+	}
+
+	return settlement.SettledTime, nil
+}
+
+// GetSharedExpenses returns all expenses of a fund which are linked to fund members and have not been settled yet until the specified time (inclusive)
+func (s *FundSettlementService) GetSharedExpenses(c core.Context, uid int64, fundId int64, endTime int64) ([]*models.FundSharedExpense, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	var members []*models.FundMember
+	err := s.UserDataDB(uid).NewSession(c).Where("fund_id=?", fundId).Find(&members)
+
+	if err != nil {
+		return nil, err
+	}
+
+	memberIdsByLinkedUid := make(map[int64]int64, len(members))
+
+	for _, member := range members {
+		if member.LinkedUid > 0 {
+			memberIdsByLinkedUid[member.LinkedUid] = member.MemberId
+		}
+	}
+
+	var transactions []*models.Transaction
+	err = s.UserDataDB(uid).NewSession(c).
+		Where("fund_id=? AND deleted=? AND type=? AND settled_time=? AND transaction_time<=?", fundId, false, models.TRANSACTION_DB_TYPE_EXPENSE, 0, utils.GetMaxTransactionTimeFromUnixTime(endTime)).
+		OrderBy("transaction_time asc").
+		Find(&transactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transactions) < 1 {
+		return make([]*models.FundSharedExpense, 0), nil
+	}
+
+	transactionIds := make([]int64, len(transactions))
+	accountIds := make([]int64, len(transactions))
+
+	for i, transaction := range transactions {
+		transactionIds[i] = transaction.TransactionId
+		accountIds[i] = transaction.AccountId
+	}
+
+	var transactionMembers []*models.TransactionMember
+	err = s.UserDataDB(uid).NewSession(c).In("transaction_id", transactionIds).Find(&transactionMembers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	membersByTransactionId := make(map[int64][]*models.TransactionMember)
+
+	for _, transactionMember := range transactionMembers {
+		membersByTransactionId[transactionMember.TransactionId] = append(membersByTransactionId[transactionMember.TransactionId], transactionMember)
+	}
+
+	var accounts []*models.Account
+	err = s.UserDataDB(uid).NewSession(c).In("account_id", utils.ToUniqueInt64Slice(accountIds)).Find(&accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesByAccountId := make(map[int64]string, len(accounts))
+
+	for _, account := range accounts {
+		currenciesByAccountId[account.AccountId] = account.Currency
+	}
+
+	expenses := make([]*models.FundSharedExpense, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		members, exists := membersByTransactionId[transaction.TransactionId]
+
+		if !exists {
+			continue
+		}
+
+		payerMemberId, exists := memberIdsByLinkedUid[transaction.Uid]
+
+		if !exists {
+			log.Warnf(c, "[fund_settlements.GetSharedExpenses] transaction \"id:%d\" is skipped, because its creator \"uid:%d\" is not a member of fund \"id:%d\"", transaction.TransactionId, transaction.Uid, fundId)
+			continue
+		}
+
+		currency, exists := currenciesByAccountId[transaction.AccountId]
+
+		if !exists {
+			log.Warnf(c, "[fund_settlements.GetSharedExpenses] transaction \"id:%d\" is skipped, because account \"id:%d\" does not exist", transaction.TransactionId, transaction.AccountId)
+			continue
+		}
+
+		memberAmounts, err := models.CalculateTransactionMemberAmounts(transaction.Amount, members)
+
+		if err != nil {
+			log.Warnf(c, "[fund_settlements.GetSharedExpenses] transaction \"id:%d\" is skipped, because its member split is invalid, %s", transaction.TransactionId, err.Error())
+			continue
+		}
+
+		expenses = append(expenses, &models.FundSharedExpense{
+			TransactionId: transaction.TransactionId,
+			PayerMemberId: payerMemberId,
+			Currency:      currency,
+			Amount:        transaction.Amount,
+			MemberAmounts: memberAmounts,
+		})
+	}
+
+	return expenses, nil
+}
+
+// CreateSettlement saves a new fund settlement with its repayment transactions, and marks the specified shared expenses as settled in the same database transaction
+func (s *FundSettlementService) CreateSettlement(c core.Context, settlement *models.FundSettlement, repaymentTransactions []*models.Transaction, settledTransactionIds []int64) error {
+	if settlement.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if settlement.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if settlement.SettledTime <= 0 || settlement.SettledTime > time.Now().Unix() {
+		return errs.ErrFundSettlementTimeInvalid
+	}
+
+	settlement.CreatedUnixTime = time.Now().Unix()
+
+	saveSettlement := func(sess *xorm.Session) error {
+		exists, err := sess.Where("fund_id=? AND settled_time>=?", settlement.FundId, settlement.SettledTime).Exist(&models.FundSettlement{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrFundSettlementTimeInvalid
+		}
+
+		transactionIds := make([]string, len(repaymentTransactions))
+
+		for i := 0; i < len(repaymentTransactions); i++ {
+			transactionIds[i] = utils.Int64ToString(repaymentTransactions[i].TransactionId)
+		}
+
+		settlement.TransactionIds = strings.Join(transactionIds, ",")
+
+		_, err = sess.Insert(settlement)
+
+		if err != nil {
+			log.Errorf(c, "[fund_settlements.CreateSettlement] failed to insert settlement of fund \"id:%d\", because %s", settlement.FundId, err.Error())
+			return err
+		}
+
+		if len(settledTransactionIds) < 1 {
+			return nil
+		}
+
+		updateModel := &models.Transaction{
+			SettledTime: settlement.SettledTime,
+		}
+
+		updatedRows, err := sess.Cols("settled_time").Where("fund_id=? AND deleted=? AND settled_time=?", settlement.FundId, false, 0).In("transaction_id", settledTransactionIds).Update(updateModel)
+
+		if err != nil {
+			log.Errorf(c, "[fund_settlements.CreateSettlement] failed to mark shared expenses of fund \"id:%d\" as settled, because %s", settlement.FundId, err.Error())
+			return err
+		} else if updatedRows != int64(len(settledTransactionIds)) {
+			// Some expenses have been deleted or settled by others after the balances were calculated
+			return errs.ErrFundSettlementRepaymentsMismatch
+		}
+
+		return nil
+	}
+
+	if len(repaymentTransactions) < 1 {
+		return s.UserDataDB(settlement.Uid).DoTransaction(c, saveSettlement)
+	}
+
+	return Transactions.batchCreateTransactions(c, settlement.Uid, repaymentTransactions, nil, nil, false, nil, saveSettlement)
+}
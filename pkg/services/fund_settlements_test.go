@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestFundSettlementService_GetLastSettledTime_InvalidParameters(t *testing.T) {
+	service := &FundSettlementService{}
+
+	_, err := service.GetLastSettledTime(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetLastSettledTime(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestFundSettlementService_GetSharedExpenses_InvalidParameters(t *testing.T) {
+	service := &FundSettlementService{}
+
+	_, err := service.GetSharedExpenses(nil, 0, 1001, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetSharedExpenses(nil, 1001, 0, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestFundSettlementService_CreateSettlement_InvalidParameters(t *testing.T) {
+	service := &FundSettlementService{}
+
+	err := service.CreateSettlement(nil, &models.FundSettlement{Uid: 0, FundId: 1001, SettledTime: 1}, nil, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreateSettlement(nil, &models.FundSettlement{Uid: 1001, FundId: 0, SettledTime: 1}, nil, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.CreateSettlement(nil, &models.FundSettlement{Uid: 1001, FundId: 1001, SettledTime: 0}, nil, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "settlement time")
+
+	err = service.CreateSettlement(nil, &models.FundSettlement{Uid: 1001, FundId: 1001, SettledTime: time.Now().Unix() + 3600}, nil, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "settlement time")
+}
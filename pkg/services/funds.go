@@ -185,7 +185,7 @@ func (s *FundService) createFundInternal(c core.Context, fund *models.Fund) erro
 			return err
 		}
 
-		return nil
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fund.FundId, fund.OwnerUid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND, fund.FundId, models.FUND_ACTIVITY_ACTION_CREATE, nil, fund, fundActivityFields))
 	})
 }
 
@@ -207,17 +207,32 @@ func (s *FundService) ModifyFund(c core.Context, uid int64, fund *models.Fund) e
 	now := time.Now().Unix()
 	fund.UpdatedUnixTime = now
 
-	updatedRows, err := s.UserDataDB(uid).NewSession(c).Where("fund_id=? AND deleted=?", fund.FundId, false).
-		Cols("name", "default_currency", "updated_unix_time").Update(fund)
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldFund := &models.Fund{}
+		has, err := sess.ID(fund.FundId).Where("deleted=?", false).Get(oldFund)
 
-	if err != nil {
-		log.Errorf(c, "[funds.ModifyFund] failed to update fund \"fund_id:%d\", because %s", fund.FundId, err.Error())
-		return err
-	} else if updatedRows < 1 {
-		return errs.ErrFundNotFound
-	}
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrFundNotFound
+		}
+
+		updatedRows, err := sess.Where("fund_id=? AND deleted=?", fund.FundId, false).
+			Cols("name", "default_currency", "updated_unix_time").Update(fund)
 
-	return nil
+		if err != nil {
+			log.Errorf(c, "[funds.ModifyFund] failed to update fund \"fund_id:%d\", because %s", fund.FundId, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrFundNotFound
+		}
+
+		newFund := *oldFund
+		newFund.Name = fund.Name
+		newFund.DefaultCurrency = fund.DefaultCurrency
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fund.FundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND, fund.FundId, models.FUND_ACTIVITY_ACTION_MODIFY, oldFund, &newFund, fundActivityFields))
+	})
 }
 
 // DeleteFund deletes an existing fund
@@ -238,6 +253,15 @@ func (s *FundService) DeleteFund(c core.Context, uid int64, fundId int64) error
 	now := time.Now().Unix()
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldFund := &models.Fund{}
+		has, err := sess.ID(fundId).Where("deleted=?", false).Get(oldFund)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrFundNotFound
+		}
+
 		// Soft delete fund
 		updatedRows, err := sess.Where("fund_id=? AND deleted=?", fundId, false).
 			Cols("deleted", "deleted_unix_time", "updated_unix_time").
@@ -257,7 +281,7 @@ func (s *FundService) DeleteFund(c core.Context, uid int64, fundId int64) error
 		// Note: We don't delete fund members or financial data here
 		// They should be handled separately if needed
 
-		return nil
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND, fundId, models.FUND_ACTIVITY_ACTION_DELETE, oldFund, nil, fundActivityFields))
 	})
 }
 
@@ -276,6 +300,12 @@ func (s *FundService) addMemberInternal(c core.Context, uid int64, fundId int64,
 		return errs.ErrFundAccessDenied
 	}
 
+	if member.Role == 0 {
+		member.Role = models.FUND_ROLE_MEMBER
+	} else if member.Role == models.FUND_ROLE_OWNER || !member.Role.IsValid() {
+		return errs.ErrInvalidFundRole
+	}
+
 	memberId := s.GenerateUuid(uuid.UUID_TYPE_FUND_MEMBER)
 	if memberId < 1 {
 		return errs.ErrSystemIsBusy
@@ -285,19 +315,20 @@ func (s *FundService) addMemberInternal(c core.Context, uid int64, fundId int64,
 
 	member.MemberId = memberId
 	member.FundId = fundId
-	member.Role = models.FUND_ROLE_MEMBER // New members are always regular members
-	member.LinkedUid = 0                  // Not linked initially
+	member.LinkedUid = 0 // Not linked initially
 	member.CreatedBy = uid
 	member.CreatedUnixTime = now
 	member.UpdatedUnixTime = now
 
-	_, err := s.UserDataDB(uid).NewSession(c).Insert(member)
-	if err != nil {
-		log.Errorf(c, "[funds.AddMember] failed to insert fund member \"member_id:%d\", because %s", member.MemberId, err.Error())
-		return err
-	}
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(member)
+		if err != nil {
+			log.Errorf(c, "[funds.AddMember] failed to insert fund member \"member_id:%d\", because %s", member.MemberId, err.Error())
+			return err
+		}
 
-	return nil
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, member.MemberId, models.FUND_ACTIVITY_ACTION_CREATE, nil, member, fundMemberActivityFields))
+	})
 }
 
 // RemoveMember removes a member from a fund
@@ -329,15 +360,17 @@ func (s *FundService) RemoveMember(c core.Context, uid int64, fundId int64, memb
 		return errs.ErrCannotRemoveOwner
 	}
 
-	deletedRows, err := s.UserDataDB(uid).NewSession(c).Where("member_id=? AND fund_id=?", memberId, fundId).Delete(&models.FundMember{})
-	if err != nil {
-		log.Errorf(c, "[funds.RemoveMember] failed to delete fund member \"member_id:%d\", because %s", memberId, err.Error())
-		return err
-	} else if deletedRows < 1 {
-		return errs.ErrMemberNotFound
-	}
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("member_id=? AND fund_id=?", memberId, fundId).Delete(&models.FundMember{})
+		if err != nil {
+			log.Errorf(c, "[funds.RemoveMember] failed to delete fund member \"member_id:%d\", because %s", memberId, err.Error())
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrMemberNotFound
+		}
 
-	return nil
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, memberId, models.FUND_ACTIVITY_ACTION_DELETE, member, nil, fundMemberActivityFields))
+	})
 }
 
 // linkMemberToUserInternal links a fund member to an existing user
@@ -367,21 +400,237 @@ func (s *FundService) linkMemberToUserInternal(c core.Context, uid int64, member
 
 	now := time.Now().Unix()
 
-	updatedRows, err := s.UserDataDB(uid).NewSession(c).Where("member_id=?", memberId).
-		Cols("linked_uid", "updated_unix_time").
-		Update(&models.FundMember{
-			LinkedUid:       linkedUid,
-			UpdatedUnixTime: now,
-		})
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Where("member_id=?", memberId).
+			Cols("linked_uid", "updated_unix_time").
+			Update(&models.FundMember{
+				LinkedUid:       linkedUid,
+				UpdatedUnixTime: now,
+			})
+
+		if err != nil {
+			log.Errorf(c, "[funds.LinkMemberToUser] failed to link member \"member_id:%d\" to user \"uid:%d\", because %s", memberId, linkedUid, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrMemberNotFound
+		}
+
+		newMember := *member
+		newMember.LinkedUid = linkedUid
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(member.FundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, memberId, models.FUND_ACTIVITY_ACTION_MODIFY, member, &newMember, fundMemberActivityFields))
+	})
+}
+
+// TransferOwnership transfers the ownership of a fund to another linked member, the previous owner becomes an editor
+func (s *FundService) TransferOwnership(c core.Context, uid int64, fundId int64, memberId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrMemberIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		owner := &models.FundMember{}
+		has, err := sess.Where("fund_id=? AND linked_uid=? AND role=?", fundId, uid, models.FUND_ROLE_OWNER).Get(owner)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrFundAccessDenied
+		}
+
+		if owner.MemberId == memberId {
+			return errs.ErrCannotTransferOwnershipToSelf
+		}
+
+		newOwner := &models.FundMember{}
+		has, err = sess.Where("member_id=? AND fund_id=?", memberId, fundId).Get(newOwner)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrMemberNotFound
+		}
+
+		if newOwner.LinkedUid <= 0 {
+			return errs.ErrFundMemberNotLinked
+		}
+
+		if newOwner.LinkedUid == uid {
+			return errs.ErrCannotTransferOwnershipToSelf
+		}
+
+		updatedRows, err := sess.ID(newOwner.MemberId).Where("fund_id=? AND linked_uid=?", fundId, newOwner.LinkedUid).
+			Cols("role", "updated_unix_time").
+			Update(&models.FundMember{Role: models.FUND_ROLE_OWNER, UpdatedUnixTime: now})
+
+		if err != nil {
+			log.Errorf(c, "[funds.TransferOwnership] failed to promote member \"member_id:%d\" to owner, because %s", newOwner.MemberId, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrMemberNotFound
+		}
+
+		updatedRows, err = sess.ID(owner.MemberId).Where("fund_id=? AND role=?", fundId, models.FUND_ROLE_OWNER).
+			Cols("role", "updated_unix_time").
+			Update(&models.FundMember{Role: models.FUND_ROLE_EDITOR, UpdatedUnixTime: now})
+
+		if err != nil {
+			log.Errorf(c, "[funds.TransferOwnership] failed to demote previous owner \"member_id:%d\", because %s", owner.MemberId, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrFundAccessDenied
+		}
+
+		updatedRows, err = sess.ID(fundId).Where("deleted=?", false).
+			Cols("owner_uid", "updated_unix_time").
+			Update(&models.Fund{OwnerUid: newOwner.LinkedUid, UpdatedUnixTime: now})
+
+		if err != nil {
+			log.Errorf(c, "[funds.TransferOwnership] failed to update owner of fund \"fund_id:%d\", because %s", fundId, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrFundNotFound
+		}
+
+		ownerCount, err := sess.Where("fund_id=? AND role=?", fundId, models.FUND_ROLE_OWNER).Count(&models.FundMember{})
+
+		if err != nil {
+			return err
+		} else if ownerCount != 1 {
+			log.Errorf(c, "[funds.TransferOwnership] fund \"fund_id:%d\" would have %d owners after transferring ownership", fundId, ownerCount)
+			return errs.ErrOperationFailed
+		}
+
+		updatedOwner := *owner
+		updatedOwner.Role = models.FUND_ROLE_EDITOR
+		err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, owner.MemberId, models.FUND_ACTIVITY_ACTION_MODIFY, owner, &updatedOwner, fundMemberActivityFields))
+
+		if err != nil {
+			return err
+		}
+
+		updatedNewOwner := *newOwner
+		updatedNewOwner.Role = models.FUND_ROLE_OWNER
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, newOwner.MemberId, models.FUND_ACTIVITY_ACTION_MODIFY, newOwner, &updatedNewOwner, fundMemberActivityFields))
+	})
+}
+
+// LeaveFund unlinks the current user from a fund, the member and its transaction links are kept for history
+func (s *FundService) LeaveFund(c core.Context, uid int64, fundId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		member := &models.FundMember{}
+		has, err := sess.Where("fund_id=? AND linked_uid=?", fundId, uid).Get(member)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrFundAccessDenied
+		}
+
+		if member.Role == models.FUND_ROLE_OWNER {
+			return errs.ErrFundOwnerCannotLeave
+		}
+
+		updatedRows, err := sess.ID(member.MemberId).Where("linked_uid=? AND role<>?", uid, models.FUND_ROLE_OWNER).
+			Cols("linked_uid", "updated_unix_time").
+			Update(&models.FundMember{LinkedUid: 0, UpdatedUnixTime: now})
+
+		if err != nil {
+			log.Errorf(c, "[funds.LeaveFund] failed to unlink member \"member_id:%d\" from user \"uid:%d\", because %s", member.MemberId, uid, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrMemberNotFound
+		}
+
+		unlinkedMember := *member
+		unlinkedMember.LinkedUid = 0
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, member.MemberId, models.FUND_ACTIVITY_ACTION_MODIFY, member, &unlinkedMember, fundMemberActivityFields))
+	})
+}
+
+// ModifyMemberRole promotes or demotes a non-owner member of a fund
+func (s *FundService) ModifyMemberRole(c core.Context, uid int64, fundId int64, memberId int64, role models.FundRole) (*models.FundMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if memberId <= 0 {
+		return nil, errs.ErrMemberIdInvalid
+	}
+
+	if role == models.FUND_ROLE_OWNER || !role.IsValid() {
+		return nil, errs.ErrInvalidFundRole
+	}
+
+	// Check if user can modify this fund
+	if !s.canUserModifyFund(c, uid, fundId) {
+		return nil, errs.ErrFundAccessDenied
+	}
+
+	member := &models.FundMember{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.Where("member_id=? AND fund_id=?", memberId, fundId).Get(member)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrMemberNotFound
+		}
+
+		if member.Role == models.FUND_ROLE_OWNER {
+			return errs.ErrCannotChangeOwnerRole
+		}
+
+		oldMember := *member
+		member.Role = role
+		member.UpdatedUnixTime = time.Now().Unix()
+
+		updatedRows, err := sess.ID(memberId).Where("fund_id=? AND role<>?", fundId, models.FUND_ROLE_OWNER).
+			Cols("role", "updated_unix_time").
+			Update(member)
+
+		if err != nil {
+			log.Errorf(c, "[funds.ModifyMemberRole] failed to update role of member \"member_id:%d\", because %s", memberId, err.Error())
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrMemberNotFound
+		}
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_FUND_MEMBER, memberId, models.FUND_ACTIVITY_ACTION_MODIFY, &oldMember, member, fundMemberActivityFields))
+	})
 
 	if err != nil {
-		log.Errorf(c, "[funds.LinkMemberToUser] failed to link member \"member_id:%d\" to user \"uid:%d\", because %s", memberId, linkedUid, err.Error())
-		return err
-	} else if updatedRows < 1 {
-		return errs.ErrMemberNotFound
+		return nil, err
 	}
 
-	return nil
+	return member, nil
 }
 
 // canUserAccessFund checks if user has access to a fund (either as owner or member)
@@ -428,6 +677,23 @@ func (s *FundService) GetUserRoleInFund(c core.Context, uid int64, fundId int64)
 	return member.Role, nil
 }
 
+// GetUserPermissionInFund returns the user's permission for performing the action on the resource in a specific fund
+func (s *FundService) GetUserPermissionInFund(c core.Context, uid int64, fundId int64, resource models.FundResource, action models.FundAction) (models.FundPermission, error) {
+	role, err := s.GetUserRoleInFund(c, uid, fundId)
+
+	if err != nil {
+		return models.FUND_PERMISSION_NONE, err
+	}
+
+	permission := role.GetPermission(resource, action)
+
+	if !permission.IsAllowed() {
+		return models.FUND_PERMISSION_NONE, errs.ErrFundPermissionDenied
+	}
+
+	return permission, nil
+}
+
 // API wrapper methods
 
 // GetUserFunds returns all funds that user has access to with role and member count
@@ -478,6 +744,7 @@ func (s *FundService) AddMember(c core.Context, uid int64, fundId int64, req *mo
 	member := &models.FundMember{
 		Name:  req.Name,
 		Email: req.Email,
+		Role:  req.Role,
 	}
 
 	err := s.addMemberInternal(c, uid, fundId, member)
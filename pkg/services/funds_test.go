@@ -32,6 +32,24 @@ func TestFundService_GetUserRoleInFund_InvalidFundId(t *testing.T) {
 	assert.Contains(t, err.Error(), "fund id is invalid")
 }
 
+func TestFundService_GetUserPermissionInFund_InvalidUserId(t *testing.T) {
+	fundService := &FundService{}
+
+	permission, err := fundService.GetUserPermissionInFund(nil, 0, 1001, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+	assert.Equal(t, models.FUND_PERMISSION_NONE, permission)
+}
+
+func TestFundService_GetUserPermissionInFund_InvalidFundId(t *testing.T) {
+	fundService := &FundService{}
+
+	permission, err := fundService.GetUserPermissionInFund(nil, 1001, 0, models.FUND_RESOURCE_TRANSACTION, models.FUND_ACTION_READ)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+	assert.Equal(t, models.FUND_PERMISSION_NONE, permission)
+}
+
 func TestFundService_GetFundByFundId_InvalidUserId(t *testing.T) {
 	fundService := &FundService{}
 
@@ -238,3 +256,59 @@ func TestFundService_LinkMemberToUser_InvalidLinkedUserId(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "user id is invalid")
 }
+
+func TestFundService_TransferOwnership_InvalidParameters(t *testing.T) {
+	fundService := &FundService{}
+
+	err := fundService.TransferOwnership(nil, 0, 1001, 2001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = fundService.TransferOwnership(nil, 1001, 0, 2001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = fundService.TransferOwnership(nil, 1001, 2001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "member id is invalid")
+}
+
+func TestFundService_LeaveFund_InvalidParameters(t *testing.T) {
+	fundService := &FundService{}
+
+	err := fundService.LeaveFund(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = fundService.LeaveFund(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestFundService_ModifyMemberRole_InvalidParameters(t *testing.T) {
+	fundService := &FundService{}
+
+	_, err := fundService.ModifyMemberRole(nil, 0, 1001, 2001, models.FUND_ROLE_EDITOR)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = fundService.ModifyMemberRole(nil, 1001, 0, 2001, models.FUND_ROLE_EDITOR)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = fundService.ModifyMemberRole(nil, 1001, 2001, 0, models.FUND_ROLE_EDITOR)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "member id is invalid")
+}
+
+func TestFundService_ModifyMemberRole_InvalidRole(t *testing.T) {
+	fundService := &FundService{}
+
+	_, err := fundService.ModifyMemberRole(nil, 1001, 2001, 3001, models.FUND_ROLE_OWNER)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid fund role")
+
+	_, err = fundService.ModifyMemberRole(nil, 1001, 2001, 3001, models.FundRole(9))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid fund role")
+}
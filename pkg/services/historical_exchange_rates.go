@@ -0,0 +1,126 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// HistoricalExchangeRateService represents historical exchange rate service
+type HistoricalExchangeRateService struct {
+	ServiceUsingDB
+}
+
+// Initialize a historical exchange rate service singleton instance
+var (
+	HistoricalExchangeRates = &HistoricalExchangeRateService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRatesByDateRange returns the historical exchange rate models of the data source between the start date and the end date,
+// only the exchange rates of the specified currencies are returned if the currencies is not empty
+func (s *HistoricalExchangeRateService) GetExchangeRatesByDateRange(c core.Context, dataSource string, startDate string, endDate string, currencies []string) ([]*models.HistoricalExchangeRate, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	sess := s.UserDB().NewSession(c).Where("data_source=? AND rate_date>=? AND rate_date<=?", dataSource, startDate, endDate)
+
+	if len(currencies) > 0 {
+		sess = sess.In("currency", currencies)
+	}
+
+	var rates []*models.HistoricalExchangeRate
+	err := sess.OrderBy("rate_date asc, currency asc").Find(&rates)
+
+	return rates, err
+}
+
+// GetExchangeRatesForConversion returns the historical exchange rate models of the data source which are applicable to the dates between
+// the start date and the end date, including the latest exchange rates earlier than the start date, the start date can be empty
+func (s *HistoricalExchangeRateService) GetExchangeRatesForConversion(c core.Context, dataSource string, startDate string, endDate string, currencies []string) ([]*models.HistoricalExchangeRate, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if startDate != "" {
+		latestRate := &models.HistoricalExchangeRate{}
+		has, err := s.UserDB().NewSession(c).Where("data_source=? AND rate_date<?", dataSource, startDate).OrderBy("rate_date desc").Limit(1).Get(latestRate)
+
+		if err != nil {
+			return nil, err
+		} else if has {
+			startDate = latestRate.RateDate
+		}
+	}
+
+	return s.GetExchangeRatesByDateRange(c, dataSource, startDate, endDate, currencies)
+}
+
+// GetRateDatesByDateRange returns a map of all the dates between the start date and the end date which have stored exchange rates of the data source
+func (s *HistoricalExchangeRateService) GetRateDatesByDateRange(c core.Context, dataSource string, startDate string, endDate string) (map[string]bool, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	var rates []*models.HistoricalExchangeRate
+	err := s.UserDB().NewSession(c).Distinct("rate_date").Where("data_source=? AND rate_date>=? AND rate_date<=?", dataSource, startDate, endDate).Find(&rates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rateDates := make(map[string]bool, len(rates))
+
+	for i := 0; i < len(rates); i++ {
+		rateDates[rates[i].RateDate] = true
+	}
+
+	return rateDates, nil
+}
+
+// SaveExchangeRates saves the exchange rates of the data source applicable to the specified date to database, the existed exchange rates of the same date are replaced
+func (s *HistoricalExchangeRateService) SaveExchangeRates(c core.Context, dataSource string, rateDate string, exchangeRates *models.LatestExchangeRateResponse) error {
+	if dataSource == "" {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if _, err := utils.ParseFromLongDateFirstTime(rateDate, 0); err != nil {
+		return errs.ErrHistoricalExchangeRateDateInvalid
+	}
+
+	if exchangeRates == nil || len(exchangeRates.ExchangeRates) < 1 {
+		return errs.ErrHistoricalExchangeRateNotFound
+	}
+
+	rates := models.CreateHistoricalExchangeRates(dataSource, rateDate, exchangeRates)
+	now := time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("data_source=? AND rate_date=?", dataSource, rateDate).Delete(&models.HistoricalExchangeRate{})
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(rates); i++ {
+			rates[i].CreatedUnixTime = now
+			_, err = sess.Insert(rates[i])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
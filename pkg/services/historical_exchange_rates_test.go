@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestHistoricalExchangeRateService_SaveExchangeRates_InvalidParameters(t *testing.T) {
+	service := &HistoricalExchangeRateService{}
+	exchangeRates := &models.LatestExchangeRateResponse{
+		BaseCurrency:  "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{{Currency: "USD", Rate: "1.1746"}},
+	}
+
+	err := service.SaveExchangeRates(nil, "", "2021-04-01", exchangeRates)
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSource, err)
+
+	err = service.SaveExchangeRates(nil, "euro_central_bank", "2021/04/01", exchangeRates)
+	assert.Equal(t, errs.ErrHistoricalExchangeRateDateInvalid, err)
+
+	err = service.SaveExchangeRates(nil, "euro_central_bank", "2021-04-01", nil)
+	assert.Equal(t, errs.ErrHistoricalExchangeRateNotFound, err)
+
+	err = service.SaveExchangeRates(nil, "euro_central_bank", "2021-04-01", &models.LatestExchangeRateResponse{BaseCurrency: "EUR"})
+	assert.Equal(t, errs.ErrHistoricalExchangeRateNotFound, err)
+}
+
+func TestHistoricalExchangeRateService_GetExchangeRates_InvalidParameters(t *testing.T) {
+	service := &HistoricalExchangeRateService{}
+
+	_, err := service.GetExchangeRatesByDateRange(nil, "", "2021-04-01", "2021-04-30", nil)
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSource, err)
+
+	_, err = service.GetExchangeRatesForConversion(nil, "", "2021-04-01", "2021-04-30", nil)
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSource, err)
+
+	_, err = service.GetRateDatesByDateRange(nil, "", "2021-04-01", "2021-04-30")
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSource, err)
+}
@@ -0,0 +1,532 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// InvestmentService represents investment service
+type InvestmentService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an investment service singleton instance
+var (
+	Investments = &InvestmentService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllSecuritiesByUid returns all security models of user
+func (s *InvestmentService) GetAllSecuritiesByUid(c core.Context, uid int64, fundId int64) ([]*models.Security, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	var securities []*models.Security
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).OrderBy("symbol asc").Find(&securities)
+
+	return securities, err
+}
+
+// GetSecurityById returns a security model according to security id
+func (s *InvestmentService) GetSecurityById(c core.Context, uid int64, fundId int64, securityId int64) (*models.Security, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if securityId <= 0 {
+		return nil, errs.ErrSecurityIdInvalid
+	}
+
+	return s.getSecurity(s.UserDataDB(uid).NewSession(c), uid, fundId, securityId)
+}
+
+// CreateSecurity saves a new security model to database
+func (s *InvestmentService) CreateSecurity(c core.Context, security *models.Security) error {
+	if security.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if security.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if !security.CostBasisMethod.IsValid() {
+		return errs.ErrSecurityCostBasisMethodInvalid
+	}
+
+	security.SecurityId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if security.SecurityId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	security.Symbol = strings.TrimSpace(security.Symbol)
+	security.Deleted = false
+	security.CreatedUnixTime = time.Now().Unix()
+	security.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(security.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isSecuritySymbolAvailable(sess, security)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(security)
+
+		return err
+	})
+}
+
+// ModifySecurity saves an existed security model to database, the currency cannot be changed if the security has trades
+func (s *InvestmentService) ModifySecurity(c core.Context, security *models.Security) error {
+	if security.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if security.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if !security.CostBasisMethod.IsValid() {
+		return errs.ErrSecurityCostBasisMethodInvalid
+	}
+
+	security.Symbol = strings.TrimSpace(security.Symbol)
+	security.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(security.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldSecurity, err := s.getSecurity(sess, security.Uid, security.FundId, security.SecurityId)
+
+		if err != nil {
+			return err
+		}
+
+		err = s.isSecuritySymbolAvailable(sess, security)
+
+		if err != nil {
+			return err
+		}
+
+		if oldSecurity.Currency != security.Currency {
+			exists, err := sess.Where("uid=? AND deleted=? AND fund_id=? AND security_id=?", security.Uid, false, security.FundId, security.SecurityId).Limit(1).Exist(&models.InvestmentTrade{})
+
+			if err != nil {
+				return err
+			} else if exists {
+				return errs.ErrSecurityCurrencyCannotBeChangedWhenInUse
+			}
+		}
+
+		updatedRows, err := sess.ID(security.SecurityId).Cols("symbol", "name", "currency", "cost_basis_method", "updated_unix_time").Where("uid=? AND deleted=? AND fund_id=?", security.Uid, false, security.FundId).Update(security)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrSecurityNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteSecurity deletes an existed security from database, the security cannot be deleted if it has trades
+func (s *InvestmentService) DeleteSecurity(c core.Context, uid int64, fundId int64, securityId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if securityId <= 0 {
+		return errs.ErrSecurityIdInvalid
+	}
+
+	updateModel := &models.Security{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND deleted=? AND fund_id=? AND security_id=?", uid, false, fundId, securityId).Limit(1).Exist(&models.InvestmentTrade{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrSecurityInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(securityId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrSecurityNotFound
+		}
+
+		_, err = sess.Where("uid=? AND security_id=?", uid, securityId).Delete(&models.SecurityPrice{})
+
+		return err
+	})
+}
+
+// GetTrades returns all trade models of the specified account and security ordered by trade time,
+// zero account id or security id means all accounts or securities
+func (s *InvestmentService) GetTrades(c core.Context, uid int64, fundId int64, accountId int64, securityId int64) ([]*models.InvestmentTrade, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	return s.getTrades(s.UserDataDB(uid).NewSession(c), uid, fundId, accountId, securityId)
+}
+
+// CreateTrade saves a new trade model and the transaction which changes the balance of account by the trade to database in one
+// database transaction, the trade is rejected if it sells more shares than the account holds at the trade time
+func (s *InvestmentService) CreateTrade(c core.Context, trade *models.InvestmentTrade, categoryId int64, utcOffset int16, clientIp string) error {
+	if trade.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if trade.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	err := models.ValidateInvestmentTrade(trade)
+
+	if err != nil {
+		return err
+	}
+
+	transaction, err := trade.ToLedgerTransaction(categoryId, utcOffset)
+
+	if err != nil {
+		return err
+	}
+
+	transaction.CreatedIp = clientIp
+
+	trade.TradeId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if trade.TradeId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	trade.Deleted = false
+	trade.CreatedUnixTime = time.Now().Unix()
+	trade.UpdatedUnixTime = time.Now().Unix()
+
+	return Transactions.batchCreateTransactions(c, trade.Uid, []*models.Transaction{transaction}, nil, nil, false, nil, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(trade.AccountId).Where("uid=? AND deleted=? AND fund_id=?", trade.Uid, false, trade.FundId).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if account.Category != models.ACCOUNT_CATEGORY_INVESTMENT || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			return errs.ErrInvestmentAccountInvalid
+		}
+
+		security, err := s.getSecurity(sess, trade.Uid, trade.FundId, trade.SecurityId)
+
+		if err != nil {
+			return err
+		}
+
+		if security.Currency != account.Currency {
+			return errs.ErrSecurityCurrencyNotMatchAccount
+		}
+
+		trades, err := s.getTrades(sess, trade.Uid, trade.FundId, trade.AccountId, trade.SecurityId)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = models.BuildInvestmentHoldings(append(trades, trade), map[int64]*models.Security{security.SecurityId: security})
+
+		if err != nil {
+			return err
+		}
+
+		trade.TransactionId = transaction.TransactionId
+		_, err = sess.Insert(trade)
+
+		return err
+	})
+}
+
+// DeleteTrade deletes an existed trade and the linked transaction from database in one database transaction, the trade cannot be
+// deleted if the later sells would exceed the holding, and the linked transaction which has already been deleted is skipped
+func (s *InvestmentService) DeleteTrade(c core.Context, uid int64, fundId int64, tradeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if tradeId <= 0 {
+		return errs.ErrInvestmentTradeIdInvalid
+	}
+
+	updateModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		trade := &models.InvestmentTrade{}
+		has, err := sess.ID(tradeId).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Get(trade)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		trades, err := s.getTrades(sess, uid, fundId, trade.AccountId, trade.SecurityId)
+
+		if err != nil {
+			return err
+		}
+
+		remainingTrades := make([]*models.InvestmentTrade, 0, len(trades))
+
+		for _, existedTrade := range trades {
+			if existedTrade.TradeId != tradeId {
+				remainingTrades = append(remainingTrades, existedTrade)
+			}
+		}
+
+		_, err = models.BuildInvestmentHoldings(remainingTrades, nil)
+
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err := sess.ID(tradeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		// The trade must be deleted before its transaction, otherwise the transaction of investment trade cannot be deleted
+		if trade.TransactionId > 0 {
+			err = Transactions.doDeleteTransaction(c, sess, uid, uid, trade.TransactionId, false)
+
+			// The transaction may have been deleted along with all transactions of user
+			if err == errs.ErrTransactionNotFound {
+				log.Warnf(c, "[investments.DeleteTrade] transaction \"id:%d\" of trade \"id:%d\" does not exist", trade.TransactionId, tradeId)
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetSecurityPrices returns all price models of the specified security ordered by date descending
+func (s *InvestmentService) GetSecurityPrices(c core.Context, uid int64, fundId int64, securityId int64) ([]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if securityId <= 0 {
+		return nil, errs.ErrSecurityIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	_, err := s.getSecurity(sess, uid, fundId, securityId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []*models.SecurityPrice
+	err = sess.Where("uid=? AND security_id=?", uid, securityId).OrderBy("price_date desc").Find(&prices)
+
+	return prices, err
+}
+
+// GetLatestSecurityPrices returns a map of the latest price not later than the specified date of each security,
+// the key of map is security id
+func (s *InvestmentService) GetLatestSecurityPrices(c core.Context, uid int64, securityIds []int64, maxPriceDate string) (map[int64]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	prices := make(map[int64]*models.SecurityPrice, len(securityIds))
+
+	for _, securityId := range utils.ToUniqueInt64Slice(securityIds) {
+		price := &models.SecurityPrice{}
+		has, err := sess.Where("uid=? AND security_id=? AND price_date<=?", uid, securityId, maxPriceDate).OrderBy("price_date desc").Limit(1).Get(price)
+
+		if err != nil {
+			return nil, err
+		} else if has {
+			prices[securityId] = price
+		}
+	}
+
+	return prices, nil
+}
+
+// SetSecurityPrices saves the prices of the specified security to database, the existed prices on the same dates are replaced
+func (s *InvestmentService) SetSecurityPrices(c core.Context, uid int64, fundId int64, securityId int64, prices []*models.SecurityPrice) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if securityId <= 0 {
+		return errs.ErrSecurityIdInvalid
+	}
+
+	if len(prices) > models.MaximumSecurityPricesCountOfImport {
+		return errs.ErrTooManySecurityPricesToImport
+	}
+
+	priceDates := make([]string, len(prices))
+
+	for i, price := range prices {
+		err := models.ValidateSecurityPriceDate(price.PriceDate)
+
+		if err != nil {
+			return err
+		}
+
+		priceDates[i] = price.PriceDate
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := s.getSecurity(sess, uid, fundId, securityId)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=? AND security_id=?", uid, securityId).In("price_date", priceDates).Delete(&models.SecurityPrice{})
+
+		if err != nil {
+			return err
+		}
+
+		savedPriceDates := make(map[string]bool, len(prices))
+
+		// the later price of the same date in request overrides the earlier one
+		for i := len(prices) - 1; i >= 0; i-- {
+			price := prices[i]
+
+			if savedPriceDates[price.PriceDate] {
+				continue
+			}
+
+			price.SecurityId = securityId
+			price.Uid = uid
+			price.CreatedUnixTime = now
+			price.UpdatedUnixTime = now
+
+			_, err = sess.Insert(price)
+
+			if err != nil {
+				return err
+			}
+
+			savedPriceDates[price.PriceDate] = true
+		}
+
+		return nil
+	})
+}
+
+func (s *InvestmentService) getSecurity(sess *xorm.Session, uid int64, fundId int64, securityId int64) (*models.Security, error) {
+	security := &models.Security{}
+	has, err := sess.ID(securityId).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Get(security)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrSecurityNotFound
+	}
+
+	return security, nil
+}
+
+func (s *InvestmentService) getTrades(sess *xorm.Session, uid int64, fundId int64, accountId int64, securityId int64) ([]*models.InvestmentTrade, error) {
+	sess = sess.Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId)
+
+	if accountId > 0 {
+		sess = sess.And("account_id=?", accountId)
+	}
+
+	if securityId > 0 {
+		sess = sess.And("security_id=?", securityId)
+	}
+
+	var trades []*models.InvestmentTrade
+	err := sess.OrderBy("trade_time asc, trade_id asc").Find(&trades)
+
+	return trades, err
+}
+
+func (s *InvestmentService) isSecuritySymbolAvailable(sess *xorm.Session, security *models.Security) error {
+	exists, err := sess.Where("uid=? AND deleted=? AND fund_id=? AND symbol=? AND security_id<>?", security.Uid, false, security.FundId, security.Symbol, security.SecurityId).Exist(&models.Security{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrSecuritySymbolAlreadyExists
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestInvestmentService_GetSecurityById_InvalidParameters(t *testing.T) {
+	service := &InvestmentService{}
+
+	_, err := service.GetSecurityById(nil, 0, 1001, 1001)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, err = service.GetSecurityById(nil, 1001, 0, 1001)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	_, err = service.GetSecurityById(nil, 1001, 1001, 0)
+	assert.Equal(t, errs.ErrSecurityIdInvalid, err)
+}
+
+func TestInvestmentService_CreateSecurity_InvalidParameters(t *testing.T) {
+	service := &InvestmentService{}
+
+	err := service.CreateSecurity(nil, &models.Security{Uid: 0, FundId: 1001, CostBasisMethod: models.INVESTMENT_COST_BASIS_METHOD_FIFO})
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.CreateSecurity(nil, &models.Security{Uid: 1001, FundId: 0, CostBasisMethod: models.INVESTMENT_COST_BASIS_METHOD_FIFO})
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.CreateSecurity(nil, &models.Security{Uid: 1001, FundId: 1001, CostBasisMethod: 0})
+	assert.Equal(t, errs.ErrSecurityCostBasisMethodInvalid, err)
+}
+
+func TestInvestmentService_CreateTrade_InvalidParameters(t *testing.T) {
+	service := &InvestmentService{}
+
+	err := service.CreateTrade(nil, &models.InvestmentTrade{Uid: 0, FundId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, Quantity: 10000}, 4001, 0, "")
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.CreateTrade(nil, &models.InvestmentTrade{Uid: 1001, FundId: 0, Type: models.INVESTMENT_TRADE_TYPE_BUY, Quantity: 10000}, 4001, 0, "")
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.CreateTrade(nil, &models.InvestmentTrade{Uid: 1001, FundId: 1001, Type: models.INVESTMENT_TRADE_TYPE_BUY, Quantity: 0}, 4001, 0, "")
+	assert.Equal(t, errs.ErrInvestmentTradeQuantityInvalid, err)
+}
+
+func TestInvestmentService_DeleteTrade_InvalidParameters(t *testing.T) {
+	service := &InvestmentService{}
+
+	err := service.DeleteTrade(nil, 0, 1001, 1001)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.DeleteTrade(nil, 1001, 0, 1001)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.DeleteTrade(nil, 1001, 1001, 0)
+	assert.Equal(t, errs.ErrInvestmentTradeIdInvalid, err)
+}
+
+func TestInvestmentService_DeleteTrade_TransactionOfTrade(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Balance: -1000},
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 1000},
+		&models.InvestmentTrade{TradeId: 5001, Uid: 1001, FundId: 4001, AccountId: 2001, SecurityId: 6001, Type: models.INVESTMENT_TRADE_TYPE_BUY, Quantity: 10, UnitPrice: 100, Amount: 1000, TransactionId: 3001},
+	)
+
+	err := Transactions.DeleteTransaction(c, 1001, 1001, 3001, false)
+	assert.Equal(t, errs.ErrCannotDeleteInvestmentTradeTransaction, err)
+
+	err = Transactions.ModifyTransaction(c, &models.Transaction{TransactionId: 3001, Uid: 1001, AccountId: 2001, Amount: 900}, 1001, 0, nil, nil, nil, nil, nil, nil, nil, false)
+	assert.Equal(t, errs.ErrCannotModifyInvestmentTradeTransaction, err)
+
+	err = Investments.DeleteTrade(c, 1001, 4001, 5001)
+	assert.Nil(t, err)
+
+	_, err = Transactions.GetTransactionByTransactionId(c, 1001, 3001)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	account := &models.Account{}
+	_, err = datastore.Container.UserDataStore.Choose(1001).NewSession(c).ID(2001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), account.Balance)
+}
+
+func TestInvestmentService_DeleteTrade_TransactionAlreadyDeleted(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT},
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 1000, Deleted: true},
+		&models.InvestmentTrade{TradeId: 5001, Uid: 1001, FundId: 4001, AccountId: 2001, SecurityId: 6001, Type: models.INVESTMENT_TRADE_TYPE_BUY, Quantity: 10, UnitPrice: 100, Amount: 1000, TransactionId: 3001},
+	)
+
+	err := Investments.DeleteTrade(c, 1001, 4001, 5001)
+	assert.Nil(t, err)
+
+	trades, err := Investments.GetTrades(c, 1001, 4001, 2001, 6001)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(trades))
+}
+
+func TestInvestmentService_SetSecurityPrices_InvalidParameters(t *testing.T) {
+	service := &InvestmentService{}
+
+	err := service.SetSecurityPrices(nil, 0, 1001, 1001, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.SetSecurityPrices(nil, 1001, 1001, 0, nil)
+	assert.Equal(t, errs.ErrSecurityIdInvalid, err)
+
+	err = service.SetSecurityPrices(nil, 1001, 1001, 1001, make([]*models.SecurityPrice, models.MaximumSecurityPricesCountOfImport+1))
+	assert.Equal(t, errs.ErrTooManySecurityPricesToImport, err)
+
+	err = service.SetSecurityPrices(nil, 1001, 1001, 1001, []*models.SecurityPrice{{PriceDate: "2024/05/01", Price: 100}})
+	assert.Equal(t, errs.ErrSecurityPriceDateInvalid, err)
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// PayeeService represents payee service
+type PayeeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a payee service singleton instance
+var (
+	Payees = &PayeeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllPayeesByUid returns all payee models of user
+func (s *PayeeService) GetAllPayeesByUid(c core.Context, uid int64, fundId int64) ([]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	var payees []*models.Payee
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&payees)
+
+	return payees, err
+}
+
+// GetPayeeByPayeeId returns a payee model according to payee id
+func (s *PayeeService) GetPayeeByPayeeId(c core.Context, uid int64, fundId int64, payeeId int64) (*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if payeeId <= 0 {
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	payee := &models.Payee{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(payeeId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Get(payee)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrPayeeNotFound
+	}
+
+	return payee, nil
+}
+
+// GetPayeesByPayeeIds returns payee models according to payee ids
+func (s *PayeeService) GetPayeesByPayeeIds(c core.Context, uid int64, payeeIds []int64) (map[int64]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if payeeIds == nil {
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	var payees []*models.Payee
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).In("payee_id", payeeIds).Find(&payees)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payeeMap := s.GetPayeeMapByList(payees)
+	return payeeMap, err
+}
+
+// GetOrCreatePayeesByNames returns the payee models matched by the given names or aliases, and creates new payees
+// for the names which are not matched, the key of returned map is the normalized name
+func (s *PayeeService) GetOrCreatePayeesByNames(c core.Context, uid int64, fundId int64, names []string) (map[string]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	payeeNameMap := make(map[string]*models.Payee)
+
+	if len(names) < 1 {
+		return payeeNameMap, nil
+	}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var existedPayees []*models.Payee
+		err := sess.Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&existedPayees)
+
+		if err != nil {
+			return err
+		}
+
+		payeeNameMap = s.GetPayeeNameMapByList(existedPayees)
+		newPayees := make([]*models.Payee, 0)
+
+		for i := 0; i < len(names); i++ {
+			name := strings.TrimSpace(names[i])
+			normalizedName := models.NormalizePayeeName(name)
+
+			if normalizedName == "" {
+				continue
+			}
+
+			if _, exists := payeeNameMap[normalizedName]; exists {
+				continue
+			}
+
+			if utf8.RuneCountInString(name) > 128 {
+				continue
+			}
+
+			payee := &models.Payee{
+				Uid:    uid,
+				FundId: fundId,
+				Name:   name,
+			}
+
+			payeeNameMap[normalizedName] = payee
+			newPayees = append(newPayees, payee)
+		}
+
+		if len(newPayees) < 1 {
+			return nil
+		}
+
+		payeeUuids := s.GenerateUuids(uuid.UUID_TYPE_PAYEE, uint16(len(newPayees)))
+
+		if len(payeeUuids) < len(newPayees) {
+			return errs.ErrSystemIsBusy
+		}
+
+		now := time.Now().Unix()
+
+		for i := 0; i < len(newPayees); i++ {
+			payee := newPayees[i]
+			payee.PayeeId = payeeUuids[i]
+			payee.Deleted = false
+			payee.CreatedUnixTime = now
+			payee.UpdatedUnixTime = now
+
+			_, err := sess.Insert(payee)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return payeeNameMap, nil
+}
+
+// CreatePayee saves a new payee model to database
+func (s *PayeeService) CreatePayee(c core.Context, payee *models.Payee) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if payee.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if strings.TrimSpace(payee.Name) == "" {
+		return errs.ErrPayeeNameIsEmpty
+	}
+
+	payee.PayeeId = s.GenerateUuid(uuid.UUID_TYPE_PAYEE)
+
+	if payee.PayeeId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	payee.Deleted = false
+	payee.CreatedUnixTime = time.Now().Unix()
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isPayeeValid(sess, payee)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(payee)
+		return err
+	})
+}
+
+// ModifyPayee saves an existed payee model to database
+func (s *PayeeService) ModifyPayee(c core.Context, payee *models.Payee) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if payee.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if strings.TrimSpace(payee.Name) == "" {
+		return errs.ErrPayeeNameIsEmpty
+	}
+
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isPayeeValid(sess, payee)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(payee.PayeeId).Cols("name", "aliases", "default_category_id", "address", "geo_longitude", "geo_latitude", "updated_unix_time").Where("uid=? AND fund_id=? AND deleted=?", payee.Uid, payee.FundId, false).Update(payee)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeletePayee deletes an existed payee from database
+func (s *PayeeService) DeletePayee(c core.Context, uid int64, fundId int64, payeeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if payeeId <= 0 {
+		return errs.ErrPayeeIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "payee_id").Where("uid=? AND deleted=? AND payee_id=?", uid, false, payeeId).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(payeeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return nil
+	})
+}
+
+// GetPayeeMapByList returns a payee map by a list
+func (s *PayeeService) GetPayeeMapByList(payees []*models.Payee) map[int64]*models.Payee {
+	payeeMap := make(map[int64]*models.Payee)
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+		payeeMap[payee.PayeeId] = payee
+	}
+
+	return payeeMap
+}
+
+// GetPayeeNameMapByList returns a payee map by a list, the key of map is the normalized name or alias of payee
+func (s *PayeeService) GetPayeeNameMapByList(payees []*models.Payee) map[string]*models.Payee {
+	payeeMap := make(map[string]*models.Payee)
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+		names := payee.GetNormalizedNames()
+
+		for j := 0; j < len(names); j++ {
+			if _, exists := payeeMap[names[j]]; !exists {
+				payeeMap[names[j]] = payee
+			}
+		}
+	}
+
+	return payeeMap
+}
+
+func (s *PayeeService) isPayeeValid(sess *xorm.Session, payee *models.Payee) error {
+	var existedPayees []*models.Payee
+	err := sess.Cols("payee_id", "name", "aliases").Where("uid=? AND fund_id=? AND deleted=? AND payee_id<>?", payee.Uid, payee.FundId, false, payee.PayeeId).Find(&existedPayees)
+
+	if err != nil {
+		return err
+	}
+
+	existedNameMap := s.GetPayeeNameMapByList(existedPayees)
+	names := payee.GetNormalizedNames()
+
+	for i := 0; i < len(names); i++ {
+		if _, exists := existedNameMap[names[i]]; exists {
+			return errs.ErrPayeeNameAlreadyExists
+		}
+	}
+
+	if payee.DefaultCategoryId > 0 {
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(payee.DefaultCategoryId).Where("uid=? AND fund_id=? AND deleted=?", payee.Uid, payee.FundId, false).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		if category.Type != models.CATEGORY_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_EXPENSE {
+			return errs.ErrPayeeDefaultCategoryTypeInvalid
+		}
+	}
+
+	return nil
+}
+
+// getPayeeDefaultCategoryId returns the default category id of the payee if the type of default category matches the
+// transaction type, or returns 0 if the payee has no default category matched
+func getPayeeDefaultCategoryId(sess *xorm.Session, uid int64, fundId int64, payeeId int64, transactionType models.TransactionDbType) (int64, error) {
+	if payeeId <= 0 {
+		return 0, nil
+	}
+
+	payee := &models.Payee{}
+	has, err := sess.ID(payeeId).Cols("payee_id", "default_category_id").Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Get(payee)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrPayeeNotFound
+	} else if payee.DefaultCategoryId <= 0 {
+		return 0, nil
+	}
+
+	category := &models.TransactionCategory{}
+	has, err = sess.ID(payee.DefaultCategoryId).Cols("category_id", "type").Where("uid=? AND fund_id=? AND deleted=? AND hidden=?", uid, fundId, false, false).Get(category)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, nil
+	}
+
+	if (transactionType == models.TRANSACTION_DB_TYPE_INCOME && category.Type == models.CATEGORY_TYPE_INCOME) ||
+		(transactionType == models.TRANSACTION_DB_TYPE_EXPENSE && category.Type == models.CATEGORY_TYPE_EXPENSE) {
+		return category.CategoryId, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestPayeeService_GetPayeeByPayeeId_InvalidParameters(t *testing.T) {
+	service := &PayeeService{}
+
+	_, err := service.GetPayeeByPayeeId(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetPayeeByPayeeId(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetPayeeByPayeeId(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "payee id is invalid")
+}
+
+func TestPayeeService_GetOrCreatePayeesByNames_EmptyNames(t *testing.T) {
+	service := &PayeeService{}
+
+	_, err := service.GetOrCreatePayeesByNames(nil, 0, 1001, []string{"Coffee House"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	payeeNameMap, err := service.GetOrCreatePayeesByNames(nil, 1001, 1001, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(payeeNameMap))
+}
+
+func TestPayeeService_CreatePayee_InvalidParameters(t *testing.T) {
+	service := &PayeeService{}
+
+	err := service.CreatePayee(nil, &models.Payee{Uid: 0, FundId: 1001, Name: "Coffee House"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreatePayee(nil, &models.Payee{Uid: 1001, FundId: 0, Name: "Coffee House"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.CreatePayee(nil, &models.Payee{Uid: 1001, FundId: 1001, Name: " "})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "payee name is empty")
+}
+
+func TestPayeeService_DeletePayee_InvalidParameters(t *testing.T) {
+	service := &PayeeService{}
+
+	err := service.DeletePayee(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "payee id is invalid")
+}
+
+func TestPayeeService_GetPayeeNameMapByList(t *testing.T) {
+	service := &PayeeService{}
+
+	payees := []*models.Payee{
+		{PayeeId: 1, Name: "Coffee House", Aliases: "Coffee HSE\nCH"},
+		{PayeeId: 2, Name: "Bakery", Aliases: "ch"},
+	}
+
+	payeeNameMap := service.GetPayeeNameMapByList(payees)
+	assert.Equal(t, 4, len(payeeNameMap))
+	assert.Equal(t, int64(1), payeeNameMap["coffee house"].PayeeId)
+	assert.Equal(t, int64(1), payeeNameMap["coffee hse"].PayeeId)
+	assert.Equal(t, int64(1), payeeNameMap["ch"].PayeeId)
+	assert.Equal(t, int64(2), payeeNameMap["bakery"].PayeeId)
+
+	payeeMap := service.GetPayeeMapByList(payees)
+	assert.Equal(t, 2, len(payeeMap))
+	assert.Equal(t, "Bakery", payeeMap[2].Name)
+}
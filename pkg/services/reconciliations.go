@@ -0,0 +1,353 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// ReconciliationService represents reconciliation service
+type ReconciliationService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a reconciliation service singleton instance
+var (
+	Reconciliations = &ReconciliationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllReconciliationsByAccountId returns all reconciliation models of the specified account, or of all accounts if account id is zero
+func (s *ReconciliationService) GetAllReconciliationsByAccountId(c core.Context, uid int64, fundId int64, accountId int64) ([]*models.Reconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId)
+
+	if accountId > 0 {
+		sess = sess.And("account_id=?", accountId)
+	}
+
+	var reconciliations []*models.Reconciliation
+	err := sess.OrderBy("statement_end_time desc, reconciliation_id desc").Find(&reconciliations)
+
+	return reconciliations, err
+}
+
+// GetReconciliationById returns a reconciliation model according to reconciliation id
+func (s *ReconciliationService) GetReconciliationById(c core.Context, uid int64, fundId int64, reconciliationId int64) (*models.Reconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return nil, errs.ErrReconciliationIdInvalid
+	}
+
+	reconciliation := &models.Reconciliation{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(reconciliationId).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Get(reconciliation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrReconciliationNotFound
+	}
+
+	return reconciliation, nil
+}
+
+// GetUnreconciledTransactions returns all transactions of the reconciliation account which are not later than
+// the statement end time and have not been reconciled
+func (s *ReconciliationService) GetUnreconciledTransactions(c core.Context, uid int64, reconciliation *models.Reconciliation) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).
+		Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND cleared_status<>?", uid, false, reconciliation.AccountId, utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementEndTime), models.TRANSACTION_CLEARED_STATUS_RECONCILED).
+		OrderBy("transaction_time desc").
+		Find(&transactions)
+
+	return transactions, err
+}
+
+// GetClearedBalance returns the sum of balance changes of all cleared and reconciled transactions of the reconciliation account
+// which are not later than the statement end time
+func (s *ReconciliationService) GetClearedBalance(c core.Context, uid int64, reconciliation *models.Reconciliation) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	return s.getClearedBalance(s.UserDataDB(uid).NewSession(c), uid, reconciliation)
+}
+
+// CreateReconciliation saves a new reconciliation model to database
+func (s *ReconciliationService) CreateReconciliation(c core.Context, reconciliation *models.Reconciliation) error {
+	if reconciliation.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if reconciliation.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	reconciliation.ReconciliationId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if reconciliation.ReconciliationId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	reconciliation.Deleted = false
+	reconciliation.Status = models.RECONCILIATION_STATUS_IN_PROGRESS
+	reconciliation.ClearedBalance = 0
+	reconciliation.ReconciledCount = 0
+	reconciliation.CreatedUnixTime = time.Now().Unix()
+	reconciliation.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(reconciliation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var existedReconciliations []*models.Reconciliation
+		err := sess.Where("uid=? AND deleted=? AND fund_id=? AND account_id=?", reconciliation.Uid, false, reconciliation.FundId, reconciliation.AccountId).Find(&existedReconciliations)
+
+		if err != nil {
+			return err
+		}
+
+		for _, existedReconciliation := range existedReconciliations {
+			if !existedReconciliation.IsCompleted() {
+				return errs.ErrReconciliationAlreadyInProgress
+			}
+
+			if existedReconciliation.StatementEndTime > reconciliation.StatementEndTime {
+				return errs.ErrReconciliationStatementTimeEarly
+			}
+		}
+
+		_, err = sess.Insert(reconciliation)
+
+		return err
+	})
+}
+
+// MarkTransactions sets the cleared status of the specified transactions of the reconciliation account,
+// reconciled transactions and transactions later than the statement end time cannot be marked
+func (s *ReconciliationService) MarkTransactions(c core.Context, uid int64, fundId int64, reconciliationId int64, transactionIds []int64, cleared bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return errs.ErrReconciliationIdInvalid
+	}
+
+	if len(transactionIds) > models.MaximumTransactionsCountOfReconciliationMark {
+		return errs.ErrTooManyTransactionsToReconcile
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	updateModel := &models.Transaction{
+		ClearedStatus:   models.TRANSACTION_CLEARED_STATUS_UNCLEARED,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	if cleared {
+		updateModel.ClearedStatus = models.TRANSACTION_CLEARED_STATUS_CLEARED
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		reconciliation, err := s.getInProgressReconciliation(sess, uid, fundId, reconciliationId)
+
+		if err != nil {
+			return err
+		}
+
+		var transactions []*models.Transaction
+		err = sess.Cols("transaction_id", "cleared_status").
+			Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=?", uid, false, reconciliation.AccountId, utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementEndTime)).
+			In("transaction_id", transactionIds).
+			Find(&transactions)
+
+		if err != nil {
+			return err
+		} else if len(transactions) != len(transactionIds) {
+			return errs.ErrTransactionNotFound
+		}
+
+		for _, transaction := range transactions {
+			if transaction.ClearedStatus == models.TRANSACTION_CLEARED_STATUS_RECONCILED {
+				return errs.ErrCannotModifyReconciledTransaction
+			}
+		}
+
+		_, err = sess.Cols("cleared_status", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Update(updateModel)
+
+		return err
+	})
+}
+
+// CompleteReconciliation marks all cleared transactions of the reconciliation account as reconciled if the cleared balance
+// matches the statement balance, and returns the completed reconciliation model
+func (s *ReconciliationService) CompleteReconciliation(c core.Context, uid int64, fundId int64, reconciliationId int64) (*models.Reconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return nil, errs.ErrReconciliationIdInvalid
+	}
+
+	var reconciliation *models.Reconciliation
+	now := time.Now().Unix()
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var err error
+		reconciliation, err = s.getInProgressReconciliation(sess, uid, fundId, reconciliationId)
+
+		if err != nil {
+			return err
+		}
+
+		clearedBalance, err := s.getClearedBalance(sess, uid, reconciliation)
+
+		if err != nil {
+			return err
+		} else if clearedBalance != reconciliation.StatementBalance {
+			return errs.ErrReconciliationBalanceNotMatch
+		}
+
+		transactionUpdateModel := &models.Transaction{
+			ClearedStatus:   models.TRANSACTION_CLEARED_STATUS_RECONCILED,
+			UpdatedUnixTime: now,
+		}
+
+		reconciledRows, err := sess.Cols("cleared_status", "updated_unix_time").
+			Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=? AND cleared_status=?", uid, false, reconciliation.AccountId, utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementEndTime), models.TRANSACTION_CLEARED_STATUS_CLEARED).
+			Update(transactionUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		reconciliation.Status = models.RECONCILIATION_STATUS_COMPLETED
+		reconciliation.ClearedBalance = clearedBalance
+		reconciliation.ReconciledCount = int32(reconciledRows)
+		reconciliation.UpdatedUnixTime = now
+		reconciliation.CompletedUnixTime = now
+
+		updatedRows, err := sess.ID(reconciliation.ReconciliationId).Cols("status", "cleared_balance", "reconciled_count", "updated_unix_time", "completed_unix_time").Where("uid=? AND deleted=? AND status=?", uid, false, models.RECONCILIATION_STATUS_IN_PROGRESS).Update(reconciliation)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrReconciliationNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// DeleteReconciliation deletes an in-progress reconciliation, the transactions marked as cleared are kept cleared
+func (s *ReconciliationService) DeleteReconciliation(c core.Context, uid int64, fundId int64, reconciliationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return errs.ErrReconciliationIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Reconciliation{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := s.getInProgressReconciliation(sess, uid, fundId, reconciliationId)
+
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err := sess.ID(reconciliationId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrReconciliationNotFound
+		}
+
+		return nil
+	})
+}
+
+func (s *ReconciliationService) getInProgressReconciliation(sess *xorm.Session, uid int64, fundId int64, reconciliationId int64) (*models.Reconciliation, error) {
+	reconciliation := &models.Reconciliation{}
+	has, err := sess.ID(reconciliationId).Where("uid=? AND deleted=? AND fund_id=?", uid, false, fundId).Get(reconciliation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrReconciliationNotFound
+	} else if reconciliation.IsCompleted() {
+		return nil, errs.ErrReconciliationAlreadyCompleted
+	}
+
+	return reconciliation, nil
+}
+
+func (s *ReconciliationService) getClearedBalance(sess *xorm.Session, uid int64, reconciliation *models.Reconciliation) (int64, error) {
+	var transactions []*models.Transaction
+	err := sess.Cols("type", "amount", "related_account_amount", "cleared_status").
+		Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=?", uid, false, reconciliation.AccountId, utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementEndTime)).
+		Find(&transactions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return models.CalculateClearedBalance(transactions), nil
+}
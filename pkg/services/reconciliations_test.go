@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestReconciliationService_GetReconciliationById_InvalidParameters(t *testing.T) {
+	service := &ReconciliationService{}
+
+	_, err := service.GetReconciliationById(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetReconciliationById(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetReconciliationById(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "reconciliation id is invalid")
+}
+
+func TestReconciliationService_CreateReconciliation_InvalidParameters(t *testing.T) {
+	service := &ReconciliationService{}
+
+	err := service.CreateReconciliation(nil, &models.Reconciliation{Uid: 0, FundId: 1001, AccountId: 2001})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreateReconciliation(nil, &models.Reconciliation{Uid: 1001, FundId: 0, AccountId: 2001})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestReconciliationService_MarkTransactions_TooManyTransactions(t *testing.T) {
+	service := &ReconciliationService{}
+
+	transactionIds := make([]int64, models.MaximumTransactionsCountOfReconciliationMark+1)
+
+	for i := 0; i < len(transactionIds); i++ {
+		transactionIds[i] = int64(i + 1)
+	}
+
+	err := service.MarkTransactions(nil, 1001, 1001, 1001, transactionIds, true)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "too many transactions")
+}
+
+func TestReconciliationService_CompleteReconciliation_InvalidParameters(t *testing.T) {
+	service := &ReconciliationService{}
+
+	_, err := service.CompleteReconciliation(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "reconciliation id is invalid")
+
+	err = service.DeleteReconciliation(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "reconciliation id is invalid")
+}
@@ -119,6 +119,12 @@ func (s *TokenService) CreatePasswordResetTokenWithoutUserAgent(c core.Context,
 	return token, claims, err
 }
 
+// CreateFundInvitationToken generates a new fund invitation token and saves to database
+func (s *TokenService) CreateFundInvitationToken(c core.Context, user *models.User, context string) (string, *core.UserTokenClaims, error) {
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_FUND_INVITATION, "", context, s.CurrentConfig().FundInvitationTokenExpiredTimeDuration)
+	return token, claims, err
+}
+
 // CreateMCPToken generates a new MCP token and saves to database
 func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
 	tokenExpiredTimeDuration := time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
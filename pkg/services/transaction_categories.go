@@ -251,7 +251,12 @@ func (s *TransactionCategoryService) CreateCategory(c core.Context, category *mo
 
 	return s.UserDataDB(category.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Insert(category)
-		return err
+
+		if err != nil {
+			return err
+		}
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(category.FundId, category.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_CATEGORY, category.CategoryId, models.FUND_ACTIVITY_ACTION_CREATE, nil, category, categoryActivityFields))
 	})
 }
 
@@ -317,6 +322,12 @@ func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64,
 			if err != nil {
 				return err
 			}
+
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_CATEGORY, category.CategoryId, models.FUND_ACTIVITY_ACTION_CREATE, nil, category, categoryActivityFields))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -342,6 +353,15 @@ func (s *TransactionCategoryService) ModifyCategory(c core.Context, category *mo
 	category.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(category.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldCategory := &models.TransactionCategory{}
+		has, err := sess.ID(category.CategoryId).Where("uid=? AND fund_id=? AND deleted=?", category.Uid, category.FundId, false).Get(oldCategory)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
 		updatedRows, err := sess.ID(category.CategoryId).Cols("parent_category_id", "name", "icon", "color", "comment", "hidden", "updated_unix_time").Where("uid=? AND fund_id=? AND deleted=?", category.Uid, category.FundId, false).Update(category)
 
 		if err != nil {
@@ -350,7 +370,16 @@ func (s *TransactionCategoryService) ModifyCategory(c core.Context, category *mo
 			return errs.ErrTransactionCategoryNotFound
 		}
 
-		return nil
+		newCategory := &models.TransactionCategory{}
+		has, err = sess.ID(category.CategoryId).Where("uid=? AND fund_id=? AND deleted=?", category.Uid, category.FundId, false).Get(newCategory)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(category.FundId, category.Uid, models.FUND_ACTIVITY_ENTITY_TYPE_CATEGORY, category.CategoryId, models.FUND_ACTIVITY_ACTION_MODIFY, oldCategory, newCategory, categoryActivityFields))
 	})
 }
 
@@ -471,6 +500,15 @@ func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, f
 			return errs.ErrTransactionCategoryNotFound
 		}
 
+		for i := 0; i < len(categoryAndSubCategories); i++ {
+			category := categoryAndSubCategories[i]
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_CATEGORY, category.CategoryId, models.FUND_ACTIVITY_ACTION_DELETE, category, nil, categoryActivityFields))
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return err
 	})
 }
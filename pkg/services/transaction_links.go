@@ -0,0 +1,313 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionLinkService represents transaction link service
+type TransactionLinkService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction link service singleton instance
+var (
+	TransactionLinks = &TransactionLinkService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetLinksByTransactionId returns all links from or to the specified transaction of the fund regardless of which member created it,
+// the check function is called to verify whether current user can read the transaction, and the links whose other transaction
+// has been deleted are not returned
+func (s *TransactionLinkService) GetLinksByTransactionId(c core.Context, uid int64, fundId int64, transactionId int64, check func(transaction *models.Transaction) error) ([]*models.TransactionLink, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	transaction, err := s.getLinkableTransaction(sess, fundId, transactionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if check != nil {
+		if err := check(transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	var links []*models.TransactionLink
+	err = sess.Where("deleted=? AND fund_id=? AND (transaction_id=? OR linked_transaction_id=?)", false, fundId, transaction.TransactionId, transaction.TransactionId).OrderBy("created_unix_time asc, link_id asc").Find(&links)
+
+	if err != nil {
+		return nil, err
+	}
+
+	otherTransactionIds := make([]int64, 0, len(links))
+
+	for _, link := range links {
+		if link.TransactionId == transaction.TransactionId {
+			otherTransactionIds = append(otherTransactionIds, link.LinkedTransactionId)
+		} else {
+			otherTransactionIds = append(otherTransactionIds, link.TransactionId)
+		}
+	}
+
+	if len(otherTransactionIds) < 1 {
+		return links, nil
+	}
+
+	var existedTransactionIds []int64
+	err = sess.Table(&models.Transaction{}).Cols("transaction_id").Where("deleted=? AND fund_id=?", false, fundId).In("transaction_id", utils.ToUniqueInt64Slice(otherTransactionIds)).Find(&existedTransactionIds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	existedTransactionIdsMap := utils.ToSet(existedTransactionIds)
+	availableLinks := make([]*models.TransactionLink, 0, len(links))
+
+	for i, link := range links {
+		if _, exists := existedTransactionIdsMap[otherTransactionIds[i]]; exists {
+			availableLinks = append(availableLinks, link)
+		}
+	}
+
+	return availableLinks, nil
+}
+
+// CreateLink saves a new transaction link model to database, a refund, reimbursement or installment can only be linked to one original transaction,
+// both transactions can be created by any member of the fund, and the check function is called to verify whether current user can link the transaction
+func (s *TransactionLinkService) CreateLink(c core.Context, link *models.TransactionLink, check func(transaction *models.Transaction) error) error {
+	if link.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if link.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if !link.LinkType.IsValid() {
+		return errs.ErrTransactionLinkTypeInvalid
+	}
+
+	link.LinkId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if link.LinkId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	link.Deleted = false
+	link.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(link.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		transaction, err := s.getLinkableTransaction(sess, link.FundId, link.TransactionId)
+
+		if err != nil {
+			return err
+		}
+
+		if check != nil {
+			if err := check(transaction); err != nil {
+				return err
+			}
+		}
+
+		linkedTransaction, err := s.getLinkableTransaction(sess, link.FundId, link.LinkedTransactionId)
+
+		if err == errs.ErrTransactionNotFound {
+			return errs.ErrLinkedTransactionNotFound
+		} else if err != nil {
+			return err
+		}
+
+		err = models.ValidateTransactionLink(link.LinkType, transaction, linkedTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		link.TransactionId = transaction.TransactionId
+		link.LinkedTransactionId = linkedTransaction.TransactionId
+
+		var existedLinks []*models.TransactionLink
+		err = sess.Where("deleted=? AND fund_id=? AND transaction_id=?", false, link.FundId, link.TransactionId).Find(&existedLinks)
+
+		if err != nil {
+			return err
+		}
+
+		for _, existedLink := range existedLinks {
+			if existedLink.LinkedTransactionId == link.LinkedTransactionId && existedLink.LinkType == link.LinkType {
+				return errs.ErrTransactionLinkAlreadyExists
+			}
+
+			if existedLink.LinkType.IsLinkedToOriginal() && link.LinkType.IsLinkedToOriginal() {
+				return errs.ErrTransactionAlreadyLinkedToOriginal
+			}
+		}
+
+		_, err = sess.Insert(link)
+
+		return err
+	})
+}
+
+// DeleteLink deletes an existed transaction link of the fund from database, and the check function is called to verify whether current user can delete the link
+func (s *TransactionLinkService) DeleteLink(c core.Context, uid int64, fundId int64, linkId int64, check func(link *models.TransactionLink) error) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if linkId <= 0 {
+		return errs.ErrTransactionLinkIdInvalid
+	}
+
+	updateModel := &models.TransactionLink{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		link := &models.TransactionLink{}
+		has, err := sess.ID(linkId).Where("deleted=? AND fund_id=?", false, fundId).Get(link)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionLinkNotFound
+		}
+
+		if check != nil {
+			if err := check(link); err != nil {
+				return err
+			}
+		}
+
+		deletedRows, err := sess.ID(linkId).Cols("deleted", "deleted_unix_time").Where("deleted=? AND fund_id=?", false, fundId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionLinkNotFound
+		}
+
+		return nil
+	})
+}
+
+func (s *TransactionLinkService) getLinkableTransaction(sess *xorm.Session, fundId int64, transactionId int64) (*models.Transaction, error) {
+	transaction := &models.Transaction{}
+	has, err := sess.ID(transactionId).Where("deleted=? AND fund_id=?", false, fundId).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return transaction, nil
+	}
+
+	// a transfer is always linked by its transfer out transaction
+	relatedTransaction := &models.Transaction{}
+	has, err = sess.ID(transaction.RelatedId).Where("deleted=? AND fund_id=?", false, fundId).Get(relatedTransaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	return relatedTransaction, nil
+}
+
+// getRefundOriginalTransactionsMap returns a map of the original expenses of the refund and reimbursement incomes in the specified
+// transactions, the links and the original expenses can be created by any member of the fund, the key of map is the transaction id of refund
+func getRefundOriginalTransactionsMap(sess *xorm.Session, fundId int64, transactions []*models.Transaction) (map[int64]*models.Transaction, error) {
+	incomeTransactionIds := make([]int64, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			incomeTransactionIds = append(incomeTransactionIds, transaction.TransactionId)
+		}
+	}
+
+	originalTransactions := make(map[int64]*models.Transaction)
+	incomeTransactionIds = utils.ToUniqueInt64Slice(incomeTransactionIds)
+
+	for i := 0; i < len(incomeTransactionIds); i += pageCountForLoadTransactionAmounts {
+		end := i + pageCountForLoadTransactionAmounts
+
+		if end > len(incomeTransactionIds) {
+			end = len(incomeTransactionIds)
+		}
+
+		var links []*models.TransactionLink
+		err := sess.Where("deleted=? AND fund_id=?", false, fundId).In("link_type", models.TRANSACTION_LINK_TYPE_REFUND_OF, models.TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF).In("transaction_id", incomeTransactionIds[i:end]).Find(&links)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(links) < 1 {
+			continue
+		}
+
+		linkedTransactionIds := make([]int64, len(links))
+
+		for j, link := range links {
+			linkedTransactionIds[j] = link.LinkedTransactionId
+		}
+
+		var linkedTransactions []*models.Transaction
+		err = sess.Select("transaction_id, type, category_id").Where("deleted=? AND fund_id=?", false, fundId).In("transaction_id", utils.ToUniqueInt64Slice(linkedTransactionIds)).Find(&linkedTransactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		linkedTransactionsMap := make(map[int64]*models.Transaction, len(linkedTransactions))
+
+		for _, linkedTransaction := range linkedTransactions {
+			linkedTransactionsMap[linkedTransaction.TransactionId] = linkedTransaction
+		}
+
+		for _, link := range links {
+			if linkedTransaction, exists := linkedTransactionsMap[link.LinkedTransactionId]; exists {
+				originalTransactions[link.TransactionId] = linkedTransaction
+			}
+		}
+	}
+
+	return originalTransactions, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionLinkService_GetLinksByTransactionId_InvalidParameters(t *testing.T) {
+	service := &TransactionLinkService{}
+
+	_, err := service.GetLinksByTransactionId(nil, 0, 1001, 1001, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, err = service.GetLinksByTransactionId(nil, 1001, 0, 1001, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	_, err = service.GetLinksByTransactionId(nil, 1001, 1001, 0, nil)
+	assert.Equal(t, errs.ErrTransactionIdInvalid, err)
+}
+
+func TestTransactionLinkService_CreateLink_InvalidParameters(t *testing.T) {
+	service := &TransactionLinkService{}
+
+	err := service.CreateLink(nil, &models.TransactionLink{Uid: 0, FundId: 1001, LinkType: models.TRANSACTION_LINK_TYPE_REFUND_OF}, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.CreateLink(nil, &models.TransactionLink{Uid: 1001, FundId: 0, LinkType: models.TRANSACTION_LINK_TYPE_REFUND_OF}, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.CreateLink(nil, &models.TransactionLink{Uid: 1001, FundId: 1001, LinkType: 0}, nil)
+	assert.Equal(t, errs.ErrTransactionLinkTypeInvalid, err)
+}
+
+func TestTransactionLinkService_DeleteLink_InvalidParameters(t *testing.T) {
+	service := &TransactionLinkService{}
+
+	err := service.DeleteLink(nil, 0, 1001, 1001, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.DeleteLink(nil, 1001, 0, 1001, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.DeleteLink(nil, 1001, 1001, 0, nil)
+	assert.Equal(t, errs.ErrTransactionLinkIdInvalid, err)
+}
+
+func TestTransactionLinkService_TransactionsOfOtherMembers(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, CategoryId: 5001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+		&models.Transaction{TransactionId: 3002, Uid: 1002, FundId: 4001, AccountId: 2002, CategoryId: 5002, Type: models.TRANSACTION_DB_TYPE_INCOME, Amount: 100},
+		&models.Transaction{TransactionId: 3003, Uid: 1001, FundId: 4002, AccountId: 2003, CategoryId: 5003, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+	)
+
+	denyOtherMembers := func(transaction *models.Transaction) error {
+		if !models.FUND_PERMISSION_OWN.IsAllowedOn(1002, transaction.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	}
+
+	err := TransactionLinks.CreateLink(c, &models.TransactionLink{Uid: 1002, FundId: 4001, TransactionId: 3002, LinkedTransactionId: 3003, LinkType: models.TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF}, denyOtherMembers)
+	assert.Equal(t, errs.ErrLinkedTransactionNotFound, err)
+
+	link := &models.TransactionLink{Uid: 1002, FundId: 4001, TransactionId: 3002, LinkedTransactionId: 3001, LinkType: models.TRANSACTION_LINK_TYPE_REIMBURSEMENT_OF}
+	err = TransactionLinks.CreateLink(c, link, denyOtherMembers)
+	assert.Nil(t, err)
+
+	links, err := TransactionLinks.GetLinksByTransactionId(c, 1001, 4001, 3001, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(links))
+	assert.Equal(t, link.LinkId, links[0].LinkId)
+
+	_, err = TransactionLinks.GetLinksByTransactionId(c, 1002, 4001, 3001, denyOtherMembers)
+	assert.Equal(t, errs.ErrFundPermissionDenied, err)
+
+	originalTransactions, err := getRefundOriginalTransactionsMap(TransactionLinks.UserDataDB(1002).NewSession(c), 4001, []*models.Transaction{{TransactionId: 3002, Type: models.TRANSACTION_DB_TYPE_INCOME}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5001), originalTransactions[3002].CategoryId)
+
+	err = TransactionLinks.DeleteLink(c, 1001, 4001, link.LinkId, func(link *models.TransactionLink) error {
+		if !models.FUND_PERMISSION_OWN.IsAllowedOn(1001, link.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	})
+	assert.Equal(t, errs.ErrFundPermissionDenied, err)
+
+	err = TransactionLinks.DeleteLink(c, 1001, 4001, link.LinkId, nil)
+	assert.Nil(t, err)
+}
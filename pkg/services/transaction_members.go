@@ -10,6 +10,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // TransactionMemberService represents transaction member service
@@ -86,8 +87,9 @@ func (s *TransactionMemberService) GetTransactionsByMemberId(c core.Context, uid
 	return transactions, err
 }
 
-// LinkTransactionMembers links a transaction to multiple members
-func (s *TransactionMemberService) LinkTransactionMembers(c core.Context, uid int64, transactionId int64, memberIds []int64) error {
+// LinkTransactionMembers links a transaction to multiple members with the split of each member,
+// the transaction is linked to all members of its fund if no members are specified, reconciled transaction cannot be changed
+func (s *TransactionMemberService) LinkTransactionMembers(c core.Context, uid int64, transactionId int64, members []*models.TransactionMember) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -96,36 +98,30 @@ func (s *TransactionMemberService) LinkTransactionMembers(c core.Context, uid in
 		return errs.ErrTransactionIdInvalid
 	}
 
-	if len(memberIds) == 0 {
-		// If no member IDs provided, link to all members of the fund
-		return s.linkTransactionToAllMembers(c, uid, transactionId)
+	// Guard against nil container (e.g., in unit tests)
+	if s.container == nil {
+		return errs.ErrSystemError
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// First, remove existing links
-		_, err := sess.Where("transaction_id=?", transactionId).Delete(&models.TransactionMember{})
+		transaction := &models.Transaction{}
+		has, err := sess.Where("transaction_id=? AND uid=? AND deleted=?", transactionId, uid, false).Get(transaction)
+
 		if err != nil {
-			log.Errorf(c, "[transaction_members.LinkTransactionMembers] failed to delete existing transaction members for transaction \"transaction_id:%d\", because %s", transactionId, err.Error())
 			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
 		}
 
-		// Then, create new links
-		now := time.Now().Unix()
-		for _, memberId := range memberIds {
-			transactionMember := &models.TransactionMember{
-				TransactionId:   transactionId,
-				MemberId:        memberId,
-				CreatedUnixTime: now,
-			}
+		reconciled, err := isTransactionReconciled(sess, transaction)
 
-			_, err := sess.Insert(transactionMember)
-			if err != nil {
-				log.Errorf(c, "[transaction_members.LinkTransactionMembers] failed to insert transaction member \"transaction_id:%d, member_id:%d\", because %s", transactionId, memberId, err.Error())
-				return err
-			}
+		if err != nil {
+			return err
+		} else if reconciled {
+			return errs.ErrCannotModifyReconciledTransaction
 		}
 
-		return nil
+		return setTransactionMembers(c, sess, transaction, members, time.Now().Unix())
 	})
 }
 
@@ -148,40 +144,6 @@ func (s *TransactionMemberService) UnlinkTransactionMembers(c core.Context, uid
 	return nil
 }
 
-// linkTransactionToAllMembers links a transaction to all members of its fund
-func (s *TransactionMemberService) linkTransactionToAllMembers(c core.Context, uid int64, transactionId int64) error {
-	// Guard against nil container (e.g., in unit tests)
-	if s.container == nil {
-		return errs.ErrSystemError
-	}
-
-	// First, get the transaction to find its fund
-	transaction := &models.Transaction{}
-	has, err := s.UserDataDB(uid).NewSession(c).Where("transaction_id=? AND uid=? AND deleted=?", transactionId, uid, false).Get(transaction)
-	if err != nil {
-		return err
-	}
-	if !has {
-		return errs.ErrTransactionNotFound
-	}
-
-	// Get all members of the fund
-	var fundMembers []*models.FundMember
-	err = s.UserDataDB(uid).NewSession(c).Where("fund_id=?", transaction.FundId).Find(&fundMembers)
-	if err != nil {
-		return err
-	}
-
-	// Extract member IDs
-	memberIds := make([]int64, len(fundMembers))
-	for i, member := range fundMembers {
-		memberIds[i] = member.MemberId
-	}
-
-	// Link to all members
-	return s.LinkTransactionMembers(c, uid, transactionId, memberIds)
-}
-
 // GetTransactionMembersWithDetails returns transaction members with fund member details
 func (s *TransactionMemberService) GetTransactionMembersWithDetails(c core.Context, uid int64, transactionId int64) ([]*models.FundMember, error) {
 	if uid <= 0 {
@@ -281,3 +243,106 @@ func (s *TransactionMemberService) canUserAccessFund(c core.Context, uid int64,
 
 	return count > 0
 }
+
+// insertTransactionMembersWithEqualSplit links the specified members to a transaction in the specified session,
+// the amount of transaction is split equally, and all members must still exist in the fund
+func insertTransactionMembersWithEqualSplit(sess *xorm.Session, fundId int64, transactionId int64, memberIds []int64, now int64) error {
+	if len(memberIds) < 1 {
+		return nil
+	}
+
+	memberIds = utils.ToUniqueInt64Slice(memberIds)
+	memberCount, err := sess.Where("fund_id=?", fundId).In("member_id", memberIds).Count(&models.FundMember{})
+
+	if err != nil {
+		return err
+	} else if memberCount != int64(len(memberIds)) {
+		return errs.ErrMemberNotFound
+	}
+
+	for _, memberId := range memberIds {
+		transactionMember := &models.TransactionMember{
+			TransactionId:   transactionId,
+			MemberId:        memberId,
+			SplitType:       models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL,
+			CreatedUnixTime: now,
+		}
+
+		if _, err := sess.Insert(transactionMember); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setTransactionMembers replaces the members linked to a transaction in the specified session,
+// the transaction is linked to all members of its fund with equal split if no members are specified
+func setTransactionMembers(c core.Context, sess *xorm.Session, transaction *models.Transaction, members []*models.TransactionMember, now int64) error {
+	if len(members) == 0 {
+		var fundMembers []*models.FundMember
+		err := sess.Where("fund_id=?", transaction.FundId).Find(&fundMembers)
+
+		if err != nil {
+			return err
+		} else if len(fundMembers) == 0 {
+			return errs.ErrMemberNotFound
+		}
+
+		members = make([]*models.TransactionMember, len(fundMembers))
+
+		for i, fundMember := range fundMembers {
+			members[i] = &models.TransactionMember{
+				MemberId:  fundMember.MemberId,
+				SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL,
+			}
+		}
+	}
+
+	// Validate the split against the transaction amount
+	_, err := models.CalculateTransactionMemberAmounts(transaction.Amount, members)
+
+	if err != nil {
+		return err
+	}
+
+	memberIds := make([]int64, len(members))
+
+	for i, member := range members {
+		memberIds[i] = member.MemberId
+	}
+
+	memberCount, err := sess.Where("fund_id=?", transaction.FundId).In("member_id", memberIds).Count(&models.FundMember{})
+
+	if err != nil {
+		return err
+	} else if memberCount != int64(len(memberIds)) {
+		return errs.ErrMemberNotFound
+	}
+
+	_, err = sess.Where("transaction_id=?", transaction.TransactionId).Delete(&models.TransactionMember{})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_members.setTransactionMembers] failed to delete existing transaction members for transaction \"transaction_id:%d\", because %s", transaction.TransactionId, err.Error())
+		return err
+	}
+
+	for _, member := range members {
+		transactionMember := &models.TransactionMember{
+			TransactionId:   transaction.TransactionId,
+			MemberId:        member.MemberId,
+			SplitType:       member.SplitType,
+			SplitValue:      member.SplitValue,
+			CreatedUnixTime: now,
+		}
+
+		_, err = sess.Insert(transactionMember)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_members.setTransactionMembers] failed to insert transaction member \"transaction_id:%d, member_id:%d\", because %s", transaction.TransactionId, member.MemberId, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
 
 func TestTransactionMemberService_GetTransactionMembersByTransactionId_InvalidUserId(t *testing.T) {
@@ -92,38 +94,44 @@ func TestTransactionMemberService_GetTransactionsByMemberId_InvalidMemberId(t *t
 
 func TestTransactionMemberService_LinkTransactionMembers_InvalidUserId(t *testing.T) {
 	service := &TransactionMemberService{}
-	memberIds := []int64{1001, 1002}
+	members := []*models.TransactionMember{
+		{MemberId: 1001, SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 1002, SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}
 
-	err := service.LinkTransactionMembers(nil, 0, 2001, memberIds)
+	err := service.LinkTransactionMembers(nil, 0, 2001, members)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "user id is invalid")
 
-	err = service.LinkTransactionMembers(nil, -1, 2001, memberIds)
+	err = service.LinkTransactionMembers(nil, -1, 2001, members)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "user id is invalid")
 }
 
 func TestTransactionMemberService_LinkTransactionMembers_InvalidTransactionId(t *testing.T) {
 	service := &TransactionMemberService{}
-	memberIds := []int64{1001, 1002}
+	members := []*models.TransactionMember{
+		{MemberId: 1001, SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+		{MemberId: 1002, SplitType: models.TRANSACTION_MEMBER_SPLIT_TYPE_EQUAL},
+	}
 
-	err := service.LinkTransactionMembers(nil, 1001, 0, memberIds)
+	err := service.LinkTransactionMembers(nil, 1001, 0, members)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "transaction id is invalid")
 
-	err = service.LinkTransactionMembers(nil, 1001, -1, memberIds)
+	err = service.LinkTransactionMembers(nil, 1001, -1, members)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "transaction id is invalid")
 }
 
 func TestTransactionMemberService_LinkTransactionMembers_EmptyMemberIds(t *testing.T) {
 	service := &TransactionMemberService{}
-	memberIds := []int64{}
+	members := []*models.TransactionMember{}
 
 	// When empty member IDs are provided, it should attempt to link to all fund members
 	// This will fail due to no database connection, but validates that the method
-	// accepts empty member IDs and attempts the linkTransactionToAllMembers path
-	err := service.LinkTransactionMembers(nil, 1001, 2001, memberIds)
+	// accepts empty member IDs and attempts to link all fund members
+	err := service.LinkTransactionMembers(nil, 1001, 2001, members)
 	assert.NotNil(t, err) // Expected to fail due to no DB connection
 	// The error should not be a validation error for user ID or transaction ID
 	assert.NotContains(t, err.Error(), "user id is invalid")
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// TransactionRevisionService represents transaction revision service
+type TransactionRevisionService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction revision service singleton instance
+var (
+	TransactionRevisions = &TransactionRevisionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetRevisionsByTransactionId returns all revisions of a transaction ordered by revision number descending
+func (s *TransactionRevisionService) GetRevisionsByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionRevision, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var revisions []*models.TransactionRevision
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND transaction_id=?", uid, transactionId).OrderBy("revision desc").Find(&revisions)
+
+	return revisions, err
+}
+
+// GetRevision returns a transaction revision model according to transaction id and revision number
+func (s *TransactionRevisionService) GetRevision(c core.Context, uid int64, transactionId int64, revision int32) (*models.TransactionRevision, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	if revision <= 0 {
+		return nil, errs.ErrTransactionRevisionInvalid
+	}
+
+	transactionRevision := &models.TransactionRevision{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND transaction_id=? AND revision=?", uid, transactionId, revision).Get(transactionRevision)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionRevisionNotFound
+	}
+
+	return transactionRevision, nil
+}
+
+// CreateRevision saves a new transaction revision model to database, the revision number is the next number of
+// the latest revision of the transaction
+func (s *TransactionRevisionService) CreateRevision(c core.Context, transactionRevision *models.TransactionRevision) error {
+	if transactionRevision.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if transactionRevision.TransactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	return s.UserDataDB(transactionRevision.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := lockTransactionsForRevision(sess, transactionRevision.Uid, []int64{transactionRevision.TransactionId})
+
+		if err != nil {
+			return err
+		}
+
+		return insertTransactionRevision(c, sess, transactionRevision)
+	})
+}
+
+// lockTransactionsForRevision locks the rows of the specified transactions in the specified session by a no-op update,
+// it must be called before any other query of the database transaction, so that concurrent modifications of the same
+// transaction are serialized and the latest revision read afterwards is always up-to-date
+func lockTransactionsForRevision(sess *xorm.Session, uid int64, transactionIds []int64) error {
+	if len(transactionIds) < 1 {
+		return nil
+	}
+
+	_, err := sess.SetExpr("updated_unix_time", "updated_unix_time").Where("uid=?", uid).In("transaction_id", transactionIds).Update(&models.Transaction{})
+
+	return err
+}
+
+// lockFundTransactionsForRevision locks the rows of the specified transactions of the fund in the specified session like lockTransactionsForRevision,
+// the transactions may be created by different users of the fund
+func lockFundTransactionsForRevision(sess *xorm.Session, fundId int64, transactionIds []int64) error {
+	if len(transactionIds) < 1 {
+		return nil
+	}
+
+	_, err := sess.SetExpr("updated_unix_time", "updated_unix_time").Where("fund_id=?", fundId).In("transaction_id", transactionIds).Update(&models.Transaction{})
+
+	return err
+}
+
+// insertTransactionRevision saves a new transaction revision model in the specified session, the revision number
+// is the next number of the latest revision of the transaction, the transaction must be locked by lockTransactionsForRevision first
+func insertTransactionRevision(c core.Context, sess *xorm.Session, transactionRevision *models.TransactionRevision) error {
+	latestRevision := &models.TransactionRevision{}
+	has, err := sess.Where("uid=? AND transaction_id=?", transactionRevision.Uid, transactionRevision.TransactionId).OrderBy("revision desc").Limit(1).Get(latestRevision)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_revisions.insertTransactionRevision] failed to get latest revision of transaction \"transaction_id:%d\", because %s", transactionRevision.TransactionId, err.Error())
+		return err
+	}
+
+	transactionRevision.Revision = 1
+	transactionRevision.CreatedUnixTime = time.Now().Unix()
+
+	if has {
+		transactionRevision.Revision = latestRevision.Revision + 1
+	}
+
+	_, err = sess.Insert(transactionRevision)
+
+	return err
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionRevisionService_GetRevisionsByTransactionId_InvalidParameters(t *testing.T) {
+	service := &TransactionRevisionService{}
+
+	_, err := service.GetRevisionsByTransactionId(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetRevisionsByTransactionId(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction id is invalid")
+}
+
+func TestTransactionRevisionService_GetRevision_InvalidParameters(t *testing.T) {
+	service := &TransactionRevisionService{}
+
+	_, err := service.GetRevision(nil, 0, 1001, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetRevision(nil, 1001, 0, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction id is invalid")
+
+	_, err = service.GetRevision(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction revision is invalid")
+}
+
+func TestTransactionRevisionService_CreateRevision_InvalidParameters(t *testing.T) {
+	service := &TransactionRevisionService{}
+
+	err := service.CreateRevision(nil, &models.TransactionRevision{Uid: 0, TransactionId: 1001})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreateRevision(nil, &models.TransactionRevision{Uid: 1001, TransactionId: 0})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction id is invalid")
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionRuleService represents transaction rule service
+type TransactionRuleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction rule service singleton instance
+var (
+	TransactionRules = &TransactionRuleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllRulesByFundId returns all transaction rule models of a fund which are visible to user, including the fund rules
+// and the personal rules of the user
+func (s *TransactionRuleService) GetAllRulesByFundId(c core.Context, uid int64, fundId int64) ([]*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	var rules []*models.TransactionRule
+	err := s.UserDataDB(uid).NewSession(c).Where("fund_id=? AND deleted=? AND (scope=? OR uid=?)", fundId, false, models.TRANSACTION_RULE_SCOPE_FUND, uid).OrderBy("display_order asc, rule_id asc").Find(&rules)
+
+	return rules, err
+}
+
+// GetRuleByRuleId returns a transaction rule model according to rule id if it is visible to user
+func (s *TransactionRuleService) GetRuleByRuleId(c core.Context, uid int64, fundId int64, ruleId int64) (*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if ruleId <= 0 {
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ruleId).Where("fund_id=? AND deleted=? AND (scope=? OR uid=?)", fundId, false, models.TRANSACTION_RULE_SCOPE_FUND, uid).Get(rule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionRuleNotFound
+	}
+
+	return rule, nil
+}
+
+// GetMaxDisplayOrder returns the max display order of the transaction rules in a fund
+func (s *TransactionRuleService) GetMaxDisplayOrder(c core.Context, uid int64, fundId int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return 0, errs.ErrFundIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("fund_id", "deleted", "display_order").Where("fund_id=? AND deleted=?", fundId, false).OrderBy("display_order desc").Limit(1).Get(rule)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return rule.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// GetRuleEngine returns the transaction rule engine which contains all enabled rules applied to the transactions
+// created by user in the specified fund
+func (s *TransactionRuleService) GetRuleEngine(c core.Context, uid int64, fundId int64) (*models.TransactionRuleEngine, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return models.NewTransactionRuleEngine(nil), nil
+	}
+
+	return getTransactionRuleEngine(s.UserDataDB(uid).NewSession(c), uid, fundId)
+}
+
+// CreateRule saves a new transaction rule model to database
+func (s *TransactionRuleService) CreateRule(c core.Context, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if rule.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	err := rule.Validate()
+
+	if err != nil {
+		return err
+	}
+
+	rule.RuleId = s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION_RULE)
+
+	if rule.RuleId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	rule.Deleted = false
+	rule.CreatedUnixTime = time.Now().Unix()
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(rule)
+		return err
+	})
+}
+
+// ModifyRule saves an existed transaction rule model to database, the scope and the creator of rule cannot be modified
+func (s *TransactionRuleService) ModifyRule(c core.Context, uid int64, rule *models.TransactionRule) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if rule.FundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	err := rule.Validate()
+
+	if err != nil {
+		return err
+	}
+
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(rule.RuleId).Cols("name", "disabled", "transaction_type", "comment_pattern", "min_amount", "max_amount", "account_id", "counterparty_account_id", "set_category_id", "add_tag_ids", "set_member_ids", "set_comment", "updated_unix_time").Where("fund_id=? AND deleted=?", rule.FundId, false).Update(rule)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return nil
+	})
+}
+
+// ModifyRuleDisplayOrders updates display order of given transaction rules
+func (s *TransactionRuleService) ModifyRuleDisplayOrders(c core.Context, uid int64, fundId int64, rules []*models.TransactionRule) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rules[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(rules); i++ {
+			rule := rules[i]
+			updatedRows, err := sess.ID(rule.RuleId).Cols("display_order", "updated_unix_time").Where("fund_id=? AND deleted=? AND (scope=? OR uid=?)", fundId, false, models.TRANSACTION_RULE_SCOPE_FUND, uid).Update(rule)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionRuleNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRule deletes an existed transaction rule from database
+func (s *TransactionRuleService) DeleteRule(c core.Context, uid int64, fundId int64, ruleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(ruleId).Cols("deleted", "deleted_unix_time").Where("fund_id=? AND deleted=?", fundId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return nil
+	})
+}
+
+func (s *TransactionRuleService) isRuleValid(sess *xorm.Session, rule *models.TransactionRule) error {
+	if rule.AccountId > 0 {
+		exists, err := sess.ID(rule.AccountId).Where("uid=? AND fund_id=? AND deleted=?", rule.Uid, rule.FundId, false).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrSourceAccountNotFound
+		}
+	}
+
+	if rule.CounterpartyAccountId > 0 {
+		exists, err := sess.ID(rule.CounterpartyAccountId).Where("uid=? AND fund_id=? AND deleted=?", rule.Uid, rule.FundId, false).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrDestinationAccountNotFound
+		}
+	}
+
+	if rule.SetCategoryId > 0 {
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(rule.SetCategoryId).Where("uid=? AND fund_id=? AND deleted=?", rule.Uid, rule.FundId, false).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		categoryType, err := rule.GetCategoryType()
+
+		if err != nil {
+			return err
+		} else if category.Type != categoryType {
+			return errs.ErrTransactionRuleCategoryTypeInvalid
+		}
+	}
+
+	if tagIds := rule.GetAddTagIds(); len(tagIds) > 0 {
+		tagCount, err := sess.Where("uid=? AND fund_id=? AND deleted=?", rule.Uid, rule.FundId, false).In("tag_id", tagIds).Count(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if tagCount != int64(len(tagIds)) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	if memberIds := rule.GetSetMemberIds(); len(memberIds) > 0 {
+		memberCount, err := sess.Where("fund_id=?", rule.FundId).In("member_id", memberIds).Count(&models.FundMember{})
+
+		if err != nil {
+			return err
+		} else if memberCount != int64(len(memberIds)) {
+			return errs.ErrMemberNotFound
+		}
+	}
+
+	return nil
+}
+
+// getTransactionRuleEngine returns the transaction rule engine of the transactions created by user in the specified fund,
+// the actions which cannot be used by the user any more (e.g. deleted or hidden categories and tags, or categories
+// of other users in fund rules) are removed from the rules
+func getTransactionRuleEngine(sess *xorm.Session, uid int64, fundId int64) (*models.TransactionRuleEngine, error) {
+	var rules []*models.TransactionRule
+	err := sess.Where("fund_id=? AND deleted=? AND disabled=? AND (scope=? OR uid=?)", fundId, false, false, models.TRANSACTION_RULE_SCOPE_FUND, uid).Find(&rules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) < 1 {
+		return models.NewTransactionRuleEngine(nil), nil
+	}
+
+	categoryIds := make([]int64, 0, len(rules))
+	tagIds := make([]int64, 0, len(rules))
+	memberIds := make([]int64, 0, len(rules))
+
+	for _, rule := range rules {
+		if rule.SetCategoryId > 0 {
+			categoryIds = append(categoryIds, rule.SetCategoryId)
+		}
+
+		tagIds = append(tagIds, rule.GetAddTagIds()...)
+		memberIds = append(memberIds, rule.GetSetMemberIds()...)
+	}
+
+	availableCategories := make(map[int64]*models.TransactionCategory)
+	availableTagIds := make(map[int64]bool)
+	availableMemberIds := make(map[int64]bool)
+
+	if len(categoryIds) > 0 {
+		var categories []*models.TransactionCategory
+		err = sess.Where("uid=? AND fund_id=? AND deleted=? AND hidden=? AND parent_category_id<>?", uid, fundId, false, false, models.LevelOneTransactionCategoryParentId).In("category_id", utils.ToUniqueInt64Slice(categoryIds)).Find(&categories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parentCategoryIds := make([]int64, 0, len(categories))
+
+		for _, category := range categories {
+			parentCategoryIds = append(parentCategoryIds, category.ParentCategoryId)
+		}
+
+		var availableParentCategories []*models.TransactionCategory
+
+		if len(parentCategoryIds) > 0 {
+			err = sess.Cols("category_id").Where("uid=? AND fund_id=? AND deleted=? AND hidden=?", uid, fundId, false, false).In("category_id", utils.ToUniqueInt64Slice(parentCategoryIds)).Find(&availableParentCategories)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		availableParentCategoryIds := make(map[int64]bool, len(availableParentCategories))
+
+		for _, parentCategory := range availableParentCategories {
+			availableParentCategoryIds[parentCategory.CategoryId] = true
+		}
+
+		for _, category := range categories {
+			if availableParentCategoryIds[category.ParentCategoryId] {
+				availableCategories[category.CategoryId] = category
+			}
+		}
+	}
+
+	if len(tagIds) > 0 {
+		var tags []*models.TransactionTag
+		err = sess.Where("uid=? AND fund_id=? AND deleted=? AND hidden=?", uid, fundId, false, false).In("tag_id", utils.ToUniqueInt64Slice(tagIds)).Find(&tags)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range tags {
+			availableTagIds[tag.TagId] = true
+		}
+	}
+
+	if len(memberIds) > 0 {
+		var members []*models.FundMember
+		err = sess.Where("fund_id=?", fundId).In("member_id", utils.ToUniqueInt64Slice(memberIds)).Find(&members)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			availableMemberIds[member.MemberId] = true
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.SetCategoryId > 0 {
+			category, exists := availableCategories[rule.SetCategoryId]
+
+			if categoryType, err := rule.GetCategoryType(); !exists || err != nil || category.Type != categoryType {
+				rule.SetCategoryId = 0
+			}
+		}
+
+		rule.AddTagIds = getAvailableTransactionRuleIds(rule.GetAddTagIds(), availableTagIds)
+
+		if ruleMemberIds := rule.GetSetMemberIds(); len(ruleMemberIds) > 0 && len(getAvailableTransactionRuleIds(ruleMemberIds, availableMemberIds)) != len(ruleMemberIds) {
+			rule.SetMemberIds = ""
+		}
+	}
+
+	return models.NewTransactionRuleEngine(rules), nil
+}
+
+func getAvailableTransactionRuleIds(ids []int64, availableIds map[int64]bool) string {
+	result := make([]int64, 0, len(ids))
+
+	for _, id := range ids {
+		if availableIds[id] {
+			result = append(result, id)
+		}
+	}
+
+	return strings.Join(utils.Int64ArrayToStringArray(result), ",")
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionRuleService_GetRuleByRuleId_InvalidParameters(t *testing.T) {
+	service := &TransactionRuleService{}
+
+	_, err := service.GetRuleByRuleId(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetRuleByRuleId(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetRuleByRuleId(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction rule id is invalid")
+}
+
+func TestTransactionRuleService_GetRuleEngine_WithoutFund(t *testing.T) {
+	service := &TransactionRuleService{}
+
+	_, err := service.GetRuleEngine(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	ruleEngine, err := service.GetRuleEngine(nil, 1001, 0)
+	assert.Nil(t, err)
+	assert.True(t, ruleEngine.IsEmpty())
+}
+
+func TestTransactionRuleService_CreateRule_InvalidParameters(t *testing.T) {
+	service := &TransactionRuleService{}
+
+	err := service.CreateRule(nil, &models.TransactionRule{Uid: 0, FundId: 1001, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.CreateRule(nil, &models.TransactionRule{Uid: 1001, FundId: 0, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.CreateRule(nil, &models.TransactionRule{Uid: 1001, FundId: 1001, Scope: 0, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction rule scope is invalid")
+
+	err = service.CreateRule(nil, &models.TransactionRule{Uid: 1001, FundId: 1001, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction rule has no action")
+}
+
+func TestTransactionRuleService_ModifyRule_InvalidParameters(t *testing.T) {
+	service := &TransactionRuleService{}
+
+	err := service.ModifyRule(nil, 0, &models.TransactionRule{FundId: 1001, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.ModifyRule(nil, 1001, &models.TransactionRule{FundId: 0, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.ModifyRule(nil, 1001, &models.TransactionRule{FundId: 1001, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, MinAmount: 2, MaxAmount: 1, SetComment: "comment"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction rule amount range is invalid")
+}
+
+func TestTransactionRuleService_DeleteRule_InvalidParameters(t *testing.T) {
+	service := &TransactionRuleService{}
+
+	err := service.DeleteRule(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.DeleteRule(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
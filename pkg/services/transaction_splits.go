@@ -0,0 +1,238 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionSplitService represents transaction split service
+type TransactionSplitService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction split service singleton instance
+var (
+	TransactionSplits = &TransactionSplitService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetSplitsByTransactionId returns all line items of a transaction ordered by line index
+func (s *TransactionSplitService) GetSplitsByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND transaction_id=?", uid, transactionId).OrderBy("line_index asc").Find(&splits)
+
+	return splits, err
+}
+
+// GetSplitsByTransactionIds returns a map of line items ordered by line index, the key of map is transaction id
+func (s *TransactionSplitService) GetSplitsByTransactionIds(c core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	return getTransactionSplitsMap(s.UserDataDB(uid).NewSession(c), uid, transactionIds)
+}
+
+// SetTransactionSplits replaces all line items of a transaction, the transaction is no longer split if splits is empty,
+// reconciled transaction cannot be changed
+func (s *TransactionSplitService) SetTransactionSplits(c core.Context, uid int64, transactionId int64, splits []*models.TransactionSplit) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		transaction := &models.Transaction{}
+		has, err := sess.Where("transaction_id=? AND uid=? AND deleted=?", transactionId, uid, false).Get(transaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		reconciled, err := isTransactionReconciled(sess, transaction)
+
+		if err != nil {
+			return err
+		} else if reconciled {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
+
+		return setTransactionSplits(c, sess, transaction, splits, time.Now().Unix())
+	})
+}
+
+// setTransactionSplits replaces all line items of a transaction in the specified session, the transaction is no longer split if splits is empty
+func setTransactionSplits(c core.Context, sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit, now int64) error {
+	err := models.ValidateTransactionSplits(transaction.Type, transaction.Amount, splits)
+
+	if err != nil {
+		return err
+	}
+
+	err = isSplitCategoriesValid(sess, transaction, splits)
+
+	if err != nil {
+		return err
+	}
+
+	err = isSplitTagsValid(sess, transaction, splits)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Where("uid=? AND transaction_id=?", transaction.Uid, transaction.TransactionId).Delete(&models.TransactionSplit{})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_splits.setTransactionSplits] failed to delete existing line items of transaction \"transaction_id:%d\", because %s", transaction.TransactionId, err.Error())
+		return err
+	}
+
+	for i, split := range splits {
+		transactionSplit := &models.TransactionSplit{
+			TransactionId:   transaction.TransactionId,
+			LineIndex:       int32(i),
+			Uid:             transaction.Uid,
+			CategoryId:      split.CategoryId,
+			Amount:          split.Amount,
+			TagIds:          split.TagIds,
+			Comment:         split.Comment,
+			CreatedUnixTime: now,
+		}
+
+		_, err = sess.Insert(transactionSplit)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_splits.setTransactionSplits] failed to insert line item \"index:%d\" of transaction \"transaction_id:%d\", because %s", i, transaction.TransactionId, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSplitCategoriesValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	categoryIds := make([]int64, len(splits))
+
+	for i, split := range splits {
+		categoryIds[i] = split.CategoryId
+	}
+
+	categoryIds = utils.ToUniqueInt64Slice(categoryIds)
+
+	var categories []*models.TransactionCategory
+	err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("category_id", categoryIds).Find(&categories)
+
+	if err != nil {
+		return err
+	} else if len(categories) != len(categoryIds) {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	expectedCategoryType := models.CATEGORY_TYPE_EXPENSE
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		expectedCategoryType = models.CATEGORY_TYPE_INCOME
+	}
+
+	for _, category := range categories {
+		if category.Hidden {
+			return errs.ErrCannotUseHiddenTransactionCategory
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		if category.Type != expectedCategoryType {
+			return errs.ErrTransactionCategoryTypeInvalid
+		}
+	}
+
+	return nil
+}
+
+func isSplitTagsValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	var tagIds []int64
+
+	for _, split := range splits {
+		tagIds = append(tagIds, split.GetTagIds()...)
+	}
+
+	tagIds = utils.ToUniqueInt64Slice(tagIds)
+
+	if len(tagIds) < 1 {
+		return nil
+	}
+
+	var tags []*models.TransactionTag
+	err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", tagIds).Find(&tags)
+
+	if err != nil {
+		return err
+	} else if len(tags) != len(tagIds) {
+		return errs.ErrTransactionTagNotFound
+	}
+
+	for _, tag := range tags {
+		if tag.Hidden {
+			return errs.ErrCannotUseHiddenTransactionTag
+		}
+	}
+
+	return nil
+}
+
+// getTransactionSplitsMap returns a map of line items of the specified transactions ordered by line index, the key of map is transaction id
+func getTransactionSplitsMap(sess *xorm.Session, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	allSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(transactionIds); i += pageCountForLoadTransactionAmounts {
+		end := i + pageCountForLoadTransactionAmounts
+
+		if end > len(transactionIds) {
+			end = len(transactionIds)
+		}
+
+		var splits []*models.TransactionSplit
+		err := sess.Where("uid=?", uid).In("transaction_id", transactionIds[i:end]).OrderBy("transaction_id asc, line_index asc").Find(&splits)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, split := range splits {
+			allSplits[split.TransactionId] = append(allSplits[split.TransactionId], split)
+		}
+	}
+
+	return allSplits, nil
+}
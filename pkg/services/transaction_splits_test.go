@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionSplitService_GetSplitsByTransactionId_InvalidParameters(t *testing.T) {
+	service := &TransactionSplitService{}
+
+	_, err := service.GetSplitsByTransactionId(nil, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetSplitsByTransactionId(nil, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction id is invalid")
+}
+
+func TestTransactionSplitService_GetSplitsByTransactionIds_InvalidParameters(t *testing.T) {
+	service := &TransactionSplitService{}
+
+	_, err := service.GetSplitsByTransactionIds(nil, 0, []int64{1001})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+}
+
+func TestTransactionSplitService_SetTransactionSplits_InvalidParameters(t *testing.T) {
+	service := &TransactionSplitService{}
+	splits := []*models.TransactionSplit{
+		{CategoryId: 1001, Amount: 100},
+		{CategoryId: 1002, Amount: 200},
+	}
+
+	err := service.SetTransactionSplits(nil, 0, 1001, splits)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.SetTransactionSplits(nil, 1001, 0, splits)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction id is invalid")
+}
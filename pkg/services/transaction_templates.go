@@ -53,29 +53,37 @@ func (s *TransactionTemplateService) GetTotalScheduledTemplateCountByUid(c core.
 }
 
 // GetAllTemplatesByUid returns all transaction template models of user
-func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, uid int64, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
+func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, uid int64, fundId int64, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
 	var templates []*models.TransactionTemplate
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_type=?", uid, false, templateType).Find(&templates)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND fund_id=? AND deleted=? AND template_type=?", uid, fundId, false, templateType).Find(&templates)
 
 	return templates, err
 }
 
 // GetTemplateByTemplateId returns a transaction template model according to transaction template id
-func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid int64, templateId int64) (*models.TransactionTemplate, error) {
+func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid int64, fundId int64, templateId int64) (*models.TransactionTemplate, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
 	if templateId <= 0 {
 		return nil, errs.ErrTransactionTemplateIdInvalid
 	}
 
 	template := &models.TransactionTemplate{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(templateId).Where("uid=? AND deleted=?", uid, false).Get(template)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(templateId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Get(template)
 
 	if err != nil {
 		return nil, err
@@ -87,13 +95,17 @@ func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid
 }
 
 // GetMaxDisplayOrder returns the max display order
-func (s *TransactionTemplateService) GetMaxDisplayOrder(c core.Context, uid int64, templateType models.TransactionTemplateType) (int32, error) {
+func (s *TransactionTemplateService) GetMaxDisplayOrder(c core.Context, uid int64, fundId int64, templateType models.TransactionTemplateType) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return 0, errs.ErrFundIdInvalid
+	}
+
 	template := &models.TransactionTemplate{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=? AND template_type=?", uid, false, templateType).OrderBy("display_order desc").Limit(1).Get(template)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "fund_id", "deleted", "display_order").Where("uid=? AND fund_id=? AND deleted=? AND template_type=?", uid, fundId, false, templateType).OrderBy("display_order desc").Limit(1).Get(template)
 
 	if err != nil {
 		return 0, err
@@ -134,6 +146,54 @@ func (s *TransactionTemplateService) CreateTemplate(c core.Context, template *mo
 	})
 }
 
+// CreateTemplates saves new transaction template models of the same user to database in one transaction
+func (s *TransactionTemplateService) CreateTemplates(c core.Context, uid int64, templates []*models.TransactionTemplate) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if len(templates) < 1 {
+		return nil
+	}
+
+	templateUuids := s.GenerateUuids(uuid.UUID_TYPE_TEMPLATE, uint16(len(templates)))
+
+	if len(templateUuids) < len(templates) {
+		return errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	for i, template := range templates {
+		if template.Uid != uid {
+			return errs.ErrUserIdInvalid
+		}
+
+		template.TemplateId = templateUuids[i]
+		template.Deleted = false
+		template.CreatedUnixTime = now
+		template.UpdatedUnixTime = now
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for _, template := range templates {
+			err := s.isTemplateValid(sess, template)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Insert(template)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // ModifyTemplate saves an existed transaction template model to database
 func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *models.TransactionTemplate) error {
 	if template.Uid <= 0 {
@@ -149,7 +209,7 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 			return err
 		}
 
-		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
+		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "scheduled_occurrence_count", "scheduled_exception_dates", "scheduled_weekend_rule", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
 
 		if err != nil {
 			return err
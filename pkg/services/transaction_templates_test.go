@@ -0,0 +1,20 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionTemplateService_CreateTemplates_InvalidParameters(t *testing.T) {
+	service := &TransactionTemplateService{}
+
+	err := service.CreateTemplates(nil, 0, []*models.TransactionTemplate{{Uid: 1001}})
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.CreateTemplates(nil, 1001, nil)
+	assert.Nil(t, err)
+}
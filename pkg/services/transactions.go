@@ -76,11 +76,11 @@ func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, pageC
 
 // GetAllTransactionsByMaxTime returns all transactions before given time
 func (s *TransactionService) GetAllTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, count int32, noDuplicated bool) ([]*models.Transaction, error) {
-	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", 1, count, false, noDuplicated)
+	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", nil, 1, count, false, noDuplicated)
 }
 
 // GetAllSpecifiedTransactions returns all transactions that match given conditions
-func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, query *models.TransactionQueryExpression, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -88,7 +88,7 @@ func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagIds, noTags, tagFilterType, amountFilter, keyword, 1, pageCount, false, noDuplicated)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagIds, noTags, tagFilterType, amountFilter, keyword, query, 1, pageCount, false, noDuplicated)
 
 		if err != nil {
 			return nil, err
@@ -116,7 +116,7 @@ func (s *TransactionService) GetAllTransactionsWithAccountBalanceByMaxTime(c cor
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", 1, pageCount, false, true)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", nil, 1, pageCount, false, true)
 
 		if err != nil {
 			return nil, 0, 0, 0, 0, err
@@ -198,7 +198,7 @@ func (s *TransactionService) GetAllTransactionsWithAccountBalanceByMaxTime(c cor
 }
 
 // GetTransactionsByMaxTime returns transactions before given time
-func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, query *models.TransactionQueryExpression, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -235,6 +235,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, noDuplicated)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
+	sess = s.appendTransactionQueryConditionToQuery(sess, uid, query)
 
 	err = sess.Limit(int(actualCount), int(count*(page-1))).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -242,7 +243,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 }
 
 // GetTransactionsInMonthByPage returns all transactions in given year and month
-func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, query *models.TransactionQueryExpression) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -269,6 +270,7 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
+	sess = s.appendTransactionQueryConditionToQuery(sess, uid, query)
 
 	err = sess.OrderBy("transaction_time desc").Find(&transactions)
 
@@ -309,13 +311,39 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 	return transaction, nil
 }
 
+// GetTransactionByFundIdAndTransactionId returns a transaction model in the specified fund according to transaction id, regardless of which member created it
+func (s *TransactionService) GetTransactionByFundIdAndTransactionId(c core.Context, uid int64, fundId int64, transactionId int64) (*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	transaction := &models.Transaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("fund_id=? AND deleted=?", fundId, false).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	return transaction, nil
+}
+
 // GetAllTransactionCount returns total count of transactions
 func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64) (int64, error) {
-	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "")
+	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", nil)
 }
 
 // GetTransactionCount returns count of transactions
-func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string) (int64, error) {
+func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, query *models.TransactionQueryExpression) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -334,12 +362,159 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
+	sess = s.appendTransactionQueryConditionToQuery(sess, uid, query)
 
 	return sess.Count(&models.Transaction{})
 }
 
-// CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+// ResolveTransactionQueryNames fills the ids of categories, accounts, tags and payees matched by the names in the transaction query,
+// the terms which match nothing would filter out all transactions
+func (s *TransactionService) ResolveTransactionQueryNames(c core.Context, uid int64, fundId int64, query *models.TransactionQueryExpression) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if query == nil {
+		return nil
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	if categoryTerms := query.GetTermsByField(models.TRANSACTION_QUERY_FIELD_CATEGORY); len(categoryTerms) > 0 {
+		var categories []*models.TransactionCategory
+		err := sess.Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&categories)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(categoryTerms); i++ {
+			categoryTerms[i].ResolvedIds = TransactionCategories.GetCategoryOrSubCategoryIdsByCategoryName(categories, categoryTerms[i].Value)
+		}
+	}
+
+	if accountTerms := query.GetTermsByField(models.TRANSACTION_QUERY_FIELD_ACCOUNT); len(accountTerms) > 0 {
+		var accounts []*models.Account
+		err := sess.Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&accounts)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(accountTerms); i++ {
+			accountTerms[i].ResolvedIds = Accounts.GetAccountOrSubAccountIdsByAccountName(accounts, accountTerms[i].Value)
+		}
+	}
+
+	if tagTerms := query.GetTermsByField(models.TRANSACTION_QUERY_FIELD_TAG); len(tagTerms) > 0 {
+		var tags []*models.TransactionTag
+		err := sess.Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&tags)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(tagTerms); i++ {
+			tagTerms[i].ResolvedIds = make([]int64, 0)
+
+			for j := 0; j < len(tags); j++ {
+				if tags[j].Name == tagTerms[i].Value {
+					tagTerms[i].ResolvedIds = append(tagTerms[i].ResolvedIds, tags[j].TagId)
+				}
+			}
+		}
+	}
+
+	if payeeTerms := query.GetTermsByField(models.TRANSACTION_QUERY_FIELD_PAYEE); len(payeeTerms) > 0 {
+		var payees []*models.Payee
+		err := sess.Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Find(&payees)
+
+		if err != nil {
+			return err
+		}
+
+		payeeNameMap := Payees.GetPayeeNameMapByList(payees)
+
+		for i := 0; i < len(payeeTerms); i++ {
+			payeeTerms[i].ResolvedIds = make([]int64, 0)
+
+			if payee, exists := payeeNameMap[models.NormalizePayeeName(payeeTerms[i].Value)]; exists {
+				payeeTerms[i].ResolvedIds = append(payeeTerms[i].ResolvedIds, payee.PayeeId)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetDuplicateCandidateTransactions returns the existing transactions which may be duplicated by the specified new transactions,
+// including the transactions in the same accounts within the duplicate time window or with the same external ids
+func (s *TransactionService) GetDuplicateCandidateTransactions(c core.Context, uid int64, fundId int64, transactions []*models.Transaction) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	accountIds := make([]int64, 0, len(transactions))
+	externalIdsMap := make(map[string]bool)
+	minTransactionUnixTime := int64(0)
+	maxTransactionUnixTime := int64(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.AccountId <= 0 {
+			continue
+		}
+
+		accountIds = append(accountIds, transaction.AccountId)
+
+		if transaction.ExternalId != "" {
+			externalIdsMap[transaction.ExternalId] = true
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+		if len(accountIds) == 1 || transactionUnixTime < minTransactionUnixTime {
+			minTransactionUnixTime = transactionUnixTime
+		}
+
+		if len(accountIds) == 1 || transactionUnixTime > maxTransactionUnixTime {
+			maxTransactionUnixTime = transactionUnixTime
+		}
+	}
+
+	if len(accountIds) < 1 {
+		return make([]*models.Transaction, 0), nil
+	}
+
+	accountIds = utils.ToUniqueInt64Slice(accountIds)
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(minTransactionUnixTime - models.ImportTransactionDuplicateTimeWindow)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(maxTransactionUnixTime + models.ImportTransactionDuplicateTimeWindow)
+	matchCondition := builder.Cond(builder.And(builder.Gte{"transaction_time": minTransactionTime}, builder.Lte{"transaction_time": maxTransactionTime}))
+
+	if len(externalIdsMap) > 0 {
+		externalIds := make([]string, 0, len(externalIdsMap))
+
+		for externalId := range externalIdsMap {
+			externalIds = append(externalIds, externalId)
+		}
+
+		matchCondition = builder.Or(matchCondition, builder.In("external_id", externalIds))
+	}
+
+	var existingTransactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).And(builder.In("account_id", accountIds)).And(matchCondition).Find(&existingTransactions)
+
+	return existingTransactions, err
+}
+
+// CreateTransaction saves a new transaction to database, and links the specified members (if not nil) and saves the split line items to the transaction in the same database transaction
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, members []*models.TransactionMember, splits []*models.TransactionSplit) error {
+	return s.createTransaction(c, transaction, tagIds, pictureIds, members, splits, nil)
+}
+
+// createTransaction saves a new transaction to database, and calls afterCreated (if not nil) in the same database transaction after the transaction is created
+func (s *TransactionService) createTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, members []*models.TransactionMember, splits []*models.TransactionSplit, afterCreated func(sess *xorm.Session) error) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -351,6 +526,18 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		return err
 	}
 
+	err = s.fillPayeeDefaultCategory(c, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	ruleMemberIds, tagIds, err := s.applyTransactionRules(c, transaction, tagIds, nil)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	needTransactionUuidCount := 1
@@ -408,12 +595,59 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	userDataDb := s.UserDataDB(transaction.Uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		if members != nil {
+			err = setTransactionMembers(c, sess, transaction, members, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateTransaction] failed to link members to transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+				return err
+			}
+		} else {
+			err = insertTransactionMembersWithEqualSplit(sess, transaction.FundId, transaction.TransactionId, ruleMemberIds, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateTransaction] failed to link members set by transaction rules to transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+				return err
+			}
+		}
+
+		if len(splits) > 0 {
+			err = setTransactionSplits(c, sess, transaction, splits, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateTransaction] failed to set split line items of transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+				return err
+			}
+		}
+
+		err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), newTransactionFundActivity(transaction.Uid, transaction, models.FUND_ACTIVITY_ACTION_CREATE, nil, transaction))
+
+		if err != nil {
+			return err
+		}
+
+		if afterCreated != nil {
+			return afterCreated(sess)
+		}
+
+		return nil
 	})
 }
 
-// BatchCreateTransactions saves new transactions to database
-func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, processHandler core.TaskProcessUpdateHandler) error {
+// BatchCreateTransactions saves new transactions and their split line items to database, the keys of allTagIds and allSplits are the indexes of transactions,
+// the transaction rules of the fund are applied when applyRules is true, which should be false if the rules have been applied when previewing
+func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, allSplits map[int][]*models.TransactionSplit, applyRules bool, processHandler core.TaskProcessUpdateHandler) error {
+	return s.batchCreateTransactions(c, uid, transactions, allTagIds, allSplits, applyRules, processHandler, nil)
+}
+
+// batchCreateTransactions saves new transactions to database, and calls afterCreated (if not nil) in the same database transaction after all transactions are created
+func (s *TransactionService) batchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, allSplits map[int][]*models.TransactionSplit, applyRules bool, processHandler core.TaskProcessUpdateHandler, afterCreated func(sess *xorm.Session) error) error {
 	now := time.Now().Unix()
 	currentProcess := float64(0)
 	processUpdateStep := int(math.Max(100.0, float64(len(transactions)/100.0)))
@@ -421,6 +655,13 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 	needTransactionUuidCount := uint16(0)
 	needTagIndexUuidCount := uint16(0)
 
+	if allTagIds == nil {
+		allTagIds = make(map[int][]int64)
+	}
+
+	allRuleMemberIds := make(map[int][]int64)
+	ruleEngines := make(map[int64]*models.TransactionRuleEngine)
+
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 
@@ -435,6 +676,28 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 			return err
 		}
 
+		err = s.fillPayeeDefaultCategory(c, transaction)
+
+		if err != nil {
+			return err
+		}
+
+		if applyRules {
+			ruleMemberIds, tagIds, err := s.applyTransactionRules(c, transaction, allTagIds[i], ruleEngines)
+
+			if err != nil {
+				return err
+			}
+
+			if len(tagIds) > 0 {
+				allTagIds[i] = tagIds
+			}
+
+			if len(ruleMemberIds) > 0 {
+				allRuleMemberIds[i] = ruleMemberIds
+			}
+		}
+
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 			needTransactionUuidCount += 2
 		} else {
@@ -536,6 +799,26 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 				log.Errorf(c, "[transactions.BatchCreateTransactions] failed to create trasaction (datetime: %s, type: %s, amount: %d)", utils.FormatUnixTimeToLongDateTime(transactionUnixTime, transactionTimeZone), transaction.Type, transaction.Amount)
 				return err
 			}
+
+			err = insertTransactionMembersWithEqualSplit(sess, transaction.FundId, transaction.TransactionId, allRuleMemberIds[i], now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.BatchCreateTransactions] failed to link members set by transaction rules to transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+				return err
+			}
+
+			if len(allSplits[i]) > 0 {
+				err = setTransactionSplits(c, sess, transaction, allSplits[i], now)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.BatchCreateTransactions] failed to set split line items of transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+					return err
+				}
+			}
+		}
+
+		if afterCreated != nil {
+			return afterCreated(sess)
 		}
 
 		return nil
@@ -562,7 +845,7 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 
 	for i := 0; i < s.UserDataDBCount(); i++ {
 		var templates []*models.TransactionTemplate
-		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND template_type=? AND (scheduled_frequency_type=? OR scheduled_frequency_type=?) AND (scheduled_start_time IS NULL OR scheduled_start_time<=?) AND (scheduled_end_time IS NULL OR scheduled_end_time>=?) AND scheduled_at>=? AND scheduled_at<?", false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, startTime.Unix(), startTime.Unix(), minScheduledAt, maxScheduledAt).Find(&templates)
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND template_type=? AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?) AND (scheduled_start_time IS NULL OR scheduled_start_time<=?) AND (scheduled_end_time IS NULL OR scheduled_end_time>=?) AND scheduled_at>=? AND scheduled_at<?", false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY_WEEKDAY, startTime.Unix(), startTime.Unix(), minScheduledAt, maxScheduledAt).Find(&templates)
 
 		if err != nil {
 			return err
@@ -590,15 +873,16 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 			continue
 		}
 
-		if (template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY) ||
-			template.ScheduledFrequency == "" {
+		if template.IsScheduledOccurrenceCountReached() {
 			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency", template.TemplateId)
+			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, all %d occurrences have been created", template.TemplateId, template.ScheduledOccurrenceCount)
 			continue
 		}
 
-		frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(template.ScheduledFrequency, ","))
+		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
+		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
+		transactionTime := time.Unix(transactionUnixTime, 0).In(templateTimeZone)
+		scheduled, err := template.IsScheduledOnDate(transactionTime)
 
 		if err != nil {
 			skipCount++
@@ -606,18 +890,9 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 			continue
 		}
 
-		frequencyValueSet := utils.ToSet(frequencyValues)
-		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
-		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
-		transactionTime := time.Unix(transactionUnixTime, 0).In(templateTimeZone)
-
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, startTimeInUTC.Weekday())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && !frequencyValueSet[int64(transactionTime.Day())] {
+		if !scheduled {
 			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d of month", template.TemplateId, startTimeInUTC.Day())
+			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, utils.FormatUnixTimeToLongDate(transactionUnixTime, templateTimeZone))
 			continue
 		}
 
@@ -649,6 +924,7 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 
 		transaction := &models.Transaction{
 			Uid:               template.Uid,
+			FundId:            template.FundId,
 			Type:              transactionDbType,
 			CategoryId:        template.CategoryId,
 			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
@@ -667,7 +943,20 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		}
 
 		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+
+		// The created occurrence count is updated in the same database transaction, so the count never misses a created transaction
+		err = s.createTransaction(c, transaction, tagIds, nil, nil, nil, func(sess *xorm.Session) error {
+			updatedRows, err := sess.ID(template.TemplateId).Where("uid=? AND deleted=?", template.Uid, false).Incr("scheduled_created_count").Update(&models.TransactionTemplate{})
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateScheduledTransactions] failed to update created occurrence count of transaction template \"id:%d\", because %s", template.TemplateId, err.Error())
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionTemplateNotFound
+			}
+
+			return nil
+		})
 
 		if err == nil {
 			successCount++
@@ -683,9 +972,11 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 	return nil
 }
 
-// ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
-	if transaction.Uid <= 0 {
+// ModifyTransaction saves an existed transaction to database, reconciled transaction can only be modified when unlockReconciled is set,
+// the members and the split line items of the transaction are replaced with the specified ones (if not nil) and the revision (if not nil)
+// is saved in the same database transaction, the actor is the user who modifies the transaction and may not be its creator
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, actorUid int64, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, members []*models.TransactionMember, splits []*models.TransactionSplit, transactionRevision *models.TransactionRevision, unlockReconciled bool) error {
+	if transaction.Uid <= 0 || actorUid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
@@ -696,482 +987,217 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return errs.ErrSystemIsBusy
 	}
 
-	updateCols := make([]string, 0, 16)
-
-	now := time.Now().Unix()
-
-	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-	transaction.UpdatedUnixTime = now
-	updateCols = append(updateCols, "updated_unix_time")
-
 	addTagIds = utils.ToUniqueInt64Slice(addTagIds)
 	removeTagIds = utils.ToUniqueInt64Slice(removeTagIds)
 
-	transactionTagIndexes := make([]*models.TransactionTagIndex, len(addTagIds))
-
-	for i := 0; i < len(addTagIds); i++ {
-		transactionTagIndexes[i] = &models.TransactionTagIndex{
-			TagIndexId:      tagIndexUuids[i],
-			Uid:             transaction.Uid,
-			FundId:          transaction.FundId,
-			Deleted:         false,
-			TagId:           addTagIds[i],
-			TransactionId:   transaction.TransactionId,
-			TransactionTime: transaction.TransactionTime,
-			CreatedUnixTime: now,
-			UpdatedUnixTime: now,
-		}
-	}
+	transactionTagIndexes := s.getNewTransactionTagIndexes(transaction, addTagIds, tagIndexUuids, time.Now().Unix())
 
 	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify current transaction
-		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
-
-		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction, because %s", err.Error())
-			return err
-		} else if !has {
-			return errs.ErrTransactionNotFound
-		}
-
-		transaction.Type = oldTransaction.Type
+		if transactionRevision != nil {
+			err := lockTransactionsForRevision(sess, transaction.Uid, []int64{transaction.TransactionId})
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			transaction.RelatedId = oldTransaction.RelatedId
+			if err != nil {
+				return err
+			}
 		}
 
-		// Check whether account id is valid
-		err = s.isAccountIdValid(transaction)
+		err := s.doModifyTransaction(c, sess, transaction, actorUid, currentTagIdsCount, transactionTagIndexes, addTagIds, removeTagIds, addPictureIds, removePictureIds, unlockReconciled)
 
 		if err != nil {
 			return err
 		}
 
-		// Get and verify source and destination account (if necessary)
-		sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+		err = s.doModifyTransactionMembersAndSplits(c, sess, transaction, members, splits)
 
 		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get account, because %s", err.Error())
 			return err
 		}
 
-		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-			return errs.ErrCannotModifyTransactionInHiddenAccount
-		}
-
-		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
-			return errs.ErrCannotModifyTransactionInParentAccount
+		if transactionRevision != nil {
+			return insertTransactionRevision(c, sess, transactionRevision)
 		}
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
-			sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
-			return errs.ErrTransactionSourceAndDestinationAmountNotEqual
-		}
+		return nil
+	})
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
-			(transaction.Amount < 0 || transaction.RelatedAccountAmount < 0) {
-			return errs.ErrTransferTransactionAmountCannotBeLessThanZero
-		}
+	if err != nil {
+		return err
+	}
 
-		oldSourceAccount, oldDestinationAccount, err := s.getOldAccountModels(sess, transaction, oldTransaction, sourceAccount, destinationAccount)
+	return nil
+}
 
-		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get old account, because %s", err.Error())
-			return err
-		}
+// ModifyTransactionMembersAndSplits replaces the members and the split line items of an existed transaction with the specified ones (if not nil)
+// and saves the revision (if not nil) in one database transaction, reconciled transaction can only be modified when unlockReconciled is set
+func (s *TransactionService) ModifyTransactionMembersAndSplits(c core.Context, uid int64, transactionId int64, members []*models.TransactionMember, splits []*models.TransactionSplit, transactionRevision *models.TransactionRevision, unlockReconciled bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
 
-		if oldSourceAccount.Hidden || (oldDestinationAccount != nil && oldDestinationAccount.Hidden) {
-			return errs.ErrCannotAddTransactionToHiddenAccount
-		}
+	if transactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
 
-		// Append modified columns and verify
-		if transaction.CategoryId != oldTransaction.CategoryId {
-			// Get and verify category
-			err = s.isCategoryValid(sess, transaction)
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if transactionRevision != nil {
+			err := lockTransactionsForRevision(sess, uid, []int64{transactionId})
 
 			if err != nil {
 				return err
 			}
-
-			updateCols = append(updateCols, "category_id")
 		}
 
-		modifyTransactionTime := false
-
-		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
-			if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-				return errs.ErrBalanceModificationTransactionCannotModifyTime
-			}
-
-			sameSecondLatestTransaction := &models.Transaction{}
-			minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-			maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-
-			has, err = sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
-
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to get trasaction time, because %s", err.Error())
-				return err
-			}
-
-			if has && sameSecondLatestTransaction.TransactionTime < maxTransactionTime-1 {
-				transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
-			} else if has && sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
-
-			updateCols = append(updateCols, "transaction_time")
-			modifyTransactionTime = true
-		}
-
-		if transaction.TimezoneUtcOffset != oldTransaction.TimezoneUtcOffset {
-			updateCols = append(updateCols, "timezone_utc_offset")
-		}
-
-		if transaction.AccountId != oldTransaction.AccountId {
-			updateCols = append(updateCols, "account_id")
-		}
-
-		if transaction.Amount != oldTransaction.Amount {
-			if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-				transaction.RelatedAccountAmount = oldTransaction.RelatedAccountAmount + transaction.Amount - oldTransaction.Amount
-				updateCols = append(updateCols, "related_account_amount")
-			}
-
-			updateCols = append(updateCols, "amount")
-		}
-
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
-				updateCols = append(updateCols, "related_account_id")
-			}
-
-			if transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
-				updateCols = append(updateCols, "related_account_amount")
-			}
-		}
-
-		if transaction.HideAmount != oldTransaction.HideAmount {
-			updateCols = append(updateCols, "hide_amount")
-		}
-
-		if transaction.Comment != oldTransaction.Comment {
-			updateCols = append(updateCols, "comment")
-		}
-
-		if transaction.GeoLongitude != oldTransaction.GeoLongitude {
-			updateCols = append(updateCols, "geo_longitude")
-		}
-
-		if transaction.GeoLatitude != oldTransaction.GeoLatitude {
-			updateCols = append(updateCols, "geo_latitude")
-		}
-
-		// Get and verify tags
-		err = s.isTagsValid(sess, transaction, transactionTagIndexes, addTagIds)
-
-		if err != nil {
-			return err
-		}
-
-		// Get and verify pictures
-		err = s.isPicturesValid(sess, transaction, addPictureIds)
+		transaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
 
 		if err != nil {
 			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
 		}
 
-		// Not allow to add transaction before balance modification transaction
-		if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			otherTransactionExists := false
-
-			if destinationAccount != nil && sourceAccount.AccountId != destinationAccount.AccountId {
-				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?) AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, destinationAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
-			} else {
-				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND account_id=? AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
-			}
+		if !unlockReconciled {
+			reconciled, err := isTransactionReconciled(sess, transaction)
 
 			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to get whether other transactions exist, because %s", err.Error())
 				return err
-			} else if otherTransactionExists {
-				return errs.ErrCannotAddTransactionBeforeBalanceModificationTransaction
+			} else if reconciled {
+				return errs.ErrCannotModifyReconciledTransaction
 			}
 		}
 
-		// Update transaction row
-		updatedRows, err := sess.ID(transaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", transaction.Uid, false).Update(transaction)
+		err = s.doModifyTransactionMembersAndSplits(c, sess, transaction, members, splits)
 
 		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction, because %s", err.Error())
 			return err
-		} else if updatedRows < 1 {
-			return errs.ErrTransactionNotFound
 		}
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			relatedTransaction := s.GetRelatedTransferTransaction(transaction)
-
-			if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
-
-			relatedUpdateCols := s.getRelatedUpdateColumns(updateCols)
-			updatedRows, err := sess.ID(relatedTransaction.TransactionId).Cols(relatedUpdateCols...).Where("uid=? AND deleted=?", relatedTransaction.Uid, false).Update(relatedTransaction)
-
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction, because %s", err.Error())
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction")
-				return errs.ErrDatabaseOperationFailed
-			}
+		if transactionRevision != nil {
+			return insertTransactionRevision(c, sess, transactionRevision)
 		}
 
-		// Update transaction tag index
-		if len(removeTagIds) > 0 {
-			tagIndexUpdateModel := &models.TransactionTagIndex{
-				Deleted:         true,
-				DeletedUnixTime: now,
-			}
-
-			deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("tag_id", removeTagIds).Update(tagIndexUpdateModel)
-
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction tag index, because %s", err.Error())
-				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionTagNotFound
-			}
-		}
+		return nil
+	})
+}
 
-		if len(transactionTagIndexes) > 0 {
-			for i := 0; i < len(transactionTagIndexes); i++ {
-				transactionTagIndex := transactionTagIndexes[i]
-				transactionTagIndex.TransactionTime = transaction.TransactionTime
+// BatchModifyTransactions applies the same modification to all specified transactions of the fund in one database transaction, the transactions
+// may be created by other users of the fund and the permission is checked by checkTransaction, nothing is saved if any transaction cannot be modified,
+// and the errors of these transactions are returned in a map whose key is transaction id
+func (s *TransactionService) BatchModifyTransactions(c core.Context, uid int64, fundId int64, transactionIds []int64, modification *models.TransactionBatchModification, checkTransaction func(transaction *models.Transaction) error) (int, map[int64]error, error) {
+	if uid <= 0 {
+		return 0, nil, errs.ErrUserIdInvalid
+	}
 
-				_, err := sess.Insert(transactionTagIndex)
+	if fundId <= 0 {
+		return 0, nil, errs.ErrFundIdInvalid
+	}
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction tag index, because %s", err.Error())
-					return err
-				}
-			}
-		} else if len(transactionTagIndexes) == 0 && currentTagIdsCount > 0 && modifyTransactionTime {
-			tagIndexUpdateModel := &models.TransactionTagIndex{
-				TransactionTime: transaction.TransactionTime,
-			}
+	if len(transactionIds) > models.MaximumTransactionsCountOfBatchModification {
+		return 0, nil, errs.ErrTooManyTransactionsToBatchModify
+	}
 
-			_, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(tagIndexUpdateModel)
+	err := modification.Validate()
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction tag index, because %s", err.Error())
-				return err
-			}
-		}
+	if err != nil {
+		return 0, nil, err
+	}
 
-		// Update transaction picture
-		if len(removePictureIds) > 0 {
-			pictureUpdateModel := &models.TransactionPictureInfo{
-				Deleted:         true,
-				DeletedUnixTime: now,
-			}
+	modifiedCount := 0
+	transactionErrors := make(map[int64]error)
 
-			deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Update(pictureUpdateModel)
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := lockFundTransactionsForRevision(sess, fundId, transactionIds)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction picture info, because %s", err.Error())
-				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionPictureNotFound
-			}
+		if err != nil {
+			return err
 		}
 
-		if len(addPictureIds) > 0 {
-			pictureUpdateModel := &models.TransactionPictureInfo{
-				TransactionId:   transaction.TransactionId,
-				UpdatedUnixTime: now,
-			}
-
-			_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", addPictureIds).Update(pictureUpdateModel)
+		if len(modification.MemberIds) > 0 {
+			memberIds := utils.ToUniqueInt64Slice(modification.MemberIds)
+			memberCount, err := sess.Where("fund_id=?", fundId).In("member_id", memberIds).Count(&models.FundMember{})
 
 			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update new transaction picture info, because %s", err.Error())
 				return err
+			} else if memberCount != int64(len(memberIds)) {
+				return errs.ErrMemberNotFound
 			}
 		}
 
-		// Update account table
-		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			if transaction.AccountId != oldTransaction.AccountId {
-				return errs.ErrBalanceModificationTransactionCannotChangeAccountId
-			}
-
-			if transaction.Amount != oldTransaction.Amount && transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
-				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
-			var oldAccountNewAmount int64 = 0
-			var newAccountNewAmount int64 = 0
-
-			if transaction.AccountId == oldTransaction.AccountId {
-				oldAccountNewAmount = transaction.Amount
-			} else if transaction.AccountId != oldTransaction.AccountId {
-				newAccountNewAmount = transaction.Amount
-			}
-
-			if oldAccountNewAmount != oldTransaction.Amount {
-				oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
-
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-
-			if newAccountNewAmount != 0 {
-				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+		processedTransactionIds := make(map[int64]bool, len(transactionIds))
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
-			var oldAccountNewAmount int64 = 0
-			var newAccountNewAmount int64 = 0
+		for _, transactionId := range transactionIds {
+			modified, err := s.doBatchModifyTransaction(c, sess, uid, fundId, transactionId, modification, checkTransaction, processedTransactionIds)
 
-			if transaction.AccountId == oldTransaction.AccountId {
-				oldAccountNewAmount = transaction.Amount
-			} else if transaction.AccountId != oldTransaction.AccountId {
-				newAccountNewAmount = transaction.Amount
+			if err != nil && errs.IsCustomError(err) {
+				transactionErrors[transactionId] = err
+				continue
+			} else if err != nil {
+				log.Errorf(c, "[transactions.BatchModifyTransactions] failed to modify transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+				return err
 			}
 
-			if oldAccountNewAmount != oldTransaction.Amount {
-				oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
-
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
+			if modified {
+				modifiedCount++
 			}
+		}
 
-			if newAccountNewAmount != 0 {
-				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+		if len(transactionErrors) > 0 {
+			return errs.ErrTransactionBatchModificationFailed
+		}
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			var oldSourceAccountNewAmount int64 = 0
-			var newSourceAccountNewAmount int64 = 0
+		return nil
+	})
 
-			if transaction.AccountId == oldTransaction.AccountId {
-				oldSourceAccountNewAmount = transaction.Amount
-			} else if transaction.AccountId != oldTransaction.AccountId {
-				newSourceAccountNewAmount = transaction.Amount
-			}
+	if err != nil {
+		return 0, transactionErrors, err
+	}
 
-			if oldSourceAccountNewAmount != oldTransaction.Amount {
-				oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
+	return modifiedCount, nil, nil
+}
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
+// ModifyTransactionClearedStatus sets the cleared status of one transaction row, reconciled transaction can only be changed when unlockReconciled is set
+func (s *TransactionService) ModifyTransactionClearedStatus(c core.Context, uid int64, transactionId int64, clearedStatus models.TransactionClearedStatus, unlockReconciled bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
 
-			if newSourceAccountNewAmount != 0 {
-				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+	if clearedStatus != models.TRANSACTION_CLEARED_STATUS_UNCLEARED && clearedStatus != models.TRANSACTION_CLEARED_STATUS_CLEARED {
+		return errs.ErrTransactionClearedStatusInvalid
+	}
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
+	updateModel := &models.Transaction{
+		ClearedStatus:   clearedStatus,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
 
-			var oldDestinationAccountNewAmount int64 = 0
-			var newDestinationAccountNewAmount int64 = 0
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldTransaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
 
-			if transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
-				oldDestinationAccountNewAmount = transaction.RelatedAccountAmount
-			} else if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
-				newDestinationAccountNewAmount = transaction.RelatedAccountAmount
-			}
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
 
-			if oldDestinationAccountNewAmount != oldTransaction.RelatedAccountAmount {
-				oldDestinationAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(oldDestinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, oldDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldDestinationAccount.Uid, false).Update(oldDestinationAccount)
+		if oldTransaction.ClearedStatus == clearedStatus {
+			return errs.ErrNothingWillBeUpdated
+		}
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
+		if oldTransaction.ClearedStatus == models.TRANSACTION_CLEARED_STATUS_RECONCILED && !unlockReconciled {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
 
-			if newDestinationAccountNewAmount != 0 {
-				destinationAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+		updatedRows, err := sess.ID(transactionId).Cols("cleared_status", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
-				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			return errs.ErrTransactionTypeInvalid
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionNotFound
 		}
 
 		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context, uid int64, fromAccountId int64, toAccountId int64) error {
@@ -1219,6 +1245,26 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 			return errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
 		}
 
+		// reconciled transactions of from account and the balance modification transaction of to account cannot be changed
+		reconciledTransactionExists, err := sess.Cols("uid", "deleted", "cleared_status").Where("uid=? AND deleted=? AND cleared_status=? AND (account_id=? OR related_account_id=? OR (account_id=? AND type=?))", uid, false, models.TRANSACTION_CLEARED_STATUS_RECONCILED, fromAccountId, fromAccountId, toAccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to get whether reconciled transactions exist, because %s", err.Error())
+			return err
+		} else if reconciledTransactionExists {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
+
+		// the transactions of investment trades must stay in the account of trades
+		tradeTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=? AND transaction_id>?", uid, false, fromAccountId, 0).Limit(1).Exist(&models.InvestmentTrade{})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to get whether investment trades exist, because %s", err.Error())
+			return err
+		} else if tradeTransactionExists {
+			return errs.ErrCannotModifyInvestmentTradeTransaction
+		}
+
 		// combine balance modification transaction
 		var balanceModificationTransactions []*models.Transaction
 		err = sess.Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?)", uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, fromAccountId, toAccountId).Find(&balanceModificationTransactions)
@@ -1387,33 +1433,55 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 	})
 }
 
-// DeleteTransaction deletes an existed transaction from database
-func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transactionId int64) error {
+// DeleteTransaction deletes an existed transaction of the specified user from database, reconciled transaction can only be deleted when unlockReconciled is set,
+// the actor is the user who deletes the transaction and may not be its creator
+func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, actorUid int64, transactionId int64, unlockReconciled bool) error {
+	if uid <= 0 || actorUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.doDeleteTransaction(c, sess, uid, actorUid, transactionId, unlockReconciled)
+	})
+}
+
+// RestoreTransaction restores a deleted transaction of a fund with its transfer counterpart, tags and pictures, and re-applies the balance changes to accounts,
+// the transaction is restored for the member who created it and the check function is called to verify whether current user can restore it
+func (s *TransactionService) RestoreTransaction(c core.Context, uid int64, fundId int64, transactionId int64, check func(transaction *models.Transaction) error) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Transaction{
-		Deleted:         true,
-		DeletedUnixTime: now,
+		Deleted:         false,
+		UpdatedUnixTime: now,
+		DeletedUnixTime: 0,
 	}
 
 	tagIndexUpdateModel := &models.TransactionTagIndex{
-		Deleted:         true,
-		DeletedUnixTime: now,
+		Deleted:         false,
+		DeletedUnixTime: 0,
 	}
 
 	pictureUpdateModel := &models.TransactionPictureInfo{
-		Deleted:         true,
-		DeletedUnixTime: now,
+		Deleted:         false,
+		DeletedUnixTime: 0,
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify current transaction
+		// Get and verify deleted transaction
 		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+		has, err := sess.ID(transactionId).Where("fund_id=? AND deleted=?", fundId, true).Get(oldTransaction)
 
 		if err != nil {
 			return err
@@ -1421,6 +1489,26 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return errs.ErrTransactionNotFound
 		}
 
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			relatedTransactionId := oldTransaction.RelatedId
+			oldTransaction = &models.Transaction{}
+			has, err = sess.ID(relatedTransactionId).Where("fund_id=? AND deleted=?", fundId, true).Get(oldTransaction)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrTransactionNotFound
+			}
+		}
+
+		if check != nil {
+			if err := check(oldTransaction); err != nil {
+				return err
+			}
+		}
+
+		ownerUid := oldTransaction.Uid
+
 		// Get and verify source and destination account
 		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
 
@@ -1429,41 +1517,75 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		}
 
 		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-			return errs.ErrCannotDeleteTransactionInHiddenAccount
+			return errs.ErrCannotAddTransactionToHiddenAccount
 		}
 
 		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
-			return errs.ErrCannotDeleteTransactionInParentAccount
+			return errs.ErrCannotAddTransactionToParentAccount
 		}
 
-		// Update transaction row to deleted
-		deletedRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		// Get and verify category
+		err = s.isCategoryValid(sess, oldTransaction)
 
 		if err != nil {
 			return err
-		} else if deletedRows < 1 {
-			return errs.ErrTransactionNotFound
 		}
 
-		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		// Verify balance modification transaction
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", ownerUid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
 
 			if err != nil {
+				log.Errorf(c, "[transactions.RestoreTransaction] failed to get whether other transactions exist, because %s", err.Error())
 				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionNotFound
+			} else if otherTransactionExists {
+				return errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty
+			}
+		} else { // Not allow to restore transaction before balance modification transaction
+			otherTransactionExists := false
+
+			if destinationAccount != nil && sourceAccount.AccountId != destinationAccount.AccountId {
+				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?) AND transaction_time>=?", ownerUid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, destinationAccount.AccountId, oldTransaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
+			} else {
+				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND account_id=? AND transaction_time>=?", ownerUid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, oldTransaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
+			}
+
+			if err != nil {
+				log.Errorf(c, "[transactions.RestoreTransaction] failed to get whether other transactions exist, because %s", err.Error())
+				return err
+			} else if otherTransactionExists {
+				return errs.ErrCannotAddTransactionBeforeBalanceModificationTransaction
+			}
+		}
+
+		// Update transaction row to not deleted
+		restoredRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", ownerUid, fundId, true).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < 1 {
+			return errs.ErrTransactionNotFound
+		}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			restoredRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", ownerUid, fundId, true).Update(updateModel)
+
+			if err != nil {
+				return err
+			} else if restoredRows < 1 {
+				log.Errorf(c, "[transactions.RestoreTransaction] failed to restore related transaction \"id:%d\"", oldTransaction.RelatedId)
+				return errs.ErrTransactionNotFound
 			}
 		}
 
-		// Update transaction tag index
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
+		// Update transaction tag index and picture which were deleted with the transaction
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", ownerUid, true, oldTransaction.TransactionId, oldTransaction.DeletedUnixTime).Update(tagIndexUpdateModel)
 
 		if err != nil {
 			return err
 		}
 
-		// Update transaction picture
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", ownerUid, true, oldTransaction.TransactionId, oldTransaction.DeletedUnixTime).Update(pictureUpdateModel)
 
 		if err != nil {
 			return err
@@ -1473,68 +1595,66 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if oldTransaction.RelatedAccountAmount != 0 {
 				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 				if err != nil {
 					return err
 				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
 			if oldTransaction.Amount != 0 {
 				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 				if err != nil {
 					return err
 				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
 			if oldTransaction.Amount != 0 {
 				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 				if err != nil {
 					return err
 				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
 			if oldTransaction.Amount != 0 {
 				sourceAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+				updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 				if err != nil {
 					return err
 				} else if updatedSourceRows < 1 {
-					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
 
 			if oldTransaction.RelatedAccountAmount != 0 {
 				destinationAccount.UpdatedUnixTime = time.Now().Unix()
-				updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+				updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
 
 				if err != nil {
 					return err
 				} else if updatedDestinationRows < 1 {
-					log.Errorf(c, "[transactions.DeleteTransaction] failed to update related account balance")
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update related account balance")
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			return errs.ErrTransactionTypeInvalid
 		}
 
-		return err
+		return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), newTransactionFundActivity(uid, oldTransaction, models.FUND_ACTIVITY_ACTION_RESTORE, nil, oldTransaction))
 	})
 }
 
@@ -1610,7 +1730,7 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		return errs.ErrAccountIdInvalid
 	}
 
-	transactions, err := s.GetAllSpecifiedTransactions(c, uid, 0, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", pageCount, true)
+	transactions, err := s.GetAllSpecifiedTransactions(c, uid, 0, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", nil, pageCount, true)
 
 	if err != nil {
 		return err
@@ -1624,9 +1744,9 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		transaction := transactions[i]
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			err = s.DeleteTransaction(c, uid, transaction.RelatedId)
+			err = s.DeleteTransaction(c, uid, uid, transaction.RelatedId, true)
 		} else {
-			err = s.DeleteTransaction(c, uid, transaction.TransactionId)
+			err = s.DeleteTransaction(c, uid, uid, transaction.TransactionId, true)
 		}
 
 		if err != nil {
@@ -1661,6 +1781,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		TransactionTime:      relatedTransactionTime,
 		TimezoneUtcOffset:    originalTransaction.TimezoneUtcOffset,
 		AccountId:            originalTransaction.RelatedAccountId,
+		PayeeId:              originalTransaction.PayeeId,
 		Amount:               originalTransaction.RelatedAccountAmount,
 		RelatedId:            originalTransaction.TransactionId,
 		RelatedAccountId:     originalTransaction.AccountId,
@@ -1803,11 +1924,17 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 }
 
 // GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
+// if netRefunds is true, the linked refunds and reimbursements are counted as negative expenses in the category of their original expenses,
+// if amountsConverter is not nil, the amounts are converted to the default currency by the exchange rates applicable to the dates of the transactions
+func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, fundId int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, query *models.TransactionQueryExpression, utcOffset int16, useTransactionTimezone bool, netRefunds bool, amountsConverter *models.TransactionAmountsConverter) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
 	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
 	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
 
@@ -1823,9 +1950,10 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 		endTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
 	}
 
-	condition := "uid=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
-	conditionParams := make([]any, 0, 6)
+	condition := "uid=? AND fund_id=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
+	conditionParams := make([]any, 0, 7)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, fundId)
 	conditionParams = append(conditionParams, false)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
@@ -1858,8 +1986,9 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
+		sess = s.appendTransactionQueryConditionToQuery(sess, uid, query)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -1867,7 +1996,25 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			return nil, err
 		}
 
-		allTransactions = append(allTransactions, transactions...)
+		allSplits, err := getTransactionSplitsMap(s.UserDataDB(uid).NewSession(c), uid, s.GetTransactionIds(transactions))
+
+		if err != nil {
+			return nil, err
+		}
+
+		expandedTransactions := models.ExpandSplitTransactions(transactions, allSplits)
+
+		if netRefunds {
+			originalTransactions, err := getRefundOriginalTransactionsMap(s.UserDataDB(uid).NewSession(c), fundId, transactions)
+
+			if err != nil {
+				return nil, err
+			}
+
+			expandedTransactions = models.NetRefundTransactions(expandedTransactions, originalTransactions)
+		}
+
+		allTransactions = append(allTransactions, expandedTransactions...)
 
 		if len(transactions) < pageCountForLoadTransactionAmounts {
 			maxTransactionTime = -1
@@ -1887,12 +2034,19 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
 
 		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
 			continue
 		}
 
+		amount, err := s.getConvertedTransactionAmount(transaction, transactionUnixTime, timeZone, amountsConverter)
+
+		if err != nil {
+			return nil, err
+		}
+
 		groupKey := fmt.Sprintf("%d_%d", transaction.CategoryId, transaction.AccountId)
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -1913,7 +2067,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			transactionTotalAmountsMap[groupKey] = totalAmounts
 		}
 
-		totalAmounts.Amount += transaction.Amount
+		totalAmounts.Amount += amount
 	}
 
 	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
@@ -1926,11 +2080,17 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 }
 
 // GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+// if netRefunds is true, the linked refunds and reimbursements are counted as negative expenses in the category of their original expenses,
+// if amountsConverter is not nil, the amounts are converted to the default currency by the exchange rates applicable to the dates of the transactions
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, fundId int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, query *models.TransactionQueryExpression, utcOffset int16, useTransactionTimezone bool, netRefunds bool, amountsConverter *models.TransactionAmountsConverter) (map[int32][]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
 	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
 	var startTransactionTime, endTransactionTime int64
 	var err error
@@ -1951,9 +2111,10 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		}
 	}
 
-	condition := "uid=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
-	conditionParams := make([]any, 0, 6)
+	condition := "uid=? AND fund_id=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
+	conditionParams := make([]any, 0, 7)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, fundId)
 	conditionParams = append(conditionParams, false)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
@@ -1986,8 +2147,9 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
+		sess = s.appendTransactionQueryConditionToQuery(sess, uid, query)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -1995,7 +2157,25 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			return nil, err
 		}
 
-		allTransactions = append(allTransactions, transactions...)
+		allSplits, err := getTransactionSplitsMap(s.UserDataDB(uid).NewSession(c), uid, s.GetTransactionIds(transactions))
+
+		if err != nil {
+			return nil, err
+		}
+
+		expandedTransactions := models.ExpandSplitTransactions(transactions, allSplits)
+
+		if netRefunds {
+			originalTransactions, err := getRefundOriginalTransactionsMap(s.UserDataDB(uid).NewSession(c), fundId, transactions)
+
+			if err != nil {
+				return nil, err
+			}
+
+			expandedTransactions = models.NetRefundTransactions(expandedTransactions, originalTransactions)
+		}
+
+		allTransactions = append(allTransactions, expandedTransactions...)
 
 		if len(transactions) < pageCountForLoadTransactionAmounts {
 			maxTransactionTime = -1
@@ -2018,12 +2198,19 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(transactionUnixTime, timeZone)
 
 		if (startYearMonth > 0 && yearMonth < startYearMonth) || (endYearMonth > 0 && yearMonth > endYearMonth) {
 			continue
 		}
 
+		amount, err := s.getConvertedTransactionAmount(transaction, transactionUnixTime, timeZone, amountsConverter)
+
+		if err != nil {
+			return nil, err
+		}
+
 		groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId)
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -2044,7 +2231,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 		}
 
-		transactionAmounts.Amount += transaction.Amount
+		transactionAmounts.Amount += amount
 	}
 
 	for groupKey, transaction := range transactionsMonthlyAmountsMap {
@@ -2063,6 +2250,227 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 	return transactionsMonthlyAmounts, nil
 }
 
+// GetFutureTransactions returns all transactions in the fund whose transaction time is later than the specific time
+func (s *TransactionService) GetFutureTransactions(c core.Context, uid int64, fundId int64, minUnixTime int64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, account_id, transaction_time, timezone_utc_offset, amount, related_account_amount").Where("uid=? AND fund_id=? AND deleted=? AND transaction_time>?", uid, fundId, false, utils.GetMaxTransactionTimeFromUnixTime(minUnixTime)).Find(&transactions)
+
+	return transactions, err
+}
+
+// GetAccountsAverageMonthlyAmounts returns the average monthly net amount (income minus expense) of every account in the fund by specific date range,
+// the transactions created by scheduled transaction templates are not included
+func (s *TransactionService) GetAccountsAverageMonthlyAmounts(c core.Context, uid int64, fundId int64, startUnixTime int64, endUnixTime int64, months int) (map[int64]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if months <= 0 {
+		return make(map[int64]int64), nil
+	}
+
+	condition := "uid=? AND fund_id=? AND deleted=? AND (type=? OR type=?) AND scheduled_created=? AND transaction_time>=?"
+	conditionParams := []any{uid, fundId, false, models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE, false, utils.GetMinTransactionTimeFromUnixTime(startUnixTime)}
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	totalAmounts := make(map[int64]int64)
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+
+		finalConditionParams := make([]any, 0, len(conditionParams)+1)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+		finalConditionParams = append(finalConditionParams, maxTransactionTime)
+
+		err := s.UserDataDB(uid).NewSession(c).Select("type, account_id, transaction_time, amount").Where(condition+" AND transaction_time<=?", finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			if transactions[i].Type == models.TRANSACTION_DB_TYPE_INCOME {
+				totalAmounts[transactions[i].AccountId] += transactions[i].Amount
+			} else if transactions[i].Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				totalAmounts[transactions[i].AccountId] -= transactions[i].Amount
+			}
+		}
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	averageAmounts := make(map[int64]int64, len(totalAmounts))
+
+	for accountId, totalAmount := range totalAmounts {
+		averageAmounts[accountId] = totalAmount / int64(months)
+	}
+
+	return averageAmounts, nil
+}
+
+// GetPayeesTotalIncomeAndExpense returns the total income and expense amount of every payee and account in the fund by specific date range
+func (s *TransactionService) GetPayeesTotalIncomeAndExpense(c core.Context, uid int64, fundId int64, startUnixTime int64, endUnixTime int64, transactionType models.TransactionType, utcOffset int16, useTransactionTimezone bool) ([]*models.PayeeTotalAmount, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
+
+	if startUnixTime > 0 {
+		startLocalDateTime = utils.FormatUnixTimeToNumericLocalDateTime(startUnixTime, clientLocation)
+		startUnixTime = utils.GetMinUnixTimeWithSameLocalDateTime(startUnixTime, utcOffset)
+		startTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
+	}
+
+	if endUnixTime > 0 {
+		endLocalDateTime = utils.FormatUnixTimeToNumericLocalDateTime(endUnixTime, clientLocation)
+		endUnixTime = utils.GetMaxUnixTimeWithSameLocalDateTime(endUnixTime, utcOffset)
+		endTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	allTransactions, err := s.getPayeeStatisticTransactions(c, uid, fundId, transactionType, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payeeTotalAmountsMap := make(map[string]*models.PayeeTotalAmount)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		timeZone := clientLocation
+
+		if useTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		}
+
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+
+		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
+			continue
+		}
+
+		groupKey := fmt.Sprintf("%d_%d_%d", transaction.PayeeId, transaction.AccountId, transaction.Type)
+		totalAmount, exists := payeeTotalAmountsMap[groupKey]
+
+		if !exists {
+			totalAmount = &models.PayeeTotalAmount{
+				PayeeId:   transaction.PayeeId,
+				AccountId: transaction.AccountId,
+				Type:      transaction.Type,
+			}
+
+			payeeTotalAmountsMap[groupKey] = totalAmount
+		}
+
+		totalAmount.Amount += transaction.Amount
+		totalAmount.Count++
+	}
+
+	payeeTotalAmounts := make([]*models.PayeeTotalAmount, 0, len(payeeTotalAmountsMap))
+
+	for _, totalAmount := range payeeTotalAmountsMap {
+		payeeTotalAmounts = append(payeeTotalAmounts, totalAmount)
+	}
+
+	return payeeTotalAmounts, nil
+}
+
+// GetPayeesMonthlyIncomeAndExpense returns the monthly income and expense amount of every payee and account in the fund by specific date range
+func (s *TransactionService) GetPayeesMonthlyIncomeAndExpense(c core.Context, uid int64, fundId int64, startYear int32, startMonth int32, endYear int32, endMonth int32, transactionType models.TransactionType, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.PayeeTotalAmount, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	var startTransactionTime, endTransactionTime int64
+	var err error
+
+	if startYear > 0 && startMonth > 0 {
+		startTransactionTime, _, err = utils.GetTransactionTimeRangeByYearMonth(startYear, startMonth)
+
+		if err != nil {
+			return nil, errs.ErrSystemError
+		}
+	}
+
+	if endYear > 0 && endMonth > 0 {
+		_, endTransactionTime, err = utils.GetTransactionTimeRangeByYearMonth(endYear, endMonth)
+
+		if err != nil {
+			return nil, errs.ErrSystemError
+		}
+	}
+
+	allTransactions, err := s.getPayeeStatisticTransactions(c, uid, fundId, transactionType, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	startYearMonth := startYear*100 + startMonth
+	endYearMonth := endYear*100 + endMonth
+	payeeMonthlyAmountsMap := make(map[string]*models.PayeeTotalAmount)
+	payeeMonthlyAmountYearMonths := make(map[string]int32)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		timeZone := clientLocation
+
+		if useTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		}
+
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+
+		if (startYearMonth > 0 && yearMonth < startYearMonth) || (endYearMonth > 0 && yearMonth > endYearMonth) {
+			continue
+		}
+
+		groupKey := fmt.Sprintf("%d_%d_%d_%d", yearMonth, transaction.PayeeId, transaction.AccountId, transaction.Type)
+		totalAmount, exists := payeeMonthlyAmountsMap[groupKey]
+
+		if !exists {
+			totalAmount = &models.PayeeTotalAmount{
+				PayeeId:   transaction.PayeeId,
+				AccountId: transaction.AccountId,
+				Type:      transaction.Type,
+			}
+
+			payeeMonthlyAmountsMap[groupKey] = totalAmount
+			payeeMonthlyAmountYearMonths[groupKey] = yearMonth
+		}
+
+		totalAmount.Amount += transaction.Amount
+		totalAmount.Count++
+	}
+
+	payeeMonthlyAmounts := make(map[int32][]*models.PayeeTotalAmount)
+
+	for groupKey, totalAmount := range payeeMonthlyAmountsMap {
+		yearMonth := payeeMonthlyAmountYearMonths[groupKey]
+		payeeMonthlyAmounts[yearMonth] = append(payeeMonthlyAmounts[yearMonth], totalAmount)
+	}
+
+	return payeeMonthlyAmounts, nil
+}
+
 // GetTransactionMapByList returns a transaction map by a list
 func (s *TransactionService) GetTransactionMapByList(transactions []*models.Transaction) map[int64]*models.Transaction {
 	transactionMap := make(map[int64]*models.Transaction)
@@ -2119,6 +2527,13 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify payee
+	err = s.isPayeeValid(sess, transaction.Uid, transaction.FundId, transaction.PayeeId)
+
+	if err != nil {
+		return err
+	}
+
 	// Get and verify tags
 	err = s.isTagsValid(sess, transaction, transactionTagIndexes, tagIds)
 
@@ -2163,181 +2578,1163 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
-	// Insert transaction row
-	var relatedTransaction *models.Transaction
+	// Insert transaction row
+	var relatedTransaction *models.Transaction
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction = s.GetRelatedTransferTransaction(transaction)
+	}
+
+	insertTransactionSavePointName := "insert_transaction"
+	err = database.SetSavePoint(sess, insertTransactionSavePointName)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doCreateTransaction] failed to set save point \"%s\", because %s", insertTransactionSavePointName, err.Error())
+		return err
+	}
+
+	createdRows, err := sess.Insert(transaction)
+
+	if err != nil || createdRows < 1 { // maybe another transaction has same time
+		if err != nil {
+			log.Warnf(c, "[transactions.doCreateTransaction] cannot create trasaction, because %s, regenerate transaction time value", err.Error())
+		} else {
+			log.Warnf(c, "[transactions.doCreateTransaction] cannot create trasaction, regenerate transaction time value")
+		}
+
+		err = database.RollbackToSavePoint(sess, insertTransactionSavePointName)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to rollback to save point \"%s\", because %s", insertTransactionSavePointName, err.Error())
+			return err
+		}
+
+		sameSecondLatestTransaction := &models.Transaction{}
+		minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		has, err := sess.Where("uid=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to get trasaction time, because %s", err.Error())
+			return err
+		} else if !has {
+			log.Errorf(c, "[transactions.doCreateTransaction] it should have transactions in %d - %d, but result is empty", minTransactionTime, maxTransactionTime)
+			return errs.ErrDatabaseOperationFailed
+		} else if sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
+			return errs.ErrTooMuchTransactionInOneSecond
+		}
+
+		transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
+		createdRows, err := sess.Insert(transaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction again, because %s", err.Error())
+			return err
+		} else if createdRows < 1 {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction again")
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
+
+	if relatedTransaction != nil {
+		relatedTransaction.TransactionTime = transaction.TransactionTime + 1
+
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
+			return errs.ErrTooMuchTransactionInOneSecond
+		}
+
+		createdRows, err := sess.Insert(relatedTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add related transaction, because %s", err.Error())
+			return err
+		} else if createdRows < 1 {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add related transaction")
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
+
+	err = nil
+
+	// Insert transaction tag index
+	if len(transactionTagIndexes) > 0 {
+		for i := 0; i < len(transactionTagIndexes); i++ {
+			transactionTagIndex := transactionTagIndexes[i]
+			transactionTagIndex.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(transactionTagIndex)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction tag index, because %s", err.Error())
+				return err
+			}
+		}
+	}
+
+	// Update transaction picture
+	if len(pictureIds) > 0 {
+		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to update transaction picture info, because %s", err.Error())
+			return err
+		}
+	}
+
+	// Update account table
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		if transaction.RelatedAccountAmount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		if transaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		if transaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		if transaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedSourceRows < 1 {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if transaction.RelatedAccountAmount != 0 {
+			destinationAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedDestinationRows < 1 {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	return err
+}
+
+// doDeleteTransaction deletes an existed transaction in the specified session and reverts the balance changes of accounts
+func (s *TransactionService) doDeleteTransaction(c core.Context, sess *xorm.Session, uid int64, actorUid int64, transactionId int64, unlockReconciled bool) error {
+	now := time.Now().Unix()
+
+	updateModel := &models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	tagIndexUpdateModel := &models.TransactionTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	// Get and verify current transaction
+	oldTransaction := &models.Transaction{}
+	has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionNotFound
+	}
+
+	if !unlockReconciled {
+		reconciled, err := isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		} else if reconciled {
+			return errs.ErrCannotDeleteReconciledTransaction
+		}
+	}
+
+	tradeTransaction, err := isInvestmentTradeTransaction(sess, oldTransaction)
+
+	if err != nil {
+		return err
+	} else if tradeTransaction {
+		return errs.ErrCannotDeleteInvestmentTradeTransaction
+	}
+
+	// Get and verify source and destination account
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
+
+	if err != nil {
+		return err
+	}
+
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return errs.ErrCannotDeleteTransactionInHiddenAccount
+	}
+
+	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+		return errs.ErrCannotDeleteTransactionInParentAccount
+	}
+
+	// Update transaction row to deleted
+	deletedRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrTransactionNotFound
+	}
+
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionNotFound
+		}
+	}
+
+	// Update transaction tag index
+	_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	// Update transaction picture
+	_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	// Update account table
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		if oldTransaction.RelatedAccountAmount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doDeleteTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		if oldTransaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doDeleteTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		if oldTransaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doDeleteTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		if oldTransaction.Amount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedSourceRows < 1 {
+				log.Errorf(c, "[transactions.doDeleteTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if oldTransaction.RelatedAccountAmount != 0 {
+			destinationAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+			if err != nil {
+				return err
+			} else if updatedDestinationRows < 1 {
+				log.Errorf(c, "[transactions.doDeleteTransaction] failed to update related account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), newTransactionFundActivity(actorUid, oldTransaction, models.FUND_ACTIVITY_ACTION_DELETE, oldTransaction, nil))
+}
+
+func (s *TransactionService) doModifyTransactionMembersAndSplits(c core.Context, sess *xorm.Session, transaction *models.Transaction, members []*models.TransactionMember, splits []*models.TransactionSplit) error {
+	now := time.Now().Unix()
+
+	if members != nil && len(members) < 1 {
+		_, err := sess.Where("transaction_id=?", transaction.TransactionId).Delete(&models.TransactionMember{})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransactionMembersAndSplits] failed to unlink members from transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+			return err
+		}
+	} else if members != nil {
+		err := setTransactionMembers(c, sess, transaction, members, now)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransactionMembersAndSplits] failed to link members to transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+			return err
+		}
+	}
+
+	if splits != nil {
+		err := setTransactionSplits(c, sess, transaction, splits, now)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransactionMembersAndSplits] failed to set split line items of transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) doModifyTransaction(c core.Context, sess *xorm.Session, transaction *models.Transaction, actorUid int64, currentTagIdsCount int, transactionTagIndexes []*models.TransactionTagIndex, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, unlockReconciled bool) error {
+	updateCols := make([]string, 0, 16)
+
+	now := time.Now().Unix()
+
+	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+	transaction.UpdatedUnixTime = now
+	updateCols = append(updateCols, "updated_unix_time")
+
+	// Get and verify current transaction
+	oldTransaction := &models.Transaction{}
+	has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doModifyTransaction] failed to get current transaction, because %s", err.Error())
+		return err
+	} else if !has {
+		return errs.ErrTransactionNotFound
+	}
+
+	transaction.Type = oldTransaction.Type
+	transaction.FundId = oldTransaction.FundId
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transaction.RelatedId = oldTransaction.RelatedId
+	}
+
+	if !unlockReconciled {
+		reconciled, err := isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to get related transaction, because %s", err.Error())
+			return err
+		} else if reconciled {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
+	}
+
+	if transaction.AccountId != oldTransaction.AccountId || transaction.Amount != oldTransaction.Amount {
+		tradeTransaction, err := isInvestmentTradeTransaction(sess, oldTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to get whether transaction belongs to investment trade, because %s", err.Error())
+			return err
+		} else if tradeTransaction {
+			return errs.ErrCannotModifyInvestmentTradeTransaction
+		}
+	}
+
+	// Check whether account id is valid
+	err = s.isAccountIdValid(transaction)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify source and destination account (if necessary)
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doModifyTransaction] failed to get account, because %s", err.Error())
+		return err
+	}
+
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return errs.ErrCannotModifyTransactionInHiddenAccount
+	}
+
+	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+		return errs.ErrCannotModifyTransactionInParentAccount
+	}
+
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
+		sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+		return errs.ErrTransactionSourceAndDestinationAmountNotEqual
+	}
+
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
+		(transaction.Amount < 0 || transaction.RelatedAccountAmount < 0) {
+		return errs.ErrTransferTransactionAmountCannotBeLessThanZero
+	}
+
+	oldSourceAccount, oldDestinationAccount, err := s.getOldAccountModels(sess, transaction, oldTransaction, sourceAccount, destinationAccount)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doModifyTransaction] failed to get old account, because %s", err.Error())
+		return err
+	}
+
+	if oldSourceAccount.Hidden || (oldDestinationAccount != nil && oldDestinationAccount.Hidden) {
+		return errs.ErrCannotAddTransactionToHiddenAccount
+	}
+
+	// Append modified columns and verify
+	if transaction.CategoryId != oldTransaction.CategoryId {
+		// Get and verify category
+		err = s.isCategoryValid(sess, transaction)
+
+		if err != nil {
+			return err
+		}
+
+		updateCols = append(updateCols, "category_id")
+	}
+
+	if transaction.PayeeId != oldTransaction.PayeeId {
+		// Get and verify payee
+		err = s.isPayeeValid(sess, transaction.Uid, oldTransaction.FundId, transaction.PayeeId)
+
+		if err != nil {
+			return err
+		}
+
+		updateCols = append(updateCols, "payee_id")
+	}
+
+	modifyTransactionTime := false
+
+	if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			return errs.ErrBalanceModificationTransactionCannotModifyTime
+		}
+
+		sameSecondLatestTransaction := &models.Transaction{}
+		minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		has, err = sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to get trasaction time, because %s", err.Error())
+			return err
+		}
+
+		if has && sameSecondLatestTransaction.TransactionTime < maxTransactionTime-1 {
+			transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
+		} else if has && sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
+			return errs.ErrTooMuchTransactionInOneSecond
+		}
+
+		updateCols = append(updateCols, "transaction_time")
+		modifyTransactionTime = true
+	}
+
+	if transaction.TimezoneUtcOffset != oldTransaction.TimezoneUtcOffset {
+		updateCols = append(updateCols, "timezone_utc_offset")
+	}
+
+	if transaction.AccountId != oldTransaction.AccountId {
+		updateCols = append(updateCols, "account_id")
+	}
+
+	if transaction.Amount != oldTransaction.Amount {
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			transaction.RelatedAccountAmount = oldTransaction.RelatedAccountAmount + transaction.Amount - oldTransaction.Amount
+			updateCols = append(updateCols, "related_account_amount")
+		}
+
+		updateCols = append(updateCols, "amount")
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
+			updateCols = append(updateCols, "related_account_id")
+		}
+
+		if transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
+			updateCols = append(updateCols, "related_account_amount")
+		}
+	}
+
+	if transaction.HideAmount != oldTransaction.HideAmount {
+		updateCols = append(updateCols, "hide_amount")
+	}
+
+	if transaction.Comment != oldTransaction.Comment {
+		updateCols = append(updateCols, "comment")
+	}
+
+	if transaction.GeoLongitude != oldTransaction.GeoLongitude {
+		updateCols = append(updateCols, "geo_longitude")
+	}
+
+	if transaction.GeoLatitude != oldTransaction.GeoLatitude {
+		updateCols = append(updateCols, "geo_latitude")
+	}
+
+	// Get and verify tags
+	err = s.isTagsValid(sess, transaction, transactionTagIndexes, addTagIds)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify pictures
+	err = s.isPicturesValid(sess, transaction, addPictureIds)
+
+	if err != nil {
+		return err
+	}
+
+	// Not allow to add transaction before balance modification transaction
+	if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		otherTransactionExists := false
+
+		if destinationAccount != nil && sourceAccount.AccountId != destinationAccount.AccountId {
+			otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?) AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, destinationAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
+		} else {
+			otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND account_id=? AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
+		}
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to get whether other transactions exist, because %s", err.Error())
+			return err
+		} else if otherTransactionExists {
+			return errs.ErrCannotAddTransactionBeforeBalanceModificationTransaction
+		}
+	}
+
+	// Update transaction row
+	updatedRows, err := sess.ID(transaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", transaction.Uid, false).Update(transaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doModifyTransaction] failed to update transaction, because %s", err.Error())
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrTransactionNotFound
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction := s.GetRelatedTransferTransaction(transaction)
+
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
+			return errs.ErrTooMuchTransactionInOneSecond
+		}
+
+		relatedUpdateCols := s.getRelatedUpdateColumns(updateCols)
+		updatedRows, err := sess.ID(relatedTransaction.TransactionId).Cols(relatedUpdateCols...).Where("uid=? AND deleted=?", relatedTransaction.Uid, false).Update(relatedTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to update related transaction, because %s", err.Error())
+			return err
+		} else if updatedRows < 1 {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to update related transaction")
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
+
+	// Update transaction tag index
+	if len(removeTagIds) > 0 {
+		tagIndexUpdateModel := &models.TransactionTagIndex{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("tag_id", removeTagIds).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to remove old transaction tag index, because %s", err.Error())
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	if len(transactionTagIndexes) > 0 {
+		for i := 0; i < len(transactionTagIndexes); i++ {
+			transactionTagIndex := transactionTagIndexes[i]
+			transactionTagIndex.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(transactionTagIndex)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to add new transaction tag index, because %s", err.Error())
+				return err
+			}
+		}
+	} else if len(transactionTagIndexes) == 0 && currentTagIdsCount > 0 && modifyTransactionTime {
+		tagIndexUpdateModel := &models.TransactionTagIndex{
+			TransactionTime: transaction.TransactionTime,
+		}
+
+		_, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to update transaction tag index, because %s", err.Error())
+			return err
+		}
+	}
+
+	// Update transaction picture
+	if len(removePictureIds) > 0 {
+		pictureUpdateModel := &models.TransactionPictureInfo{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Update(pictureUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to remove old transaction picture info, because %s", err.Error())
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionPictureNotFound
+		}
+	}
+
+	if len(addPictureIds) > 0 {
+		pictureUpdateModel := &models.TransactionPictureInfo{
+			TransactionId:   transaction.TransactionId,
+			UpdatedUnixTime: now,
+		}
+
+		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", addPictureIds).Update(pictureUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doModifyTransaction] failed to update new transaction picture info, because %s", err.Error())
+			return err
+		}
+	}
+
+	// Update account table
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		if transaction.AccountId != oldTransaction.AccountId {
+			return errs.ErrBalanceModificationTransactionCannotChangeAccountId
+		}
+
+		if transaction.Amount != oldTransaction.Amount && transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		var oldAccountNewAmount int64 = 0
+		var newAccountNewAmount int64 = 0
+
+		if transaction.AccountId == oldTransaction.AccountId {
+			oldAccountNewAmount = transaction.Amount
+		} else if transaction.AccountId != oldTransaction.AccountId {
+			newAccountNewAmount = transaction.Amount
+		}
+
+		if oldAccountNewAmount != oldTransaction.Amount {
+			oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if newAccountNewAmount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		var oldAccountNewAmount int64 = 0
+		var newAccountNewAmount int64 = 0
+
+		if transaction.AccountId == oldTransaction.AccountId {
+			oldAccountNewAmount = transaction.Amount
+		} else if transaction.AccountId != oldTransaction.AccountId {
+			newAccountNewAmount = transaction.Amount
+		}
+
+		if oldAccountNewAmount != oldTransaction.Amount {
+			oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if newAccountNewAmount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		var oldSourceAccountNewAmount int64 = 0
+		var newSourceAccountNewAmount int64 = 0
+
+		if transaction.AccountId == oldTransaction.AccountId {
+			oldSourceAccountNewAmount = transaction.Amount
+		} else if transaction.AccountId != oldTransaction.AccountId {
+			newSourceAccountNewAmount = transaction.Amount
+		}
+
+		if oldSourceAccountNewAmount != oldTransaction.Amount {
+			oldSourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if newSourceAccountNewAmount != 0 {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		var oldDestinationAccountNewAmount int64 = 0
+		var newDestinationAccountNewAmount int64 = 0
+
+		if transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
+			oldDestinationAccountNewAmount = transaction.RelatedAccountAmount
+		} else if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
+			newDestinationAccountNewAmount = transaction.RelatedAccountAmount
+		}
+
+		if oldDestinationAccountNewAmount != oldTransaction.RelatedAccountAmount {
+			oldDestinationAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(oldDestinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, oldDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldDestinationAccount.Uid, false).Update(oldDestinationAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		if newDestinationAccountNewAmount != 0 {
+			destinationAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance, because %s", err.Error())
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.doModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+	} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	// Record the activity with the transaction saved in database
+	newTransaction := &models.Transaction{}
+	has, err = sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(newTransaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doModifyTransaction] failed to get updated transaction, because %s", err.Error())
+		return err
+	} else if !has {
+		return errs.ErrTransactionNotFound
+	}
+
+	return insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), newTransactionFundActivity(actorUid, oldTransaction, models.FUND_ACTIVITY_ACTION_MODIFY, oldTransaction, newTransaction))
+}
+
+func (s *TransactionService) doBatchModifyTransaction(c core.Context, sess *xorm.Session, uid int64, fundId int64, transactionId int64, modification *models.TransactionBatchModification, checkTransaction func(transaction *models.Transaction) error, processedTransactionIds map[int64]bool) (bool, error) {
+	if transactionId <= 0 {
+		return false, errs.ErrTransactionIdInvalid
+	}
+
+	oldTransaction := &models.Transaction{}
+	has, err := sess.ID(transactionId).Where("fund_id=? AND deleted=?", fundId, false).Get(oldTransaction)
+
+	if err != nil {
+		return false, err
+	} else if !has {
+		return false, errs.ErrTransactionNotFound
+	}
+
+	// The transaction may be created by other user of the fund, so all the data are read and written with the uid of its creator
+	ownerUid := oldTransaction.Uid
+
+	// Modify the transfer out transaction instead, so that both transactions of the transfer are kept consistent
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransactionId := oldTransaction.RelatedId
+		oldTransaction = &models.Transaction{}
+		has, err = sess.ID(relatedTransactionId).Where("uid=? AND deleted=?", ownerUid, false).Get(oldTransaction)
+
+		if err != nil {
+			return false, err
+		} else if !has {
+			return false, errs.ErrTransactionNotFound
+		}
+	}
+
+	if processedTransactionIds[oldTransaction.TransactionId] {
+		return false, nil
+	}
+
+	processedTransactionIds[oldTransaction.TransactionId] = true
+
+	if checkTransaction != nil {
+		err = checkTransaction(oldTransaction)
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && modification.CategoryId > 0 {
+		return false, errs.ErrBalanceModificationTransactionCannotSetCategory
+	}
+
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && modification.AccountId > 0 && modification.AccountId != oldTransaction.AccountId {
+		return false, errs.ErrBalanceModificationTransactionCannotChangeAccountId
+	}
+
+	var tagIndexes []*models.TransactionTagIndex
+	err = sess.Where("uid=? AND deleted=? AND transaction_id=?", ownerUid, false, oldTransaction.TransactionId).Find(&tagIndexes)
+
+	if err != nil {
+		return false, err
+	}
+
+	currentTagIds := make([]int64, len(tagIndexes))
+
+	for i, tagIndex := range tagIndexes {
+		currentTagIds[i] = tagIndex.TagId
+	}
+
+	addTagIds, removeTagIds, err := modification.GetNewTagIds(currentTagIds)
+
+	if err != nil {
+		return false, err
+	}
+
+	newComment, err := modification.GetNewComment(oldTransaction.Comment)
+
+	if err != nil {
+		return false, err
+	}
+
+	transaction := *oldTransaction
+	transaction.Comment = newComment
+
+	if modification.CategoryId > 0 {
+		transaction.CategoryId = modification.CategoryId
+	}
+
+	if modification.AccountId > 0 {
+		transaction.AccountId = modification.AccountId
+	}
+
+	var members []*models.TransactionMember
+	err = sess.Where("transaction_id=?", oldTransaction.TransactionId).Find(&members)
+
+	if err != nil {
+		return false, err
+	}
+
+	membersChanged := modification.IsMembersChanged(members)
+
+	if transaction.CategoryId == oldTransaction.CategoryId && transaction.AccountId == oldTransaction.AccountId &&
+		transaction.Comment == oldTransaction.Comment && len(addTagIds) < 1 && len(removeTagIds) < 1 && !membersChanged {
+		return false, nil
+	}
+
+	var pictureInfos []*models.TransactionPictureInfo
+	err = sess.Where("uid=? AND deleted=? AND transaction_id=?", ownerUid, false, oldTransaction.TransactionId).OrderBy("picture_id asc").Find(&pictureInfos)
+
+	if err != nil {
+		return false, err
+	}
+
+	pictureIds := make([]int64, len(pictureInfos))
 
-	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		relatedTransaction = s.GetRelatedTransferTransaction(transaction)
+	for i, pictureInfo := range pictureInfos {
+		pictureIds[i] = pictureInfo.PictureId
 	}
 
-	insertTransactionSavePointName := "insert_transaction"
-	err = database.SetSavePoint(sess, insertTransactionSavePointName)
+	allSplits, err := getTransactionSplitsMap(sess, ownerUid, []int64{oldTransaction.TransactionId})
 
 	if err != nil {
-		log.Errorf(c, "[transactions.doCreateTransaction] failed to set save point \"%s\", because %s", insertTransactionSavePointName, err.Error())
-		return err
+		return false, err
 	}
 
-	createdRows, err := sess.Insert(transaction)
+	oldTransactionSnapshot := models.NewTransactionRevisionSnapshot(oldTransaction, currentTagIds, pictureIds, members, allSplits[oldTransaction.TransactionId])
 
-	if err != nil || createdRows < 1 { // maybe another transaction has same time
-		if err != nil {
-			log.Warnf(c, "[transactions.doCreateTransaction] cannot create trasaction, because %s, regenerate transaction time value", err.Error())
-		} else {
-			log.Warnf(c, "[transactions.doCreateTransaction] cannot create trasaction, regenerate transaction time value")
-		}
+	needTagIndexUuidCount := uint16(len(addTagIds))
+	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
 
-		err = database.RollbackToSavePoint(sess, insertTransactionSavePointName)
+	if len(tagIndexUuids) < int(needTagIndexUuidCount) {
+		return false, errs.ErrSystemIsBusy
+	}
 
-		if err != nil {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to rollback to save point \"%s\", because %s", insertTransactionSavePointName, err.Error())
-			return err
-		}
+	transactionTagIndexes := s.getNewTransactionTagIndexes(&transaction, addTagIds, tagIndexUuids, time.Now().Unix())
 
-		sameSecondLatestTransaction := &models.Transaction{}
-		minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+	err = s.doModifyTransaction(c, sess, &transaction, uid, len(currentTagIds), transactionTagIndexes, addTagIds, removeTagIds, nil, nil, false)
 
-		has, err := sess.Where("uid=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+	if err != nil {
+		return false, err
+	}
+
+	if membersChanged {
+		_, err = sess.Where("transaction_id=?", oldTransaction.TransactionId).Delete(&models.TransactionMember{})
 
 		if err != nil {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to get trasaction time, because %s", err.Error())
-			return err
-		} else if !has {
-			log.Errorf(c, "[transactions.doCreateTransaction] it should have transactions in %d - %d, but result is empty", minTransactionTime, maxTransactionTime)
-			return errs.ErrDatabaseOperationFailed
-		} else if sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
-			return errs.ErrTooMuchTransactionInOneSecond
+			return false, err
 		}
 
-		transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
-		createdRows, err := sess.Insert(transaction)
+		err = insertTransactionMembersWithEqualSplit(sess, oldTransaction.FundId, oldTransaction.TransactionId, utils.ToUniqueInt64Slice(modification.MemberIds), time.Now().Unix())
 
 		if err != nil {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction again, because %s", err.Error())
-			return err
-		} else if createdRows < 1 {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction again")
-			return errs.ErrDatabaseOperationFailed
+			return false, err
 		}
 	}
 
-	if relatedTransaction != nil {
-		relatedTransaction.TransactionTime = transaction.TransactionTime + 1
+	transactionRevision, err := models.NewTransactionRevision(oldTransaction.TransactionId, ownerUid, oldTransaction.FundId, oldTransactionSnapshot)
 
-		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
-			return errs.ErrTooMuchTransactionInOneSecond
-		}
+	if err != nil {
+		return false, err
+	}
 
-		createdRows, err := sess.Insert(relatedTransaction)
+	err = insertTransactionRevision(c, sess, transactionRevision)
 
-		if err != nil {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to add related transaction, because %s", err.Error())
-			return err
-		} else if createdRows < 1 {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to add related transaction")
-			return errs.ErrDatabaseOperationFailed
-		}
+	if err != nil {
+		return false, err
 	}
 
-	err = nil
+	return true, nil
+}
 
-	// Insert transaction tag index
-	if len(transactionTagIndexes) > 0 {
-		for i := 0; i < len(transactionTagIndexes); i++ {
-			transactionTagIndex := transactionTagIndexes[i]
-			transactionTagIndex.TransactionTime = transaction.TransactionTime
+// applyTransactionRules applies the matched transaction rules of the fund to a new transaction, the category and comment
+// set by rules are only filled when the transaction has no explicit value, and returns the ids of members set by rules
+// and the tag ids after adding the tags of rules, the rule engines are cached by fund id when rule engine cache is not nil
+func (s *TransactionService) applyTransactionRules(c core.Context, transaction *models.Transaction, tagIds []int64, ruleEngines map[int64]*models.TransactionRuleEngine) ([]int64, []int64, error) {
+	if transaction.FundId <= 0 {
+		return nil, tagIds, nil
+	}
 
-			_, err := sess.Insert(transactionTagIndex)
+	ruleEngine, exists := ruleEngines[transaction.FundId]
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction tag index, because %s", err.Error())
-				return err
-			}
+	if !exists {
+		var err error
+		ruleEngine, err = getTransactionRuleEngine(s.UserDataDB(transaction.Uid).NewSession(c), transaction.Uid, transaction.FundId)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.applyTransactionRules] failed to get transaction rules of fund \"id:%d\" for user \"uid:%d\", because %s", transaction.FundId, transaction.Uid, err.Error())
+			return nil, nil, err
+		}
+
+		if ruleEngines != nil {
+			ruleEngines[transaction.FundId] = ruleEngine
 		}
 	}
 
-	// Update transaction picture
-	if len(pictureIds) > 0 {
-		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
+	if ruleEngine.IsEmpty() {
+		return nil, tagIds, nil
+	}
 
-		if err != nil {
-			log.Errorf(c, "[transactions.doCreateTransaction] failed to update transaction picture info, because %s", err.Error())
-			return err
-		}
+	result := ruleEngine.Evaluate(transaction)
+
+	if !result.IsMatched() {
+		return nil, tagIds, nil
 	}
 
-	// Update account table
-	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-		if transaction.RelatedAccountAmount != 0 {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+	return result.MemberIds, result.FillTo(transaction, tagIds), nil
+}
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+func (s *TransactionService) getPayeeStatisticTransactions(c core.Context, uid int64, fundId int64, transactionType models.TransactionType, minTransactionTime int64, maxTransactionTime int64) ([]*models.Transaction, error) {
+	condition := "uid=? AND fund_id=? AND deleted=?"
+	conditionParams := make([]any, 0, 6)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, fundId)
+	conditionParams = append(conditionParams, false)
+
+	if transactionType == models.TRANSACTION_TYPE_INCOME {
+		condition = condition + " AND type=?"
+		conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	} else if transactionType == models.TRANSACTION_TYPE_EXPENSE {
+		condition = condition + " AND type=?"
+		conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+	} else if transactionType == 0 {
+		condition = condition + " AND (type=? OR type=?)"
+		conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+		conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+	} else {
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 8)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if minTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time>=?"
+			finalConditionParams = append(finalConditionParams, minTransactionTime)
 		}
-	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
-		if transaction.Amount != 0 {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
 		}
-	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
-		if transaction.Amount != 0 {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+		err := s.UserDataDB(uid).NewSession(c).Select("type, payee_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
 		}
-	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-		if transaction.Amount != 0 {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
-				return err
-			} else if updatedSourceRows < 1 {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = -1
+			break
 		}
 
-		if transaction.RelatedAccountAmount != 0 {
-			destinationAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
 
-			if err != nil {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance, because %s", err.Error())
-				return err
-			} else if updatedDestinationRows < 1 {
-				log.Errorf(c, "[transactions.doCreateTransaction] failed to update account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+	return allTransactions, nil
+}
+
+func (s *TransactionService) getConvertedTransactionAmount(transaction *models.Transaction, transactionUnixTime int64, timezone *time.Location, amountsConverter *models.TransactionAmountsConverter) (int64, error) {
+	if amountsConverter == nil {
+		return transaction.Amount, nil
+	}
+
+	return amountsConverter.ConvertAmount(transaction.AccountId, transaction.Amount, utils.FormatUnixTimeToLongDate(transactionUnixTime, timezone))
+}
+
+func (s *TransactionService) getNewTransactionTagIndexes(transaction *models.Transaction, tagIds []int64, tagIndexUuids []int64, now int64) []*models.TransactionTagIndex {
+	transactionTagIndexes := make([]*models.TransactionTagIndex, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		transactionTagIndexes[i] = &models.TransactionTagIndex{
+			TagIndexId:      tagIndexUuids[i],
+			Uid:             transaction.Uid,
+			FundId:          transaction.FundId,
+			Deleted:         false,
+			TagId:           tagIds[i],
+			TransactionId:   transaction.TransactionId,
+			TransactionTime: transaction.TransactionTime,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
 		}
-	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		return errs.ErrTransactionTypeInvalid
 	}
 
-	return err
+	return transactionTagIndexes
 }
 
 func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagIds []int64, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
@@ -2533,6 +3930,75 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 	return sess
 }
 
+func (s *TransactionService) appendTransactionQueryConditionToQuery(sess *xorm.Session, uid int64, query *models.TransactionQueryExpression) *xorm.Session {
+	if query == nil {
+		return sess
+	}
+
+	return sess.And(s.buildTransactionQueryExpressionCondition(uid, query))
+}
+
+func (s *TransactionService) buildTransactionQueryExpressionCondition(uid int64, query *models.TransactionQueryExpression) builder.Cond {
+	switch query.NodeType {
+	case models.TRANSACTION_QUERY_NODE_TYPE_AND, models.TRANSACTION_QUERY_NODE_TYPE_OR:
+		conds := make([]builder.Cond, len(query.Children))
+
+		for i := 0; i < len(query.Children); i++ {
+			conds[i] = s.buildTransactionQueryExpressionCondition(uid, query.Children[i])
+		}
+
+		if query.NodeType == models.TRANSACTION_QUERY_NODE_TYPE_OR {
+			return builder.Or(conds...)
+		}
+
+		return builder.And(conds...)
+	case models.TRANSACTION_QUERY_NODE_TYPE_NOT:
+		return builder.Not{s.buildTransactionQueryExpressionCondition(uid, query.Children[0])}
+	}
+
+	switch query.Field {
+	case models.TRANSACTION_QUERY_FIELD_KEYWORD, models.TRANSACTION_QUERY_FIELD_COMMENT:
+		return builder.Like{"comment", query.Value}
+	case models.TRANSACTION_QUERY_FIELD_CATEGORY:
+		return builder.In("category_id", query.ResolvedIds)
+	case models.TRANSACTION_QUERY_FIELD_ACCOUNT:
+		return builder.In("account_id", query.ResolvedIds)
+	case models.TRANSACTION_QUERY_FIELD_PAYEE:
+		return builder.In("payee_id", query.ResolvedIds)
+	case models.TRANSACTION_QUERY_FIELD_TAG:
+		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false}, builder.In("tag_id", query.ResolvedIds)))
+		return builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery))
+	case models.TRANSACTION_QUERY_FIELD_AMOUNT:
+		switch query.Operator {
+		case models.TRANSACTION_QUERY_OPERATOR_NOT_EQUAL:
+			return builder.Neq{"amount": query.AmountValue}
+		case models.TRANSACTION_QUERY_OPERATOR_GREATER_THAN:
+			return builder.Gt{"amount": query.AmountValue}
+		case models.TRANSACTION_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL:
+			return builder.Gte{"amount": query.AmountValue}
+		case models.TRANSACTION_QUERY_OPERATOR_LESS_THAN:
+			return builder.Lt{"amount": query.AmountValue}
+		case models.TRANSACTION_QUERY_OPERATOR_LESS_THAN_OR_EQUAL:
+			return builder.Lte{"amount": query.AmountValue}
+		default:
+			return builder.Eq{"amount": query.AmountValue}
+		}
+	case models.TRANSACTION_QUERY_FIELD_TYPE:
+		if query.TypeValue == models.TRANSACTION_TYPE_TRANSFER {
+			return builder.In("type", models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+		}
+
+		transactionDbType, _ := query.TypeValue.ToTransactionDbType()
+		return builder.Eq{"type": transactionDbType}
+	case models.TRANSACTION_QUERY_FIELD_AFTER:
+		return builder.Gte{"transaction_time": query.TimeValue}
+	case models.TRANSACTION_QUERY_FIELD_BEFORE:
+		return builder.Lt{"transaction_time": query.TimeValue}
+	default:
+		return builder.Expr("0=1")
+	}
+}
+
 func (s *TransactionService) isAccountIdValid(transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.RelatedAccountId != 0 && transaction.RelatedAccountId != transaction.AccountId {
@@ -2698,6 +4164,37 @@ func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []stri
 	return relatedUpdateCols
 }
 
+// isTransactionReconciled returns whether the transaction or its transfer counterpart is reconciled in the specified session
+func isTransactionReconciled(sess *xorm.Session, transaction *models.Transaction) (bool, error) {
+	if transaction.ClearedStatus == models.TRANSACTION_CLEARED_STATUS_RECONCILED {
+		return true, nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return false, nil
+	}
+
+	relatedTransaction := &models.Transaction{}
+	has, err := sess.ID(transaction.RelatedId).Cols("transaction_id", "cleared_status").Where("uid=? AND deleted=?", transaction.Uid, false).Get(relatedTransaction)
+
+	if err != nil {
+		return false, err
+	} else if !has {
+		return false, errs.ErrTransactionNotFound
+	}
+
+	return relatedTransaction.ClearedStatus == models.TRANSACTION_CLEARED_STATUS_RECONCILED, nil
+}
+
+// isInvestmentTradeTransaction returns whether the transaction changes the account balance for an existed investment trade in the specified session
+func isInvestmentTradeTransaction(sess *xorm.Session, transaction *models.Transaction) (bool, error) {
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return false, nil
+	}
+
+	return sess.Cols("uid", "deleted", "transaction_id").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Limit(1).Exist(&models.InvestmentTrade{})
+}
+
 func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.CategoryId != 0 {
@@ -2744,6 +4241,38 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 	return nil
 }
 
+func (s *TransactionService) isPayeeValid(sess *xorm.Session, uid int64, fundId int64, payeeId int64) error {
+	if payeeId <= 0 {
+		return nil
+	}
+
+	exists, err := sess.ID(payeeId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Exist(&models.Payee{})
+
+	if err != nil {
+		return err
+	} else if !exists {
+		return errs.ErrPayeeNotFound
+	}
+
+	return nil
+}
+
+func (s *TransactionService) fillPayeeDefaultCategory(c core.Context, transaction *models.Transaction) error {
+	if transaction.PayeeId <= 0 || transaction.CategoryId != 0 || transaction.FundId <= 0 {
+		return nil
+	}
+
+	categoryId, err := getPayeeDefaultCategoryId(s.UserDataDB(transaction.Uid).NewSession(c), transaction.Uid, transaction.FundId, transaction.PayeeId, transaction.Type)
+
+	if err != nil {
+		return err
+	}
+
+	transaction.CategoryId = categoryId
+
+	return nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
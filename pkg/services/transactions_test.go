@@ -0,0 +1,354 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionService_BatchModifyTransactions_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+	modification := &models.TransactionBatchModification{CategoryId: 1001}
+
+	_, _, err := service.BatchModifyTransactions(nil, 0, 1001, []int64{1001}, modification, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, _, err = service.BatchModifyTransactions(nil, 1001, 0, []int64{1001}, modification, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	_, _, err = service.BatchModifyTransactions(nil, 1001, 1001, make([]int64, models.MaximumTransactionsCountOfBatchModification+1), modification, nil)
+	assert.Equal(t, errs.ErrTooManyTransactionsToBatchModify, err)
+
+	_, _, err = service.BatchModifyTransactions(nil, 1001, 1001, []int64{1001}, &models.TransactionBatchModification{}, nil)
+	assert.Equal(t, errs.ErrNothingWillBeUpdated, err)
+}
+
+func TestTransactionService_BatchModifyTransactions_TransactionsOfOtherMembers(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT},
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100, Comment: "old"},
+		&models.Transaction{TransactionId: 3002, Uid: 1001, FundId: 4002, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+	)
+
+	modification := &models.TransactionBatchModification{Comment: "new"}
+	ownPermissionCheck := func(transaction *models.Transaction) error {
+		if !models.FUND_PERMISSION_OWN.IsAllowedOn(1002, transaction.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	}
+
+	_, transactionErrors, err := Transactions.BatchModifyTransactions(c, 1002, 4001, []int64{3001}, modification, ownPermissionCheck)
+	assert.Equal(t, errs.ErrTransactionBatchModificationFailed, err)
+	assert.Equal(t, errs.ErrFundPermissionDenied, transactionErrors[3001])
+
+	_, transactionErrors, err = Transactions.BatchModifyTransactions(c, 1002, 4001, []int64{3002}, modification, nil)
+	assert.Equal(t, errs.ErrTransactionBatchModificationFailed, err)
+	assert.Equal(t, errs.ErrTransactionNotFound, transactionErrors[3002])
+
+	modifiedCount, _, err := Transactions.BatchModifyTransactions(c, 1002, 4001, []int64{3001}, modification, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, modifiedCount)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1001, 3001)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", transaction.Comment)
+
+	revisions, err := TransactionRevisions.GetRevisionsByTransactionId(c, 1001, 3001)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(revisions))
+
+	activityCount, err := FundActivities.GetActivityCountByFundId(c, 1002, 4001, 1002, models.FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), activityCount)
+}
+
+func TestTransactionService_GetTransactionByFundIdAndTransactionId_TransactionOfOtherMember(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+		&models.Transaction{TransactionId: 3002, Uid: 1001, FundId: 4002, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+		&models.TransactionRevision{TransactionId: 3001, Uid: 1001, FundId: 4001, Revision: 1, Snapshot: "{}"},
+	)
+
+	_, err := Transactions.GetTransactionByTransactionId(c, 1002, 3001)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	transaction, err := Transactions.GetTransactionByFundIdAndTransactionId(c, 1002, 4001, 3001)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1001), transaction.Uid)
+	assert.False(t, models.FUND_PERMISSION_OWN.IsAllowedOn(1002, transaction.Uid))
+	assert.True(t, models.FUND_PERMISSION_ALL.IsAllowedOn(1002, transaction.Uid))
+
+	revision, err := TransactionRevisions.GetRevision(c, transaction.Uid, transaction.TransactionId, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), revision.Revision)
+
+	_, err = Transactions.GetTransactionByFundIdAndTransactionId(c, 1002, 4001, 3002)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+}
+
+func TestTransactionService_BatchCreateTransactions_ApplyRules(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT},
+		&models.TransactionCategory{CategoryId: 5000, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE},
+		&models.TransactionCategory{CategoryId: 5001, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 5000},
+		&models.TransactionRule{RuleId: 6001, Uid: 1001, FundId: 4001, Scope: models.TRANSACTION_RULE_SCOPE_PERSONAL, AccountId: 2001, SetComment: "rule"},
+	)
+
+	newTransactions := func() []*models.Transaction {
+		return []*models.Transaction{
+			{Uid: 1001, FundId: 4001, AccountId: 2001, CategoryId: 5001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+			{Uid: 1001, FundId: 4001, AccountId: 2001, CategoryId: 5001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100, Comment: "explicit"},
+		}
+	}
+
+	transactions := newTransactions()
+	err := Transactions.BatchCreateTransactions(c, 1001, transactions, nil, nil, true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "rule", transactions[0].Comment)
+	assert.Equal(t, "explicit", transactions[1].Comment)
+
+	transactions = newTransactions()
+	err = Transactions.BatchCreateTransactions(c, 1001, transactions, nil, nil, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", transactions[0].Comment)
+	assert.Equal(t, "explicit", transactions[1].Comment)
+}
+
+func TestTransactionService_CreateScheduledTransactions_UpdateCreatedCount(t *testing.T) {
+	c := initializeTestDataStore(t)
+	currentTime := time.Date(2026, 1, 7, 10, 0, 0, 0, time.Local)
+	currentTimeInUTC := currentTime.In(time.UTC)
+
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT},
+		&models.TransactionCategory{CategoryId: 5000, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE},
+		&models.TransactionCategory{CategoryId: 5001, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 5000},
+		&models.TransactionTemplate{
+			TemplateId:               7001,
+			Uid:                      1001,
+			FundId:                   4001,
+			TemplateType:             models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+			Type:                     models.TRANSACTION_TYPE_EXPENSE,
+			CategoryId:               5001,
+			AccountId:                2001,
+			Amount:                   100,
+			ScheduledFrequencyType:   models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+			ScheduledFrequency:       "0,1,2,3,4,5,6",
+			ScheduledAt:              int16(currentTimeInUTC.Hour()*60 + currentTimeInUTC.Minute()),
+			ScheduledOccurrenceCount: 2,
+			ScheduledCreatedCount:    1,
+		},
+	)
+
+	err := Transactions.CreateScheduledTransactions(c, currentTime.Unix(), 15*time.Minute)
+	assert.Nil(t, err)
+
+	err = Transactions.CreateScheduledTransactions(c, currentTime.Unix(), 15*time.Minute)
+	assert.Nil(t, err)
+
+	template := &models.TransactionTemplate{}
+	_, err = datastore.Container.UserDataStore.Choose(1001).NewSession(c).ID(7001).Get(template)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), template.ScheduledCreatedCount)
+
+	transactionCount, err := datastore.Container.UserDataStore.Choose(1001).NewSession(c).Where("uid=? AND scheduled_created=?", 1001, true).Count(&models.Transaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), transactionCount)
+}
+
+func TestTransactionService_BuildTransactionQueryExpressionCondition(t *testing.T) {
+	service := &TransactionService{}
+
+	query, err := models.ParseTransactionQuery("category:Food (amount>50 OR -comment:refund) type:transfer", 0)
+	assert.Nil(t, err)
+
+	query.Children[0].ResolvedIds = []int64{3001, 3002}
+
+	sql, args, err := builder.ToSQL(service.buildTransactionQueryExpressionCondition(1001, query))
+	assert.Nil(t, err)
+	assert.Equal(t, "category_id IN (?,?) AND (amount>? OR NOT comment LIKE ?) AND type IN (?,?)", sql)
+	assert.Equal(t, []any{int64(3001), int64(3002), int64(5000), "%refund%", models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN}, args)
+}
+
+func TestTransactionService_BuildTransactionQueryExpressionCondition_UnresolvedNames(t *testing.T) {
+	service := &TransactionService{}
+
+	query, err := models.ParseTransactionQuery("account:Unknown", 0)
+	assert.Nil(t, err)
+
+	sql, _, err := builder.ToSQL(service.buildTransactionQueryExpressionCondition(1001, query))
+	assert.Nil(t, err)
+	assert.Equal(t, "0=1", sql)
+}
+
+func TestTransactionService_BuildTransactionQueryExpressionCondition_Tag(t *testing.T) {
+	service := &TransactionService{}
+
+	query, err := models.ParseTransactionQuery("tag:trip", 0)
+	assert.Nil(t, err)
+
+	query.ResolvedIds = []int64{4001}
+
+	sql, args, err := builder.ToSQL(service.buildTransactionQueryExpressionCondition(1001, query))
+	assert.Nil(t, err)
+	assert.Equal(t, "transaction_id IN (SELECT transaction_id FROM transaction_tag_index WHERE uid=? AND deleted=? AND tag_id IN (?)) OR related_id IN (SELECT transaction_id FROM transaction_tag_index WHERE uid=? AND deleted=? AND tag_id IN (?))", sql)
+	assert.Equal(t, []any{int64(1001), false, int64(4001), int64(1001), false, int64(4001)}, args)
+}
+
+func TestTransactionService_ResolveTransactionQueryNames_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	err := service.ResolveTransactionQueryNames(nil, 0, 1001, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.ResolveTransactionQueryNames(nil, 1001, 1001, nil)
+	assert.Nil(t, err)
+}
+
+func TestTransactionService_ModifyTransactionClearedStatus_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	err := service.ModifyTransactionClearedStatus(nil, 0, 1001, models.TRANSACTION_CLEARED_STATUS_CLEARED, false)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.ModifyTransactionClearedStatus(nil, 1001, 1001, models.TRANSACTION_CLEARED_STATUS_RECONCILED, false)
+	assert.Equal(t, errs.ErrTransactionClearedStatusInvalid, err)
+}
+
+func TestTransactionService_DeleteTransaction_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	err := service.DeleteTransaction(nil, 0, 1002, 3001, false)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.DeleteTransaction(nil, 1001, 0, 3001, false)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
+
+func TestTransactionService_DeleteTransaction_ActorIsNotCreator(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Balance: -100},
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100},
+	)
+
+	err := Transactions.DeleteTransaction(c, 1001, 1002, 3001, false)
+	assert.Nil(t, err)
+
+	activities, err := FundActivities.GetActivitiesByFundId(c, 1002, 4001, 0, models.FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION, 0, 0, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(activities))
+	assert.Equal(t, int64(1002), activities[0].Uid)
+	assert.Equal(t, models.FUND_ACTIVITY_ACTION_DELETE, activities[0].Action)
+
+	account := &models.Account{}
+	_, err = datastore.Container.UserDataStore.Choose(1001).NewSession(c).ID(2001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), account.Balance)
+}
+
+func TestTransactionService_ModifyTransaction_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	err := service.ModifyTransaction(nil, &models.Transaction{TransactionId: 3001, Uid: 1001}, 0, 0, nil, nil, nil, nil, nil, nil, nil, false)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
+
+func TestTransactionService_RestoreTransaction_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	err := service.RestoreTransaction(nil, 0, 1001, 1001, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	err = service.RestoreTransaction(nil, 1001, 0, 1001, nil)
+	assert.Equal(t, errs.ErrFundIdInvalid, err)
+
+	err = service.RestoreTransaction(nil, 1001, 1001, 0, nil)
+	assert.Equal(t, errs.ErrTransactionIdInvalid, err)
+}
+
+func TestTransactionService_RestoreTransaction_TransactionOfOtherMember(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Account{AccountId: 2001, Uid: 1001, FundId: 4001, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Balance: 0},
+		&models.TransactionCategory{CategoryId: 5000, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE},
+		&models.TransactionCategory{CategoryId: 5001, Uid: 1001, FundId: 4001, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 5000},
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, AccountId: 2001, CategoryId: 5001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100, Deleted: true, DeletedUnixTime: 1700000000},
+		&models.Transaction{TransactionId: 3002, Uid: 1001, FundId: 4002, AccountId: 2001, CategoryId: 5001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100, Deleted: true, DeletedUnixTime: 1700000000},
+	)
+
+	ownPermissionCheck := func(transaction *models.Transaction) error {
+		if !models.FUND_PERMISSION_OWN.IsAllowedOn(1002, transaction.Uid) {
+			return errs.ErrFundPermissionDenied
+		}
+
+		return nil
+	}
+
+	err := Transactions.RestoreTransaction(c, 1002, 4001, 3001, ownPermissionCheck)
+	assert.Equal(t, errs.ErrFundPermissionDenied, err)
+
+	err = Transactions.RestoreTransaction(c, 1002, 4001, 3002, nil)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	err = Transactions.RestoreTransaction(c, 1002, 4001, 3001, nil)
+	assert.Nil(t, err)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1001, 3001)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1001), transaction.Uid)
+
+	account := &models.Account{}
+	_, err = datastore.Container.UserDataStore.Choose(1001).NewSession(c).ID(2001).Get(account)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-100), account.Balance)
+
+	activities, err := FundActivities.GetActivitiesByFundId(c, 1002, 4001, 0, models.FUND_ACTIVITY_ENTITY_TYPE_TRANSACTION, 0, 0, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(activities))
+	assert.Equal(t, int64(1002), activities[0].Uid)
+	assert.Equal(t, models.FUND_ACTIVITY_ACTION_RESTORE, activities[0].Action)
+}
+
+func TestTransactionService_GetDuplicateCandidateTransactions_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	transactions, err := service.GetDuplicateCandidateTransactions(nil, 0, 0, nil)
+	assert.Nil(t, transactions)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	transactions, err = service.GetDuplicateCandidateTransactions(nil, 1001, 0, []*models.Transaction{{AccountId: 0}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(transactions))
+}
+
+func TestTransactionService_GetFutureTransactions_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	transactions, err := service.GetFutureTransactions(nil, 0, 0, 0)
+	assert.Nil(t, transactions)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
+
+func TestTransactionService_GetAccountsAverageMonthlyAmounts_InvalidParameters(t *testing.T) {
+	service := &TransactionService{}
+
+	amounts, err := service.GetAccountsAverageMonthlyAmounts(nil, 0, 0, 0, 0, 3)
+	assert.Nil(t, amounts)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	amounts, err = service.GetAccountsAverageMonthlyAmounts(nil, 1001, 0, 0, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(amounts))
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const pageCountForPurgeExpiredTransactions = 500
+
+// TrashService represents trash bin service
+type TrashService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a trash bin service singleton instance
+var (
+	Trash = &TrashService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetDeletedTransactions returns the deleted transactions of a fund ordered by deleted time descending, only the ones
+// created by the specified creator are returned if creator uid is set, the transfer in transactions are not returned
+// because they are restored with their transfer out transactions
+func (s *TrashService) GetDeletedTransactions(c core.Context, uid int64, fundId int64, creatorUid int64, maxDeletedTime int64, count int32) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c).Where("fund_id=? AND deleted=? AND type<>?", fundId, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+
+	if creatorUid > 0 {
+		sess = sess.And("uid=?", creatorUid)
+	}
+
+	if maxDeletedTime > 0 {
+		sess = sess.And("deleted_unix_time<?", maxDeletedTime)
+	}
+
+	var transactions []*models.Transaction
+	err := sess.OrderBy("deleted_unix_time desc, transaction_id asc").Limit(int(count)).Find(&transactions)
+
+	return transactions, err
+}
+
+// GetDeletedAccounts returns the deleted accounts of a fund ordered by deleted time descending
+func (s *TrashService) GetDeletedAccounts(c core.Context, uid int64, fundId int64, maxDeletedTime int64, count int32) ([]*models.Account, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true)
+
+	if maxDeletedTime > 0 {
+		sess = sess.And("deleted_unix_time<?", maxDeletedTime)
+	}
+
+	var accounts []*models.Account
+	err := sess.OrderBy("deleted_unix_time desc, account_id asc").Limit(int(count)).Find(&accounts)
+
+	return accounts, err
+}
+
+// GetDeletedCategories returns the deleted transaction categories of a fund ordered by deleted time descending
+func (s *TrashService) GetDeletedCategories(c core.Context, uid int64, fundId int64, maxDeletedTime int64, count int32) ([]*models.TransactionCategory, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return nil, errs.ErrFundIdInvalid
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true)
+
+	if maxDeletedTime > 0 {
+		sess = sess.And("deleted_unix_time<?", maxDeletedTime)
+	}
+
+	var categories []*models.TransactionCategory
+	err := sess.OrderBy("deleted_unix_time desc, category_id asc").Limit(int(count)).Find(&categories)
+
+	return categories, err
+}
+
+// RestoreAccount restores a deleted account with the sub-accounts and the balance modification transactions deleted
+// along with it, the parent account must not be deleted
+func (s *TrashService) RestoreAccount(c core.Context, uid int64, fundId int64, accountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if accountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(accountId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			parentAccountExists, err := sess.ID(account.ParentAccountId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Exist(&models.Account{})
+
+			if err != nil {
+				return err
+			} else if !parentAccountExists {
+				return errs.ErrTrashItemParentNotRestored
+			}
+		}
+
+		var accountAndSubAccounts []*models.Account
+		err = sess.Where("uid=? AND fund_id=? AND deleted=? AND deleted_unix_time=? AND (account_id=? OR parent_account_id=?)", uid, fundId, true, account.DeletedUnixTime, accountId, accountId).Find(&accountAndSubAccounts)
+
+		if err != nil {
+			return err
+		}
+
+		accountAndSubAccountIds := make([]int64, len(accountAndSubAccounts))
+
+		for i := 0; i < len(accountAndSubAccounts); i++ {
+			accountAndSubAccountIds[i] = accountAndSubAccounts[i].AccountId
+		}
+
+		var balanceModificationTransactions []*models.Transaction
+		err = sess.Where("uid=? AND fund_id=? AND deleted=? AND deleted_unix_time=? AND type=?", uid, fundId, true, account.DeletedUnixTime, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE).In("account_id", accountAndSubAccountIds).Find(&balanceModificationTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		updateModel := &models.Account{
+			Deleted:         false,
+			UpdatedUnixTime: now,
+			DeletedUnixTime: 0,
+		}
+
+		restoredRows, err := sess.Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true).In("account_id", accountAndSubAccountIds).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < int64(len(accountAndSubAccountIds)) {
+			log.Errorf(c, "[trash.RestoreAccount] it should restore %d accounts, but have restored %d actually", len(accountAndSubAccountIds), restoredRows)
+			return errs.ErrDatabaseOperationFailed
+		}
+
+		// The balance of account is set to zero when deleted, so the balance is the amount of balance modification transaction
+		for i := 0; i < len(balanceModificationTransactions); i++ {
+			transaction := balanceModificationTransactions[i]
+			transactionUpdateModel := &models.Transaction{
+				Deleted:         false,
+				UpdatedUnixTime: now,
+				DeletedUnixTime: 0,
+			}
+
+			restoredRows, err = sess.ID(transaction.TransactionId).Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND deleted=?", uid, true).Update(transactionUpdateModel)
+
+			if err != nil {
+				return err
+			} else if restoredRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+
+			accountUpdateModel := &models.Account{
+				UpdatedUnixTime: now,
+			}
+
+			updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[trash.RestoreAccount] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		for i := 0; i < len(accountAndSubAccounts); i++ {
+			restoredAccount := accountAndSubAccounts[i]
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_ACCOUNT, restoredAccount.AccountId, models.FUND_ACTIVITY_ACTION_RESTORE, nil, restoredAccount, accountActivityFields))
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RestoreCategory restores a deleted transaction category with the sub-categories deleted along with it, the parent
+// category must not be deleted
+func (s *TrashService) RestoreCategory(c core.Context, uid int64, fundId int64, categoryId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fundId <= 0 {
+		return errs.ErrFundIdInvalid
+	}
+
+	if categoryId <= 0 {
+		return errs.ErrTransactionCategoryIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(categoryId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			parentCategoryExists, err := sess.ID(category.ParentCategoryId).Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, false).Exist(&models.TransactionCategory{})
+
+			if err != nil {
+				return err
+			} else if !parentCategoryExists {
+				return errs.ErrTrashItemParentNotRestored
+			}
+		}
+
+		var categoryAndSubCategories []*models.TransactionCategory
+		err = sess.Where("uid=? AND fund_id=? AND deleted=? AND deleted_unix_time=? AND (category_id=? OR parent_category_id=?)", uid, fundId, true, category.DeletedUnixTime, categoryId, categoryId).Find(&categoryAndSubCategories)
+
+		if err != nil {
+			return err
+		}
+
+		categoryAndSubCategoryIds := make([]int64, len(categoryAndSubCategories))
+
+		for i := 0; i < len(categoryAndSubCategories); i++ {
+			categoryAndSubCategoryIds[i] = categoryAndSubCategories[i].CategoryId
+		}
+
+		updateModel := &models.TransactionCategory{
+			Deleted:         false,
+			UpdatedUnixTime: now,
+			DeletedUnixTime: 0,
+		}
+
+		restoredRows, err := sess.Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND fund_id=? AND deleted=?", uid, fundId, true).In("category_id", categoryAndSubCategoryIds).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < int64(len(categoryAndSubCategoryIds)) {
+			log.Errorf(c, "[trash.RestoreCategory] it should restore %d categories, but have restored %d actually", len(categoryAndSubCategoryIds), restoredRows)
+			return errs.ErrDatabaseOperationFailed
+		}
+
+		for i := 0; i < len(categoryAndSubCategories); i++ {
+			restoredCategory := categoryAndSubCategories[i]
+			err = insertFundActivity(c, sess, s.GenerateUuid(uuid.UUID_TYPE_FUND_ACTIVITY), models.NewFundActivity(fundId, uid, models.FUND_ACTIVITY_ENTITY_TYPE_CATEGORY, restoredCategory.CategoryId, models.FUND_ACTIVITY_ACTION_RESTORE, nil, restoredCategory, categoryActivityFields))
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PurgeExpiredItems permanently deletes the transactions, accounts and categories which were deleted before the specified
+// unix time from all user data databases, the line items, member links, transaction links and revisions of purged transactions
+// are deleted as well, transaction pictures are kept because the picture files are not managed here
+func (s *TrashService) PurgeExpiredItems(c core.Context, expiredUnixTime int64) error {
+	var errors []error
+	totalTransactionCount := int64(0)
+	totalAccountCount := int64(0)
+	totalCategoryCount := int64(0)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		database := s.UserDataDBByIndex(i)
+
+		for {
+			var transactionIds []int64
+			err := database.NewSession(c).Table(&models.Transaction{}).Cols("transaction_id").Where("deleted=? AND deleted_unix_time<?", true, expiredUnixTime).Limit(pageCountForPurgeExpiredTransactions).Find(&transactionIds)
+
+			if err != nil {
+				errors = append(errors, err)
+				break
+			}
+
+			if len(transactionIds) < 1 {
+				break
+			}
+
+			err = database.DoTransaction(c, func(sess *xorm.Session) error {
+				if _, err := sess.In("transaction_id", transactionIds).Delete(&models.TransactionSplit{}); err != nil {
+					return err
+				}
+
+				if _, err := sess.In("transaction_id", transactionIds).Delete(&models.TransactionMember{}); err != nil {
+					return err
+				}
+
+				if _, err := sess.In("transaction_id", transactionIds).Delete(&models.TransactionRevision{}); err != nil {
+					return err
+				}
+
+				if _, err := sess.In("transaction_id", transactionIds).Delete(&models.TransactionLink{}); err != nil {
+					return err
+				}
+
+				if _, err := sess.In("linked_transaction_id", transactionIds).Delete(&models.TransactionLink{}); err != nil {
+					return err
+				}
+
+				if _, err := sess.Where("deleted=?", true).In("transaction_id", transactionIds).Delete(&models.TransactionTagIndex{}); err != nil {
+					return err
+				}
+
+				count, err := sess.Where("deleted=?", true).In("transaction_id", transactionIds).Delete(&models.Transaction{})
+				totalTransactionCount += count
+
+				return err
+			})
+
+			if err != nil {
+				errors = append(errors, err)
+				break
+			}
+
+			if len(transactionIds) < pageCountForPurgeExpiredTransactions {
+				break
+			}
+		}
+
+		err := database.DoTransaction(c, func(sess *xorm.Session) error {
+			if _, err := sess.Where("deleted=? AND deleted_unix_time<?", true, expiredUnixTime).Delete(&models.TransactionTagIndex{}); err != nil {
+				return err
+			}
+
+			accountCount, err := sess.Where("deleted=? AND deleted_unix_time<?", true, expiredUnixTime).Delete(&models.Account{})
+
+			if err != nil {
+				return err
+			}
+
+			categoryCount, err := sess.Where("deleted=? AND deleted_unix_time<?", true, expiredUnixTime).Delete(&models.TransactionCategory{})
+
+			if err != nil {
+				return err
+			}
+
+			totalAccountCount += accountCount
+			totalCategoryCount += categoryCount
+
+			return nil
+		})
+
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	if totalTransactionCount > 0 || totalAccountCount > 0 || totalCategoryCount > 0 {
+		log.Infof(c, "[trash.PurgeExpiredItems] %d transactions, %d accounts and %d categories have been purged", totalTransactionCount, totalAccountCount, totalCategoryCount)
+	} else if len(errors) == 0 {
+		log.Infof(c, "[trash.PurgeExpiredItems] no expired items have been purged")
+	}
+
+	return errs.NewMultiErrorOrNil(errors...)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTrashService_GetDeletedItems_InvalidParameters(t *testing.T) {
+	service := &TrashService{}
+
+	_, err := service.GetDeletedTransactions(nil, 0, 1001, 0, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetDeletedTransactions(nil, 1001, 0, 0, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetDeletedAccounts(nil, 0, 1001, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetDeletedAccounts(nil, 1001, 0, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	_, err = service.GetDeletedCategories(nil, 0, 1001, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	_, err = service.GetDeletedCategories(nil, 1001, 0, 0, 10)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+}
+
+func TestTrashService_GetDeletedTransactions_TransactionsOfOtherMembers(t *testing.T) {
+	c := initializeTestDataStore(t)
+	insertTestRows(t, c,
+		&models.Transaction{TransactionId: 3001, Uid: 1001, FundId: 4001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Deleted: true, DeletedUnixTime: 1700000002},
+		&models.Transaction{TransactionId: 3002, Uid: 1002, FundId: 4001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Deleted: true, DeletedUnixTime: 1700000001},
+		&models.Transaction{TransactionId: 3003, Uid: 1002, FundId: 4002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, Deleted: true, DeletedUnixTime: 1700000000},
+	)
+
+	transactions, err := Trash.GetDeletedTransactions(c, 1002, 4001, 0, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(transactions))
+	assert.Equal(t, int64(3001), transactions[0].TransactionId)
+	assert.Equal(t, int64(3002), transactions[1].TransactionId)
+
+	transactions, err = Trash.GetDeletedTransactions(c, 1002, 4001, 1002, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, int64(3002), transactions[0].TransactionId)
+}
+
+func TestTrashService_RestoreAccount_InvalidParameters(t *testing.T) {
+	service := &TrashService{}
+
+	err := service.RestoreAccount(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.RestoreAccount(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.RestoreAccount(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "account id is invalid")
+}
+
+func TestTrashService_RestoreCategory_InvalidParameters(t *testing.T) {
+	service := &TrashService{}
+
+	err := service.RestoreCategory(nil, 0, 1001, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "user id is invalid")
+
+	err = service.RestoreCategory(nil, 1001, 0, 1001)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fund id is invalid")
+
+	err = service.RestoreCategory(nil, 1001, 1001, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "transaction category id is invalid")
+}
@@ -169,14 +169,17 @@ const (
 	defaultInMemoryDuplicateCheckerCleanupInterval uint32 = 60  // 1 minutes
 	defaultDuplicateSubmissionsInterval            uint32 = 300 // 5 minutes
 
-	defaultSecretKey                     string = "ezbookkeeping"
-	defaultTokenExpiredTime              uint32 = 2592000 // 30 days
-	defaultTokenMinRefreshInterval       uint32 = 86400   // 1 day
-	defaultTemporaryTokenExpiredTime     uint32 = 300     // 5 minutes
-	defaultEmailVerifyTokenExpiredTime   uint32 = 3600    // 60 minutes
-	defaultPasswordResetTokenExpiredTime uint32 = 3600    // 60 minutes
-	defaultMaxFailuresPerIpPerMinute     uint32 = 5
-	defaultMaxFailuresPerUserPerMinute   uint32 = 5
+	defaultTrashRetentionDays uint32 = 30
+
+	defaultSecretKey                      string = "ezbookkeeping"
+	defaultTokenExpiredTime               uint32 = 2592000 // 30 days
+	defaultTokenMinRefreshInterval        uint32 = 86400   // 1 day
+	defaultTemporaryTokenExpiredTime      uint32 = 300     // 5 minutes
+	defaultEmailVerifyTokenExpiredTime    uint32 = 3600    // 60 minutes
+	defaultPasswordResetTokenExpiredTime  uint32 = 3600    // 60 minutes
+	defaultFundInvitationTokenExpiredTime uint32 = 604800  // 7 days
+	defaultMaxFailuresPerIpPerMinute      uint32 = 5
+	defaultMaxFailuresPerUserPerMinute    uint32 = 5
 
 	defaultOAuth2StateExpiredTime uint32 = 300   // 5 minutes
 	defaultOAuth2RequestTimeout   uint32 = 10000 // 10 seconds
@@ -186,7 +189,12 @@ const (
 
 	defaultImportFileMaxSize uint32 = 10485760 // 10MB
 
-	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+	defaultExchangeRatesDataRequestTimeout  uint32 = 10000 // 10 seconds
+	defaultExchangeRatesHistoryBackfillDays uint32 = 30
+	maxExchangeRatesHistoryBackfillDays     uint32 = 3660
+	defaultExchangeRatesCacheExpiredTime    uint32 = 3600 // 60 minutes
+
+	defaultExchangeRatesMetricsAllowedRemoteIPs = "127.0.0.1,::1"
 )
 
 // DatabaseConfig represents the database setting config
@@ -344,21 +352,27 @@ type Config struct {
 	// Cron
 	EnableRemoveExpiredTokens        bool
 	EnableCreateScheduledTransaction bool
+	EnablePurgeExpiredTrash          bool
+	TrashRetentionDays               uint32
+	EnableSendCreditCardDueReminders bool
+	EnableStoreExchangeRatesHistory  bool
 
 	// Secret
-	SecretKeyNoSet                        bool
-	SecretKey                             string
-	TokenExpiredTime                      uint32
-	TokenExpiredTimeDuration              time.Duration
-	TokenMinRefreshInterval               uint32
-	TemporaryTokenExpiredTime             uint32
-	TemporaryTokenExpiredTimeDuration     time.Duration
-	EmailVerifyTokenExpiredTime           uint32
-	EmailVerifyTokenExpiredTimeDuration   time.Duration
-	PasswordResetTokenExpiredTime         uint32
-	PasswordResetTokenExpiredTimeDuration time.Duration
-	MaxFailuresPerIpPerMinute             uint32
-	MaxFailuresPerUserPerMinute           uint32
+	SecretKeyNoSet                         bool
+	SecretKey                              string
+	TokenExpiredTime                       uint32
+	TokenExpiredTimeDuration               time.Duration
+	TokenMinRefreshInterval                uint32
+	TemporaryTokenExpiredTime              uint32
+	TemporaryTokenExpiredTimeDuration      time.Duration
+	EmailVerifyTokenExpiredTime            uint32
+	EmailVerifyTokenExpiredTimeDuration    time.Duration
+	PasswordResetTokenExpiredTime          uint32
+	PasswordResetTokenExpiredTimeDuration  time.Duration
+	FundInvitationTokenExpiredTime         uint32
+	FundInvitationTokenExpiredTimeDuration time.Duration
+	MaxFailuresPerIpPerMinute              uint32
+	MaxFailuresPerUserPerMinute            uint32
 
 	// Auth
 	EnableInternalAuth                bool
@@ -426,10 +440,15 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource                       string
+	ExchangeRatesFallbackDataSources              []string
 	ExchangeRatesRequestTimeout                   uint32
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+	ExchangeRatesHistoryBackfillDays              uint32
+	ExchangeRatesCacheExpiredTime                 uint32
+	ExchangeRatesCacheExpiredTimeDuration         time.Duration
+	ExchangeRatesMetricsAllowedRemoteIPs          []*core.IPPattern
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -653,31 +672,40 @@ func loadServerConfiguration(config *Config, configFile *ini.File, sectionName s
 
 func loadMCPServerConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableMCPServer = getConfigItemBoolValue(configFile, sectionName, "enable_mcp", false)
-	mcpAllowedRemoteIps := getConfigItemStringValue(configFile, sectionName, "mcp_allowed_remote_ips", "")
+	mcpAllowedRemoteIPs, err := parseIPPatterns(getConfigItemStringValue(configFile, sectionName, "mcp_allowed_remote_ips", ""))
 
-	if mcpAllowedRemoteIps != "" {
-		remoteIPs := strings.Split(mcpAllowedRemoteIps, ",")
-		config.MCPAllowedRemoteIPs = make([]*core.IPPattern, 0, len(remoteIPs))
+	if err != nil {
+		return err
+	}
 
-		for i := 0; i < len(remoteIPs); i++ {
-			ip := strings.TrimSpace(remoteIPs[i])
-			pattern, err := core.ParseIPPattern(ip)
+	config.MCPAllowedRemoteIPs = mcpAllowedRemoteIPs
 
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			if pattern == nil {
-				continue
-			}
+func parseIPPatterns(value string) ([]*core.IPPattern, error) {
+	if value == "" {
+		return nil, nil
+	}
 
-			config.MCPAllowedRemoteIPs = append(config.MCPAllowedRemoteIPs, pattern)
+	items := strings.Split(value, ",")
+	patterns := make([]*core.IPPattern, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		pattern, err := core.ParseIPPattern(strings.TrimSpace(items[i]))
+
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		config.MCPAllowedRemoteIPs = nil
+
+		if pattern == nil {
+			continue
+		}
+
+		patterns = append(patterns, pattern)
 	}
 
-	return nil
+	return patterns, nil
 }
 
 func loadDatabaseConfiguration(config *Config, configFile *ini.File, sectionName string) error {
@@ -930,6 +958,15 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnablePurgeExpiredTrash = getConfigItemBoolValue(configFile, sectionName, "enable_purge_expired_trash", false)
+	config.TrashRetentionDays = getConfigItemUint32Value(configFile, sectionName, "trash_retention_days", defaultTrashRetentionDays)
+
+	if config.TrashRetentionDays < 1 {
+		return errs.ErrInvalidTrashRetentionDays
+	}
+
+	config.EnableSendCreditCardDueReminders = getConfigItemBoolValue(configFile, sectionName, "enable_send_credit_card_due_reminders", false)
+	config.EnableStoreExchangeRatesHistory = getConfigItemBoolValue(configFile, sectionName, "enable_store_exchange_rates_history", false)
 
 	return nil
 }
@@ -976,6 +1013,14 @@ func loadSecurityConfiguration(config *Config, configFile *ini.File, sectionName
 
 	config.PasswordResetTokenExpiredTimeDuration = time.Duration(config.PasswordResetTokenExpiredTime) * time.Second
 
+	config.FundInvitationTokenExpiredTime = getConfigItemUint32Value(configFile, sectionName, "fund_invitation_token_expired_time", defaultFundInvitationTokenExpiredTime)
+
+	if config.FundInvitationTokenExpiredTime < 60 {
+		return errs.ErrInvalidFundInvitationTokenExpiredTime
+	}
+
+	config.FundInvitationTokenExpiredTimeDuration = time.Duration(config.FundInvitationTokenExpiredTime) * time.Second
+
 	config.MaxFailuresPerIpPerMinute = getConfigItemUint32Value(configFile, sectionName, "max_failures_per_ip_per_minute", defaultMaxFailuresPerIpPerMinute)
 	config.MaxFailuresPerUserPerMinute = getConfigItemUint32Value(configFile, sectionName, "max_failures_per_user_per_minute", defaultMaxFailuresPerUserPerMinute)
 
@@ -1153,7 +1198,73 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
 
-	if dataSource == ReserveBankOfAustraliaDataSource ||
+	if isValidExchangeRatesDataSource(dataSource) {
+		config.ExchangeRatesDataSource = dataSource
+	} else {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	fallbackDataSources := getConfigItemStringValue(configFile, sectionName, "fallback_data_sources", "")
+
+	if fallbackDataSources != "" {
+		if config.ExchangeRatesDataSource == UserCustomExchangeRatesDataSource {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		dataSources := strings.Split(fallbackDataSources, ",")
+		config.ExchangeRatesFallbackDataSources = make([]string, 0, len(dataSources))
+		existedDataSources := map[string]bool{
+			config.ExchangeRatesDataSource: true,
+		}
+
+		for i := 0; i < len(dataSources); i++ {
+			fallbackDataSource := strings.TrimSpace(dataSources[i])
+
+			if fallbackDataSource == "" {
+				continue
+			}
+
+			if !isValidExchangeRatesDataSource(fallbackDataSource) || fallbackDataSource == UserCustomExchangeRatesDataSource || existedDataSources[fallbackDataSource] {
+				return errs.ErrInvalidExchangeRatesDataSource
+			}
+
+			config.ExchangeRatesFallbackDataSources = append(config.ExchangeRatesFallbackDataSources, fallbackDataSource)
+			existedDataSources[fallbackDataSource] = true
+		}
+	} else {
+		config.ExchangeRatesFallbackDataSources = nil
+	}
+
+	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+
+	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
+		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
+	}
+
+	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+	config.ExchangeRatesHistoryBackfillDays = getConfigItemUint32Value(configFile, sectionName, "history_backfill_days", defaultExchangeRatesHistoryBackfillDays)
+
+	if config.ExchangeRatesHistoryBackfillDays > maxExchangeRatesHistoryBackfillDays {
+		config.ExchangeRatesHistoryBackfillDays = maxExchangeRatesHistoryBackfillDays
+	}
+
+	config.ExchangeRatesCacheExpiredTime = getConfigItemUint32Value(configFile, sectionName, "cache_expired_time", defaultExchangeRatesCacheExpiredTime)
+	config.ExchangeRatesCacheExpiredTimeDuration = time.Duration(config.ExchangeRatesCacheExpiredTime) * time.Second
+
+	metricsAllowedRemoteIPs, err := parseIPPatterns(getConfigItemStringValue(configFile, sectionName, "metrics_allowed_remote_ips", defaultExchangeRatesMetricsAllowedRemoteIPs))
+
+	if err != nil {
+		return err
+	}
+
+	config.ExchangeRatesMetricsAllowedRemoteIPs = metricsAllowedRemoteIPs
+
+	return nil
+}
+
+func isValidExchangeRatesDataSource(dataSource string) bool {
+	return dataSource == ReserveBankOfAustraliaDataSource ||
 		dataSource == BankOfCanadaDataSource ||
 		dataSource == CzechNationalBankDataSource ||
 		dataSource == DanmarksNationalbankDataSource ||
@@ -1170,22 +1281,7 @@ func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectio
 		dataSource == NationalBankOfUkraineDataSource ||
 		dataSource == CentralBankOfUzbekistanDataSource ||
 		dataSource == InternationalMonetaryFundDataSource ||
-		dataSource == UserCustomExchangeRatesDataSource {
-		config.ExchangeRatesDataSource = dataSource
-	} else {
-		return errs.ErrInvalidExchangeRatesDataSource
-	}
-
-	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
-	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
-
-	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
-		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
-	}
-
-	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
-
-	return nil
+		dataSource == UserCustomExchangeRatesDataSource
 }
 
 func getWorkingPath() (string, error) {
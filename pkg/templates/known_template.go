@@ -6,5 +6,7 @@ type KnownTemplate string
 const (
 	TEMPLATE_VERIFY_EMAIL                   KnownTemplate = "email/verify_email"
 	TEMPLATE_PASSWORD_RESET                 KnownTemplate = "email/password_reset"
+	TEMPLATE_FUND_INVITATION                KnownTemplate = "email/fund_invitation"
+	TEMPLATE_CREDIT_CARD_DUE_REMINDER       KnownTemplate = "email/credit_card_due_reminder"
 	SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION KnownTemplate = "prompt/receipt_image_recognition"
 )
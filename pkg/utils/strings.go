@@ -102,6 +102,56 @@ func ContainsOnlyOneRune(s string, r rune) bool {
 	return true
 }
 
+// GetStringSimilarity returns the similarity of two strings from 0 to 1, the strings are compared case-insensitively
+// and only letters and digits are counted, the similarity is the dice coefficient of their character bigrams
+func GetStringSimilarity(s1 string, s2 string) float64 {
+	chars1 := getLowerLetterAndDigitRunes(s1)
+	chars2 := getLowerLetterAndDigitRunes(s2)
+
+	if len(chars1) < 1 || len(chars2) < 1 {
+		return 0
+	}
+
+	if string(chars1) == string(chars2) {
+		return 1
+	}
+
+	if len(chars1) < 2 || len(chars2) < 2 {
+		return 0
+	}
+
+	bigrams := make(map[string]int, len(chars1)-1)
+
+	for i := 0; i < len(chars1)-1; i++ {
+		bigrams[string(chars1[i:i+2])]++
+	}
+
+	matchedCount := 0
+
+	for i := 0; i < len(chars2)-1; i++ {
+		bigram := string(chars2[i : i+2])
+
+		if bigrams[bigram] > 0 {
+			bigrams[bigram]--
+			matchedCount++
+		}
+	}
+
+	return float64(2*matchedCount) / float64(len(chars1)-1+len(chars2)-1)
+}
+
+func getLowerLetterAndDigitRunes(s string) []rune {
+	chars := make([]rune, 0, len(s))
+
+	for _, char := range s {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			chars = append(chars, unicode.ToLower(char))
+		}
+	}
+
+	return chars
+}
+
 // GetRandomString returns a random string of which length is n
 func GetRandomString(n int) (string, error) {
 	var result = make([]byte, n)
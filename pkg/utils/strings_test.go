@@ -147,6 +147,17 @@ func TestMD5EncodeToString(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestGetStringSimilarity(t *testing.T) {
+	assert.Equal(t, float64(1), GetStringSimilarity("Coffee House", "coffee-house"))
+	assert.Equal(t, float64(0), GetStringSimilarity("", "Coffee House"))
+	assert.Equal(t, float64(0), GetStringSimilarity("Coffee House", "!!!"))
+	assert.Equal(t, float64(0), GetStringSimilarity("a", "b"))
+	assert.Equal(t, float64(0), GetStringSimilarity("abc", "xyz"))
+	assert.InDelta(t, 0.8889, GetStringSimilarity("night", "nights"), 0.0001)
+	assert.InDelta(t, 0.25, GetStringSimilarity("night", "nacht"), 0.0001)
+	assert.InDelta(t, 0.6667, GetStringSimilarity("星巴克咖啡", "星巴克"), 0.0001)
+}
+
 func TestEncodePassword(t *testing.T) {
 	password := "foobar"
 	salt := "salt"
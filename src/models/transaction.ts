@@ -269,7 +269,7 @@ export class Transaction implements TransactionInfoResponse {
             pictureIds: this.getPictureIds(),
             comment: this.comment,
             geoLocation: this.getNormalizedGeoLocation(),
-            memberIds: this.memberIds
+            memberIds: this.memberIds.length ? this.memberIds : undefined // empty member ids would remove all members of the transaction
         };
     }
 